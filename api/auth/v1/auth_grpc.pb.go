@@ -1,4 +1,8 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: auth/v1/auth.proto
 
 package authv1
 
@@ -11,16 +15,177 @@ import (
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-// Requires gRPC-Go v1.32.0 or later.
-const _ = grpc.SupportPackageIsVersion7
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Auth_Register_FullMethodName                   = "/auth.Auth/Register"
+	Auth_GetRegistrationStatus_FullMethodName      = "/auth.Auth/GetRegistrationStatus"
+	Auth_Login_FullMethodName                      = "/auth.Auth/Login"
+	Auth_IsAdmin_FullMethodName                    = "/auth.Auth/IsAdmin"
+	Auth_RequestPasswordReset_FullMethodName       = "/auth.Auth/RequestPasswordReset"
+	Auth_ResetPassword_FullMethodName              = "/auth.Auth/ResetPassword"
+	Auth_StartDeviceAuthorization_FullMethodName   = "/auth.Auth/StartDeviceAuthorization"
+	Auth_ApproveDeviceAuthorization_FullMethodName = "/auth.Auth/ApproveDeviceAuthorization"
+	Auth_PollDeviceToken_FullMethodName            = "/auth.Auth/PollDeviceToken"
+	Auth_RefreshToken_FullMethodName               = "/auth.Auth/RefreshToken"
+	Auth_RevokeRefreshToken_FullMethodName         = "/auth.Auth/RevokeRefreshToken"
+	Auth_ReportLogin_FullMethodName                = "/auth.Auth/ReportLogin"
+	Auth_ReportLeakedSecret_FullMethodName         = "/auth.Auth/ReportLeakedSecret"
+	Auth_RequestUnfreeze_FullMethodName            = "/auth.Auth/RequestUnfreeze"
+	Auth_Unfreeze_FullMethodName                   = "/auth.Auth/Unfreeze"
+	Auth_GetUserInfo_FullMethodName                = "/auth.Auth/GetUserInfo"
+	Auth_WhoAmI_FullMethodName                     = "/auth.Auth/WhoAmI"
+	Auth_IntrospectToken_FullMethodName            = "/auth.Auth/IntrospectToken"
+	Auth_GetJwks_FullMethodName                    = "/auth.Auth/GetJwks"
+	Auth_GetServiceStatus_FullMethodName           = "/auth.Auth/GetServiceStatus"
+	Auth_SetPushMfa_FullMethodName                 = "/auth.Auth/SetPushMfa"
+	Auth_UploadAvatar_FullMethodName               = "/auth.Auth/UploadAvatar"
+	Auth_ListLoginChallenges_FullMethodName        = "/auth.Auth/ListLoginChallenges"
+	Auth_DecideLoginChallenge_FullMethodName       = "/auth.Auth/DecideLoginChallenge"
+	Auth_PollLoginChallenge_FullMethodName         = "/auth.Auth/PollLoginChallenge"
+	Auth_RequireStepUp_FullMethodName              = "/auth.Auth/RequireStepUp"
+	Auth_Logout_FullMethodName                     = "/auth.Auth/Logout"
+	Auth_ListSessions_FullMethodName               = "/auth.Auth/ListSessions"
+	Auth_RevokeSession_FullMethodName              = "/auth.Auth/RevokeSession"
+	Auth_MintTestToken_FullMethodName              = "/auth.Auth/MintTestToken"
+)
 
 // AuthClient is the client API for Auth service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AuthClient interface {
+	// Register is not naturally idempotent. Clients that retry or hedge it should
+	// send an "idempotency-key" metadata value; repeated calls with the same key
+	// return the original response instead of "user already exists".
+	// When registration.per_ip_daily_limit is set, attempts beyond it fail with
+	// RESOURCE_EXHAUSTED and an ErrorInfo detail of reason REGISTRATION_QUOTA_EXCEEDED,
+	// telling it from server overload. Its RetryInfo detail is the time until the quota
+	// is restored, rather than a short backoff.
+	// With async set, the account is created in the background and the response only
+	// carries a registration_id to poll GetRegistrationStatus with.
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	// GetRegistrationStatus reports the progress of an async registration. Statuses are
+	// kept for registration.async.status_ttl, after which it fails with NOT_FOUND.
+	GetRegistrationStatus(ctx context.Context, in *GetRegistrationStatusRequest, opts ...grpc.CallOption) (*GetRegistrationStatusResponse, error)
+	// Login fails with PERMISSION_DENIED if the app requires membership and the user
+	// was not granted access with Admin.GrantAppAccess.
 	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
 	IsAdmin(ctx context.Context, in *IsAdminRequest, opts ...grpc.CallOption) (*IsAdminResponse, error)
+	// RequestPasswordReset emails a single-use reset token to the user. It succeeds
+	// whether or not the email is registered, so it cannot be used to discover accounts.
+	RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest, opts ...grpc.CallOption) (*RequestPasswordResetResponse, error)
+	// ResetPassword sets a new password using a token from RequestPasswordReset.
+	// Like Register, it honors the "idempotency-key" metadata value for safe retries.
+	ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*ResetPasswordResponse, error)
+	// StartDeviceAuthorization starts the OAuth 2.0 device authorization grant (RFC 8628)
+	// for CLI tools and other devices that cannot show a login form. The device shows
+	// the user code and verification URI to the user and polls PollDeviceToken.
+	StartDeviceAuthorization(ctx context.Context, in *StartDeviceAuthorizationRequest, opts ...grpc.CallOption) (*StartDeviceAuthorizationResponse, error)
+	// ApproveDeviceAuthorization approves or denies a device by its user code on behalf of
+	// the signed-in user. It requires the user's token in the "authorization" metadata.
+	ApproveDeviceAuthorization(ctx context.Context, in *ApproveDeviceAuthorizationRequest, opts ...grpc.CallOption) (*ApproveDeviceAuthorizationResponse, error)
+	// PollDeviceToken returns a token once the user approved the device. A token is
+	// issued only once per device code.
+	PollDeviceToken(ctx context.Context, in *PollDeviceTokenRequest, opts ...grpc.CallOption) (*PollDeviceTokenResponse, error)
+	// RefreshToken exchanges a refresh token returned by Login, PollLoginChallenge, or
+	// PollDeviceToken for a new token, until refresh_tokens.ttl after the login. The refresh
+	// token is rotated: the response carries the next one, and presenting a used one again
+	// revokes every refresh token issued since the login.
+	//
+	// Only while refresh_tokens.ttl is 0, a token can be exchanged for a new one with a full
+	// lifetime instead; tokens that expired within token_validation.refresh_grace are accepted
+	// as well. Otherwise it fails with FAILED_PRECONDITION, so revoking the refresh tokens of
+	// a session ends it.
+	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error)
+	// RevokeRefreshToken revokes a refresh token and every other one issued since the same
+	// login, signing a single device out once its access token expires. Unknown refresh
+	// tokens are not reported as errors, so no token is required.
+	RevokeRefreshToken(ctx context.Context, in *RevokeRefreshTokenRequest, opts ...grpc.CallOption) (*RevokeRefreshTokenResponse, error)
+	// ReportLogin freezes the account of a user who reports through the link in a login
+	// notification email that a login was not theirs, revoking all of its tokens. The page at
+	// login_notifications.report_url calls it with the link's "token" query parameter.
+	ReportLogin(ctx context.Context, in *ReportLoginRequest, opts ...grpc.CallOption) (*ReportLoginResponse, error)
+	// ReportLeakedSecret lets secret scanners report an app secret found in public, e.g. in a
+	// commit. The secret is replaced with a random one, every token issued for the app is revoked,
+	// and the rotation is audited and emailed to secret_leaks.notify_emails. Admins then set a
+	// new secret with Admin.UpdateApp. No token is required, since knowing the secret proves the
+	// leak, and it succeeds whether or not an app uses the secret.
+	ReportLeakedSecret(ctx context.Context, in *ReportLeakedSecretRequest, opts ...grpc.CallOption) (*ReportLeakedSecretResponse, error)
+	// RequestUnfreeze emails a single-use unfreeze token to the owner of a frozen account.
+	// Like RequestPasswordReset, it succeeds for unknown emails and accounts that are not frozen.
+	RequestUnfreeze(ctx context.Context, in *RequestUnfreezeRequest, opts ...grpc.CallOption) (*RequestUnfreezeResponse, error)
+	// Unfreeze unfreezes an account using a token from RequestUnfreeze and sets a new password.
+	Unfreeze(ctx context.Context, in *UnfreezeRequest, opts ...grpc.CallOption) (*UnfreezeResponse, error)
+	// GetUserInfo returns the profile of the signed-in user, for relying parties of apps whose
+	// tokens carry minimal claims. It requires the user's token in the "authorization" metadata.
+	// Responses may be cached per token for the max-age of the "cache-control" response header.
+	GetUserInfo(ctx context.Context, in *GetUserInfoRequest, opts ...grpc.CallOption) (*GetUserInfoResponse, error)
+	// WhoAmI resolves a token to its user, the apps accepting it, the roles of its user, and
+	// the protected methods the token may call, so that relying parties can fill their
+	// authorization caches with a single call. Tokens issued at sign-in carry no OAuth scopes;
+	// audience and permissions describe what the token grants instead. Fails with
+	// UNAUTHENTICATED for tokens ValidateToken would reject.
+	WhoAmI(ctx context.Context, in *WhoAmIRequest, opts ...grpc.CallOption) (*WhoAmIResponse, error)
+	// IntrospectToken reports whether an access or refresh token is active and what it was
+	// issued for, following RFC 7662, so that clients can check tokens without the app secret.
+	// Unknown, expired, and revoked tokens are reported as not active rather than failing.
+	IntrospectToken(ctx context.Context, in *IntrospectTokenRequest, opts ...grpc.CallOption) (*IntrospectTokenResponse, error)
+	// GetJwks returns the public keys of the tokens of apps with the RS256 token format as a
+	// JSON Web Key Set (RFC 7517), so consumers can verify the tokens offline. Tokens name
+	// their key in the "kid" header. Empty when no signing key is configured.
+	GetJwks(ctx context.Context, in *GetJwksRequest, opts ...grpc.CallOption) (*GetJwksResponse, error)
+	// GetServiceStatus returns the announcement operators set with Admin.SetAnnouncement, e.g.
+	// of upcoming maintenance. Every response also carries it in the "sso-announcement" header.
+	GetServiceStatus(ctx context.Context, in *GetServiceStatusRequest, opts ...grpc.CallOption) (*GetServiceStatusResponse, error)
+	// SetPushMfa turns push approval of logins on or off for the signed-in user. While it is on,
+	// Login returns a challenge instead of a token, which the user approves from a device that
+	// stays signed in. It requires the user's token in the "authorization" metadata.
+	SetPushMfa(ctx context.Context, in *SetPushMfaRequest, opts ...grpc.CallOption) (*SetPushMfaResponse, error)
+	// UploadAvatar sets the avatar of the signed-in user to a PNG, JPEG, GIF, or WebP image,
+	// streamed in chunks of any size. The image is stored under a key derived from its content,
+	// so its URL never changes; GetUserInfo returns it as avatar_url. It requires the user's
+	// token in the "authorization" metadata. Fails with INVALID_ARGUMENT if the image is empty,
+	// larger than avatars.max_size, or of another type, and with FAILED_PRECONDITION if avatars
+	// are disabled.
+	UploadAvatar(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadAvatarRequest, UploadAvatarResponse], error)
+	// ListLoginChallenges returns the pending login challenges of the signed-in user, for the
+	// companion app to show. It requires the user's token in the "authorization" metadata.
+	ListLoginChallenges(ctx context.Context, in *ListLoginChallengesRequest, opts ...grpc.CallOption) (*ListLoginChallengesResponse, error)
+	// DecideLoginChallenge approves or denies a pending login challenge of the signed-in user.
+	// It requires the user's token in the "authorization" metadata.
+	DecideLoginChallenge(ctx context.Context, in *DecideLoginChallengeRequest, opts ...grpc.CallOption) (*DecideLoginChallengeResponse, error)
+	// PollLoginChallenge returns a token once the user approved the login challenge returned
+	// by Login. A token is issued only once per challenge.
+	PollLoginChallenge(ctx context.Context, in *PollLoginChallengeRequest, opts ...grpc.CallOption) (*PollLoginChallengeResponse, error)
+	// RequireStepUp checks that the user of a token approved a second factor recently enough
+	// for a sensitive action. If not, it starts a challenge the user approves like a login
+	// challenge; PollLoginChallenge then returns an elevated token with the "acr" and
+	// "auth_time" claims.
+	RequireStepUp(ctx context.Context, in *RequireStepUpRequest, opts ...grpc.CallOption) (*RequireStepUpResponse, error)
+	// Logout signs the user out of every app by revoking every token issued to the user so
+	// far, and sends a logout token to apps with a back-channel logout URI. It requires the
+	// user's token in the "authorization" metadata.
+	// To sign out of a single device, use RevokeRefreshToken or RevokeSession instead.
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
+	// ListSessions returns the active sessions of the signed-in user, most recently used
+	// first. A session is a sign-in holding a refresh token that can still be exchanged. It
+	// requires the user's token in the "authorization" metadata.
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// RevokeSession signs the signed-in user out of one of their sessions by revoking its
+	// refresh tokens, like RevokeRefreshToken does for the device holding them. It requires
+	// the user's token in the "authorization" metadata, and fails with NOT_FOUND if the user
+	// has no active session with the ID.
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error)
+	// MintTestToken mints a token for an app with the claims given, signed and formatted like
+	// the app's tokens, so CI suites of dependent services can obtain tokens without seeding
+	// users. The user named need not exist, so the token carries a "test" claim and is only
+	// accepted by services verifying tokens themselves; this service rejects it. It requires
+	// a token of an admin or an owner of the app in the "authorization" metadata, and fails
+	// with FAILED_PRECONDITION unless the app is flagged test-only with SetAppTestOnly. For
+	// development only: it fails with PERMISSION_DENIED unless test_tokens.enabled is set,
+	// which is refused in prod, and with UNIMPLEMENTED in builds with the notesttokens tag.
+	MintTestToken(ctx context.Context, in *MintTestTokenRequest, opts ...grpc.CallOption) (*MintTestTokenResponse, error)
 }
 
 type authClient struct {
@@ -32,8 +197,19 @@ func NewAuthClient(cc grpc.ClientConnInterface) AuthClient {
 }
 
 func (c *authClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RegisterResponse)
-	err := c.cc.Invoke(ctx, "/auth.Auth/Register", in, out, opts...)
+	err := c.cc.Invoke(ctx, Auth_Register_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetRegistrationStatus(ctx context.Context, in *GetRegistrationStatusRequest, opts ...grpc.CallOption) (*GetRegistrationStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRegistrationStatusResponse)
+	err := c.cc.Invoke(ctx, Auth_GetRegistrationStatus_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -41,8 +217,9 @@ func (c *authClient) Register(ctx context.Context, in *RegisterRequest, opts ...
 }
 
 func (c *authClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(LoginResponse)
-	err := c.cc.Invoke(ctx, "/auth.Auth/Login", in, out, opts...)
+	err := c.cc.Invoke(ctx, Auth_Login_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -50,8 +227,272 @@ func (c *authClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.C
 }
 
 func (c *authClient) IsAdmin(ctx context.Context, in *IsAdminRequest, opts ...grpc.CallOption) (*IsAdminResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(IsAdminResponse)
-	err := c.cc.Invoke(ctx, "/auth.Auth/IsAdmin", in, out, opts...)
+	err := c.cc.Invoke(ctx, Auth_IsAdmin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest, opts ...grpc.CallOption) (*RequestPasswordResetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestPasswordResetResponse)
+	err := c.cc.Invoke(ctx, Auth_RequestPasswordReset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*ResetPasswordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResetPasswordResponse)
+	err := c.cc.Invoke(ctx, Auth_ResetPassword_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) StartDeviceAuthorization(ctx context.Context, in *StartDeviceAuthorizationRequest, opts ...grpc.CallOption) (*StartDeviceAuthorizationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartDeviceAuthorizationResponse)
+	err := c.cc.Invoke(ctx, Auth_StartDeviceAuthorization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ApproveDeviceAuthorization(ctx context.Context, in *ApproveDeviceAuthorizationRequest, opts ...grpc.CallOption) (*ApproveDeviceAuthorizationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApproveDeviceAuthorizationResponse)
+	err := c.cc.Invoke(ctx, Auth_ApproveDeviceAuthorization_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) PollDeviceToken(ctx context.Context, in *PollDeviceTokenRequest, opts ...grpc.CallOption) (*PollDeviceTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PollDeviceTokenResponse)
+	err := c.cc.Invoke(ctx, Auth_PollDeviceToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*RefreshTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefreshTokenResponse)
+	err := c.cc.Invoke(ctx, Auth_RefreshToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RevokeRefreshToken(ctx context.Context, in *RevokeRefreshTokenRequest, opts ...grpc.CallOption) (*RevokeRefreshTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeRefreshTokenResponse)
+	err := c.cc.Invoke(ctx, Auth_RevokeRefreshToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ReportLogin(ctx context.Context, in *ReportLoginRequest, opts ...grpc.CallOption) (*ReportLoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportLoginResponse)
+	err := c.cc.Invoke(ctx, Auth_ReportLogin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ReportLeakedSecret(ctx context.Context, in *ReportLeakedSecretRequest, opts ...grpc.CallOption) (*ReportLeakedSecretResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportLeakedSecretResponse)
+	err := c.cc.Invoke(ctx, Auth_ReportLeakedSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RequestUnfreeze(ctx context.Context, in *RequestUnfreezeRequest, opts ...grpc.CallOption) (*RequestUnfreezeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestUnfreezeResponse)
+	err := c.cc.Invoke(ctx, Auth_RequestUnfreeze_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Unfreeze(ctx context.Context, in *UnfreezeRequest, opts ...grpc.CallOption) (*UnfreezeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnfreezeResponse)
+	err := c.cc.Invoke(ctx, Auth_Unfreeze_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetUserInfo(ctx context.Context, in *GetUserInfoRequest, opts ...grpc.CallOption) (*GetUserInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserInfoResponse)
+	err := c.cc.Invoke(ctx, Auth_GetUserInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) WhoAmI(ctx context.Context, in *WhoAmIRequest, opts ...grpc.CallOption) (*WhoAmIResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WhoAmIResponse)
+	err := c.cc.Invoke(ctx, Auth_WhoAmI_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) IntrospectToken(ctx context.Context, in *IntrospectTokenRequest, opts ...grpc.CallOption) (*IntrospectTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IntrospectTokenResponse)
+	err := c.cc.Invoke(ctx, Auth_IntrospectToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetJwks(ctx context.Context, in *GetJwksRequest, opts ...grpc.CallOption) (*GetJwksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJwksResponse)
+	err := c.cc.Invoke(ctx, Auth_GetJwks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetServiceStatus(ctx context.Context, in *GetServiceStatusRequest, opts ...grpc.CallOption) (*GetServiceStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetServiceStatusResponse)
+	err := c.cc.Invoke(ctx, Auth_GetServiceStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) SetPushMfa(ctx context.Context, in *SetPushMfaRequest, opts ...grpc.CallOption) (*SetPushMfaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetPushMfaResponse)
+	err := c.cc.Invoke(ctx, Auth_SetPushMfa_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) UploadAvatar(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadAvatarRequest, UploadAvatarResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Auth_ServiceDesc.Streams[0], Auth_UploadAvatar_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadAvatarRequest, UploadAvatarResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Auth_UploadAvatarClient = grpc.ClientStreamingClient[UploadAvatarRequest, UploadAvatarResponse]
+
+func (c *authClient) ListLoginChallenges(ctx context.Context, in *ListLoginChallengesRequest, opts ...grpc.CallOption) (*ListLoginChallengesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLoginChallengesResponse)
+	err := c.cc.Invoke(ctx, Auth_ListLoginChallenges_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) DecideLoginChallenge(ctx context.Context, in *DecideLoginChallengeRequest, opts ...grpc.CallOption) (*DecideLoginChallengeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecideLoginChallengeResponse)
+	err := c.cc.Invoke(ctx, Auth_DecideLoginChallenge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) PollLoginChallenge(ctx context.Context, in *PollLoginChallengeRequest, opts ...grpc.CallOption) (*PollLoginChallengeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PollLoginChallengeResponse)
+	err := c.cc.Invoke(ctx, Auth_PollLoginChallenge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RequireStepUp(ctx context.Context, in *RequireStepUpRequest, opts ...grpc.CallOption) (*RequireStepUpResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequireStepUpResponse)
+	err := c.cc.Invoke(ctx, Auth_RequireStepUp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogoutResponse)
+	err := c.cc.Invoke(ctx, Auth_Logout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, Auth_ListSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*RevokeSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeSessionResponse)
+	err := c.cc.Invoke(ctx, Auth_RevokeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) MintTestToken(ctx context.Context, in *MintTestTokenRequest, opts ...grpc.CallOption) (*MintTestTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MintTestTokenResponse)
+	err := c.cc.Invoke(ctx, Auth_MintTestToken_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -60,28 +501,241 @@ func (c *authClient) IsAdmin(ctx context.Context, in *IsAdminRequest, opts ...gr
 
 // AuthServer is the server API for Auth service.
 // All implementations must embed UnimplementedAuthServer
-// for forward compatibility
+// for forward compatibility.
 type AuthServer interface {
+	// Register is not naturally idempotent. Clients that retry or hedge it should
+	// send an "idempotency-key" metadata value; repeated calls with the same key
+	// return the original response instead of "user already exists".
+	// When registration.per_ip_daily_limit is set, attempts beyond it fail with
+	// RESOURCE_EXHAUSTED and an ErrorInfo detail of reason REGISTRATION_QUOTA_EXCEEDED,
+	// telling it from server overload. Its RetryInfo detail is the time until the quota
+	// is restored, rather than a short backoff.
+	// With async set, the account is created in the background and the response only
+	// carries a registration_id to poll GetRegistrationStatus with.
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
+	// GetRegistrationStatus reports the progress of an async registration. Statuses are
+	// kept for registration.async.status_ttl, after which it fails with NOT_FOUND.
+	GetRegistrationStatus(context.Context, *GetRegistrationStatusRequest) (*GetRegistrationStatusResponse, error)
+	// Login fails with PERMISSION_DENIED if the app requires membership and the user
+	// was not granted access with Admin.GrantAppAccess.
 	Login(context.Context, *LoginRequest) (*LoginResponse, error)
 	IsAdmin(context.Context, *IsAdminRequest) (*IsAdminResponse, error)
+	// RequestPasswordReset emails a single-use reset token to the user. It succeeds
+	// whether or not the email is registered, so it cannot be used to discover accounts.
+	RequestPasswordReset(context.Context, *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error)
+	// ResetPassword sets a new password using a token from RequestPasswordReset.
+	// Like Register, it honors the "idempotency-key" metadata value for safe retries.
+	ResetPassword(context.Context, *ResetPasswordRequest) (*ResetPasswordResponse, error)
+	// StartDeviceAuthorization starts the OAuth 2.0 device authorization grant (RFC 8628)
+	// for CLI tools and other devices that cannot show a login form. The device shows
+	// the user code and verification URI to the user and polls PollDeviceToken.
+	StartDeviceAuthorization(context.Context, *StartDeviceAuthorizationRequest) (*StartDeviceAuthorizationResponse, error)
+	// ApproveDeviceAuthorization approves or denies a device by its user code on behalf of
+	// the signed-in user. It requires the user's token in the "authorization" metadata.
+	ApproveDeviceAuthorization(context.Context, *ApproveDeviceAuthorizationRequest) (*ApproveDeviceAuthorizationResponse, error)
+	// PollDeviceToken returns a token once the user approved the device. A token is
+	// issued only once per device code.
+	PollDeviceToken(context.Context, *PollDeviceTokenRequest) (*PollDeviceTokenResponse, error)
+	// RefreshToken exchanges a refresh token returned by Login, PollLoginChallenge, or
+	// PollDeviceToken for a new token, until refresh_tokens.ttl after the login. The refresh
+	// token is rotated: the response carries the next one, and presenting a used one again
+	// revokes every refresh token issued since the login.
+	//
+	// Only while refresh_tokens.ttl is 0, a token can be exchanged for a new one with a full
+	// lifetime instead; tokens that expired within token_validation.refresh_grace are accepted
+	// as well. Otherwise it fails with FAILED_PRECONDITION, so revoking the refresh tokens of
+	// a session ends it.
+	RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error)
+	// RevokeRefreshToken revokes a refresh token and every other one issued since the same
+	// login, signing a single device out once its access token expires. Unknown refresh
+	// tokens are not reported as errors, so no token is required.
+	RevokeRefreshToken(context.Context, *RevokeRefreshTokenRequest) (*RevokeRefreshTokenResponse, error)
+	// ReportLogin freezes the account of a user who reports through the link in a login
+	// notification email that a login was not theirs, revoking all of its tokens. The page at
+	// login_notifications.report_url calls it with the link's "token" query parameter.
+	ReportLogin(context.Context, *ReportLoginRequest) (*ReportLoginResponse, error)
+	// ReportLeakedSecret lets secret scanners report an app secret found in public, e.g. in a
+	// commit. The secret is replaced with a random one, every token issued for the app is revoked,
+	// and the rotation is audited and emailed to secret_leaks.notify_emails. Admins then set a
+	// new secret with Admin.UpdateApp. No token is required, since knowing the secret proves the
+	// leak, and it succeeds whether or not an app uses the secret.
+	ReportLeakedSecret(context.Context, *ReportLeakedSecretRequest) (*ReportLeakedSecretResponse, error)
+	// RequestUnfreeze emails a single-use unfreeze token to the owner of a frozen account.
+	// Like RequestPasswordReset, it succeeds for unknown emails and accounts that are not frozen.
+	RequestUnfreeze(context.Context, *RequestUnfreezeRequest) (*RequestUnfreezeResponse, error)
+	// Unfreeze unfreezes an account using a token from RequestUnfreeze and sets a new password.
+	Unfreeze(context.Context, *UnfreezeRequest) (*UnfreezeResponse, error)
+	// GetUserInfo returns the profile of the signed-in user, for relying parties of apps whose
+	// tokens carry minimal claims. It requires the user's token in the "authorization" metadata.
+	// Responses may be cached per token for the max-age of the "cache-control" response header.
+	GetUserInfo(context.Context, *GetUserInfoRequest) (*GetUserInfoResponse, error)
+	// WhoAmI resolves a token to its user, the apps accepting it, the roles of its user, and
+	// the protected methods the token may call, so that relying parties can fill their
+	// authorization caches with a single call. Tokens issued at sign-in carry no OAuth scopes;
+	// audience and permissions describe what the token grants instead. Fails with
+	// UNAUTHENTICATED for tokens ValidateToken would reject.
+	WhoAmI(context.Context, *WhoAmIRequest) (*WhoAmIResponse, error)
+	// IntrospectToken reports whether an access or refresh token is active and what it was
+	// issued for, following RFC 7662, so that clients can check tokens without the app secret.
+	// Unknown, expired, and revoked tokens are reported as not active rather than failing.
+	IntrospectToken(context.Context, *IntrospectTokenRequest) (*IntrospectTokenResponse, error)
+	// GetJwks returns the public keys of the tokens of apps with the RS256 token format as a
+	// JSON Web Key Set (RFC 7517), so consumers can verify the tokens offline. Tokens name
+	// their key in the "kid" header. Empty when no signing key is configured.
+	GetJwks(context.Context, *GetJwksRequest) (*GetJwksResponse, error)
+	// GetServiceStatus returns the announcement operators set with Admin.SetAnnouncement, e.g.
+	// of upcoming maintenance. Every response also carries it in the "sso-announcement" header.
+	GetServiceStatus(context.Context, *GetServiceStatusRequest) (*GetServiceStatusResponse, error)
+	// SetPushMfa turns push approval of logins on or off for the signed-in user. While it is on,
+	// Login returns a challenge instead of a token, which the user approves from a device that
+	// stays signed in. It requires the user's token in the "authorization" metadata.
+	SetPushMfa(context.Context, *SetPushMfaRequest) (*SetPushMfaResponse, error)
+	// UploadAvatar sets the avatar of the signed-in user to a PNG, JPEG, GIF, or WebP image,
+	// streamed in chunks of any size. The image is stored under a key derived from its content,
+	// so its URL never changes; GetUserInfo returns it as avatar_url. It requires the user's
+	// token in the "authorization" metadata. Fails with INVALID_ARGUMENT if the image is empty,
+	// larger than avatars.max_size, or of another type, and with FAILED_PRECONDITION if avatars
+	// are disabled.
+	UploadAvatar(grpc.ClientStreamingServer[UploadAvatarRequest, UploadAvatarResponse]) error
+	// ListLoginChallenges returns the pending login challenges of the signed-in user, for the
+	// companion app to show. It requires the user's token in the "authorization" metadata.
+	ListLoginChallenges(context.Context, *ListLoginChallengesRequest) (*ListLoginChallengesResponse, error)
+	// DecideLoginChallenge approves or denies a pending login challenge of the signed-in user.
+	// It requires the user's token in the "authorization" metadata.
+	DecideLoginChallenge(context.Context, *DecideLoginChallengeRequest) (*DecideLoginChallengeResponse, error)
+	// PollLoginChallenge returns a token once the user approved the login challenge returned
+	// by Login. A token is issued only once per challenge.
+	PollLoginChallenge(context.Context, *PollLoginChallengeRequest) (*PollLoginChallengeResponse, error)
+	// RequireStepUp checks that the user of a token approved a second factor recently enough
+	// for a sensitive action. If not, it starts a challenge the user approves like a login
+	// challenge; PollLoginChallenge then returns an elevated token with the "acr" and
+	// "auth_time" claims.
+	RequireStepUp(context.Context, *RequireStepUpRequest) (*RequireStepUpResponse, error)
+	// Logout signs the user out of every app by revoking every token issued to the user so
+	// far, and sends a logout token to apps with a back-channel logout URI. It requires the
+	// user's token in the "authorization" metadata.
+	// To sign out of a single device, use RevokeRefreshToken or RevokeSession instead.
+	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	// ListSessions returns the active sessions of the signed-in user, most recently used
+	// first. A session is a sign-in holding a refresh token that can still be exchanged. It
+	// requires the user's token in the "authorization" metadata.
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// RevokeSession signs the signed-in user out of one of their sessions by revoking its
+	// refresh tokens, like RevokeRefreshToken does for the device holding them. It requires
+	// the user's token in the "authorization" metadata, and fails with NOT_FOUND if the user
+	// has no active session with the ID.
+	RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error)
+	// MintTestToken mints a token for an app with the claims given, signed and formatted like
+	// the app's tokens, so CI suites of dependent services can obtain tokens without seeding
+	// users. The user named need not exist, so the token carries a "test" claim and is only
+	// accepted by services verifying tokens themselves; this service rejects it. It requires
+	// a token of an admin or an owner of the app in the "authorization" metadata, and fails
+	// with FAILED_PRECONDITION unless the app is flagged test-only with SetAppTestOnly. For
+	// development only: it fails with PERMISSION_DENIED unless test_tokens.enabled is set,
+	// which is refused in prod, and with UNIMPLEMENTED in builds with the notesttokens tag.
+	MintTestToken(context.Context, *MintTestTokenRequest) (*MintTestTokenResponse, error)
 	mustEmbedUnimplementedAuthServer()
 }
 
-// UnimplementedAuthServer must be embedded to have forward compatible implementations.
-type UnimplementedAuthServer struct {
-}
+// UnimplementedAuthServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAuthServer struct{}
 
 func (UnimplementedAuthServer) Register(context.Context, *RegisterRequest) (*RegisterResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
 }
+func (UnimplementedAuthServer) GetRegistrationStatus(context.Context, *GetRegistrationStatusRequest) (*GetRegistrationStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRegistrationStatus not implemented")
+}
 func (UnimplementedAuthServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
 }
 func (UnimplementedAuthServer) IsAdmin(context.Context, *IsAdminRequest) (*IsAdminResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method IsAdmin not implemented")
 }
+func (UnimplementedAuthServer) RequestPasswordReset(context.Context, *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestPasswordReset not implemented")
+}
+func (UnimplementedAuthServer) ResetPassword(context.Context, *ResetPasswordRequest) (*ResetPasswordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetPassword not implemented")
+}
+func (UnimplementedAuthServer) StartDeviceAuthorization(context.Context, *StartDeviceAuthorizationRequest) (*StartDeviceAuthorizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartDeviceAuthorization not implemented")
+}
+func (UnimplementedAuthServer) ApproveDeviceAuthorization(context.Context, *ApproveDeviceAuthorizationRequest) (*ApproveDeviceAuthorizationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveDeviceAuthorization not implemented")
+}
+func (UnimplementedAuthServer) PollDeviceToken(context.Context, *PollDeviceTokenRequest) (*PollDeviceTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PollDeviceToken not implemented")
+}
+func (UnimplementedAuthServer) RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (UnimplementedAuthServer) RevokeRefreshToken(context.Context, *RevokeRefreshTokenRequest) (*RevokeRefreshTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeRefreshToken not implemented")
+}
+func (UnimplementedAuthServer) ReportLogin(context.Context, *ReportLoginRequest) (*ReportLoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportLogin not implemented")
+}
+func (UnimplementedAuthServer) ReportLeakedSecret(context.Context, *ReportLeakedSecretRequest) (*ReportLeakedSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportLeakedSecret not implemented")
+}
+func (UnimplementedAuthServer) RequestUnfreeze(context.Context, *RequestUnfreezeRequest) (*RequestUnfreezeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestUnfreeze not implemented")
+}
+func (UnimplementedAuthServer) Unfreeze(context.Context, *UnfreezeRequest) (*UnfreezeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unfreeze not implemented")
+}
+func (UnimplementedAuthServer) GetUserInfo(context.Context, *GetUserInfoRequest) (*GetUserInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserInfo not implemented")
+}
+func (UnimplementedAuthServer) WhoAmI(context.Context, *WhoAmIRequest) (*WhoAmIResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WhoAmI not implemented")
+}
+func (UnimplementedAuthServer) IntrospectToken(context.Context, *IntrospectTokenRequest) (*IntrospectTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IntrospectToken not implemented")
+}
+func (UnimplementedAuthServer) GetJwks(context.Context, *GetJwksRequest) (*GetJwksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJwks not implemented")
+}
+func (UnimplementedAuthServer) GetServiceStatus(context.Context, *GetServiceStatusRequest) (*GetServiceStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetServiceStatus not implemented")
+}
+func (UnimplementedAuthServer) SetPushMfa(context.Context, *SetPushMfaRequest) (*SetPushMfaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPushMfa not implemented")
+}
+func (UnimplementedAuthServer) UploadAvatar(grpc.ClientStreamingServer[UploadAvatarRequest, UploadAvatarResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadAvatar not implemented")
+}
+func (UnimplementedAuthServer) ListLoginChallenges(context.Context, *ListLoginChallengesRequest) (*ListLoginChallengesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLoginChallenges not implemented")
+}
+func (UnimplementedAuthServer) DecideLoginChallenge(context.Context, *DecideLoginChallengeRequest) (*DecideLoginChallengeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecideLoginChallenge not implemented")
+}
+func (UnimplementedAuthServer) PollLoginChallenge(context.Context, *PollLoginChallengeRequest) (*PollLoginChallengeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PollLoginChallenge not implemented")
+}
+func (UnimplementedAuthServer) RequireStepUp(context.Context, *RequireStepUpRequest) (*RequireStepUpResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequireStepUp not implemented")
+}
+func (UnimplementedAuthServer) Logout(context.Context, *LogoutRequest) (*LogoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedAuthServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedAuthServer) RevokeSession(context.Context, *RevokeSessionRequest) (*RevokeSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (UnimplementedAuthServer) MintTestToken(context.Context, *MintTestTokenRequest) (*MintTestTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MintTestToken not implemented")
+}
 func (UnimplementedAuthServer) mustEmbedUnimplementedAuthServer() {}
+func (UnimplementedAuthServer) testEmbeddedByValue()              {}
 
 // UnsafeAuthServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to AuthServer will
@@ -91,6 +745,13 @@ type UnsafeAuthServer interface {
 }
 
 func RegisterAuthServer(s grpc.ServiceRegistrar, srv AuthServer) {
+	// If the following call pancis, it indicates UnimplementedAuthServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
 	s.RegisterService(&Auth_ServiceDesc, srv)
 }
 
@@ -104,7 +765,7 @@ func _Auth_Register_Handler(srv interface{}, ctx context.Context, dec func(inter
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.Auth/Register",
+		FullMethod: Auth_Register_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(AuthServer).Register(ctx, req.(*RegisterRequest))
@@ -112,6 +773,24 @@ func _Auth_Register_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Auth_GetRegistrationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegistrationStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).GetRegistrationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_GetRegistrationStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).GetRegistrationStatus(ctx, req.(*GetRegistrationStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Auth_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(LoginRequest)
 	if err := dec(in); err != nil {
@@ -122,7 +801,7 @@ func _Auth_Login_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.Auth/Login",
+		FullMethod: Auth_Login_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(AuthServer).Login(ctx, req.(*LoginRequest))
@@ -140,7 +819,7 @@ func _Auth_IsAdmin_Handler(srv interface{}, ctx context.Context, dec func(interf
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.Auth/IsAdmin",
+		FullMethod: Auth_IsAdmin_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(AuthServer).IsAdmin(ctx, req.(*IsAdminRequest))
@@ -148,26 +827,593 @@ func _Auth_IsAdmin_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
-// Auth_ServiceDesc is the grpc.ServiceDesc for Auth service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var Auth_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "auth.Auth",
-	HandlerType: (*AuthServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Register",
-			Handler:    _Auth_Register_Handler,
-		},
-		{
-			MethodName: "Login",
-			Handler:    _Auth_Login_Handler,
-		},
-		{
-			MethodName: "IsAdmin",
-			Handler:    _Auth_IsAdmin_Handler,
+func _Auth_RequestPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestPasswordResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RequestPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RequestPasswordReset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RequestPasswordReset(ctx, req.(*RequestPasswordResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ResetPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ResetPassword_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ResetPassword(ctx, req.(*ResetPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_StartDeviceAuthorization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartDeviceAuthorizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).StartDeviceAuthorization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_StartDeviceAuthorization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).StartDeviceAuthorization(ctx, req.(*StartDeviceAuthorizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ApproveDeviceAuthorization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveDeviceAuthorizationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ApproveDeviceAuthorization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ApproveDeviceAuthorization_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ApproveDeviceAuthorization(ctx, req.(*ApproveDeviceAuthorizationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_PollDeviceToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PollDeviceTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).PollDeviceToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_PollDeviceToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).PollDeviceToken(ctx, req.(*PollDeviceTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RefreshToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RefreshToken(ctx, req.(*RefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RevokeRefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RevokeRefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RevokeRefreshToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RevokeRefreshToken(ctx, req.(*RevokeRefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ReportLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ReportLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ReportLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ReportLogin(ctx, req.(*ReportLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ReportLeakedSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportLeakedSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ReportLeakedSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ReportLeakedSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ReportLeakedSecret(ctx, req.(*ReportLeakedSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RequestUnfreeze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestUnfreezeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RequestUnfreeze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RequestUnfreeze_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RequestUnfreeze(ctx, req.(*RequestUnfreezeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_Unfreeze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnfreezeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Unfreeze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_Unfreeze_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Unfreeze(ctx, req.(*UnfreezeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_GetUserInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).GetUserInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_GetUserInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).GetUserInfo(ctx, req.(*GetUserInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_WhoAmI_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WhoAmIRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).WhoAmI(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_WhoAmI_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).WhoAmI(ctx, req.(*WhoAmIRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_IntrospectToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IntrospectTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).IntrospectToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_IntrospectToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).IntrospectToken(ctx, req.(*IntrospectTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_GetJwks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJwksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).GetJwks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_GetJwks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).GetJwks(ctx, req.(*GetJwksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_GetServiceStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServiceStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).GetServiceStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_GetServiceStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).GetServiceStatus(ctx, req.(*GetServiceStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_SetPushMfa_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPushMfaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).SetPushMfa(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_SetPushMfa_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).SetPushMfa(ctx, req.(*SetPushMfaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_UploadAvatar_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AuthServer).UploadAvatar(&grpc.GenericServerStream[UploadAvatarRequest, UploadAvatarResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Auth_UploadAvatarServer = grpc.ClientStreamingServer[UploadAvatarRequest, UploadAvatarResponse]
+
+func _Auth_ListLoginChallenges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLoginChallengesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ListLoginChallenges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ListLoginChallenges_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ListLoginChallenges(ctx, req.(*ListLoginChallengesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_DecideLoginChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecideLoginChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).DecideLoginChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_DecideLoginChallenge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).DecideLoginChallenge(ctx, req.(*DecideLoginChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_PollLoginChallenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PollLoginChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).PollLoginChallenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_PollLoginChallenge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).PollLoginChallenge(ctx, req.(*PollLoginChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RequireStepUp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequireStepUpRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RequireStepUp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RequireStepUp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RequireStepUp(ctx, req.(*RequireStepUpRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_ListSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_RevokeSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_MintTestToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MintTestTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).MintTestToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Auth_MintTestToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).MintTestToken(ctx, req.(*MintTestTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Auth_ServiceDesc is the grpc.ServiceDesc for Auth service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Auth_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Auth",
+	HandlerType: (*AuthServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _Auth_Register_Handler,
+		},
+		{
+			MethodName: "GetRegistrationStatus",
+			Handler:    _Auth_GetRegistrationStatus_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _Auth_Login_Handler,
+		},
+		{
+			MethodName: "IsAdmin",
+			Handler:    _Auth_IsAdmin_Handler,
+		},
+		{
+			MethodName: "RequestPasswordReset",
+			Handler:    _Auth_RequestPasswordReset_Handler,
+		},
+		{
+			MethodName: "ResetPassword",
+			Handler:    _Auth_ResetPassword_Handler,
+		},
+		{
+			MethodName: "StartDeviceAuthorization",
+			Handler:    _Auth_StartDeviceAuthorization_Handler,
+		},
+		{
+			MethodName: "ApproveDeviceAuthorization",
+			Handler:    _Auth_ApproveDeviceAuthorization_Handler,
+		},
+		{
+			MethodName: "PollDeviceToken",
+			Handler:    _Auth_PollDeviceToken_Handler,
+		},
+		{
+			MethodName: "RefreshToken",
+			Handler:    _Auth_RefreshToken_Handler,
+		},
+		{
+			MethodName: "RevokeRefreshToken",
+			Handler:    _Auth_RevokeRefreshToken_Handler,
+		},
+		{
+			MethodName: "ReportLogin",
+			Handler:    _Auth_ReportLogin_Handler,
+		},
+		{
+			MethodName: "ReportLeakedSecret",
+			Handler:    _Auth_ReportLeakedSecret_Handler,
+		},
+		{
+			MethodName: "RequestUnfreeze",
+			Handler:    _Auth_RequestUnfreeze_Handler,
+		},
+		{
+			MethodName: "Unfreeze",
+			Handler:    _Auth_Unfreeze_Handler,
+		},
+		{
+			MethodName: "GetUserInfo",
+			Handler:    _Auth_GetUserInfo_Handler,
+		},
+		{
+			MethodName: "WhoAmI",
+			Handler:    _Auth_WhoAmI_Handler,
+		},
+		{
+			MethodName: "IntrospectToken",
+			Handler:    _Auth_IntrospectToken_Handler,
+		},
+		{
+			MethodName: "GetJwks",
+			Handler:    _Auth_GetJwks_Handler,
+		},
+		{
+			MethodName: "GetServiceStatus",
+			Handler:    _Auth_GetServiceStatus_Handler,
+		},
+		{
+			MethodName: "SetPushMfa",
+			Handler:    _Auth_SetPushMfa_Handler,
+		},
+		{
+			MethodName: "ListLoginChallenges",
+			Handler:    _Auth_ListLoginChallenges_Handler,
+		},
+		{
+			MethodName: "DecideLoginChallenge",
+			Handler:    _Auth_DecideLoginChallenge_Handler,
+		},
+		{
+			MethodName: "PollLoginChallenge",
+			Handler:    _Auth_PollLoginChallenge_Handler,
+		},
+		{
+			MethodName: "RequireStepUp",
+			Handler:    _Auth_RequireStepUp_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _Auth_Logout_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _Auth_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _Auth_RevokeSession_Handler,
+		},
+		{
+			MethodName: "MintTestToken",
+			Handler:    _Auth_MintTestToken_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UploadAvatar",
+			Handler:       _Auth_UploadAvatar_Handler,
+			ClientStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "auth/v1/auth.proto",
 }
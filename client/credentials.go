@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// PerRPCCredentials attaches tokens of a token source to gRPC calls as
+// "authorization: Bearer <token>" metadata, the way the SSO service and the
+// services protected by it expect them. Pass it to grpc.WithPerRPCCredentials
+// to authenticate every call of a connection, or to grpc.PerRPCCredentials for a single call.
+type PerRPCCredentials struct {
+	Source *TokenSource
+	// AllowInsecure lets tokens be sent over connections without transport security,
+	// e.g. to a local sidecar. Tokens are bearer credentials, so leave it unset otherwise.
+	AllowInsecure bool
+}
+
+var _ credentials.PerRPCCredentials = PerRPCCredentials{}
+
+// GetRequestMetadata returns the authorization metadata of a call, fetching a token if needed.
+func (c PerRPCCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	token, err := c.Source.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+// RequireTransportSecurity reports whether calls must use transport security.
+func (c PerRPCCredentials) RequireTransportSecurity() bool {
+	return !c.AllowInsecure
+}
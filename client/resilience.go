@@ -0,0 +1,267 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy configures the retries of UnaryClientInterceptor. Backoffs grow by Multiplier
+// from InitialBackoff up to MaxBackoff, with up to 20% of jitter either way.
+type RetryPolicy struct {
+	MaxAttempts    int           // attempts per call, including the first; 1 disables retries
+	InitialBackoff time.Duration // delay before the first retry
+	MaxBackoff     time.Duration // longest delay between attempts
+	Multiplier     float64       // growth of the delay after each retry
+	// Codes retried in addition to RESOURCE_EXHAUSTED carrying a RetryInfo detail, which the
+	// server sends when it sheds load. Calls are retried no sooner than the delay a RetryInfo
+	// detail suggests, and not at all when it exceeds MaxBackoff, as for an exhausted
+	// registration quota. Errors such as INVALID_ARGUMENT or PERMISSION_DENIED fail the same
+	// way however often they are retried, so they are never worth listing.
+	RetryableCodes []codes.Code
+}
+
+// DefaultRetryPolicy returns a policy making up to 3 attempts, retrying only UNAVAILABLE,
+// which the server never returns after changing anything.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		RetryableCodes: []codes.Code{codes.Unavailable},
+	}
+}
+
+// Hooks are called as calls progress, e.g. to update metrics. Any of them may be nil.
+type Hooks struct {
+	// OnAttempt is called after every attempt with its number, starting at 1, its status code, and duration.
+	OnAttempt func(method string, attempt int, code codes.Code, duration time.Duration)
+	// OnRetry is called before waiting delay to make attempt.
+	OnRetry func(method string, attempt int, delay time.Duration)
+	// OnCircuitStateChange is called when the circuit breaker changes state.
+	OnCircuitStateChange func(from CircuitState, to CircuitState)
+}
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // calls go through
+	CircuitOpen                         // calls fail fast with UNAVAILABLE
+	CircuitHalfOpen                     // a single call probes whether the server recovered
+)
+
+// String returns the name of the state, e.g. for metric labels.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// failureCodes lists the codes telling that the server is unhealthy, rather than that the call was wrong.
+// RESOURCE_EXHAUSTED is left out, since it also reports quotas of single clients.
+var failureCodes = []codes.Code{codes.Unavailable, codes.DeadlineExceeded}
+
+// CircuitBreaker stops calling a server that keeps failing, safe for concurrent use. After
+// threshold consecutive attempts fail with UNAVAILABLE or DEADLINE_EXCEEDED, it opens and fails calls at once for cooldown; then it lets a single call probe the server,
+// closing if it succeeds and opening again otherwise.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int       // consecutive failures while closed
+	openedAt time.Time // moment the breaker last opened
+}
+
+// NewCircuitBreaker creates a closed circuit breaker.
+//
+// Parameters:
+//   - threshold: consecutive failed attempts opening the breaker
+//   - cooldown: how long the breaker stays open before probing the server
+//
+// Returns:
+//   - *CircuitBreaker: closed circuit breaker
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// State returns the current state of the breaker.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// allow reports whether an attempt may be made, moving an open breaker whose cooldown
+// passed to half-open for the attempt to probe the server.
+func (b *CircuitBreaker) allow(notify func(from CircuitState, to CircuitState)) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitClosed:
+		return true
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+
+		b.transition(CircuitHalfOpen, notify)
+
+		return true
+	default:
+		// A probe is already in flight.
+		return false
+	}
+}
+
+// record updates the breaker with the outcome of an attempt.
+func (b *CircuitBreaker) record(code codes.Code, notify func(from CircuitState, to CircuitState)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := slices.Contains(failureCodes, code)
+
+	switch {
+	case !failed:
+		b.failures = 0
+		if b.state != CircuitClosed {
+			b.transition(CircuitClosed, notify)
+		}
+	case b.state == CircuitHalfOpen:
+		b.openedAt = time.Now()
+		b.transition(CircuitOpen, notify)
+	case b.state == CircuitClosed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.failures = 0
+			b.openedAt = time.Now()
+			b.transition(CircuitOpen, notify)
+		}
+	}
+}
+
+// transition moves the breaker to state, which the caller holds the lock for.
+func (b *CircuitBreaker) transition(state CircuitState, notify func(from CircuitState, to CircuitState)) {
+	from := b.state
+	b.state = state
+
+	if notify != nil {
+		notify(from, state)
+	}
+}
+
+// UnaryClientInterceptor returns an interceptor retrying failed calls according to policy,
+// guarded by breaker, and reporting their progress to hooks. Pass it to
+// grpc.WithChainUnaryInterceptor. Calls honor their context while waiting between attempts.
+//
+// Parameters:
+//   - policy: retry policy, such as DefaultRetryPolicy()
+//   - breaker: circuit breaker shared by the calls to one server; nil for none
+//   - hooks: callbacks for metrics; the zero value for none
+//
+// Returns:
+//   - grpc.UnaryClientInterceptor: the interceptor
+//
+// Possible errors returned to callers:
+//   - codes.Unavailable: if the breaker is open, besides the errors of the last attempt
+func UnaryClientInterceptor(policy RetryPolicy, breaker *CircuitBreaker, hooks Hooks) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		backoff := policy.InitialBackoff
+
+		for attempt := 1; ; attempt++ {
+			if breaker != nil && !breaker.allow(hooks.OnCircuitStateChange) {
+				return status.Error(codes.Unavailable, "circuit breaker is open")
+			}
+
+			start := time.Now()
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			code := status.Code(err)
+
+			if hooks.OnAttempt != nil {
+				hooks.OnAttempt(method, attempt, code, time.Since(start))
+			}
+
+			if breaker != nil {
+				breaker.record(code, hooks.OnCircuitStateChange)
+			}
+
+			if err == nil || attempt >= policy.MaxAttempts {
+				return err
+			}
+
+			delay, ok := retryDelay(err, policy, backoff)
+			if !ok {
+				return err
+			}
+
+			if hooks.OnRetry != nil {
+				hooks.OnRetry(method, attempt+1, delay)
+			}
+
+			timer := time.NewTimer(delay)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+
+				return err
+			case <-timer.C:
+			}
+
+			backoff = min(time.Duration(float64(backoff)*policy.Multiplier), policy.MaxBackoff)
+		}
+	}
+}
+
+// retryDelay returns how long to wait before retrying a call that failed with err,
+// and whether it should be retried at all.
+func retryDelay(err error, policy RetryPolicy, backoff time.Duration) (time.Duration, bool) {
+	st := status.Convert(err)
+
+	// Up to 20% of jitter keeps clients failing together from retrying together.
+	delay := time.Duration(float64(backoff) * (0.8 + 0.4*rand.Float64()))
+
+	retryable := slices.Contains(policy.RetryableCodes, st.Code())
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			suggested := info.GetRetryDelay().AsDuration()
+			if suggested > policy.MaxBackoff {
+				return 0, false
+			}
+
+			return max(delay, suggested), retryable || st.Code() == codes.ResourceExhausted
+		}
+	}
+
+	return delay, retryable
+}
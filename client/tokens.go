@@ -0,0 +1,265 @@
+// Package client helps Go services call the SSO service and the services protected by it.
+// It logs in as a service account, caches the tokens per app, refreshes them before they
+// expire, and attaches them to outgoing gRPC calls.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultRefreshAhead is how long before a token expires it is refreshed, unless set with WithRefreshAhead.
+const DefaultRefreshAhead = time.Minute
+
+// ErrPushApprovalRequired is returned when the account has push approval of logins turned on.
+// Such accounts need a person to approve every login and cannot be used as service accounts.
+var ErrPushApprovalRequired = errors.New("login requires push approval")
+
+// Option configures a TokenSource.
+type Option func(*TokenSource)
+
+// WithRefreshAhead sets how long before a token expires it is refreshed.
+// Calls made in that window still get the current token while one of them refreshes it.
+func WithRefreshAhead(d time.Duration) Option {
+	return func(s *TokenSource) {
+		s.refreshAhead = d
+	}
+}
+
+// TokenSource provides tokens of an account for an app, safe for concurrent use.
+// The first call logs in; later calls reuse the token until it is about to expire,
+// and then exchange the refresh token of the login with RefreshToken, logging in again if it
+// can no longer be refreshed.
+type TokenSource struct {
+	auth         pb.AuthClient
+	email        string
+	password     string
+	appID        int32
+	refreshAhead time.Duration
+
+	mu           sync.Mutex
+	token        string
+	refreshToken string // next refresh token of the login; empty if the service issues none
+	expiresAt    time.Time
+	refreshing   chan struct{} // closed when the refresh in progress ends; nil if none is
+}
+
+// NewTokenSource creates a token source logging in to an app as an account.
+//
+// Parameters:
+//   - auth: client of the Auth service
+//   - email: email of the account, usually a service account
+//   - password: password of the account
+//   - appID: ID of the app tokens are issued for
+//   - opts: options, such as WithRefreshAhead
+//
+// Returns:
+//   - *TokenSource: token source that logs in on first use
+func NewTokenSource(auth pb.AuthClient, email string, password string, appID int32, opts ...Option) *TokenSource {
+	s := &TokenSource{
+		auth:         auth,
+		email:        email,
+		password:     password,
+		appID:        appID,
+		refreshAhead: DefaultRefreshAhead,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Token returns a valid token, logging in or refreshing it first if needed. Only one call
+// at a time talks to the SSO service; while the current token is still valid, other calls
+// get it meanwhile, and otherwise they wait.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - string: token for the app
+//   - error: nil on success, or the error of the failed login
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	for {
+		s.mu.Lock()
+
+		now := time.Now()
+		valid := s.token != "" && now.Before(s.expiresAt)
+
+		if valid && now.Before(s.expiresAt.Add(-s.refreshAhead)) {
+			token := s.token
+			s.mu.Unlock()
+
+			return token, nil
+		}
+
+		if s.refreshing == nil {
+			break
+		}
+
+		if valid {
+			token := s.token
+			s.mu.Unlock()
+
+			return token, nil
+		}
+
+		refreshing := s.refreshing
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-refreshing:
+		}
+	}
+
+	// The lock is still held here.
+	current, currentRefresh, currentExpiresAt := s.token, s.refreshToken, s.expiresAt
+	refreshing := make(chan struct{})
+	s.refreshing = refreshing
+	s.mu.Unlock()
+
+	token, refreshToken, expiresAt, err := s.fetch(ctx, current, currentRefresh, currentExpiresAt)
+
+	s.mu.Lock()
+	s.refreshing = nil
+	close(refreshing)
+
+	if err == nil {
+		s.token, s.refreshToken, s.expiresAt = token, refreshToken, expiresAt
+	}
+
+	s.mu.Unlock()
+
+	if err != nil {
+		// A token that has not expired yet is still good; the next call retries.
+		if current != "" && time.Now().Before(currentExpiresAt) {
+			return current, nil
+		}
+
+		return "", err
+	}
+
+	return token, nil
+}
+
+// Invalidate drops the cached token, e.g. after a call was rejected with UNAUTHENTICATED
+// because the token was revoked, so the next call to Token logs in again.
+func (s *TokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token, s.refreshToken, s.expiresAt = "", "", time.Time{}
+}
+
+// fetch exchanges refreshToken if it is set, or else refreshes current if it is set and has
+// not expired, which the service only allows while it issues no refresh tokens. It logs in
+// otherwise or if the refresh is rejected, and returns the new token with the next refresh
+// token and the expiration time of the token.
+func (s *TokenSource) fetch(ctx context.Context, current, refreshToken string, expiresAt time.Time) (string, string, time.Time, error) {
+	const op = "client.TokenSource.fetch"
+
+	var req *pb.RefreshTokenRequest
+
+	switch {
+	case refreshToken != "":
+		req = &pb.RefreshTokenRequest{RefreshToken: refreshToken}
+	case current != "" && time.Now().Before(expiresAt):
+		req = &pb.RefreshTokenRequest{Token: current}
+	}
+
+	token, next := "", ""
+
+	if req != nil {
+		resp, err := s.auth.RefreshToken(ctx, req)
+		switch status.Code(err) {
+		case codes.OK:
+			token, next = resp.GetToken(), resp.GetRefreshToken()
+		case codes.Unauthenticated, codes.PermissionDenied, codes.FailedPrecondition:
+			// Revoked, expired, or access to the app was withdrawn; logging in tells which.
+		default:
+			return "", "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if token == "" {
+		resp, err := s.auth.Login(ctx, &pb.LoginRequest{Email: s.email, Password: s.password, AppId: s.appID})
+		if err != nil {
+			return "", "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if resp.GetMfaChallengeId() != "" {
+			return "", "", time.Time{}, fmt.Errorf("%s: %w", op, ErrPushApprovalRequired)
+		}
+
+		token, next = resp.GetToken(), resp.GetRefreshToken()
+	}
+
+	// Tokens may be encrypted, so their expiration time is asked for rather than read.
+	resp, err := s.auth.WhoAmI(ctx, &pb.WhoAmIRequest{Token: token})
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, next, resp.GetExpiresAt().AsTime(), nil
+}
+
+// Cache holds a token source per app for one account, safe for concurrent use.
+type Cache struct {
+	auth     pb.AuthClient
+	email    string
+	password string
+	opts     []Option
+
+	mu      sync.Mutex
+	sources map[int32]*TokenSource
+}
+
+// NewCache creates a cache of token sources logging in as an account.
+//
+// Parameters:
+//   - auth: client of the Auth service
+//   - email: email of the account, usually a service account
+//   - password: password of the account
+//   - opts: options applied to every token source
+//
+// Returns:
+//   - *Cache: empty cache
+func NewCache(auth pb.AuthClient, email string, password string, opts ...Option) *Cache {
+	return &Cache{
+		auth:     auth,
+		email:    email,
+		password: password,
+		opts:     opts,
+		sources:  make(map[int32]*TokenSource),
+	}
+}
+
+// Source returns the token source of an app, creating it on first use.
+func (c *Cache) Source(appID int32) *TokenSource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.sources[appID]
+	if !ok {
+		s = NewTokenSource(c.auth, c.email, c.password, appID, c.opts...)
+		c.sources[appID] = s
+	}
+
+	return s
+}
+
+// Token returns a valid token for an app, like the Token method of its token source.
+func (c *Cache) Token(ctx context.Context, appID int32) (string, error) {
+	return c.Source(appID).Token(ctx)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	grpcapp "github.com/kirinyoku/sso-grpc/internal/app/grpc"
+	"github.com/kirinyoku/sso-grpc/internal/config"
+)
+
+// configUsage describes the config subcommand.
+const configUsage = "usage: sso config validate --file <path>"
+
+// runConfig runs the config subcommand with its arguments and returns the exit code:
+// 0 if the file is valid, 1 if it is not, and 2 for invalid arguments.
+//
+// `sso config validate --file prod.yml` loads the file like the service does, with
+// environment variables overriding it, and checks it with config.Validate without
+// starting the service, so pipelines can reject invalid files before deploying them.
+// The authorization policy file it names, if any, is checked as well.
+func runConfig(args []string) int {
+	if len(args) == 0 || args[0] != "validate" {
+		fmt.Fprintln(os.Stderr, configUsage)
+
+		return 2
+	}
+
+	flags := flag.NewFlagSet("sso config validate", flag.ContinueOnError)
+	path := flags.String("file", "", "Path to the config file to validate")
+
+	if err := flags.Parse(args[1:]); err != nil || *path == "" || flags.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, configUsage)
+
+		return 2
+	}
+
+	cfg, err := config.LoadByPath(*path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *path, err)
+
+		return 1
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s is invalid for env %q:\n", *path, cfg.Env)
+
+		var joined interface{ Unwrap() []error }
+		if errors.As(err, &joined) {
+			for _, problem := range joined.Unwrap() {
+				if !errors.Is(problem, config.ErrInvalidConfig) {
+					fmt.Fprintf(os.Stderr, "  - %v\n", problem)
+				}
+			}
+		}
+
+		return 1
+	}
+
+	if _, err := grpcapp.NewAuthzPolicy(slog.New(slog.DiscardHandler), cfg.GRPC.AuthzPolicy.File); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: grpc.authz_policy.file: %v\n", *path, err)
+
+		return 1
+	}
+
+	fmt.Printf("%s is valid for env %q\n", *path, cfg.Env)
+
+	return 0
+}
@@ -12,11 +12,19 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfig(os.Args[2:]))
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "snapshot" {
+		os.Exit(runSnapshot(os.Args[2:]))
+	}
+
 	cfg := config.MustLoad()
 
 	log := logger.New(cfg)
 
-	application := app.New(log, cfg.GRPC.Port, cfg.StoragePath, cfg.TokenTTL)
+	application := app.New(log, cfg)
 
 	go application.GRPCSrv.MustRun()
 
@@ -27,5 +35,5 @@ func main() {
 
 	log.Info("stopping application", slog.String("signal", sig.String()))
 
-	application.GRPCSrv.Stop()
+	application.Stop()
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/kirinyoku/sso-grpc/internal/services/snapshot"
+)
+
+// snapshotUsage describes the snapshot subcommand.
+const snapshotUsage = "usage: SNAPSHOTS_KEY=<key> sso snapshot decrypt --in <snapshot> --object <object key> --out <database>"
+
+// runSnapshot runs the snapshot subcommand with its arguments and returns the exit code:
+// 0 if the snapshot was restored, 1 if it could not be, and 2 for invalid arguments.
+//
+// `sso snapshot decrypt --in sso-20240131T020000Z.db.enc --object sso/sso-20240131T020000Z.db.enc
+// --out sso.db` decrypts a snapshot downloaded from object storage with the key in SNAPSHOTS_KEY,
+// which is read from the environment so it does not end up in the shell history. Snapshots are
+// bound to the object key they were uploaded under, so one put in place of another fails to
+// decrypt. The service runs on the restored database once it is put at storage_path.
+func runSnapshot(args []string) int {
+	if len(args) == 0 || args[0] != "decrypt" {
+		fmt.Fprintln(os.Stderr, snapshotUsage)
+
+		return 2
+	}
+
+	flags := flag.NewFlagSet("sso snapshot decrypt", flag.ContinueOnError)
+	in := flags.String("in", "", "Path to the snapshot to decrypt")
+	object := flags.String("object", "", "Object key the snapshot was downloaded from")
+	out := flags.String("out", "", "Path to write the database to; must not exist")
+
+	if err := flags.Parse(args[1:]); err != nil || *in == "" || *object == "" || *out == "" || flags.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, snapshotUsage)
+
+		return 2
+	}
+
+	sealed, err := os.Open(*in)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+
+		return 1
+	}
+
+	defer sealed.Close()
+
+	file, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+
+		return 1
+	}
+
+	// The database is written as the snapshot is decrypted, so a failed one is removed.
+	err = snapshot.Decrypt(os.Getenv("SNAPSHOTS_KEY"), *object, sealed, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(*out)
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *in, err)
+
+		return 1
+	}
+
+	fmt.Printf("%s restored to %s\n", *in, *out)
+
+	return 0
+}
@@ -4,11 +4,50 @@
 package app
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
 	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
 	"time"
 
 	grpcapp "github.com/kirinyoku/sso-grpc/internal/app/grpc"
+	radiusapp "github.com/kirinyoku/sso-grpc/internal/app/radius"
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/attributes"
+	"github.com/kirinyoku/sso-grpc/internal/lib/blob"
+	"github.com/kirinyoku/sso-grpc/internal/lib/cachebus"
+	"github.com/kirinyoku/sso-grpc/internal/lib/clock"
+	"github.com/kirinyoku/sso-grpc/internal/lib/disposable"
+	"github.com/kirinyoku/sso-grpc/internal/lib/events"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jobs"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/lib/mailer"
+	"github.com/kirinyoku/sso-grpc/internal/lib/ntp"
+	"github.com/kirinyoku/sso-grpc/internal/lib/passhash"
+	"github.com/kirinyoku/sso-grpc/internal/lib/pii"
+	"github.com/kirinyoku/sso-grpc/internal/lib/s3"
+	"github.com/kirinyoku/sso-grpc/internal/lib/templates"
+	"github.com/kirinyoku/sso-grpc/internal/services/admin"
+	"github.com/kirinyoku/sso-grpc/internal/services/announcement"
+	"github.com/kirinyoku/sso-grpc/internal/services/audit"
 	"github.com/kirinyoku/sso-grpc/internal/services/auth"
+	"github.com/kirinyoku/sso-grpc/internal/services/diagnostics"
+	"github.com/kirinyoku/sso-grpc/internal/services/keyring"
+	"github.com/kirinyoku/sso-grpc/internal/services/logout"
+	"github.com/kirinyoku/sso-grpc/internal/services/outbox"
+	"github.com/kirinyoku/sso-grpc/internal/services/slo"
+	"github.com/kirinyoku/sso-grpc/internal/services/snapshot"
+	"github.com/kirinyoku/sso-grpc/internal/services/usage"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
 	"github.com/kirinyoku/sso-grpc/internal/storage/sqlite"
 )
 
@@ -17,6 +56,35 @@ import (
 type App struct {
 	// GRPCSrv is the gRPC server instance that handles all incoming API requests.
 	GRPCSrv *grpcapp.App
+
+	// stopBackground cancels background tasks such as blocklist refreshes.
+	stopBackground context.CancelFunc
+}
+
+// Option customizes the application built by New.
+type Option func(*options)
+
+// options holds the settings collected from Option values.
+type options struct {
+	authHooks []auth.Hook // hooks run around registration and login
+	clock     clock.Clock // source of the current time
+}
+
+// WithAuthHooks registers hooks that run around registration and login,
+// letting embedders add custom checks without modifying core code.
+// Hooks run in the order they are registered.
+func WithAuthHooks(hooks ...auth.Hook) Option {
+	return func(o *options) {
+		o.authHooks = append(o.authHooks, hooks...)
+	}
+}
+
+// WithClock replaces the system clock used to issue and validate tokens,
+// e.g. to exercise expiration in tests without waiting.
+func WithClock(clk clock.Clock) Option {
+	return func(o *options) {
+		o.clock = clk
+	}
 }
 
 // New creates and initializes a new instance of the application.
@@ -24,26 +92,611 @@ type App struct {
 //
 // Parameters:
 //   - log: logger instance for application-wide logging
-//   - grpcPort: TCP port on which the gRPC server will listen
-//   - storagePath: filesystem path for the SQLite database
-//   - tokenTTL: duration for which JWT tokens should remain valid
+//   - cfg: application configuration
+//   - opts: optional customizations such as WithAuthHooks
 //
 // Returns:
 //   - *App: fully initialized application instance
 //
-// Note: The function will panic if it fails to initialize the storage layer,
-// as the application cannot function without a working database connection.
-func New(log *slog.Logger, grpcPort int, storagePath string, tokenTTL time.Duration) *App {
-	storage, err := sqlite.New(storagePath)
+// Note: The function will panic if the configuration fails Validate, or if it fails to
+// initialize the storage layer or the gRPC server, as the application cannot function without them.
+func New(log *slog.Logger, cfg *config.Config, opts ...Option) *App {
+	o := options{clock: clock.System}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+
+	var err error
+
+	var piiCipher *pii.Cipher
+	if cfg.PII.Key != "" {
+		piiCipher, err = pii.New(cfg.PII.Key)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	storage, err := sqlite.New(cfg.StoragePath, piiCipher, log, cfg.QueryLog, cfg.SQLite)
+	if err != nil {
+		panic(err)
+	}
+
+	if err := checkAdminApp(context.Background(), storage, cfg.GRPC.AdminAppID); err != nil {
+		panic(err)
+	}
+
+	mail := mailer.New(log, cfg.SMTP)
+
+	emailTemplates, err := templates.New(cfg.EmailTemplates.Dir, cfg.EmailTemplates.DefaultLocale)
+	if err != nil {
+		panic(err)
+	}
+
+	disposableCfg := cfg.Registration.DisposableEmails
+	disposableList := disposable.New(log, disposableCfg.ListURL)
+
+	hasher, err := passhash.New(log, cfg.PasswordHashing)
+	if err != nil {
+		panic(err)
+	}
+
+	auditLog := audit.New(log, storage, cfg.Audit.Key)
+
+	logouts := logout.New(log, storage, cfg.BackchannelLogout, o.clock)
+
+	dispatcher := outbox.New(log, storage, cfg.Outbox, o.clock)
+	dispatcher.Handle(models.TopicBackchannelLogout, logouts.Deliver, cfg.BackchannelLogout.Attempts, cfg.BackchannelLogout.Backoff)
+
+	eventBus := events.New(log)
+
+	var avatarStore blob.Store
+	if cfg.Avatars.Dir != "" {
+		fsStore, err := blob.NewFS(cfg.Avatars.Dir)
+		if err != nil {
+			panic(err)
+		}
+
+		avatarStore = fsStore
+	}
+
+	signingKeys, err := loadSigningKeys(cfg.TokenSigning)
+	if err != nil {
+		panic(err)
+	}
+
+	authService, err := auth.New(
+		log,
+		storage,
+		mail,
+		emailTemplates,
+		disposableList,
+		hasher,
+		auditLog,
+		eventBus,
+		avatarStore,
+		signingKeys,
+		cfg.TokenTTL,
+		cfg.Region,
+		cfg.TokenClaims,
+		cfg.PasswordReset,
+		cfg.Registration,
+		cfg.Enforcement,
+		cfg.DeviceAuthorization,
+		cfg.PushMFA,
+		cfg.StepUp,
+		cfg.TokenValidation,
+		cfg.RefreshTokens,
+		cfg.LoginNotifications,
+		cfg.StorageTimeouts,
+		cfg.UserAttributes,
+		cfg.SecretLeaks,
+		cfg.Avatars,
+		cfg.TestTokens,
+		o.clock,
+		o.authHooks...,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	events.Subscribe(eventBus, authService.NotifyLogin)
+	subscribeMetrics(eventBus)
+
+	attributeSchema, err := attributes.NewSchema(cfg.UserAttributes.Types)
+	if err != nil {
+		panic("user_attributes.types: " + err.Error())
+	}
+
+	cacheBus := cachebus.New(log, storage, cfg.CacheBus.PollInterval, cfg.CacheBus.Retention)
+	cacheBus.Subscribe(cachebus.TopicRoles, authService.InvalidateRole)
+	cacheBus.Subscribe(cachebus.TopicTokens, authService.InvalidateTokens)
+
+	announcements := announcement.New(log, storage)
+	if err := announcements.Load(context.Background()); err != nil {
+		panic(err)
+	}
+
+	cacheBus.Subscribe(cachebus.TopicAnnouncement, announcements.Invalidate)
+
+	signingKeyring := keyring.New(log, storage, cacheBus, signingKeys, cfg.TokenSigning, o.clock)
+	if err := signingKeyring.Load(context.Background()); err != nil {
+		panic(err)
+	}
+
+	cacheBus.Subscribe(cachebus.TopicSigningKeys, signingKeyring.Invalidate)
+
+	watchdog := diagnostics.New(log, cfg.Diagnostics)
+
+	usageMeter := usage.New(log, storage, cfg.Usage)
+
+	sloTracker := slo.New(log, cfg.SLO, o.clock)
+
+	authzPolicy, err := grpcapp.NewAuthzPolicy(log, cfg.GRPC.AuthzPolicy.File)
 	if err != nil {
 		panic(err)
 	}
 
-	authService := auth.New(log, storage, tokenTTL)
+	scheduler := jobs.New(log, o.clock)
+	scheduler.Register(jobs.Job{
+		Name:        "outbox",
+		Description: "Delivers due outbox messages, such as back-channel logouts.",
+		Interval:    cfg.Outbox.PollInterval,
+		Run:         dispatcher.Dispatch,
+	})
+
+	if disposableCfg.ListURL != "" {
+		scheduler.Register(jobs.Job{
+			Name:        "disposable_emails",
+			Description: "Downloads the list of disposable email domains.",
+			Interval:    disposableCfg.RefreshInterval,
+			Run:         disposableList.Refresh,
+		})
+	}
+
+	if cfg.GRPC.AuthzPolicy.File != "" {
+		scheduler.Register(jobs.Job{
+			Name:        "authz_policy",
+			Description: "Applies changes to the authorization policy file.",
+			Interval:    cfg.GRPC.AuthzPolicy.ReloadInterval,
+			Run:         authzPolicy.Reload,
+		})
+	}
+
+	if cfg.Usage.FlushInterval > 0 {
+		scheduler.Register(jobs.Job{
+			Name:        "usage",
+			Description: "Flushes request counts and removes expired usage rollups.",
+			Interval:    cfg.Usage.FlushInterval,
+			Run:         usageMeter.Maintain,
+			RunOnStop:   true,
+		})
+	}
+
+	if signingKeys != nil {
+		scheduler.Register(jobs.Job{
+			Name:        "signing_keys",
+			Description: "Drops signing keys past their grace period and rotates the signing key on schedule.",
+			Interval:    cfg.TokenSigning.CheckInterval,
+			Run:         signingKeyring.Maintain,
+		})
+	}
+
+	if len(cfg.SLO.Objectives) > 0 {
+		scheduler.Register(jobs.Job{
+			Name:        "slo",
+			Description: "Computes error budgets and burn rates of the SLOs and warns when budgets burn too fast.",
+			Interval:    cfg.SLO.CheckInterval,
+			Run:         sloTracker.Check,
+		})
+	}
+
+	if snapshots := cfg.Snapshots; snapshots.Interval > 0 {
+		bucket, err := s3.New(snapshots.Endpoint, snapshots.Region, snapshots.Bucket, snapshots.AccessKeyID, snapshots.SecretAccessKey, snapshots.Timeout)
+		if err != nil {
+			panic(err)
+		}
+
+		uploader, err := snapshot.New(log, storage, bucket, snapshots, o.clock)
+		if err != nil {
+			panic(err)
+		}
+
+		scheduler.Register(jobs.Job{
+			Name:        "snapshots",
+			Description: "Uploads an encrypted snapshot of the database to object storage and deletes expired ones.",
+			Interval:    snapshots.Interval,
+			Run:         uploader.Run,
+		})
+	}
+
+	adminService := admin.New(log, storage, emailTemplates, cfg.Stats.CacheTTL, auditLog, cacheBus, cfg.SupportTokens, attributeSchema, watchdog, scheduler, cfg.TokenFormat, signingKeys, signingKeyring, authService, cfg.GRPC.AdminAppID)
+
+	if err := bootstrap(context.Background(), log, cfg.Bootstrap, storage, authService, adminService, os.Stderr); err != nil {
+		panic(err)
+	}
+
+	grpcApp, err := grpcapp.New(log, cfg.GRPC, authService, adminService, authService, adminService, storage, auditLog, usageMeter, announcements, sloTracker, authzPolicy)
+	if err != nil {
+		panic(err)
+	}
+
+	var radiusApp *radiusapp.App
+	if cfg.RADIUS.Addr != "" {
+		radiusApp, err = radiusapp.New(log, cfg.RADIUS, authService)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	authService.RunRegistrationWorkers(ctx)
+
+	go scheduler.Run(ctx)
+
+	go cacheBus.Run(ctx)
+
+	go watchdog.Run(ctx)
+
+	if cfg.ClockCheck.NTPServer != "" {
+		go checkClock(ctx, log, cfg.ClockCheck, o.clock)
+	}
+
+	if cfg.Metrics.Addr != "" {
+		go serveMetrics(ctx, log, cfg.Metrics.Addr)
+	}
+
+	if avatarStore != nil && cfg.Avatars.Addr != "" {
+		go serveAvatars(ctx, log, cfg.Avatars.Addr, avatarStore)
+	}
+
+	if cfg.Introspection.Addr != "" {
+		go serveIntrospection(ctx, log, cfg.Introspection.Addr, authService)
+	}
+
+	if signingKeys != nil && cfg.TokenSigning.JWKSAddr != "" {
+		go serveJWKS(ctx, log, cfg.TokenSigning.JWKSAddr, signingKeys)
+	}
 
-	grpcApp := grpcapp.New(log, grpcPort, authService)
+	if radiusApp != nil {
+		go radiusApp.Run(ctx)
+	}
 
 	return &App{
-		GRPCSrv: grpcApp,
+		GRPCSrv:        grpcApp,
+		stopBackground: cancel,
+	}
+}
+
+// Stop gracefully stops the gRPC server and background tasks, including the RADIUS listener.
+func (a *App) Stop() {
+	a.GRPCSrv.Stop()
+	a.stopBackground()
+}
+
+// checkAdminApp fails if the admin app belongs to an app family. Every app of the family
+// signs tokens with the family secret, so each could sign tokens admin methods accept.
+// An admin app that does not exist yet passes, since no tokens can be issued for it.
+func checkAdminApp(ctx context.Context, apps *sqlite.Storage, adminAppID int32) error {
+	if adminAppID == 0 {
+		return nil
+	}
+
+	app, err := apps.App(ctx, adminAppID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("read admin app %d: %w", adminAppID, err)
+	}
+
+	if app.FamilyID != 0 {
+		return fmt.Errorf("grpc.admin_app_id: app %d belongs to app family %d, whose other apps could sign its tokens; move it out of the family first", adminAppID, app.FamilyID)
+	}
+
+	return nil
+}
+
+// checkClock compares the clock against an NTP server once and logs an error if the
+// offset exceeds the configured maximum. Skewed clocks make tokens expire early or late
+// without any other symptom, so the check runs at startup rather than on failures.
+func checkClock(ctx context.Context, log *slog.Logger, cfg config.ClockCheck, clk clock.Clock) {
+	const op = "app.checkClock"
+
+	log = log.With(
+		slog.String("op", op),
+		slog.String("ntp_server", cfg.NTPServer),
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	offset, err := ntp.Offset(ctx, cfg.NTPServer, clk)
+	if err != nil {
+		log.Warn("failed to check clock against ntp server", slog.String("error", err.Error()))
+
+		return
+	}
+
+	if offset.Abs() > cfg.MaxSkew {
+		log.Error("SYSTEM CLOCK IS SKEWED: token expiration will be validated incorrectly",
+			slog.Duration("offset", offset),
+			slog.Duration("max_skew", cfg.MaxSkew),
+		)
+
+		return
+	}
+
+	log.Info("clock checked against ntp server", slog.Duration("offset", offset))
+}
+
+// serveMetrics serves the counters published with expvar, such as storage_slow_queries
+// and runtime_goroutines, as JSON at /debug/vars on addr until ctx is canceled. Failures
+// are logged rather than stopping the service, since metrics are not needed to serve requests.
+func serveMetrics(ctx context.Context, log *slog.Logger, addr string) {
+	const op = "app.serveMetrics"
+
+	log = log.With(
+		slog.String("op", op),
+		slog.String("addr", addr),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	log.Info("metrics server started")
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("metrics server failed", slog.String("error", err.Error()))
+	}
+}
+
+// serveAvatars serves the avatar images in store over HTTP at /avatars/<key> on addr until
+// ctx is canceled. Keys are derived from the content of the images, so responses may be
+// cached forever. Failures are logged rather than stopping the service, like serveMetrics.
+func serveAvatars(ctx context.Context, log *slog.Logger, addr string, store blob.Store) {
+	const op = "app.serveAvatars"
+
+	log = log.With(
+		slog.String("op", op),
+		slog.String("addr", addr),
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /avatars/{key}", func(w http.ResponseWriter, r *http.Request) {
+		key := r.PathValue("key")
+
+		image, err := store.Open(r.Context(), key)
+		if err != nil {
+			if errors.Is(err, blob.ErrNotFound) || errors.Is(err, blob.ErrInvalidKey) {
+				http.NotFound(w, r)
+
+				return
+			}
+
+			log.Error("failed to open avatar", slog.String("key", key), slog.String("error", err.Error()))
+			http.Error(w, "internal error", http.StatusInternalServerError)
+
+			return
+		}
+
+		defer image.Close()
+
+		w.Header().Set("Content-Type", mime.TypeByExtension(path.Ext(key)))
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		if _, err := io.Copy(w, image); err != nil {
+			log.Warn("failed to send avatar", slog.String("key", key), slog.String("error", err.Error()))
+		}
+	})
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	log.Info("avatar server started")
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("avatar server failed", slog.String("error", err.Error()))
+	}
+}
+
+// introspectionResponse is the JSON body of RFC 7662 introspection responses. Every field
+// but active is omitted for tokens that are not active.
+type introspectionResponse struct {
+	Active    bool     `json:"active"`
+	TokenType string   `json:"token_type,omitempty"`
+	Sub       string   `json:"sub,omitempty"`
+	Username  string   `json:"username,omitempty"`
+	ClientID  string   `json:"client_id,omitempty"`
+	Aud       []string `json:"aud,omitempty"`
+	Iat       int64    `json:"iat,omitempty"`
+	Exp       int64    `json:"exp,omitempty"`
+	AuthTime  int64    `json:"auth_time,omitempty"`
+	AMR       []string `json:"amr,omitempty"`
+	ACR       string   `json:"acr,omitempty"`
+	Iss       string   `json:"iss,omitempty"`
+	JTI       string   `json:"jti,omitempty"`
+}
+
+// serveIntrospection serves RFC 7662 token introspection over HTTP at POST /introspect on
+// addr until ctx is canceled, like Auth.IntrospectToken over gRPC. Failures are logged
+// rather than stopping the service, like serveMetrics.
+func serveIntrospection(ctx context.Context, log *slog.Logger, addr string, authService *auth.Auth) {
+	const op = "app.serveIntrospection"
+
+	log = log.With(
+		slog.String("op", op),
+		slog.String("addr", addr),
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /introspect", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+
+		token := r.PostFormValue("token")
+		if token == "" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error":"invalid_request","error_description":"token is required"}`)
+
+			return
+		}
+
+		in, err := authService.Introspect(r.Context(), token, r.PostFormValue("token_type_hint"))
+		if err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+
+			return
+		}
+
+		resp := introspectionResponse{Active: in.Active}
+
+		if in.Active {
+			resp.TokenType = in.TokenType
+			resp.Sub = in.User.PublicID
+			resp.Username = in.User.Email
+			resp.ClientID = strconv.Itoa(int(in.AppID))
+			resp.Exp = in.ExpiresAt.Unix()
+			resp.AMR = in.Authn.Methods
+			resp.ACR = in.Authn.ACR
+			resp.Iss = in.Issuer
+			resp.JTI = in.TokenID
+
+			for _, appID := range in.Audience {
+				resp.Aud = append(resp.Aud, strconv.Itoa(int(appID)))
+			}
+
+			if !in.IssuedAt.IsZero() {
+				resp.Iat = in.IssuedAt.Unix()
+			}
+
+			if !in.Authn.Time.IsZero() {
+				resp.AuthTime = in.Authn.Time.Unix()
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Warn("failed to send introspection response", slog.String("error", err.Error()))
+		}
+	})
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	log.Info("introspection server started")
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("introspection server failed", slog.String("error", err.Error()))
+	}
+}
+
+// loadSigningKeys loads the RSA key signing RS256 tokens from the configured PEM, or the
+// PEM file. Returns nil keys if neither is configured.
+func loadSigningKeys(cfg config.TokenSigning) (*jwt.Keys, error) {
+	const op = "app.loadSigningKeys"
+
+	privateKey := []byte(cfg.PrivateKey)
+
+	if len(privateKey) == 0 {
+		if cfg.PrivateKeyFile == "" {
+			return nil, nil
+		}
+
+		var err error
+
+		privateKey, err = os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	keys, err := jwt.NewKeys(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+// serveJWKS serves the public keys of RS256 tokens as a JSON Web Key Set over HTTP at
+// /.well-known/jwks.json on addr until ctx is canceled. The set is encoded on every request,
+// so rotated keys are served at once. Failures are logged rather than stopping the service,
+// like serveMetrics.
+func serveJWKS(ctx context.Context, log *slog.Logger, addr string, keys *jwt.Keys) {
+	const op = "app.serveJWKS"
+
+	log = log.With(
+		slog.String("op", op),
+		slog.String("addr", addr),
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		body, err := json.Marshal(map[string][]jwt.JWK{"keys": keys.JWKS()})
+		if err != nil {
+			log.Error("failed to encode key set", slog.String("error", err.Error()))
+			http.Error(w, "internal error", http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+
+		if _, err := w.Write(body); err != nil {
+			log.Warn("failed to send key set", slog.String("error", err.Error()))
+		}
+	})
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	log.Info("key set server started")
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("key set server failed", slog.String("error", err.Error()))
 	}
 }
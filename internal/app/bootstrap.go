@@ -0,0 +1,121 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/services/admin"
+	"github.com/kirinyoku/sso-grpc/internal/services/auth"
+)
+
+// generatedSecretBytes is the number of random bytes in generated passwords and app secrets.
+const generatedSecretBytes = 24
+
+// emptyChecker reports whether the database has neither users nor apps.
+type emptyChecker interface {
+	IsEmpty(ctx context.Context) (bool, error)
+}
+
+// bootstrap creates the first admin and, if configured, the first app on an empty database,
+// so a new deployment is usable without manual SQL. It does nothing once the database has
+// users or apps, or when no admin email is configured. Passwords and secrets that are not
+// configured are generated and written to out, never to the log, as they are shown only once.
+func bootstrap(
+	ctx context.Context,
+	log *slog.Logger,
+	cfg config.Bootstrap,
+	storage emptyChecker,
+	authService *auth.Auth,
+	adminService *admin.Admin,
+	out io.Writer,
+) error {
+	const op = "app.bootstrap"
+
+	if cfg.AdminEmail == "" {
+		return nil
+	}
+
+	log = log.With(slog.String("op", op))
+
+	empty, err := storage.IsEmpty(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !empty {
+		log.Debug("database is not empty, skipping bootstrap")
+
+		return nil
+	}
+
+	password, generatedPassword := cfg.AdminPassword, false
+	if password == "" {
+		if password, err = generateSecret(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		generatedPassword = true
+	}
+
+	userID, _, err := authService.Register(ctx, cfg.AdminEmail, password, 0, auth.ClientInfo{})
+	if err != nil {
+		return fmt.Errorf("%s: register admin: %w", op, err)
+	}
+
+	user, err := adminService.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := adminService.UpdateUser(ctx, userID, true, user.Version); err != nil {
+		return fmt.Errorf("%s: grant admin: %w", op, err)
+	}
+
+	log.Warn("bootstrapped first admin", slog.Int64("user_id", userID))
+
+	if generatedPassword {
+		fmt.Fprintf(out, "bootstrap: admin %s was created with password %s\n", cfg.AdminEmail, password)
+	}
+
+	if cfg.AppName == "" {
+		return nil
+	}
+
+	secret, generatedAppSecret := cfg.AppSecret, false
+	if secret == "" {
+		if secret, err = generateSecret(); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		generatedAppSecret = true
+	}
+
+	appID, err := adminService.CreateApp(ctx, cfg.AppName, secret, "", false, false, 0)
+	if err != nil {
+		return fmt.Errorf("%s: create app: %w", op, err)
+	}
+
+	log.Warn("bootstrapped first app", slog.Int("app_id", int(appID)))
+
+	if generatedAppSecret {
+		fmt.Fprintf(out, "bootstrap: app %s was created with ID %d and secret %s\n", cfg.AppName, appID, secret)
+	}
+
+	return nil
+}
+
+// generateSecret returns a random URL-safe string for a generated password or app secret.
+func generateSecret() (string, error) {
+	b := make([]byte, generatedSecretBytes)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
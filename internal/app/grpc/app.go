@@ -4,41 +4,502 @@
 package grpcapp
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
+	"net/netip"
+	"os"
+	"time"
 
+	adminv1 "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	authv1 "github.com/kirinyoku/sso-grpc/api/auth/v1"
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	admingrpc "github.com/kirinyoku/sso-grpc/internal/grpc/admin"
 	authgrpc "github.com/kirinyoku/sso-grpc/internal/grpc/auth"
+	"github.com/kirinyoku/sso-grpc/internal/grpc/connstats"
+	"github.com/kirinyoku/sso-grpc/internal/grpc/interceptors"
+	"github.com/kirinyoku/sso-grpc/internal/lib/authzpolicy"
+	"github.com/kirinyoku/sso-grpc/internal/lib/netaddr"
+	"github.com/kirinyoku/sso-grpc/internal/lib/proxyproto"
 	"google.golang.org/grpc"
+	grpcadmin "google.golang.org/grpc/admin"
+	channelz "google.golang.org/grpc/channelz/service"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	xdscreds "google.golang.org/grpc/credentials/xds"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/xds"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
+// server is the subset of *grpc.Server and *xds.GRPCServer used by App,
+// allowing either to back the application.
+type server interface {
+	grpc.ServiceRegistrar
+	Serve(l net.Listener) error
+	GracefulStop()
+}
+
 // App represents the gRPC server application.
 // It encapsulates the gRPC server and its configuration.
 type App struct {
-	log        *slog.Logger // Logger for application events
-	gRPCServer *grpc.Server // gRPC server instance
-	port       int          // TCP port on which the server listens
+	log            *slog.Logger   // Logger for application events
+	gRPCServer     server         // gRPC server instance
+	port           int            // TCP port on which the server listens
+	adminCleanup   func()         // Releases resources held by the gRPC admin services, if registered
+	proxyProtocol  bool           // Read client addresses from PROXY protocol headers
+	trustedProxies []netip.Prefix // Peers whose PROXY headers are read; all peers if empty
+}
+
+// proxyHeaderTimeout bounds how long a connection may take to send its PROXY protocol header.
+const proxyHeaderTimeout = 5 * time.Second
+
+// adminMethods lists the gRPC methods that may only be called with an admin token, unless
+// the authorization policy file sets another level.
+var adminMethods = []string{
+	adminv1.Admin_CreateApp_FullMethodName,
+	adminv1.Admin_DeleteApp_FullMethodName,
+	adminv1.Admin_GetApp_FullMethodName,
+	adminv1.Admin_ListApps_FullMethodName,
+	adminv1.Admin_UpdateApp_FullMethodName,
+	adminv1.Admin_RotateAppSecret_FullMethodName,
+	adminv1.Admin_GetUser_FullMethodName,
+	adminv1.Admin_UpdateUser_FullMethodName,
+	adminv1.Admin_FreezeUser_FullMethodName,
+	adminv1.Admin_RevokeAllUserTokens_FullMethodName,
+	adminv1.Admin_MergeUsers_FullMethodName,
+	adminv1.Admin_GetUserAttributes_FullMethodName,
+	adminv1.Admin_SetUserAttributes_FullMethodName,
+	adminv1.Admin_FindUsersByAttribute_FullMethodName,
+	adminv1.Admin_SearchUsers_FullMethodName,
+	adminv1.Admin_GetUserLabels_FullMethodName,
+	adminv1.Admin_SetUserLabels_FullMethodName,
+	adminv1.Admin_GrantAppAccess_FullMethodName,
+	adminv1.Admin_RevokeAppAccess_FullMethodName,
+	adminv1.Admin_AddAppOwner_FullMethodName,
+	adminv1.Admin_RemoveAppOwner_FullMethodName,
+	adminv1.Admin_ListAppOwners_FullMethodName,
+	adminv1.Admin_CreateAppFamily_FullMethodName,
+	adminv1.Admin_GetAppFamily_FullMethodName,
+	adminv1.Admin_DeleteAppFamily_FullMethodName,
+	adminv1.Admin_InvalidatePasswordResetTokens_FullMethodName,
+	adminv1.Admin_RevokeAppTokens_FullMethodName,
+	adminv1.Admin_RenderEmailTemplate_FullMethodName,
+	adminv1.Admin_GetAppEmailDomains_FullMethodName,
+	adminv1.Admin_SetAppEmailDomains_FullMethodName,
+	adminv1.Admin_GetAppDisposableEmailPolicy_FullMethodName,
+	adminv1.Admin_SetAppDisposableEmailPolicy_FullMethodName,
+	adminv1.Admin_GetAppLabels_FullMethodName,
+	adminv1.Admin_SetAppLabels_FullMethodName,
+	adminv1.Admin_GetStats_FullMethodName,
+	adminv1.Admin_GetUsage_FullMethodName,
+	adminv1.Admin_VerifyAuditLog_FullMethodName,
+	adminv1.Admin_IssueSupportToken_FullMethodName,
+	adminv1.Admin_RevokeSupportToken_FullMethodName,
+	adminv1.Admin_SetAnnouncement_FullMethodName,
+	adminv1.Admin_ReloadApps_FullMethodName,
+	adminv1.Admin_SetUserPushMfa_FullMethodName,
+	adminv1.Admin_SetAppBackchannelLogoutUri_FullMethodName,
+	adminv1.Admin_SetAppTokenTtl_FullMethodName,
+	adminv1.Admin_SetAppTestOnly_FullMethodName,
+	adminv1.Admin_GetDiagnostics_FullMethodName,
+	adminv1.Admin_ListJobs_FullMethodName,
+	adminv1.Admin_GetJobStatus_FullMethodName,
+	adminv1.Admin_RunJobNow_FullMethodName,
+	adminv1.Admin_ListSigningKeys_FullMethodName,
+	adminv1.Admin_RotateSigningKey_FullMethodName,
+	adminv1.Admin_SimulateLogin_FullMethodName,
+	adminv1.Admin_SimulatePolicy_FullMethodName,
+	adminv1.Admin_GetUserAtTime_FullMethodName,
+	authv1.Auth_MintTestToken_FullMethodName,
+}
+
+// supportMethods lists the read-only admin methods that also accept a support token
+// scoped to the user or app the request addresses.
+var supportMethods = []string{
+	adminv1.Admin_GetUser_FullMethodName,
+	adminv1.Admin_GetUserAttributes_FullMethodName,
+	adminv1.Admin_GetApp_FullMethodName,
+	adminv1.Admin_GetAppEmailDomains_FullMethodName,
+	adminv1.Admin_GetAppDisposableEmailPolicy_FullMethodName,
+}
+
+// ownerMethods lists the admin methods that owners of the app a request addresses may
+// call without being admins. Changing owners and deleting apps is left to admins.
+var ownerMethods = []string{
+	adminv1.Admin_GetApp_FullMethodName,
+	adminv1.Admin_UpdateApp_FullMethodName,
+	adminv1.Admin_RotateAppSecret_FullMethodName,
+	adminv1.Admin_GrantAppAccess_FullMethodName,
+	adminv1.Admin_RevokeAppAccess_FullMethodName,
+	adminv1.Admin_ListAppOwners_FullMethodName,
+	adminv1.Admin_RevokeAppTokens_FullMethodName,
+	adminv1.Admin_GetAppEmailDomains_FullMethodName,
+	adminv1.Admin_SetAppEmailDomains_FullMethodName,
+	adminv1.Admin_GetAppDisposableEmailPolicy_FullMethodName,
+	adminv1.Admin_SetAppDisposableEmailPolicy_FullMethodName,
+	adminv1.Admin_GetUsage_FullMethodName,
+	authv1.Auth_MintTestToken_FullMethodName,
+}
+
+// userMethods lists the gRPC methods that may only be called with a token of a signed-in user,
+// unless the authorization policy file sets another level.
+var userMethods = []string{
+	authv1.Auth_ApproveDeviceAuthorization_FullMethodName,
+	authv1.Auth_GetUserInfo_FullMethodName,
+	authv1.Auth_SetPushMfa_FullMethodName,
+	authv1.Auth_ListLoginChallenges_FullMethodName,
+	authv1.Auth_DecideLoginChallenge_FullMethodName,
+	authv1.Auth_Logout_FullMethodName,
+	authv1.Auth_ListSessions_FullMethodName,
+	authv1.Auth_RevokeSession_FullMethodName,
+}
+
+// credentialMethods lists the gRPC methods whose requests or responses carry passwords,
+// tokens, or secrets. grpc.require_tls applies to them even though anyone may call them.
+var credentialMethods = []string{
+	authv1.Auth_Register_FullMethodName,
+	authv1.Auth_Login_FullMethodName,
+	authv1.Auth_ResetPassword_FullMethodName,
+	authv1.Auth_StartDeviceAuthorization_FullMethodName,
+	authv1.Auth_PollDeviceToken_FullMethodName,
+	authv1.Auth_RefreshToken_FullMethodName,
+	authv1.Auth_RevokeRefreshToken_FullMethodName,
+	authv1.Auth_ReportLogin_FullMethodName,
+	authv1.Auth_ReportLeakedSecret_FullMethodName,
+	authv1.Auth_Unfreeze_FullMethodName,
+	authv1.Auth_WhoAmI_FullMethodName,
+	authv1.Auth_IntrospectToken_FullMethodName,
+	authv1.Auth_PollLoginChallenge_FullMethodName,
+	authv1.Auth_RequireStepUp_FullMethodName,
+	authv1.Auth_MintTestToken_FullMethodName,
+	adminv1.Admin_RotateAppSecret_FullMethodName,
+}
+
+// userStreamMethods lists the streaming gRPC methods that may only be called with a token
+// of a signed-in user. Only authorization and transport security apply to streams.
+var userStreamMethods = []string{
+	authv1.Auth_UploadAvatar_FullMethodName,
+}
+
+// unauditedMethods lists the privileged methods that only read the caller's own data.
+// Relying parties call them on every request, so recording them would flood the audit log.
+var unauditedMethods = []string{
+	authv1.Auth_GetUserInfo_FullMethodName,
+	authv1.Auth_ListLoginChallenges_FullMethodName,
+	authv1.Auth_ListSessions_FullMethodName,
+}
+
+// hashingMethods lists the gRPC methods that hash passwords with bcrypt. They are the most
+// CPU-intensive calls, so they have their own concurrency limit.
+var hashingMethods = []string{
+	authv1.Auth_Register_FullMethodName,
+	authv1.Auth_Login_FullMethodName,
+	authv1.Auth_ResetPassword_FullMethodName,
+	authv1.Auth_Unfreeze_FullMethodName,
+}
+
+// validationMethods lists the gRPC methods relying parties call to validate tokens on
+// every request. Under overload they are shed last.
+var validationMethods = []string{
+	authv1.Auth_IsAdmin_FullMethodName,
+	authv1.Auth_WhoAmI_FullMethodName,
+	authv1.Auth_IntrospectToken_FullMethodName,
+	authv1.Auth_GetUserInfo_FullMethodName,
+}
+
+// signInMethods lists the gRPC methods issuing tokens to users. Under overload they are
+// shed after every other call but token validation.
+var signInMethods = []string{
+	authv1.Auth_Login_FullMethodName,
+	authv1.Auth_RefreshToken_FullMethodName,
+	authv1.Auth_PollDeviceToken_FullMethodName,
+	authv1.Auth_PollLoginChallenge_FullMethodName,
+}
+
+// idempotentMethods lists the gRPC methods that honor the "idempotency-key" metadata.
+var idempotentMethods = []string{
+	authv1.Auth_Register_FullMethodName,
+	authv1.Auth_ResetPassword_FullMethodName,
+}
+
+// NewAuthzPolicy creates the policy of the auth level each unary method requires: admin for
+// adminMethods and authenticated for userMethods, overridden by the policy file at path.
+//
+// Parameters:
+//   - log: logger for policy reloads
+//   - path: policy file overriding the built-in levels; empty to use only them
+//
+// Returns:
+//   - *authzpolicy.Policy: policy consulted by the Authorization interceptor on every call
+//   - error: non-nil if the policy file cannot be loaded or names a method the server does not serve
+func NewAuthzPolicy(log *slog.Logger, path string) (*authzpolicy.Policy, error) {
+	const op = "grpcapp.NewAuthzPolicy"
+
+	defaults := make(map[string]authzpolicy.Level, len(adminMethods)+len(userMethods))
+	for _, m := range userMethods {
+		defaults[m] = authzpolicy.LevelAuthenticated
+	}
+	for _, m := range adminMethods {
+		defaults[m] = authzpolicy.LevelAdmin
+	}
+
+	policy, err := authzpolicy.New(log, path, defaults, unaryMethods())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return policy, nil
+}
+
+// unaryMethods returns the full names of the unary methods the server serves. Streaming
+// methods are authorized by the StreamAuthorization interceptor alone.
+func unaryMethods() []string {
+	var methods []string
+
+	for _, file := range []protoreflect.FileDescriptor{authv1.File_auth_v1_auth_proto, adminv1.File_admin_v1_admin_proto} {
+		services := file.Services()
+		for i := range services.Len() {
+			service := services.Get(i)
+
+			for j := range service.Methods().Len() {
+				method := service.Methods().Get(j)
+				if method.IsStreamingClient() || method.IsStreamingServer() {
+					continue
+				}
+
+				methods = append(methods, fmt.Sprintf("/%s/%s", service.FullName(), method.Name()))
+			}
+		}
+	}
+
+	return methods
 }
 
 // New creates and initializes a new gRPC application instance.
 //
 // Parameters:
 //   - log: logger for application events
-//   - port: TCP port on which the gRPC server will listen
+//   - cfg: gRPC server configuration
 //   - authService: authentication service implementation
+//   - adminService: admin service implementation
+//   - validator: token validator used to authorize admin and user calls
+//   - supportValidator: support token validator used to authorize support tooling
+//   - idempotencyStore: persistence for idempotency keys and replayed responses
+//   - auditRecorder: audit log recording admin and user calls
+//   - usageRecorder: usage accounting counting calls made with user tokens
+//   - announcements: announcement attached to every response and reported by GetServiceStatus
+//   - sloRecorder: SLO tracking recording the duration and status of every unary call
+//   - authzPolicy: auth level each unary method requires, created by NewAuthzPolicy
 //
 // Returns:
 //   - *App: new gRPC application instance with registered services
-func New(log *slog.Logger, port int, authService authgrpc.Auth) *App {
-	gRPCServer := grpc.NewServer()
+//   - error: non-nil if the xDS server cannot be created (e.g. missing bootstrap)
+//     or a trusted proxy is not an address or CIDR range
+//
+// When cfg.XDS is set, the server is managed by the xDS control plane named in the
+// bootstrap file (GRPC_XDS_BOOTSTRAP), and the standard gRPC admin services
+// (channelz and CSDS) are registered so the mesh can inspect it. Otherwise channelz
+// is registered only when cfg.Channelz is set.
+//
+// When cfg.TLS names a certificate, connections are secured with TLS, or mTLS if a
+// client CA is also set. Under xDS, the mesh may provide TLS instead, and the
+// certificate (or plaintext) is used only when it does not. When cfg.RequireTLS is
+// set, admin and signed-in user calls over plaintext connections are rejected.
+//
+// Every unary call is first recorded against the SLOs of its method, so calls shed or
+// rejected by the other interceptors count too. The client address is resolved next:
+// peers in cfg.TrustedProxies are replaced by the address they forwarded in
+// x-forwarded-for, and with cfg.ProxyProtocol by the one in their PROXY protocol header.
+//
+// Calls beyond cfg.Concurrency limits are then shed with RESOURCE_EXHAUSTED before the
+// remaining interceptors run, admin calls first and token validation last. The deadline each caller sent is then shortened by
+// cfg.DeadlineOverhead, so handlers give up before the caller does.
+func New(
+	log *slog.Logger,
+	cfg config.GRPC,
+	authService authgrpc.Auth,
+	adminService admingrpc.Admin,
+	validator interceptors.TokenValidator,
+	supportValidator interceptors.SupportTokenValidator,
+	idempotencyStore interceptors.IdempotencyStore,
+	auditRecorder interceptors.AuditRecorder,
+	usageRecorder interceptors.UsageRecorder,
+	announcements interceptors.AnnouncementSource,
+	sloRecorder interceptors.SLORecorder,
+	authzPolicy *authzpolicy.Policy,
+) (*App, error) {
+	const op = "grpcapp.New"
+
+	if cfg.RequireTLS && cfg.TLS.CertFile == "" && !cfg.XDS {
+		return nil, fmt.Errorf("%s: require_tls is set but no TLS certificate is configured", op)
+	}
 
-	authgrpc.Register(gRPCServer, authService)
+	var creds credentials.TransportCredentials = insecure.NewCredentials()
+	if cfg.TLS.CertFile != "" {
+		tlsCreds, err := serverTLS(cfg.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
 
-	return &App{
-		log:        log,
-		port:       port,
-		gRPCServer: gRPCServer,
+		creds = tlsCreds
 	}
+
+	trustedProxies, err := netaddr.ParsePrefixes(cfg.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("%s: trusted_proxies: %w", op, err)
+	}
+
+	unary := []grpc.UnaryServerInterceptor{
+		interceptors.SLO(sloRecorder),
+		interceptors.ClientAddress(trustedProxies),
+		interceptors.ConcurrencyLimit(
+			log,
+			cfg.Concurrency.MaxInFlight,
+			cfg.Concurrency.MaxInFlightHashing,
+			hashingMethods,
+			methodPriorities(),
+			cfg.Concurrency.RetryAfter,
+		),
+		interceptors.Deadline(log, cfg.DeadlineOverhead),
+		interceptors.Announcement(announcements),
+	}
+
+	if cfg.RequireTLS {
+		unary = append(unary, interceptors.RequireTLS(log, authzPolicy, credentialMethods))
+	}
+
+	unary = append(unary,
+		interceptors.Authorization(log, validator, supportValidator, authzPolicy, supportMethods, ownerMethods, cfg.AdminAppID, cfg.Concurrency.RetryAfter),
+		interceptors.Audit(log, auditRecorder, authzPolicy, unauditedMethods),
+		interceptors.Usage(usageRecorder),
+		interceptors.Idempotency(log, idempotencyStore, cfg.IdempotencyKeyTTL, idempotentMethods),
+	)
+
+	var stream []grpc.StreamServerInterceptor
+
+	if cfg.RequireTLS {
+		stream = append(stream, interceptors.StreamRequireTLS(log, userStreamMethods))
+	}
+
+	stream = append(stream, interceptors.StreamAuthorization(log, validator, userStreamMethods))
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+		grpc.StatsHandler(connstats.New(log)),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionAge:      cfg.MaxConnectionAge,
+			MaxConnectionAgeGrace: cfg.Timeout,
+		}),
+	}
+
+	a := &App{
+		log:            log,
+		port:           cfg.Port,
+		proxyProtocol:  cfg.ProxyProtocol,
+		trustedProxies: trustedProxies,
+	}
+
+	if cfg.XDS {
+		xdsCreds, err := xdscreds.NewServerCredentials(xdscreds.ServerOptions{FallbackCreds: creds})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		opts = append(opts, grpc.Creds(xdsCreds), xds.ServingModeCallback(func(addr net.Addr, args xds.ServingModeChangeArgs) {
+			log.Info("xDS serving mode changed",
+				slog.String("addr", addr.String()),
+				slog.String("mode", args.Mode.String()),
+				slog.Any("error", args.Err),
+			)
+		}))
+
+		xdsServer, err := xds.NewGRPCServer(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		cleanup, err := grpcadmin.Register(xdsServer)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		a.gRPCServer = xdsServer
+		a.adminCleanup = cleanup
+	} else {
+		grpcServer := grpc.NewServer(append(opts, grpc.Creds(creds))...)
+
+		if cfg.Channelz {
+			channelz.RegisterChannelzServiceToServer(grpcServer)
+
+			log.Info("channelz service registered")
+		}
+
+		a.gRPCServer = grpcServer
+	}
+
+	authgrpc.Register(a.gRPCServer, authService, announcements, authgrpc.Policy{
+		Levels:       authzPolicy,
+		OwnerMethods: ownerMethods,
+		AdminAppID:   cfg.AdminAppID,
+	}, cfg.Concurrency.RetryAfter)
+	admingrpc.Register(a.gRPCServer, adminService)
+
+	return a, nil
+}
+
+// methodPriorities returns the priority of methods under overload: admin calls are shed
+// first, then everything else, then sign-ins, and token validation last.
+func methodPriorities() map[string]interceptors.Priority {
+	priorities := make(map[string]interceptors.Priority)
+
+	for _, m := range adminMethods {
+		priorities[m] = interceptors.PriorityLow
+	}
+
+	for _, m := range signInMethods {
+		priorities[m] = interceptors.PriorityHigh
+	}
+
+	for _, m := range validationMethods {
+		priorities[m] = interceptors.PriorityCritical
+	}
+
+	return priorities
+}
+
+// serverTLS loads the server certificate and, if configured, the CA verifying client certificates.
+func serverTLS(cfg config.TLS) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load tls certificate: %w", err)
+	}
+
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls client ca: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("tls client ca contains no certificates")
+		}
+
+		tlsCfg.ClientCAs = pool
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return credentials.NewTLS(tlsCfg), nil
 }
 
 // MustRun starts the gRPC server and panics if it fails to start.
@@ -69,6 +530,10 @@ func (a *App) Run() error {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if a.proxyProtocol {
+		l = proxyproto.NewListener(l, a.trustedProxies, proxyHeaderTimeout)
+	}
+
 	log.Info("gRPC server started successfully", slog.String("addr", l.Addr().String()))
 
 	if err := a.gRPCServer.Serve(l); err != nil {
@@ -93,5 +558,9 @@ func (a *App) Stop() {
 
 	a.gRPCServer.GracefulStop()
 
+	if a.adminCleanup != nil {
+		a.adminCleanup()
+	}
+
 	log.Info("gRPC server stopped successfully")
 }
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"context"
+	"expvar"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/events"
+)
+
+// Counters of registrations and logins, served by serveMetrics.
+var (
+	usersRegistered = expvar.NewInt("auth_users_registered")
+	loginsSucceeded = expvar.NewMap("auth_logins_succeeded") // by method, e.g. "password"
+	loginsFailed    = expvar.NewMap("auth_logins_failed")    // by reason, e.g. "invalid_password"
+)
+
+// subscribeMetrics counts the registrations and logins published on bus.
+func subscribeMetrics(bus *events.Bus) {
+	events.Subscribe(bus, func(_ context.Context, _ events.UserRegistered) {
+		usersRegistered.Add(1)
+	})
+
+	events.Subscribe(bus, func(_ context.Context, event events.LoginSucceeded) {
+		loginsSucceeded.Add(event.Method, 1)
+	})
+
+	events.Subscribe(bus, func(_ context.Context, event events.LoginFailed) {
+		loginsFailed.Add(event.Reason, 1)
+	})
+}
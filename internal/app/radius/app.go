@@ -0,0 +1,394 @@
+// Package radiusapp provides a RADIUS listener authenticating network equipment users,
+// such as VPN and switch logins, against the same user store as the gRPC API.
+// Only Access-Request packets with PAP passwords are supported.
+package radiusapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/lib/netaddr"
+	"github.com/kirinyoku/sso-grpc/internal/lib/radius"
+	"github.com/kirinyoku/sso-grpc/internal/services/auth"
+)
+
+const (
+	// duplicateWindow is how long a response is kept to answer retransmissions of its request.
+	duplicateWindow = 30 * time.Second
+	// challengePollInterval is how often a pending push MFA challenge is checked.
+	challengePollInterval = time.Second
+)
+
+// Auth defines the authentication methods used to answer Access-Requests.
+type Auth interface {
+	// Login authenticates a user with email and password for an app.
+	Login(ctx context.Context, email string, password string, appID int32, client auth.ClientInfo) (string, error)
+
+	// PollLoginChallenge exchanges a login challenge for a token once the user approved it.
+	PollLoginChallenge(ctx context.Context, challengeID string) (string, error)
+}
+
+// client is a piece of network equipment allowed to send requests.
+type client struct {
+	network                   netip.Prefix // addresses the equipment sends requests from
+	secret                    string       // secret shared with the equipment
+	appID                     int32        // app users log in to
+	allowMissingAuthenticator bool         // accept requests without a Message-Authenticator
+}
+
+// exchange is a request being answered, or answered recently.
+type exchange struct {
+	response []byte    // encoded response; nil while the request is handled
+	expires  time.Time // moment retransmissions are no longer answered
+}
+
+// App is the RADIUS listener.
+type App struct {
+	log             *slog.Logger
+	auth            Auth
+	conn            *net.UDPConn
+	clients         []client
+	approvalTimeout time.Duration
+	inFlight        chan struct{} // semaphore bounding requests handled at once
+
+	mu        sync.Mutex          // guards exchanges
+	exchanges map[string]exchange // requests by source address, identifier, and authenticator
+}
+
+// New creates a new RADIUS listener bound to the configured address.
+// Call Run to start answering requests.
+//
+// Parameters:
+//   - log: logger instance for structured logging
+//   - cfg: listen address, clients, and limits
+//   - authService: service users are authenticated with
+//
+// Returns:
+//   - *App: listener ready to run
+//   - error: non-nil if a client is misconfigured or the address cannot be bound
+func New(log *slog.Logger, cfg config.RADIUS, authService Auth) (*App, error) {
+	const op = "radiusapp.New"
+
+	if cfg.ApprovalTimeout <= 0 || cfg.MaxInFlight <= 0 {
+		return nil, fmt.Errorf("%s: approval_timeout and max_in_flight must be positive", op)
+	}
+
+	a := &App{
+		log:             log,
+		auth:            authService,
+		approvalTimeout: cfg.ApprovalTimeout,
+		inFlight:        make(chan struct{}, cfg.MaxInFlight),
+		exchanges:       make(map[string]exchange),
+	}
+
+	for _, c := range cfg.Clients {
+		network, err := netaddr.ParsePrefix(c.Network)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid client network %q: %w", op, c.Network, err)
+		}
+
+		if c.Secret == "" || c.AppID <= 0 {
+			return nil, fmt.Errorf("%s: client %q needs a secret and an app_id", op, c.Network)
+		}
+
+		a.clients = append(a.clients, client{
+			network:                   network,
+			secret:                    c.Secret,
+			appID:                     c.AppID,
+			allowMissingAuthenticator: c.AllowMissingMessageAuthenticator,
+		})
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.conn, err = net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return a, nil
+}
+
+// Run answers requests until ctx is canceled, then closes the socket.
+// Requests from unknown addresses, malformed requests, and requests beyond
+// the in-flight limit are dropped, leaving the equipment to retransmit them.
+func (a *App) Run(ctx context.Context) {
+	const op = "radiusapp.App.Run"
+
+	log := a.log.With(slog.String("op", op), slog.String("addr", a.conn.LocalAddr().String()))
+
+	go func() {
+		<-ctx.Done()
+		a.conn.Close()
+	}()
+
+	log.Info("RADIUS listener started")
+
+	buf := make([]byte, radius.MaxPacketSize)
+
+	for {
+		n, from, err := a.conn.ReadFromUDPAddrPort(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				log.Info("RADIUS listener stopped")
+
+				return
+			}
+
+			log.Warn("failed to read packet", slog.String("error", err.Error()))
+
+			continue
+		}
+
+		from = netip.AddrPortFrom(from.Addr().Unmap(), from.Port())
+
+		c, ok := a.client(from.Addr())
+		if !ok {
+			log.Warn("packet from unknown client dropped", slog.String("from", from.String()))
+
+			continue
+		}
+
+		select {
+		case a.inFlight <- struct{}{}:
+		default:
+			log.Warn("too many requests in flight, packet dropped", slog.String("from", from.String()))
+
+			continue
+		}
+
+		packet := make([]byte, n)
+		copy(packet, buf[:n])
+
+		go func() {
+			defer func() { <-a.inFlight }()
+
+			a.handle(ctx, c, from, packet)
+		}()
+	}
+}
+
+// client returns the client configured for a source address.
+func (a *App) client(addr netip.Addr) (client, bool) {
+	for _, c := range a.clients {
+		if c.network.Contains(addr) {
+			return c, true
+		}
+	}
+
+	return client{}, false
+}
+
+// handle answers a request, or resends the response to a request already answered.
+func (a *App) handle(ctx context.Context, c client, from netip.AddrPort, packet []byte) {
+	const op = "radiusapp.App.handle"
+
+	log := a.log.With(slog.String("op", op), slog.String("from", from.String()))
+
+	req, err := radius.Parse(packet)
+	if err != nil {
+		log.Warn("malformed packet dropped", slog.String("error", err.Error()))
+
+		return
+	}
+
+	if req.Code != radius.CodeAccessRequest {
+		log.Warn("unsupported packet dropped", slog.Int("code", int(req.Code)))
+
+		return
+	}
+
+	// Requests must be verified before they are answered: responses to forged requests
+	// would let an attacker without the secret forge responses (BlastRADIUS).
+	if err := radius.VerifyRequest(req, c.secret); err != nil {
+		if !errors.Is(err, radius.ErrMissingMessageAuthenticator) || !c.allowMissingAuthenticator {
+			log.Warn("unauthenticated packet dropped", slog.String("error", err.Error()))
+
+			return
+		}
+	}
+
+	key := fmt.Sprintf("%s/%d/%x", from, req.Identifier, req.Authenticator)
+
+	if response, ok := a.begin(key); !ok {
+		if response != nil {
+			a.send(log, from, response)
+		}
+
+		return
+	}
+
+	code, ok := a.authenticate(ctx, log, c, from, req)
+	if !ok {
+		a.forget(key)
+
+		return
+	}
+
+	response, err := radius.EncodeResponse(req, code, c.secret)
+	if err != nil {
+		log.Error("failed to encode response", slog.String("error", err.Error()))
+		a.forget(key)
+
+		return
+	}
+
+	a.finish(key, response)
+	a.send(log, from, response)
+}
+
+// begin records a request as handled. It reports false for retransmissions of a request
+// already handled, along with its response, or nil if it is not answered yet.
+func (a *App) begin(key string) ([]byte, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+
+	if e, ok := a.exchanges[key]; ok && (e.response == nil || now.Before(e.expires)) {
+		return e.response, false
+	}
+
+	for k, e := range a.exchanges {
+		if e.response != nil && !now.Before(e.expires) {
+			delete(a.exchanges, k)
+		}
+	}
+
+	a.exchanges[key] = exchange{}
+
+	return nil, true
+}
+
+// finish records the response to a request, to resend it to retransmissions.
+func (a *App) finish(key string, response []byte) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.exchanges[key] = exchange{response: response, expires: time.Now().Add(duplicateWindow)}
+}
+
+// forget removes a request left unanswered, so its retransmissions are handled again.
+func (a *App) forget(key string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.exchanges, key)
+}
+
+// send writes a response to the equipment.
+func (a *App) send(log *slog.Logger, to netip.AddrPort, response []byte) {
+	if _, err := a.conn.WriteToUDPAddrPort(response, to); err != nil {
+		log.Warn("failed to send response", slog.String("error", err.Error()))
+	}
+}
+
+// authenticate logs the user of a request in and returns the response code. It reports
+// false if the request cannot be answered, e.g. because storage failed, so the equipment
+// retries or fails over to another server instead of rejecting the user.
+func (a *App) authenticate(ctx context.Context, log *slog.Logger, c client, from netip.AddrPort, req *radius.Packet) (byte, bool) {
+	username, _ := req.Get(radius.AttrUserName)
+	log = log.With(slog.String("username", string(username)), slog.Int("app_id", int(c.appID)))
+
+	value, ok := req.Get(radius.AttrUserPassword)
+	if !ok || len(username) == 0 {
+		// CHAP and EAP cannot be checked against password hashes.
+		log.Warn("request without user name or PAP password rejected")
+
+		return radius.CodeAccessReject, true
+	}
+
+	password, err := radius.DecryptPassword(value, c.secret, req.Authenticator)
+	if err != nil {
+		log.Warn("invalid password attribute rejected", slog.String("error", err.Error()))
+
+		return radius.CodeAccessReject, true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.approvalTimeout)
+	defer cancel()
+
+	_, err = a.auth.Login(ctx, string(username), string(password), c.appID, clientInfo(from, req))
+
+	var challengeErr *auth.ChallengeRequiredError
+	if errors.As(err, &challengeErr) {
+		log.Info("waiting for push MFA approval")
+
+		err = a.awaitApproval(ctx, challengeErr.ChallengeID)
+	}
+
+	var rejectErr *auth.RejectError
+
+	switch {
+	case err == nil:
+		log.Info("access accepted")
+
+		return radius.CodeAccessAccept, true
+	case errors.Is(err, auth.ErrInvalidAppID):
+		log.Error("client configured with unknown app, access rejected")
+
+		return radius.CodeAccessReject, true
+	case errors.Is(err, auth.ErrInvalidCredentials),
+		errors.Is(err, auth.ErrAccountFrozen),
+		errors.Is(err, auth.ErrNotAppMember),
+		errors.Is(err, auth.ErrChallengeDenied),
+		errors.Is(err, auth.ErrChallengeExpired),
+		errors.Is(err, auth.ErrInvalidChallenge),
+		errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &rejectErr):
+		log.Info("access rejected", slog.String("reason", err.Error()))
+
+		return radius.CodeAccessReject, true
+	default:
+		log.Error("failed to authenticate, request dropped", slog.String("error", err.Error()))
+
+		return 0, false
+	}
+}
+
+// awaitApproval polls a push MFA challenge until the user decides or ctx expires.
+func (a *App) awaitApproval(ctx context.Context, challengeID string) error {
+	ticker := time.NewTicker(challengePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		_, err := a.auth.PollLoginChallenge(ctx, challengeID)
+		if !errors.Is(err, auth.ErrChallengePending) {
+			return err
+		}
+	}
+}
+
+// clientInfo describes the user's client for the login notification: the Calling-Station-Id
+// when it is an address, as sent by VPN concentrators, or the equipment's address otherwise.
+func clientInfo(from netip.AddrPort, req *radius.Packet) auth.ClientInfo {
+	info := auth.ClientInfo{IP: from.Addr().String(), UserAgent: "RADIUS"}
+
+	if station, ok := req.Get(radius.AttrCallingStationID); ok {
+		if addr, err := netip.ParseAddr(string(station)); err == nil {
+			info.IP = addr.String()
+		}
+	}
+
+	if nas, ok := req.Get(radius.AttrNASIdentifier); ok && len(nas) > 0 {
+		info.UserAgent = fmt.Sprintf("RADIUS (%s)", nas)
+	}
+
+	return info
+}
@@ -2,7 +2,9 @@
 package config
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"os"
 	"time"
 
@@ -12,16 +14,411 @@ import (
 // Config represents the application configuration structure.
 // It holds general settings and nested GRPC configuration.
 type Config struct {
-	Env         string        `yaml:"env" env-default:"local"`          // Application environment (e.g., local, dev, prod)
-	StoragePath string        `yaml:"storage_path" env-required:"true"` // Path to the storage or database file
-	TokenTTL    time.Duration `yaml:"token_ttl" env-required:"true"`    // Time-to-live for access tokens
-	GRPC        GRPC          `yaml:"grpc"`                             // GRPC server-related settings
+	Env                 string              `yaml:"env" env-default:"local"`          // Application environment (e.g., local, dev, prod)
+	StoragePath         string              `yaml:"storage_path" env-required:"true"` // Path to the storage or database file
+	SQLite              SQLite              `yaml:"sqlite"`                           // Tuning of SQLite connections
+	TokenTTL            time.Duration       `yaml:"token_ttl" env-required:"true"`    // Time-to-live for access tokens
+	TokenFormat         string              `yaml:"token_format" env-default:"jwt"`   // Format of the tokens of apps created without one: jwt, jwt_rs256, or paseto_v4_local
+	Region              string              `yaml:"region" env:"REGION"`              // Region of this deployment in geo-distributed setups; empty otherwise
+	GRPC                GRPC                `yaml:"grpc"`                             // GRPC server-related settings
+	SMTP                SMTP                `yaml:"smtp"`                             // Outbound email settings
+	PasswordReset       PasswordReset       `yaml:"password_reset"`                   // Password reset token settings
+	PasswordHashing     PasswordHashing     `yaml:"password_hashing"`                 // bcrypt cost of password hashes and its tuning
+	EmailTemplates      EmailTemplates      `yaml:"email_templates"`                  // Outbound email template settings
+	Registration        Registration        `yaml:"registration"`                     // Restrictions on new accounts
+	Enforcement         Enforcement         `yaml:"enforcement"`                      // Whether policy violations are rejected or only reported
+	Stats               Stats               `yaml:"stats"`                            // Admin statistics settings
+	DeviceAuthorization DeviceAuthorization `yaml:"device_authorization"`             // Device authorization grant settings
+	PushMFA             PushMFA             `yaml:"push_mfa"`                         // Approval of logins from a signed-in device
+	StepUp              StepUp              `yaml:"step_up"`                          // Second factor required before sensitive actions
+	BackchannelLogout   BackchannelLogout   `yaml:"backchannel_logout"`               // Logout tokens POSTed to apps when a user's tokens are revoked
+	Outbox              Outbox              `yaml:"outbox"`                           // Delivery of messages queued with the changes they report
+	Audit               Audit               `yaml:"audit"`                            // Audit log settings
+	PII                 PII                 `yaml:"pii"`                              // Encryption of personal data at rest
+	TokenValidation     TokenValidation     `yaml:"token_validation"`                 // Tolerance for clock skew and expired tokens
+	RefreshTokens       RefreshTokens       `yaml:"refresh_tokens"`                   // Long-lived tokens exchanged for new access tokens
+	Avatars             Avatars             `yaml:"avatars"`                          // Profile images uploaded by users
+	ClockCheck          ClockCheck          `yaml:"clock_check"`                      // Startup comparison of the system clock against NTP
+	LoginNotifications  LoginNotifications  `yaml:"login_notifications"`              // Emails sent to users after every login
+	SecretLeaks         SecretLeaks         `yaml:"secret_leaks"`                     // Handling of app secrets reported as leaked
+	SupportTokens       SupportTokens       `yaml:"support_tokens"`                   // Read-only tokens issued to support tooling
+	TestTokens          TestTokens          `yaml:"test_tokens"`                      // Tokens with arbitrary claims minted for CI suites of dependent services
+	StorageTimeouts     StorageTimeouts     `yaml:"storage_timeouts"`                 // Per-method deadlines for storage queries
+	UserAttributes      UserAttributes      `yaml:"user_attributes"`                  // Custom key/value attributes of users
+	QueryLog            QueryLog            `yaml:"query_log"`                        // Logging of storage queries
+	Metrics             Metrics             `yaml:"metrics"`                          // Exposure of internal counters to monitoring
+	Introspection       Introspection       `yaml:"introspection"`                    // RFC 7662 token introspection over HTTP
+	TokenSigning        TokenSigning        `yaml:"token_signing"`                    // RSA key signing the tokens of apps with the RS256 format
+	TokenClaims         TokenClaims         `yaml:"token_claims"`                     // Optional registered claims of issued tokens
+	RADIUS              RADIUS              `yaml:"radius"`                           // Authentication of network equipment users over RADIUS
+	CacheBus            CacheBus            `yaml:"cache_bus"`                        // Propagation of cache invalidations between replicas
+	Usage               Usage               `yaml:"usage"`                            // Accounting of authenticated requests per app and user
+	Diagnostics         Diagnostics         `yaml:"diagnostics"`                      // Sampling of goroutines, heap, and GC, with stack dumps past thresholds
+	SLO                 SLO                 `yaml:"slo"`                              // Availability and latency objectives of gRPC methods
+	Snapshots           Snapshots           `yaml:"snapshots"`                        // Encrypted backups of the database uploaded to object storage
+	Bootstrap           Bootstrap           `yaml:"bootstrap"`                        // First admin and app created on an empty database
 }
 
 // GRPC holds configuration values related to the GRPC server.
 type GRPC struct {
-	Port    int           `yaml:"port" env-required:"true"` // Port on which the GRPC server runs
-	Timeout time.Duration `yaml:"timeout" env-default:"1h"` // Request timeout for GRPC server
+	Port              int           `yaml:"port" env-required:"true"`              // Port on which the GRPC server runs
+	Timeout           time.Duration `yaml:"timeout" env-default:"1h"`              // Request timeout for GRPC server
+	MaxConnectionAge  time.Duration `yaml:"max_connection_age" env-default:"0"`    // Lifetime after which client connections are gracefully closed so clients rebalance (0 disables)
+	XDS               bool          `yaml:"xds" env-default:"false"`               // Serve through an xDS-managed server configured by GRPC_XDS_BOOTSTRAP
+	Channelz          bool          `yaml:"channelz" env-default:"false"`          // Register the channelz service for inspecting live connections
+	IdempotencyKeyTTL time.Duration `yaml:"idempotency_key_ttl" env-default:"24h"` // How long idempotency keys and their responses are retained
+	TLS               TLS           `yaml:"tls"`                                   // Transport security; connections are plaintext unless configured
+	RequireTLS        bool          `yaml:"require_tls" env-default:"false"`       // Reject admin and signed-in user calls over connections without TLS
+	Concurrency       Concurrency   `yaml:"concurrency"`                           // Limits on calls handled at once
+	DeadlineOverhead  time.Duration `yaml:"deadline_overhead" env-default:"50ms"`  // Time reserved from each caller's deadline for returning the response; 0 disables
+	TrustedProxies    []string      `yaml:"trusted_proxies"`                       // Addresses or CIDR ranges of load balancers whose x-forwarded-for and PROXY headers are trusted
+	ProxyProtocol     bool          `yaml:"proxy_protocol" env-default:"false"`    // Require a PROXY protocol header from trusted proxies (from every peer if none are listed)
+	AuthzPolicy       AuthzPolicy   `yaml:"authz_policy"`                          // Per-method auth levels overriding the built-in ones
+	AdminAppID        int32         `yaml:"admin_app_id" env:"ADMIN_APP_ID"`       // App whose tokens may call admin methods; other apps' tokens must be RS256; 0 accepts RS256 tokens only
+}
+
+// AuthzPolicy holds configuration values related to the policy file overriding the auth
+// level each gRPC method requires.
+type AuthzPolicy struct {
+	File           string        `yaml:"file" env:"AUTHZ_POLICY_FILE"`      // YAML file mapping full method names to levels; only built-in levels apply when empty
+	ReloadInterval time.Duration `yaml:"reload_interval" env-default:"30s"` // How often the file is read again and applied if it changed
+}
+
+// Concurrency holds configuration values related to the number of calls the GRPC server
+// handles at once. Calls over a limit fail with RESOURCE_EXHAUSTED instead of queueing.
+type Concurrency struct {
+	MaxInFlight        int           `yaml:"max_in_flight" env-default:"1000"`       // Maximum calls handled at once; 0 disables the limit
+	MaxInFlightHashing int           `yaml:"max_in_flight_hashing" env-default:"32"` // Maximum password hashing calls (register, login, password changes) at once; 0 disables the limit
+	RetryAfter         time.Duration `yaml:"retry_after" env-default:"1s"`           // Delay suggested in RetryInfo details to shed clients and to calls failing while storage is unavailable
+}
+
+// TLS holds configuration values related to transport security of the GRPC server.
+type TLS struct {
+	CertFile     string `yaml:"cert_file"`      // PEM certificate chain of the server; TLS is disabled when empty
+	KeyFile      string `yaml:"key_file"`       // PEM private key of the server certificate
+	ClientCAFile string `yaml:"client_ca_file"` // PEM CA bundle verifying client certificates; enables mTLS when set
+}
+
+// SMTP holds configuration values related to outbound email delivery.
+// When Host is empty, messages are logged instead of sent.
+type SMTP struct {
+	Host     string `yaml:"host"`                                  // SMTP server host
+	Port     int    `yaml:"port" env-default:"587"`                // SMTP server port
+	Username string `yaml:"username"`                              // SMTP username; authentication is skipped when empty
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`          // SMTP password
+	From     string `yaml:"from" env-default:"no-reply@localhost"` // Sender address of outbound messages
+}
+
+// EmailTemplates holds configuration values related to outbound email templates.
+type EmailTemplates struct {
+	Dir           string `yaml:"dir"`                             // Optional directory overriding the embedded templates
+	DefaultLocale string `yaml:"default_locale" env-default:"en"` // Locale used when the requested one has no template
+}
+
+// Registration holds configuration values restricting which users may register.
+// The lists apply to every app; apps can add their own lists through the admin API.
+type Registration struct {
+	AllowedDomains   []string          `yaml:"allowed_domains"`                      // Email domains allowed to register; empty allows every domain
+	DeniedDomains    []string          `yaml:"denied_domains"`                       // Email domains denied registration; takes precedence over AllowedDomains
+	DisposableEmails DisposableEmails  `yaml:"disposable_emails"`                    // Handling of disposable email providers
+	IDStrategy       string            `yaml:"id_strategy" env-default:"sequential"` // Public ID format of new users: sequential, ulid, or uuidv7
+	PerIPDailyLimit  int               `yaml:"per_ip_daily_limit" env-default:"0"`   // Registrations accepted from a source address per UTC day; 0 disables the limit
+	IPAllowlist      []string          `yaml:"ip_allowlist"`                         // Source addresses and CIDR ranges exempt from the daily limit, e.g. corporate NATs
+	Async            AsyncRegistration `yaml:"async"`                                // Background account creation for Register calls with async set
+}
+
+// AsyncRegistration holds configuration values related to async registration.
+type AsyncRegistration struct {
+	Workers   int           `yaml:"workers" env-default:"4"`      // Accounts created concurrently in the background; 0 disables async registration
+	QueueSize int           `yaml:"queue_size" env-default:"100"` // Registrations waiting for a worker; beyond it Register fails with ResourceExhausted
+	StatusTTL time.Duration `yaml:"status_ttl" env-default:"24h"` // How long GetRegistrationStatus reports a registration
+	Timeout   time.Duration `yaml:"timeout" env-default:"5m"`     // Age after which a pending registration is reported as interrupted
+}
+
+// DisposableEmails holds configuration values related to disposable email providers.
+type DisposableEmails struct {
+	Policy          string        `yaml:"policy" env-default:"allow"`         // Default policy for apps without their own: allow, flag, or reject
+	ListURL         string        `yaml:"list_url"`                           // Optional URL of a maintained blocklist replacing the embedded one
+	RefreshInterval time.Duration `yaml:"refresh_interval" env-default:"24h"` // How often the blocklist is downloaded from ListURL
+}
+
+// DeviceAuthorization holds configuration values related to the OAuth 2.0
+// device authorization grant used by CLI tools and other input-constrained devices.
+type DeviceAuthorization struct {
+	VerificationURI string        `yaml:"verification_uri" env-default:"http://localhost:8080/device"` // Page where users enter the user code
+	CodeTTL         time.Duration `yaml:"code_ttl" env-default:"10m"`                                  // How long device and user codes remain valid
+	PollInterval    time.Duration `yaml:"poll_interval" env-default:"5s"`                              // Minimum time between token polls
+}
+
+// PushMFA holds configuration values related to push approval of logins.
+type PushMFA struct {
+	ChallengeTTL time.Duration `yaml:"challenge_ttl" env-default:"2m"` // How long a login waits for the user's approval
+}
+
+// StepUp holds configuration values related to step-up authentication before sensitive actions.
+type StepUp struct {
+	MaxAge  time.Duration            `yaml:"max_age" env-default:"5m"` // How long ago the user may have approved a second factor
+	Actions map[string]time.Duration `yaml:"actions"`                  // max_age of individual actions, overriding the default
+}
+
+// BackchannelLogout holds configuration values related to the logout tokens POSTed to apps
+// with a back-channel logout URI. Deliveries are queued in the outbox, so they survive restarts.
+type BackchannelLogout struct {
+	Issuer   string        `yaml:"issuer" env-default:"sso"`   // "iss" claim of logout tokens
+	TokenTTL time.Duration `yaml:"token_ttl" env-default:"2m"` // How long an app may take to receive a logout token
+	Timeout  time.Duration `yaml:"timeout" env-default:"5s"`   // Deadline of each POST to an app
+	Attempts int           `yaml:"attempts" env-default:"5"`   // POSTs made before a delivery is given up; 0 disables back-channel logout
+	Backoff  time.Duration `yaml:"backoff" env-default:"1s"`   // Delay before the first retry, doubled for each following one
+}
+
+// Outbox holds configuration values related to the dispatcher delivering the messages queued
+// in the outbox table, such as back-channel logouts.
+type Outbox struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"` // How often due messages are looked for
+	BatchSize    int           `yaml:"batch_size" env-default:"100"`   // Messages claimed at a time
+	Workers      int           `yaml:"workers" env-default:"4"`        // Messages delivered concurrently
+	Lease        time.Duration `yaml:"lease" env-default:"1m"`         // How long a claimed message is hidden from other replicas; redelivered after it if the process stops
+}
+
+// Audit holds configuration values related to the audit log.
+type Audit struct {
+	Key string `yaml:"key" env:"AUDIT_KEY"` // Secret signing audit events with HMAC-SHA256; events are only hashed when empty
+}
+
+// PII holds configuration values related to the encryption of personal data at rest.
+type PII struct {
+	Key string `yaml:"key" env:"PII_KEY"` // Base64-encoded 32-byte key encrypting user emails; stored in plain text when empty
+}
+
+// TokenValidation holds configuration values related to the validation of access tokens.
+type TokenValidation struct {
+	Leeway        time.Duration `yaml:"leeway" env-default:"0s"`          // How long after expiration tokens are still accepted, to tolerate clock skew
+	RefreshGrace  time.Duration `yaml:"refresh_grace" env-default:"0s"`   // How long after expiration (beyond the leeway) tokens can still be refreshed
+	MicroCacheTTL time.Duration `yaml:"micro_cache_ttl" env-default:"0s"` // How long token validations and admin statuses are reused, at most 1s; 0 disables
+}
+
+// RefreshTokens holds configuration values related to refresh tokens, issued alongside access
+// tokens at login and exchanged for new access tokens with RefreshToken.
+type RefreshTokens struct {
+	TTL time.Duration `yaml:"ttl" env-default:"720h"` // How long after login a refresh token chain can be used; rotation does not extend it; 0 disables
+}
+
+// Avatars holds configuration values related to the profile images users upload with
+// UploadAvatar. Images are stored under keys derived from their content, so their URLs
+// never change and can be cached indefinitely.
+type Avatars struct {
+	Dir     string `yaml:"dir"`                            // Directory the images are stored in; empty disables avatars
+	BaseURL string `yaml:"base_url"`                       // URL the keys of the images are appended to in profiles, e.g. of a CDN
+	MaxSize int    `yaml:"max_size" env-default:"1048576"` // Largest image accepted, in bytes
+	Addr    string `yaml:"addr"`                           // Address the images are served at over HTTP under /avatars/; empty if served otherwise
+}
+
+// LoginNotifications holds configuration values related to the emails sent after every login.
+// Each email links to ReportURL with a signed token, letting the user freeze the account
+// if the login was not theirs.
+type LoginNotifications struct {
+	Enabled   bool          `yaml:"enabled" env-default:"false"`       // Send an email after every login
+	ReportURL string        `yaml:"report_url"`                        // Page calling ReportLogin with the "token" query parameter
+	LinkTTL   time.Duration `yaml:"link_ttl" env-default:"72h"`        // How long the report link remains valid
+	Key       string        `yaml:"key" env:"LOGIN_NOTIFICATIONS_KEY"` // Secret signing report links
+}
+
+// SecretLeaks holds configuration values related to app secrets reported as leaked
+// by secret scanners.
+type SecretLeaks struct {
+	NotifyEmails []string `yaml:"notify_emails"` // Addresses emailed when a leaked secret is rotated, e.g. the security team
+}
+
+// SupportTokens holds configuration values related to support tokens, the read-only
+// tokens admins issue to support tooling for a single user or app.
+type SupportTokens struct {
+	DefaultTTL time.Duration `yaml:"default_ttl" env-default:"1h"` // Lifetime of tokens issued without an explicit TTL
+	MaxTTL     time.Duration `yaml:"max_ttl" env-default:"24h"`    // Longest lifetime an admin may request
+}
+
+// TestTokens holds configuration values related to MintTestToken, which mints tokens with
+// arbitrary claims so CI suites of dependent services need not seed users. It is refused
+// in production, and builds with the notesttokens tag leave it out altogether.
+type TestTokens struct {
+	Enabled bool          `yaml:"enabled" env:"TEST_TOKENS_ENABLED" env-default:"false"` // Serve MintTestToken
+	MaxTTL  time.Duration `yaml:"max_ttl" env-default:"1h"`                              // Longest lifetime a test token may be minted with
+}
+
+// UserAttributes holds configuration values related to the custom key/value attributes
+// of users, such as department or clearance, managed through the admin API.
+type UserAttributes struct {
+	Types    map[string]string `yaml:"types"`                         // Type of each declared key: "string", "int", or "bool"; undeclared keys accept strings
+	InTokens bool              `yaml:"in_tokens" env-default:"false"` // Add attributes to issued tokens as the "attrs" claim, except for apps with minimal claims
+}
+
+// StorageTimeouts holds per-method deadlines for storage queries, so a slow database
+// degrades latency rather than holding calls until their callers time out too.
+type StorageTimeouts struct {
+	IsAdmin      time.Duration `yaml:"is_admin" env-default:"200ms"`    // Deadline of the admin status query; 0 disables it
+	RoleCacheAge time.Duration `yaml:"role_cache_age" env-default:"5m"` // How old an admin status may be when served because the query timed out; 0 disables the fallback
+}
+
+// SQLite holds the PRAGMA settings applied to every SQLite connection when it is opened.
+// Empty and zero values keep the driver defaults, except for foreign keys, which are enforced.
+type SQLite struct {
+	JournalMode string `yaml:"journal_mode"` // DELETE, TRUNCATE, PERSIST, MEMORY, WAL, or OFF; the default is DELETE
+	Synchronous string `yaml:"synchronous"`  // OFF, NORMAL, FULL, or EXTRA; the default is NORMAL
+	CacheSize   int    `yaml:"cache_size"`   // Page cache size in pages if positive, or in KiB if negative; the default is -2000
+	ForeignKeys *bool  `yaml:"foreign_keys"` // Enforce foreign key constraints; nil, the default, enforces them
+}
+
+// QueryLog holds configuration values related to logging storage queries.
+// Parameter values are never logged, only their types and sizes.
+type QueryLog struct {
+	Enabled       bool          `yaml:"enabled" env-default:"false"`        // Log every query at debug level
+	SlowThreshold time.Duration `yaml:"slow_threshold" env-default:"100ms"` // Queries taking longer are logged at warn level and counted; 0 disables it
+}
+
+// Metrics holds configuration values related to exposing internal counters.
+type Metrics struct {
+	Addr string `yaml:"addr" env:"METRICS_ADDR"` // HTTP address serving counters at /debug/vars, e.g. ":9090"; empty disables it
+}
+
+// Introspection holds configuration values related to the HTTP endpoint of RFC 7662 token
+// introspection, for OAuth2 libraries that cannot call Auth.IntrospectToken over gRPC.
+type Introspection struct {
+	Addr string `yaml:"addr" env:"INTROSPECTION_ADDR"` // HTTP address serving POST /introspect, e.g. ":8080"; empty disables it
+}
+
+// TokenSigning holds configuration values related to the RSA key signing the tokens of apps
+// with the jwt_rs256 token format. Consumers of such apps verify tokens with the public key,
+// published as a JSON Web Key Set, instead of sharing the app secret. The configured key
+// signs tokens until the first rotation; rotated keys are stored in the database.
+type TokenSigning struct {
+	PrivateKeyFile   string        `yaml:"private_key_file" env:"TOKEN_SIGNING_PRIVATE_KEY_FILE"` // PEM file of an RSA private key of at least 2048 bits
+	PrivateKey       string        `yaml:"private_key" env:"TOKEN_SIGNING_PRIVATE_KEY"`           // PEM of the key itself, taking precedence over the file
+	JWKSAddr         string        `yaml:"jwks_addr"`                                             // HTTP address serving the public keys at /.well-known/jwks.json; empty disables it
+	GracePeriod      time.Duration `yaml:"grace_period" env-default:"24h"`                        // How long a retired key still verifies tokens; at least token_ttl
+	RotationInterval time.Duration `yaml:"rotation_interval" env-default:"0s"`                    // Age at which the signing key is rotated automatically; 0 rotates only on request
+	CheckInterval    time.Duration `yaml:"check_interval" env-default:"10m"`                      // How often keys past their grace period are dropped and the rotation schedule is checked
+}
+
+// TokenClaims holds configuration values related to the optional registered claims of RFC 7519
+// added to issued tokens, so they interoperate with standard JWT middleware. Tokens always
+// carry "sub", "iat", and "exp".
+type TokenClaims struct {
+	Issuer    string `yaml:"issuer" env:"TOKEN_ISSUER"` // "iss" claim, e.g. "https://sso.example.com"; tokens naming another issuer are rejected; empty omits it
+	Audience  bool   `yaml:"audience"`                  // Add "aud", the app ID, to full tokens, which otherwise only name their app in "app_id"
+	NotBefore bool   `yaml:"not_before"`                // Add "nbf", equal to "iat"
+	TokenID   bool   `yaml:"token_id"`                  // Add "jti", a random ID unique to each token
+}
+
+// RADIUS holds configuration values related to the RADIUS listener authenticating users of
+// network equipment, such as VPN concentrators and switches, with PAP.
+type RADIUS struct {
+	Addr            string         `yaml:"addr" env:"RADIUS_ADDR"`             // UDP address to listen on, e.g. ":1812"; empty disables the listener
+	Clients         []RADIUSClient `yaml:"clients"`                            // Equipment allowed to send requests; requests from other addresses are dropped
+	ApprovalTimeout time.Duration  `yaml:"approval_timeout" env-default:"25s"` // How long a request waits for users with push MFA to approve the login
+	MaxInFlight     int            `yaml:"max_in_flight" env-default:"64"`     // Requests handled at once; beyond it new ones are dropped
+}
+
+// RADIUSClient holds configuration values related to a piece of equipment sending RADIUS requests.
+type RADIUSClient struct {
+	Network                          string `yaml:"network"`                             // Address or CIDR range the equipment sends requests from
+	Secret                           string `yaml:"secret"`                              // Secret shared with the equipment
+	AppID                            int32  `yaml:"app_id"`                              // App users log in to, subject to its membership rules
+	AllowMissingMessageAuthenticator bool   `yaml:"allow_missing_message_authenticator"` // Accept requests without a Message-Authenticator, for legacy equipment
+}
+
+// ClockCheck holds configuration values related to the startup check of the system clock.
+// Skew between servers breaks token expiration, so it is reported loudly.
+type ClockCheck struct {
+	NTPServer string        `yaml:"ntp_server" env:"NTP_SERVER" env-default:"pool.ntp.org:123"` // NTP server to compare against; empty disables the check
+	MaxSkew   time.Duration `yaml:"max_skew" env-default:"1s"`                                  // Offset from the NTP server above which an error is logged
+	Timeout   time.Duration `yaml:"timeout" env-default:"3s"`                                   // How long to wait for the NTP server
+}
+
+// Stats holds configuration values related to admin statistics.
+type Stats struct {
+	CacheTTL time.Duration `yaml:"cache_ttl" env-default:"1m"` // How long computed statistics are served from cache
+}
+
+// Usage holds configuration values related to usage accounting.
+type Usage struct {
+	FlushInterval time.Duration `yaml:"flush_interval" env-default:"1m"` // How often counted requests are added to the daily rollups; 0 disables accounting
+	Retention     time.Duration `yaml:"retention" env-default:"2160h"`   // How long daily rollups are kept (default 90 days)
+}
+
+// Diagnostics holds configuration values related to the runtime watchdog, which samples
+// goroutines, heap, and garbage collection and logs goroutine stacks when a threshold is crossed.
+type Diagnostics struct {
+	SampleInterval     time.Duration `yaml:"sample_interval" env-default:"15s"`   // How often the runtime is sampled for metrics and thresholds; 0 disables the watchdog
+	GoroutineThreshold int           `yaml:"goroutine_threshold" env-default:"0"` // Goroutines above which stacks are dumped to the log; 0 disables the threshold
+	HeapThresholdMB    int           `yaml:"heap_threshold_mb" env-default:"0"`   // Allocated heap in MiB above which stacks are dumped to the log; 0 disables the threshold
+	DumpCooldown       time.Duration `yaml:"dump_cooldown" env-default:"10m"`     // Minimum time between stack dumps while a threshold stays crossed
+}
+
+// SLO holds configuration values related to the service level objectives of gRPC methods.
+// Burn rates are computed over two rolling windows, and a warning is logged only while both
+// exceed the alert threshold, so that short spikes and old incidents do not alert.
+type SLO struct {
+	Objectives    []Objective   `yaml:"objectives"`                         // Objectives of gRPC methods; empty disables tracking
+	Window        time.Duration `yaml:"window" env-default:"1h"`            // Rolling window error budgets and long burn rates are computed over
+	AlertWindow   time.Duration `yaml:"alert_window" env-default:"5m"`      // Recent window short burn rates are computed over
+	BurnRateAlert float64       `yaml:"burn_rate_alert" env-default:"14.4"` // Burn rate both windows must exceed for a warning to be logged
+	CheckInterval time.Duration `yaml:"check_interval" env-default:"1m"`    // How often burn rates and budgets are computed and published
+}
+
+// Snapshots holds configuration values related to the scheduled backups of the database.
+// Snapshots are encrypted and uploaded to S3-compatible object storage, such as Amazon S3,
+// or Google Cloud Storage through its XML API with HMAC keys.
+type Snapshots struct {
+	Interval        time.Duration `yaml:"interval" env-default:"0s"`                           // Time between snapshots; 0 disables them
+	Retention       time.Duration `yaml:"retention" env-default:"720h"`                        // Age at which snapshots are deleted; the latest one is always kept
+	Key             string        `yaml:"key" env:"SNAPSHOTS_KEY"`                             // Base64-encoded 32-byte key encrypting snapshots with AES-256-GCM
+	Endpoint        string        `yaml:"endpoint"`                                            // URL of the object storage, e.g. https://s3.eu-west-1.amazonaws.com
+	Region          string        `yaml:"region" env-default:"us-east-1"`                      // Region requests are signed for; "auto" for Google Cloud Storage
+	Bucket          string        `yaml:"bucket"`                                              // Bucket snapshots are stored in
+	Prefix          string        `yaml:"prefix" env-default:"sso/"`                           // Prefix of the keys of snapshots in the bucket
+	AccessKeyID     string        `yaml:"access_key_id" env:"SNAPSHOTS_ACCESS_KEY_ID"`         // ID of the access key, or HMAC key, uploading snapshots
+	SecretAccessKey string        `yaml:"secret_access_key" env:"SNAPSHOTS_SECRET_ACCESS_KEY"` // Secret of the access key
+	Timeout         time.Duration `yaml:"timeout" env-default:"5m"`                            // Time limit of every request to the object storage, including uploads
+}
+
+// Objective holds configuration values related to the objectives of a gRPC method.
+type Objective struct {
+	Method        string        `yaml:"method"`         // Full gRPC method name, e.g. "/auth.Auth/Login"
+	Availability  float64       `yaml:"availability"`   // Share of calls that must not fail with a server error, e.g. 0.999; 0 disables the objective
+	Latency       time.Duration `yaml:"latency"`        // Duration within which calls count as fast
+	LatencyTarget float64       `yaml:"latency_target"` // Share of calls that must be fast, e.g. 0.99; 0 disables the objective
+}
+
+// CacheBus holds configuration values related to propagating cache invalidations between
+// replicas sharing the database. Invalidations are always applied on the replica making the change.
+type CacheBus struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"0"` // How often invalidations of other replicas are read; 0 for a single replica
+	Retention    time.Duration `yaml:"retention" env-default:"1h"`    // How long published invalidations are kept for replicas to read
+}
+
+// Enforcement holds how violations of each policy are handled: enforce rejects the request,
+// report_only logs and counts the violation in the policy_violations metric and lets the request
+// through, so the impact of a new or tightened policy can be measured before it is enforced.
+type Enforcement struct {
+	RegistrationDomains string `yaml:"registration_domains" env-default:"enforce"` // Allowed and denied email domains, of every app and per app
+	DisposableEmails    string `yaml:"disposable_emails" env-default:"enforce"`    // Rejection of disposable email addresses by the reject policy
+	RegistrationQuota   string `yaml:"registration_quota" env-default:"enforce"`   // Daily registrations per source address
+	PasswordResetLimit  string `yaml:"password_reset_limit" env-default:"enforce"` // Reset and unfreeze tokens issued per account within password_reset.window
+}
+
+// PasswordReset holds configuration values related to password reset tokens.
+type PasswordReset struct {
+	TokenTTL    time.Duration `yaml:"token_ttl" env-default:"15m"`  // Time-to-live for reset tokens
+	MaxRequests int           `yaml:"max_requests" env-default:"3"` // Maximum reset tokens issued per account within Window
+	Window      time.Duration `yaml:"window" env-default:"1h"`      // Window over which MaxRequests is enforced
+}
+
+// PasswordHashing holds configuration values related to hashing passwords with bcrypt.
+// With a target duration, the cost is tuned within MinCost and MaxCost to hash in about that time.
+type PasswordHashing struct {
+	Cost           int           `yaml:"cost" env-default:"10"`           // Cost of new hashes, or the starting cost when tuning
+	MinCost        int           `yaml:"min_cost" env-default:"10"`       // Lowest cost tuning may select
+	MaxCost        int           `yaml:"max_cost" env-default:"14"`       // Highest cost tuning may select
+	TargetDuration time.Duration `yaml:"target_duration" env-default:"0"` // Hash time tuning aims for; 0 disables tuning
 }
 
 // MustLoad loads the application configuration from a YAML file
@@ -36,18 +433,30 @@ func MustLoad() *Config {
 	return MustLoadByPath(configPath)
 }
 
+// MustLoadByPath loads the configuration like LoadByPath, panicking if it cannot be loaded.
 func MustLoadByPath(path string) *Config {
+	cfg, err := LoadByPath(path)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return cfg
+}
+
+// LoadByPath loads the configuration from the YAML file at path, with environment
+// variables overriding it. The result is not validated; see Validate.
+func LoadByPath(path string) (*Config, error) {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		panic("config file does not exist")
+		return nil, errors.New("config file does not exist")
 	}
 
 	var cfg Config
 
 	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
-		panic("failed to load config: " + err.Error())
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	return &cfg
+	return &cfg, nil
 }
 
 // fetchConfigPath retrieves the path to the configuration file.
@@ -65,3 +474,13 @@ func fetchConfigPath() string {
 
 	return res
 }
+
+// Bootstrap holds configuration values related to the first start of the service. When the
+// database has neither users nor apps, an admin and optionally an app are created from them,
+// so the service is usable without manual SQL. They are ignored once the database has data.
+type Bootstrap struct {
+	AdminEmail    string `yaml:"admin_email" env:"BOOTSTRAP_ADMIN_EMAIL"`       // Email of the first admin; empty disables bootstrapping
+	AdminPassword string `yaml:"admin_password" env:"BOOTSTRAP_ADMIN_PASSWORD"` // Password of the first admin; generated and printed once when empty
+	AppName       string `yaml:"app_name" env:"BOOTSTRAP_APP_NAME"`             // Name of the first app; no app is created when empty
+	AppSecret     string `yaml:"app_secret" env:"BOOTSTRAP_APP_SECRET"`         // Secret of the first app; generated and printed once when empty
+}
@@ -0,0 +1,266 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/attributes"
+	"github.com/kirinyoku/sso-grpc/internal/lib/enforcement"
+	"github.com/kirinyoku/sso-grpc/internal/lib/ids"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/lib/netaddr"
+	"github.com/kirinyoku/sso-grpc/internal/lib/pii"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Environments the service runs in. Production is held to stricter rules by Validate.
+const (
+	EnvLocal = "local"
+	EnvDev   = "dev"
+	EnvProd  = "prod"
+)
+
+// MaxMicroCacheTTL bounds token_validation.micro_cache_ttl, so the cache only absorbs bursts
+// and never serves results stale enough to matter.
+const MaxMicroCacheTTL = time.Second
+
+// ErrInvalidConfig is wrapped by the error returned by Validate.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// validation collects the problems found by Validate.
+type validation struct {
+	problems []error
+}
+
+// check records a problem with field unless ok holds.
+func (v *validation) check(ok bool, field, format string, args ...any) {
+	if !ok {
+		v.problems = append(v.problems, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+}
+
+// Validate checks the configuration against the rules the service relies on, including
+// rules spanning several fields and the stricter rules of production, so a file can be
+// checked in CI without starting the service. Every problem is reported, not just the first.
+//
+// Returns:
+//   - error: nil if the configuration is valid, or ErrInvalidConfig joined with one error
+//     per problem, each naming the offending field
+func (c *Config) Validate() error {
+	var v validation
+
+	v.check(c.Env == EnvLocal || c.Env == EnvDev || c.Env == EnvProd, "env", "must be %s, %s, or %s, got %q", EnvLocal, EnvDev, EnvProd, c.Env)
+	v.check(c.TokenTTL > 0, "token_ttl", "must be positive")
+
+	c.validateGRPC(&v)
+	c.validateRegistration(&v)
+	c.validateAuthentication(&v)
+	c.validateBackground(&v)
+
+	if c.Env == EnvProd {
+		c.validateProduction(&v)
+	}
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+
+	return errors.Join(append([]error{ErrInvalidConfig}, v.problems...)...)
+}
+
+// validateGRPC checks the listener, its transport security, and the proxies in front of it.
+func (c *Config) validateGRPC(v *validation) {
+	v.check(c.GRPC.Port > 0 && c.GRPC.Port <= 65535, "grpc.port", "must be between 1 and 65535")
+	v.check(c.GRPC.Timeout > 0, "grpc.timeout", "must be positive")
+	v.check((c.GRPC.TLS.CertFile == "") == (c.GRPC.TLS.KeyFile == ""), "grpc.tls", "cert_file and key_file must be set together")
+	v.check(c.GRPC.TLS.ClientCAFile == "" || c.GRPC.TLS.CertFile != "", "grpc.tls.client_ca_file", "requires cert_file")
+	v.check(!c.GRPC.RequireTLS || c.GRPC.TLS.CertFile != "" || c.GRPC.XDS, "grpc.require_tls", "requires tls.cert_file or xds")
+
+	_, err := netaddr.ParsePrefixes(c.GRPC.TrustedProxies)
+	v.check(err == nil, "grpc.trusted_proxies", "%v", err)
+
+	concurrency := c.GRPC.Concurrency
+	v.check(concurrency.MaxInFlight >= 0 && concurrency.MaxInFlightHashing >= 0, "grpc.concurrency", "limits must not be negative")
+
+	v.check(c.GRPC.AuthzPolicy.File == "" || c.GRPC.AuthzPolicy.ReloadInterval > 0, "grpc.authz_policy.reload_interval", "must be positive when file is set")
+	v.check(c.GRPC.AdminAppID >= 0, "grpc.admin_app_id", "must not be negative")
+}
+
+// validateRegistration checks the restrictions on new accounts.
+func (c *Config) validateRegistration(v *validation) {
+	reg := c.Registration
+
+	strategy := ids.Strategy(reg.IDStrategy)
+	v.check(strategy.Valid(), "registration.id_strategy", "unknown strategy %q", reg.IDStrategy)
+
+	// Regions assign sequential IDs independently, so they would collide once replicated.
+	v.check(c.Region == "" || strategy != ids.StrategySequential, "region", "requires a registration.id_strategy other than %q", ids.StrategySequential)
+
+	_, err := netaddr.ParsePrefixes(reg.IPAllowlist)
+	v.check(err == nil, "registration.ip_allowlist", "%v", err)
+
+	v.check(reg.PerIPDailyLimit >= 0, "registration.per_ip_daily_limit", "must not be negative")
+	v.check(reg.DisposableEmails.ListURL == "" || reg.DisposableEmails.RefreshInterval > 0,
+		"registration.disposable_emails.refresh_interval", "must be positive when list_url is set")
+
+	modes := []struct{ field, mode string }{
+		{"enforcement.registration_domains", c.Enforcement.RegistrationDomains},
+		{"enforcement.disposable_emails", c.Enforcement.DisposableEmails},
+		{"enforcement.registration_quota", c.Enforcement.RegistrationQuota},
+		{"enforcement.password_reset_limit", c.Enforcement.PasswordResetLimit},
+	}
+	for _, m := range modes {
+		v.check(enforcement.Mode(m.mode).Valid(), m.field, "must be %s or %s, got %q", enforcement.ModeEnforce, enforcement.ModeReportOnly, m.mode)
+	}
+
+	async := reg.Async
+	v.check(async.Workers >= 0 && async.QueueSize >= 0, "registration.async", "workers and queue_size must not be negative")
+	v.check(async.Workers == 0 || (async.StatusTTL > 0 && async.Timeout > 0), "registration.async", "status_ttl and timeout must be positive")
+}
+
+// validateAuthentication checks the settings of logins, tokens, and second factors.
+func (c *Config) validateAuthentication(v *validation) {
+	hashing := c.PasswordHashing
+	for _, cost := range []int{hashing.Cost, hashing.MinCost, hashing.MaxCost} {
+		v.check(cost >= bcrypt.MinCost && cost <= bcrypt.MaxCost, "password_hashing", "cost %d is outside [%d, %d]", cost, bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
+	v.check(hashing.TargetDuration <= 0 || (hashing.Cost >= hashing.MinCost && hashing.Cost <= hashing.MaxCost),
+		"password_hashing.cost", "must be between min_cost and max_cost when tuning")
+
+	v.check(c.DeviceAuthorization.CodeTTL > 0, "device_authorization.code_ttl", "must be positive")
+	v.check(c.DeviceAuthorization.PollInterval >= time.Second, "device_authorization.poll_interval", "must be at least 1s")
+	v.check(c.PushMFA.ChallengeTTL > 0, "push_mfa.challenge_ttl", "must be positive")
+	v.check(c.StepUp.MaxAge > 0, "step_up.max_age", "must be positive")
+
+	for action, maxAge := range c.StepUp.Actions {
+		v.check(maxAge > 0, "step_up.actions."+action, "must be positive")
+	}
+
+	v.check(c.TokenValidation.MicroCacheTTL >= 0 && c.TokenValidation.MicroCacheTTL <= MaxMicroCacheTTL,
+		"token_validation.micro_cache_ttl", "must be between 0 and %s", MaxMicroCacheTTL)
+
+	v.check(c.RefreshTokens.TTL >= 0, "refresh_tokens.ttl", "must not be negative")
+
+	v.check(jwt.ValidFormat(c.TokenFormat), "token_format", "must be jwt, jwt_rs256, or paseto_v4_local")
+
+	signing := c.TokenSigning
+	v.check(c.TokenFormat != jwt.FormatJWTRS256 || signing.PrivateKey != "" || signing.PrivateKeyFile != "",
+		"token_format", "jwt_rs256 requires a token_signing key")
+	v.check(signing.GracePeriod >= c.TokenTTL, "token_signing.grace_period", "must be at least token_ttl, so tokens outlive the key they were signed with")
+	v.check(signing.RotationInterval >= 0, "token_signing.rotation_interval", "must not be negative")
+	v.check(signing.CheckInterval > 0, "token_signing.check_interval", "must be positive")
+
+	if c.Avatars.Dir != "" {
+		baseURL, err := url.Parse(c.Avatars.BaseURL)
+		v.check(err == nil && baseURL.IsAbs(), "avatars.base_url", "must be an absolute URL when dir is set")
+		v.check(c.Avatars.MaxSize > 0, "avatars.max_size", "must be positive")
+	}
+
+	v.check(c.SupportTokens.DefaultTTL > 0 && c.SupportTokens.MaxTTL > 0, "support_tokens", "default_ttl and max_ttl must be positive")
+	v.check(c.SupportTokens.DefaultTTL <= c.SupportTokens.MaxTTL, "support_tokens.default_ttl", "must not exceed max_ttl")
+	v.check(!c.TestTokens.Enabled || c.TestTokens.MaxTTL > 0, "test_tokens.max_ttl", "must be positive")
+
+	if c.LoginNotifications.Enabled {
+		reportURL, err := url.Parse(c.LoginNotifications.ReportURL)
+		v.check(err == nil && reportURL.IsAbs(), "login_notifications.report_url", "must be an absolute URL")
+		v.check(c.LoginNotifications.Key != "", "login_notifications.key", "is required when enabled")
+	}
+
+	if c.PII.Key != "" {
+		_, err := pii.New(c.PII.Key)
+		v.check(err == nil, "pii.key", "%v", err)
+	}
+
+	_, err := attributes.NewSchema(c.UserAttributes.Types)
+	v.check(err == nil, "user_attributes.types", "%v", err)
+
+	if c.RADIUS.Addr != "" {
+		v.check(c.RADIUS.ApprovalTimeout > 0, "radius.approval_timeout", "must be positive")
+		v.check(c.RADIUS.MaxInFlight > 0, "radius.max_in_flight", "must be positive")
+
+		for i, client := range c.RADIUS.Clients {
+			field := fmt.Sprintf("radius.clients[%d]", i)
+
+			_, err := netaddr.ParsePrefix(client.Network)
+			v.check(err == nil, field+".network", "must be an address or CIDR range")
+			v.check(client.Secret != "", field+".secret", "is required")
+			v.check(client.AppID > 0, field+".app_id", "is required")
+		}
+	}
+}
+
+// validateBackground checks the settings of background deliveries, replication, sampling, and snapshots.
+func (c *Config) validateBackground(v *validation) {
+	logout := c.BackchannelLogout
+	v.check(logout.Attempts >= 0, "backchannel_logout.attempts", "must not be negative")
+	v.check(logout.Attempts == 0 || (logout.TokenTTL > 0 && logout.Timeout > 0 && logout.Backoff > 0),
+		"backchannel_logout", "token_ttl, timeout, and backoff must be positive")
+
+	outbox := c.Outbox
+	v.check(outbox.PollInterval > 0, "outbox.poll_interval", "must be positive")
+	v.check(outbox.BatchSize > 0 && outbox.Workers > 0, "outbox", "batch_size and workers must be positive")
+	v.check(outbox.Lease > logout.Timeout, "outbox.lease", "must exceed backchannel_logout.timeout, or deliveries in progress are claimed again")
+
+	v.check(c.CacheBus.PollInterval <= 0 || c.CacheBus.Retention > c.CacheBus.PollInterval, "cache_bus.retention", "must exceed poll_interval")
+	v.check(c.Usage.FlushInterval >= 0, "usage.flush_interval", "must not be negative")
+
+	diag := c.Diagnostics
+	v.check(diag.SampleInterval >= 0, "diagnostics.sample_interval", "must not be negative")
+	v.check(diag.GoroutineThreshold >= 0 && diag.HeapThresholdMB >= 0, "diagnostics", "thresholds must not be negative")
+	v.check(diag.DumpCooldown > 0 || (diag.GoroutineThreshold == 0 && diag.HeapThresholdMB == 0),
+		"diagnostics.dump_cooldown", "must be positive when a threshold is set")
+
+	slo := c.SLO
+	if len(slo.Objectives) > 0 {
+		v.check(slo.AlertWindow > 0, "slo.alert_window", "must be positive")
+		v.check(slo.Window >= slo.AlertWindow, "slo.window", "must be at least alert_window")
+		v.check(slo.BurnRateAlert > 0, "slo.burn_rate_alert", "must be positive")
+		v.check(slo.CheckInterval > 0, "slo.check_interval", "must be positive")
+	}
+
+	if snapshots := c.Snapshots; snapshots.Interval != 0 {
+		v.check(snapshots.Interval > 0, "snapshots.interval", "must not be negative")
+		v.check(snapshots.Retention > 0, "snapshots.retention", "must be positive")
+		v.check(snapshots.Timeout > 0, "snapshots.timeout", "must be positive")
+
+		key, err := base64.StdEncoding.DecodeString(snapshots.Key)
+		v.check(err == nil && len(key) == 32, "snapshots.key", "must be a base64-encoded 32-byte key")
+
+		endpoint, err := url.Parse(snapshots.Endpoint)
+		v.check(err == nil && endpoint.IsAbs(), "snapshots.endpoint", "must be an absolute URL")
+		v.check(snapshots.Bucket != "", "snapshots.bucket", "is required")
+		v.check(snapshots.AccessKeyID != "" && snapshots.SecretAccessKey != "", "snapshots", "access_key_id and secret_access_key are required")
+	}
+
+	methods := make(map[string]bool, len(slo.Objectives))
+
+	for i, objective := range slo.Objectives {
+		field := fmt.Sprintf("slo.objectives[%d]", i)
+
+		service, method, ok := strings.Cut(strings.TrimPrefix(objective.Method, "/"), "/")
+		v.check(strings.HasPrefix(objective.Method, "/") && ok && service != "" && method != "" && !strings.Contains(method, "/"),
+			field+".method", "must be a full method name, e.g. /auth.Auth/Login")
+		v.check(!methods[objective.Method], field+".method", "has another objective")
+		v.check(objective.Availability >= 0 && objective.Availability < 1, field+".availability", "must be at least 0 and below 1")
+		v.check(objective.LatencyTarget >= 0 && objective.LatencyTarget < 1, field+".latency_target", "must be at least 0 and below 1")
+		v.check(objective.LatencyTarget == 0 || objective.Latency > 0, field+".latency", "must be positive when latency_target is set")
+		v.check(objective.Availability > 0 || objective.LatencyTarget > 0, field, "must set availability or latency_target")
+
+		methods[objective.Method] = true
+	}
+}
+
+// validateProduction checks the rules only production is held to: traffic and audit
+// events must be protected, emails must be delivered, and debugging services must be off.
+func (c *Config) validateProduction(v *validation) {
+	v.check(c.GRPC.TLS.CertFile != "" || c.GRPC.XDS, "grpc.tls.cert_file", "is required in %s, unless xds provides TLS", EnvProd)
+	v.check(!c.GRPC.Channelz, "grpc.channelz", "must be off in %s, as channelz is unauthenticated", EnvProd)
+	v.check(c.Audit.Key != "", "audit.key", "is required in %s, so the audit log cannot be rebuilt after tampering", EnvProd)
+	v.check(c.SMTP.Host != "", "smtp.host", "is required in %s, as emails are only logged without it", EnvProd)
+	v.check(!c.TestTokens.Enabled, "test_tokens.enabled", "must be off in %s, as test tokens carry arbitrary claims", EnvProd)
+}
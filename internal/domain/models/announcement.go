@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Announcement is a message operators attach to every response, e.g. of upcoming maintenance.
+type Announcement struct {
+	Message   string
+	CreatedAt time.Time
+	ExpiresAt time.Time // zero if the announcement is shown until cleared
+}
+
+// Active reports whether the announcement is still shown at now.
+func (a *Announcement) Active(now time.Time) bool {
+	return a != nil && (a.ExpiresAt.IsZero() || now.Before(a.ExpiresAt))
+}
@@ -1,9 +1,33 @@
 // Package models provides data models for the SSO service.
 package models
 
+import "time"
+
 // App represents an application registered with the SSO service.
 type App struct {
-	ID     int
-	Name   string
-	Secret string
+	ID                   int
+	Name                 string
+	Secret               string
+	TokenFormat          string        // format of issued tokens, e.g. "jwt" or "paseto_v4_local"
+	MinimalClaims        bool          // issued tokens carry only sub, aud, iat, and exp, and no personal data
+	RequireMembership    bool          // only users granted access may log in
+	FamilyID             int32         // family the app belongs to; 0 if none
+	BackchannelLogoutURI string        // URI logout tokens are POSTed to when a user's tokens are revoked; empty if none
+	TokenTTL             time.Duration // lifetime of issued tokens; 0 uses the server default
+	TestOnly             bool          // test tokens may be minted for the app with MintTestToken
+	Family               *AppFamily    // family the app belongs to; nil unless loaded for token issuance
+	CreatedAt            time.Time     // zero if unknown
+	UpdatedAt            time.Time     // zero if unknown
+	TokensRevokedAt      time.Time     // tokens issued for the app at or before this moment are rejected; zero if never revoked
+	Version              int64         // incremented on every update, used for optimistic locking
+}
+
+// AppFamily represents a group of apps sharing an audience: tokens issued for any of
+// them are signed with the family secret and accepted by the others.
+type AppFamily struct {
+	ID        int32
+	Name      string
+	Secret    string
+	AppIDs    []int32 // IDs of the apps in the family, in ascending order
+	CreatedAt time.Time
 }
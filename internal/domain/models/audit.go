@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// AuditEvent records a call to a sensitive API method.
+type AuditEvent struct {
+	ID          int64
+	At          time.Time
+	Method      string // full gRPC method name
+	ActorUserID int64  // 0 if the caller was not authenticated
+	Target      string // identifiers from the request, e.g. "app_id=3"
+	Code        string // gRPC status code of the result, e.g. "OK"
+	SourceIP    string // address of the client; empty if unknown
+	PrevHash    []byte // hash of the previous event; zeros for the first one
+	Hash        []byte
+}
+
+// AuditVerification reports the result of checking the audit event chain.
+type AuditVerification struct {
+	Events         int64  // number of events checked
+	Valid          bool   // whether every event matches its hash and links to its predecessor
+	FirstInvalidID int64  // ID of the first event that does not verify; 0 if valid
+	HeadHash       []byte // hash of the last valid event; auditors can record it to detect truncation
+}
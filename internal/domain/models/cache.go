@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// CacheInvalidation represents a change to cached data, published for the other replicas.
+type CacheInvalidation struct {
+	ID        int64
+	Topic     string // kind of cached data, e.g. "roles"
+	Key       string // entry to drop, e.g. a user ID; empty to drop every entry of the topic
+	CreatedAt time.Time
+}
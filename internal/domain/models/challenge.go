@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Statuses of a login challenge.
+const (
+	LoginChallengePending  = "pending"  // waiting for the user
+	LoginChallengeApproved = "approved" // approved by the user, token not yet issued
+	LoginChallengeDenied   = "denied"   // denied by the user
+	LoginChallengeConsumed = "consumed" // token issued to the client that logged in
+)
+
+// LoginChallenge represents a login of a user with push MFA, or a step-up of a signed-in user
+// before a sensitive action, waiting for the user to approve it from a device that stays signed in.
+type LoginChallenge struct {
+	ID            int64
+	ChallengeHash []byte // SHA-256 of the challenge ID returned by Login; the ID itself is never stored
+	UserID        int64
+	AppID         int32
+	AppName       string // name of the app; only set when listing challenges
+	Status        string
+	Action        string // sensitive action a step-up was required for; empty for logins
+	IP            string // network address the login came from; empty if unknown
+	UserAgent     string // user agent the login came from; empty if unknown
+	CreatedAt     time.Time
+	ExpiresAt     time.Time
+}
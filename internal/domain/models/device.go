@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Statuses of a device authorization.
+const (
+	DeviceAuthorizationPending  = "pending"  // waiting for the user
+	DeviceAuthorizationApproved = "approved" // approved by the user, token not yet issued
+	DeviceAuthorizationDenied   = "denied"   // denied by the user
+	DeviceAuthorizationConsumed = "consumed" // token issued to the device
+)
+
+// DeviceAuthorization represents a pending or completed OAuth 2.0 device authorization (RFC 8628).
+type DeviceAuthorization struct {
+	ID             int64
+	DeviceCodeHash []byte // SHA-256 of the device code; the code itself is never stored
+	UserCode       string // normalized user code, without separators
+	AppID          int32
+	UserID         int64 // 0 until the user approves or denies the request
+	Status         string
+	Interval       time.Duration // minimum time between polls
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+	LastPolledAt   time.Time // zero if never polled
+}
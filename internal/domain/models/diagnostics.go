@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Diagnostics holds a sample of the runtime of one replica.
+type Diagnostics struct {
+	SampledAt    time.Time
+	StartedAt    time.Time // when the process started
+	GoVersion    string
+	GOMAXPROCS   int
+	Goroutines   int
+	HeapAlloc    uint64        // bytes of allocated heap objects
+	HeapInuse    uint64        // bytes in in-use heap spans
+	HeapObjects  uint64        // number of allocated heap objects
+	Sys          uint64        // bytes obtained from the OS
+	NumGC        uint32        // completed GC cycles
+	GCPauseTotal time.Duration // stop-the-world pauses since the process started
+	LastGC       time.Time     // zero if no GC has completed
+	LastGCPause  time.Duration
+	StackDumps   int64     // goroutine stacks logged since the process started
+	LastDump     time.Time // zero if no stacks were logged
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// UserSnapshot is the state of a user at a point in time, as recorded by the user history.
+type UserSnapshot struct {
+	UserID     int64
+	At         time.Time
+	IsAdmin    bool
+	FrozenAt   time.Time // zero if the account was not frozen
+	MergedInto int64     // 0 if the account was not merged
+	PushMFA    bool
+	MemberOf   []int32 // IDs of the apps the user was a member of, in ascending order
+	OwnerOf    []int32 // IDs of the apps the user owned, in ascending order
+	Attributes map[string]string
+	Labels     map[string]string
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// IdempotencyRecord represents a request processed under a client-supplied idempotency key.
+type IdempotencyRecord struct {
+	Method      string
+	Key         string
+	RequestHash []byte
+	Response    []byte // nil while the original request is still in progress
+	ExpiresAt   time.Time
+}
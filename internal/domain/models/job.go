@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// JobStatus describes a background job of one replica and the outcome of its last run.
+type JobStatus struct {
+	Name            string
+	Description     string
+	Interval        time.Duration // time between scheduled runs
+	Running         bool
+	Runs            int64     // runs completed since the process started
+	Failures        int64     // runs that failed since the process started
+	LastStartedAt   time.Time // zero if the job never ran
+	LastDuration    time.Duration
+	LastError       string    // empty if the last run succeeded
+	LastSucceededAt time.Time // zero if no run succeeded
+	NextRunAt       time.Time // when the next scheduled run starts
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Topics of outbox messages.
+const (
+	TopicBackchannelLogout = "backchannel_logout" // payload is a BackchannelLogout
+)
+
+// OutboxMessage represents a message for another system, stored in the same transaction as
+// the change it reports and delivered by the outbox dispatcher, at least once.
+type OutboxMessage struct {
+	ID            int64
+	Topic         string
+	Payload       []byte // JSON document whose schema depends on the topic
+	CreatedAt     time.Time
+	Attempts      int       // deliveries started so far, including the one in progress
+	NextAttemptAt time.Time // moment the message is due; pushed back while a delivery is in progress
+	LastError     string    // error of the last failed delivery; empty if none failed
+	FailedAt      time.Time // moment delivery was given up; zero while it is retried
+}
+
+// BackchannelLogout is the payload of a TopicBackchannelLogout message: the app to send
+// a logout token for the user whose tokens were revoked.
+type BackchannelLogout struct {
+	AppID  int32 `json:"app_id"`
+	UserID int64 `json:"user_id"`
+}
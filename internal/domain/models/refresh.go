@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// RefreshToken represents a refresh token issued alongside an access token. Each exchange
+// revokes it and issues the next token of its chain, which keeps the chain's expiration.
+type RefreshToken struct {
+	ID        int64
+	TokenHash []byte // SHA-256 of the token; the token itself is never stored
+	ChainID   int64  // ID of the first token of the chain, issued at login
+	UserID    int64
+	AppID     int32
+	AuthTime  time.Time // moment the user authenticated at login
+	Methods   []string  // methods the user authenticated with at login
+	ACR       string    // authentication context class the user reached at login
+	IP        string    // network address of the client starting the chain, recorded on its session; not read back
+	UserAgent string    // user agent of the client starting the chain, recorded on its session; not read back
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt time.Time // zero unless the token was exchanged or revoked
+}
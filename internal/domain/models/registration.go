@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Statuses of an async registration.
+const (
+	RegistrationPending   = "pending"   // queued or being processed by a worker
+	RegistrationCompleted = "completed" // account created
+	RegistrationFailed    = "failed"    // account not created; see FailureReason
+)
+
+// Reasons an async registration failed.
+const (
+	RegistrationFailedUserExists  = "user_exists" // the email was registered while the request was queued
+	RegistrationFailedInterrupted = "interrupted" // the server stopped before processing the request
+	RegistrationFailedInternal    = "internal"    // any other failure
+)
+
+// Registration represents the progress of an async registration.
+type Registration struct {
+	ID                 int64
+	RegistrationIDHash []byte // SHA-256 of the registration ID; the ID itself is never stored
+	UserID             int64  // 0 until the account is created
+	PublicID           string // public ID of the user; empty until the account is created
+	Status             string
+	FailureReason      string // empty unless Status is RegistrationFailed
+	CreatedAt          time.Time
+	ExpiresAt          time.Time
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Session represents a sign-in of a user on a device: the chain of refresh tokens issued at
+// login, and the client it was issued to.
+type Session struct {
+	ID         int64 // ID of the refresh token chain
+	UserID     int64
+	AppID      int32
+	AppName    string    // name of the app; only set when listing sessions
+	IP         string    // network address the login came from; empty if unknown
+	UserAgent  string    // user agent the login came from; empty if unknown
+	CreatedAt  time.Time // moment of the login
+	LastUsedAt time.Time // moment a refresh token of the chain was last exchanged; CreatedAt until then
+	ExpiresAt  time.Time // moment the chain expires
+}
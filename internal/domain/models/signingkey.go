@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SigningKey is an RSA key RS256 tokens are signed with or verified against.
+type SigningKey struct {
+	ID         string    // key ID, the RFC 7638 thumbprint of the public key
+	PrivateKey []byte    // PKCS #8 PEM of the private key; nil once the key is retired
+	PublicKey  []byte    // PKIX PEM of the public key
+	CreatedAt  time.Time // zero for the configured key, which was not created by a rotation
+	RetiredAt  time.Time // moment a newer key replaced it; zero for the key signing tokens
+	ExpiresAt  time.Time // moment a retired key stops verifying tokens, after the grace period; zero for the key signing tokens
+}
+
+// Active reports whether the key signs new tokens.
+func (k *SigningKey) Active() bool {
+	return k.RetiredAt.IsZero()
+}
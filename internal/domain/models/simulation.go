@@ -0,0 +1,23 @@
+package models
+
+// Outcomes of a simulated registration.
+const (
+	RegistrationAccepted = "accepted"
+	RegistrationFlagged  = "flagged" // accepted, and the user is flagged for a disposable email
+	RegistrationRejected = "rejected"
+)
+
+// LoginSimulation describes what a login would yield, without a token being issued.
+type LoginSimulation struct {
+	Allowed              bool
+	DenialReason         string // reason the login would be refused, as reported for failed logins; empty if allowed
+	PushApprovalRequired bool   // the login would wait for approval from a signed-in device
+	Claims               []byte // JSON object of the claims the token would carry; nil if refused
+}
+
+// RegistrationSimulation describes how a registration would be handled by the registration policies.
+type RegistrationSimulation struct {
+	Outcome string // one of the Registration outcome constants
+	Reason  string // why the registration would be rejected; empty unless rejected
+	Policy  string // policy rejecting it, as named in the enforcement config; empty unless rejected
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Stats holds aggregate counts describing the user base.
+type Stats struct {
+	TotalUsers  int64
+	Days        []DailyStats // one entry per UTC day, oldest first
+	GeneratedAt time.Time
+}
+
+// DailyStats holds activity counts for a single UTC day.
+type DailyStats struct {
+	Date          time.Time // midnight UTC
+	Registrations int64
+	ActiveUsers   int64 // distinct users who logged in
+}
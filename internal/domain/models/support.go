@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// SupportToken represents a read-only, time-boxed token issued by an admin to support tooling.
+// It is scoped to a single user or a single app.
+type SupportToken struct {
+	ID           int64
+	IssuedBy     int64  // ID of the admin who issued the token
+	UserID       int64  // user the token is scoped to; 0 if scoped to an app
+	UserPublicID string // public ID of the user the token is scoped to; empty if scoped to an app
+	AppID        int32  // app the token is scoped to; 0 if scoped to a user
+	Reason       string // why the token was issued, e.g. a ticket reference
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+	RevokedAt    time.Time // zero if not revoked
+}
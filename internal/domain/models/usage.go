@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Usage holds the authenticated requests made for an app by a user during a UTC day.
+type Usage struct {
+	Date     time.Time // midnight UTC
+	AppID    int32
+	UserID   int64 // 0 when the counts of every user of the app are summed
+	Requests int64
+	Errors   int64 // requests that failed with a status other than OK
+}
@@ -1,8 +1,23 @@
 package models
 
+import "time"
+
 // User represents a user registered with the SSO service.
 type User struct {
-	ID       int64
-	Email    string
-	PassHash []byte
+	ID        int64
+	PublicID  string // identifier exposed to clients; the decimal ID for sequential IDs
+	Email     string
+	PassHash  []byte
+	IsAdmin   bool
+	CreatedAt time.Time // zero if unknown
+	UpdatedAt time.Time // zero if unknown
+	Version   int64     // incremented on every update, used for optimistic locking
+
+	TokensRevokedAt time.Time // tokens issued at or before this moment are rejected; zero if never revoked
+	FrozenAt        time.Time // moment the account was frozen; zero if it is not frozen
+	MergedInto      int64     // ID of the user the account was merged into; 0 if it was not merged
+	PushMFA         bool      // logins must be approved from a device that stays signed in
+	Avatar          string    // key of the avatar in blob storage; empty if none
+
+	Attributes map[string]string // custom attributes by key; nil unless loaded for token issuance
 }
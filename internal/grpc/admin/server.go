@@ -0,0 +1,2265 @@
+// Package admin implements the gRPC server for the admin service.
+// It provides management operations for client applications.
+package admin
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	pb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/grpc/interceptors"
+	"github.com/kirinyoku/sso-grpc/internal/lib/disposable"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/lib/templates"
+	"github.com/kirinyoku/sso-grpc/internal/services/admin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Admin defines the interface that must be implemented by the admin service.
+type Admin interface {
+	// CreateApp registers a new client application.
+	// tokenFormat is empty for JWT, and familyID is 0 for none.
+	CreateApp(
+		ctx context.Context,
+		name string,
+		secret string,
+		tokenFormat string,
+		minimalClaims bool,
+		requireMembership bool,
+		familyID int32,
+	) (appID int32, err error)
+	// DeleteApp removes a client application.
+	DeleteApp(ctx context.Context, appID int32) error
+	// GetApp returns a client application.
+	GetApp(ctx context.Context, appID int32) (*models.App, error)
+	// ListApps returns up to limit client applications with an ID above afterID having every label.
+	ListApps(ctx context.Context, labels map[string]string, afterID int32, limit int) ([]*models.App, error)
+	// UpdateApp changes a client application if it is still at the given version.
+	// Nil minimalClaims, requireMembership, and familyID keep the current settings.
+	UpdateApp(
+		ctx context.Context,
+		appID int32,
+		name string,
+		secret string,
+		tokenFormat string,
+		minimalClaims *bool,
+		requireMembership *bool,
+		familyID *int32,
+		version int64,
+	) (*models.App, error)
+	// RotateAppSecret replaces the secret of an app with a random one and returns it.
+	RotateAppSecret(ctx context.Context, appID int32) (string, *models.App, error)
+	// GetUser returns a user.
+	GetUser(ctx context.Context, userID int64) (*models.User, error)
+	// ResolveUserID returns the ID of the user with the given public ID.
+	ResolveUserID(ctx context.Context, publicID string) (userID int64, err error)
+	// UpdateUser changes a user's admin flag if the user is still at the given version.
+	UpdateUser(ctx context.Context, userID int64, isAdmin bool, version int64) (*models.User, error)
+	// FreezeUser freezes a user's account and revokes all of its tokens.
+	FreezeUser(ctx context.Context, userID int64) (*models.User, error)
+
+	// RevokeAllUserTokens revokes every access and refresh token issued to a user so far.
+	RevokeAllUserTokens(ctx context.Context, userID int64) (*models.User, error)
+	// SetUserPushMFA turns push approval of logins on or off for a user.
+	SetUserPushMFA(ctx context.Context, userID int64, enabled bool) error
+	// MergeUsers merges a duplicate account into the primary one and leaves the duplicate as a tombstone.
+	MergeUsers(ctx context.Context, primaryID int64, duplicateID int64) (*models.User, error)
+	// GrantAppAccess makes a user a member of an app.
+	GrantAppAccess(ctx context.Context, appID int32, userID int64) error
+	// RevokeAppAccess removes a user from the members of an app.
+	RevokeAppAccess(ctx context.Context, appID int32, userID int64) error
+	// AddAppOwner makes a user an owner of an app.
+	AddAppOwner(ctx context.Context, appID int32, userID int64) error
+	// RemoveAppOwner removes a user from the owners of an app.
+	RemoveAppOwner(ctx context.Context, appID int32, userID int64) error
+	// ListAppOwners returns the owners of an app in order of ID.
+	ListAppOwners(ctx context.Context, appID int32) ([]*models.User, error)
+	// CreateAppFamily creates a family of apps sharing an audience.
+	CreateAppFamily(ctx context.Context, name string, secret string) (familyID int32, err error)
+	// GetAppFamily returns an app family along with the IDs of its apps.
+	GetAppFamily(ctx context.Context, familyID int32) (*models.AppFamily, error)
+	// DeleteAppFamily removes an app family that no longer has apps.
+	DeleteAppFamily(ctx context.Context, familyID int32) error
+	// GetUserAttributes returns the custom attributes of a user.
+	GetUserAttributes(ctx context.Context, userID int64) (map[string]string, error)
+	// SetUserAttributes replaces the custom attributes of a user and returns them as stored.
+	SetUserAttributes(ctx context.Context, userID int64, attrs map[string]string) (map[string]string, error)
+	// FindUsersByAttribute returns up to limit users with an ID above afterID having an attribute set to a value.
+	FindUsersByAttribute(ctx context.Context, key string, value string, afterID int64, limit int) ([]*models.User, error)
+	// SearchUsers returns up to limit users having every label whose email starts with a query or,
+	// if fuzzy is set, is similar to it.
+	SearchUsers(ctx context.Context, query string, fuzzy bool, labels map[string]string, limit int) ([]*models.User, error)
+	// GetUserLabels returns the labels of a user.
+	GetUserLabels(ctx context.Context, userID int64) (map[string]string, error)
+	// SetUserLabels replaces the labels of a user.
+	SetUserLabels(ctx context.Context, userID int64, labels map[string]string) error
+	// InvalidatePasswordResetTokens revokes every outstanding password reset token.
+	InvalidatePasswordResetTokens(ctx context.Context) (invalidated int64, err error)
+	// RevokeAppTokens revokes every token issued for an app so far and purges its pending device authorizations.
+	RevokeAppTokens(ctx context.Context, appID int32) (app *models.App, purged int64, err error)
+	// RenderEmailTemplate renders an email template with sample data.
+	RenderEmailTemplate(ctx context.Context, name string, appID int32, locale string, data map[string]string) (*templates.Email, error)
+	// GetAppEmailDomains returns the email domains allowed and denied registration through an app.
+	GetAppEmailDomains(ctx context.Context, appID int32) (allowed []string, denied []string, err error)
+	// SetAppEmailDomains replaces the email domains allowed and denied registration through an app.
+	SetAppEmailDomains(ctx context.Context, appID int32, allowed []string, denied []string) error
+	// GetAppDisposableEmailPolicy returns how an app handles disposable email addresses.
+	GetAppDisposableEmailPolicy(ctx context.Context, appID int32) (disposable.Policy, error)
+	// SetAppDisposableEmailPolicy sets how an app handles disposable email addresses.
+	SetAppDisposableEmailPolicy(ctx context.Context, appID int32, policy disposable.Policy) error
+	// GetAppLabels returns the labels of an app.
+	GetAppLabels(ctx context.Context, appID int32) (map[string]string, error)
+	// SetAppLabels replaces the labels of an app.
+	SetAppLabels(ctx context.Context, appID int32, labels map[string]string) error
+	// SetAppBackchannelLogoutURI sets the URI an app receives logout tokens at.
+	SetAppBackchannelLogoutURI(ctx context.Context, appID int32, uri string) error
+
+	// SetAppTokenTTL sets the lifetime of tokens issued for an app.
+	SetAppTokenTTL(ctx context.Context, appID int32, ttl time.Duration) error
+	// SetAppTestOnly sets whether test tokens may be minted for an app.
+	SetAppTestOnly(ctx context.Context, appID int32, testOnly bool) error
+	// GetStats returns aggregate user counts with daily counts for the given number of days.
+	GetStats(ctx context.Context, days int) (*models.Stats, error)
+	// GetUsage returns up to limit entries of daily usage per app, or per app and user if byUser is set.
+	GetUsage(ctx context.Context, appID int32, userID int64, days int, byUser bool, limit int) ([]models.Usage, bool, error)
+	// VerifyAuditLog checks that no audit event was modified, inserted, or removed.
+	VerifyAuditLog(ctx context.Context) (*models.AuditVerification, error)
+	// IssueSupportToken issues a read-only support token scoped to a user or an app.
+	IssueSupportToken(
+		ctx context.Context,
+		issuerID int64,
+		userID int64,
+		appID int32,
+		ttl time.Duration,
+		reason string,
+	) (token string, supportToken *models.SupportToken, err error)
+	// RevokeSupportToken revokes a support token.
+	RevokeSupportToken(ctx context.Context, tokenID int64) error
+	// SetAnnouncement sets the announcement attached to every response; an empty message clears it.
+	SetAnnouncement(ctx context.Context, message string, expiresAt time.Time) error
+	// ReloadApps drops every cached result derived from apps on every replica.
+	ReloadApps(ctx context.Context) error
+	// GetDiagnostics samples the goroutines, heap, and garbage collector of this replica.
+	GetDiagnostics() *models.Diagnostics
+	// ListJobs returns the status of the background jobs of this replica.
+	ListJobs() []models.JobStatus
+	// GetJobStatus returns the status of a background job of this replica.
+	GetJobStatus(name string) (*models.JobStatus, error)
+	// RunJobNow runs a background job of this replica and waits for the run to finish.
+	RunJobNow(ctx context.Context, name string) (*models.JobStatus, error)
+	// ListSigningKeys returns the RSA keys RS256 tokens are signed with and verified against.
+	ListSigningKeys() []*models.SigningKey
+	// RotateSigningKey generates a new RSA key to sign RS256 tokens with and retires the current one.
+	RotateSigningKey(ctx context.Context) (*models.SigningKey, error)
+	// SimulateLogin evaluates what a login of a user to an app would yield under proposed app settings.
+	// An empty tokenFormat and nil minimalClaims and requireMembership keep the current settings.
+	SimulateLogin(
+		ctx context.Context,
+		userID int64,
+		appID int32,
+		tokenFormat string,
+		minimalClaims *bool,
+		requireMembership *bool,
+	) (*models.LoginSimulation, error)
+	// SimulatePolicy evaluates whether an email could register through an app under a proposed
+	// registration policy. Nil domains and policy keep the current ones.
+	SimulatePolicy(
+		ctx context.Context,
+		email string,
+		appID int32,
+		domains *admin.EmailDomains,
+		policy *disposable.Policy,
+	) (*models.RegistrationSimulation, error)
+	// GetUserAtTime returns the state of a user at a point in time.
+	GetUserAtTime(ctx context.Context, userID int64, at time.Time) (*models.UserSnapshot, error)
+}
+
+const (
+	// defaultStatsDays is the number of days reported when the request does not specify it.
+	defaultStatsDays = 30
+	// maxStatsDays is the maximum number of days a stats request may cover.
+	maxStatsDays = 90
+	// maxUsageEntries is the maximum number of entries a usage response carries.
+	maxUsageEntries = 1000
+	// defaultFindUsersPageSize is the number of users returned when the request does not specify it.
+	defaultFindUsersPageSize = 100
+	// maxFindUsersPageSize is the maximum number of users returned per page.
+	maxFindUsersPageSize = 1000
+	// defaultSearchUsersPageSize is the number of users a search returns when the request does not specify it.
+	defaultSearchUsersPageSize = 20
+	// maxSearchUsersPageSize is the maximum number of users a search returns.
+	maxSearchUsersPageSize = 100
+	// defaultListAppsPageSize is the number of apps returned when the request does not specify it.
+	defaultListAppsPageSize = 100
+	// maxListAppsPageSize is the maximum number of apps returned per page.
+	maxListAppsPageSize = 1000
+	// maxSearchQueryLength is the maximum number of characters of a search query, the length of the longest email.
+	maxSearchQueryLength = 254
+	// minFuzzySearchQueryLength is the minimum number of characters of a fuzzy search query, the length of a trigram.
+	minFuzzySearchQueryLength = 3
+	// maxAnnouncementLength is the maximum number of characters of an announcement.
+	maxAnnouncementLength = 500
+)
+
+// disposablePolicies maps API policies to the policies of the admin service.
+// The unspecified policy maps to the empty policy, which selects the server default.
+var disposablePolicies = map[pb.DisposableEmailPolicy]disposable.Policy{
+	pb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_UNSPECIFIED: "",
+	pb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_ALLOW:       disposable.PolicyAllow,
+	pb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_FLAG:        disposable.PolicyFlag,
+	pb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_REJECT:      disposable.PolicyReject,
+}
+
+// tokenFormats maps API token formats to the formats of the token library.
+// The unspecified format maps to the empty format, which selects JWT on creation
+// and keeps the current format on update.
+var tokenFormats = map[pb.TokenFormat]string{
+	pb.TokenFormat_TOKEN_FORMAT_UNSPECIFIED:     "",
+	pb.TokenFormat_TOKEN_FORMAT_JWT:             jwt.FormatJWT,
+	pb.TokenFormat_TOKEN_FORMAT_PASETO_V4_LOCAL: jwt.FormatPASETO,
+	pb.TokenFormat_TOKEN_FORMAT_JWT_RS256:       jwt.FormatJWTRS256,
+}
+
+// server implements the gRPC Admin service.
+type server struct {
+	pb.UnimplementedAdminServer       // Embed the unimplemented server for forward compatibility
+	admin                       Admin // Admin service implementation
+}
+
+// Register registers the admin service implementation with the gRPC server.
+//
+// Parameters:
+//   - s: The gRPC server instance
+//   - admin: Implementation of the Admin interface
+func Register(s grpc.ServiceRegistrar, admin Admin) {
+	pb.RegisterAdminServer(s, &server{admin: admin})
+}
+
+const (
+	// emptyValue represents the zero value for numeric fields in protobuf messages
+	emptyValue = 0
+)
+
+// CreateApp handles client application registration requests.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.AlreadyExists: if the app name or secret is already taken
+//   - codes.NotFound: if no app family exists with family_id
+//   - codes.FailedPrecondition: if family_id is set and the app would be the admin app
+//   - codes.Internal: if the creation process fails
+func (s *server) CreateApp(ctx context.Context, req *pb.CreateAppRequest) (*pb.CreateAppResponse, error) {
+	if err := validateCreateAppRequest(req); err != nil {
+		return nil, err
+	}
+
+	appID, err := s.admin.CreateApp(
+		ctx,
+		req.GetName(),
+		req.GetSecret(),
+		tokenFormats[req.GetTokenFormat()],
+		req.GetMinimalClaims(),
+		req.GetRequireMembership(),
+		req.GetFamilyId(),
+	)
+	if err != nil {
+		if errors.Is(err, admin.ErrAppExists) {
+			return nil, status.Error(codes.AlreadyExists, "app already exists")
+		}
+
+		if errors.Is(err, admin.ErrInvalidTokenFormat) {
+			return nil, status.Error(codes.InvalidArgument, "token_format is not supported by the server")
+		}
+
+		if errors.Is(err, admin.ErrAppFamilyNotFound) {
+			return nil, status.Error(codes.NotFound, "app family not found")
+		}
+
+		if errors.Is(err, admin.ErrAdminAppInFamily) {
+			return nil, status.Error(codes.FailedPrecondition, "the admin app cannot join an app family")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.CreateAppResponse{
+		AppId: appID,
+	}, nil
+}
+
+// DeleteApp handles client application removal requests.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.NotFound: if the app does not exist
+//   - codes.Internal: if the removal process fails
+func (s *server) DeleteApp(ctx context.Context, req *pb.DeleteAppRequest) (*pb.DeleteAppResponse, error) {
+	if err := validateDeleteAppRequest(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.admin.DeleteApp(ctx, req.GetAppId()); err != nil {
+		if errors.Is(err, admin.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.DeleteAppResponse{}, nil
+}
+
+// GetApp handles requests for a client application.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if app_id is missing
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.Internal: if the app cannot be read
+func (s *server) GetApp(ctx context.Context, req *pb.GetAppRequest) (*pb.GetAppResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	app, err := s.admin.GetApp(ctx, req.GetAppId())
+	if err != nil {
+		if errors.Is(err, admin.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.GetAppResponse{App: appToProto(app)}, nil
+}
+
+// ListApps handles requests listing client applications, optionally filtered by labels.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails or a label of the filter is invalid
+//   - codes.Internal: if the apps cannot be read
+func (s *server) ListApps(ctx context.Context, req *pb.ListAppsRequest) (*pb.ListAppsResponse, error) {
+	afterID, err := validateListAppsRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize == emptyValue {
+		pageSize = defaultListAppsPageSize
+	}
+
+	apps, err := s.admin.ListApps(ctx, req.GetLabels(), afterID, pageSize)
+	if err != nil {
+		if labelErr := labelsError(err); labelErr != nil {
+			return nil, labelErr
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &pb.ListAppsResponse{}
+
+	for _, app := range apps {
+		resp.Apps = append(resp.Apps, appToProto(app))
+	}
+
+	// A full page may be followed by more apps; the next request tells.
+	if len(apps) == pageSize {
+		resp.NextPageToken = strconv.FormatInt(int64(apps[len(apps)-1].ID), 10)
+	}
+
+	return resp, nil
+}
+
+// UpdateApp handles client application update requests.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.NotFound: if no app exists with the ID, or no app family with family_id
+//   - codes.Aborted: if the app was modified since the given version
+//   - codes.AlreadyExists: if the name or secret is taken by another app
+//   - codes.FailedPrecondition: if the app is the admin app and family_id names a family
+//   - codes.Internal: if the update fails
+func (s *server) UpdateApp(ctx context.Context, req *pb.UpdateAppRequest) (*pb.UpdateAppResponse, error) {
+	if err := validateUpdateAppRequest(req); err != nil {
+		return nil, err
+	}
+
+	app, err := s.admin.UpdateApp(
+		ctx,
+		req.GetAppId(),
+		req.GetName(),
+		req.GetSecret(),
+		tokenFormats[req.GetTokenFormat()],
+		req.MinimalClaims,
+		req.RequireMembership,
+		req.FamilyId,
+		req.GetVersion(),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidTokenFormat):
+			return nil, status.Error(codes.InvalidArgument, "token_format is not supported by the server")
+		case errors.Is(err, admin.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		case errors.Is(err, admin.ErrVersionConflict):
+			return nil, status.Error(codes.Aborted, "app was modified concurrently")
+		case errors.Is(err, admin.ErrAppExists):
+			return nil, status.Error(codes.AlreadyExists, "app already exists")
+		case errors.Is(err, admin.ErrAppFamilyNotFound):
+			return nil, status.Error(codes.NotFound, "app family not found")
+		case errors.Is(err, admin.ErrAdminAppInFamily):
+			return nil, status.Error(codes.FailedPrecondition, "the admin app cannot join an app family")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.UpdateAppResponse{App: appToProto(app)}, nil
+}
+
+// RotateAppSecret handles requests replacing the secret of an app with a random one.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if app_id is missing
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.FailedPrecondition: if the app is the admin app
+//   - codes.Aborted: if the app was modified concurrently
+//   - codes.Internal: if the secret cannot be rotated
+func (s *server) RotateAppSecret(ctx context.Context, req *pb.RotateAppSecretRequest) (*pb.RotateAppSecretResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	secret, app, err := s.admin.RotateAppSecret(ctx, req.GetAppId())
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		case errors.Is(err, admin.ErrAdminAppSecret):
+			return nil, status.Error(codes.FailedPrecondition, "the secret of the admin app can only be set with UpdateApp")
+		case errors.Is(err, admin.ErrVersionConflict):
+			return nil, status.Error(codes.Aborted, "app was modified concurrently")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RotateAppSecretResponse{Secret: secret, App: appToProto(app)}, nil
+}
+
+// GetUser handles requests for a user.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if user_id is missing
+//   - codes.NotFound: if no user exists with the ID
+//   - codes.Internal: if the user cannot be read
+func (s *server) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.admin.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, admin.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.GetUserResponse{User: userToProto(user)}, nil
+}
+
+// UpdateUser handles user update requests.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.NotFound: if no user exists with the ID
+//   - codes.Aborted: if the user was modified since the given version
+//   - codes.Internal: if the update fails
+func (s *server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	if err := validateUpdateUserRequest(req); err != nil {
+		return nil, err
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.admin.UpdateUser(ctx, userID, req.GetIsAdmin(), req.GetVersion())
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		case errors.Is(err, admin.ErrVersionConflict):
+			return nil, status.Error(codes.Aborted, "user was modified concurrently")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.UpdateUserResponse{User: userToProto(user)}, nil
+}
+
+// FreezeUser handles requests to freeze a user's account.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if user_id is missing
+//   - codes.NotFound: if no user exists with the ID
+//   - codes.Internal: if the account cannot be frozen
+func (s *server) FreezeUser(ctx context.Context, req *pb.FreezeUserRequest) (*pb.FreezeUserResponse, error) {
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.admin.FreezeUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, admin.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.FreezeUserResponse{User: userToProto(user)}, nil
+}
+
+// RevokeAllUserTokens handles requests to revoke every token issued to a user.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if user_id is missing
+//   - codes.NotFound: if no user exists with the ID
+//   - codes.Internal: if the tokens cannot be revoked
+func (s *server) RevokeAllUserTokens(
+	ctx context.Context,
+	req *pb.RevokeAllUserTokensRequest,
+) (*pb.RevokeAllUserTokensResponse, error) {
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.admin.RevokeAllUserTokens(ctx, userID)
+	if err != nil {
+		if errors.Is(err, admin.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RevokeAllUserTokensResponse{User: userToProto(user)}, nil
+}
+
+// SetUserPushMfa handles requests to turn push approval of logins on or off for a user.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if user_id is missing
+//   - codes.NotFound: if no user exists with the ID
+//   - codes.Internal: if the setting cannot be changed
+func (s *server) SetUserPushMfa(ctx context.Context, req *pb.SetUserPushMfaRequest) (*pb.SetUserPushMfaResponse, error) {
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.admin.SetUserPushMFA(ctx, userID, req.GetEnabled()); err != nil {
+		if errors.Is(err, admin.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SetUserPushMfaResponse{}, nil
+}
+
+// MergeUsers handles requests to merge a duplicate account into the primary one.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if either user is missing, or both are the same user
+//   - codes.NotFound: if either user doesn't exist
+//   - codes.FailedPrecondition: if either user was already merged into another one
+//   - codes.Internal: if the users cannot be merged
+func (s *server) MergeUsers(ctx context.Context, req *pb.MergeUsersRequest) (*pb.MergeUsersResponse, error) {
+	if req.GetPrimaryUserId() == emptyValue && req.GetPrimaryPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "primary_user_id is required")
+	}
+
+	if req.GetDuplicateUserId() == emptyValue && req.GetDuplicatePublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "duplicate_user_id is required")
+	}
+
+	primaryID, err := s.resolveUserID(ctx, req.GetPrimaryUserId(), req.GetPrimaryPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	duplicateID, err := s.resolveUserID(ctx, req.GetDuplicateUserId(), req.GetDuplicatePublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.admin.MergeUsers(ctx, primaryID, duplicateID)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrSameUser):
+			return nil, status.Error(codes.InvalidArgument, "cannot merge a user into itself")
+		case errors.Is(err, admin.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		case errors.Is(err, admin.ErrUserMerged):
+			return nil, status.Error(codes.FailedPrecondition, "user already merged")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.MergeUsersResponse{User: userToProto(user)}, nil
+}
+
+// GetUserAttributes handles requests for a user's custom attributes.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if user_id is missing
+//   - codes.NotFound: if no user exists with the ID
+//   - codes.Internal: if the attributes cannot be read
+func (s *server) GetUserAttributes(
+	ctx context.Context,
+	req *pb.GetUserAttributesRequest,
+) (*pb.GetUserAttributesResponse, error) {
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := s.admin.GetUserAttributes(ctx, userID)
+	if err != nil {
+		if errors.Is(err, admin.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.GetUserAttributesResponse{Attributes: attrs}, nil
+}
+
+// SetUserAttributes handles requests replacing a user's custom attributes.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if user_id is missing, an attribute is invalid, or there are too many
+//   - codes.NotFound: if no user exists with the ID
+//   - codes.Internal: if the attributes cannot be saved
+func (s *server) SetUserAttributes(
+	ctx context.Context,
+	req *pb.SetUserAttributesRequest,
+) (*pb.SetUserAttributesResponse, error) {
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := s.admin.SetUserAttributes(ctx, userID, req.GetAttributes())
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidAttribute):
+			return nil, status.Error(codes.InvalidArgument, "invalid attribute key or value")
+		case errors.Is(err, admin.ErrTooManyAttributes):
+			return nil, status.Error(codes.InvalidArgument, "too many attributes")
+		case errors.Is(err, admin.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SetUserAttributesResponse{Attributes: attrs}, nil
+}
+
+// FindUsersByAttribute handles requests for the users having an attribute value.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails or the key or value is invalid
+//   - codes.Internal: if the users cannot be read
+func (s *server) FindUsersByAttribute(
+	ctx context.Context,
+	req *pb.FindUsersByAttributeRequest,
+) (*pb.FindUsersByAttributeResponse, error) {
+	afterID, err := validateFindUsersByAttributeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize == emptyValue {
+		pageSize = defaultFindUsersPageSize
+	}
+
+	users, err := s.admin.FindUsersByAttribute(ctx, req.GetKey(), req.GetValue(), afterID, pageSize)
+	if err != nil {
+		if errors.Is(err, admin.ErrInvalidAttribute) {
+			return nil, status.Error(codes.InvalidArgument, "invalid attribute key or value")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &pb.FindUsersByAttributeResponse{}
+
+	for _, user := range users {
+		resp.Users = append(resp.Users, userToProto(user))
+	}
+
+	// A full page may be followed by more users; the next request tells.
+	if len(users) == pageSize {
+		resp.NextPageToken = strconv.FormatInt(users[len(users)-1].ID, 10)
+	}
+
+	return resp, nil
+}
+
+// SearchUsers handles requests finding users by part of their email.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.FailedPrecondition: if emails are stored encrypted
+//   - codes.Internal: if the users cannot be searched
+func (s *server) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (*pb.SearchUsersResponse, error) {
+	if err := validateSearchUsersRequest(req); err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize == emptyValue {
+		pageSize = defaultSearchUsersPageSize
+	}
+
+	fuzzy := req.GetMode() == pb.SearchMode_SEARCH_MODE_FUZZY
+
+	users, err := s.admin.SearchUsers(ctx, req.GetQuery(), fuzzy, req.GetLabels(), pageSize)
+	if err != nil {
+		if labelErr := labelsError(err); labelErr != nil {
+			return nil, labelErr
+		}
+
+		if errors.Is(err, admin.ErrSearchUnavailable) {
+			return nil, status.Error(codes.FailedPrecondition, "emails are stored encrypted and cannot be searched")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &pb.SearchUsersResponse{}
+
+	for _, user := range users {
+		resp.Users = append(resp.Users, userToProto(user))
+	}
+
+	return resp, nil
+}
+
+// GetUserLabels handles requests for a user's labels.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if user_id is missing
+//   - codes.NotFound: if no user exists with the ID
+//   - codes.Internal: if the labels cannot be read
+func (s *server) GetUserLabels(ctx context.Context, req *pb.GetUserLabelsRequest) (*pb.GetUserLabelsResponse, error) {
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := s.admin.GetUserLabels(ctx, userID)
+	if err != nil {
+		if errors.Is(err, admin.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.GetUserLabelsResponse{Labels: labels}, nil
+}
+
+// SetUserLabels handles requests replacing a user's labels.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if user_id is missing, a label is invalid, or there are too many
+//   - codes.NotFound: if no user exists with the ID
+//   - codes.Internal: if the labels cannot be saved
+func (s *server) SetUserLabels(ctx context.Context, req *pb.SetUserLabelsRequest) (*pb.SetUserLabelsResponse, error) {
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.admin.SetUserLabels(ctx, userID, req.GetLabels()); err != nil {
+		if labelErr := labelsError(err); labelErr != nil {
+			return nil, labelErr
+		}
+
+		if errors.Is(err, admin.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SetUserLabelsResponse{}, nil
+}
+
+// GrantAppAccess handles requests granting a user access to an app.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if app_id or user_id is missing
+//   - codes.NotFound: if no app or user exists with the ID
+//   - codes.Internal: if the membership cannot be changed
+func (s *server) GrantAppAccess(ctx context.Context, req *pb.GrantAppAccessRequest) (*pb.GrantAppAccessResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.admin.GrantAppAccess(ctx, req.GetAppId(), userID); err != nil {
+		switch {
+		case errors.Is(err, admin.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		case errors.Is(err, admin.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.GrantAppAccessResponse{}, nil
+}
+
+// RevokeAppAccess handles requests revoking a user's access to an app.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if app_id or user_id is missing
+//   - codes.NotFound: if no app or user exists with the ID
+//   - codes.Internal: if the membership cannot be changed
+func (s *server) RevokeAppAccess(ctx context.Context, req *pb.RevokeAppAccessRequest) (*pb.RevokeAppAccessResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.admin.RevokeAppAccess(ctx, req.GetAppId(), userID); err != nil {
+		switch {
+		case errors.Is(err, admin.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		case errors.Is(err, admin.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RevokeAppAccessResponse{}, nil
+}
+
+// AddAppOwner handles requests making a user an owner of an app.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if app_id or user_id is missing
+//   - codes.NotFound: if no app or user exists with the ID
+//   - codes.Internal: if the owners cannot be changed
+func (s *server) AddAppOwner(ctx context.Context, req *pb.AddAppOwnerRequest) (*pb.AddAppOwnerResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.admin.AddAppOwner(ctx, req.GetAppId(), userID); err != nil {
+		switch {
+		case errors.Is(err, admin.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		case errors.Is(err, admin.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.AddAppOwnerResponse{}, nil
+}
+
+// RemoveAppOwner handles requests removing a user from the owners of an app.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if app_id or user_id is missing
+//   - codes.NotFound: if no app or user exists with the ID
+//   - codes.Internal: if the owners cannot be changed
+func (s *server) RemoveAppOwner(ctx context.Context, req *pb.RemoveAppOwnerRequest) (*pb.RemoveAppOwnerResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.admin.RemoveAppOwner(ctx, req.GetAppId(), userID); err != nil {
+		switch {
+		case errors.Is(err, admin.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		case errors.Is(err, admin.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RemoveAppOwnerResponse{}, nil
+}
+
+// ListAppOwners handles requests for the owners of an app.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if app_id is missing
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.Internal: if the owners cannot be read
+func (s *server) ListAppOwners(ctx context.Context, req *pb.ListAppOwnersRequest) (*pb.ListAppOwnersResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	owners, err := s.admin.ListAppOwners(ctx, req.GetAppId())
+	if err != nil {
+		if errors.Is(err, admin.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &pb.ListAppOwnersResponse{}
+
+	for _, owner := range owners {
+		resp.Owners = append(resp.Owners, userToProto(owner))
+	}
+
+	return resp, nil
+}
+
+// CreateAppFamily handles app family creation requests.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if name or secret is missing
+//   - codes.AlreadyExists: if the family name or secret is already taken
+//   - codes.Internal: if the creation process fails
+func (s *server) CreateAppFamily(ctx context.Context, req *pb.CreateAppFamilyRequest) (*pb.CreateAppFamilyResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if req.GetSecret() == "" {
+		return nil, status.Error(codes.InvalidArgument, "secret is required")
+	}
+
+	familyID, err := s.admin.CreateAppFamily(ctx, req.GetName(), req.GetSecret())
+	if err != nil {
+		if errors.Is(err, admin.ErrAppFamilyExists) {
+			return nil, status.Error(codes.AlreadyExists, "app family already exists")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.CreateAppFamilyResponse{FamilyId: familyID}, nil
+}
+
+// GetAppFamily handles requests for an app family.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if family_id is missing
+//   - codes.NotFound: if no family exists with the ID
+//   - codes.Internal: if the family cannot be read
+func (s *server) GetAppFamily(ctx context.Context, req *pb.GetAppFamilyRequest) (*pb.GetAppFamilyResponse, error) {
+	if req.GetFamilyId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "family_id is required")
+	}
+
+	family, err := s.admin.GetAppFamily(ctx, req.GetFamilyId())
+	if err != nil {
+		if errors.Is(err, admin.ErrAppFamilyNotFound) {
+			return nil, status.Error(codes.NotFound, "app family not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.GetAppFamilyResponse{
+		Family: &pb.AppFamily{
+			Id:        family.ID,
+			Name:      family.Name,
+			AppIds:    family.AppIDs,
+			CreatedAt: timestampOrNil(family.CreatedAt),
+		},
+	}, nil
+}
+
+// DeleteAppFamily handles app family removal requests.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if family_id is missing
+//   - codes.NotFound: if no family exists with the ID
+//   - codes.FailedPrecondition: if apps still belong to the family
+//   - codes.Internal: if the removal process fails
+func (s *server) DeleteAppFamily(ctx context.Context, req *pb.DeleteAppFamilyRequest) (*pb.DeleteAppFamilyResponse, error) {
+	if req.GetFamilyId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "family_id is required")
+	}
+
+	if err := s.admin.DeleteAppFamily(ctx, req.GetFamilyId()); err != nil {
+		switch {
+		case errors.Is(err, admin.ErrAppFamilyNotFound):
+			return nil, status.Error(codes.NotFound, "app family not found")
+		case errors.Is(err, admin.ErrAppFamilyNotEmpty):
+			return nil, status.Error(codes.FailedPrecondition, "app family still has apps")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.DeleteAppFamilyResponse{}, nil
+}
+
+// InvalidatePasswordResetTokens handles requests to revoke all outstanding password reset tokens.
+//
+// Possible errors:
+//   - codes.Internal: if the tokens cannot be revoked
+func (s *server) InvalidatePasswordResetTokens(
+	ctx context.Context,
+	req *pb.InvalidatePasswordResetTokensRequest,
+) (*pb.InvalidatePasswordResetTokensResponse, error) {
+	invalidated, err := s.admin.InvalidatePasswordResetTokens(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.InvalidatePasswordResetTokensResponse{
+		Invalidated: invalidated,
+	}, nil
+}
+
+// RevokeAppTokens handles requests to revoke every token issued for an app.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.NotFound: if the app doesn't exist
+//   - codes.Internal: if the tokens cannot be revoked
+func (s *server) RevokeAppTokens(
+	ctx context.Context,
+	req *pb.RevokeAppTokensRequest,
+) (*pb.RevokeAppTokensResponse, error) {
+	if err := validateRevokeAppTokensRequest(req); err != nil {
+		return nil, err
+	}
+
+	app, purged, err := s.admin.RevokeAppTokens(ctx, req.GetAppId())
+	if err != nil {
+		if errors.Is(err, admin.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RevokeAppTokensResponse{
+		App:                        appToProto(app),
+		PurgedDeviceAuthorizations: purged,
+	}, nil
+}
+
+// RenderEmailTemplate handles email template preview requests.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails or the template cannot be rendered
+//   - codes.NotFound: if no template matches the name and locale
+func (s *server) RenderEmailTemplate(
+	ctx context.Context,
+	req *pb.RenderEmailTemplateRequest,
+) (*pb.RenderEmailTemplateResponse, error) {
+	if err := validateRenderEmailTemplateRequest(req); err != nil {
+		return nil, err
+	}
+
+	email, err := s.admin.RenderEmailTemplate(ctx, req.GetName(), req.GetAppId(), req.GetLocale(), req.GetData())
+	if err != nil {
+		if errors.Is(err, admin.ErrTemplateNotFound) {
+			return nil, status.Error(codes.NotFound, "template not found")
+		}
+
+		return nil, status.Errorf(codes.InvalidArgument, "failed to render template: %v", err)
+	}
+
+	return &pb.RenderEmailTemplateResponse{
+		Subject: email.Subject,
+		Body:    email.Body,
+	}, nil
+}
+
+// GetAppEmailDomains handles requests for an app's email domain restrictions.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.Internal: if the lists cannot be read
+func (s *server) GetAppEmailDomains(
+	ctx context.Context,
+	req *pb.GetAppEmailDomainsRequest,
+) (*pb.GetAppEmailDomainsResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	allowed, denied, err := s.admin.GetAppEmailDomains(ctx, req.GetAppId())
+	if err != nil {
+		if errors.Is(err, admin.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.GetAppEmailDomainsResponse{
+		AllowedDomains: allowed,
+		DeniedDomains:  denied,
+	}, nil
+}
+
+// SetAppEmailDomains handles requests replacing an app's email domain restrictions.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails or a domain is invalid
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.Internal: if the lists cannot be saved
+func (s *server) SetAppEmailDomains(
+	ctx context.Context,
+	req *pb.SetAppEmailDomainsRequest,
+) (*pb.SetAppEmailDomainsResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.admin.SetAppEmailDomains(ctx, req.GetAppId(), req.GetAllowedDomains(), req.GetDeniedDomains()); err != nil {
+		if errors.Is(err, admin.ErrInvalidDomain) {
+			return nil, status.Error(codes.InvalidArgument, "invalid email domain")
+		}
+
+		if errors.Is(err, admin.ErrDomainConflict) {
+			return nil, status.Error(codes.InvalidArgument, "email domain is both allowed and denied")
+		}
+
+		if errors.Is(err, admin.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SetAppEmailDomainsResponse{}, nil
+}
+
+// GetAppDisposableEmailPolicy handles requests for an app's disposable email policy.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.Internal: if the policy cannot be read
+func (s *server) GetAppDisposableEmailPolicy(
+	ctx context.Context,
+	req *pb.GetAppDisposableEmailPolicyRequest,
+) (*pb.GetAppDisposableEmailPolicyResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	policy, err := s.admin.GetAppDisposableEmailPolicy(ctx, req.GetAppId())
+	if err != nil {
+		if errors.Is(err, admin.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	for apiPolicy, p := range disposablePolicies {
+		if p == policy {
+			return &pb.GetAppDisposableEmailPolicyResponse{Policy: apiPolicy}, nil
+		}
+	}
+
+	return nil, status.Error(codes.Internal, "internal error")
+}
+
+// SetAppDisposableEmailPolicy handles requests setting an app's disposable email policy.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails or the policy is unknown
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.Internal: if the policy cannot be saved
+func (s *server) SetAppDisposableEmailPolicy(
+	ctx context.Context,
+	req *pb.SetAppDisposableEmailPolicyRequest,
+) (*pb.SetAppDisposableEmailPolicyResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	policy, ok := disposablePolicies[req.GetPolicy()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown policy")
+	}
+
+	if err := s.admin.SetAppDisposableEmailPolicy(ctx, req.GetAppId(), policy); err != nil {
+		if errors.Is(err, admin.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SetAppDisposableEmailPolicyResponse{}, nil
+}
+
+// GetAppLabels handles requests for an app's labels.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if app_id is missing
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.Internal: if the labels cannot be read
+func (s *server) GetAppLabels(ctx context.Context, req *pb.GetAppLabelsRequest) (*pb.GetAppLabelsResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	labels, err := s.admin.GetAppLabels(ctx, req.GetAppId())
+	if err != nil {
+		if errors.Is(err, admin.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.GetAppLabelsResponse{Labels: labels}, nil
+}
+
+// SetAppLabels handles requests replacing an app's labels.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if app_id is missing, a label is invalid, or there are too many
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.Internal: if the labels cannot be saved
+func (s *server) SetAppLabels(ctx context.Context, req *pb.SetAppLabelsRequest) (*pb.SetAppLabelsResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.admin.SetAppLabels(ctx, req.GetAppId(), req.GetLabels()); err != nil {
+		if labelErr := labelsError(err); labelErr != nil {
+			return nil, labelErr
+		}
+
+		if errors.Is(err, admin.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SetAppLabelsResponse{}, nil
+}
+
+// SetAppBackchannelLogoutUri handles requests setting the URI an app receives logout tokens at.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if app_id is missing or the URI is invalid
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.Internal: if the URI cannot be saved
+func (s *server) SetAppBackchannelLogoutUri(
+	ctx context.Context,
+	req *pb.SetAppBackchannelLogoutUriRequest,
+) (*pb.SetAppBackchannelLogoutUriResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.admin.SetAppBackchannelLogoutURI(ctx, req.GetAppId(), req.GetUri()); err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidLogoutURI):
+			return nil, status.Error(codes.InvalidArgument, "uri must be an absolute http or https uri without a fragment")
+		case errors.Is(err, admin.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SetAppBackchannelLogoutUriResponse{}, nil
+}
+
+// SetAppTokenTtl handles requests setting the lifetime of tokens issued for an app.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if app_id is missing or ttl_seconds is negative
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.Internal: if the lifetime cannot be saved
+func (s *server) SetAppTokenTtl(ctx context.Context, req *pb.SetAppTokenTtlRequest) (*pb.SetAppTokenTtlResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.admin.SetAppTokenTTL(ctx, req.GetAppId(), time.Duration(req.GetTtlSeconds())*time.Second); err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidTokenTTL):
+			return nil, status.Error(codes.InvalidArgument, "ttl_seconds must not be negative")
+		case errors.Is(err, admin.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SetAppTokenTtlResponse{}, nil
+}
+
+// SetAppTestOnly handles requests setting whether test tokens may be minted for an app.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if app_id is missing
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.Internal: if the flag cannot be saved
+func (s *server) SetAppTestOnly(ctx context.Context, req *pb.SetAppTestOnlyRequest) (*pb.SetAppTestOnlyResponse, error) {
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.admin.SetAppTestOnly(ctx, req.GetAppId(), req.GetTestOnly()); err != nil {
+		if errors.Is(err, admin.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SetAppTestOnlyResponse{}, nil
+}
+
+// GetStats handles requests for aggregate user counts.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.Internal: if the counts cannot be computed
+func (s *server) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
+	if err := validateGetStatsRequest(req); err != nil {
+		return nil, err
+	}
+
+	days := int(req.GetDays())
+	if days == emptyValue {
+		days = defaultStatsDays
+	}
+
+	stats, err := s.admin.GetStats(ctx, days)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &pb.GetStatsResponse{
+		TotalUsers:  stats.TotalUsers,
+		GeneratedAt: timestamppb.New(stats.GeneratedAt),
+	}
+
+	for _, day := range stats.Days {
+		resp.Days = append(resp.Days, &pb.DailyStats{
+			Date:          day.Date.Format(time.DateOnly),
+			Registrations: day.Registrations,
+			ActiveUsers:   day.ActiveUsers,
+		})
+	}
+
+	return resp, nil
+}
+
+// GetUsage handles requests for the usage of apps.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.NotFound: if no user exists with public_id
+//   - codes.Internal: if the usage cannot be read
+func (s *server) GetUsage(ctx context.Context, req *pb.GetUsageRequest) (*pb.GetUsageResponse, error) {
+	if req.GetDays() < 0 || req.GetDays() > maxStatsDays {
+		return nil, status.Errorf(codes.InvalidArgument, "days must be between 1 and %d", maxStatsDays)
+	}
+
+	days := int(req.GetDays())
+	if days == emptyValue {
+		days = defaultStatsDays
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	usage, truncated, err := s.admin.GetUsage(ctx, req.GetAppId(), userID, days, req.GetByUser(), maxUsageEntries)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &pb.GetUsageResponse{Truncated: truncated}
+
+	for _, u := range usage {
+		resp.Usage = append(resp.Usage, &pb.Usage{
+			Date:     u.Date.Format(time.DateOnly),
+			AppId:    u.AppID,
+			UserId:   u.UserID,
+			Requests: u.Requests,
+			Errors:   u.Errors,
+		})
+	}
+
+	return resp, nil
+}
+
+// VerifyAuditLog handles requests to verify the audit log.
+// A broken chain is reported in the response rather than as an error.
+//
+// Possible errors:
+//   - codes.Internal: if the audit log cannot be read
+func (s *server) VerifyAuditLog(ctx context.Context, req *pb.VerifyAuditLogRequest) (*pb.VerifyAuditLogResponse, error) {
+	result, err := s.admin.VerifyAuditLog(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.VerifyAuditLogResponse{
+		Valid:          result.Valid,
+		Events:         result.Events,
+		FirstInvalidId: result.FirstInvalidID,
+		HeadHash:       hex.EncodeToString(result.HeadHash),
+	}, nil
+}
+
+// GetDiagnostics handles requests for a sample of the runtime of this replica.
+func (s *server) GetDiagnostics(ctx context.Context, req *pb.GetDiagnosticsRequest) (*pb.GetDiagnosticsResponse, error) {
+	d := s.admin.GetDiagnostics()
+
+	return &pb.GetDiagnosticsResponse{
+		SampledAt:       timestamppb.New(d.SampledAt),
+		StartedAt:       timestamppb.New(d.StartedAt),
+		GoVersion:       d.GoVersion,
+		Gomaxprocs:      int32(d.GOMAXPROCS),
+		Goroutines:      int64(d.Goroutines),
+		HeapAllocBytes:  d.HeapAlloc,
+		HeapInuseBytes:  d.HeapInuse,
+		HeapObjects:     d.HeapObjects,
+		SysBytes:        d.Sys,
+		NumGc:           d.NumGC,
+		GcPauseTotalUs:  d.GCPauseTotal.Microseconds(),
+		LastGcAt:        timestampOrNil(d.LastGC),
+		LastGcPauseUs:   d.LastGCPause.Microseconds(),
+		StackDumps:      d.StackDumps,
+		LastStackDumpAt: timestampOrNil(d.LastDump),
+	}, nil
+}
+
+// ListJobs handles requests for the status of the background jobs of this replica.
+func (s *server) ListJobs(ctx context.Context, req *pb.ListJobsRequest) (*pb.ListJobsResponse, error) {
+	statuses := s.admin.ListJobs()
+
+	jobs := make([]*pb.Job, 0, len(statuses))
+	for i := range statuses {
+		jobs = append(jobs, jobToProto(&statuses[i]))
+	}
+
+	return &pb.ListJobsResponse{Jobs: jobs}, nil
+}
+
+// GetJobStatus handles requests for the status of a background job of this replica.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if name is missing
+//   - codes.NotFound: if no job has the name
+//   - codes.Internal: if the status cannot be read
+func (s *server) GetJobStatus(ctx context.Context, req *pb.GetJobStatusRequest) (*pb.GetJobStatusResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	job, err := s.admin.GetJobStatus(req.GetName())
+	if err != nil {
+		if errors.Is(err, admin.ErrJobNotFound) {
+			return nil, status.Error(codes.NotFound, "job not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.GetJobStatusResponse{Job: jobToProto(job)}, nil
+}
+
+// RunJobNow handles requests to run a background job of this replica immediately.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if name is missing
+//   - codes.NotFound: if no job has the name
+//   - codes.Internal: if the job cannot be run
+func (s *server) RunJobNow(ctx context.Context, req *pb.RunJobNowRequest) (*pb.RunJobNowResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	job, err := s.admin.RunJobNow(ctx, req.GetName())
+	if err != nil {
+		if errors.Is(err, admin.ErrJobNotFound) {
+			return nil, status.Error(codes.NotFound, "job not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RunJobNowResponse{Job: jobToProto(job)}, nil
+}
+
+// ListSigningKeys handles requests for the RSA keys RS256 tokens are signed with and verified against.
+func (s *server) ListSigningKeys(ctx context.Context, req *pb.ListSigningKeysRequest) (*pb.ListSigningKeysResponse, error) {
+	signingKeys := s.admin.ListSigningKeys()
+
+	keys := make([]*pb.SigningKey, 0, len(signingKeys))
+	for _, key := range signingKeys {
+		keys = append(keys, signingKeyToProto(key))
+	}
+
+	return &pb.ListSigningKeysResponse{Keys: keys}, nil
+}
+
+// RotateSigningKey handles requests to rotate the RSA key RS256 tokens are signed with.
+//
+// Possible errors:
+//   - codes.FailedPrecondition: if no RSA key is configured
+//   - codes.Aborted: if another rotation completed meanwhile
+//   - codes.Internal: if the key cannot be rotated
+func (s *server) RotateSigningKey(ctx context.Context, req *pb.RotateSigningKeyRequest) (*pb.RotateSigningKeyResponse, error) {
+	key, err := s.admin.RotateSigningKey(ctx)
+	if err != nil {
+		if errors.Is(err, admin.ErrNoSigningKey) {
+			return nil, status.Error(codes.FailedPrecondition, "no RSA signing key is configured")
+		}
+
+		if errors.Is(err, admin.ErrRotationConflict) {
+			return nil, status.Error(codes.Aborted, "signing key was rotated concurrently")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RotateSigningKeyResponse{Key: signingKeyToProto(key)}, nil
+}
+
+// SimulateLogin handles requests to evaluate a login under proposed app settings.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails or the token format is not supported
+//   - codes.NotFound: if the user or app does not exist
+//   - codes.Internal: if the login cannot be evaluated
+func (s *server) SimulateLogin(ctx context.Context, req *pb.SimulateLoginRequest) (*pb.SimulateLoginResponse, error) {
+	if err := validateSimulateLoginRequest(req); err != nil {
+		return nil, err
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	// Unset settings keep the current ones, like unset fields of the settings do.
+	settings := req.GetSettings()
+	if settings == nil {
+		settings = &pb.AppLoginSettings{}
+	}
+
+	simulation, err := s.admin.SimulateLogin(
+		ctx,
+		userID,
+		req.GetAppId(),
+		tokenFormats[settings.GetTokenFormat()],
+		settings.MinimalClaims,
+		settings.RequireMembership,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidTokenFormat):
+			return nil, status.Error(codes.InvalidArgument, "token_format is not supported by the server")
+		case errors.Is(err, admin.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		case errors.Is(err, admin.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SimulateLoginResponse{
+		Allowed:              simulation.Allowed,
+		DenialReason:         simulation.DenialReason,
+		PushApprovalRequired: simulation.PushApprovalRequired,
+		Claims:               string(simulation.Claims),
+	}, nil
+}
+
+// registrationOutcomes maps the outcomes of simulated registrations to the API outcomes.
+var registrationOutcomes = map[string]pb.RegistrationOutcome{
+	models.RegistrationAccepted: pb.RegistrationOutcome_REGISTRATION_OUTCOME_ACCEPTED,
+	models.RegistrationFlagged:  pb.RegistrationOutcome_REGISTRATION_OUTCOME_FLAGGED,
+	models.RegistrationRejected: pb.RegistrationOutcome_REGISTRATION_OUTCOME_REJECTED,
+}
+
+// SimulatePolicy handles requests to evaluate a registration under a proposed registration policy.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails or a proposed domain or policy is invalid
+//   - codes.NotFound: if no app exists with the ID
+//   - codes.Internal: if the policy cannot be evaluated
+func (s *server) SimulatePolicy(ctx context.Context, req *pb.SimulatePolicyRequest) (*pb.SimulatePolicyResponse, error) {
+	if req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if req.GetAppId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	var domains *admin.EmailDomains
+	if req.EmailDomains != nil {
+		domains = &admin.EmailDomains{
+			Allowed: req.GetEmailDomains().GetAllowedDomains(),
+			Denied:  req.GetEmailDomains().GetDeniedDomains(),
+		}
+	}
+
+	var policy *disposable.Policy
+	if req.DisposableEmailPolicy != nil {
+		p, ok := disposablePolicies[req.GetDisposableEmailPolicy()]
+		if !ok {
+			return nil, status.Error(codes.InvalidArgument, "unknown disposable_email_policy")
+		}
+
+		policy = &p
+	}
+
+	simulation, err := s.admin.SimulatePolicy(ctx, req.GetEmail(), req.GetAppId(), domains, policy)
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrInvalidDomain):
+			return nil, status.Error(codes.InvalidArgument, "invalid email domain")
+		case errors.Is(err, admin.ErrDomainConflict):
+			return nil, status.Error(codes.InvalidArgument, "email domain is both allowed and denied")
+		case errors.Is(err, admin.ErrInvalidPolicy):
+			return nil, status.Error(codes.InvalidArgument, "unknown disposable_email_policy")
+		case errors.Is(err, admin.ErrAppNotFound):
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SimulatePolicyResponse{
+		Outcome: registrationOutcomes[simulation.Outcome],
+		Reason:  simulation.Reason,
+		Policy:  simulation.Policy,
+	}, nil
+}
+
+// GetUserAtTime handles requests for the state of a user at a point in time.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if user_id or at is missing or invalid
+//   - codes.NotFound: if no user exists with the ID, or no state of the user was recorded at the time
+//   - codes.Internal: if the history cannot be read
+func (s *server) GetUserAtTime(ctx context.Context, req *pb.GetUserAtTimeRequest) (*pb.GetUserAtTimeResponse, error) {
+	if err := validateGetUserAtTimeRequest(req); err != nil {
+		return nil, err
+	}
+
+	userID, err := s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.admin.GetUserAtTime(ctx, userID, req.GetAt().AsTime())
+	if err != nil {
+		switch {
+		case errors.Is(err, admin.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		case errors.Is(err, admin.ErrUserHistoryNotFound):
+			return nil, status.Error(codes.NotFound, "no history of the user at that time")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.GetUserAtTimeResponse{
+		UserId:     snapshot.UserID,
+		At:         timestamppb.New(snapshot.At),
+		IsAdmin:    snapshot.IsAdmin,
+		FrozenAt:   timestampOrNil(snapshot.FrozenAt),
+		MergedInto: snapshot.MergedInto,
+		PushMfa:    snapshot.PushMFA,
+		MemberOf:   snapshot.MemberOf,
+		OwnerOf:    snapshot.OwnerOf,
+		Attributes: snapshot.Attributes,
+		Labels:     snapshot.Labels,
+	}, nil
+}
+
+// IssueSupportToken handles requests for a support token.
+// The issuer is identified by the token verified by the Authorization interceptor.
+//
+// Possible errors:
+//   - codes.Unauthenticated: if the caller has no valid token
+//   - codes.InvalidArgument: if request validation fails or the TTL exceeds the maximum
+//   - codes.NotFound: if the user or app does not exist
+//   - codes.Internal: if the token cannot be issued
+func (s *server) IssueSupportToken(ctx context.Context, req *pb.IssueSupportTokenRequest) (*pb.IssueSupportTokenResponse, error) {
+	if err := validateIssueSupportTokenRequest(req); err != nil {
+		return nil, err
+	}
+
+	claims, ok := interceptors.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	var userID int64
+	if req.GetAppId() == emptyValue {
+		var err error
+
+		userID, err = s.resolveUserID(ctx, req.GetUserId(), req.GetPublicId())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ttl := time.Duration(req.GetTtlSeconds()) * time.Second
+
+	token, supportToken, err := s.admin.IssueSupportToken(ctx, claims.UserID, userID, req.GetAppId(), ttl, req.GetReason())
+	if err != nil {
+		if errors.Is(err, admin.ErrInvalidSupportTokenTTL) {
+			return nil, status.Error(codes.InvalidArgument, "ttl_seconds exceeds the maximum support token lifetime")
+		}
+
+		if errors.Is(err, admin.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		if errors.Is(err, admin.ErrAppNotFound) {
+			return nil, status.Error(codes.NotFound, "app not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.IssueSupportTokenResponse{
+		Token:          token,
+		SupportTokenId: supportToken.ID,
+		ExpiresAt:      timestamppb.New(supportToken.ExpiresAt),
+	}, nil
+}
+
+// RevokeSupportToken handles support token revocation requests.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if support_token_id is missing
+//   - codes.NotFound: if the token does not exist
+//   - codes.Internal: if the token cannot be revoked
+func (s *server) RevokeSupportToken(ctx context.Context, req *pb.RevokeSupportTokenRequest) (*pb.RevokeSupportTokenResponse, error) {
+	if req.GetSupportTokenId() == emptyValue {
+		return nil, status.Error(codes.InvalidArgument, "support_token_id is required")
+	}
+
+	if err := s.admin.RevokeSupportToken(ctx, req.GetSupportTokenId()); err != nil {
+		if errors.Is(err, admin.ErrSupportTokenNotFound) {
+			return nil, status.Error(codes.NotFound, "support token not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RevokeSupportTokenResponse{}, nil
+}
+
+// SetAnnouncement handles requests to set or clear the announcement attached to every response.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if the message is too long or not printable ASCII, or expires_at has passed
+//   - codes.Internal: if the announcement cannot be stored or propagated
+func (s *server) SetAnnouncement(ctx context.Context, req *pb.SetAnnouncementRequest) (*pb.SetAnnouncementResponse, error) {
+	if err := validateSetAnnouncementRequest(req); err != nil {
+		return nil, err
+	}
+
+	var expiresAt time.Time
+	if req.GetExpiresAt() != nil {
+		expiresAt = req.GetExpiresAt().AsTime()
+	}
+
+	if err := s.admin.SetAnnouncement(ctx, req.GetMessage(), expiresAt); err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SetAnnouncementResponse{}, nil
+}
+
+// ReloadApps handles requests to drop cached results derived from apps on every replica.
+//
+// Possible errors:
+//   - codes.Internal: if the reload cannot be propagated to the replicas
+func (s *server) ReloadApps(ctx context.Context, req *pb.ReloadAppsRequest) (*pb.ReloadAppsResponse, error) {
+	if err := s.admin.ReloadApps(ctx); err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.ReloadAppsResponse{}, nil
+}
+
+// validateCreateAppRequest validates the app creation request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateCreateAppRequest(req *pb.CreateAppRequest) error {
+	if req.GetName() == "" {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if req.GetSecret() == "" {
+		return status.Error(codes.InvalidArgument, "secret is required")
+	}
+
+	if _, ok := tokenFormats[req.GetTokenFormat()]; !ok {
+		return status.Error(codes.InvalidArgument, "unknown token_format")
+	}
+
+	return nil
+}
+
+// validateDeleteAppRequest validates the app removal request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateDeleteAppRequest(req *pb.DeleteAppRequest) error {
+	if req.GetAppId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	return nil
+}
+
+// validateRevokeAppTokensRequest validates the app token revocation request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateRevokeAppTokensRequest(req *pb.RevokeAppTokensRequest) error {
+	if req.GetAppId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	return nil
+}
+
+// validateRenderEmailTemplateRequest validates the template preview request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateRenderEmailTemplateRequest(req *pb.RenderEmailTemplateRequest) error {
+	if req.GetName() == "" {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	return nil
+}
+
+// validateGetStatsRequest validates the stats request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateGetStatsRequest(req *pb.GetStatsRequest) error {
+	if req.GetDays() < 0 || req.GetDays() > maxStatsDays {
+		return status.Errorf(codes.InvalidArgument, "days must be between 1 and %d", maxStatsDays)
+	}
+
+	return nil
+}
+
+// validateFindUsersByAttributeRequest validates the attribute search request parameters.
+// Returns the ID after which the requested page starts, or a gRPC error if the request is invalid.
+func validateFindUsersByAttributeRequest(req *pb.FindUsersByAttributeRequest) (int64, error) {
+	if req.GetKey() == "" {
+		return 0, status.Error(codes.InvalidArgument, "key is required")
+	}
+
+	if req.GetPageSize() < 0 || req.GetPageSize() > maxFindUsersPageSize {
+		return 0, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", maxFindUsersPageSize)
+	}
+
+	if req.GetPageToken() == "" {
+		return 0, nil
+	}
+
+	afterID, err := strconv.ParseInt(req.GetPageToken(), 10, 64)
+	if err != nil || afterID <= 0 {
+		return 0, status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+
+	return afterID, nil
+}
+
+// validateSearchUsersRequest validates the user search request parameters.
+// Returns a gRPC error if the request is invalid.
+func validateSearchUsersRequest(req *pb.SearchUsersRequest) error {
+	length := utf8.RuneCountInString(req.GetQuery())
+
+	if length == emptyValue {
+		return status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	if length > maxSearchQueryLength {
+		return status.Errorf(codes.InvalidArgument, "query must be at most %d characters", maxSearchQueryLength)
+	}
+
+	if _, ok := pb.SearchMode_name[int32(req.GetMode())]; !ok {
+		return status.Error(codes.InvalidArgument, "invalid mode")
+	}
+
+	if req.GetMode() == pb.SearchMode_SEARCH_MODE_FUZZY && length < minFuzzySearchQueryLength {
+		return status.Errorf(codes.InvalidArgument, "fuzzy queries must be at least %d characters", minFuzzySearchQueryLength)
+	}
+
+	if req.GetPageSize() < 0 || req.GetPageSize() > maxSearchUsersPageSize {
+		return status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", maxSearchUsersPageSize)
+	}
+
+	return nil
+}
+
+// validateListAppsRequest validates the app listing request parameters.
+// Returns the ID after which apps are listed, or a gRPC error if the request is invalid.
+func validateListAppsRequest(req *pb.ListAppsRequest) (int32, error) {
+	if req.GetPageSize() < 0 || req.GetPageSize() > maxListAppsPageSize {
+		return 0, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", maxListAppsPageSize)
+	}
+
+	if req.GetPageToken() == "" {
+		return 0, nil
+	}
+
+	afterID, err := strconv.ParseInt(req.GetPageToken(), 10, 32)
+	if err != nil || afterID <= 0 {
+		return 0, status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+
+	return int32(afterID), nil
+}
+
+// labelsError maps the label validation errors of the admin service to a gRPC error.
+// Returns nil for other errors.
+func labelsError(err error) error {
+	switch {
+	case errors.Is(err, admin.ErrInvalidLabel):
+		return status.Error(codes.InvalidArgument, "invalid label key or value")
+	case errors.Is(err, admin.ErrTooManyLabels):
+		return status.Error(codes.InvalidArgument, "too many labels")
+	}
+
+	return nil
+}
+
+// resolveUserID returns the user ID addressed by a request, preferring publicID when set.
+// Returns a gRPC error if the public ID cannot be resolved.
+func (s *server) resolveUserID(ctx context.Context, userID int64, publicID string) (int64, error) {
+	if publicID == "" {
+		return userID, nil
+	}
+
+	userID, err := s.admin.ResolveUserID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, admin.ErrUserNotFound) {
+			return 0, status.Error(codes.NotFound, "user not found")
+		}
+
+		return 0, status.Error(codes.Internal, "internal error")
+	}
+
+	return userID, nil
+}
+
+// validateSimulateLoginRequest validates the login simulation request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateSimulateLoginRequest(req *pb.SimulateLoginRequest) error {
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if req.GetAppId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if _, ok := tokenFormats[req.GetSettings().GetTokenFormat()]; !ok {
+		return status.Error(codes.InvalidArgument, "unknown token_format")
+	}
+
+	return nil
+}
+
+// validateGetUserAtTimeRequest validates the point-in-time user request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateGetUserAtTimeRequest(req *pb.GetUserAtTimeRequest) error {
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if req.At == nil {
+		return status.Error(codes.InvalidArgument, "at is required")
+	}
+
+	if err := req.GetAt().CheckValid(); err != nil {
+		return status.Error(codes.InvalidArgument, "invalid at")
+	}
+
+	return nil
+}
+
+// validateIssueSupportTokenRequest validates the support token request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateIssueSupportTokenRequest(req *pb.IssueSupportTokenRequest) error {
+	hasUser := req.GetUserId() != emptyValue || req.GetPublicId() != ""
+	hasApp := req.GetAppId() != emptyValue
+
+	if hasUser == hasApp {
+		return status.Error(codes.InvalidArgument, "exactly one of user_id, public_id, or app_id is required")
+	}
+
+	if req.GetTtlSeconds() < 0 {
+		return status.Error(codes.InvalidArgument, "ttl_seconds must not be negative")
+	}
+
+	if req.GetReason() == "" {
+		return status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	return nil
+}
+
+// validateSetAnnouncementRequest validates the announcement request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateSetAnnouncementRequest(req *pb.SetAnnouncementRequest) error {
+	message := req.GetMessage()
+
+	if len(message) > maxAnnouncementLength {
+		return status.Errorf(codes.InvalidArgument, "message must not exceed %d characters", maxAnnouncementLength)
+	}
+
+	// Metadata values must be printable ASCII, and the announcement is sent as metadata.
+	for _, c := range []byte(message) {
+		if c < ' ' || c > '~' {
+			return status.Error(codes.InvalidArgument, "message must be printable ASCII")
+		}
+	}
+
+	if req.ExpiresAt != nil {
+		if err := req.GetExpiresAt().CheckValid(); err != nil {
+			return status.Error(codes.InvalidArgument, "invalid expires_at")
+		}
+
+		if message != "" && !req.GetExpiresAt().AsTime().After(time.Now()) {
+			return status.Error(codes.InvalidArgument, "expires_at must be in the future")
+		}
+	}
+
+	return nil
+}
+
+// validateUpdateAppRequest validates the app update request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateUpdateAppRequest(req *pb.UpdateAppRequest) error {
+	if req.GetAppId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if req.GetVersion() == emptyValue {
+		return status.Error(codes.InvalidArgument, "version is required")
+	}
+
+	if _, ok := tokenFormats[req.GetTokenFormat()]; !ok {
+		return status.Error(codes.InvalidArgument, "unknown token_format")
+	}
+
+	return nil
+}
+
+// validateUpdateUserRequest validates the user update request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateUpdateUserRequest(req *pb.UpdateUserRequest) error {
+	if req.GetUserId() == emptyValue && req.GetPublicId() == "" {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if req.GetVersion() == emptyValue {
+		return status.Error(codes.InvalidArgument, "version is required")
+	}
+
+	if req.IsAdmin == nil {
+		return status.Error(codes.InvalidArgument, "is_admin is required")
+	}
+
+	return nil
+}
+
+// appToProto converts an application to its API representation. The secret is never returned.
+func appToProto(app *models.App) *pb.App {
+	resp := &pb.App{
+		Id:                   int32(app.ID),
+		Name:                 app.Name,
+		CreatedAt:            timestampOrNil(app.CreatedAt),
+		UpdatedAt:            timestampOrNil(app.UpdatedAt),
+		Version:              app.Version,
+		MinimalClaims:        app.MinimalClaims,
+		RequireMembership:    app.RequireMembership,
+		FamilyId:             app.FamilyID,
+		TokensRevokedAt:      timestampOrNil(app.TokensRevokedAt),
+		BackchannelLogoutUri: app.BackchannelLogoutURI,
+		TokenTtlSeconds:      int32(app.TokenTTL / time.Second),
+		TestOnly:             app.TestOnly,
+	}
+
+	for api, format := range tokenFormats {
+		if format != "" && format == app.TokenFormat {
+			resp.TokenFormat = api
+		}
+	}
+
+	return resp
+}
+
+// userToProto converts a user to its API representation.
+func userToProto(user *models.User) *pb.User {
+	return &pb.User{
+		Id:              user.ID,
+		PublicId:        user.PublicID,
+		Email:           user.Email,
+		IsAdmin:         user.IsAdmin,
+		CreatedAt:       timestampOrNil(user.CreatedAt),
+		UpdatedAt:       timestampOrNil(user.UpdatedAt),
+		Version:         user.Version,
+		FrozenAt:        timestampOrNil(user.FrozenAt),
+		MergedInto:      user.MergedInto,
+		PushMfa:         user.PushMFA,
+		TokensRevokedAt: timestampOrNil(user.TokensRevokedAt),
+	}
+}
+
+// jobToProto converts the status of a background job to its API representation.
+func jobToProto(job *models.JobStatus) *pb.Job {
+	return &pb.Job{
+		Name:            job.Name,
+		Description:     job.Description,
+		IntervalMs:      job.Interval.Milliseconds(),
+		Running:         job.Running,
+		Runs:            job.Runs,
+		Failures:        job.Failures,
+		LastStartedAt:   timestampOrNil(job.LastStartedAt),
+		LastDurationMs:  job.LastDuration.Milliseconds(),
+		LastError:       job.LastError,
+		LastSucceededAt: timestampOrNil(job.LastSucceededAt),
+		NextRunAt:       timestampOrNil(job.NextRunAt),
+	}
+}
+
+// signingKeyToProto converts a signing key to its API representation.
+func signingKeyToProto(key *models.SigningKey) *pb.SigningKey {
+	return &pb.SigningKey{
+		Kid:       key.ID,
+		Active:    key.Active(),
+		CreatedAt: timestampOrNil(key.CreatedAt),
+		RetiredAt: timestampOrNil(key.RetiredAt),
+		ExpiresAt: timestampOrNil(key.ExpiresAt),
+	}
+}
+
+// timestampOrNil converts t to a protobuf timestamp, leaving unknown (zero) times unset.
+func timestampOrNil(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
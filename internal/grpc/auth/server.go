@@ -5,28 +5,134 @@ package auth
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
 
 	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/grpc/interceptors"
+	"github.com/kirinyoku/sso-grpc/internal/lib/authzpolicy"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
 	"github.com/kirinyoku/sso-grpc/internal/services/auth"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Auth defines the interface that must be implemented by the authentication service.
 type Auth interface {
 	// Register creates a new user account with the provided credentials.
-	Register(ctx context.Context, email, password string) (userID int64, err error)
+	// appID is 0 when the client does not register through an app.
+	// client describes where the registration comes from, for the daily quota of its address.
+	Register(ctx context.Context, email, password string, appID int32, client auth.ClientInfo) (userID int64, publicID string, err error)
+	// RegisterAsync admits a registration and creates the account in the background.
+	RegisterAsync(ctx context.Context, email, password string, appID int32, client auth.ClientInfo) (registrationID string, err error)
+	// RegistrationQuota reports the registrations left today to the address of client,
+	// or false if the address is not limited.
+	RegistrationQuota(client auth.ClientInfo) (state auth.QuotaState, ok bool)
+	// GetRegistrationStatus reports the progress of an async registration.
+	GetRegistrationStatus(ctx context.Context, registrationID string) (*models.Registration, error)
+	// ResolveUserID returns the ID of the user with the given public ID.
+	ResolveUserID(ctx context.Context, publicID string) (userID int64, err error)
 	// Login authenticates a user and returns an authentication token.
-	Login(ctx context.Context, email, password string, appID int32) (token string, err error)
+	// client describes where the user logs in from, for the login notification.
+	Login(ctx context.Context, email, password string, appID int32, client auth.ClientInfo) (token string, err error)
 	// IsAdmin checks if the specified user has administrative privileges.
 	IsAdmin(ctx context.Context, userID int64) (isAdmin bool, err error)
+	// RequestPasswordReset emails a password reset token to the user.
+	RequestPasswordReset(ctx context.Context, email string, appID int32, locale string) error
+	// ResetPassword sets a new password using a password reset token.
+	ResetPassword(ctx context.Context, token string, newPassword string) error
+	// StartDeviceAuthorization starts the device authorization grant for an app.
+	StartDeviceAuthorization(ctx context.Context, appID int32) (*auth.DeviceAuthorization, error)
+	// ApproveDeviceAuthorization records a user's approval or denial of a device.
+	ApproveDeviceAuthorization(ctx context.Context, userCode string, userID int64, approve bool) (appID int32, err error)
+	// PollDeviceToken exchanges a device code for a token once the device is approved.
+	PollDeviceToken(ctx context.Context, deviceCode string) (token string, err error)
+	// RefreshToken exchanges a valid or recently expired token for a new one.
+	RefreshToken(ctx context.Context, token string) (newToken string, err error)
+	// IssueRefreshToken issues a refresh token alongside a token just issued to a user's client,
+	// or returns an empty string if refresh tokens are disabled.
+	IssueRefreshToken(ctx context.Context, token string, client auth.ClientInfo) (refreshToken string, err error)
+	// ExchangeRefreshToken exchanges a refresh token for a new token and the next refresh token.
+	ExchangeRefreshToken(ctx context.Context, refreshToken string) (token string, nextRefreshToken string, err error)
+	// RevokeRefreshToken revokes a refresh token and every other one issued since the same login.
+	RevokeRefreshToken(ctx context.Context, refreshToken string) error
+	// ReportLogin freezes the account of the user a login report link was sent to.
+	ReportLogin(ctx context.Context, token string) error
+	// ReportLeakedSecret rotates an app secret reported as leaked and revokes the app's tokens.
+	ReportLeakedSecret(ctx context.Context, secret string, source string) error
+	// RequestUnfreeze emails an unfreeze token to the owner of a frozen account.
+	RequestUnfreeze(ctx context.Context, email string, appID int32, locale string) error
+	// Unfreeze unfreezes an account using an unfreeze token and sets a new password.
+	Unfreeze(ctx context.Context, token string, newPassword string) error
+	// GetUserInfo returns the profile of a signed-in user.
+	GetUserInfo(ctx context.Context, userID int64) (*models.User, error)
+	// AvatarURL returns the URL of a user's avatar, or an empty string if the user has none.
+	AvatarURL(user *models.User) string
+	// SetAvatar stores an image as the avatar of a signed-in user and returns its URL.
+	SetAvatar(ctx context.Context, userID int64, image []byte) (avatarURL string, err error)
+	// MaxAvatarSize returns the largest avatar accepted by SetAvatar, in bytes.
+	MaxAvatarSize() int
+	// WhoAmI resolves a token to the identity and roles of its user.
+	WhoAmI(ctx context.Context, token string) (*auth.Identity, error)
+	// JWKS returns the public keys RS256 tokens are verified against.
+	JWKS() []jwt.JWK
+	// Introspect reports whether an access or refresh token is active and what it was issued for.
+	Introspect(ctx context.Context, token, tokenTypeHint string) (*auth.Introspection, error)
+	// SetPushMFA turns push approval of logins on or off for a user.
+	SetPushMFA(ctx context.Context, userID int64, enabled bool) error
+	// ListLoginChallenges returns the pending login challenges of a user.
+	ListLoginChallenges(ctx context.Context, userID int64) ([]models.LoginChallenge, error)
+	// DecideLoginChallenge records a user's approval or denial of one of their login challenges.
+	DecideLoginChallenge(ctx context.Context, id int64, userID int64, approve bool) error
+	// PollLoginChallenge exchanges a login challenge for a token once the user approved it.
+	PollLoginChallenge(ctx context.Context, challengeID string) (token string, err error)
+	// RequireStepUp checks that the user of a token approved a second factor recently enough for an action.
+	RequireStepUp(ctx context.Context, token string, action string, client auth.ClientInfo) (jwt.Authentication, error)
+	// Logout revokes every token issued to a user so far.
+	Logout(ctx context.Context, userID int64) error
+	// ListSessions returns the active sessions of a user.
+	ListSessions(ctx context.Context, userID int64) ([]models.Session, error)
+	// RevokeSession revokes the refresh tokens of one of a user's active sessions.
+	RevokeSession(ctx context.Context, id int64, userID int64) error
+}
+
+// Announcements defines the interface used to read the announcement reported by GetServiceStatus.
+type Announcements interface {
+	// Current returns the announcement shown now, or nil if there is none.
+	Current() *models.Announcement
+}
+
+// MethodLevels defines the interface used to look up the methods requiring each auth level.
+type MethodLevels interface {
+	// Level returns the level a method requires.
+	Level(method string) authzpolicy.Level
+	// Methods returns the methods requiring a level.
+	Methods(level authzpolicy.Level) []string
+}
+
+// Policy describes the methods protected by the Authorization interceptor, so that WhoAmI
+// can report which of them a token may call.
+type Policy struct {
+	Levels       MethodLevels // levels methods require, as enforced by the interceptor
+	OwnerMethods []string     // admin methods owners of the addressed app may call as well
+	AdminAppID   int32        // app whose tokens admin methods accept without the service's signature
 }
 
 // server implements the gRPC Auth service.
 type server struct {
-	pb.UnimplementedAuthServer      // Embed the unimplemented server for forward compatibility
-	auth                       Auth // Authentication service implementation
+	pb.UnimplementedAuthServer               // Embed the unimplemented server for forward compatibility
+	auth                       Auth          // Authentication service implementation
+	announcements              Announcements // Announcement reported by GetServiceStatus
+	policy                     Policy        // Methods protected by the Authorization interceptor
+	retryAfter                 time.Duration // Delay suggested in RetryInfo details when the service is busy or unavailable
 }
 
 // Register registers the authentication service implementation with the gRPC server.
@@ -34,57 +140,189 @@ type server struct {
 // Parameters:
 //   - s: The gRPC server instance
 //   - auth: Implementation of the Auth interface
-func Register(s *grpc.Server, auth Auth) {
-	pb.RegisterAuthServer(s, &server{auth: auth})
+//   - announcements: Announcement reported by GetServiceStatus
+//   - policy: Methods protected by the Authorization interceptor, reported by WhoAmI
+//   - retryAfter: Delay suggested in RetryInfo details when the service is busy or unavailable
+func Register(s grpc.ServiceRegistrar, auth Auth, announcements Announcements, policy Policy, retryAfter time.Duration) {
+	pb.RegisterAuthServer(s, &server{auth: auth, announcements: announcements, policy: policy, retryAfter: retryAfter})
 }
 
 const (
 	// emptyValue represents the zero value for numeric fields in protobuf messages
 	emptyValue = 0
+	// cacheControlHeader is the response metadata key telling clients how long a response may be cached
+	cacheControlHeader = "cache-control"
+	// userInfoMaxAge is the longest time GetUserInfo responses may be cached
+	userInfoMaxAge = 5 * time.Minute
+	// errorDomain is the domain of the ErrorInfo details attached to errors
+	errorDomain = "sso"
+	// registrationQuotaReason is the ErrorInfo reason telling a spent registration quota from server overload,
+	// which also fails with codes.ResourceExhausted
+	registrationQuotaReason = "REGISTRATION_QUOTA_EXCEEDED"
+	// rateLimitLimitTrailer is the trailer reporting the number of calls a client may make per period
+	rateLimitLimitTrailer = "ratelimit-limit"
+	// rateLimitRemainingTrailer is the trailer reporting the number of calls left to a client in the period
+	rateLimitRemainingTrailer = "ratelimit-remaining"
+	// rateLimitResetTrailer is the trailer reporting the seconds until the period ends and the limit is restored
+	rateLimitResetTrailer = "ratelimit-reset"
 )
 
 // Register handles user registration requests.
 //
 // It validates the request and delegates to the underlying Auth service.
-// Returns a user ID on success or an appropriate gRPC error on failure.
+// Returns a user ID on success, or a registration ID for async registrations,
+// or an appropriate gRPC error on failure.
+//
+// When the client's address is subject to the daily registration quota, the
+// ratelimit-limit, ratelimit-remaining, and ratelimit-reset trailers report what is
+// left of it, whether the call succeeds or not, so clients can slow down in time.
 //
 // Possible errors:
 //   - codes.InvalidArgument: if request validation fails
+//   - codes.PermissionDenied: if the email domain is not allowed or a registration hook rejected the request
+//   - codes.ResourceExhausted: if the client's address used up its registrations for the day,
+//     with an ErrorInfo detail of reason REGISTRATION_QUOTA_EXCEEDED and a RetryInfo detail
+//     of the time until the quota is restored, or the async registration queue is full, with
+//     a RetryInfo detail
+//   - codes.FailedPrecondition: if async registration is requested but disabled
 //   - codes.Internal: if the registration process fails
 func (s *server) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
 	if err := validateRegisterRequest(req); err != nil {
 		return nil, err
 	}
 
-	userID, err := s.auth.Register(ctx, req.GetEmail(), req.GetPassword())
+	client := clientInfo(ctx)
+	defer s.setRegistrationQuotaTrailer(ctx, client)
+
+	if req.GetAsync() {
+		registrationID, err := s.auth.RegisterAsync(ctx, req.GetEmail(), req.GetPassword(), req.GetAppId(), client)
+		if err != nil {
+			if errors.Is(err, auth.ErrAsyncRegistrationDisabled) {
+				return nil, status.Error(codes.FailedPrecondition, "async registration is disabled")
+			}
+
+			if errors.Is(err, auth.ErrRegistrationQueueFull) {
+				return nil, interceptors.RetryStatus(codes.ResourceExhausted, "too many registrations in progress", s.retryAfter).Err()
+			}
+
+			return nil, s.registerError(err, client)
+		}
+
+		return &pb.RegisterResponse{RegistrationId: registrationID}, nil
+	}
+
+	userID, publicID, err := s.auth.Register(ctx, req.GetEmail(), req.GetPassword(), req.GetAppId(), client)
+	if err != nil {
+		return nil, s.registerError(err, client)
+	}
+
+	return &pb.RegisterResponse{
+		UserId:   userID,
+		PublicId: publicID,
+	}, nil
+}
+
+// GetRegistrationStatus handles status requests for async registrations.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.NotFound: if the registration ID is unknown or its status has expired
+//   - codes.Internal: if the status cannot be read
+func (s *server) GetRegistrationStatus(
+	ctx context.Context,
+	req *pb.GetRegistrationStatusRequest,
+) (*pb.GetRegistrationStatusResponse, error) {
+	if err := validateGetRegistrationStatusRequest(req); err != nil {
+		return nil, err
+	}
+
+	reg, err := s.auth.GetRegistrationStatus(ctx, req.GetRegistrationId())
 	if err != nil {
-		if errors.Is(err, auth.ErrUserExists) {
-			return nil, status.Error(codes.AlreadyExists, "user already exists")
+		if errors.Is(err, auth.ErrRegistrationNotFound) {
+			return nil, status.Error(codes.NotFound, "registration not found")
 		}
 
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
-	return &pb.RegisterResponse{
-		UserId: userID,
+	return &pb.GetRegistrationStatusResponse{
+		Status:        registrationStatuses[reg.Status],
+		UserId:        reg.UserID,
+		PublicId:      reg.PublicID,
+		FailureReason: reg.FailureReason,
 	}, nil
 }
 
+// setRegistrationQuotaTrailer reports the registration quota left to client in the trailers
+// of the call, if its address is subject to the quota.
+func (s *server) setRegistrationQuotaTrailer(ctx context.Context, client auth.ClientInfo) {
+	state, ok := s.auth.RegistrationQuota(client)
+	if !ok {
+		return
+	}
+
+	reset := max(time.Until(state.Reset), 0)
+
+	// Setting trailers only fails outside of a gRPC call, which never happens in a handler.
+	_ = grpc.SetTrailer(ctx, metadata.Pairs(
+		rateLimitLimitTrailer, strconv.Itoa(state.Limit),
+		rateLimitRemainingTrailer, strconv.Itoa(state.Remaining),
+		rateLimitResetTrailer, strconv.Itoa(int(reset.Seconds())),
+	))
+}
+
+// registerError maps the errors of synchronous and async registration from client to gRPC errors.
+func (s *server) registerError(err error, client auth.ClientInfo) error {
+	if errors.Is(err, auth.ErrUserExists) {
+		return status.Error(codes.AlreadyExists, "user already exists")
+	}
+
+	if errors.Is(err, auth.ErrInvalidAppID) {
+		return status.Error(codes.InvalidArgument, "invalid app ID")
+	}
+
+	var rejectErr *auth.RejectError
+	if errors.As(err, &rejectErr) {
+		return status.Error(codes.PermissionDenied, rejectErr.Reason)
+	}
+
+	if errors.Is(err, auth.ErrRegistrationQuotaExceeded) {
+		// Retrying pays off only once the quota is restored at the end of the day.
+		var reset time.Duration
+		if state, ok := s.auth.RegistrationQuota(client); ok {
+			reset = max(time.Until(state.Reset), 0)
+		}
+
+		exhausted := interceptors.RetryStatus(codes.ResourceExhausted, "too many registrations from this address today", reset)
+		if withInfo, err := exhausted.WithDetails(&errdetails.ErrorInfo{Reason: registrationQuotaReason, Domain: errorDomain}); err == nil {
+			exhausted = withInfo
+		}
+
+		return exhausted.Err()
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
+
 // Login handles user authentication requests.
 //
 // It validates the request, authenticates the user, and returns an authentication token.
-// Returns a JWT token on success or an appropriate gRPC error on failure.
+// Returns a JWT token on success or an appropriate gRPC error on failure. For users with
+// push MFA, a challenge to poll PollLoginChallenge with is returned instead of the token.
 //
 // Possible errors:
 //   - codes.InvalidArgument: if request validation fails
 //   - codes.Unauthenticated: if authentication fails
+//   - codes.FailedPrecondition: if the account is frozen and must be unfrozen first
+//   - codes.PermissionDenied: if a login hook rejected the request, or the app requires
+//     membership the user was not granted
 //   - codes.Internal: if the login process fails
 func (s *server) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
 	if err := validateLoginRequest(req); err != nil {
 		return nil, err
 	}
 
-	token, err := s.auth.Login(ctx, req.GetEmail(), req.GetPassword(), req.GetAppId())
+	token, err := s.auth.Login(ctx, req.GetEmail(), req.GetPassword(), req.GetAppId(), clientInfo(ctx))
 	if err != nil {
 		if errors.Is(err, auth.ErrInvalidCredentials) {
 			return nil, status.Error(codes.InvalidArgument, "invalid credentials")
@@ -94,11 +332,38 @@ func (s *server) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResp
 			return nil, status.Error(codes.InvalidArgument, "invalid app ID")
 		}
 
+		if errors.Is(err, auth.ErrAccountFrozen) {
+			return nil, status.Error(codes.FailedPrecondition, "account is frozen")
+		}
+
+		if errors.Is(err, auth.ErrNotAppMember) {
+			return nil, status.Error(codes.PermissionDenied, "user is not a member of the app")
+		}
+
+		var challengeErr *auth.ChallengeRequiredError
+		if errors.As(err, &challengeErr) {
+			return &pb.LoginResponse{
+				MfaChallengeId:        challengeErr.ChallengeID,
+				MfaChallengeExpiresAt: timestamppb.New(challengeErr.ExpiresAt),
+			}, nil
+		}
+
+		var rejectErr *auth.RejectError
+		if errors.As(err, &rejectErr) {
+			return nil, status.Error(codes.PermissionDenied, rejectErr.Reason)
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	refreshToken, err := s.auth.IssueRefreshToken(ctx, token, clientInfo(ctx))
+	if err != nil {
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
 	return &pb.LoginResponse{
-		Token: token,
+		Token:        token,
+		RefreshToken: refreshToken,
 	}, nil
 }
 
@@ -109,18 +374,38 @@ func (s *server) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResp
 //
 // Possible errors:
 //   - codes.InvalidArgument: if user_id is invalid or missing
+//   - codes.Unavailable: if storage is too slow and no recent admin status is cached, with a RetryInfo detail
 //   - codes.Internal: if the admin check fails
 func (s *server) IsAdmin(ctx context.Context, req *pb.IsAdminRequest) (*pb.IsAdminResponse, error) {
 	if err := validateIsAdminRequest(req); err != nil {
 		return nil, err
 	}
 
-	isAdmin, err := s.auth.IsAdmin(ctx, req.GetUserId())
+	userID := req.GetUserId()
+
+	if req.GetPublicId() != "" {
+		var err error
+
+		userID, err = s.auth.ResolveUserID(ctx, req.GetPublicId())
+		if err != nil {
+			if errors.Is(err, auth.ErrUserNotFound) {
+				return nil, status.Error(codes.NotFound, "user not found")
+			}
+
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	isAdmin, err := s.auth.IsAdmin(ctx, userID)
 	if err != nil {
 		if errors.Is(err, auth.ErrUserNotFound) {
 			return nil, status.Error(codes.NotFound, "user not found")
 		}
 
+		if errors.Is(err, auth.ErrUnavailable) {
+			return nil, interceptors.RetryStatus(codes.Unavailable, "service temporarily unavailable", s.retryAfter).Err()
+		}
+
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
@@ -129,48 +414,1067 @@ func (s *server) IsAdmin(ctx context.Context, req *pb.IsAdminRequest) (*pb.IsAdm
 	}, nil
 }
 
-// validateRegisterRequest validates the registration request parameters.
-// Returns nil if the request is valid, otherwise returns a gRPC error.
-func validateRegisterRequest(req *pb.RegisterRequest) error {
-	if req.GetEmail() == "" {
-		return status.Error(codes.InvalidArgument, "email is required")
+// RequestPasswordReset handles password reset token requests.
+//
+// It succeeds whether or not the email belongs to an account.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.Internal: if the token cannot be issued or delivered
+func (s *server) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	if err := validateRequestPasswordResetRequest(req); err != nil {
+		return nil, err
 	}
 
-	if req.GetPassword() == "" {
-		return status.Error(codes.InvalidArgument, "password is required")
+	if err := s.auth.RequestPasswordReset(ctx, req.GetEmail(), req.GetAppId(), req.GetLocale()); err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
 	}
 
-	return nil
+	return &pb.RequestPasswordResetResponse{}, nil
 }
 
-// validateLoginRequest validates the login request parameters.
-// Returns nil if the request is valid, otherwise returns a gRPC error.
-func validateLoginRequest(req *pb.LoginRequest) error {
-	if req.GetEmail() == "" {
-		return status.Error(codes.InvalidArgument, "email is required")
+// ResetPassword handles password reset requests.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails or the token is invalid or expired
+//   - codes.Internal: if the reset process fails
+func (s *server) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	if err := validateResetPasswordRequest(req); err != nil {
+		return nil, err
 	}
 
-	if req.GetPassword() == "" {
-		return status.Error(codes.InvalidArgument, "password is required")
+	if err := s.auth.ResetPassword(ctx, req.GetToken(), req.GetNewPassword()); err != nil {
+		if errors.Is(err, auth.ErrInvalidResetToken) {
+			return nil, status.Error(codes.InvalidArgument, "invalid or expired reset token")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
 	}
 
-	if req.AppId == emptyValue {
-		return status.Error(codes.InvalidArgument, "app_id is required")
+	return &pb.ResetPasswordResponse{}, nil
+}
+
+// StartDeviceAuthorization handles requests to start the device authorization grant.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails or the app does not exist
+//   - codes.Internal: if the authorization cannot be started
+func (s *server) StartDeviceAuthorization(ctx context.Context, req *pb.StartDeviceAuthorizationRequest) (*pb.StartDeviceAuthorizationResponse, error) {
+	if err := validateStartDeviceAuthorizationRequest(req); err != nil {
+		return nil, err
 	}
 
-	return nil
+	device, err := s.auth.StartDeviceAuthorization(ctx, req.GetAppId())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidAppID) {
+			return nil, status.Error(codes.InvalidArgument, "invalid app ID")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.StartDeviceAuthorizationResponse{
+		DeviceCode:              device.DeviceCode,
+		UserCode:                device.UserCode,
+		VerificationUri:         device.VerificationURI,
+		VerificationUriComplete: device.VerificationURIComplete,
+		ExpiresIn:               int32(device.ExpiresIn / time.Second),
+		Interval:                int32(device.Interval / time.Second),
+	}, nil
 }
 
-// validateIsAdminRequest validates the admin check request parameters.
-// Returns nil if the request is valid, otherwise returns a gRPC error.
-func validateIsAdminRequest(req *pb.IsAdminRequest) error {
-	if req.GetUserId() == emptyValue {
-		return status.Error(codes.InvalidArgument, "user_id is required")
+// ApproveDeviceAuthorization handles a signed-in user's decision on a device.
+// The caller is identified by the token verified by the Authorization interceptor.
+//
+// Possible errors:
+//   - codes.Unauthenticated: if the caller has no valid token
+//   - codes.InvalidArgument: if request validation fails or the user code is invalid or expired
+//   - codes.Internal: if the decision cannot be recorded
+func (s *server) ApproveDeviceAuthorization(ctx context.Context, req *pb.ApproveDeviceAuthorizationRequest) (*pb.ApproveDeviceAuthorizationResponse, error) {
+	if err := validateApproveDeviceAuthorizationRequest(req); err != nil {
+		return nil, err
 	}
 
-	if req.GetUserId() < 0 {
-		return status.Error(codes.InvalidArgument, "invalid user_id")
+	claims, ok := interceptors.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
 	}
 
-	return nil
+	appID, err := s.auth.ApproveDeviceAuthorization(ctx, req.GetUserCode(), claims.UserID, !req.GetDeny())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidUserCode) {
+			return nil, status.Error(codes.InvalidArgument, "invalid or expired user code")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.ApproveDeviceAuthorizationResponse{
+		AppId: appID,
+	}, nil
+}
+
+// PollDeviceToken handles token polls from devices.
+//
+// Pending, slowed down, denied, and expired authorizations are reported in the
+// response status rather than as errors, since devices expect them while polling.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails or the device code is unknown or already used
+//   - codes.PermissionDenied: if a login hook rejected the request
+//   - codes.Internal: if the token cannot be issued
+func (s *server) PollDeviceToken(ctx context.Context, req *pb.PollDeviceTokenRequest) (*pb.PollDeviceTokenResponse, error) {
+	if err := validatePollDeviceTokenRequest(req); err != nil {
+		return nil, err
+	}
+
+	token, err := s.auth.PollDeviceToken(ctx, req.GetDeviceCode())
+	if err != nil {
+		for target, st := range deviceTokenStatuses {
+			if errors.Is(err, target) {
+				return &pb.PollDeviceTokenResponse{Status: st}, nil
+			}
+		}
+
+		if errors.Is(err, auth.ErrInvalidDeviceCode) {
+			return nil, status.Error(codes.InvalidArgument, "invalid device code")
+		}
+
+		var rejectErr *auth.RejectError
+		if errors.As(err, &rejectErr) {
+			return nil, status.Error(codes.PermissionDenied, rejectErr.Reason)
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	refreshToken, err := s.auth.IssueRefreshToken(ctx, token, clientInfo(ctx))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.PollDeviceTokenResponse{
+		Status:       pb.DeviceTokenStatus_DEVICE_TOKEN_STATUS_APPROVED,
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// RefreshToken handles token refresh requests.
+//
+// A refresh token takes precedence over the token; it is rotated, and the next one is returned.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.FailedPrecondition: if only a token is given while refresh tokens are enabled
+//   - codes.Unauthenticated: if the token is invalid or expired beyond the refresh grace window,
+//     or the refresh token is unknown, revoked, or expired
+//   - codes.PermissionDenied: if the app requires membership and the user's access was revoked
+//   - codes.Internal: if the token cannot be issued
+func (s *server) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
+	if err := validateRefreshTokenRequest(req); err != nil {
+		return nil, err
+	}
+
+	var (
+		token, refreshToken string
+		err                 error
+	)
+
+	if req.GetRefreshToken() != "" {
+		token, refreshToken, err = s.auth.ExchangeRefreshToken(ctx, req.GetRefreshToken())
+	} else {
+		token, err = s.auth.RefreshToken(ctx, req.GetToken())
+	}
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		if errors.Is(err, auth.ErrNotAppMember) {
+			return nil, status.Error(codes.PermissionDenied, "user is not a member of the app")
+		}
+
+		if errors.Is(err, auth.ErrRefreshTokenRequired) {
+			return nil, status.Error(codes.FailedPrecondition, "refresh_token is required while refresh tokens are enabled")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RefreshTokenResponse{
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// RevokeRefreshToken handles revocations of refresh tokens.
+//
+// Unknown refresh tokens are not reported as errors.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.Internal: if the refresh token cannot be revoked
+func (s *server) RevokeRefreshToken(ctx context.Context, req *pb.RevokeRefreshTokenRequest) (*pb.RevokeRefreshTokenResponse, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	if err := s.auth.RevokeRefreshToken(ctx, req.GetRefreshToken()); err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RevokeRefreshTokenResponse{}, nil
+}
+
+// ReportLogin handles reports of unauthorized logins made through login notification links.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails, or the link is invalid or expired
+//   - codes.Internal: if the tokens cannot be revoked
+func (s *server) ReportLogin(ctx context.Context, req *pb.ReportLoginRequest) (*pb.ReportLoginResponse, error) {
+	if err := validateReportLoginRequest(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.auth.ReportLogin(ctx, req.GetToken()); err != nil {
+		if errors.Is(err, auth.ErrInvalidReportLink) {
+			return nil, status.Error(codes.InvalidArgument, "invalid or expired link")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.ReportLoginResponse{}, nil
+}
+
+// ReportLeakedSecret handles reports of leaked app secrets from secret scanners.
+// It succeeds whether or not an app uses the secret.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.Internal: if the secret cannot be rotated
+func (s *server) ReportLeakedSecret(ctx context.Context, req *pb.ReportLeakedSecretRequest) (*pb.ReportLeakedSecretResponse, error) {
+	if err := validateReportLeakedSecretRequest(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.auth.ReportLeakedSecret(ctx, req.GetSecret(), req.GetSource()); err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.ReportLeakedSecretResponse{}, nil
+}
+
+// RequestUnfreeze handles requests for an unfreeze token.
+// It succeeds whether or not the email belongs to a frozen account.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.Internal: if the token cannot be issued or delivered
+func (s *server) RequestUnfreeze(ctx context.Context, req *pb.RequestUnfreezeRequest) (*pb.RequestUnfreezeResponse, error) {
+	if err := validateRequestUnfreezeRequest(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.auth.RequestUnfreeze(ctx, req.GetEmail(), req.GetAppId(), req.GetLocale()); err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RequestUnfreezeResponse{}, nil
+}
+
+// Unfreeze handles requests to unfreeze an account with an unfreeze token.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails, or the token is invalid or expired
+//   - codes.Internal: if the account cannot be unfrozen
+func (s *server) Unfreeze(ctx context.Context, req *pb.UnfreezeRequest) (*pb.UnfreezeResponse, error) {
+	if err := validateUnfreezeRequest(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.auth.Unfreeze(ctx, req.GetToken(), req.GetNewPassword()); err != nil {
+		if errors.Is(err, auth.ErrInvalidUnfreezeToken) {
+			return nil, status.Error(codes.InvalidArgument, "invalid or expired unfreeze token")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.UnfreezeResponse{}, nil
+}
+
+// GetUserInfo handles profile requests of a signed-in user.
+// The caller is identified by the token verified by the Authorization interceptor.
+// The response may be cached until the token expires, for at most userInfoMaxAge.
+//
+// Possible errors:
+//   - codes.Unauthenticated: if the caller has no valid token
+//   - codes.NotFound: if the user no longer exists
+//   - codes.Internal: if the user cannot be read
+func (s *server) GetUserInfo(ctx context.Context, req *pb.GetUserInfoRequest) (*pb.GetUserInfoResponse, error) {
+	claims, ok := interceptors.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	user, err := s.auth.GetUserInfo(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	// Setting the header only fails if headers were already sent, which never happens in a unary handler.
+	maxAge := max(min(time.Until(claims.ExpiresAt), userInfoMaxAge), 0)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(cacheControlHeader, fmt.Sprintf("private, max-age=%d", int(maxAge.Seconds()))))
+
+	return &pb.GetUserInfoResponse{
+		Sub:       user.PublicID,
+		Email:     user.Email,
+		AvatarUrl: s.auth.AvatarURL(user),
+	}, nil
+}
+
+// WhoAmI handles requests resolving a token to the identity, roles, and permissions of its user.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if token is missing
+//   - codes.Unauthenticated: if the token is invalid, expired, or revoked
+//   - codes.Internal: if the identity cannot be resolved
+func (s *server) WhoAmI(ctx context.Context, req *pb.WhoAmIRequest) (*pb.WhoAmIResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	identity, err := s.auth.WhoAmI(ctx, req.GetToken())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &pb.WhoAmIResponse{
+		Sub:         identity.User.PublicID,
+		UserId:      identity.User.ID,
+		Email:       identity.User.Email,
+		AppId:       identity.AppID,
+		Audience:    identity.Audience,
+		ExpiresAt:   timestamppb.New(identity.ExpiresAt),
+		Roles:       identity.Roles,
+		OwnedAppIds: identity.OwnedAppIDs,
+		Permissions: s.permissions(identity),
+	}
+
+	if !identity.IssuedAt.IsZero() {
+		resp.IssuedAt = timestamppb.New(identity.IssuedAt)
+	}
+
+	if !identity.Authn.Time.IsZero() {
+		resp.AuthTime = timestamppb.New(identity.Authn.Time)
+	}
+
+	resp.Amr = identity.Authn.Methods
+	resp.Acr = identity.Authn.ACR
+
+	return resp, nil
+}
+
+// IntrospectToken handles RFC 7662 introspection requests for access and refresh tokens.
+// Tokens that are not active are reported as such rather than failing.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if token is missing
+//   - codes.Internal: if the token cannot be checked
+func (s *server) IntrospectToken(ctx context.Context, req *pb.IntrospectTokenRequest) (*pb.IntrospectTokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	in, err := s.auth.Introspect(ctx, req.GetToken(), req.GetTokenTypeHint())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	if !in.Active {
+		return &pb.IntrospectTokenResponse{}, nil
+	}
+
+	resp := &pb.IntrospectTokenResponse{
+		Active:    true,
+		TokenType: in.TokenType,
+		Sub:       in.User.PublicID,
+		Username:  in.User.Email,
+		AppId:     in.AppID,
+		Audience:  in.Audience,
+		ExpiresAt: timestamppb.New(in.ExpiresAt),
+		Amr:       in.Authn.Methods,
+		Acr:       in.Authn.ACR,
+		Iss:       in.Issuer,
+		Jti:       in.TokenID,
+	}
+
+	if !in.IssuedAt.IsZero() {
+		resp.IssuedAt = timestamppb.New(in.IssuedAt)
+	}
+
+	if !in.Authn.Time.IsZero() {
+		resp.AuthTime = timestamppb.New(in.Authn.Time)
+	}
+
+	return resp, nil
+}
+
+// GetJwks handles requests for the public keys RS256 tokens are verified against.
+func (s *server) GetJwks(ctx context.Context, req *pb.GetJwksRequest) (*pb.GetJwksResponse, error) {
+	resp := &pb.GetJwksResponse{}
+
+	for _, key := range s.auth.JWKS() {
+		resp.Keys = append(resp.Keys, &pb.Jwk{
+			Kty: key.KeyType,
+			Use: key.Use,
+			Alg: key.Algorithm,
+			Kid: key.KeyID,
+			N:   key.Modulus,
+			E:   key.Exponent,
+		})
+	}
+
+	return resp, nil
+}
+
+// GetServiceStatus handles requests for the announcement operators set, e.g. of upcoming maintenance.
+// It needs no token, so client apps can check it before their users sign in.
+func (s *server) GetServiceStatus(ctx context.Context, req *pb.GetServiceStatusRequest) (*pb.GetServiceStatusResponse, error) {
+	a := s.announcements.Current()
+	if a == nil {
+		return &pb.GetServiceStatusResponse{}, nil
+	}
+
+	resp := &pb.GetServiceStatusResponse{
+		Announcement: a.Message,
+		AnnouncedAt:  timestamppb.New(a.CreatedAt),
+	}
+
+	if !a.ExpiresAt.IsZero() {
+		resp.ExpiresAt = timestamppb.New(a.ExpiresAt)
+	}
+
+	return resp, nil
+}
+
+// SetPushMfa turns push approval of logins on or off for the signed-in user.
+// The caller is identified by the token verified by the Authorization interceptor.
+//
+// Possible errors:
+//   - codes.Unauthenticated: if the caller has no valid token
+//   - codes.NotFound: if the user no longer exists
+//   - codes.Internal: if the setting cannot be changed
+func (s *server) SetPushMfa(ctx context.Context, req *pb.SetPushMfaRequest) (*pb.SetPushMfaResponse, error) {
+	claims, ok := interceptors.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	if err := s.auth.SetPushMFA(ctx, claims.UserID, req.GetEnabled()); err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.SetPushMfaResponse{}, nil
+}
+
+// UploadAvatar handles avatar uploads of the signed-in user, streamed in chunks.
+// The caller is identified by the token verified by the StreamAuthorization interceptor.
+// Uploads are rejected as soon as they exceed the size limit, so they are never buffered whole.
+//
+// Possible errors:
+//   - codes.Unauthenticated: if the caller has no valid token
+//   - codes.InvalidArgument: if the image is empty, too large, or of an unsupported type
+//   - codes.FailedPrecondition: if avatars are disabled
+//   - codes.NotFound: if the user no longer exists
+//   - codes.Internal: if the avatar cannot be stored
+func (s *server) UploadAvatar(stream pb.Auth_UploadAvatarServer) error {
+	ctx := stream.Context()
+
+	claims, ok := interceptors.ClaimsFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	maxSize := s.auth.MaxAvatarSize()
+
+	var image []byte
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if len(image)+len(req.GetChunk()) > maxSize {
+			return status.Errorf(codes.InvalidArgument, "avatar exceeds %d bytes", maxSize)
+		}
+
+		image = append(image, req.GetChunk()...)
+	}
+
+	avatarURL, err := s.auth.SetAvatar(ctx, claims.UserID, image)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrAvatarsDisabled):
+			return status.Error(codes.FailedPrecondition, "avatars are disabled")
+		case errors.Is(err, auth.ErrInvalidAvatar):
+			return status.Error(codes.InvalidArgument, "avatar must be a png, jpeg, gif, or webp image")
+		case errors.Is(err, auth.ErrUserNotFound):
+			return status.Error(codes.NotFound, "user not found")
+		}
+
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	return stream.SendAndClose(&pb.UploadAvatarResponse{AvatarUrl: avatarURL})
+}
+
+// ListLoginChallenges handles requests for the pending login challenges of the signed-in user.
+// The caller is identified by the token verified by the Authorization interceptor.
+//
+// Possible errors:
+//   - codes.Unauthenticated: if the caller has no valid token
+//   - codes.Internal: if the challenges cannot be read
+func (s *server) ListLoginChallenges(ctx context.Context, req *pb.ListLoginChallengesRequest) (*pb.ListLoginChallengesResponse, error) {
+	claims, ok := interceptors.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	challenges, err := s.auth.ListLoginChallenges(ctx, claims.UserID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &pb.ListLoginChallengesResponse{
+		Challenges: make([]*pb.LoginChallenge, 0, len(challenges)),
+	}
+
+	for _, c := range challenges {
+		resp.Challenges = append(resp.Challenges, &pb.LoginChallenge{
+			Id:        c.ID,
+			AppId:     c.AppID,
+			AppName:   c.AppName,
+			Ip:        c.IP,
+			UserAgent: c.UserAgent,
+			CreatedAt: timestamppb.New(c.CreatedAt),
+			ExpiresAt: timestamppb.New(c.ExpiresAt),
+			Action:    c.Action,
+		})
+	}
+
+	return resp, nil
+}
+
+// DecideLoginChallenge handles a signed-in user's decision on one of their login challenges.
+// The caller is identified by the token verified by the Authorization interceptor.
+//
+// Possible errors:
+//   - codes.Unauthenticated: if the caller has no valid token
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.NotFound: if the user has no pending, unexpired challenge with the ID
+//   - codes.Internal: if the decision cannot be recorded
+func (s *server) DecideLoginChallenge(ctx context.Context, req *pb.DecideLoginChallengeRequest) (*pb.DecideLoginChallengeResponse, error) {
+	if err := validateDecideLoginChallengeRequest(req); err != nil {
+		return nil, err
+	}
+
+	claims, ok := interceptors.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	if err := s.auth.DecideLoginChallenge(ctx, req.GetId(), claims.UserID, !req.GetDeny()); err != nil {
+		if errors.Is(err, auth.ErrInvalidChallenge) {
+			return nil, status.Error(codes.NotFound, "login challenge not found or already decided")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.DecideLoginChallengeResponse{}, nil
+}
+
+// PollLoginChallenge handles token polls of logins waiting for push approval.
+//
+// Pending, denied, and expired challenges are reported in the response status rather
+// than as errors, since clients expect them while polling.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails or the challenge is unknown or already used
+//   - codes.PermissionDenied: if a login hook rejected the request
+//   - codes.Internal: if the token cannot be issued
+func (s *server) PollLoginChallenge(ctx context.Context, req *pb.PollLoginChallengeRequest) (*pb.PollLoginChallengeResponse, error) {
+	if err := validatePollLoginChallengeRequest(req); err != nil {
+		return nil, err
+	}
+
+	token, err := s.auth.PollLoginChallenge(ctx, req.GetChallengeId())
+	if err != nil {
+		for target, st := range loginChallengeStatuses {
+			if errors.Is(err, target) {
+				return &pb.PollLoginChallengeResponse{Status: st}, nil
+			}
+		}
+
+		if errors.Is(err, auth.ErrInvalidChallenge) {
+			return nil, status.Error(codes.InvalidArgument, "invalid login challenge")
+		}
+
+		var rejectErr *auth.RejectError
+		if errors.As(err, &rejectErr) {
+			return nil, status.Error(codes.PermissionDenied, rejectErr.Reason)
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	refreshToken, err := s.auth.IssueRefreshToken(ctx, token, clientInfo(ctx))
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.PollLoginChallengeResponse{
+		Status:       pb.LoginChallengeStatus_LOGIN_CHALLENGE_STATUS_APPROVED,
+		Token:        token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// RequireStepUp handles checks that a token is elevated enough for a sensitive action.
+//
+// A token that is not elevated enough is not an error: the response carries a challenge instead.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.Unauthenticated: if the token is invalid, expired, or revoked
+//   - codes.FailedPrecondition: if the user has no push MFA to approve a step-up with
+//   - codes.Internal: if the check fails
+func (s *server) RequireStepUp(ctx context.Context, req *pb.RequireStepUpRequest) (*pb.RequireStepUpResponse, error) {
+	if err := validateRequireStepUpRequest(req); err != nil {
+		return nil, err
+	}
+
+	authn, err := s.auth.RequireStepUp(ctx, req.GetToken(), req.GetAction(), clientInfo(ctx))
+	if err != nil {
+		var challengeErr *auth.ChallengeRequiredError
+		if errors.As(err, &challengeErr) {
+			return &pb.RequireStepUpResponse{
+				ChallengeId:        challengeErr.ChallengeID,
+				ChallengeExpiresAt: timestamppb.New(challengeErr.ExpiresAt),
+			}, nil
+		}
+
+		if errors.Is(err, auth.ErrInvalidToken) {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		if errors.Is(err, auth.ErrStepUpUnavailable) {
+			return nil, status.Error(codes.FailedPrecondition, "step-up requires push approval to be enabled")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RequireStepUpResponse{
+		Satisfied: true,
+		AuthTime:  timestamppb.New(authn.Time),
+	}, nil
+}
+
+// permissions evaluates the policy for identity, listing the protected methods its token may call.
+func (s *server) permissions(identity *auth.Identity) []*pb.Permission {
+	var permissions []*pb.Permission
+
+	for _, m := range s.policy.Levels.Methods(authzpolicy.LevelAuthenticated) {
+		permissions = append(permissions, &pb.Permission{Method: m})
+	}
+
+	// Admin and scoped methods reject tokens anyone holding the secret of their app could have forged.
+	if !identity.ServiceSigned && (s.policy.AdminAppID == 0 || identity.AppID != s.policy.AdminAppID) {
+		return permissions
+	}
+
+	for _, scope := range identity.Authn.Scopes {
+		for _, m := range s.policy.Levels.Methods(authzpolicy.ScopeLevel(scope)) {
+			permissions = append(permissions, &pb.Permission{Method: m})
+		}
+	}
+
+	if slices.Contains(identity.Roles, auth.RoleAdmin) {
+		for _, m := range s.policy.Levels.Methods(authzpolicy.LevelAdmin) {
+			permissions = append(permissions, &pb.Permission{Method: m})
+		}
+
+		return permissions
+	}
+
+	if len(identity.OwnedAppIDs) == 0 {
+		return permissions
+	}
+
+	for _, m := range s.policy.OwnerMethods {
+		if s.policy.Levels.Level(m) != authzpolicy.LevelAdmin {
+			continue
+		}
+
+		permissions = append(permissions, &pb.Permission{Method: m, AppIds: identity.OwnedAppIDs})
+	}
+
+	return permissions
+}
+
+// clientInfo describes the client of a call by its network address, as resolved by the
+// ClientAddress interceptor, and user agent.
+func clientInfo(ctx context.Context) auth.ClientInfo {
+	client := auth.ClientInfo{IP: interceptors.ClientAddressFromContext(ctx)}
+
+	if values := metadata.ValueFromIncomingContext(ctx, "user-agent"); len(values) > 0 {
+		client.UserAgent = values[0]
+	}
+
+	return client
+}
+
+// registrationStatuses maps the statuses of async registrations to response statuses.
+var registrationStatuses = map[string]pb.RegistrationStatus{
+	models.RegistrationPending:   pb.RegistrationStatus_REGISTRATION_STATUS_PENDING,
+	models.RegistrationCompleted: pb.RegistrationStatus_REGISTRATION_STATUS_COMPLETED,
+	models.RegistrationFailed:    pb.RegistrationStatus_REGISTRATION_STATUS_FAILED,
+}
+
+// deviceTokenStatuses maps the service errors that devices expect while polling to response statuses.
+var deviceTokenStatuses = map[error]pb.DeviceTokenStatus{
+	auth.ErrAuthorizationPending: pb.DeviceTokenStatus_DEVICE_TOKEN_STATUS_PENDING,
+	auth.ErrSlowDown:             pb.DeviceTokenStatus_DEVICE_TOKEN_STATUS_SLOW_DOWN,
+	auth.ErrAccessDenied:         pb.DeviceTokenStatus_DEVICE_TOKEN_STATUS_DENIED,
+	auth.ErrExpiredToken:         pb.DeviceTokenStatus_DEVICE_TOKEN_STATUS_EXPIRED,
+}
+
+// loginChallengeStatuses maps the service errors that clients expect while polling a login challenge to response statuses.
+var loginChallengeStatuses = map[error]pb.LoginChallengeStatus{
+	auth.ErrChallengePending: pb.LoginChallengeStatus_LOGIN_CHALLENGE_STATUS_PENDING,
+	auth.ErrChallengeDenied:  pb.LoginChallengeStatus_LOGIN_CHALLENGE_STATUS_DENIED,
+	auth.ErrChallengeExpired: pb.LoginChallengeStatus_LOGIN_CHALLENGE_STATUS_EXPIRED,
+}
+
+// validateRegisterRequest validates the registration request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateRegisterRequest(req *pb.RegisterRequest) error {
+	if req.GetEmail() == "" {
+		return status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if req.GetPassword() == "" {
+		return status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	return nil
+}
+
+// validateLoginRequest validates the login request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateLoginRequest(req *pb.LoginRequest) error {
+	if req.GetEmail() == "" {
+		return status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if req.GetPassword() == "" {
+		return status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	if req.AppId == emptyValue {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	return nil
+}
+
+// validateIsAdminRequest validates the admin check request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateIsAdminRequest(req *pb.IsAdminRequest) error {
+	if req.GetPublicId() != "" {
+		return nil
+	}
+
+	if req.GetUserId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if req.GetUserId() < 0 {
+		return status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	return nil
+}
+
+// validateRequestPasswordResetRequest validates the password reset token request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateRequestPasswordResetRequest(req *pb.RequestPasswordResetRequest) error {
+	if req.GetEmail() == "" {
+		return status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	return nil
+}
+
+// validateResetPasswordRequest validates the password reset request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateResetPasswordRequest(req *pb.ResetPasswordRequest) error {
+	if req.GetToken() == "" {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if req.GetNewPassword() == "" {
+		return status.Error(codes.InvalidArgument, "new_password is required")
+	}
+
+	return nil
+}
+
+// validateStartDeviceAuthorizationRequest validates the device authorization start request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateStartDeviceAuthorizationRequest(req *pb.StartDeviceAuthorizationRequest) error {
+	if req.GetAppId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	return nil
+}
+
+// validateApproveDeviceAuthorizationRequest validates the device approval request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateApproveDeviceAuthorizationRequest(req *pb.ApproveDeviceAuthorizationRequest) error {
+	if req.GetUserCode() == "" {
+		return status.Error(codes.InvalidArgument, "user_code is required")
+	}
+
+	return nil
+}
+
+// validateGetRegistrationStatusRequest validates the registration status request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateGetRegistrationStatusRequest(req *pb.GetRegistrationStatusRequest) error {
+	if req.GetRegistrationId() == "" {
+		return status.Error(codes.InvalidArgument, "registration_id is required")
+	}
+
+	return nil
+}
+
+// validatePollDeviceTokenRequest validates the device token poll request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validatePollDeviceTokenRequest(req *pb.PollDeviceTokenRequest) error {
+	if req.GetDeviceCode() == "" {
+		return status.Error(codes.InvalidArgument, "device_code is required")
+	}
+
+	return nil
+}
+
+// validateDecideLoginChallengeRequest validates the login challenge decision request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateDecideLoginChallengeRequest(req *pb.DecideLoginChallengeRequest) error {
+	if req.GetId() <= emptyValue {
+		return status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	return nil
+}
+
+// validatePollLoginChallengeRequest validates the login challenge poll request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validatePollLoginChallengeRequest(req *pb.PollLoginChallengeRequest) error {
+	if req.GetChallengeId() == "" {
+		return status.Error(codes.InvalidArgument, "challenge_id is required")
+	}
+
+	return nil
+}
+
+// validateRequireStepUpRequest validates the step-up request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateRequireStepUpRequest(req *pb.RequireStepUpRequest) error {
+	if req.GetToken() == "" {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if req.GetAction() == "" {
+		return status.Error(codes.InvalidArgument, "action is required")
+	}
+
+	return nil
+}
+
+// validateRefreshTokenRequest validates the token refresh request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateRefreshTokenRequest(req *pb.RefreshTokenRequest) error {
+	if req.GetToken() == "" && req.GetRefreshToken() == "" {
+		return status.Error(codes.InvalidArgument, "token or refresh_token is required")
+	}
+
+	return nil
+}
+
+// validateReportLoginRequest validates the login report request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateReportLoginRequest(req *pb.ReportLoginRequest) error {
+	if req.GetToken() == "" {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	return nil
+}
+
+// validateReportLeakedSecretRequest validates the leaked secret report parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateReportLeakedSecretRequest(req *pb.ReportLeakedSecretRequest) error {
+	if req.GetSecret() == "" {
+		return status.Error(codes.InvalidArgument, "secret is required")
+	}
+
+	return nil
+}
+
+// validateRequestUnfreezeRequest validates the unfreeze token request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateRequestUnfreezeRequest(req *pb.RequestUnfreezeRequest) error {
+	if req.GetEmail() == "" {
+		return status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	return nil
+}
+
+// validateUnfreezeRequest validates the unfreeze request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateUnfreezeRequest(req *pb.UnfreezeRequest) error {
+	if req.GetToken() == "" {
+		return status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if req.GetNewPassword() == "" {
+		return status.Error(codes.InvalidArgument, "new_password is required")
+	}
+
+	return nil
+}
+
+// validateRevokeSessionRequest validates the session revocation request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateRevokeSessionRequest(req *pb.RevokeSessionRequest) error {
+	if req.GetId() <= emptyValue {
+		return status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	return nil
+}
+
+// Logout handles requests of the signed-in user to sign out of every app.
+// The caller is identified by the token verified by the Authorization interceptor.
+//
+// Possible errors:
+//   - codes.Unauthenticated: if the caller has no valid token
+//   - codes.NotFound: if the user no longer exists
+//   - codes.Internal: if the tokens cannot be revoked
+func (s *server) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	claims, ok := interceptors.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	if err := s.auth.Logout(ctx, claims.UserID); err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.LogoutResponse{}, nil
+}
+
+// ListSessions handles requests for the active sessions of the signed-in user.
+// The caller is identified by the token verified by the Authorization interceptor.
+//
+// Possible errors:
+//   - codes.Unauthenticated: if the caller has no valid token
+//   - codes.Internal: if the sessions cannot be read
+func (s *server) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	claims, ok := interceptors.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	sessions, err := s.auth.ListSessions(ctx, claims.UserID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &pb.ListSessionsResponse{
+		Sessions: make([]*pb.Session, 0, len(sessions)),
+	}
+
+	for _, ss := range sessions {
+		resp.Sessions = append(resp.Sessions, &pb.Session{
+			Id:         ss.ID,
+			AppId:      ss.AppID,
+			AppName:    ss.AppName,
+			Ip:         ss.IP,
+			UserAgent:  ss.UserAgent,
+			CreatedAt:  timestamppb.New(ss.CreatedAt),
+			LastUsedAt: timestamppb.New(ss.LastUsedAt),
+			ExpiresAt:  timestamppb.New(ss.ExpiresAt),
+		})
+	}
+
+	return resp, nil
+}
+
+// RevokeSession handles requests of the signed-in user to sign out of one of their sessions.
+// The caller is identified by the token verified by the Authorization interceptor.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if request validation fails
+//   - codes.Unauthenticated: if the caller has no valid token
+//   - codes.NotFound: if the user has no active session with the ID
+//   - codes.Internal: if the session cannot be revoked
+func (s *server) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	if err := validateRevokeSessionRequest(req); err != nil {
+		return nil, err
+	}
+
+	claims, ok := interceptors.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing token")
+	}
+
+	if err := s.auth.RevokeSession(ctx, req.GetId(), claims.UserID); err != nil {
+		if errors.Is(err, auth.ErrSessionNotFound) {
+			return nil, status.Error(codes.NotFound, "session not found")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.RevokeSessionResponse{}, nil
 }
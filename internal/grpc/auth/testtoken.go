@@ -0,0 +1,100 @@
+//go:build !notesttokens
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+	"github.com/kirinyoku/sso-grpc/internal/services/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TestTokenMinter defines the interface of authentication services that mint test tokens.
+// It is left out of Auth so builds with the notesttokens tag need not implement it.
+type TestTokenMinter interface {
+	// MintTestToken mints a token for an app with the given claims.
+	MintTestToken(ctx context.Context, appID int32, claims auth.TestTokenClaims, ttl time.Duration) (token string, expiresAt time.Time, err error)
+}
+
+// MintTestToken handles requests to mint a token with arbitrary claims for CI suites.
+//
+// Possible errors:
+//   - codes.InvalidArgument: if required fields are missing, ttl_seconds is negative or
+//     exceeds test_tokens.max_ttl, a scope is empty or contains whitespace, or the app
+//     doesn't exist
+//   - codes.PermissionDenied: if test tokens are not enabled
+//   - codes.FailedPrecondition: if the app is not flagged test-only
+//   - codes.Unimplemented: if the authentication service does not mint test tokens
+//   - codes.Internal: if the token cannot be minted
+func (s *server) MintTestToken(ctx context.Context, req *pb.MintTestTokenRequest) (*pb.MintTestTokenResponse, error) {
+	if err := validateMintTestTokenRequest(req); err != nil {
+		return nil, err
+	}
+
+	minter, ok := s.auth.(TestTokenMinter)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "test tokens are not supported")
+	}
+
+	token, expiresAt, err := minter.MintTestToken(ctx, req.GetAppId(), auth.TestTokenClaims{
+		Subject:    req.GetSubject(),
+		UserID:     req.GetUserId(),
+		Email:      req.GetEmail(),
+		Attributes: req.GetAttributes(),
+		AMR:        req.GetAmr(),
+		ACR:        req.GetAcr(),
+		Scopes:     req.GetScopes(),
+	}, time.Duration(req.GetTtlSeconds())*time.Second)
+	if err != nil {
+		if errors.Is(err, auth.ErrTestTokensDisabled) {
+			return nil, status.Error(codes.PermissionDenied, "test tokens are disabled")
+		}
+
+		if errors.Is(err, auth.ErrInvalidTestTokenTTL) {
+			return nil, status.Error(codes.InvalidArgument, "ttl_seconds exceeds test_tokens.max_ttl")
+		}
+
+		if errors.Is(err, auth.ErrInvalidAppID) {
+			return nil, status.Error(codes.InvalidArgument, "invalid app ID")
+		}
+
+		if errors.Is(err, auth.ErrAppNotTestOnly) {
+			return nil, status.Error(codes.FailedPrecondition, "app is not test-only")
+		}
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &pb.MintTestTokenResponse{Token: token, ExpiresAt: timestamppb.New(expiresAt)}, nil
+}
+
+// validateMintTestTokenRequest validates the test token request parameters.
+// Returns nil if the request is valid, otherwise returns a gRPC error.
+func validateMintTestTokenRequest(req *pb.MintTestTokenRequest) error {
+	if req.GetAppId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if req.GetSubject() == "" {
+		return status.Error(codes.InvalidArgument, "subject is required")
+	}
+
+	if req.GetTtlSeconds() < 0 {
+		return status.Error(codes.InvalidArgument, "ttl_seconds must not be negative")
+	}
+
+	for _, scope := range req.GetScopes() {
+		if scope == "" || strings.ContainsFunc(scope, unicode.IsSpace) {
+			return status.Error(codes.InvalidArgument, "scopes must be non-empty and contain no whitespace")
+		}
+	}
+
+	return nil
+}
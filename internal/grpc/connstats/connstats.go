@@ -0,0 +1,96 @@
+// Package connstats provides a gRPC stats handler that tracks per-connection
+// activity, so operators can see how client-side load balancers spread
+// connections and RPCs across server replicas.
+package connstats
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/stats"
+)
+
+// Handler implements stats.Handler and records connection lifetimes
+// and the number of RPCs served over each connection.
+type Handler struct {
+	log    *slog.Logger // Logger for connection events
+	active atomic.Int64 // Number of currently open connections
+}
+
+// connKey is the context key under which per-connection state is stored.
+type connKey struct{}
+
+// conn holds the state tracked for a single client connection.
+type conn struct {
+	remoteAddr string       // Address of the connected client
+	openedAt   time.Time    // Moment the connection was established
+	rpcs       atomic.Int64 // Number of RPCs started on the connection
+}
+
+// New creates a new connection stats handler.
+//
+// Parameters:
+//   - log: logger for connection events
+//
+// Returns:
+//   - *Handler: handler ready to be passed to grpc.StatsHandler
+func New(log *slog.Logger) *Handler {
+	return &Handler{log: log}
+}
+
+// Active returns the number of currently open client connections.
+func (h *Handler) Active() int64 {
+	return h.active.Load()
+}
+
+// TagConn attaches per-connection state to the connection context.
+func (h *Handler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	c := &conn{openedAt: time.Now()}
+	if info.RemoteAddr != nil {
+		c.remoteAddr = info.RemoteAddr.String()
+	}
+
+	return context.WithValue(ctx, connKey{}, c)
+}
+
+// HandleConn logs connection establishment and teardown along with the
+// connection's lifetime and number of RPCs it carried.
+func (h *Handler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	c, ok := ctx.Value(connKey{}).(*conn)
+	if !ok {
+		return
+	}
+
+	switch s.(type) {
+	case *stats.ConnBegin:
+		h.log.Debug("client connection opened",
+			slog.String("remote_addr", c.remoteAddr),
+			slog.Int64("active_connections", h.active.Add(1)),
+		)
+	case *stats.ConnEnd:
+		h.log.Debug("client connection closed",
+			slog.String("remote_addr", c.remoteAddr),
+			slog.Duration("lifetime", time.Since(c.openedAt)),
+			slog.Int64("rpcs", c.rpcs.Load()),
+			slog.Int64("active_connections", h.active.Add(-1)),
+		)
+	}
+}
+
+// TagRPC leaves the RPC context unchanged.
+func (h *Handler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+// HandleRPC counts RPCs started on the connection.
+func (h *Handler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if _, ok := s.(*stats.Begin); !ok {
+		return
+	}
+
+	if c, ok := ctx.Value(connKey{}).(*conn); ok {
+		c.rpcs.Add(1)
+	}
+}
@@ -0,0 +1,38 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// AnnouncementSource defines the interface used to read the announcement attached to responses.
+type AnnouncementSource interface {
+	// Current returns the announcement shown now, or nil if there is none.
+	Current() *models.Announcement
+}
+
+// announcementHeader is the response metadata key carrying the current announcement.
+const announcementHeader = "sso-announcement"
+
+// Announcement returns a unary interceptor that attaches the current announcement, e.g. of
+// upcoming maintenance, to every response as the "sso-announcement" header, so client apps
+// can show it without polling. Failed calls carry it as well.
+//
+// Parameters:
+//   - source: the current announcement
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: interceptor attaching the announcement
+func Announcement(source AnnouncementSource) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if a := source.Current(); a != nil {
+			// Setting the header only fails if headers were already sent, which never happens before the handler runs.
+			_ = grpc.SetHeader(ctx, metadata.Pairs(announcementHeader, a.Message))
+		}
+
+		return handler(ctx, req)
+	}
+}
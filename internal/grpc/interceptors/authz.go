@@ -0,0 +1,378 @@
+// Package interceptors provides gRPC server interceptors shared by all services.
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/authzpolicy"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/services/admin"
+	"github.com/kirinyoku/sso-grpc/internal/services/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// TokenValidator defines the interface used to authenticate and authorize callers.
+type TokenValidator interface {
+	// ValidateToken verifies a token and returns its claims.
+	ValidateToken(ctx context.Context, token string) (*jwt.Claims, error)
+	// IsAdmin checks if the specified user has administrative privileges.
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
+	// IsAppOwner checks if the specified user is an owner of an application.
+	IsAppOwner(ctx context.Context, appID int32, userID int64) (bool, error)
+}
+
+// SupportTokenValidator defines the interface used to authenticate support tooling.
+type SupportTokenValidator interface {
+	// ValidateSupportToken verifies a support token and returns it.
+	ValidateSupportToken(ctx context.Context, token string) (*models.SupportToken, error)
+}
+
+// MethodPolicy defines the interface used to look up the auth level each method requires.
+type MethodPolicy interface {
+	// Level returns the level a method requires.
+	Level(method string) authzpolicy.Level
+}
+
+// claimsKey is the context key under which verified caller claims are stored.
+type claimsKey struct{}
+
+// supportTokenKey is the context key under which a verified support token is stored.
+type supportTokenKey struct{}
+
+const (
+	// authorizationHeader is the metadata key carrying the caller's token
+	authorizationHeader = "authorization"
+	// bearerPrefix is the scheme prefix expected in the authorization header
+	bearerPrefix = "Bearer "
+)
+
+// Authorization returns a unary interceptor that enforces the level policy requires for
+// each method: a valid admin token for admin methods, a valid token of any user for
+// authenticated methods, and a valid token granted the scope for scoped methods.
+// Anonymous methods pass through untouched. The policy is consulted on every call, so
+// changes to it apply right away.
+//
+// Admin methods listed in supportMethods also accept a support token, as long as every
+// user_id, public_id, and app_id set in the request matches the token's scope.
+//
+// Admin methods listed in ownerMethods also accept a token of a user who is not an admin
+// but owns the app named by the request's app_id.
+//
+// Admin methods, including those called by owners, and scoped methods only accept tokens of
+// the admin app or tokens signed by the service (RS256). Every relying party holds the secret
+// of its app, so it could sign a token of its app for any user, admins included, and with any
+// scope.
+//
+// Parameters:
+//   - log: logger for authorization events
+//   - validator: token validation and role lookup implementation
+//   - supportValidator: support token validation implementation
+//   - policy: level each full gRPC method name (e.g. "/admin.Admin/CreateApp") requires
+//   - supportMethods: full gRPC method names of admin methods that accept support tokens
+//   - ownerMethods: full gRPC method names of admin methods that owners of the addressed app may call
+//   - adminAppID: app whose tokens admin methods accept even when signed with its secret; 0 for none
+//   - retryAfter: delay suggested in the RetryInfo error detail when storage is unavailable
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: interceptor enforcing the policy
+//
+// Possible errors returned to clients:
+//   - codes.Unauthenticated: if the token is missing or invalid
+//   - codes.PermissionDenied: if the caller is neither an admin nor an owner of the addressed app,
+//     the token of an admin or scoped method is neither of the admin app nor signed by the service, the token is not granted the scope the method requires, or a support token is used for
+//     another method or resource than it is scoped to
+//   - codes.Unavailable: if storage is too slow to check admin privileges and none are cached,
+//     with a RetryInfo detail
+//   - codes.Internal: if the check itself fails
+func Authorization(
+	log *slog.Logger,
+	validator TokenValidator,
+	supportValidator SupportTokenValidator,
+	policy MethodPolicy,
+	supportMethods []string,
+	ownerMethods []string,
+	adminAppID int32,
+	retryAfter time.Duration,
+) grpc.UnaryServerInterceptor {
+	supported := make(map[string]struct{}, len(supportMethods))
+	for _, m := range supportMethods {
+		supported[m] = struct{}{}
+	}
+
+	owned := make(map[string]struct{}, len(ownerMethods))
+	for _, m := range ownerMethods {
+		owned[m] = struct{}{}
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		level := policy.Level(info.FullMethod)
+		if level == authzpolicy.LevelAnonymous {
+			return handler(ctx, req)
+		}
+
+		const op = "interceptors.Authorization"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("method", info.FullMethod),
+		)
+
+		token := bearerToken(ctx)
+		if token == "" {
+			return nil, status.Error(codes.Unauthenticated, "missing token")
+		}
+
+		if strings.HasPrefix(token, admin.SupportTokenPrefix) {
+			if _, ok := supported[info.FullMethod]; !ok || level != authzpolicy.LevelAdmin {
+				return nil, status.Error(codes.PermissionDenied, "support tokens cannot call this method")
+			}
+
+			supportToken, err := supportValidator.ValidateSupportToken(ctx, token)
+			if err != nil {
+				if errors.Is(err, admin.ErrInvalidSupportToken) {
+					return nil, status.Error(codes.Unauthenticated, "invalid token")
+				}
+
+				return nil, status.Error(codes.Internal, "internal error")
+			}
+
+			log = log.With(
+				slog.Int64("support_token_id", supportToken.ID),
+				slog.Int64("issued_by", supportToken.IssuedBy),
+			)
+
+			if !supportScopeAllows(supportToken, req) {
+				log.Warn("request outside of support token scope")
+
+				return nil, status.Error(codes.PermissionDenied, "request is outside of the support token scope")
+			}
+
+			log.Info("support access")
+
+			return handler(context.WithValue(ctx, supportTokenKey{}, supportToken), req)
+		}
+
+		claims, err := validator.ValidateToken(ctx, token)
+		if err != nil {
+			if errors.Is(err, auth.ErrInvalidToken) {
+				return nil, status.Error(codes.Unauthenticated, "invalid token")
+			}
+
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+
+		if scope, ok := level.Scope(); ok {
+			// The service does not grant scopes itself, so a scope is only trusted in a
+			// token the relying parties cannot sign themselves.
+			if !adminCredential(claims, adminAppID) {
+				log.Warn("scoped method called with an app-signed token",
+					slog.Int64("user_id", claims.UserID),
+					slog.Int("app_id", int(claims.AppID)),
+				)
+
+				return nil, status.Error(codes.PermissionDenied, "scoped methods require a token of the admin app or one signed by the service")
+			}
+
+			if !slices.Contains(claims.Scopes, scope) {
+				log.Warn("scope required", slog.Int64("user_id", claims.UserID), slog.String("scope", scope))
+
+				return nil, status.Errorf(codes.PermissionDenied, "scope %q required", scope)
+			}
+
+			return handler(context.WithValue(ctx, claimsKey{}, claims), req)
+		}
+
+		if level != authzpolicy.LevelAdmin {
+			return handler(context.WithValue(ctx, claimsKey{}, claims), req)
+		}
+
+		if !adminCredential(claims, adminAppID) {
+			log.Warn("admin method called with an app-signed token",
+				slog.Int64("user_id", claims.UserID),
+				slog.Int("app_id", int(claims.AppID)),
+			)
+
+			return nil, status.Error(codes.PermissionDenied, "admin methods require a token of the admin app or one signed by the service")
+		}
+
+		isAdmin, err := validator.IsAdmin(ctx, claims.UserID)
+		if err != nil {
+			if errors.Is(err, auth.ErrUserNotFound) {
+				return nil, status.Error(codes.Unauthenticated, "invalid token")
+			}
+
+			if errors.Is(err, auth.ErrUnavailable) {
+				return nil, RetryStatus(codes.Unavailable, "service temporarily unavailable", retryAfter).Err()
+			}
+
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+
+		if !isAdmin {
+			if _, ok := owned[info.FullMethod]; ok {
+				return authorizeOwner(ctx, log, validator, claims, req, handler)
+			}
+
+			log.Warn("admin privileges required", slog.Int64("user_id", claims.UserID))
+
+			return nil, status.Error(codes.PermissionDenied, "admin privileges required")
+		}
+
+		return handler(context.WithValue(ctx, claimsKey{}, claims), req)
+	}
+}
+
+// adminCredential reports whether a token may call admin methods if its user is an admin
+// or owns the addressed app, or scoped methods if it is granted the scope: it must be issued
+// for adminAppID, or signed by the service.
+func adminCredential(claims *jwt.Claims, adminAppID int32) bool {
+	return claims.ServiceSigned || (adminAppID != 0 && claims.AppID == adminAppID)
+}
+
+// authorizeOwner lets a user who is not an admin call an owner method if the user owns the
+// app named by the request's app_id. Requests setting a field of adminOnlyFields are denied.
+func authorizeOwner(
+	ctx context.Context,
+	log *slog.Logger,
+	validator TokenValidator,
+	claims *jwt.Claims,
+	req any,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	log = log.With(slog.Int64("user_id", claims.UserID))
+
+	appID, ok := ownedAppID(req)
+	if !ok {
+		log.Warn("admin privileges required")
+
+		return nil, status.Error(codes.PermissionDenied, "admin privileges required")
+	}
+
+	isOwner, err := validator.IsAppOwner(ctx, appID, claims.UserID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	if !isOwner {
+		log.Warn("admin privileges or app ownership required", slog.Int("app_id", int(appID)))
+
+		return nil, status.Error(codes.PermissionDenied, "admin privileges or app ownership required")
+	}
+
+	log.Info("app owner access", slog.Int("app_id", int(appID)))
+
+	return handler(context.WithValue(ctx, claimsKey{}, claims), req)
+}
+
+// adminOnlyFields lists the request fields only admins may set on owner methods. Joining a
+// family shares the audience of the app with apps the owner may not own. An owner choosing
+// the secret of the app could pick the secret of another app, so owners rotate it to a
+// random one with Admin.RotateAppSecret instead.
+var adminOnlyFields = []protoreflect.Name{"family_id", "secret"}
+
+// ownedAppID returns the app_id of a request an app owner may make: one that sets
+// app_id and none of adminOnlyFields.
+func ownedAppID(req any) (int32, bool) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return 0, false
+	}
+
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+
+	for _, name := range adminOnlyFields {
+		if fd := fields.ByName(name); fd != nil && m.Has(fd) {
+			return 0, false
+		}
+	}
+
+	fd := fields.ByName("app_id")
+	if fd == nil || fd.Kind() != protoreflect.Int32Kind || !m.Has(fd) {
+		return 0, false
+	}
+
+	return int32(m.Get(fd).Int()), true
+}
+
+// ClaimsFromContext returns the verified claims of the caller, if the
+// request passed through the Authorization interceptor.
+func ClaimsFromContext(ctx context.Context) (*jwt.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*jwt.Claims)
+	return claims, ok
+}
+
+// SupportTokenFromContext returns the verified support token of the caller, if the
+// request passed through the Authorization interceptor with a support token.
+func SupportTokenFromContext(ctx context.Context) (*models.SupportToken, bool) {
+	supportToken, ok := ctx.Value(supportTokenKey{}).(*models.SupportToken)
+	return supportToken, ok
+}
+
+// supportScopeAllows reports whether a request addresses only the resource a support token
+// is scoped to: every user_id, public_id, and app_id set in the request must match the
+// token, and at least one of them must be set.
+func supportScopeAllows(supportToken *models.SupportToken, req any) bool {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return false
+	}
+
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+
+	scoped := map[protoreflect.Name]any{
+		"user_id":   supportToken.UserID,
+		"public_id": supportToken.UserPublicID,
+		"app_id":    supportToken.AppID,
+	}
+
+	matched := false
+
+	for name, want := range scoped {
+		fd := fields.ByName(name)
+		if fd == nil || !m.Has(fd) {
+			continue
+		}
+
+		if m.Get(fd).Interface() != want {
+			return false
+		}
+
+		matched = true
+	}
+
+	return matched
+}
+
+// bearerToken extracts the bearer token from incoming request metadata.
+// Returns an empty string if no token is present.
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		return ""
+	}
+
+	token, ok := strings.CutPrefix(values[0], bearerPrefix)
+	if !ok {
+		return ""
+	}
+
+	return token
+}
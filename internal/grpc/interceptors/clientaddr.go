@@ -0,0 +1,81 @@
+package interceptors
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/netaddr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// clientAddressKey is the context key under which the resolved client address is stored.
+type clientAddressKey struct{}
+
+// ClientAddress returns a unary interceptor that resolves the network address of the
+// client, for rate limiting, audit events, and login notifications. It must run first,
+// so every other interceptor sees the resolved address.
+//
+// The address is the peer's, unless the peer is a trusted proxy. x-forwarded-for is
+// then read from the right, skipping trusted proxies: entries further left were added
+// by the client or untrusted hops and could be forged.
+//
+// Parameters:
+//   - trusted: addresses of the proxies in front of the server; x-forwarded-for is ignored if empty
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: interceptor storing the client address in the context
+func ClientAddress(trusted []netip.Prefix) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		p, ok := peer.FromContext(ctx)
+		if !ok || p.Addr == nil {
+			return handler(ctx, req)
+		}
+
+		addr := p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+
+		addr = resolveForwarded(addr, trusted, metadata.ValueFromIncomingContext(ctx, "x-forwarded-for"))
+
+		return handler(context.WithValue(ctx, clientAddressKey{}, addr), req)
+	}
+}
+
+// resolveForwarded returns the right-most address of the chain made of the forwarded
+// addresses and the peer that is not a trusted proxy. Parsing stops at the first
+// invalid entry, and the last address parsed is used.
+func resolveForwarded(peerAddr string, trusted []netip.Prefix, forwarded []string) string {
+	var hops []string
+	for _, value := range forwarded {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+
+	client := peerAddr
+
+	for i := len(hops); ; i-- {
+		addr, err := netip.ParseAddr(client)
+		if err != nil || !netaddr.Contains(trusted, addr) || i == 0 {
+			return client
+		}
+
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i-1]))
+		if err != nil {
+			return client
+		}
+
+		client = hop.Unmap().String()
+	}
+}
+
+// ClientAddressFromContext returns the address of the client resolved by ClientAddress,
+// or an empty string if it is unknown.
+func ClientAddressFromContext(ctx context.Context) string {
+	addr, _ := ctx.Value(clientAddressKey{}).(string)
+
+	return addr
+}
@@ -0,0 +1,168 @@
+package interceptors
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// Priority is the class of a method under overload. When the server is busy, calls of
+// lower priority are shed first, keeping the latency of higher priority calls stable.
+type Priority int
+
+const (
+	// PriorityLow is for calls that can wait, such as admin calls. They are shed once
+	// half of the in-flight limit is in use.
+	PriorityLow Priority = iota
+	// PriorityNormal is for calls not classified otherwise, such as registration. They are
+	// shed once three quarters of the in-flight limit are in use.
+	PriorityNormal
+	// PriorityHigh is for calls signing users in. They are shed once 90% of the in-flight
+	// limit is in use.
+	PriorityHigh
+	// PriorityCritical is for calls validating tokens, which the rest of the platform makes
+	// on every request. They are shed only when the in-flight limit is reached.
+	PriorityCritical
+)
+
+// String returns the name of p as logged with shed calls.
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	case PriorityCritical:
+		return "critical"
+	default:
+		return "normal"
+	}
+}
+
+// limit returns how many calls may be in flight when a call of priority p is admitted.
+func (p Priority) limit(maxInFlight int) int64 {
+	var percent int
+	switch p {
+	case PriorityLow:
+		percent = 50
+	case PriorityHigh:
+		percent = 90
+	case PriorityCritical:
+		percent = 100
+	default:
+		percent = 75
+	}
+
+	return max(int64(maxInFlight*percent/100), 1)
+}
+
+// ConcurrencyLimit returns a unary interceptor that bounds the number of calls handled
+// at once, so a spike cannot exhaust memory or CPU. Calls over a limit are shed right
+// away rather than queued. It should run first, so shed calls cost as little as possible.
+//
+// Each method is admitted only while fewer calls are in flight than its priority allows,
+// so lower priority calls are shed first as load grows. Calls to hashingMethods, which
+// hash passwords with bcrypt, also count against their own limit.
+//
+// Parameters:
+//   - log: logger for shed calls
+//   - maxInFlight: maximum number of calls handled at once; 0 disables the limit
+//   - maxHashing: maximum number of calls to hashingMethods handled at once; 0 disables the limit
+//   - hashingMethods: full gRPC method names that hash passwords
+//   - priorities: priority of full gRPC method names; other methods are PriorityNormal
+//   - retryAfter: delay suggested to shed clients in the RetryInfo error detail
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: interceptor enforcing the limits
+//
+// Possible errors returned to clients:
+//   - codes.ResourceExhausted: if a limit is reached, with a RetryInfo detail
+func ConcurrencyLimit(
+	log *slog.Logger,
+	maxInFlight int,
+	maxHashing int,
+	hashingMethods []string,
+	priorities map[string]Priority,
+	retryAfter time.Duration,
+) grpc.UnaryServerInterceptor {
+	var inFlight atomic.Int64
+
+	var hashing chan struct{}
+	if maxHashing > 0 {
+		hashing = make(chan struct{}, maxHashing)
+	}
+
+	hashed := make(map[string]struct{}, len(hashingMethods))
+	for _, m := range hashingMethods {
+		hashed[m] = struct{}{}
+	}
+
+	overloaded := RetryStatus(codes.ResourceExhausted, "server is overloaded, retry later", retryAfter)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		const op = "interceptors.ConcurrencyLimit"
+
+		if maxInFlight > 0 {
+			priority, ok := priorities[info.FullMethod]
+			if !ok {
+				priority = PriorityNormal
+			}
+
+			if n := inFlight.Add(1); n > priority.limit(maxInFlight) {
+				inFlight.Add(-1)
+
+				log.Warn("call shed: too many calls in flight",
+					slog.String("op", op),
+					slog.String("method", info.FullMethod),
+					slog.String("priority", priority.String()),
+					slog.Int64("in_flight", n-1),
+					slog.Int("max_in_flight", maxInFlight),
+				)
+
+				return nil, overloaded.Err()
+			}
+			defer inFlight.Add(-1)
+		}
+
+		if _, ok := hashed[info.FullMethod]; ok {
+			if !acquire(hashing) {
+				log.Warn("call shed: too many password hashing calls in flight",
+					slog.String("op", op),
+					slog.String("method", info.FullMethod),
+					slog.Int("max_in_flight_hashing", maxHashing),
+				)
+
+				return nil, overloaded.Err()
+			}
+			defer release(hashing)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// acquire takes a slot of the semaphore without waiting and reports whether one was free.
+// A nil semaphore is unlimited.
+func acquire(sem chan struct{}) bool {
+	if sem == nil {
+		return true
+	}
+
+	select {
+	case sem <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// release frees a slot taken by acquire.
+func release(sem chan struct{}) {
+	if sem != nil {
+		<-sem
+	}
+}
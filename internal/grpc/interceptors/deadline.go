@@ -0,0 +1,53 @@
+package interceptors
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Deadline returns a unary interceptor that shortens the deadline the caller sent in
+// grpc-timeout by overhead before the call is handled. Storage queries and outbound
+// calls such as emails receive the shortened context, so the server stops working and
+// responds before the caller gives up, rather than finishing work nobody waits for.
+// Calls without a deadline are passed through unchanged.
+//
+// Parameters:
+//   - log: logger for rejected calls
+//   - overhead: time reserved for returning the response; 0 disables the interceptor
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: interceptor shortening deadlines
+//
+// Possible errors returned to clients:
+//   - codes.DeadlineExceeded: if no more than overhead is left when the call arrives
+func Deadline(log *slog.Logger, overhead time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		const op = "interceptors.Deadline"
+
+		deadline, ok := ctx.Deadline()
+		if !ok || overhead <= 0 {
+			return handler(ctx, req)
+		}
+
+		budget := deadline.Add(-overhead)
+		if !time.Now().Before(budget) {
+			log.Debug("call rejected: deadline too close",
+				slog.String("op", op),
+				slog.String("method", info.FullMethod),
+				slog.Duration("left", time.Until(deadline)),
+			)
+
+			return nil, status.Error(codes.DeadlineExceeded, "deadline too close to handle the call")
+		}
+
+		ctx, cancel := context.WithDeadline(ctx, budget)
+		defer cancel()
+
+		return handler(ctx, req)
+	}
+}
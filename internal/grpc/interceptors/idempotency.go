@@ -0,0 +1,209 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// IdempotencyStore defines the interface used to persist idempotency keys and responses.
+type IdempotencyStore interface {
+	// ReserveIdempotencyKey records that a request with the key is being processed.
+	ReserveIdempotencyKey(ctx context.Context, method string, key string, requestHash []byte, expiresAt time.Time) error
+	// IdempotencyRecord retrieves the unexpired record of a key.
+	IdempotencyRecord(ctx context.Context, method string, key string) (*models.IdempotencyRecord, error)
+	// SaveIdempotentResponse stores the response produced for a reserved key.
+	SaveIdempotentResponse(ctx context.Context, method string, key string, response []byte) error
+	// DeleteIdempotencyKey releases a reserved key so the request can be retried.
+	DeleteIdempotencyKey(ctx context.Context, method string, key string) error
+}
+
+const (
+	// idempotencyKeyHeader is the metadata key carrying the client-supplied idempotency key
+	idempotencyKeyHeader = "idempotency-key"
+	// maxIdempotencyKeyLength bounds the size of stored keys
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency returns a unary interceptor that makes the listed methods safe to retry
+// and hedge. When a request carries an "idempotency-key" metadata value, the first
+// successful response is stored and replayed for every later request with the same key
+// until ttl elapses. Requests without a key pass through untouched.
+//
+// Parameters:
+//   - log: logger for idempotency events
+//   - store: persistence for keys and responses
+//   - ttl: how long a key and its response are retained
+//   - methods: full gRPC method names that honor idempotency keys
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: interceptor enforcing idempotency
+//
+// Possible errors returned to clients:
+//   - codes.InvalidArgument: if the key is too long or reused with a different request
+//   - codes.Aborted: if a request with the same key is still in progress
+//   - codes.Internal: if the key cannot be stored or the response replayed
+func Idempotency(log *slog.Logger, store IdempotencyStore, ttl time.Duration, methods []string) grpc.UnaryServerInterceptor {
+	enabled := make(map[string]struct{}, len(methods))
+	for _, m := range methods {
+		enabled[m] = struct{}{}
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := enabled[info.FullMethod]; !ok {
+			return handler(ctx, req)
+		}
+
+		key := idempotencyKey(ctx)
+		if key == "" {
+			return handler(ctx, req)
+		}
+
+		const op = "interceptors.Idempotency"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("method", info.FullMethod),
+		)
+
+		if len(key) > maxIdempotencyKeyLength {
+			return nil, status.Error(codes.InvalidArgument, "idempotency key is too long")
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			log.Error("failed to marshal request", slog.String("error", err.Error()))
+
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+
+		requestHash := sha256.Sum256(payload)
+
+		err = store.ReserveIdempotencyKey(ctx, info.FullMethod, key, requestHash[:], time.Now().Add(ttl))
+		if err != nil {
+			if errors.Is(err, storage.ErrIdempotencyKeyExists) {
+				return replay(ctx, log, store, info.FullMethod, key, requestHash[:])
+			}
+
+			log.Error("failed to reserve idempotency key", slog.String("error", err.Error()))
+
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			// The request had no lasting effect, so a retry with the same key must run it again.
+			if delErr := store.DeleteIdempotencyKey(context.WithoutCancel(ctx), info.FullMethod, key); delErr != nil {
+				log.Error("failed to release idempotency key", slog.String("error", delErr.Error()))
+			}
+
+			return nil, err
+		}
+
+		if err := saveResponse(context.WithoutCancel(ctx), store, info.FullMethod, key, resp); err != nil {
+			log.Error("failed to save idempotent response", slog.String("error", err.Error()))
+
+			// Leaving the key reserved would block every retry with Aborted until it expires.
+			if delErr := store.DeleteIdempotencyKey(context.WithoutCancel(ctx), info.FullMethod, key); delErr != nil {
+				log.Error("failed to release idempotency key", slog.String("error", delErr.Error()))
+			}
+		}
+
+		return resp, nil
+	}
+}
+
+// replay returns the stored response of a request previously made with the same key.
+func replay(ctx context.Context, log *slog.Logger, store IdempotencyStore, method string, key string, requestHash []byte) (any, error) {
+	record, err := store.IdempotencyRecord(ctx, method, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrIdempotencyKeyNotFound) {
+			// The original request failed or expired between our reservation attempt and now.
+			return nil, status.Error(codes.Aborted, "request with the same idempotency key is in progress")
+		}
+
+		log.Error("failed to get idempotency record", slog.String("error", err.Error()))
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	if subtle.ConstantTimeCompare(record.RequestHash, requestHash) != 1 {
+		return nil, status.Error(codes.InvalidArgument, "idempotency key was used with a different request")
+	}
+
+	if record.Response == nil {
+		return nil, status.Error(codes.Aborted, "request with the same idempotency key is in progress")
+	}
+
+	var wrapped anypb.Any
+
+	if err := proto.Unmarshal(record.Response, &wrapped); err != nil {
+		log.Error("failed to unmarshal idempotent response", slog.String("error", err.Error()))
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp, err := wrapped.UnmarshalNew()
+	if err != nil {
+		log.Error("failed to unmarshal idempotent response", slog.String("error", err.Error()))
+
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	log.Info("replayed idempotent response")
+
+	return resp, nil
+}
+
+// saveResponse stores resp for later replay under the given key.
+func saveResponse(ctx context.Context, store IdempotencyStore, method string, key string, resp any) error {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return errors.New("response is not a protobuf message")
+	}
+
+	wrapped, err := anypb.New(msg)
+	if err != nil {
+		return err
+	}
+
+	data, err := proto.Marshal(wrapped)
+	if err != nil {
+		return err
+	}
+
+	return store.SaveIdempotentResponse(ctx, method, key, data)
+}
+
+// idempotencyKey extracts the idempotency key from incoming request metadata.
+// Returns an empty string if no key is present.
+func idempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(idempotencyKeyHeader)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
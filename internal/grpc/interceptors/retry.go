@@ -0,0 +1,33 @@
+package interceptors
+
+import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RetryStatus returns a status carrying a RetryInfo error detail that suggests how long
+// clients should wait before retrying, so generated clients and the Go client back off
+// correctly. It is meant for codes.ResourceExhausted and codes.Unavailable, whose calls
+// changed nothing and succeed once the server recovers.
+//
+// Parameters:
+//   - code: status code, e.g. codes.Unavailable
+//   - msg: status message
+//   - delay: delay suggested to clients before retrying
+//
+// Returns:
+//   - *status.Status: the status with the RetryInfo detail
+func RetryStatus(code codes.Code, msg string, delay time.Duration) *status.Status {
+	st := status.New(code, msg)
+
+	// Adding details only fails for messages that cannot be marshaled, which RetryInfo always can.
+	if withRetry, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}); err == nil {
+		st = withRetry
+	}
+
+	return st
+}
@@ -0,0 +1,37 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SLORecorder defines the interface used to track service level objectives.
+type SLORecorder interface {
+	// Record counts a call of a method, with the time it took and the code it returned.
+	Record(fullMethod string, duration time.Duration, code codes.Code)
+}
+
+// SLO returns a unary interceptor that records the duration and status code of every call
+// against the objectives of its method. It must run first so the duration includes the
+// time spent in the other interceptors and calls they reject are counted.
+//
+// Parameters:
+//   - recorder: SLO tracking implementation
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: interceptor recording calls
+func SLO(recorder SLORecorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		recorder.Record(info.FullMethod, time.Since(start), status.Code(err))
+
+		return resp, err
+	}
+}
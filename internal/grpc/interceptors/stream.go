@@ -0,0 +1,104 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/kirinyoku/sso-grpc/internal/services/admin"
+	"github.com/kirinyoku/sso-grpc/internal/services/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// StreamAuthorization returns a stream interceptor that requires a valid token of any user
+// for every streaming method listed in userMethods, like Authorization does for unary ones.
+// Support tokens are rejected, since every streaming method acts on behalf of the caller.
+// Other methods pass through untouched.
+//
+// Parameters:
+//   - log: logger for authorization events
+//   - validator: token validation implementation
+//   - userMethods: full gRPC method names of streaming methods requiring a signed-in user
+//
+// Returns:
+//   - grpc.StreamServerInterceptor: interceptor enforcing the policy
+//
+// Possible errors returned to clients:
+//   - codes.Unauthenticated: if the token is missing or invalid
+//   - codes.PermissionDenied: if a support token is used
+//   - codes.Internal: if the check itself fails
+func StreamAuthorization(log *slog.Logger, validator TokenValidator, userMethods []string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !slices.Contains(userMethods, info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx := ss.Context()
+
+		token := bearerToken(ctx)
+		if token == "" {
+			return status.Error(codes.Unauthenticated, "missing token")
+		}
+
+		if strings.HasPrefix(token, admin.SupportTokenPrefix) {
+			log.Warn("support token used for a streaming method",
+				slog.String("op", "interceptors.StreamAuthorization"),
+				slog.String("method", info.FullMethod),
+			)
+
+			return status.Error(codes.PermissionDenied, "support tokens cannot call this method")
+		}
+
+		claims, err := validator.ValidateToken(ctx, token)
+		if err != nil {
+			if errors.Is(err, auth.ErrInvalidToken) {
+				return status.Error(codes.Unauthenticated, "invalid token")
+			}
+
+			return status.Error(codes.Internal, "internal error")
+		}
+
+		return handler(srv, &contextStream{ServerStream: ss, ctx: context.WithValue(ctx, claimsKey{}, claims)})
+	}
+}
+
+// StreamRequireTLS returns a stream interceptor that rejects calls to the listed streaming
+// methods unless the connection is secured with TLS, like RequireTLS does for unary ones.
+// It must run before StreamAuthorization.
+func StreamRequireTLS(log *slog.Logger, methods []string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		if p, ok := peer.FromContext(ss.Context()); ok {
+			if _, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+				return handler(srv, ss)
+			}
+		}
+
+		log.Warn("rejected call over insecure transport",
+			slog.String("op", "interceptors.StreamRequireTLS"),
+			slog.String("method", info.FullMethod),
+		)
+
+		return status.Error(codes.PermissionDenied, "method requires a TLS connection")
+	}
+}
+
+// contextStream is a server stream whose context carries values added by interceptors.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream with the values added by interceptors.
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
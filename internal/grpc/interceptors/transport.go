@@ -0,0 +1,66 @@
+package interceptors
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/authzpolicy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RequireTLS returns a unary interceptor that rejects calls to methods the policy does
+// not leave anonymous, and to the listed methods whatever their level, unless the
+// connection is secured with TLS or mTLS, as reported by the peer's auth info. Levels are
+// looked up on every call, so reloads of the policy apply. It must run before
+// Authorization, so the request is refused before its token is used. Other methods pass
+// through untouched.
+//
+// Parameters:
+//   - log: logger for rejected calls
+//   - policy: levels of the methods; all but anonymous ones may only be called over TLS
+//   - credentialMethods: full gRPC method names carrying passwords, tokens, or secrets,
+//     which may only be called over TLS even when anonymous
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: interceptor enforcing the policy
+//
+// Possible errors returned to clients:
+//   - codes.PermissionDenied: if the connection is not secured with TLS
+func RequireTLS(log *slog.Logger, policy MethodPolicy, credentialMethods []string) grpc.UnaryServerInterceptor {
+	carrying := make(map[string]struct{}, len(credentialMethods))
+	for _, m := range credentialMethods {
+		carrying[m] = struct{}{}
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		_, carriesCredentials := carrying[info.FullMethod]
+		if !carriesCredentials && policy.Level(info.FullMethod) == authzpolicy.LevelAnonymous {
+			return handler(ctx, req)
+		}
+
+		const op = "interceptors.RequireTLS"
+
+		p, ok := peer.FromContext(ctx)
+		if ok {
+			if _, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+				return handler(ctx, req)
+			}
+		}
+
+		attrs := []any{
+			slog.String("op", op),
+			slog.String("method", info.FullMethod),
+		}
+		if p != nil && p.Addr != nil {
+			attrs = append(attrs, slog.String("peer", p.Addr.String()))
+		}
+
+		log.Warn("rejected call over insecure transport", attrs...)
+
+		return nil, status.Error(codes.PermissionDenied, "method requires a TLS connection")
+	}
+}
@@ -0,0 +1,35 @@
+package interceptors
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// UsageRecorder defines the interface used to account for authenticated requests.
+type UsageRecorder interface {
+	// Record counts a request made for an app by a user.
+	Record(appID int32, userID int64, failed bool)
+}
+
+// Usage returns a unary interceptor that counts every call made with a user's token
+// for the app and user the token was issued for, and whether it failed.
+// It must run after Authorization so the caller is known. Calls without a token,
+// made with a support token, or rejected by Authorization are not counted.
+//
+// Parameters:
+//   - recorder: usage accounting implementation
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: interceptor counting requests
+func Usage(recorder UsageRecorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+
+		if claims, ok := ClaimsFromContext(ctx); ok {
+			recorder.Record(claims.AppID, claims.UserID, err != nil)
+		}
+
+		return resp, err
+	}
+}
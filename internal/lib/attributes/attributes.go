@@ -0,0 +1,143 @@
+// Package attributes validates the custom key/value attributes of users, such as
+// department or clearance, against the types declared for them.
+package attributes
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidKey is returned when an attribute key is not a lowercase identifier.
+	ErrInvalidKey = errors.New("invalid attribute key")
+
+	// ErrInvalidValue is returned when an attribute value does not match the type declared for its key.
+	ErrInvalidValue = errors.New("invalid attribute value")
+
+	// ErrTooMany is returned when a user is given more than MaxPerUser attributes.
+	ErrTooMany = errors.New("too many attributes")
+)
+
+const (
+	// MaxPerUser is the maximum number of attributes a user can have.
+	MaxPerUser = 32
+	// MaxValueLength is the maximum length of an attribute value in bytes.
+	MaxValueLength = 256
+)
+
+// keyPattern matches valid attribute keys: a lowercase letter followed by up to 63
+// lowercase letters, digits, or underscores.
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// Type is the type of the values an attribute accepts.
+type Type string
+
+const (
+	// TypeString accepts any value up to MaxValueLength bytes.
+	TypeString Type = "string"
+	// TypeInt accepts base-10 integers.
+	TypeInt Type = "int"
+	// TypeBool accepts "true" and "false".
+	TypeBool Type = "bool"
+)
+
+// Valid reports whether t is a known type.
+func (t Type) Valid() bool {
+	switch t {
+	case TypeString, TypeInt, TypeBool:
+		return true
+	default:
+		return false
+	}
+}
+
+// Schema maps attribute keys to the types of their values.
+// Keys it does not declare accept strings.
+type Schema map[string]Type
+
+// NewSchema builds a schema from configured key types.
+//
+// Parameters:
+//   - types: type name of each declared key, e.g. {"clearance": "int"}
+//
+// Returns:
+//   - Schema: schema ready to validate attributes
+//   - error: non-nil if a key is invalid or a type is unknown
+func NewSchema(types map[string]string) (Schema, error) {
+	schema := make(Schema, len(types))
+
+	for key, name := range types {
+		if !keyPattern.MatchString(key) {
+			return nil, fmt.Errorf("%q: %w", key, ErrInvalidKey)
+		}
+
+		t := Type(name)
+		if !t.Valid() {
+			return nil, fmt.Errorf("%q: unknown attribute type %q", key, name)
+		}
+
+		schema[key] = t
+	}
+
+	return schema, nil
+}
+
+// Normalize validates attributes against the schema and returns them in canonical form:
+// integers without leading zeros or a plus sign, and booleans as "true" or "false".
+// Equal values are then stored identically, so they can be queried by exact match.
+//
+// Returns ErrInvalidKey, ErrInvalidValue, or ErrTooMany, wrapped with the offending key.
+func (s Schema) Normalize(attrs map[string]string) (map[string]string, error) {
+	if len(attrs) > MaxPerUser {
+		return nil, ErrTooMany
+	}
+
+	res := make(map[string]string, len(attrs))
+
+	for key, value := range attrs {
+		n, err := s.NormalizeValue(key, value)
+		if err != nil {
+			return nil, err
+		}
+
+		res[key] = n
+	}
+
+	return res, nil
+}
+
+// NormalizeValue validates a single attribute value against the type declared for its key
+// and returns it in canonical form.
+//
+// Returns ErrInvalidKey or ErrInvalidValue, wrapped with the key.
+func (s Schema) NormalizeValue(key string, value string) (string, error) {
+	if !keyPattern.MatchString(key) {
+		return "", fmt.Errorf("%q: %w", key, ErrInvalidKey)
+	}
+
+	if len(value) > MaxValueLength || strings.ContainsRune(value, 0) {
+		return "", fmt.Errorf("%q: %w", key, ErrInvalidValue)
+	}
+
+	switch s[key] {
+	case TypeInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%q: %w", key, ErrInvalidValue)
+		}
+
+		return strconv.FormatInt(n, 10), nil
+	case TypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%q: %w", key, ErrInvalidValue)
+		}
+
+		return strconv.FormatBool(b), nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,221 @@
+// Package authzpolicy maps gRPC methods to the auth level their callers need. Built-in
+// levels can be overridden per method by a YAML policy file, which is reloaded while the
+// service runs, so access can be tightened or granted without a release.
+//
+// A policy file lists full method names under methods:
+//
+//	methods:
+//	  /auth.Auth/GetServiceStatus: authenticated
+//	  /admin.Admin/GetStats: scope:stats.read
+package authzpolicy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Level is the auth level a method requires of its callers.
+type Level string
+
+const (
+	// LevelAnonymous lets anyone call a method, without a token.
+	LevelAnonymous Level = "anonymous"
+	// LevelAuthenticated requires a valid token of any user.
+	LevelAuthenticated Level = "authenticated"
+	// LevelAdmin requires a valid token of an admin.
+	LevelAdmin Level = "admin"
+)
+
+// scopePrefix starts levels requiring a token granted a scope, e.g. "scope:stats.read".
+const scopePrefix = "scope:"
+
+// ScopeLevel returns the level requiring a valid token granted scope.
+func ScopeLevel(scope string) Level {
+	return Level(scopePrefix + scope)
+}
+
+// Scope returns the scope a level requires, and whether it requires one.
+func (l Level) Scope() (string, bool) {
+	return strings.CutPrefix(string(l), scopePrefix)
+}
+
+// Valid reports whether l is a known level, or requires a non-empty scope without whitespace.
+func (l Level) Valid() bool {
+	switch l {
+	case LevelAnonymous, LevelAuthenticated, LevelAdmin:
+		return true
+	}
+
+	scope, ok := l.Scope()
+
+	return ok && scope != "" && !strings.ContainsFunc(scope, unicode.IsSpace)
+}
+
+var (
+	// ErrUnknownMethod is returned when a policy file names a method the service does not serve.
+	ErrUnknownMethod = errors.New("unknown method")
+
+	// ErrInvalidLevel is returned when a policy file requires an unknown level.
+	ErrInvalidLevel = errors.New("invalid level")
+)
+
+// file is the content of a policy file.
+type file struct {
+	Methods map[string]Level `yaml:"methods"` // levels by full method name, e.g. "/admin.Admin/GetStats"
+}
+
+// Policy holds the level each method requires, safe for concurrent use.
+type Policy struct {
+	log      *slog.Logger
+	path     string              // policy file overriding defaults; empty to use only defaults
+	defaults map[string]Level    // built-in levels by full method name
+	known    map[string]struct{} // methods a policy file may name
+	mu       sync.RWMutex
+	levels   map[string]Level // levels in force: defaults overridden by the file
+	content  []byte           // content of the file the levels were loaded from
+}
+
+// New creates a policy from the built-in levels, overridden by the policy file at path.
+//
+// Parameters:
+//   - log: logger for reload events
+//   - path: policy file overriding the built-in levels; empty to use only them
+//   - defaults: built-in levels by full method name; methods not listed are anonymous
+//   - methods: full names of the methods a policy file may name
+//
+// Returns:
+//   - *Policy: policy ready to use
+//   - error: non-nil if the file cannot be read, is not valid YAML, or names an unknown
+//     method (ErrUnknownMethod) or level (ErrInvalidLevel)
+func New(log *slog.Logger, path string, defaults map[string]Level, methods []string) (*Policy, error) {
+	const op = "authzpolicy.New"
+
+	p := &Policy{
+		log:      log,
+		path:     path,
+		defaults: defaults,
+		known:    make(map[string]struct{}, len(methods)),
+		levels:   defaults,
+	}
+
+	for _, m := range methods {
+		p.known[m] = struct{}{}
+	}
+
+	if path == "" {
+		return p, nil
+	}
+
+	if err := p.load(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return p, nil
+}
+
+// Level returns the level a method requires; methods nothing protects are anonymous.
+func (p *Policy) Level(method string) Level {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if level, ok := p.levels[method]; ok {
+		return level
+	}
+
+	return LevelAnonymous
+}
+
+// Methods returns the methods requiring level, in order of name.
+func (p *Policy) Methods(level Level) []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var methods []string
+	for m, l := range p.levels {
+		if l == level {
+			methods = append(methods, m)
+		}
+	}
+
+	slices.Sort(methods)
+
+	return methods
+}
+
+// Reload reads the policy file again and applies it if it changed. It runs as the
+// authz_policy job every reload interval, and does nothing if no file is configured.
+// On failure the levels in force are kept.
+//
+// Returns:
+//   - error: non-nil if the file cannot be read or is invalid
+func (p *Policy) Reload(ctx context.Context) error {
+	const op = "authzpolicy.Policy.Reload"
+
+	if p.path == "" {
+		return nil
+	}
+
+	if err := p.load(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// load reads and validates the policy file, then replaces the levels in force if the file changed.
+func (p *Policy) load() error {
+	content, err := os.ReadFile(p.path)
+	if err != nil {
+		return err
+	}
+
+	p.mu.RLock()
+	unchanged := p.content != nil && bytes.Equal(content, p.content)
+	p.mu.RUnlock()
+
+	if unchanged {
+		return nil
+	}
+
+	var f file
+	if err := yaml.Unmarshal(content, &f); err != nil {
+		return fmt.Errorf("parse %s: %w", p.path, err)
+	}
+
+	levels := maps.Clone(p.defaults)
+
+	for m, level := range f.Methods {
+		if _, ok := p.known[m]; !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownMethod, m)
+		}
+
+		if !level.Valid() {
+			return fmt.Errorf("%w %q for %s", ErrInvalidLevel, level, m)
+		}
+
+		levels[m] = level
+	}
+
+	p.mu.Lock()
+	p.levels = levels
+	p.content = content
+	p.mu.Unlock()
+
+	p.log.Info("authorization policy loaded",
+		slog.String("path", p.path),
+		slog.Int("overrides", len(f.Methods)),
+	)
+
+	return nil
+}
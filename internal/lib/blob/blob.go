@@ -0,0 +1,125 @@
+// Package blob stores immutable objects, such as avatars, under keys derived from their
+// content. Objects are never modified once stored, so they can be cached indefinitely.
+//
+// Store is implemented by FS for a directory on local or mounted storage. Other backends,
+// such as object stores, plug in by implementing Store.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// ErrNotFound is returned when no object is stored under a key.
+var ErrNotFound = errors.New("object not found")
+
+// ErrInvalidKey is returned for keys that are not lowercase letters, digits, dots, and dashes.
+var ErrInvalidKey = errors.New("invalid key")
+
+// keyPattern matches valid keys. It keeps keys safe to use as file names and URL paths.
+var keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{0,127}$`)
+
+// Store defines the interface of blob storage backends.
+type Store interface {
+	// Put stores an object under a key. Storing the same key again keeps the first object,
+	// which holds the same content, since keys are derived from it.
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Open opens the object stored under a key.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// FS stores objects as files in a directory.
+type FS struct {
+	dir string // directory holding one file per object
+}
+
+// NewFS creates a store in a directory, creating the directory if it does not exist.
+//
+// Parameters:
+//   - dir: directory holding one file per object
+//
+// Returns:
+//   - *FS: store ready to use
+//   - error: non-nil if the directory cannot be created
+func NewFS(dir string) (*FS, error) {
+	const op = "blob.NewFS"
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &FS{dir: dir}, nil
+}
+
+// Put writes an object to a temporary file and renames it into place, so readers never
+// see a partially written object.
+//
+// Possible errors:
+//   - ErrInvalidKey: if the key is not valid
+func (f *FS) Put(ctx context.Context, key string, data []byte) error {
+	const op = "blob.FS.Put"
+
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("%s: %w", op, ErrInvalidKey)
+	}
+
+	path := filepath.Join(f.dir, key)
+
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(f.dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Open opens the file of an object.
+//
+// Possible errors:
+//   - ErrInvalidKey: if the key is not valid
+//   - ErrNotFound: if no object is stored under the key
+func (f *FS) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	const op = "blob.FS.Open"
+
+	if !keyPattern.MatchString(key) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidKey)
+	}
+
+	file, err := os.Open(filepath.Join(f.dir, key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s: %w", op, ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return file, nil
+}
@@ -0,0 +1,184 @@
+// Package cachebus propagates invalidations of in-process caches between replicas of the
+// service. Replicas share the database, so invalidations are published to a table in it,
+// which every replica polls.
+package cachebus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+)
+
+// TopicRoles is the topic of cached admin statuses, keyed by user ID.
+const TopicRoles = "roles"
+
+// TopicTokens is the topic of cached token validation results, which has no key:
+// any revocation invalidates every result.
+const TopicTokens = "tokens"
+
+// TopicAnnouncement is the topic of the cached announcement, which has no key.
+const TopicAnnouncement = "announcement"
+
+// TopicSigningKeys is the topic of the loaded RSA signing keys, which has no key.
+const TopicSigningKeys = "signing_keys"
+
+// pollBatchSize is the maximum number of invalidations read per query.
+const pollBatchSize = 500
+
+// Storage defines the interface used to publish and read invalidations.
+type Storage interface {
+	// SaveCacheInvalidation publishes an invalidation and returns its ID.
+	SaveCacheInvalidation(ctx context.Context, topic string, key string, at time.Time) (int64, error)
+	// CacheInvalidations lists up to limit invalidations with an ID above afterID, in order of ID.
+	CacheInvalidations(ctx context.Context, afterID int64, limit int) ([]models.CacheInvalidation, error)
+	// LastCacheInvalidationID returns the ID of the latest invalidation, or 0 if there is none.
+	LastCacheInvalidationID(ctx context.Context) (int64, error)
+	// DeleteCacheInvalidations removes the invalidations published before a moment.
+	DeleteCacheInvalidations(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Bus delivers cache invalidations to the subscribers of every replica, safe for concurrent use.
+type Bus struct {
+	log          *slog.Logger
+	storage      Storage
+	pollInterval time.Duration // 0 to deliver invalidations only on the publishing replica
+	retention    time.Duration // how long published invalidations are kept
+
+	mu       sync.RWMutex
+	handlers map[string][]func(key string)
+}
+
+// New creates a bus.
+//
+// Parameters:
+//   - log: logger for delivery events
+//   - storage: table the replicas exchange invalidations through
+//   - pollInterval: how often Run reads the invalidations of other replicas; 0 for a single replica,
+//     in which case nothing is written to storage
+//   - retention: how long published invalidations are kept for slower replicas
+//
+// Returns:
+//   - *Bus: bus ready to use
+func New(log *slog.Logger, storage Storage, pollInterval time.Duration, retention time.Duration) *Bus {
+	return &Bus{
+		log:          log,
+		storage:      storage,
+		pollInterval: pollInterval,
+		retention:    retention,
+		handlers:     make(map[string][]func(key string)),
+	}
+}
+
+// Subscribe registers fn to be called with the key of every invalidation of topic.
+// Handlers must be quick, since they run on the publisher's call or the polling loop.
+func (b *Bus) Subscribe(topic string, fn func(key string)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[topic] = append(b.handlers[topic], fn)
+}
+
+// Publish invalidates key of topic on this replica at once, and on the others once they poll.
+// The replica sees its own invalidation again when polling, which is harmless.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - topic: kind of cached data
+//   - key: entry to drop; empty for every entry of the topic
+//
+// Returns:
+//   - error: non-nil if the invalidation cannot be published to the other replicas
+func (b *Bus) Publish(ctx context.Context, topic string, key string) error {
+	const op = "cachebus.Bus.Publish"
+
+	b.deliver(topic, key)
+
+	if b.pollInterval <= 0 {
+		return nil
+	}
+
+	if _, err := b.storage.SaveCacheInvalidation(ctx, topic, key, time.Now()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Run delivers the invalidations published by every replica until ctx is canceled,
+// polling every poll interval, and removes those older than the retention.
+// Invalidations published before Run started are skipped, since caches start empty.
+// It returns immediately if the poll interval is 0.
+func (b *Bus) Run(ctx context.Context) {
+	const op = "cachebus.Bus.Run"
+
+	if b.pollInterval <= 0 {
+		return
+	}
+
+	log := b.log.With(slog.String("op", op))
+
+	lastID, err := b.storage.LastCacheInvalidationID(ctx)
+	if err != nil {
+		log.Error("failed to read latest cache invalidation", slog.String("error", err.Error()))
+	}
+
+	ticker := time.NewTicker(b.pollInterval)
+	defer ticker.Stop()
+
+	lastPrune := time.Time{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		lastID = b.poll(ctx, log, lastID)
+
+		if time.Since(lastPrune) >= b.retention/2 {
+			if deleted, err := b.storage.DeleteCacheInvalidations(ctx, time.Now().Add(-b.retention)); err != nil {
+				log.Error("failed to delete old cache invalidations", slog.String("error", err.Error()))
+			} else if deleted > 0 {
+				log.Info("old cache invalidations deleted", slog.Int64("deleted", deleted))
+			}
+
+			lastPrune = time.Now()
+		}
+	}
+}
+
+// poll delivers the invalidations published after lastID and returns the ID of the last one delivered.
+func (b *Bus) poll(ctx context.Context, log *slog.Logger, lastID int64) int64 {
+	for {
+		invalidations, err := b.storage.CacheInvalidations(ctx, lastID, pollBatchSize)
+		if err != nil {
+			log.Error("failed to read cache invalidations", slog.String("error", err.Error()))
+
+			return lastID
+		}
+
+		for _, inv := range invalidations {
+			b.deliver(inv.Topic, inv.Key)
+			lastID = inv.ID
+		}
+
+		if len(invalidations) < pollBatchSize {
+			return lastID
+		}
+	}
+}
+
+// deliver calls the handlers of topic with key.
+func (b *Bus) deliver(topic string, key string) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, fn := range b.handlers[topic] {
+		fn(key)
+	}
+}
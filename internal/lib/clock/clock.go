@@ -0,0 +1,22 @@
+// Package clock abstracts the time source, so code that depends on the
+// current time can be driven by a fake clock.
+package clock
+
+import "time"
+
+// Clock tells the current time.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// System is the clock of the operating system.
+var System Clock = Func(time.Now)
+
+// Func adapts a function to the Clock interface, e.g. to return a fixed time.
+type Func func() time.Time
+
+// Now returns f().
+func (f Func) Now() time.Time {
+	return f()
+}
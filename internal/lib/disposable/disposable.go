@@ -0,0 +1,166 @@
+// Package disposable detects email addresses hosted by disposable email providers.
+//
+// An embedded blocklist is always available; it can be replaced at runtime by
+// periodically downloading a maintained list, such as
+// https://github.com/disposable-email-domains/disposable-email-domains.
+package disposable
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policy defines how registrations from disposable email providers are handled.
+type Policy string
+
+const (
+	// PolicyAllow accepts disposable email addresses.
+	PolicyAllow Policy = "allow"
+	// PolicyFlag accepts disposable email addresses and flags the user.
+	PolicyFlag Policy = "flag"
+	// PolicyReject rejects disposable email addresses.
+	PolicyReject Policy = "reject"
+)
+
+// Valid reports whether p is a known policy.
+func (p Policy) Valid() bool {
+	switch p {
+	case PolicyAllow, PolicyFlag, PolicyReject:
+		return true
+	default:
+		return false
+	}
+}
+
+// maxListSize limits the size of a downloaded blocklist.
+const maxListSize = 16 << 20
+
+//go:embed domains.txt
+var embedded string
+
+// List is a blocklist of disposable email domains, safe for concurrent use.
+type List struct {
+	log     *slog.Logger
+	url     string       // source of refreshed lists; empty to use only the embedded one
+	client  *http.Client // client used to download the list
+	mu      sync.RWMutex
+	domains map[string]struct{}
+}
+
+// New creates a blocklist initialized with the embedded domains.
+//
+// Parameters:
+//   - log: logger for refresh events
+//   - url: optional URL of a list with one domain per line; empty to use only the embedded list
+//
+// Returns:
+//   - *List: blocklist ready to use
+func New(log *slog.Logger, url string) *List {
+	domains, _ := parse(strings.NewReader(embedded))
+
+	return &List{
+		log:     log,
+		url:     url,
+		client:  &http.Client{Timeout: time.Minute},
+		domains: domains,
+	}
+}
+
+// Contains reports whether domain or one of its parent domains is disposable.
+func (l *List) Contains(domain string) bool {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for domain != "" {
+		if _, ok := l.domains[domain]; ok {
+			return true
+		}
+
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			return false
+		}
+
+		domain = parent
+	}
+
+	return false
+}
+
+// Refresh downloads the list from the configured URL and replaces the current one.
+// It runs as the disposable_emails job every refresh interval, and does nothing if no
+// URL is configured. On failure the current list is kept.
+//
+// Returns:
+//   - error: non-nil if the list cannot be downloaded or is empty
+func (l *List) Refresh(ctx context.Context) error {
+	const op = "disposable.List.Refresh"
+
+	if l.url == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.url, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %s", op, resp.Status)
+	}
+
+	domains, err := parse(io.LimitReader(resp.Body, maxListSize))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(domains) == 0 {
+		return fmt.Errorf("%s: downloaded list is empty", op)
+	}
+
+	l.mu.Lock()
+	l.domains = domains
+	l.mu.Unlock()
+
+	l.log.Info("disposable email domains refreshed", slog.String("op", op), slog.Int("domains", len(domains)))
+
+	return nil
+}
+
+// parse reads one domain per line, skipping blank lines and # comments.
+func parse(r io.Reader) (map[string]struct{}, error) {
+	domains := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		domains[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return domains, nil
+}
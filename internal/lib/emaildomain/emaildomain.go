@@ -0,0 +1,57 @@
+// Package emaildomain provides helpers for matching email addresses against domain lists.
+package emaildomain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidDomain is returned when a value cannot be used as an email domain.
+var ErrInvalidDomain = errors.New("invalid email domain")
+
+// Normalize returns domain in the canonical form used for matching:
+// lowercase, without surrounding spaces or a leading "@".
+func Normalize(domain string) (string, error) {
+	domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+
+	if domain == "" || strings.ContainsAny(domain, "@ \t\r\n") {
+		return "", ErrInvalidDomain
+	}
+
+	return domain, nil
+}
+
+// NormalizeAll normalizes every domain in the list and removes duplicates.
+func NormalizeAll(domains []string) ([]string, error) {
+	res := make([]string, 0, len(domains))
+
+	for _, d := range domains {
+		n, err := Normalize(d)
+		if err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(res, n) {
+			res = append(res, n)
+		}
+	}
+
+	return res, nil
+}
+
+// FromEmail returns the normalized domain of an email address,
+// or an empty string if the address has no domain.
+func FromEmail(email string) string {
+	i := strings.LastIndex(email, "@")
+	if i < 0 {
+		return ""
+	}
+
+	domain, err := Normalize(email[i+1:])
+	if err != nil {
+		return ""
+	}
+
+	return domain
+}
@@ -0,0 +1,78 @@
+// Package enforcement decides whether requests violating a policy are rejected, so that new
+// policies can be dark-launched: run in report-only mode, their violations are logged and
+// counted without affecting anyone, and the impact of enforcing them can be measured first.
+package enforcement
+
+import (
+	"expvar"
+	"sync"
+)
+
+// Mode defines how violations of a policy are handled.
+type Mode string
+
+const (
+	// ModeEnforce rejects requests violating the policy.
+	ModeEnforce Mode = "enforce"
+	// ModeReportOnly only counts violations of the policy and lets requests through.
+	ModeReportOnly Mode = "report_only"
+)
+
+// Valid reports whether m is a known mode.
+func (m Mode) Valid() bool {
+	switch m {
+	case ModeEnforce, ModeReportOnly:
+		return true
+	default:
+		return false
+	}
+}
+
+// violations counts violations by policy, then "enforced" or "reported", so that the numbers
+// reported before a policy is enforced can be compared with the rejections after.
+var (
+	violations   = expvar.NewMap("policy_violations")
+	violationsMu sync.Mutex // serializes adding the counters of a policy
+)
+
+// Violated counts a violation of a policy handled in mode and reports whether the request
+// violating it is rejected: always, unless mode is ModeReportOnly.
+//
+// Parameters:
+//   - policy: name of the policy, as counted in the policy_violations metric
+//   - mode: how violations of the policy are handled
+//
+// Returns:
+//   - bool: true if the request is rejected, false if the violation is only reported
+func Violated(policy string, mode Mode) bool {
+	counts := policyViolations(policy)
+
+	if mode == ModeReportOnly {
+		counts.Add("reported", 1)
+
+		return false
+	}
+
+	counts.Add("enforced", 1)
+
+	return true
+}
+
+// policyViolations returns the violation counters of a policy, adding them on its first violation.
+func policyViolations(policy string) *expvar.Map {
+	if counts, ok := violations.Get(policy).(*expvar.Map); ok {
+		return counts
+	}
+
+	violationsMu.Lock()
+	defer violationsMu.Unlock()
+
+	if counts, ok := violations.Get(policy).(*expvar.Map); ok {
+		return counts
+	}
+
+	counts := new(expvar.Map)
+	violations.Set(policy, counts)
+
+	return counts
+}
@@ -0,0 +1,144 @@
+// Package events is an in-process bus carrying domain events from the services to the
+// subsystems reacting to them, such as metrics and emails, so the code paths producing
+// events need not know who consumes them.
+//
+// Events are delivered synchronously, in the order handlers subscribed, on the caller
+// of Publish. Handlers must be quick; slow work such as sending emails belongs in a
+// goroutine started by the handler. Events are not persisted, and are only seen by the
+// replica publishing them.
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Event is a fact about the domain that subsystems may react to.
+type Event interface {
+	// Name identifies the type of the event, e.g. in logs.
+	Name() string
+}
+
+// Ways a user logged in, reported by LoginSucceeded.
+const (
+	LoginPassword = "password" // email and password
+	LoginPushMFA  = "push_mfa" // email and password, approved from a signed-in device
+	LoginDevice   = "device"   // device authorization grant approved by a signed-in user
+)
+
+// Reasons a password login failed, reported by LoginFailed.
+const (
+	FailureRejected        = "rejected"         // a PreLogin hook rejected the login
+	FailureUnknownUser     = "unknown_user"     // no user has the email
+	FailureInvalidPassword = "invalid_password" // the password does not match
+	FailureMergedAccount   = "merged_account"   // the account was merged into another one
+	FailureFrozenAccount   = "frozen_account"   // the account is frozen
+	FailureInvalidApp      = "invalid_app"      // no app has the ID
+	FailureNotAppMember    = "not_app_member"   // the app requires membership the user lacks
+)
+
+// UserRegistered is published after a user was created, synchronously or by an async registration.
+type UserRegistered struct {
+	UserID   int64
+	PublicID string
+	Email    string
+	AppID    int32 // 0 if the user did not register through an app
+	At       time.Time
+}
+
+// Name returns "user_registered".
+func (UserRegistered) Name() string { return "user_registered" }
+
+// LoginSucceeded is published after a token was issued to a user logging in.
+type LoginSucceeded struct {
+	UserID    int64
+	Email     string
+	AppID     int32
+	Method    string // LoginPassword, LoginPushMFA, or LoginDevice
+	IP        string // empty if unknown
+	UserAgent string // empty if unknown
+	At        time.Time
+}
+
+// Name returns "login_succeeded".
+func (LoginSucceeded) Name() string { return "login_succeeded" }
+
+// LoginFailed is published when a password login is refused. Logins waiting for push
+// approval are neither failed nor succeeded until the user decides.
+type LoginFailed struct {
+	Email  string
+	UserID int64 // 0 if no user has the email
+	AppID  int32
+	Reason string // one of the Failure constants
+	IP     string // empty if unknown
+	At     time.Time
+}
+
+// Name returns "login_failed".
+func (LoginFailed) Name() string { return "login_failed" }
+
+// Bus delivers published events to the handlers subscribed to their type.
+type Bus struct {
+	log *slog.Logger // logger for structured logging
+
+	mu       sync.RWMutex
+	handlers map[string][]func(ctx context.Context, event Event) // handlers by event name
+}
+
+// New creates a new Bus without subscribers.
+//
+// Parameters:
+//   - log: logger instance for structured logging
+//
+// Returns a new *Bus instance ready to use.
+func New(log *slog.Logger) *Bus {
+	return &Bus{
+		log:      log,
+		handlers: make(map[string][]func(ctx context.Context, event Event)),
+	}
+}
+
+// Subscribe registers fn to be called with every event of type E published on b.
+// It is a function rather than a method, since methods cannot have type parameters.
+func Subscribe[E Event](b *Bus, fn func(ctx context.Context, event E)) {
+	var zero E
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[zero.Name()] = append(b.handlers[zero.Name()], func(ctx context.Context, event Event) {
+		fn(ctx, event.(E))
+	})
+}
+
+// Publish delivers event to every handler subscribed to its type. A handler that panics
+// is logged and skipped, so a faulty subscriber cannot fail the operation publishing the event.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	b.mu.RLock()
+	handlers := b.handlers[event.Name()]
+	b.mu.RUnlock()
+
+	for _, fn := range handlers {
+		b.deliver(ctx, event, fn)
+	}
+}
+
+// deliver calls a handler, recovering from its panics.
+func (b *Bus) deliver(ctx context.Context, event Event, fn func(ctx context.Context, event Event)) {
+	const op = "events.Bus.deliver"
+
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.Error("event handler panicked",
+				slog.String("op", op),
+				slog.String("event", event.Name()),
+				slog.String("panic", fmt.Sprint(r)),
+			)
+		}
+	}()
+
+	fn(ctx, event)
+}
@@ -0,0 +1,127 @@
+// Package ids generates public identifiers for new records.
+//
+// Time-ordered random identifiers do not reveal how many records exist and
+// can be generated independently in several regions without coordination.
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Strategy selects how public identifiers are generated.
+type Strategy string
+
+const (
+	// StrategySequential uses the decimal form of the sequential database ID.
+	StrategySequential Strategy = "sequential"
+	// StrategyULID uses ULIDs, e.g. 01ARZ3NDEKTSV4RRFFQ69G5FAV.
+	StrategyULID Strategy = "ulid"
+	// StrategyUUIDv7 uses version 7 UUIDs, e.g. 0190163d-8694-739b-aea5-966c26f8ad91.
+	StrategyUUIDv7 Strategy = "uuidv7"
+)
+
+// Valid reports whether s is a known strategy.
+func (s Strategy) Valid() bool {
+	switch s {
+	case StrategySequential, StrategyULID, StrategyUUIDv7:
+		return true
+	default:
+		return false
+	}
+}
+
+// New generates a public identifier using the strategy.
+//
+// Returns:
+//   - string: the identifier, or an empty string for StrategySequential,
+//     whose identifiers are assigned by the database
+//   - error: non-nil if the strategy is unknown or randomness is unavailable
+func New(s Strategy, now time.Time) (string, error) {
+	const op = "ids.New"
+
+	var (
+		id  string
+		err error
+	)
+
+	switch s {
+	case StrategySequential:
+		return "", nil
+	case StrategyULID:
+		id, err = newULID(now)
+	case StrategyUUIDv7:
+		id, err = newUUIDv7(now)
+	default:
+		return "", fmt.Errorf("%s: unknown strategy %q", op, s)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID returns a ULID: a 48-bit millisecond timestamp followed by
+// 80 random bits, encoded as 26 Crockford base32 characters.
+func newULID(now time.Time) (string, error) {
+	var b [16]byte
+
+	putMillis(b[:6], now)
+
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+
+	// The 128 bits are encoded as 26 5-bit groups, with 2 implicit leading zero bits.
+	out := make([]byte, 26)
+	for i := range out {
+		var v byte
+
+		for bit := i*5 - 2; bit < i*5+3; bit++ {
+			v <<= 1
+
+			if bit >= 0 && b[bit/8]&(0x80>>(bit%8)) != 0 {
+				v |= 1
+			}
+		}
+
+		out[i] = crockford[v]
+	}
+
+	return string(out), nil
+}
+
+// newUUIDv7 returns a version 7 UUID as defined by RFC 9562.
+func newUUIDv7(now time.Time) (string, error) {
+	var b [16]byte
+
+	putMillis(b[:6], now)
+
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+
+	b[6] = b[6]&0x0f | 0x70 // version 7
+	b[8] = b[8]&0x3f | 0x80 // RFC 9562 variant
+
+	h := hex.EncodeToString(b[:])
+
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32], nil
+}
+
+// putMillis writes the 48-bit Unix millisecond timestamp of t to b in big-endian order.
+func putMillis(b []byte, t time.Time) {
+	ms := uint64(t.UnixMilli())
+
+	for i := 5; i >= 0; i-- {
+		b[i] = byte(ms)
+		ms >>= 8
+	}
+}
@@ -0,0 +1,234 @@
+// Package jobs runs the background jobs of a replica, such as delivering the outbox, on
+// their intervals and records the outcome of every run, so operators can check that
+// maintenance actually happens and trigger a run without waiting for the next one.
+//
+// Jobs run on every replica independently; their status is kept in memory and reset
+// when the process restarts.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/clock"
+)
+
+// ErrNotFound is returned for names no job was registered under.
+var ErrNotFound = errors.New("job not found")
+
+// Job describes a job to register with a Scheduler.
+type Job struct {
+	Name        string
+	Description string
+	Interval    time.Duration // time between scheduled runs; must be positive
+
+	// Run performs one run of the job. Runs of a job never overlap.
+	Run func(ctx context.Context) error
+
+	// RunOnStop runs the job a last time when the scheduler stops, e.g. to flush buffered data.
+	RunOnStop bool
+}
+
+// job is a registered job and its status.
+type job struct {
+	Job
+
+	runMu sync.Mutex // serializes runs
+
+	mu     sync.Mutex
+	status models.JobStatus
+}
+
+// Scheduler runs registered jobs on their intervals, safe for concurrent use once Run started.
+type Scheduler struct {
+	log   *slog.Logger // logger for structured logging
+	clock clock.Clock  // source of the current time
+	jobs  []*job       // registered jobs in order of registration
+}
+
+// New creates a new Scheduler. Register jobs with Register, then call Run.
+//
+// Parameters:
+//   - log: logger instance for structured logging
+//   - clk: source of the current time
+//
+// Returns a new *Scheduler instance without jobs.
+func New(log *slog.Logger, clk clock.Clock) *Scheduler {
+	return &Scheduler{
+		log:   log,
+		clock: clk,
+	}
+}
+
+// Register adds a job. It must be called before Run, and names must be unique.
+func (s *Scheduler) Register(j Job) {
+	s.jobs = append(s.jobs, &job{
+		Job: j,
+		status: models.JobStatus{
+			Name:        j.Name,
+			Description: j.Description,
+			Interval:    j.Interval,
+		},
+	})
+}
+
+// Run runs every job immediately and then an interval after each run finished, until ctx
+// is canceled, and returns once the last runs finished.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, j := range s.jobs {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			s.schedule(ctx, j)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// schedule runs a job every interval, counted from the end of the previous run, until ctx is canceled.
+func (s *Scheduler) schedule(ctx context.Context, j *job) {
+	j.mu.Lock()
+	j.status.NextRunAt = s.clock.Now()
+	j.mu.Unlock()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if j.RunOnStop {
+				s.run(context.WithoutCancel(ctx), j)
+			}
+
+			return
+		case <-timer.C:
+		}
+
+		s.run(ctx, j)
+
+		j.mu.Lock()
+		j.status.NextRunAt = s.clock.Now().Add(j.Interval)
+		j.mu.Unlock()
+
+		timer.Reset(j.Interval)
+	}
+}
+
+// run performs one run of a job, after the previous one finished, and records its outcome.
+func (s *Scheduler) run(ctx context.Context, j *job) models.JobStatus {
+	const op = "jobs.Scheduler.run"
+
+	j.runMu.Lock()
+	defer j.runMu.Unlock()
+
+	start := s.clock.Now()
+
+	j.mu.Lock()
+	j.status.Running = true
+	j.status.LastStartedAt = start
+	j.mu.Unlock()
+
+	err := j.Run(ctx)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.status.Running = false
+	j.status.LastDuration = s.clock.Now().Sub(start)
+	j.status.Runs++
+
+	if err != nil {
+		j.status.Failures++
+		j.status.LastError = err.Error()
+
+		s.log.Error("background job failed",
+			slog.String("op", op),
+			slog.String("job", j.Name),
+			slog.String("error", err.Error()),
+		)
+	} else {
+		j.status.LastError = ""
+		j.status.LastSucceededAt = start
+	}
+
+	return j.status
+}
+
+// Jobs returns the status of every job in order of registration.
+func (s *Scheduler) Jobs() []models.JobStatus {
+	statuses := make([]models.JobStatus, 0, len(s.jobs))
+
+	for _, j := range s.jobs {
+		j.mu.Lock()
+		statuses = append(statuses, j.status)
+		j.mu.Unlock()
+	}
+
+	return statuses
+}
+
+// Job returns the status of a job.
+//
+// Possible errors:
+//   - ErrNotFound: if no job is registered under the name
+func (s *Scheduler) Job(name string) (*models.JobStatus, error) {
+	const op = "jobs.Scheduler.Job"
+
+	j, err := s.find(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	status := j.status
+
+	return &status, nil
+}
+
+// RunNow runs a job outside its schedule and waits for the run to finish, first waiting for
+// a run in progress. Scheduled runs are not moved. A failing run is not reported as an error;
+// it is recorded in the returned status like scheduled runs.
+//
+// Parameters:
+//   - ctx: context of the run; canceling it interrupts the run
+//   - name: name of the job
+//
+// Returns:
+//   - *models.JobStatus: status of the job after the run
+//   - error: nil on success, or ErrNotFound if no job is registered under the name
+func (s *Scheduler) RunNow(ctx context.Context, name string) (*models.JobStatus, error) {
+	const op = "jobs.Scheduler.RunNow"
+
+	j, err := s.find(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	status := s.run(ctx, j)
+
+	return &status, nil
+}
+
+// find returns the job registered under a name.
+func (s *Scheduler) find(name string) (*job, error) {
+	for _, j := range s.jobs {
+		if j.Name == name {
+			return j, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
@@ -1,32 +1,488 @@
 // Package jwt provides JWT (JSON Web Token) functionality for authentication and authorization.
+// JWTs are signed with the app secret (HS256) or, for apps opting in, with an RSA key of the
+// service (RS256). Apps can opt into PASETO v4.local tokens instead; parsing detects the
+// format transparently.
 package jwt
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/paseto"
 )
 
-// NewToken generates a JWT token for the specified user and application.
+// ErrInvalidToken is returned when a token is malformed, expired, or has an invalid signature.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims holds the verified claims of a token issued by the SSO service.
+// Tokens of apps with minimal claims carry neither UserID nor Email; callers resolve
+// the user from Subject instead.
+type Claims struct {
+	UserID        int64     // ID of the user the token was issued to; 0 for minimal tokens
+	Subject       string    // public ID of the user; empty for tokens issued before public IDs
+	Region        string    // region of the issuing deployment; empty if not configured or minimal
+	AppID         int32     // ID of the application the token was issued for
+	Email         string    // email of the user the token was issued to; empty for minimal tokens
+	IssuedAt      time.Time // moment the token was issued; zero for tokens issued before it was recorded
+	ExpiresAt     time.Time // moment after which the token is no longer valid
+	AuthTime      time.Time // moment the user last authenticated; zero if the token does not record it
+	AMR           []string  // methods the user authenticated with, e.g. AMRPassword; empty if not recorded
+	ACR           string    // authentication context class the user reached, e.g. ACRMultiFactor; empty if not recorded
+	Scopes        []string  // scopes the token was granted; empty for tokens issued at sign-in, which carry none
+	Issuer        string    // issuer of the token; empty if it does not name one
+	ID            string    // unique ID of the token; empty for tokens issued without one
+	ServiceSigned bool      // signed with a key of the service (RS256) rather than an app secret, so no app could have forged it
+	Test          bool      // minted by MintTestToken, carried in the "test" claim; the service itself never accepts it
+}
+
+// Authentication context classes, carried in the "acr" claim.
+const (
+	ACRSingleFactor = "1fa" // the user authenticated with a single factor, such as a password
+	ACRMultiFactor  = "2fa" // the user approved the authentication with a second factor
+)
+
+// Authentication methods, carried in the "amr" claim. The values are registered in RFC 8176.
+const (
+	AMRPassword    = "pwd" // the user entered their password
+	AMRMultiFactor = "mfa" // the user approved the authentication from a signed-in device
+)
+
+// Authentication describes how and when the user proved their identity, for the
+// "auth_time", "amr", and "acr" claims, and the scopes granted with it, for the
+// space-separated "scope" claim of RFC 9068. Test marks tokens minted without any
+// authentication, with the "test" claim. The zero value omits all five claims.
+type Authentication struct {
+	Time    time.Time // moment the user authenticated
+	Methods []string  // methods the user authenticated with
+	ACR     string    // authentication context class the user reached
+	Scopes  []string  // scopes the token is granted
+	Test    bool      // the token is a test token minted with arbitrary claims
+}
+
+// Authentication returns how and when the user authenticated according to the claims,
+// so a refreshed token keeps recording the original authentication and its scopes.
+func (c *Claims) Authentication() Authentication {
+	return Authentication{Time: c.AuthTime, Methods: c.AMR, ACR: c.ACR, Scopes: c.Scopes, Test: c.Test}
+}
+
+// Token formats an app can issue.
+const (
+	FormatJWT      = "jwt"             // HS256-signed JWT
+	FormatJWTRS256 = "jwt_rs256"       // RS256-signed JWT, verifiable with the public keys of the service
+	FormatPASETO   = "paseto_v4_local" // PASETO v4.local, encrypted with a key derived from the app secret
+)
+
+// ErrNoSigningKey is returned when issuing an RS256 token without an RSA key configured.
+var ErrNoSigningKey = errors.New("no RSA signing key configured")
+
+// ValidFormat reports whether format is a known token format.
+func ValidFormat(format string) bool {
+	return format == FormatJWT || format == FormatJWTRS256 || format == FormatPASETO
+}
+
+// StandardClaims selects the optional registered claims of RFC 7519 added to tokens, for
+// middleware expecting them. Tokens always carry "sub", "iat", and "exp".
+type StandardClaims struct {
+	Issuer    string // "iss" claim, e.g. the URL of the service; empty to omit it
+	Audience  bool   // add "aud", the app ID, to full tokens, which otherwise name their app in "app_id"
+	NotBefore bool   // add "nbf", equal to "iat"
+	TokenID   bool   // add "jti", a random ID unique to the token
+}
+
+// tokenIDSize is the number of random bytes of a token ID.
+const tokenIDSize = 16
+
+// newTokenID returns a random token ID for the "jti" claim, or an empty string if std does
+// not add one.
+func newTokenID(std StandardClaims) (string, error) {
+	if !std.TokenID {
+		return "", nil
+	}
+
+	id := make([]byte, tokenIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
+
+// SecretFunc returns the signing secret of the application with the given ID,
+// which is the family secret for apps in a family.
+type SecretFunc func(appID int32) (string, error)
+
+// NewToken generates a token for the specified user and application,
+// in the format selected by app.TokenFormat (a JWT when empty). When app.MinimalClaims
+// is set, the token carries only sub, aud (the app ID), iat, and exp, besides the standard
+// claims selected by std. Otherwise the user's attributes, if any were loaded, are added
+// as the "attrs" claim.
+//
+// Tokens of apps in a family, if the family was loaded, are signed with the family secret.
+// JWTs then list every app of the family in aud; minimal ones name the issuing app in azp.
+// RS256 tokens are signed with the signing key of keys instead, named in the "kid" header.
 //
 // Parameters:
 //   - user: user to generate token for
 //   - app: application to generate token for
+//   - keys: RSA keys signing RS256 tokens; nil if none are configured
+//   - now: moment the token is issued at
 //   - duration: duration for which the token is valid
+//   - region: region of the issuing deployment, added as the "region" claim; empty to omit it
+//   - std: optional standard claims to add
+//   - authn: how and when the user authenticated, added as the "auth_time", "amr", "acr", and
+//     "scope" claims of full and minimal tokens alike; the zero value omits them
 //
 // Returns:
-//   - string: JWT token for authenticated sessions
-//   - error: nil on success, or an error if token generation fails
-func NewToken(user *models.User, app *models.App, duration time.Duration) (string, error) {
+//   - string: token for authenticated sessions
+//   - error: nil on success, ErrNoSigningKey if the app issues RS256 tokens but keys is nil,
+//     or another error if token generation fails
+func NewToken(
+	user *models.User,
+	app *models.App,
+	keys *Keys,
+	now time.Time,
+	duration time.Duration,
+	region string,
+	std StandardClaims,
+	authn Authentication,
+) (string, error) {
+	if app.TokenFormat == FormatPASETO {
+		return newPasetoToken(user, app, now, duration, region, std, authn)
+	}
+
+	tokenID, err := newTokenID(std)
+	if err != nil {
+		return "", err
+	}
+
 	token := jwt.New(jwt.SigningMethodHS256)
 
+	var signingKey *rsa.PrivateKey
+
+	if app.TokenFormat == FormatJWTRS256 {
+		if keys == nil {
+			return "", ErrNoSigningKey
+		}
+
+		// The key and its ID are read together, so a rotation meanwhile cannot mismatch them.
+		var kid string
+		signingKey, kid = keys.signer()
+
+		token = jwt.New(jwt.SigningMethodRS256)
+		token.Header["kid"] = kid
+	}
+
 	calims := token.Claims.(jwt.MapClaims)
 
+	if std.Issuer != "" {
+		calims["iss"] = std.Issuer
+	}
+
+	if std.NotBefore {
+		calims["nbf"] = now.Unix()
+	}
+
+	if tokenID != "" {
+		calims["jti"] = tokenID
+	}
+
+	if !authn.Time.IsZero() {
+		calims["auth_time"] = authn.Time.Unix()
+	}
+
+	if len(authn.Methods) > 0 {
+		calims["amr"] = authn.Methods
+	}
+
+	if authn.ACR != "" {
+		calims["acr"] = authn.ACR
+	}
+
+	if len(authn.Scopes) > 0 {
+		calims["scope"] = strings.Join(authn.Scopes, " ")
+	}
+
+	if authn.Test {
+		calims["test"] = true
+	}
+
+	if app.Family != nil {
+		aud := make([]string, len(app.Family.AppIDs))
+		for i, id := range app.Family.AppIDs {
+			aud[i] = strconv.Itoa(int(id))
+		}
+
+		calims["aud"] = aud
+	}
+
+	if app.MinimalClaims {
+		calims["sub"] = user.PublicID
+		calims["iat"] = now.Unix()
+		calims["exp"] = now.Add(duration).Unix()
+
+		if app.Family != nil {
+			calims["azp"] = strconv.Itoa(app.ID)
+		} else {
+			calims["aud"] = strconv.Itoa(app.ID)
+		}
+
+		return signToken(token, app, signingKey)
+	}
+
 	calims["user_id"] = user.ID
+	calims["sub"] = user.PublicID
 	calims["app_id"] = app.ID
 	calims["email"] = user.Email
-	calims["exp"] = time.Now().Add(duration).Unix()
+	calims["iat"] = now.Unix()
+	calims["exp"] = now.Add(duration).Unix()
+
+	if region != "" {
+		calims["region"] = region
+	}
+
+	if std.Audience && app.Family == nil {
+		calims["aud"] = strconv.Itoa(app.ID)
+	}
+
+	if len(user.Attributes) > 0 {
+		calims["attrs"] = user.Attributes
+	}
+
+	return signToken(token, app, signingKey)
+}
+
+// PreviewClaims returns the claims of a token NewToken would issue, so they can be reviewed
+// without handing out a token. The token is built with the same arguments and discarded.
+//
+// Returns:
+//   - []byte: the claims as a JSON object
+//   - error: non-nil if the token cannot be built, e.g. ErrNoSigningKey
+func PreviewClaims(
+	user *models.User,
+	app *models.App,
+	keys *Keys,
+	now time.Time,
+	duration time.Duration,
+	region string,
+	std StandardClaims,
+	authn Authentication,
+) ([]byte, error) {
+	token, err := NewToken(user, app, keys, now, duration, region, std, authn)
+	if err != nil {
+		return nil, err
+	}
+
+	if app.TokenFormat == FormatPASETO {
+		payload, _, err := paseto.Decrypt(pasetoKey(signingSecret(app)), token)
+
+		return payload, err
+	}
+
+	// A JWT is the header, the claims, and the signature, each base64url-encoded.
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed JWT", ErrInvalidToken)
+	}
+
+	return base64.RawURLEncoding.DecodeString(parts[1])
+}
+
+// signToken signs a JWT with signingKey if it is an RS256 token, or else with the signing
+// secret of app.
+func signToken(token *jwt.Token, app *models.App, signingKey *rsa.PrivateKey) (string, error) {
+	if token.Method == jwt.SigningMethodRS256 {
+		return token.SignedString(signingKey)
+	}
+
+	return token.SignedString([]byte(signingSecret(app)))
+}
+
+// signingSecret returns the secret tokens of app are signed with:
+// the family secret if the app's family was loaded, or the app secret.
+func signingSecret(app *models.App) string {
+	if app.Family != nil {
+		return app.Family.Secret
+	}
+
+	return app.Secret
+}
+
+// ParseToken verifies the token signature and expiration and returns its claims.
+// JWT and PASETO tokens are accepted whatever the current format of their app, so that
+// tokens issued before an app changed its format stay valid until they expire.
+//
+// Parameters:
+//   - tokenString: token previously issued by NewToken
+//   - secret: lookup of the signing secret for the app the token claims to be issued for;
+//     it is called for RS256 tokens as well, so the lookup can check that the app exists
+//   - keys: RSA keys RS256 tokens are verified against; nil to reject RS256 tokens
+//   - now: moment the expiration is checked against
+//   - leeway: how long after its expiration, or before its "nbf", the token is accepted, to
+//     tolerate clock skew
+//   - issuer: issuer tokens naming one must name; empty to accept any. Tokens without an
+//     issuer are accepted, since tokens issued before it was configured do not name one
+//
+// Returns:
+//   - *Claims: verified token claims
+//   - error: ErrInvalidToken if the token cannot be verified,
+//     or the error returned by secret if the lookup fails
+func ParseToken(
+	tokenString string,
+	secret SecretFunc,
+	keys *Keys,
+	now time.Time,
+	leeway time.Duration,
+	issuer string,
+) (*Claims, error) {
+	if paseto.IsToken(tokenString) {
+		return parsePasetoToken(tokenString, secret, now, leeway, issuer)
+	}
+
+	var secretErr error
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		appID, ok := tokenAppID(claims)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		s, err := secret(appID)
+		if err != nil {
+			secretErr = err
+			return nil, err
+		}
+
+		if token.Method == jwt.SigningMethodRS256 {
+			kid, _ := token.Header["kid"].(string)
+
+			return keys.publicKey(kid)
+		}
+
+		return []byte(s), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+		jwt.WithTimeFunc(func() time.Time { return now }),
+	)
+	if err != nil {
+		if secretErr != nil {
+			return nil, secretErr
+		}
+
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	claims := token.Claims.(jwt.MapClaims)
+
+	appID, _ := tokenAppID(claims)
+
+	// Tokens issued before public IDs were introduced have no subject.
+	sub, _ := claims["sub"].(string)
+	region, _ := claims["region"].(string)
+	iss, _ := claims["iss"].(string)
+	jti, _ := claims["jti"].(string)
+
+	if issuer != "" && iss != "" && iss != issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, iss)
+	}
+
+	exp, err := claims.GetExpirationTime()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	var issuedAt time.Time
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		issuedAt = iat.Time
+	}
+
+	result := &Claims{
+		Subject:       sub,
+		Region:        region,
+		AppID:         appID,
+		IssuedAt:      issuedAt,
+		ExpiresAt:     exp.Time,
+		Issuer:        iss,
+		ID:            jti,
+		ServiceSigned: token.Method == jwt.SigningMethodRS256,
+	}
+
+	if authTime, ok := claims["auth_time"].(float64); ok {
+		result.AuthTime = time.Unix(int64(authTime), 0)
+	}
+
+	if amr, ok := claims["amr"].([]any); ok {
+		for _, method := range amr {
+			if m, ok := method.(string); ok {
+				result.AMR = append(result.AMR, m)
+			}
+		}
+	}
+
+	result.ACR, _ = claims["acr"].(string)
+
+	if scope, ok := claims["scope"].(string); ok {
+		result.Scopes = strings.Fields(scope)
+	}
+
+	result.Test, _ = claims["test"].(bool)
+
+	// Minimal tokens identify the user by subject only.
+	if _, ok := claims["user_id"]; !ok {
+		if sub == "" {
+			return nil, ErrInvalidToken
+		}
+
+		return result, nil
+	}
+
+	userID, okUser := claims["user_id"].(float64)
+	email, okEmail := claims["email"].(string)
+	if !okUser || !okEmail {
+		return nil, ErrInvalidToken
+	}
+
+	result.UserID = int64(userID)
+	result.Email = email
+
+	return result, nil
+}
+
+// tokenAppID returns the app a JWT was issued for: the app_id claim, or for tokens
+// with minimal claims the authorized party of a family token or the audience.
+func tokenAppID(claims jwt.MapClaims) (int32, bool) {
+	if appID, ok := claims["app_id"].(float64); ok {
+		return int32(appID), true
+	}
+
+	party, ok := claims["azp"].(string)
+	if !ok {
+		aud, err := claims.GetAudience()
+		if err != nil || len(aud) != 1 {
+			return 0, false
+		}
+
+		party = aud[0]
+	}
+
+	appID, err := strconv.ParseInt(party, 10, 32)
+	if err != nil {
+		return 0, false
+	}
 
-	return token.SignedString([]byte(app.Secret))
+	return int32(appID), true
 }
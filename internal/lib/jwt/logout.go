@@ -0,0 +1,55 @@
+package jwt
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+)
+
+// LogoutEvent is the member of the "events" claim that marks a logout token,
+// as defined by OpenID Connect Back-Channel Logout 1.0.
+const LogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// logoutTokenType is the "typ" header of logout tokens, so they cannot be mistaken for ID tokens.
+const logoutTokenType = "logout+jwt"
+
+// NewLogoutToken generates a logout token telling an app that every token issued to the
+// user was revoked. Logout tokens are always JWTs signed like the app's tokens, with the
+// family secret for apps in a loaded family, whatever the app's token format.
+//
+// Parameters:
+//   - user: user whose tokens were revoked; the token names its public ID in sub
+//   - app: application the token is sent to, named in aud along with the rest of its family
+//   - issuer: value of the "iss" claim
+//   - now: moment the token is issued at
+//   - ttl: how long the app may take to receive the token
+//   - jti: value of the "jti" claim, the same for every retry of a delivery so apps can
+//     ignore tokens they already processed
+//
+// Returns:
+//   - string: signed logout token
+//   - error: nil on success, or an error if token generation fails
+func NewLogoutToken(user *models.User, app *models.App, issuer string, now time.Time, ttl time.Duration, jti string) (string, error) {
+	aud := []string{strconv.Itoa(app.ID)}
+	if app.Family != nil {
+		aud = make([]string, len(app.Family.AppIDs))
+		for i, id := range app.Family.AppIDs {
+			aud[i] = strconv.Itoa(int(id))
+		}
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":    issuer,
+		"sub":    user.PublicID,
+		"aud":    aud,
+		"iat":    now.Unix(),
+		"exp":    now.Add(ttl).Unix(),
+		"jti":    jti,
+		"events": map[string]any{LogoutEvent: map[string]any{}},
+	})
+	token.Header["typ"] = logoutTokenType
+
+	return token.SignedString([]byte(signingSecret(app)))
+}
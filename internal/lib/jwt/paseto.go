@@ -0,0 +1,222 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/paseto"
+)
+
+// pasetoClaims is the payload of a PASETO token. Registered claims follow the
+// PASETO specification, so exp is an RFC 3339 timestamp rather than Unix seconds.
+// Minimal tokens carry only sub, aud, iat, exp, how the user authenticated, and the
+// selected standard claims.
+type pasetoClaims struct {
+	UserID    int64             `json:"user_id,omitempty"`
+	Subject   string            `json:"sub"`
+	Audience  string            `json:"aud,omitempty"`
+	AppID     int32             `json:"app_id,omitempty"`
+	Email     string            `json:"email,omitempty"`
+	IssuedAt  string            `json:"iat,omitempty"`
+	ExpiresAt string            `json:"exp"`
+	Region    string            `json:"region,omitempty"`
+	Attrs     map[string]string `json:"attrs,omitempty"`
+	AuthTime  string            `json:"auth_time,omitempty"`
+	AMR       []string          `json:"amr,omitempty"`
+	ACR       string            `json:"acr,omitempty"`
+	Scope     string            `json:"scope,omitempty"`
+	Test      bool              `json:"test,omitempty"`
+	Issuer    string            `json:"iss,omitempty"`
+	NotBefore string            `json:"nbf,omitempty"`
+	TokenID   string            `json:"jti,omitempty"`
+}
+
+// pasetoFooter is the authenticated but unencrypted footer of a PASETO token,
+// used to find the app whose key decrypts it.
+type pasetoFooter struct {
+	AppID int32 `json:"app_id"`
+}
+
+// pasetoKey derives the 32-byte v4.local key of an app from its secret.
+func pasetoKey(secret string) []byte {
+	key := sha256.Sum256([]byte(secret))
+	return key[:]
+}
+
+// newPasetoToken issues a PASETO v4.local token carrying the same claims as a JWT.
+// Tokens of apps in a family are encrypted with the family key, which already limits
+// them to the family, so their audience is not listed.
+func newPasetoToken(
+	user *models.User,
+	app *models.App,
+	now time.Time,
+	duration time.Duration,
+	region string,
+	std StandardClaims,
+	authn Authentication,
+) (string, error) {
+	tokenID, err := newTokenID(std)
+	if err != nil {
+		return "", err
+	}
+
+	claims := pasetoClaims{
+		UserID:    user.ID,
+		Subject:   user.PublicID,
+		AppID:     int32(app.ID),
+		Email:     user.Email,
+		IssuedAt:  now.UTC().Format(time.RFC3339),
+		ExpiresAt: now.Add(duration).UTC().Format(time.RFC3339),
+		Region:    region,
+		Attrs:     user.Attributes,
+	}
+
+	if std.Audience && app.Family == nil {
+		claims.Audience = strconv.Itoa(app.ID)
+	}
+
+	if app.MinimalClaims {
+		claims = pasetoClaims{
+			Subject:   user.PublicID,
+			Audience:  strconv.Itoa(app.ID),
+			IssuedAt:  claims.IssuedAt,
+			ExpiresAt: claims.ExpiresAt,
+		}
+	}
+
+	claims.Issuer = std.Issuer
+	claims.TokenID = tokenID
+
+	if std.NotBefore {
+		claims.NotBefore = claims.IssuedAt
+	}
+
+	if !authn.Time.IsZero() {
+		claims.AuthTime = authn.Time.UTC().Format(time.RFC3339)
+	}
+
+	claims.AMR = authn.Methods
+	claims.ACR = authn.ACR
+	claims.Scope = strings.Join(authn.Scopes, " ")
+	claims.Test = authn.Test
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	footer, err := json.Marshal(pasetoFooter{AppID: int32(app.ID)})
+	if err != nil {
+		return "", err
+	}
+
+	return paseto.Encrypt(pasetoKey(signingSecret(app)), payload, footer)
+}
+
+// parsePasetoToken decrypts a PASETO v4.local token and verifies its expiration and "nbf",
+// allowing for leeway, and its issuer as ParseToken does.
+func parsePasetoToken(tokenString string, secret SecretFunc, now time.Time, leeway time.Duration, issuer string) (*Claims, error) {
+	rawFooter, err := paseto.Footer(tokenString)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	var footer pasetoFooter
+	if err := json.Unmarshal(rawFooter, &footer); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	s, err := secret(footer.AppID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, _, err := paseto.Decrypt(pasetoKey(s), tokenString)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	var claims pasetoClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	// Minimal tokens name the app in the audience and identify the user by subject only.
+	if claims.UserID == 0 {
+		appID, err := strconv.ParseInt(claims.Audience, 10, 32)
+		if err != nil || claims.Subject == "" {
+			return nil, ErrInvalidToken
+		}
+
+		claims.AppID = int32(appID)
+	} else if claims.Email == "" {
+		return nil, ErrInvalidToken
+	}
+
+	// The footer is authenticated, but the app in the payload is authoritative.
+	if claims.AppID != footer.AppID {
+		return nil, ErrInvalidToken
+	}
+
+	exp, err := time.Parse(time.RFC3339, claims.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	var issuedAt time.Time
+	if claims.IssuedAt != "" {
+		issuedAt, err = time.Parse(time.RFC3339, claims.IssuedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+	}
+
+	var authTime time.Time
+	if claims.AuthTime != "" {
+		authTime, err = time.Parse(time.RFC3339, claims.AuthTime)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+	}
+
+	if !now.Before(exp.Add(leeway)) {
+		return nil, fmt.Errorf("%w: token is expired", ErrInvalidToken)
+	}
+
+	if claims.NotBefore != "" {
+		nbf, err := time.Parse(time.RFC3339, claims.NotBefore)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+
+		if now.Add(leeway).Before(nbf) {
+			return nil, fmt.Errorf("%w: token is not valid yet", ErrInvalidToken)
+		}
+	}
+
+	if issuer != "" && claims.Issuer != "" && claims.Issuer != issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, claims.Issuer)
+	}
+
+	return &Claims{
+		UserID:    claims.UserID,
+		Subject:   claims.Subject,
+		Region:    claims.Region,
+		AppID:     claims.AppID,
+		Email:     claims.Email,
+		IssuedAt:  issuedAt,
+		ExpiresAt: exp,
+		AuthTime:  authTime,
+		AMR:       claims.AMR,
+		ACR:       claims.ACR,
+		Scopes:    strings.Fields(claims.Scope),
+		Issuer:    claims.Issuer,
+		ID:        claims.TokenID,
+		Test:      claims.Test,
+	}, nil
+}
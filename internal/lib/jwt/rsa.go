@@ -0,0 +1,285 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+)
+
+// minRSAKeyBits is the smallest RSA modulus accepted for signing tokens.
+const minRSAKeyBits = 2048
+
+// Keys holds the RSA keys RS256 tokens are signed with and verified against. Consumers
+// verify tokens offline against the public keys published as a JSON Web Key Set.
+// Keys are safe for concurrent use and replaced as a whole when they are rotated.
+type Keys struct {
+	mu        sync.RWMutex
+	signing   *rsa.PrivateKey
+	signingID string                    // key ID of the signing key, its RFC 7638 thumbprint
+	public    map[string]*rsa.PublicKey // keys tokens are verified against, by key ID
+}
+
+// JWK is the public part of an RSA key as a JSON Web Key (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"` // base64url-encoded big-endian modulus
+	Exponent  string `json:"e"` // base64url-encoded big-endian public exponent
+}
+
+// NewKeys parses a PEM-encoded RSA private key, in PKCS #1 or PKCS #8 form, to sign tokens with.
+//
+// Parameters:
+//   - privateKeyPEM: PEM block of an RSA private key of at least 2048 bits
+//
+// Returns:
+//   - *Keys: keys signing with the private key and verifying against its public key
+//   - error: non-nil if the PEM does not hold a suitable RSA private key
+func NewKeys(privateKeyPEM []byte) (*Keys, error) {
+	const op = "jwt.NewKeys"
+
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	kid := thumbprint(&key.PublicKey)
+
+	return &Keys{
+		signing:   key,
+		signingID: kid,
+		public:    map[string]*rsa.PublicKey{kid: &key.PublicKey},
+	}, nil
+}
+
+// GenerateSigningKey generates a new RSA key to rotate the signing key to.
+//
+// Returns:
+//   - *models.SigningKey: the key with its ID, private key, and public key set
+//   - error: non-nil if the key cannot be generated
+func GenerateSigningKey() (*models.SigningKey, error) {
+	const op = "jwt.GenerateSigningKey"
+
+	key, err := rsa.GenerateKey(rand.Reader, minRSAKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	privateKey, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	publicKey, err := encodePublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &models.SigningKey{
+		ID:         thumbprint(&key.PublicKey),
+		PrivateKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKey}),
+		PublicKey:  publicKey,
+	}, nil
+}
+
+// SigningKey returns the public part of the key signing tokens, as recorded when it is retired.
+func (k *Keys) SigningKey() (*models.SigningKey, error) {
+	const op = "jwt.Keys.SigningKey"
+
+	key, kid := k.signer()
+
+	publicKey, err := encodePublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &models.SigningKey{ID: kid, PublicKey: publicKey}, nil
+}
+
+// Replace makes a key sign tokens, and verifies tokens against it and a set of other keys
+// from then on. Tokens signed with keys in neither are rejected.
+//
+// Parameters:
+//   - signing: key signing tokens, with its private key
+//   - verifying: other keys tokens are verified against; only their public keys are used
+//
+// Returns:
+//   - error: non-nil if a key cannot be parsed, in which case the keys are left unchanged
+func (k *Keys) Replace(signing *models.SigningKey, verifying []*models.SigningKey) error {
+	const op = "jwt.Keys.Replace"
+
+	key, err := parsePrivateKey(signing.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("%s: key %s: %w", op, signing.ID, err)
+	}
+
+	kid := thumbprint(&key.PublicKey)
+	public := map[string]*rsa.PublicKey{kid: &key.PublicKey}
+
+	for _, v := range verifying {
+		publicKey, err := parsePublicKey(v.PublicKey)
+		if err != nil {
+			return fmt.Errorf("%s: key %s: %w", op, v.ID, err)
+		}
+
+		public[thumbprint(publicKey)] = publicKey
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.signing = key
+	k.signingID = kid
+	k.public = public
+
+	return nil
+}
+
+// signer returns the key signing tokens with its key ID.
+func (k *Keys) signer() (*rsa.PrivateKey, string) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.signing, k.signingID
+}
+
+// parsePrivateKey parses a PEM-encoded RSA private key, in PKCS #1 or PKCS #8 form, of at
+// least minRSAKeyBits bits.
+func parsePrivateKey(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var key *rsa.PrivateKey
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		key = parsed
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("not an RSA private key")
+		}
+
+		key = rsaKey
+	default:
+		return nil, fmt.Errorf("unexpected PEM block %q", block.Type)
+	}
+
+	if key.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("key has %d bits, at least %d are required", key.N.BitLen(), minRSAKeyBits)
+	}
+
+	return key, nil
+}
+
+// parsePublicKey parses a PEM-encoded RSA public key in PKIX form.
+func parsePublicKey(publicKeyPEM []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("no public key PEM block found")
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("not an RSA public key")
+	}
+
+	return key, nil
+}
+
+// encodePublicKey encodes an RSA public key as a PKIX PEM block.
+func encodePublicKey(key *rsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// JWKS returns the public keys tokens are verified against, for publishing as a JSON Web Key Set.
+func (k *Keys) JWKS() []JWK {
+	if k == nil {
+		return nil
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keys := make([]JWK, 0, len(k.public))
+
+	for kid, key := range k.public {
+		keys = append(keys, JWK{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: jwt.SigningMethodRS256.Alg(),
+			KeyID:     kid,
+			Modulus:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	slices.SortFunc(keys, func(a, b JWK) int { return strings.Compare(a.KeyID, b.KeyID) })
+
+	return keys
+}
+
+// publicKey returns the public key with a key ID.
+func (k *Keys) publicKey(kid string) (*rsa.PublicKey, error) {
+	if k == nil {
+		return nil, errors.New("no RSA keys are configured")
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	key, ok := k.public[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	}
+
+	return key, nil
+}
+
+// thumbprint computes the RFC 7638 thumbprint of a public key, used as its key ID so that
+// the ID stays the same wherever the key is loaded.
+func thumbprint(key *rsa.PublicKey) string {
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+
+	// The members are required in lexicographic order, without whitespace.
+	sum := sha256.Sum256([]byte(`{"e":"` + e + `","kty":"RSA","n":"` + n + `"}`))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,57 @@
+// Package labels validates the free-form labels operators put on users and apps, such as
+// "env=test" or "risk-tier=high". Unlike user attributes, labels never reach tokens; they
+// only organize and filter records in the admin API.
+package labels
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidKey is returned when a label key does not match the key syntax.
+	ErrInvalidKey = errors.New("invalid label key")
+
+	// ErrInvalidValue is returned when a label value is too long or not valid UTF-8.
+	ErrInvalidValue = errors.New("invalid label value")
+
+	// ErrTooMany is returned when a record is given more than MaxPerRecord labels.
+	ErrTooMany = errors.New("too many labels")
+)
+
+const (
+	// MaxPerRecord is the maximum number of labels a user or app can have.
+	MaxPerRecord = 32
+	// MaxValueLength is the maximum length of a label value in bytes.
+	MaxValueLength = 256
+)
+
+// keyPattern matches valid label keys: up to 63 lowercase letters, digits, dots, dashes,
+// underscores, and slashes, starting and ending with a letter or digit, so that keys can
+// be namespaced, e.g. "billing.example.com/tier".
+var keyPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9._/-]{0,61}[a-z0-9])?$`)
+
+// Validate checks labels, whether set on a record or used as a filter. Values may be empty,
+// so a label can serve as a plain tag.
+//
+// Returns:
+//   - error: nil if the labels are valid, or ErrInvalidKey, ErrInvalidValue, or ErrTooMany
+func Validate(labels map[string]string) error {
+	if len(labels) > MaxPerRecord {
+		return ErrTooMany
+	}
+
+	for key, value := range labels {
+		if !keyPattern.MatchString(key) {
+			return fmt.Errorf("%q: %w", key, ErrInvalidKey)
+		}
+
+		if len(value) > MaxValueLength || !utf8.ValidString(value) {
+			return fmt.Errorf("%q: %w", key, ErrInvalidValue)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+// Package mailer provides delivery of outbound email messages to users.
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+)
+
+// Message is an outbound email message.
+type Message struct {
+	To      string // recipient address
+	Subject string // subject line
+	Body    string // plain-text body
+}
+
+// Mailer delivers messages through the configured SMTP server.
+// When no SMTP host is configured, messages are only logged, which is
+// convenient for local development.
+type Mailer struct {
+	log *slog.Logger // logger for delivery events
+	cfg config.SMTP  // SMTP server settings
+}
+
+// New creates a new Mailer.
+//
+// Parameters:
+//   - log: logger for delivery events
+//   - cfg: SMTP server settings
+//
+// Returns:
+//   - *Mailer: mailer ready to send messages
+func New(log *slog.Logger, cfg config.SMTP) *Mailer {
+	return &Mailer{
+		log: log,
+		cfg: cfg,
+	}
+}
+
+// Send delivers msg to its recipient.
+//
+// Parameters:
+//   - ctx: context for request cancellation
+//   - msg: message to deliver
+//
+// Returns:
+//   - error: non-nil if delivery fails
+func (m *Mailer) Send(ctx context.Context, msg Message) error {
+	const op = "mailer.Mailer.Send"
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if m.cfg.Host == "" {
+		// The body may contain secrets such as reset tokens, so it is only
+		// logged at debug level, which production loggers do not emit.
+		m.log.Debug("smtp is not configured, message not delivered",
+			slog.String("op", op),
+			slog.String("to", msg.To),
+			slog.String("subject", msg.Subject),
+			slog.String("body", msg.Body),
+		)
+
+		return nil
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	if err := m.sendMail(ctx, addr, auth, msg); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// sendMail delivers msg like smtp.SendMail, but gives up when ctx is done,
+// so a slow SMTP server cannot hold a call past its deadline.
+func (m *Mailer) sendMail(ctx context.Context, addr string, auth smtp.Auth, msg Message) error {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	// Closing the connection unblocks any pending read or write once ctx is canceled.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return err
+		}
+	}
+
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); ok {
+			if err := c.Auth(auth); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := c.Mail(m.cfg.From); err != nil {
+		return err
+	}
+
+	if err := c.Rcpt(msg.To); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(m.format(msg)); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// format renders msg as an RFC 5322 message.
+func (m *Mailer) format(msg Message) []byte {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "From: %s\r\n", m.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
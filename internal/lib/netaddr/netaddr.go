@@ -0,0 +1,55 @@
+// Package netaddr parses the address ranges listed in configuration, such as
+// allowlists and trusted proxies.
+package netaddr
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// ParsePrefix parses a CIDR range, or a single address as the range holding only it.
+func ParsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+
+		return prefix.Masked(), err
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// ParsePrefixes parses a list of CIDR ranges and single addresses with ParsePrefix.
+func ParsePrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+
+	for _, entry := range entries {
+		prefix, err := ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address range %q: %w", entry, err)
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes, nil
+}
+
+// Contains reports whether addr is in any of the prefixes. IPv4-mapped IPv6
+// addresses are matched as IPv4 addresses.
+func Contains(prefixes []netip.Prefix, addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,98 @@
+// Package ntp measures the offset of a clock against an NTP server using SNTP (RFC 4330).
+package ntp
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/clock"
+)
+
+const (
+	// packetSize is the size of an NTP packet without extensions.
+	packetSize = 48
+	// clientRequest sets leap indicator 0, version 4, and mode 3 (client).
+	clientRequest = 0x23
+	// modeServer is the mode of server responses.
+	modeServer = 4
+	// ntpEpochOffset is the number of seconds between the NTP epoch (1900) and the Unix epoch (1970).
+	ntpEpochOffset = 2208988800
+)
+
+// ErrInvalidResponse is returned when the server response is malformed or unusable.
+var ErrInvalidResponse = errors.New("invalid ntp response")
+
+// Offset queries an NTP server and returns how far the clock is ahead of the server's time;
+// a negative offset means the clock is behind.
+//
+// Parameters:
+//   - ctx: context bounding the query; its deadline applies to the network exchange
+//   - server: host and port of the NTP server, e.g. "pool.ntp.org:123"
+//   - clk: clock to measure
+//
+// Returns:
+//   - time.Duration: offset of clk from the server's time
+//   - error: non-nil if the server cannot be queried or the response is invalid
+func Offset(ctx context.Context, server string, clk clock.Clock) (time.Duration, error) {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "udp", server)
+	if err != nil {
+		return 0, err
+	}
+
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return 0, err
+		}
+	}
+
+	req := make([]byte, packetSize)
+	req[0] = clientRequest
+
+	sentAt := clk.Now()
+
+	if _, err := conn.Write(req); err != nil {
+		return 0, err
+	}
+
+	resp := make([]byte, packetSize)
+
+	n, err := conn.Read(resp)
+	if err != nil {
+		return 0, err
+	}
+
+	receivedAt := clk.Now()
+
+	if n < packetSize || resp[0]&0x7 != modeServer {
+		return 0, ErrInvalidResponse
+	}
+
+	// Stratum 0 is a "kiss-o'-death" packet asking the client to back off.
+	if resp[1] == 0 {
+		return 0, fmt.Errorf("%w: kiss-o'-death %q", ErrInvalidResponse, resp[12:16])
+	}
+
+	serverReceived := fromNTP(resp[32:40])
+	serverSent := fromNTP(resp[40:48])
+
+	// The server's time is estimated at the midpoint of the exchange, canceling out symmetric network delay.
+	serverOffset := (serverReceived.Sub(sentAt) + serverSent.Sub(receivedAt)) / 2
+
+	return -serverOffset, nil
+}
+
+// fromNTP converts a 64-bit NTP timestamp to a time.
+func fromNTP(b []byte) time.Time {
+	seconds := int64(binary.BigEndian.Uint32(b[:4])) - ntpEpochOffset
+	fraction := int64(binary.BigEndian.Uint32(b[4:]))
+
+	return time.Unix(seconds, fraction*int64(time.Second)>>32)
+}
@@ -0,0 +1,184 @@
+// Package paseto implements PASETO v4.local tokens: symmetric authenticated
+// encryption with XChaCha20 and BLAKE2b, as specified at https://paseto.io.
+package paseto
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20"
+)
+
+// KeySize is the size of a v4.local key in bytes.
+const KeySize = 32
+
+// header prefixes every v4.local token.
+const header = "v4.local."
+
+const (
+	nonceSize = 32
+	tagSize   = 32
+)
+
+// ErrInvalidToken is returned when a token is malformed or fails authentication.
+var ErrInvalidToken = errors.New("invalid paseto token")
+
+// IsToken reports whether token looks like a v4.local token.
+func IsToken(token string) bool {
+	return strings.HasPrefix(token, header)
+}
+
+// Encrypt seals payload into a v4.local token. The footer is authenticated
+// but not encrypted, so it may carry a key identifier; it may be empty.
+func Encrypt(key []byte, payload []byte, footer []byte) (string, error) {
+	if len(key) != KeySize {
+		return "", errors.New("paseto key must be 32 bytes")
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	encKey, counterNonce, authKey := splitKey(key, nonce)
+
+	stream, err := chacha20.NewUnauthenticatedCipher(encKey, counterNonce)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext := make([]byte, len(payload))
+	stream.XORKeyStream(ciphertext, payload)
+
+	tag := mac(authKey, pae([]byte(header), nonce, ciphertext, footer, nil))
+
+	body := make([]byte, 0, nonceSize+len(ciphertext)+tagSize)
+	body = append(body, nonce...)
+	body = append(body, ciphertext...)
+	body = append(body, tag...)
+
+	token := header + base64.RawURLEncoding.EncodeToString(body)
+	if len(footer) > 0 {
+		token += "." + base64.RawURLEncoding.EncodeToString(footer)
+	}
+
+	return token, nil
+}
+
+// Footer returns the unauthenticated footer of a token, e.g. to select the key.
+// The footer must not be trusted until Decrypt succeeds.
+func Footer(token string) ([]byte, error) {
+	_, footer, err := split(token)
+	return footer, err
+}
+
+// Decrypt authenticates and decrypts a v4.local token.
+//
+// Returns:
+//   - []byte: the payload
+//   - []byte: the authenticated footer, empty if the token has none
+//   - error: ErrInvalidToken if the token is malformed or was not sealed with key
+func Decrypt(key []byte, token string) ([]byte, []byte, error) {
+	if len(key) != KeySize {
+		return nil, nil, errors.New("paseto key must be 32 bytes")
+	}
+
+	body, footer, err := split(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(body) < nonceSize+tagSize {
+		return nil, nil, ErrInvalidToken
+	}
+
+	nonce := body[:nonceSize]
+	ciphertext := body[nonceSize : len(body)-tagSize]
+	tag := body[len(body)-tagSize:]
+
+	encKey, counterNonce, authKey := splitKey(key, nonce)
+
+	expected := mac(authKey, pae([]byte(header), nonce, ciphertext, footer, nil))
+	if subtle.ConstantTimeCompare(tag, expected) != 1 {
+		return nil, nil, ErrInvalidToken
+	}
+
+	stream, err := chacha20.NewUnauthenticatedCipher(encKey, counterNonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payload := make([]byte, len(ciphertext))
+	stream.XORKeyStream(payload, ciphertext)
+
+	return payload, footer, nil
+}
+
+// split decodes the body and footer of a token.
+func split(token string) (body []byte, footer []byte, err error) {
+	rest, ok := strings.CutPrefix(token, header)
+	if !ok {
+		return nil, nil, ErrInvalidToken
+	}
+
+	encodedBody, encodedFooter, hasFooter := strings.Cut(rest, ".")
+
+	body, err = base64.RawURLEncoding.DecodeString(encodedBody)
+	if err != nil {
+		return nil, nil, ErrInvalidToken
+	}
+
+	if hasFooter {
+		footer, err = base64.RawURLEncoding.DecodeString(encodedFooter)
+		if err != nil {
+			return nil, nil, ErrInvalidToken
+		}
+	}
+
+	return body, footer, nil
+}
+
+// splitKey derives the encryption key, the XChaCha20 nonce, and the authentication key
+// for a token nonce.
+func splitKey(key []byte, nonce []byte) (encKey []byte, counterNonce []byte, authKey []byte) {
+	tmp := keyedHash(key, 56, []byte("paseto-encryption-key"), nonce)
+
+	return tmp[:32], tmp[32:], keyedHash(key, 32, []byte("paseto-auth-key-for-aead"), nonce)
+}
+
+// mac computes the 32-byte BLAKE2b authentication tag of a message.
+func mac(key []byte, msg []byte) []byte {
+	return keyedHash(key, tagSize, msg)
+}
+
+// keyedHash computes a keyed BLAKE2b hash of the given size over the concatenated parts.
+func keyedHash(key []byte, size int, parts ...[]byte) []byte {
+	h, err := blake2b.New(size, key)
+	if err != nil {
+		// Only reachable with invalid sizes or keys, which are constants here.
+		panic(err)
+	}
+
+	for _, p := range parts {
+		h.Write(p)
+	}
+
+	return h.Sum(nil)
+}
+
+// pae implements the pre-authentication encoding of the PASETO specification.
+func pae(pieces ...[]byte) []byte {
+	out := binary.LittleEndian.AppendUint64(nil, uint64(len(pieces)))
+
+	for _, p := range pieces {
+		out = binary.LittleEndian.AppendUint64(out, uint64(len(p))&^(1<<63))
+		out = append(out, p...)
+	}
+
+	return out
+}
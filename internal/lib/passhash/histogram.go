@@ -0,0 +1,85 @@
+package passhash
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// bounds are the upper bounds of the histogram buckets. bcrypt takes from a few
+// milliseconds at low costs to seconds at high ones.
+var bounds = []time.Duration{
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+}
+
+// histogram is an expvar counting durations into buckets. Like Prometheus histograms,
+// buckets are cumulative: each counts the durations up to its bound.
+type histogram struct {
+	mu     sync.Mutex
+	counts []int64 // durations per bucket, with a last one for those above every bound
+	count  int64
+	sum    time.Duration
+}
+
+// newHistogram creates a histogram and publishes it under name.
+func newHistogram(name string) *histogram {
+	h := &histogram{counts: make([]int64, len(bounds)+1)}
+
+	expvar.Publish(name, h)
+
+	return h
+}
+
+// observe counts d.
+func (h *histogram) observe(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	i := 0
+	for i < len(bounds) && d > bounds[i] {
+		i++
+	}
+
+	h.counts[i]++
+	h.count++
+	h.sum += d
+}
+
+// String implements expvar.Var, rendering the histogram as JSON,
+// e.g. {"count": 3, "sum_ms": 210.5, "buckets": {"le_100ms": 2, ..., "le_inf": 3}}.
+func (h *histogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets := make(map[string]int64, len(h.counts))
+
+	var cumulative int64
+
+	for i, n := range h.counts {
+		cumulative += n
+
+		label := "le_inf"
+		if i < len(bounds) {
+			label = fmt.Sprintf("le_%dms", bounds[i].Milliseconds())
+		}
+
+		buckets[label] = cumulative
+	}
+
+	out, _ := json.Marshal(struct {
+		Count   int64            `json:"count"`
+		SumMS   float64          `json:"sum_ms"`
+		Buckets map[string]int64 `json:"buckets"`
+	}{h.count, float64(h.sum) / float64(time.Millisecond), buckets})
+
+	return string(out)
+}
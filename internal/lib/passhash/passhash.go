@@ -0,0 +1,153 @@
+// Package passhash hashes and verifies passwords with bcrypt, timing every operation
+// and optionally tuning the cost of new hashes to the hardware it runs on.
+package passhash
+
+import (
+	"expvar"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// hashDuration and compareDuration are histograms of the time taken to hash and verify passwords.
+	hashDuration    = newHistogram("password_hash_duration")
+	compareDuration = newHistogram("password_compare_duration")
+
+	// currentCost is the cost of new hashes.
+	currentCost = expvar.NewInt("password_hash_cost")
+)
+
+// window is the number of timings averaged before tuning reconsiders the cost.
+const window = 16
+
+// Hasher hashes passwords at a cost that, with a target duration, is tuned after every
+// window of timings: raised while hashing twice as long still stays within the target,
+// since each step doubles the work, and lowered once hashing exceeds the target by half.
+// The gap between both thresholds keeps the cost from flapping between two values.
+type Hasher struct {
+	log *slog.Logger           // logger for cost changes
+	cfg config.PasswordHashing // cost bounds and target duration
+
+	mu      sync.Mutex    // guards the fields below
+	cost    int           // cost of new hashes
+	samples int           // timings in the current window
+	total   time.Duration // sum of the timings in the current window, scaled to cost
+}
+
+// New creates a new Hasher.
+//
+// Parameters:
+//   - log: logger for cost changes
+//   - cfg: cost of new hashes, and the bounds and target duration of its tuning
+//
+// Returns:
+//   - *Hasher: hasher starting at cfg.Cost
+//   - error: non-nil if a cost is outside the range bcrypt accepts, or the cost is outside its bounds when tuning
+func New(log *slog.Logger, cfg config.PasswordHashing) (*Hasher, error) {
+	const op = "passhash.New"
+
+	for _, cost := range []int{cfg.Cost, cfg.MinCost, cfg.MaxCost} {
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("%s: cost %d is outside [%d, %d]", op, cost, bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	}
+
+	if cfg.TargetDuration > 0 && (cfg.Cost < cfg.MinCost || cfg.Cost > cfg.MaxCost) {
+		return nil, fmt.Errorf("%s: cost %d is outside min_cost %d and max_cost %d", op, cfg.Cost, cfg.MinCost, cfg.MaxCost)
+	}
+
+	currentCost.Set(int64(cfg.Cost))
+
+	return &Hasher{
+		log:  log.With(slog.String("op", "passhash.Hasher")),
+		cfg:  cfg,
+		cost: cfg.Cost,
+	}, nil
+}
+
+// Hash returns the bcrypt hash of password at the current cost.
+func (h *Hasher) Hash(password string) ([]byte, error) {
+	h.mu.Lock()
+	cost := h.cost
+	h.mu.Unlock()
+
+	start := time.Now()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := time.Since(start)
+
+	hashDuration.observe(elapsed)
+	h.record(elapsed, cost)
+
+	return hash, nil
+}
+
+// Compare returns nil if password matches hash, or an error otherwise. Verifying a
+// password takes as long as hashing it at the cost of hash, so it also informs tuning.
+func (h *Hasher) Compare(hash []byte, password string) error {
+	start := time.Now()
+
+	err := bcrypt.CompareHashAndPassword(hash, []byte(password))
+
+	elapsed := time.Since(start)
+
+	compareDuration.observe(elapsed)
+
+	if cost, costErr := bcrypt.Cost(hash); costErr == nil {
+		h.record(elapsed, cost)
+	}
+
+	return err
+}
+
+// record adds a timing taken at cost to the current window, and tunes the cost once the window is full.
+func (h *Hasher) record(elapsed time.Duration, cost int) {
+	if h.cfg.TargetDuration <= 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Each cost step doubles the work, so timings at other costs are scaled to the current one.
+	h.total += time.Duration(float64(elapsed) * math.Pow(2, float64(h.cost-cost)))
+	h.samples++
+
+	if h.samples < window {
+		return
+	}
+
+	mean := h.total / window
+	h.total, h.samples = 0, 0
+
+	next := h.cost
+
+	switch {
+	case 2*mean <= h.cfg.TargetDuration && h.cost < h.cfg.MaxCost:
+		next++
+	case 2*mean > 3*h.cfg.TargetDuration && h.cost > h.cfg.MinCost:
+		next--
+	default:
+		return
+	}
+
+	h.log.Info("bcrypt cost adjusted",
+		slog.Int("from", h.cost),
+		slog.Int("to", next),
+		slog.Duration("mean", mean),
+		slog.Duration("target", h.cfg.TargetDuration),
+	)
+
+	h.cost = next
+	currentCost.Set(int64(next))
+}
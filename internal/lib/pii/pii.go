@@ -0,0 +1,132 @@
+// Package pii encrypts personally identifiable information for storage.
+//
+// Values are encrypted with AES-256-GCM under a random nonce, so equal values
+// produce different ciphertexts. Each value is bound to associated data naming
+// where it is stored, e.g. its column and row, so a ciphertext copied elsewhere
+// fails to decrypt. To look values up without decrypting every row,
+// Index derives a deterministic HMAC-SHA256 of a value that can be stored in a
+// unique index column. Both keys are derived from a single master key.
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the size of the master key in bytes.
+const KeySize = 32
+
+// Versions identify the ciphertext format and are stored as its first byte.
+const (
+	// VersionUnbound values were sealed without associated data, and are only
+	// decrypted to encrypt them again with it.
+	VersionUnbound byte = 1
+
+	// version values are sealed with associated data.
+	version byte = 2
+)
+
+var (
+	// ErrInvalidKey is returned when the master key is not a base64-encoded 32-byte value
+	ErrInvalidKey = errors.New("pii key must be 32 bytes encoded in base64")
+
+	// ErrInvalidCiphertext is returned when a value cannot be decrypted with the key
+	ErrInvalidCiphertext = errors.New("invalid pii ciphertext")
+)
+
+// Cipher encrypts values and derives their lookup indexes.
+type Cipher struct {
+	aead     cipher.AEAD // AES-256-GCM keyed with the derived encryption key
+	indexKey []byte      // HMAC key for lookup indexes
+}
+
+// New creates a Cipher from a base64-encoded 32-byte master key.
+//
+// Parameters:
+//   - key: master key in standard base64 encoding, e.g. from `openssl rand -base64 32`
+//
+// Returns:
+//   - *Cipher: cipher ready to use
+//   - error: ErrInvalidKey if the key is malformed
+func New(key string) (*Cipher, error) {
+	master, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(master) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(derive(master, "sso pii encryption"))
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{
+		aead:     aead,
+		indexKey: derive(master, "sso pii index"),
+	}, nil
+}
+
+// Encrypt encrypts a value bound to associated data, which Decrypt must be given again.
+// The result holds the format version, the nonce, and the sealed value.
+func (c *Cipher) Encrypt(plaintext string, aad []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append([]byte{version}, nonce...)
+
+	return c.aead.Seal(out, nonce, []byte(plaintext), aad), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt with the same associated data.
+func (c *Cipher) Decrypt(ciphertext []byte, aad []byte) (string, error) {
+	return c.open(ciphertext, version, aad)
+}
+
+// DecryptUnbound decrypts a value sealed without associated data, before values were
+// bound to where they are stored. It is only meant for encrypting them again with Encrypt.
+func (c *Cipher) DecryptUnbound(ciphertext []byte) (string, error) {
+	return c.open(ciphertext, VersionUnbound, nil)
+}
+
+// open decrypts a ciphertext of the given format version.
+func (c *Cipher) open(ciphertext []byte, v byte, aad []byte) (string, error) {
+	nonceSize := c.aead.NonceSize()
+	if len(ciphertext) < 1+nonceSize || ciphertext[0] != v {
+		return "", ErrInvalidCiphertext
+	}
+
+	plaintext, err := c.aead.Open(nil, ciphertext[1:1+nonceSize], ciphertext[1+nonceSize:], aad)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidCiphertext, err)
+	}
+
+	return string(plaintext), nil
+}
+
+// Index returns a deterministic keyed hash of a value for equality lookups.
+func (c *Cipher) Index(value string) []byte {
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+
+	return mac.Sum(nil)
+}
+
+// derive derives a purpose-specific key from the master key.
+func derive(master []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, master)
+	mac.Write([]byte(purpose))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,97 @@
+package pii_test
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/pii"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c := newCipher(t)
+
+	aad := []byte("users.email_enc:1")
+
+	first, err := c.Encrypt("user@example.com", aad)
+	require.NoError(t, err)
+
+	second, err := c.Encrypt("user@example.com", aad)
+	require.NoError(t, err)
+
+	// Nonces are random, so equal values are not told apart by their ciphertexts.
+	assert.NotEqual(t, first, second)
+	assert.NotContains(t, string(first), "user@example.com")
+
+	plaintext, err := c.Decrypt(first, aad)
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", plaintext)
+}
+
+func TestCipher_DecryptFailCases(t *testing.T) {
+	c := newCipher(t)
+
+	aad := []byte("users.email_enc:1")
+
+	ciphertext, err := c.Encrypt("user@example.com", aad)
+	require.NoError(t, err)
+
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name       string
+		cipher     *pii.Cipher
+		ciphertext []byte
+		aad        []byte
+	}{
+		{name: "Wrong key", cipher: newCipher(t), ciphertext: ciphertext, aad: aad},
+		{name: "Other row", cipher: c, ciphertext: ciphertext, aad: []byte("users.email_enc:2")},
+		{name: "Other column", cipher: c, ciphertext: ciphertext, aad: []byte("signing_keys.private_key:1")},
+		{name: "No associated data", cipher: c, ciphertext: ciphertext},
+		{name: "Tampered", cipher: c, ciphertext: tampered, aad: aad},
+		{name: "Truncated", cipher: c, ciphertext: ciphertext[:5], aad: aad},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.cipher.Decrypt(tt.ciphertext, tt.aad)
+			require.ErrorIs(t, err, pii.ErrInvalidCiphertext)
+		})
+	}
+
+	// Bound values are not taken for values sealed before binding.
+	_, err = c.DecryptUnbound(ciphertext)
+	require.ErrorIs(t, err, pii.ErrInvalidCiphertext)
+}
+
+func TestCipher_Index(t *testing.T) {
+	c := newCipher(t)
+
+	assert.Equal(t, c.Index("user@example.com"), c.Index("user@example.com"))
+	assert.NotEqual(t, c.Index("user@example.com"), c.Index("other@example.com"))
+	assert.NotEqual(t, c.Index("user@example.com"), newCipher(t).Index("user@example.com"))
+}
+
+func TestNew_InvalidKey(t *testing.T) {
+	for _, key := range []string{"", "not base64!", base64.StdEncoding.EncodeToString(make([]byte, 16))} {
+		_, err := pii.New(key)
+		require.ErrorIs(t, err, pii.ErrInvalidKey, key)
+	}
+}
+
+// newCipher creates a cipher with a random master key.
+func newCipher(t *testing.T) *pii.Cipher {
+	t.Helper()
+
+	key := make([]byte, pii.KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	c, err := pii.New(base64.StdEncoding.EncodeToString(key))
+	require.NoError(t, err)
+
+	return c
+}
@@ -0,0 +1,259 @@
+// Package proxyproto reads the PROXY protocol header (versions 1 and 2) that L4 load
+// balancers send ahead of a connection to convey the address of the client, which
+// would otherwise be hidden behind the load balancer's own address.
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/netaddr"
+)
+
+// ErrInvalidHeader is returned when a connection does not begin with a valid PROXY header.
+var ErrInvalidHeader = errors.New("invalid proxy protocol header")
+
+// v2Signature begins every version 2 header.
+var v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// maxV1HeaderSize is the longest version 1 header, including the trailing CRLF.
+const maxV1HeaderSize = 107
+
+// Listener accepts connections that begin with a PROXY header, and reports the
+// client address from the header as their remote address.
+type Listener struct {
+	net.Listener
+	trusted []netip.Prefix // peers whose headers are read; all peers if empty
+	timeout time.Duration  // deadline for reading the header
+}
+
+// NewListener wraps a listener so connections from trusted peers must begin with
+// a PROXY header. Connections from other peers are passed through untouched, so
+// clients cannot spoof their address by sending a header themselves.
+//
+// Parameters:
+//   - l: listener to wrap
+//   - trusted: addresses of the load balancers; every peer must send a header if empty
+//   - timeout: deadline for reading the header, bounding connections that never send it
+//
+// Returns a *Listener wrapping l.
+func NewListener(l net.Listener, trusted []netip.Prefix, timeout time.Duration) *Listener {
+	return &Listener{Listener: l, trusted: trusted, timeout: timeout}
+}
+
+// Accept waits for the next connection. The header is read lazily on the first Read
+// or RemoteAddr call, so a slow peer does not hold up other connections.
+func (l *Listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(l.trusted) > 0 {
+		addr, ok := conn.RemoteAddr().(*net.TCPAddr)
+		if !ok || !netaddr.Contains(l.trusted, addr.AddrPort().Addr()) {
+			return conn, nil
+		}
+	}
+
+	return &Conn{Conn: conn, reader: bufio.NewReader(conn), timeout: l.timeout}, nil
+}
+
+// Conn is a connection beginning with a PROXY header.
+type Conn struct {
+	net.Conn
+	reader  *bufio.Reader // buffers the header and any data following it
+	timeout time.Duration // deadline for reading the header
+
+	once   sync.Once // reads the header once
+	remote net.Addr  // client address from the header; the peer's address if it has none
+	err    error     // error reading the header, returned by every Read
+
+	mu           sync.Mutex // guards readDeadline
+	readDeadline time.Time  // read deadline set by the caller, restored after the header
+}
+
+// Read reads data following the header, or returns the error reading the header.
+func (c *Conn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	return c.reader.Read(b)
+}
+
+// RemoteAddr returns the client address from the header. It falls back to the peer's
+// address for headers without one, such as health checks, and for invalid headers.
+func (c *Conn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+
+	return c.remote
+}
+
+// SetDeadline sets the read and write deadlines of the connection.
+func (c *Conn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.readDeadline = t
+	c.mu.Unlock()
+
+	return c.Conn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline of the connection.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.readDeadline = t
+	c.mu.Unlock()
+
+	return c.Conn.SetReadDeadline(t)
+}
+
+// readHeader reads the header within the timeout, then restores the caller's read deadline.
+func (c *Conn) readHeader() {
+	deadline := time.Now().Add(c.timeout)
+
+	c.mu.Lock()
+	if !c.readDeadline.IsZero() && c.readDeadline.Before(deadline) {
+		deadline = c.readDeadline
+	}
+	c.mu.Unlock()
+
+	_ = c.Conn.SetReadDeadline(deadline)
+
+	c.remote, c.err = ReadHeader(c.reader)
+	if c.err != nil && !errors.Is(c.err, ErrInvalidHeader) {
+		c.err = fmt.Errorf("%w: %w", ErrInvalidHeader, c.err)
+	}
+
+	if c.remote == nil {
+		c.remote = c.Conn.RemoteAddr()
+	}
+
+	c.mu.Lock()
+	_ = c.Conn.SetReadDeadline(c.readDeadline)
+	c.mu.Unlock()
+}
+
+// ReadHeader reads a version 1 or 2 PROXY header.
+//
+// Parameters:
+//   - r: reader positioned at the start of the connection
+//
+// Returns:
+//   - net.Addr: the client address, or nil if the header carries none, as for
+//     health checks made by the load balancer itself
+//   - error: ErrInvalidHeader if the connection does not begin with a valid header,
+//     or the error reading it
+func ReadHeader(r *bufio.Reader) (net.Addr, error) {
+	prefix, err := r.Peek(5)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case string(prefix) == "PROXY":
+		return readV1(r)
+	case bytes.Equal(prefix, v2Signature[:5]):
+		return readV2(r)
+	default:
+		return nil, ErrInvalidHeader
+	}
+}
+
+// readV1 reads a human-readable header, e.g. "PROXY TCP4 203.0.113.7 10.0.0.1 51234 443\r\n".
+func readV1(r *bufio.Reader) (net.Addr, error) {
+	var line []byte
+
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if len(line) >= maxV1HeaderSize {
+			return nil, ErrInvalidHeader
+		}
+
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		line = append(line, b)
+	}
+
+	fields := strings.Split(strings.TrimSuffix(string(line), "\r\n"), " ")
+
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, ErrInvalidHeader
+	}
+
+	addr, err := netip.ParseAddr(fields[2])
+	if err != nil || addr.Is4() != (fields[1] == "TCP4") {
+		return nil, ErrInvalidHeader
+	}
+
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if err != nil {
+		return nil, ErrInvalidHeader
+	}
+
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(addr, uint16(port))), nil
+}
+
+// readV2 reads a binary header. Type-length-value extensions are skipped.
+func readV2(r *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(header[:12], v2Signature) || header[12]>>4 != 2 {
+		return nil, ErrInvalidHeader
+	}
+
+	body := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+
+	switch header[12] & 0x0F {
+	case 0x0: // LOCAL: made by the load balancer itself
+		return nil, nil
+	case 0x1: // PROXY
+	default:
+		return nil, ErrInvalidHeader
+	}
+
+	switch header[13] >> 4 {
+	case 0x1: // AF_INET
+		if len(body) < 12 {
+			return nil, ErrInvalidHeader
+		}
+
+		addr := netip.AddrFrom4([4]byte(body[0:4]))
+
+		return net.TCPAddrFromAddrPort(netip.AddrPortFrom(addr, binary.BigEndian.Uint16(body[8:10]))), nil
+	case 0x2: // AF_INET6
+		if len(body) < 36 {
+			return nil, ErrInvalidHeader
+		}
+
+		addr := netip.AddrFrom16([16]byte(body[0:16])).Unmap()
+
+		return net.TCPAddrFromAddrPort(netip.AddrPortFrom(addr, binary.BigEndian.Uint16(body[32:34]))), nil
+	default: // AF_UNSPEC and AF_UNIX carry no client IP
+		return nil, nil
+	}
+}
@@ -0,0 +1,375 @@
+// Package radius encodes and decodes RADIUS packets (RFC 2865) for PAP authentication,
+// with the Message-Authenticator attribute (RFC 3579) that protects requests and responses
+// from forgery.
+package radius
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+// Packet codes.
+const (
+	CodeAccessRequest byte = 1
+	CodeAccessAccept  byte = 2
+	CodeAccessReject  byte = 3
+)
+
+// Attribute types.
+const (
+	AttrUserName             byte = 1
+	AttrUserPassword         byte = 2
+	AttrReplyMessage         byte = 18
+	AttrCallingStationID     byte = 31
+	AttrNASIdentifier        byte = 32
+	AttrMessageAuthenticator byte = 80
+)
+
+const (
+	// MaxPacketSize is the largest packet allowed by RFC 2865.
+	MaxPacketSize = 4096
+	// headerSize is the size of the code, identifier, length, and authenticator.
+	headerSize = 20
+	// maxPasswordSize is the largest User-Password value allowed by RFC 2865.
+	maxPasswordSize = 128
+)
+
+var (
+	// ErrMalformedPacket is returned when a packet is truncated or its lengths are inconsistent.
+	ErrMalformedPacket = errors.New("malformed radius packet")
+
+	// ErrMissingMessageAuthenticator is returned when a packet has no Message-Authenticator.
+	ErrMissingMessageAuthenticator = errors.New("missing message-authenticator")
+
+	// ErrInvalidMessageAuthenticator is returned when a Message-Authenticator or response
+	// authenticator does not match, e.g. because the shared secret differs.
+	ErrInvalidMessageAuthenticator = errors.New("invalid message-authenticator")
+
+	// ErrInvalidPassword is returned when a User-Password value cannot be decrypted.
+	ErrInvalidPassword = errors.New("invalid user-password")
+)
+
+// Attribute is a RADIUS attribute.
+type Attribute struct {
+	Type  byte
+	Value []byte
+}
+
+// Packet is a RADIUS packet.
+type Packet struct {
+	Code          byte
+	Identifier    byte     // matches responses to requests
+	Authenticator [16]byte // random in requests; a hash of the response and secret in responses
+	Attributes    []Attribute
+
+	raw []byte // packet as received; nil for packets built in memory
+}
+
+// Parse decodes a packet received from the network.
+//
+// Parameters:
+//   - b: the datagram; it is retained by the packet and must not be modified
+//
+// Returns:
+//   - *Packet: decoded packet
+//   - error: ErrMalformedPacket if the packet is truncated or inconsistent
+func Parse(b []byte) (*Packet, error) {
+	if len(b) < headerSize {
+		return nil, ErrMalformedPacket
+	}
+
+	length := int(binary.BigEndian.Uint16(b[2:4]))
+	if length < headerSize || length > MaxPacketSize || length > len(b) {
+		return nil, ErrMalformedPacket
+	}
+
+	// Octets beyond the length are padding and must be ignored.
+	b = b[:length]
+
+	p := &Packet{
+		Code:       b[0],
+		Identifier: b[1],
+		raw:        b,
+	}
+	copy(p.Authenticator[:], b[4:headerSize])
+
+	for rest := b[headerSize:]; len(rest) > 0; {
+		if len(rest) < 2 || int(rest[1]) < 2 || int(rest[1]) > len(rest) {
+			return nil, ErrMalformedPacket
+		}
+
+		p.Attributes = append(p.Attributes, Attribute{Type: rest[0], Value: rest[2:rest[1]]})
+		rest = rest[rest[1]:]
+	}
+
+	return p, nil
+}
+
+// Get returns the value of the first attribute of type t.
+func (p *Packet) Get(t byte) ([]byte, bool) {
+	for _, attr := range p.Attributes {
+		if attr.Type == t {
+			return attr.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// encode serializes the packet with the given authenticator.
+func (p *Packet) encode() ([]byte, error) {
+	length := headerSize
+	for _, attr := range p.Attributes {
+		if len(attr.Value) > 253 {
+			return nil, ErrMalformedPacket
+		}
+
+		length += 2 + len(attr.Value)
+	}
+
+	if length > MaxPacketSize {
+		return nil, ErrMalformedPacket
+	}
+
+	b := make([]byte, headerSize, length)
+	b[0] = p.Code
+	b[1] = p.Identifier
+	binary.BigEndian.PutUint16(b[2:4], uint16(length))
+	copy(b[4:headerSize], p.Authenticator[:])
+
+	for _, attr := range p.Attributes {
+		b = append(b, attr.Type, byte(2+len(attr.Value)))
+		b = append(b, attr.Value...)
+	}
+
+	return b, nil
+}
+
+// messageAuthenticatorOffset returns the offset of the Message-Authenticator value in
+// an encoded packet, or -1 if it has none.
+func messageAuthenticatorOffset(b []byte) int {
+	for i := headerSize; i+2 <= len(b); i += int(b[i+1]) {
+		if b[i+1] < 2 {
+			return -1
+		}
+
+		if b[i] == AttrMessageAuthenticator && b[i+1] == 18 {
+			return i + 2
+		}
+	}
+
+	return -1
+}
+
+// messageAuthenticator computes the HMAC-MD5 of an encoded packet with its
+// Message-Authenticator value zeroed and the given authenticator in the header.
+func messageAuthenticator(b []byte, offset int, authenticator [16]byte, secret string) []byte {
+	zeroed := bytes.Clone(b)
+	copy(zeroed[4:headerSize], authenticator[:])
+	clear(zeroed[offset : offset+16])
+
+	mac := hmac.New(md5.New, []byte(secret))
+	mac.Write(zeroed)
+
+	return mac.Sum(nil)
+}
+
+// EncodeRequest serializes an Access-Request, adding a Message-Authenticator signed with
+// the shared secret. The packet's Authenticator must already be set to random bytes.
+//
+// Parameters:
+//   - p: request to encode; a Message-Authenticator is prepended to its attributes
+//   - secret: secret shared with the server
+//
+// Returns:
+//   - []byte: encoded request
+//   - error: ErrMalformedPacket if the request is too large
+func EncodeRequest(p *Packet, secret string) ([]byte, error) {
+	p.Attributes = append([]Attribute{{Type: AttrMessageAuthenticator, Value: make([]byte, 16)}}, p.Attributes...)
+
+	b, err := p.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	copy(b[headerSize+2:], messageAuthenticator(b, headerSize+2, p.Authenticator, secret))
+
+	return b, nil
+}
+
+// VerifyRequest checks the Message-Authenticator of a received request.
+//
+// Parameters:
+//   - p: request returned by Parse
+//   - secret: secret shared with the client
+//
+// Returns:
+//   - error: ErrMissingMessageAuthenticator if the request has none, ErrInvalidMessageAuthenticator
+//     if it does not match the secret, or nil if it is valid
+func VerifyRequest(p *Packet, secret string) error {
+	offset := messageAuthenticatorOffset(p.raw)
+	if offset < 0 {
+		return ErrMissingMessageAuthenticator
+	}
+
+	if !hmac.Equal(p.raw[offset:offset+16], messageAuthenticator(p.raw, offset, p.Authenticator, secret)) {
+		return ErrInvalidMessageAuthenticator
+	}
+
+	return nil
+}
+
+// EncodeResponse serializes a response to a request. A Message-Authenticator is added
+// first, as recommended against forgery of responses, and the response authenticator
+// is computed from the request authenticator and the shared secret.
+//
+// Parameters:
+//   - req: request being answered
+//   - code: code of the response, e.g. CodeAccessAccept
+//   - secret: secret shared with the client
+//   - attrs: attributes of the response
+//
+// Returns:
+//   - []byte: encoded response
+//   - error: ErrMalformedPacket if the response is too large
+func EncodeResponse(req *Packet, code byte, secret string, attrs ...Attribute) ([]byte, error) {
+	resp := &Packet{
+		Code:          code,
+		Identifier:    req.Identifier,
+		Authenticator: req.Authenticator,
+		Attributes:    append([]Attribute{{Type: AttrMessageAuthenticator, Value: make([]byte, 16)}}, attrs...),
+	}
+
+	b, err := resp.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	copy(b[headerSize+2:], messageAuthenticator(b, headerSize+2, req.Authenticator, secret))
+
+	h := md5.New()
+	h.Write(b)
+	h.Write([]byte(secret))
+	copy(b[4:headerSize], h.Sum(nil))
+
+	return b, nil
+}
+
+// VerifyResponse checks the response authenticator and Message-Authenticator of a
+// response received for a request.
+//
+// Parameters:
+//   - resp: response returned by Parse
+//   - req: request the response answers
+//   - secret: secret shared with the server
+//
+// Returns:
+//   - error: ErrInvalidMessageAuthenticator if the response does not answer the request
+//     or does not match the secret, ErrMissingMessageAuthenticator if it has none, or nil
+func VerifyResponse(resp *Packet, req *Packet, secret string) error {
+	if resp.Identifier != req.Identifier {
+		return ErrInvalidMessageAuthenticator
+	}
+
+	b := bytes.Clone(resp.raw)
+	copy(b[4:headerSize], req.Authenticator[:])
+
+	h := md5.New()
+	h.Write(b)
+	h.Write([]byte(secret))
+
+	if subtle.ConstantTimeCompare(h.Sum(nil), resp.Authenticator[:]) != 1 {
+		return ErrInvalidMessageAuthenticator
+	}
+
+	offset := messageAuthenticatorOffset(resp.raw)
+	if offset < 0 {
+		return ErrMissingMessageAuthenticator
+	}
+
+	if !hmac.Equal(resp.raw[offset:offset+16], messageAuthenticator(resp.raw, offset, req.Authenticator, secret)) {
+		return ErrInvalidMessageAuthenticator
+	}
+
+	return nil
+}
+
+// EncryptPassword hides a password for the User-Password attribute of a request.
+//
+// Parameters:
+//   - password: password to hide, at most 128 bytes
+//   - secret: secret shared with the server
+//   - authenticator: request authenticator of the packet carrying the attribute
+//
+// Returns:
+//   - []byte: attribute value
+//   - error: ErrInvalidPassword if the password is too long
+func EncryptPassword(password []byte, secret string, authenticator [16]byte) ([]byte, error) {
+	if len(password) > maxPasswordSize {
+		return nil, ErrInvalidPassword
+	}
+
+	size := (len(password) + 15) / 16 * 16
+	if size == 0 {
+		size = 16
+	}
+
+	b := make([]byte, size)
+	copy(b, password)
+
+	prev := authenticator[:]
+
+	for i := 0; i < size; i += 16 {
+		pad := passwordPad(secret, prev)
+		for j := range 16 {
+			b[i+j] ^= pad[j]
+		}
+
+		prev = b[i : i+16]
+	}
+
+	return b, nil
+}
+
+// DecryptPassword reveals the password hidden in the User-Password attribute of a request.
+//
+// Parameters:
+//   - value: attribute value
+//   - secret: secret shared with the client
+//   - authenticator: request authenticator of the packet carrying the attribute
+//
+// Returns:
+//   - []byte: the password, without padding
+//   - error: ErrInvalidPassword if the value has an invalid length
+func DecryptPassword(value []byte, secret string, authenticator [16]byte) ([]byte, error) {
+	if len(value) == 0 || len(value)%16 != 0 || len(value) > maxPasswordSize {
+		return nil, ErrInvalidPassword
+	}
+
+	b := make([]byte, len(value))
+	prev := authenticator[:]
+
+	for i := 0; i < len(value); i += 16 {
+		pad := passwordPad(secret, prev)
+		for j := range 16 {
+			b[i+j] = value[i+j] ^ pad[j]
+		}
+
+		prev = value[i : i+16]
+	}
+
+	return bytes.TrimRight(b, "\x00"), nil
+}
+
+// passwordPad returns MD5(secret + prev), the pad hiding a 16-byte block of a password.
+func passwordPad(secret string, prev []byte) []byte {
+	h := md5.New()
+	h.Write([]byte(secret))
+	h.Write(prev)
+
+	return h.Sum(nil)
+}
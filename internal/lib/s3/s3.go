@@ -0,0 +1,290 @@
+// Package s3 is a minimal client of the S3 API, enough to store, list, and delete objects
+// of a bucket. It signs requests with AWS Signature Version 4, so it works with Amazon S3
+// and S3-compatible stores, including Google Cloud Storage through its XML API with HMAC
+// keys. Buckets are addressed in path style, as in https://endpoint/bucket/key.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrUnexpectedStatus is returned when the store answers a request with an error status.
+var ErrUnexpectedStatus = errors.New("unexpected status")
+
+// Object describes a stored object.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Client sends requests to a bucket, safe for concurrent use.
+type Client struct {
+	endpoint  *url.URL     // base URL of the store
+	region    string       // region requests are signed for
+	bucket    string       // bucket the objects are in
+	accessKey string       // ID of the access key signing requests
+	secretKey string       // secret of the access key
+	client    *http.Client // client sending the requests
+}
+
+// New creates a client of a bucket.
+//
+// Parameters:
+//   - endpoint: base URL of the store, e.g. https://s3.eu-west-1.amazonaws.com
+//   - region: region requests are signed for, e.g. eu-west-1, or auto for Google Cloud Storage
+//   - bucket: bucket the objects are in
+//   - accessKey, secretKey: access key signing requests
+//   - timeout: time limit of every request, including reading the response
+//
+// Returns:
+//   - *Client: client ready to use
+//   - error: non-nil if the endpoint is not an absolute URL
+func New(endpoint, region, bucket, accessKey, secretKey string, timeout time.Duration) (*Client, error) {
+	const op = "s3.New"
+
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !u.IsAbs() {
+		return nil, fmt.Errorf("%s: endpoint %q is not an absolute URL", op, endpoint)
+	}
+
+	return &Client{
+		endpoint:  u,
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Put stores an object of size bytes read from body under a key, replacing any object stored
+// under it. The body is streamed to the store, so it is not held in memory; its content is
+// not signed, since that would take reading it twice.
+func (c *Client) Put(ctx context.Context, key string, body io.Reader, size int64) error {
+	const op = "s3.Client.Put"
+
+	resp, err := c.do(ctx, http.MethodPut, key, nil, body, size)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp.Body.Close()
+
+	return nil
+}
+
+// Delete removes the object stored under a key. Removing a missing object succeeds.
+func (c *Client) Delete(ctx context.Context, key string) error {
+	const op = "s3.Client.Delete"
+
+	resp, err := c.do(ctx, http.MethodDelete, key, nil, nil, 0)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp.Body.Close()
+
+	return nil
+}
+
+// listResult is the response to ListObjectsV2.
+type listResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns every object whose key starts with a prefix, in order of key.
+func (c *Client) List(ctx context.Context, prefix string) ([]Object, error) {
+	const op = "s3.Client.List"
+
+	var objects []Object
+
+	query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+
+	for {
+		resp, err := c.do(ctx, http.MethodGet, "", query, nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		var result listResult
+
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, o := range result.Contents {
+			objects = append(objects, Object{Key: o.Key, Size: o.Size, LastModified: o.LastModified})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+
+		query.Set("continuation-token", result.NextContinuationToken)
+	}
+}
+
+// do sends a signed request for an object of the bucket, or for the bucket itself if key is
+// empty, and returns the response if its status is 2xx. A request with a body of size bytes
+// is sent with an unsigned payload; requests without one sign the empty payload.
+func (c *Client) do(ctx context.Context, method, key string, query url.Values, body io.Reader, size int64) (*http.Response, error) {
+	path := c.endpoint.EscapedPath() + "/" + escape(c.bucket, true)
+	if key != "" {
+		path += "/" + escape(key, false)
+	}
+
+	u := *c.endpoint
+	u.RawPath = path
+	u.Path, _ = url.PathUnescape(path)
+	u.RawQuery = canonicalQuery(query)
+
+	payloadHash := emptyPayloadHash
+	if body != nil {
+		payloadHash = unsignedPayload
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.ContentLength = size
+	}
+
+	c.sign(req, path, payloadHash, time.Now().UTC())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+
+		// Error responses describe the problem in a small XML document.
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return nil, fmt.Errorf("%w %d: %s", ErrUnexpectedStatus, resp.StatusCode, bytes.TrimSpace(detail))
+	}
+
+	return resp, nil
+}
+
+// Payload hashes of requests: the SHA-256 of an empty body, and the marker of a body that is
+// not signed.
+const (
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+)
+
+// sign adds the headers of AWS Signature Version 4 to a request.
+func (c *Client) sign(req *http.Request, path, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	req.Header.Set("x-amz-date", amzDate)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + req.Header.Get("x-amz-content-sha256"),
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + c.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+c.secretKey), date)
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKey, scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign)),
+	))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data under key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes query parameters in order of name, as signatures require.
+func canonicalQuery(query url.Values) string {
+	var b strings.Builder
+
+	for _, name := range slices.Sorted(maps.Keys(query)) {
+		for _, value := range query[name] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+
+			b.WriteString(escape(name, true) + "=" + escape(value, true))
+		}
+	}
+
+	return b.String()
+}
+
+// escape percent-encodes every byte of s except unreserved characters and, unless
+// encodeSlash is set, slashes, as signatures require.
+func escape(s string, encodeSlash bool) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
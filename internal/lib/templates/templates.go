@@ -0,0 +1,208 @@
+// Package templates provides rendering of outbound email templates.
+//
+// Templates are text/template files defining a "subject" and a "body" block,
+// stored as <name>/<locale>.tmpl. Defaults are embedded in the binary and can be
+// overridden from an external directory, either for every app or for a single
+// app under apps/<app_id>/<name>/<locale>.tmpl.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Names of the templates used by the SSO service.
+const (
+	// PasswordReset is sent with a password reset token.
+	// Data: Email, Token, ExpiresAt.
+	PasswordReset = "password_reset"
+
+	// LoginNotification is sent after a login, with a link to report it as unauthorized.
+	// Data: Email, App, Time, IP, UserAgent, ReportURL, ExpiresAt.
+	LoginNotification = "login_notification"
+
+	// AccountUnfreeze is sent with a token unfreezing a frozen account.
+	// Data: Email, Token, ExpiresAt.
+	AccountUnfreeze = "account_unfreeze"
+
+	// SecretLeak is sent when an app secret reported as leaked was rotated.
+	// Data: App, AppID, Time, Source.
+	SecretLeak = "secret_leak"
+)
+
+// ErrTemplateNotFound is returned when no template matches the requested name and locale.
+var ErrTemplateNotFound = errors.New("template not found")
+
+//go:embed defaults
+var defaults embed.FS
+
+// Email is a rendered email template.
+type Email struct {
+	Subject string
+	Body    string
+}
+
+// Store looks up and renders email templates.
+type Store struct {
+	sources       []fs.FS // template sources in priority order
+	defaultLocale string  // locale used when the requested one has no template
+}
+
+// New creates a new template store.
+//
+// Parameters:
+//   - dir: optional directory with template overrides; empty to use only embedded defaults
+//   - defaultLocale: locale used when the requested one has no template
+//
+// Returns:
+//   - *Store: template store ready to render
+//   - error: non-nil if dir is set but is not a readable directory
+//
+// Templates in dir are read on every render, so edits take effect without a restart.
+func New(dir string, defaultLocale string) (*Store, error) {
+	const op = "templates.New"
+
+	embedded, err := fs.Sub(defaults, "defaults")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s := &Store{
+		defaultLocale: normalizeLocale(defaultLocale),
+	}
+
+	if dir != "" {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s: %s is not a directory", op, dir)
+		}
+
+		s.sources = append(s.sources, os.DirFS(dir))
+	}
+
+	s.sources = append(s.sources, embedded)
+
+	return s, nil
+}
+
+// Render renders the named template.
+//
+// Parameters:
+//   - name: template name, e.g. PasswordReset
+//   - appID: app whose overrides take precedence; 0 for none
+//   - locale: preferred locale such as "en" or "ru-RU"; empty for the default
+//   - data: values referenced by the template
+//
+// Returns:
+//   - *Email: rendered subject and body
+//   - error: ErrTemplateNotFound if no template matches, or another error
+//     if the template is invalid or references missing data
+//
+// Lookup order is: app override, then global override, then embedded default,
+// first for the requested locale, then its base language, then the default locale.
+func (s *Store) Render(name string, appID int32, locale string, data map[string]string) (*Email, error) {
+	const op = "templates.Store.Render"
+
+	src, err := s.lookup(name, appID, locale)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var subject, body bytes.Buffer
+
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Email{
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    strings.TrimSpace(body.String()),
+	}, nil
+}
+
+// lookup returns the source of the best matching template.
+func (s *Store) lookup(name string, appID int32, locale string) ([]byte, error) {
+	if !fs.ValidPath(name) || strings.Contains(name, "/") {
+		return nil, ErrTemplateNotFound
+	}
+
+	var prefixes []string
+	if appID != 0 {
+		prefixes = append(prefixes, path.Join("apps", strconv.Itoa(int(appID))))
+	}
+	prefixes = append(prefixes, "")
+
+	for _, loc := range s.candidateLocales(locale) {
+		for _, src := range s.sources {
+			for _, prefix := range prefixes {
+				b, err := fs.ReadFile(src, path.Join(prefix, name, loc+".tmpl"))
+				if err == nil {
+					return b, nil
+				}
+
+				if !errors.Is(err, fs.ErrNotExist) {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return nil, ErrTemplateNotFound
+}
+
+// candidateLocales returns the locales to try for the requested one, most specific first.
+func (s *Store) candidateLocales(locale string) []string {
+	var candidates []string
+
+	add := func(loc string) {
+		if loc == "" || !fs.ValidPath(loc) || strings.Contains(loc, "/") {
+			return
+		}
+
+		for _, c := range candidates {
+			if c == loc {
+				return
+			}
+		}
+
+		candidates = append(candidates, loc)
+	}
+
+	locale = normalizeLocale(locale)
+
+	add(locale)
+
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		add(base)
+	}
+
+	add(s.defaultLocale)
+
+	return candidates
+}
+
+// normalizeLocale converts a locale to the lowercase, dash-separated form used in file names.
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
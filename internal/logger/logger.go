@@ -37,5 +37,9 @@ func New(cfg *config.Config) *slog.Logger {
 		}))
 	}
 
+	if cfg.Region != "" {
+		log = log.With(slog.String("region", cfg.Region))
+	}
+
 	return log
 }
@@ -0,0 +1,1254 @@
+// Package admin provides management operations for the SSO service,
+// such as registering and removing client applications.
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/attributes"
+	"github.com/kirinyoku/sso-grpc/internal/lib/cachebus"
+	"github.com/kirinyoku/sso-grpc/internal/lib/disposable"
+	"github.com/kirinyoku/sso-grpc/internal/lib/emaildomain"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/lib/templates"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// Admin provides management services.
+type Admin struct {
+	log           *slog.Logger           // logger for structured logging
+	users         UserRepository         // users, their attributes, and statistics
+	apps          AppRepository          // apps, their settings, families, members, owners, and usage
+	tokens        TokenRepository        // support tokens and revocations of other tokens
+	announcements AnnouncementRepository // announcement attached to responses
+	templates     Templates              // email templates
+	auditLog      AuditLog               // tamper-evident log of sensitive calls
+	cacheBus      CacheBus               // invalidates cached data on every replica
+	diagnostics   Diagnostics            // samples the runtime of this replica
+	jobs          Jobs                   // background jobs of this replica
+	keyring       Keyring                // rotation of the RSA keys signing RS256 tokens
+	simulator     Simulator              // evaluation of logins and registrations under proposed settings
+
+	supportCfg      config.SupportTokens // lifetimes of support tokens
+	attributeSchema attributes.Schema    // declared types of user attributes
+	tokenFormat     string               // format of the tokens of apps created without one
+	signingKeys     *jwt.Keys            // RSA keys signing RS256 tokens; nil when none are configured
+	adminAppID      int32                // app whose tokens admin methods accept; it cannot join a family
+
+	statsCacheTTL time.Duration         // how long computed statistics are reused
+	statsMu       sync.Mutex            // guards statsCache
+	statsCache    map[int]*models.Stats // computed statistics by number of days
+}
+
+// Templates defines the interface used to render email templates.
+type Templates interface {
+	// Render renders the named template for the app and locale.
+	Render(name string, appID int32, locale string, data map[string]string) (*templates.Email, error)
+}
+
+// AuditLog defines the interface used to verify the audit log.
+type AuditLog interface {
+	// Verify checks the chain of audit events.
+	Verify(ctx context.Context) (*models.AuditVerification, error)
+}
+
+// CacheBus defines the interface used to invalidate cached data on every replica.
+type CacheBus interface {
+	// Publish invalidates an entry of a topic.
+	Publish(ctx context.Context, topic string, key string) error
+}
+
+// Diagnostics defines the interface used to sample the runtime of this replica.
+type Diagnostics interface {
+	// Sample reads the current state of the runtime.
+	Sample() *models.Diagnostics
+}
+
+// UserRepository defines the storage of users, their attributes, and statistics about
+// them used by the Admin service.
+type UserRepository interface {
+	// UserByID retrieves a user by ID.
+	UserByID(ctx context.Context, userID int64) (*models.User, error)
+
+	// UserByPublicID retrieves a user by public identifier.
+	UserByPublicID(ctx context.Context, publicID string) (*models.User, error)
+
+	// UpdateUser changes a user's admin flag if the user is still at the expected version.
+	UpdateUser(ctx context.Context, userID int64, isAdmin bool, version int64) (*models.User, error)
+
+	// FreezeUser freezes a user's account and revokes every token issued to the user so far.
+	FreezeUser(ctx context.Context, userID int64, at time.Time) error
+
+	// RevokeUserTokens revokes every token issued to a user so far.
+	RevokeUserTokens(ctx context.Context, userID int64, at time.Time) error
+
+	// SetPushMFA enables or disables push MFA for a user.
+	SetPushMFA(ctx context.Context, userID int64, enabled bool) error
+
+	// MergeUsers moves what belongs to a duplicate user to the primary one and leaves the duplicate as a tombstone.
+	MergeUsers(ctx context.Context, primaryID int64, duplicateID int64, at time.Time) (*models.User, error)
+
+	// Stats computes aggregate user counts with daily counts from since up to now.
+	Stats(ctx context.Context, since time.Time, now time.Time) (*models.Stats, error)
+
+	// UserAttributes returns the custom attributes of a user.
+	UserAttributes(ctx context.Context, userID int64) (map[string]string, error)
+
+	// SetUserAttributes replaces the custom attributes of a user.
+	SetUserAttributes(ctx context.Context, userID int64, attrs map[string]string) error
+
+	// UsersByAttribute returns up to limit users with an ID above afterID having an attribute set to a value.
+	UsersByAttribute(ctx context.Context, key string, value string, afterID int64, limit int) ([]*models.User, error)
+
+	// UsersByEmailPrefix returns up to limit users having every label whose email starts with a prefix,
+	// ignoring case, in order of email.
+	UsersByEmailPrefix(ctx context.Context, prefix string, labels map[string]string, limit int) ([]*models.User, error)
+
+	// UsersByEmailSimilarity returns up to limit users having every label whose email is at least
+	// minSimilarity similar to a query, most similar first.
+	UsersByEmailSimilarity(
+		ctx context.Context,
+		query string,
+		minSimilarity float64,
+		labels map[string]string,
+		limit int,
+	) ([]*models.User, error)
+
+	// UserLabels returns the labels of a user.
+	UserLabels(ctx context.Context, userID int64) (map[string]string, error)
+
+	// SetUserLabels replaces the labels of a user.
+	SetUserLabels(ctx context.Context, userID int64, labels map[string]string) error
+
+	// UserAtTime reconstructs the state of a user at a point in time from the user history.
+	UserAtTime(ctx context.Context, userID int64, at time.Time) (*models.UserSnapshot, error)
+}
+
+// AppRepository defines the storage of apps, their settings, families, members, owners,
+// and usage used by the Admin service.
+type AppRepository interface {
+	// SaveApp persists a new application with the given name, secret, token settings, membership requirement,
+	// and family. Returns the ID of the created application or an error if the operation fails.
+	SaveApp(
+		ctx context.Context,
+		name string,
+		secret string,
+		tokenFormat string,
+		minimalClaims bool,
+		requireMembership bool,
+		familyID int32,
+	) (int32, error)
+
+	// DeleteApp removes the application with the given ID.
+	// Returns an error if the app doesn't exist or the operation fails.
+	DeleteApp(ctx context.Context, appID int32) error
+
+	// App retrieves application information by ID.
+	App(ctx context.Context, appID int32) (*models.App, error)
+
+	// Apps returns up to limit apps with an ID above afterID having every label, in order of ID.
+	Apps(ctx context.Context, labels map[string]string, afterID int32, limit int) ([]*models.App, error)
+
+	// AppLabels returns the labels of an app.
+	AppLabels(ctx context.Context, appID int32) (map[string]string, error)
+
+	// SetAppLabels replaces the labels of an app.
+	SetAppLabels(ctx context.Context, appID int32, labels map[string]string) error
+
+	// UpdateApp changes an app's name, secret, token settings, membership requirement, and family
+	// if it is still at the expected version. Empty and nil values keep the current ones.
+	UpdateApp(
+		ctx context.Context,
+		appID int32,
+		name string,
+		secret string,
+		tokenFormat string,
+		minimalClaims *bool,
+		requireMembership *bool,
+		familyID *int32,
+		version int64,
+	) (*models.App, error)
+
+	// AppEmailDomains returns the email domain lists restricting registration for an app.
+	AppEmailDomains(ctx context.Context, appID int32) (allowed []string, denied []string, err error)
+
+	// SetAppEmailDomains replaces the email domain lists restricting registration for an app.
+	SetAppEmailDomains(ctx context.Context, appID int32, allowed []string, denied []string) error
+
+	// AppDisposableEmailPolicy returns an app's disposable email policy, or an empty string for the default.
+	AppDisposableEmailPolicy(ctx context.Context, appID int32) (string, error)
+
+	// SetAppDisposableEmailPolicy sets an app's disposable email policy; an empty string selects the default.
+	SetAppDisposableEmailPolicy(ctx context.Context, appID int32, policy string) error
+
+	// GrantAppAccess makes a user a member of an app.
+	GrantAppAccess(ctx context.Context, appID int32, userID int64, at time.Time) error
+
+	// RevokeAppAccess removes a user from the members of an app.
+	RevokeAppAccess(ctx context.Context, appID int32, userID int64) error
+
+	// Usage lists daily usage since a day, newest day first and busiest first within a day.
+	Usage(ctx context.Context, since time.Time, appID int32, userID int64, byUser bool, limit int) ([]models.Usage, error)
+
+	// AddAppOwner makes a user an owner of an app.
+	AddAppOwner(ctx context.Context, appID int32, userID int64, at time.Time) error
+
+	// RemoveAppOwner removes a user from the owners of an app.
+	RemoveAppOwner(ctx context.Context, appID int32, userID int64) error
+
+	// AppOwners lists the owners of an app in order of ID.
+	AppOwners(ctx context.Context, appID int32) ([]*models.User, error)
+
+	// SaveAppFamily persists a new app family and returns its ID.
+	SaveAppFamily(ctx context.Context, name string, secret string, createdAt time.Time) (int32, error)
+
+	// AppFamily retrieves an app family along with the IDs of its apps.
+	AppFamily(ctx context.Context, familyID int32) (*models.AppFamily, error)
+
+	// DeleteAppFamily removes an app family that no longer has apps.
+	DeleteAppFamily(ctx context.Context, familyID int32) error
+
+	// SetAppBackchannelLogoutURI sets the URI an app receives logout tokens at; an empty URI stops them.
+	SetAppBackchannelLogoutURI(ctx context.Context, appID int32, uri string) error
+
+	// SetAppTokenTTL sets the lifetime of tokens issued for an app; 0 uses the server default.
+	SetAppTokenTTL(ctx context.Context, appID int32, ttl time.Duration) error
+
+	// SetAppTestOnly sets whether test tokens may be minted for an app.
+	SetAppTestOnly(ctx context.Context, appID int32, testOnly bool) error
+}
+
+// TokenRepository defines the storage of support tokens and the revocation of other
+// tokens used by the Admin service.
+type TokenRepository interface {
+	// DeletePasswordResetTokens removes every unused password reset token.
+	// Returns the number of tokens removed.
+	DeletePasswordResetTokens(ctx context.Context) (int64, error)
+
+	// RevokeAppTokens revokes every token issued for an app so far.
+	RevokeAppTokens(ctx context.Context, appID int32, at time.Time) error
+
+	// DeleteAppDeviceAuthorizations removes up to limit device authorizations of an app
+	// that have not been exchanged for a token yet. Returns the number removed.
+	DeleteAppDeviceAuthorizations(ctx context.Context, appID int32, limit int) (int64, error)
+
+	// SaveSupportToken stores the hash of a newly issued support token and returns its ID.
+	SaveSupportToken(ctx context.Context, token *models.SupportToken, tokenHash []byte) (int64, error)
+
+	// SupportTokenByHash retrieves a support token by the hash of its value.
+	SupportTokenByHash(ctx context.Context, tokenHash []byte) (*models.SupportToken, error)
+
+	// RevokeSupportToken revokes a support token.
+	RevokeSupportToken(ctx context.Context, tokenID int64, at time.Time) error
+}
+
+// AnnouncementRepository defines the storage of the announcement attached to responses
+// used by the Admin service.
+type AnnouncementRepository interface {
+	// SetAnnouncement replaces the announcement attached to responses.
+	SetAnnouncement(ctx context.Context, a *models.Announcement) error
+
+	// DeleteAnnouncement clears the announcement attached to responses.
+	DeleteAnnouncement(ctx context.Context) error
+}
+
+// Storage combines the repositories the Admin service depends on. A single storage
+// provider implements them all; the service only calls each through the narrowest one.
+type Storage interface {
+	UserRepository
+	AppRepository
+	TokenRepository
+	AnnouncementRepository
+}
+
+// Common admin errors
+var (
+	// ErrAppExists is returned when attempting to create an app whose name or secret is already taken
+	ErrAppExists = errors.New("app already exists")
+
+	// ErrAppNotFound is returned when an app is not found
+	ErrAppNotFound = errors.New("app not found")
+
+	// ErrUserNotFound is returned when a user is not found
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrVersionConflict is returned when a record was modified since the version an update is based on
+	ErrVersionConflict = errors.New("version conflict")
+
+	// ErrTemplateNotFound is returned when no email template matches the request
+	ErrTemplateNotFound = errors.New("template not found")
+
+	// ErrInvalidDomain is returned when an email domain list contains an invalid value
+	ErrInvalidDomain = errors.New("invalid email domain")
+
+	// ErrDomainConflict is returned when an email domain is both allowed and denied
+	ErrDomainConflict = errors.New("email domain is both allowed and denied")
+
+	// ErrInvalidPolicy is returned when a disposable email policy is unknown
+	ErrInvalidPolicy = errors.New("invalid disposable email policy")
+
+	// ErrInvalidTokenFormat is returned when a token format is unknown, or needs a signing key that is not configured
+	ErrInvalidTokenFormat = errors.New("invalid token format")
+)
+
+// New creates a new instance of the Admin service with the provided dependencies.
+//
+// Parameters:
+//   - log: logger instance for structured logging
+//   - storage: storage implementation for data persistence
+//   - templates: email templates
+//   - statsCacheTTL: how long computed statistics are reused
+//   - auditLog: audit log to verify
+//   - cacheBus: bus invalidating cached data on every replica
+//   - supportCfg: lifetimes of support tokens
+//   - attributeSchema: declared types of user attributes
+//   - diagnostics: watchdog sampling the runtime of this replica
+//   - jobs: scheduler of the background jobs of this replica
+//   - defaultTokenFormat: format of the tokens of apps created without one
+//   - signingKeys: RSA keys signing RS256 tokens; nil if none are configured, so apps cannot use the RS256 format
+//   - keyring: rotation of the RSA keys signing RS256 tokens
+//   - simulator: evaluation of logins and registrations under proposed settings, without performing them
+//   - adminAppID: app whose tokens admin methods accept, which therefore cannot join a family; 0 for none
+//
+// Returns a new *Admin instance ready to use.
+func New(
+	log *slog.Logger,
+	storage Storage,
+	templates Templates,
+	statsCacheTTL time.Duration,
+	auditLog AuditLog,
+	cacheBus CacheBus,
+	supportCfg config.SupportTokens,
+	attributeSchema attributes.Schema,
+	diagnostics Diagnostics,
+	jobs Jobs,
+	defaultTokenFormat string,
+	signingKeys *jwt.Keys,
+	keyring Keyring,
+	simulator Simulator,
+	adminAppID int32,
+) *Admin {
+	return &Admin{
+		log:             log,
+		users:           storage,
+		apps:            storage,
+		tokens:          storage,
+		announcements:   storage,
+		templates:       templates,
+		auditLog:        auditLog,
+		cacheBus:        cacheBus,
+		diagnostics:     diagnostics,
+		jobs:            jobs,
+		tokenFormat:     defaultTokenFormat,
+		signingKeys:     signingKeys,
+		adminAppID:      adminAppID,
+		keyring:         keyring,
+		simulator:       simulator,
+		supportCfg:      supportCfg,
+		attributeSchema: attributeSchema,
+		statsCacheTTL:   statsCacheTTL,
+		statsCache:      make(map[int]*models.Stats),
+	}
+}
+
+// validFormat reports whether apps can issue tokens of a format: it is known and, for
+// RS256 tokens, a signing key is configured.
+func (a *Admin) validFormat(format string) bool {
+	return jwt.ValidFormat(format) && (format != jwt.FormatJWTRS256 || a.signingKeys != nil)
+}
+
+// CreateApp registers a new client application.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - name: application name (must be unique)
+//   - secret: secret used to sign tokens issued for the application (must be unique)
+//   - tokenFormat: format of tokens issued for the application; empty for the configured default
+//   - minimalClaims: whether tokens issued for the application carry only sub, aud, iat, and exp
+//   - requireMembership: whether only users granted access with GrantAppAccess may log in
+//   - familyID: family the application joins, sharing its tokens with the other apps; 0 for none
+//
+// Returns:
+//   - int32: ID of the newly created application
+//   - error: nil on success, or an error if creation fails
+//
+// Possible errors:
+//   - ErrAppExists: if an app with the given name or secret already exists
+//   - ErrInvalidTokenFormat: if the token format is unknown, or RS256 without a signing key configured
+//   - ErrAppFamilyNotFound: if no family exists with familyID
+//   - ErrAdminAppInFamily: if familyID is set and the app was given the ID of the admin app
+//   - other errors: for any other failure during app creation
+func (a *Admin) CreateApp(
+	ctx context.Context,
+	name string,
+	secret string,
+	tokenFormat string,
+	minimalClaims bool,
+	requireMembership bool,
+	familyID int32,
+) (int32, error) {
+	const op = "admin.Admin.CreateApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("name", name),
+	)
+
+	if tokenFormat == "" {
+		tokenFormat = a.tokenFormat
+	}
+
+	if !a.validFormat(tokenFormat) {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidTokenFormat)
+	}
+
+	appID, err := a.apps.SaveApp(ctx, name, secret, tokenFormat, minimalClaims, requireMembership, familyID)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrAppExists):
+			log.Warn("app already exists", slog.String("error", err.Error()))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrAppExists)
+		case errors.Is(err, storage.ErrAppFamilyNotFound):
+			log.Warn("app family not found", slog.String("error", err.Error()))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrAppFamilyNotFound)
+		}
+
+		log.Error("failed to save app", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The ID is only known once the app is saved. While the admin app does not exist yet,
+	// the new app may be given its ID.
+	if familyID != 0 && a.adminAppID != 0 && appID == a.adminAppID {
+		log.Warn("admin app created in a family", slog.Int("app_id", int(appID)), slog.Int("family_id", int(familyID)))
+
+		if err := a.apps.DeleteApp(ctx, appID); err != nil {
+			log.Error("failed to delete app", slog.String("error", err.Error()))
+
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, ErrAdminAppInFamily)
+	}
+
+	log.Info("app created successfully", slog.Int("app_id", int(appID)))
+
+	return appID, nil
+}
+
+// DeleteApp removes a client application.
+// Tokens already issued for the app can no longer be validated afterwards.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application to delete
+//
+// Returns:
+//   - error: nil on success, or an error if deletion fails
+//
+// Possible errors:
+//   - ErrAppNotFound: if no app exists with the ID
+//   - other errors: for any other failure during app deletion
+func (a *Admin) DeleteApp(ctx context.Context, appID int32) error {
+	const op = "admin.Admin.DeleteApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	if err := a.apps.DeleteApp(ctx, appID); err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to delete app", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app deleted successfully")
+
+	a.invalidateTokens(ctx, log)
+
+	return nil
+}
+
+// GetApp returns a client application.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//
+// Returns:
+//   - *models.App: the application
+//   - error: nil on success, or an error if the app cannot be read
+//
+// Possible errors:
+//   - ErrAppNotFound: if no app exists with the ID
+func (a *Admin) GetApp(ctx context.Context, appID int32) (*models.App, error) {
+	const op = "admin.Admin.GetApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	app, err := a.apps.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to get app", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// UpdateApp changes a client application's name, secret, token settings, and family.
+// The update is applied only if the app is still at the given version,
+// so concurrent edits cannot overwrite each other.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - name: new name, or an empty string to keep the current one
+//   - secret: new secret, or an empty string to keep the current one
+//   - tokenFormat: new token format, or an empty string to keep the current one
+//   - minimalClaims: whether tokens carry only minimal claims, or nil to keep the current setting
+//   - requireMembership: whether only members may log in, or nil to keep the current setting
+//   - familyID: family to move the app to, 0 to remove it from its family, or nil to keep the current one
+//   - version: version of the app the change is based on
+//
+// Returns:
+//   - *models.App: the updated application
+//   - error: nil on success, or an error if the update fails
+//
+// Possible errors:
+//   - ErrAppNotFound: if no app exists with the ID
+//   - ErrVersionConflict: if the app was modified since version was read
+//   - ErrAppExists: if the name or secret is taken by another app
+//   - ErrInvalidTokenFormat: if the token format is unknown, or RS256 without a signing key configured
+//   - ErrAppFamilyNotFound: if no family exists with familyID
+//   - ErrAdminAppInFamily: if the app is the admin app and familyID names a family
+func (a *Admin) UpdateApp(
+	ctx context.Context,
+	appID int32,
+	name string,
+	secret string,
+	tokenFormat string,
+	minimalClaims *bool,
+	requireMembership *bool,
+	familyID *int32,
+	version int64,
+) (*models.App, error) {
+	const op = "admin.Admin.UpdateApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	if tokenFormat != "" && !a.validFormat(tokenFormat) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidTokenFormat)
+	}
+
+	// Every app of a family signs tokens with the family secret, so each could sign tokens of the admin app.
+	if familyID != nil && *familyID != 0 && a.adminAppID != 0 && appID == a.adminAppID {
+		log.Warn("admin app cannot join a family", slog.Int("family_id", int(*familyID)))
+
+		return nil, fmt.Errorf("%s: %w", op, ErrAdminAppInFamily)
+	}
+
+	app, err := a.apps.UpdateApp(ctx, appID, name, secret, tokenFormat, minimalClaims, requireMembership, familyID, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrAppNotFound):
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		case errors.Is(err, storage.ErrVersionConflict):
+			log.Warn("app version conflict", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrVersionConflict)
+		case errors.Is(err, storage.ErrAppExists):
+			log.Warn("app already exists", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrAppExists)
+		case errors.Is(err, storage.ErrAppFamilyNotFound):
+			log.Warn("app family not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrAppFamilyNotFound)
+		}
+
+		log.Error("failed to update app", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app updated successfully", slog.Int64("version", app.Version))
+
+	a.invalidateTokens(ctx, log)
+
+	return app, nil
+}
+
+// GetUser returns a user.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//
+// Returns:
+//   - *models.User: the user
+//   - error: nil on success, or an error if the user cannot be read
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the ID
+func (a *Admin) GetUser(ctx context.Context, userID int64) (*models.User, error) {
+	const op = "admin.Admin.GetUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	user, err := a.users.UserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// ResolveUserID returns the ID of the user with the given public ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - publicID: public ID of the user
+//
+// Returns:
+//   - int64: ID of the user
+//   - error: nil on success, or an error if the user cannot be found
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the public ID
+func (a *Admin) ResolveUserID(ctx context.Context, publicID string) (int64, error) {
+	const op = "admin.Admin.ResolveUserID"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	user, err := a.users.UserByPublicID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user.ID, nil
+}
+
+// UpdateUser grants or revokes a user's admin privileges.
+// The update is applied only if the user is still at the given version,
+// so concurrent edits cannot overwrite each other.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - isAdmin: whether the user should have admin privileges
+//   - version: version of the user the change is based on
+//
+// Returns:
+//   - *models.User: the updated user
+//   - error: nil on success, or an error if the update fails
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the ID
+//   - ErrVersionConflict: if the user was modified since version was read
+func (a *Admin) UpdateUser(ctx context.Context, userID int64, isAdmin bool, version int64) (*models.User, error) {
+	const op = "admin.Admin.UpdateUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	user, err := a.users.UpdateUser(ctx, userID, isAdmin, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrUserNotFound):
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		case errors.Is(err, storage.ErrVersionConflict):
+			log.Warn("user version conflict", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrVersionConflict)
+		}
+
+		log.Error("failed to update user", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user updated successfully", slog.Bool("is_admin", user.IsAdmin), slog.Int64("version", user.Version))
+
+	a.invalidateRole(ctx, log, user.ID)
+
+	return user, nil
+}
+
+// FreezeUser freezes a user's account, e.g. on suspicious activity, and revokes every
+// token issued to the user so far. The user cannot log in until the account is unfrozen
+// through the email re-verification flow of the auth service.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//
+// Returns:
+//   - *models.User: the frozen user
+//   - error: nil on success, or an error if the account cannot be frozen
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the ID
+func (a *Admin) FreezeUser(ctx context.Context, userID int64) (*models.User, error) {
+	const op = "admin.Admin.FreezeUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	if err := a.users.FreezeUser(ctx, userID, time.Now()); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to freeze user", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.users.UserByID(ctx, userID)
+	if err != nil {
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Warn("account frozen")
+
+	a.invalidateTokens(ctx, log)
+
+	return user, nil
+}
+
+// RevokeAllUserTokens revokes every access and refresh token issued to a user so far, e.g.
+// when the account is compromised, ending all of its sessions. Apps with a back-channel
+// logout URI are notified. Unlike FreezeUser, the user may log in again right away.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//
+// Returns:
+//   - *models.User: the user with its new revocation time
+//   - error: nil on success, or an error if the tokens cannot be revoked
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the ID
+func (a *Admin) RevokeAllUserTokens(ctx context.Context, userID int64) (*models.User, error) {
+	const op = "admin.Admin.RevokeAllUserTokens"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	if err := a.users.RevokeUserTokens(ctx, userID, time.Now()); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to revoke user tokens", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.users.UserByID(ctx, userID)
+	if err != nil {
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Warn("user tokens revoked")
+
+	a.invalidateTokens(ctx, log)
+
+	return user, nil
+}
+
+// SetUserPushMFA turns push approval of logins on or off for a user, e.g. to let a user
+// who lost their signed-in device log in with the password alone again.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - enabled: whether logins must be approved from a signed-in device
+//
+// Returns:
+//   - error: nil on success, or an error if the setting cannot be changed
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the ID
+func (a *Admin) SetUserPushMFA(ctx context.Context, userID int64, enabled bool) error {
+	const op = "admin.Admin.SetUserPushMFA"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Bool("enabled", enabled),
+	)
+
+	if err := a.users.SetPushMFA(ctx, userID, enabled); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to set push mfa", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("push mfa changed")
+
+	return nil
+}
+
+// InvalidatePasswordResetTokens revokes every outstanding password reset token,
+// e.g. after the mail system delivering them was compromised.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - int64: number of tokens revoked
+//   - error: nil on success, or an error if the tokens cannot be revoked
+func (a *Admin) InvalidatePasswordResetTokens(ctx context.Context) (int64, error) {
+	const op = "admin.Admin.InvalidatePasswordResetTokens"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	invalidated, err := a.tokens.DeletePasswordResetTokens(ctx)
+	if err != nil {
+		log.Error("failed to delete reset tokens", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("password reset tokens invalidated", slog.Int64("invalidated", invalidated))
+
+	return invalidated, nil
+}
+
+// RenderEmailTemplate renders an email template with sample data for preview.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - name: template name
+//   - appID: app whose overrides apply; 0 for none
+//   - locale: preferred locale; empty for the default
+//   - data: sample values referenced by the template
+//
+// Returns:
+//   - *templates.Email: rendered subject and body
+//   - error: nil on success, or an error if rendering fails
+//
+// Possible errors:
+//   - ErrTemplateNotFound: if no template matches the name and locale
+//   - other errors: if the template is invalid or references missing data
+func (a *Admin) RenderEmailTemplate(
+	ctx context.Context,
+	name string,
+	appID int32,
+	locale string,
+	data map[string]string,
+) (*templates.Email, error) {
+	const op = "admin.Admin.RenderEmailTemplate"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("name", name),
+	)
+
+	email, err := a.templates.Render(name, appID, locale, data)
+	if err != nil {
+		if errors.Is(err, templates.ErrTemplateNotFound) {
+			log.Warn("template not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrTemplateNotFound)
+		}
+
+		log.Warn("failed to render template", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return email, nil
+}
+
+// GetAppEmailDomains returns the email domains allowed and denied registration through an app.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//
+// Returns:
+//   - allowed: domains allowed to register; empty if every domain is allowed
+//   - denied: domains denied registration
+//   - error: nil on success, or an error if the lists cannot be read
+//
+// Possible errors:
+//   - ErrAppNotFound: if no app exists with the ID
+func (a *Admin) GetAppEmailDomains(ctx context.Context, appID int32) (allowed []string, denied []string, err error) {
+	const op = "admin.Admin.GetAppEmailDomains"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	allowed, denied, err = a.apps.AppEmailDomains(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return nil, nil, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to get email domains", slog.String("error", err.Error()))
+
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return allowed, denied, nil
+}
+
+// SetAppEmailDomains replaces the email domains allowed and denied registration through an app.
+// Domains are normalized to lowercase and may be given with a leading "@".
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - allowed: domains allowed to register; empty to allow every domain that is not denied
+//   - denied: domains denied registration
+//
+// Returns:
+//   - error: nil on success, or an error if the lists cannot be saved
+//
+// Possible errors:
+//   - ErrInvalidDomain: if a list contains an invalid domain
+//   - ErrDomainConflict: if a domain is in both lists
+//   - ErrAppNotFound: if no app exists with the ID
+func (a *Admin) SetAppEmailDomains(ctx context.Context, appID int32, allowed []string, denied []string) error {
+	const op = "admin.Admin.SetAppEmailDomains"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	allowed, denied, err := normalizeEmailDomains(allowed, denied)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.apps.SetAppEmailDomains(ctx, appID, allowed, denied); err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to save email domains", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("email domains updated", slog.Int("allowed", len(allowed)), slog.Int("denied", len(denied)))
+
+	return nil
+}
+
+// normalizeEmailDomains normalizes the email domains allowed and denied registration through
+// an app. Returns ErrInvalidDomain if a domain is invalid, or ErrDomainConflict if one is both
+// allowed and denied.
+func normalizeEmailDomains(allowed []string, denied []string) ([]string, []string, error) {
+	allowed, err := emaildomain.NormalizeAll(allowed)
+	if err != nil {
+		return nil, nil, ErrInvalidDomain
+	}
+
+	denied, err = emaildomain.NormalizeAll(denied)
+	if err != nil {
+		return nil, nil, ErrInvalidDomain
+	}
+
+	for _, domain := range denied {
+		if slices.Contains(allowed, domain) {
+			return nil, nil, fmt.Errorf("%s: %w", domain, ErrDomainConflict)
+		}
+	}
+
+	return allowed, denied, nil
+}
+
+// GetAppDisposableEmailPolicy returns how an app handles registrations from disposable email providers.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//
+// Returns:
+//   - disposable.Policy: the app's policy, or an empty policy if the app uses the server default
+//   - error: nil on success, or an error if the policy cannot be read
+//
+// Possible errors:
+//   - ErrAppNotFound: if no app exists with the ID
+func (a *Admin) GetAppDisposableEmailPolicy(ctx context.Context, appID int32) (disposable.Policy, error) {
+	const op = "admin.Admin.GetAppDisposableEmailPolicy"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	policy, err := a.apps.AppDisposableEmailPolicy(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return "", fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to get disposable email policy", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return disposable.Policy(policy), nil
+}
+
+// SetAppDisposableEmailPolicy sets how an app handles registrations from disposable email providers.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - policy: the app's policy, or an empty policy to use the server default
+//
+// Returns:
+//   - error: nil on success, or an error if the policy cannot be saved
+//
+// Possible errors:
+//   - ErrInvalidPolicy: if the policy is unknown
+//   - ErrAppNotFound: if no app exists with the ID
+func (a *Admin) SetAppDisposableEmailPolicy(ctx context.Context, appID int32, policy disposable.Policy) error {
+	const op = "admin.Admin.SetAppDisposableEmailPolicy"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	if policy != "" && !policy.Valid() {
+		return fmt.Errorf("%s: %w", op, ErrInvalidPolicy)
+	}
+
+	if err := a.apps.SetAppDisposableEmailPolicy(ctx, appID, string(policy)); err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to save disposable email policy", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("disposable email policy updated", slog.String("policy", string(policy)))
+
+	return nil
+}
+
+// GetStats returns aggregate user counts for dashboards. Results are cached for
+// the configured TTL, so they may lag behind recent activity.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - days: number of UTC days, including today, to report daily counts for
+//
+// Returns:
+//   - *models.Stats: aggregate counts
+//   - error: nil on success, or an error if the counts cannot be computed
+func (a *Admin) GetStats(ctx context.Context, days int) (*models.Stats, error) {
+	const op = "admin.Admin.GetStats"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	now := time.Now()
+
+	a.statsMu.Lock()
+	defer a.statsMu.Unlock()
+
+	if cached, ok := a.statsCache[days]; ok && now.Sub(cached.GeneratedAt) < a.statsCacheTTL {
+		return cached, nil
+	}
+
+	stats, err := a.users.Stats(ctx, now.AddDate(0, 0, 1-days), now)
+	if err != nil {
+		log.Error("failed to compute stats", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.statsCache[days] = stats
+
+	return stats, nil
+}
+
+// GetUsage reports the requests made with user tokens per app and UTC day.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: app to report; 0 for every app
+//   - userID: user to report; 0 for every user
+//   - days: number of UTC days to report, including today
+//   - byUser: whether to report each user separately, rather than sum the users of each app
+//   - limit: maximum number of entries returned
+//
+// Returns:
+//   - []models.Usage: the usage, newest day first and busiest first within a day
+//   - bool: whether entries beyond limit were left out
+//   - error: nil on success, or an error if the usage cannot be read
+func (a *Admin) GetUsage(
+	ctx context.Context,
+	appID int32,
+	userID int64,
+	days int,
+	byUser bool,
+	limit int,
+) ([]models.Usage, bool, error) {
+	const op = "admin.Admin.GetUsage"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+
+	usage, err := a.apps.Usage(ctx, since, appID, userID, byUser || userID != 0, limit+1)
+	if err != nil {
+		log.Error("failed to get usage", slog.String("error", err.Error()))
+
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(usage) > limit {
+		return usage[:limit], true, nil
+	}
+
+	return usage, false, nil
+}
+
+// VerifyAuditLog checks that no audit event was modified, inserted, or removed.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - *models.AuditVerification: the result of the check
+//   - error: nil on success, or an error if the audit log cannot be read
+func (a *Admin) VerifyAuditLog(ctx context.Context) (*models.AuditVerification, error) {
+	const op = "admin.Admin.VerifyAuditLog"
+
+	result, err := a.auditLog.Verify(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
+
+// GetDiagnostics samples the goroutines, heap, and garbage collector of this replica.
+// Other replicas are not sampled.
+//
+// Returns a *models.Diagnostics holding the sample.
+func (a *Admin) GetDiagnostics() *models.Diagnostics {
+	return a.diagnostics.Sample()
+}
+
+// invalidateRole drops the cached admin status of a user on every replica. The change is
+// already stored, so failures are only logged; replicas then serve the old status for at
+// most the role cache age.
+func (a *Admin) invalidateRole(ctx context.Context, log *slog.Logger, userID int64) {
+	if err := a.cacheBus.Publish(ctx, cachebus.TopicRoles, strconv.FormatInt(userID, 10)); err != nil {
+		log.Error("failed to invalidate cached admin status", slog.String("error", err.Error()))
+	}
+}
+
+// invalidateTokens drops cached token validation results on every replica after tokens were
+// revoked or the apps they were issued for changed. The change is already stored, so failures
+// are only logged; replicas then serve stale validations for at most the micro cache TTL.
+func (a *Admin) invalidateTokens(ctx context.Context, log *slog.Logger) {
+	if err := a.cacheBus.Publish(ctx, cachebus.TopicTokens, ""); err != nil {
+		log.Error("failed to invalidate cached token validations", slog.String("error", err.Error()))
+	}
+}
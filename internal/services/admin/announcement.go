@@ -0,0 +1,59 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/cachebus"
+)
+
+// SetAnnouncement sets the announcement attached to every response, e.g. of upcoming
+// maintenance, and makes every replica reload it.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - message: the announcement; empty to clear the current one
+//   - expiresAt: when the announcement is no longer shown; zero to show it until it is cleared
+//
+// Returns:
+//   - error: nil on success, or an error if the announcement cannot be stored or
+//     propagated to the replicas; the call can then be repeated
+func (a *Admin) SetAnnouncement(ctx context.Context, message string, expiresAt time.Time) error {
+	const op = "admin.Admin.SetAnnouncement"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	var err error
+	if message == "" {
+		err = a.announcements.DeleteAnnouncement(ctx)
+	} else {
+		err = a.announcements.SetAnnouncement(ctx, &models.Announcement{
+			Message:   message,
+			CreatedAt: time.Now(),
+			ExpiresAt: expiresAt,
+		})
+	}
+
+	if err != nil {
+		log.Error("failed to store announcement", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Unlike cached roles, the announcement never expires from the replicas' memory,
+	// so a failed invalidation fails the call for the admin to repeat it.
+	if err := a.cacheBus.Publish(ctx, cachebus.TopicAnnouncement, ""); err != nil {
+		log.Error("failed to propagate announcement", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("announcement set", slog.String("message", message), slog.Time("expires_at", expiresAt))
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/attributes"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+var (
+	// ErrInvalidAttribute is returned when an attribute key is invalid or its value does not match the declared type
+	ErrInvalidAttribute = errors.New("invalid attribute")
+
+	// ErrTooManyAttributes is returned when a user is given more attributes than allowed
+	ErrTooManyAttributes = errors.New("too many attributes")
+)
+
+// GetUserAttributes returns the custom attributes of a user.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//
+// Returns:
+//   - map[string]string: attribute values by key; empty if the user has none
+//   - error: nil on success, or an error if the attributes cannot be read
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the ID
+func (a *Admin) GetUserAttributes(ctx context.Context, userID int64) (map[string]string, error) {
+	const op = "admin.Admin.GetUserAttributes"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	attrs, err := a.users.UserAttributes(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user attributes", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attrs, nil
+}
+
+// SetUserAttributes replaces the custom attributes of a user. Values are validated
+// against the types declared in the configuration and stored in canonical form.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - attrs: attribute values by key; empty to remove every attribute
+//
+// Returns:
+//   - map[string]string: the attributes as stored
+//   - error: nil on success, or an error if the attributes cannot be saved
+//
+// Possible errors:
+//   - ErrInvalidAttribute: if a key is invalid or a value does not match its declared type
+//   - ErrTooManyAttributes: if attrs has more than attributes.MaxPerUser entries
+//   - ErrUserNotFound: if no user exists with the ID
+func (a *Admin) SetUserAttributes(ctx context.Context, userID int64, attrs map[string]string) (map[string]string, error) {
+	const op = "admin.Admin.SetUserAttributes"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	attrs, err := a.attributeSchema.Normalize(attrs)
+	if err != nil {
+		if errors.Is(err, attributes.ErrTooMany) {
+			return nil, fmt.Errorf("%s: %w", op, ErrTooManyAttributes)
+		}
+
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrInvalidAttribute, err)
+	}
+
+	if err := a.users.SetUserAttributes(ctx, userID, attrs); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to save user attributes", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user attributes updated", slog.Int("attributes", len(attrs)))
+
+	return attrs, nil
+}
+
+// FindUsersByAttribute returns the users having an attribute set to a value, in order of ID.
+// The value is normalized like stored values, so e.g. "007" finds users with an int attribute of 7.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - key: attribute key
+//   - value: attribute value
+//   - afterID: only users with a greater ID are returned; 0 to start from the first user
+//   - limit: maximum number of users returned
+//
+// Returns:
+//   - []*models.User: matching users; empty if there are none
+//   - error: nil on success, or an error if the users cannot be read
+//
+// Possible errors:
+//   - ErrInvalidAttribute: if the key is invalid or the value does not match its declared type
+func (a *Admin) FindUsersByAttribute(
+	ctx context.Context,
+	key string,
+	value string,
+	afterID int64,
+	limit int,
+) ([]*models.User, error) {
+	const op = "admin.Admin.FindUsersByAttribute"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("key", key),
+	)
+
+	value, err := a.attributeSchema.NormalizeValue(key, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", op, ErrInvalidAttribute, err)
+	}
+
+	users, err := a.users.UsersByAttribute(ctx, key, value, afterID, limit)
+	if err != nil {
+		log.Error("failed to find users by attribute", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
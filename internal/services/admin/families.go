@@ -0,0 +1,146 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+var (
+	// ErrAppFamilyNotFound is returned when an app family with the given ID does not exist
+	ErrAppFamilyNotFound = errors.New("app family not found")
+
+	// ErrAppFamilyExists is returned when an app family with the given name or secret already exists
+	ErrAppFamilyExists = errors.New("app family already exists")
+
+	// ErrAppFamilyNotEmpty is returned when deleting an app family that still has apps
+	ErrAppFamilyNotEmpty = errors.New("app family is not empty")
+
+	// ErrAdminAppInFamily is returned when the admin app would join an app family
+	ErrAdminAppInFamily = errors.New("admin app cannot join an app family")
+)
+
+// CreateAppFamily creates a family of apps sharing an audience. Apps join it with
+// CreateApp or UpdateApp; tokens issued for any of them are then signed with the
+// family secret and accepted by the others.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - name: family name (must be unique)
+//   - secret: secret used to sign tokens issued for the apps in the family (must be unique)
+//
+// Returns:
+//   - int32: ID of the newly created family
+//   - error: nil on success, or an error if creation fails
+//
+// Possible errors:
+//   - ErrAppFamilyExists: if a family with the given name or secret already exists
+func (a *Admin) CreateAppFamily(ctx context.Context, name string, secret string) (int32, error) {
+	const op = "admin.Admin.CreateAppFamily"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("name", name),
+	)
+
+	familyID, err := a.apps.SaveAppFamily(ctx, name, secret, time.Now())
+	if err != nil {
+		if errors.Is(err, storage.ErrAppFamilyExists) {
+			log.Warn("app family already exists", slog.String("error", err.Error()))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrAppFamilyExists)
+		}
+
+		log.Error("failed to save app family", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app family created", slog.Int("family_id", int(familyID)))
+
+	return familyID, nil
+}
+
+// GetAppFamily returns an app family along with the IDs of its apps.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - familyID: ID of the family
+//
+// Returns:
+//   - *models.AppFamily: the family
+//   - error: nil on success, or an error if the family cannot be read
+//
+// Possible errors:
+//   - ErrAppFamilyNotFound: if no family exists with the ID
+func (a *Admin) GetAppFamily(ctx context.Context, familyID int32) (*models.AppFamily, error) {
+	const op = "admin.Admin.GetAppFamily"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("family_id", int(familyID)),
+	)
+
+	family, err := a.apps.AppFamily(ctx, familyID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppFamilyNotFound) {
+			log.Warn("app family not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrAppFamilyNotFound)
+		}
+
+		log.Error("failed to get app family", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return family, nil
+}
+
+// DeleteAppFamily removes an app family. Its apps must leave it first with UpdateApp,
+// so no app is left signing tokens with a secret that no longer exists.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - familyID: ID of the family
+//
+// Returns:
+//   - error: nil on success, or an error if deletion fails
+//
+// Possible errors:
+//   - ErrAppFamilyNotFound: if no family exists with the ID
+//   - ErrAppFamilyNotEmpty: if apps still belong to the family
+func (a *Admin) DeleteAppFamily(ctx context.Context, familyID int32) error {
+	const op = "admin.Admin.DeleteAppFamily"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("family_id", int(familyID)),
+	)
+
+	if err := a.apps.DeleteAppFamily(ctx, familyID); err != nil {
+		switch {
+		case errors.Is(err, storage.ErrAppFamilyNotFound):
+			log.Warn("app family not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrAppFamilyNotFound)
+		case errors.Is(err, storage.ErrAppFamilyNotEmpty):
+			log.Warn("app family is not empty", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrAppFamilyNotEmpty)
+		}
+
+		log.Error("failed to delete app family", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app family deleted")
+
+	return nil
+}
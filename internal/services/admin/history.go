@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// ErrUserHistoryNotFound is returned when no state of a user was recorded at the requested time
+var ErrUserHistoryNotFound = errors.New("user history not found")
+
+// GetUserAtTime returns the state of a user at a point in time, such as whether the user was an
+// admin or a member of an app on a given date, for compliance investigations. History is
+// recorded from the moment migration 39 is applied, or the user is created, whichever is later.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - at: point in time; precise to the second
+//
+// Returns:
+//   - *models.UserSnapshot: state of the user at the time
+//   - error: nil on success, or an error if the history cannot be read
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the ID
+//   - ErrUserHistoryNotFound: if no state of the user was recorded at the time
+func (a *Admin) GetUserAtTime(ctx context.Context, userID int64, at time.Time) (*models.UserSnapshot, error) {
+	const op = "admin.Admin.GetUserAtTime"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Time("at", at),
+	)
+
+	snapshot, err := a.users.UserAtTime(ctx, userID, at)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		if errors.Is(err, storage.ErrUserHistoryNotFound) {
+			log.Warn("user history not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserHistoryNotFound)
+		}
+
+		log.Error("failed to get user history", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return snapshot, nil
+}
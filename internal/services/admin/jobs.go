@@ -0,0 +1,99 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jobs"
+)
+
+// ErrJobNotFound is returned when no background job has the requested name.
+var ErrJobNotFound = errors.New("job not found")
+
+// Jobs defines the interface used to inspect and trigger the background jobs of this replica.
+type Jobs interface {
+	// Jobs returns the status of every job.
+	Jobs() []models.JobStatus
+
+	// Job returns the status of a job.
+	Job(name string) (*models.JobStatus, error)
+
+	// RunNow runs a job outside its schedule and waits for the run to finish.
+	RunNow(ctx context.Context, name string) (*models.JobStatus, error)
+}
+
+// ListJobs returns the status of the background jobs of this replica, such as delivering
+// the outbox, with the outcome and duration of their last run. Other replicas run their own.
+//
+// Returns a slice of models.JobStatus in order of registration.
+func (a *Admin) ListJobs() []models.JobStatus {
+	return a.jobs.Jobs()
+}
+
+// GetJobStatus returns the status of a background job of this replica.
+//
+// Parameters:
+//   - name: name of the job, as listed by ListJobs
+//
+// Returns:
+//   - *models.JobStatus: status of the job
+//   - error: nil on success, or an error if the job does not exist
+//
+// Possible errors:
+//   - ErrJobNotFound: if no job has the name
+func (a *Admin) GetJobStatus(name string) (*models.JobStatus, error) {
+	const op = "admin.Admin.GetJobStatus"
+
+	status, err := a.jobs.Job(name)
+	if err != nil {
+		if errors.Is(err, jobs.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, ErrJobNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return status, nil
+}
+
+// RunJobNow runs a background job of this replica without waiting for its next scheduled
+// run, e.g. to check that it works after changing its configuration. It waits for a run
+// already in progress first, then for the new run to finish. A run that fails is not
+// reported as an error; its error is part of the returned status.
+//
+// Parameters:
+//   - ctx: context of the run; canceling it interrupts the run
+//   - name: name of the job, as listed by ListJobs
+//
+// Returns:
+//   - *models.JobStatus: status of the job after the run
+//   - error: nil on success, or an error if the job does not exist
+//
+// Possible errors:
+//   - ErrJobNotFound: if no job has the name
+func (a *Admin) RunJobNow(ctx context.Context, name string) (*models.JobStatus, error) {
+	const op = "admin.Admin.RunJobNow"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("job", name),
+	)
+
+	status, err := a.jobs.RunNow(ctx, name)
+	if err != nil {
+		if errors.Is(err, jobs.ErrNotFound) {
+			log.Warn("job not found")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrJobNotFound)
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("job run on demand", slog.Duration("duration", status.LastDuration), slog.String("last_error", status.LastError))
+
+	return status, nil
+}
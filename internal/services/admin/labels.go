@@ -0,0 +1,228 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/labels"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+var (
+	// ErrInvalidLabel is returned when a label key or value is invalid
+	ErrInvalidLabel = errors.New("invalid label")
+
+	// ErrTooManyLabels is returned when a user or app is given, or filtered by, more labels than allowed
+	ErrTooManyLabels = errors.New("too many labels")
+)
+
+// GetUserLabels returns the labels of a user.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//
+// Returns:
+//   - map[string]string: label values by key; empty if the user has none
+//   - error: nil on success, or an error if the labels cannot be read
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the ID
+func (a *Admin) GetUserLabels(ctx context.Context, userID int64) (map[string]string, error) {
+	const op = "admin.Admin.GetUserLabels"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	userLabels, err := a.users.UserLabels(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user labels", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userLabels, nil
+}
+
+// SetUserLabels replaces the labels of a user, such as the ones marking test accounts or
+// migration cohorts.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - userLabels: label values by key; empty to remove every label
+//
+// Returns:
+//   - error: nil on success, or an error if the labels cannot be saved
+//
+// Possible errors:
+//   - ErrInvalidLabel: if a key or value is invalid
+//   - ErrTooManyLabels: if userLabels has more than labels.MaxPerRecord entries
+//   - ErrUserNotFound: if no user exists with the ID
+func (a *Admin) SetUserLabels(ctx context.Context, userID int64, userLabels map[string]string) error {
+	const op = "admin.Admin.SetUserLabels"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	if err := validateLabels(userLabels); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.users.SetUserLabels(ctx, userID, userLabels); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to save user labels", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user labels updated", slog.Int("labels", len(userLabels)))
+
+	return nil
+}
+
+// GetAppLabels returns the labels of an application.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//
+// Returns:
+//   - map[string]string: label values by key; empty if the app has none
+//   - error: nil on success, or an error if the labels cannot be read
+//
+// Possible errors:
+//   - ErrAppNotFound: if no application exists with the ID
+func (a *Admin) GetAppLabels(ctx context.Context, appID int32) (map[string]string, error) {
+	const op = "admin.Admin.GetAppLabels"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	appLabels, err := a.apps.AppLabels(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to get app labels", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return appLabels, nil
+}
+
+// SetAppLabels replaces the labels of an application, such as the ones marking its risk tier.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - appLabels: label values by key; empty to remove every label
+//
+// Returns:
+//   - error: nil on success, or an error if the labels cannot be saved
+//
+// Possible errors:
+//   - ErrInvalidLabel: if a key or value is invalid
+//   - ErrTooManyLabels: if appLabels has more than labels.MaxPerRecord entries
+//   - ErrAppNotFound: if no application exists with the ID
+func (a *Admin) SetAppLabels(ctx context.Context, appID int32, appLabels map[string]string) error {
+	const op = "admin.Admin.SetAppLabels"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	if err := validateLabels(appLabels); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.apps.SetAppLabels(ctx, appID, appLabels); err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to save app labels", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app labels updated", slog.Int("labels", len(appLabels)))
+
+	return nil
+}
+
+// ListApps returns the applications having every given label, in order of ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appLabels: labels the apps must all have; empty to list every app
+//   - afterID: only apps with a greater ID are returned; 0 to start from the first app
+//   - limit: maximum number of apps returned
+//
+// Returns:
+//   - []*models.App: matching apps; empty if there are none
+//   - error: nil on success, or an error if the apps cannot be read
+//
+// Possible errors:
+//   - ErrInvalidLabel: if a key or value of the filter is invalid
+//   - ErrTooManyLabels: if appLabels has more than labels.MaxPerRecord entries
+func (a *Admin) ListApps(ctx context.Context, appLabels map[string]string, afterID int32, limit int) ([]*models.App, error) {
+	const op = "admin.Admin.ListApps"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	if err := validateLabels(appLabels); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	apps, err := a.apps.Apps(ctx, appLabels, afterID, limit)
+	if err != nil {
+		log.Error("failed to list apps", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return apps, nil
+}
+
+// validateLabels maps the errors of labels.Validate to the errors of the Admin service.
+func validateLabels(l map[string]string) error {
+	if err := labels.Validate(l); err != nil {
+		if errors.Is(err, labels.ErrTooMany) {
+			return ErrTooManyLabels
+		}
+
+		return fmt.Errorf("%w: %w", ErrInvalidLabel, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// ErrInvalidLogoutURI is returned when a back-channel logout URI is not an absolute http or https URI
+var ErrInvalidLogoutURI = errors.New("invalid back-channel logout uri")
+
+// SetAppBackchannelLogoutURI sets the URI an app receives logout tokens at. Once set, a
+// logout token is POSTed to it whenever every token of a user is revoked: when the user
+// logs out, reports a login, is frozen, or is merged into another user.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - uri: absolute http or https URI without a fragment, or an empty string to stop sending logout tokens
+//
+// Returns:
+//   - error: nil on success, or an error if the URI cannot be saved
+//
+// Possible errors:
+//   - ErrInvalidLogoutURI: if the URI is not an absolute http or https URI, or has a fragment
+//   - ErrAppNotFound: if no app exists with the ID
+func (a *Admin) SetAppBackchannelLogoutURI(ctx context.Context, appID int32, uri string) error {
+	const op = "admin.Admin.SetAppBackchannelLogoutURI"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	if uri != "" {
+		u, err := url.Parse(uri)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Fragment != "" {
+			return fmt.Errorf("%s: %w", op, ErrInvalidLogoutURI)
+		}
+	}
+
+	if err := a.apps.SetAppBackchannelLogoutURI(ctx, appID, uri); err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to save back-channel logout uri", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("back-channel logout uri updated", slog.Bool("enabled", uri != ""))
+
+	return nil
+}
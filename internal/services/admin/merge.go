@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+var (
+	// ErrUserMerged is returned when a user to merge was already merged into another user
+	ErrUserMerged = errors.New("user already merged")
+
+	// ErrSameUser is returned when a user is merged into itself
+	ErrSameUser = errors.New("cannot merge a user into itself")
+)
+
+// MergeUsers merges a duplicate account into the primary one, e.g. when a person registered
+// twice. The duplicate's app memberships, attributes, login history, and support tokens move
+// to the primary user, and the primary user becomes an admin if the duplicate was one. The
+// duplicate is kept as a tombstone pointing to the primary user: its tokens are revoked and it
+// can no longer log in, and its email stays taken. The audit log is never rewritten, so events
+// of the duplicate keep its ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - primaryID: ID of the user to keep
+//   - duplicateID: ID of the user to merge into the primary one
+//
+// Returns:
+//   - *models.User: the primary user after the merge
+//   - error: nil on success, or an error if the users cannot be merged
+//
+// Possible errors:
+//   - ErrSameUser: if both IDs are the same
+//   - ErrUserNotFound: if either user doesn't exist
+//   - ErrUserMerged: if either user was already merged into another one
+func (a *Admin) MergeUsers(ctx context.Context, primaryID int64, duplicateID int64) (*models.User, error) {
+	const op = "admin.Admin.MergeUsers"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("primary_user_id", primaryID),
+		slog.Int64("duplicate_user_id", duplicateID),
+	)
+
+	if primaryID == duplicateID {
+		log.Warn("user merged into itself")
+
+		return nil, fmt.Errorf("%s: %w", op, ErrSameUser)
+	}
+
+	user, err := a.users.MergeUsers(ctx, primaryID, duplicateID, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrUserNotFound):
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		case errors.Is(err, storage.ErrUserMerged):
+			log.Warn("user already merged", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserMerged)
+		}
+
+		log.Error("failed to merge users", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("users merged")
+
+	a.invalidateRole(ctx, log, user.ID)
+	a.invalidateTokens(ctx, log)
+
+	return user, nil
+}
@@ -0,0 +1,138 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// AddAppOwner makes a user an owner of an app, letting the user manage it through
+// the admin API without being an admin. Adding an owner again has no effect.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - userID: ID of the user
+//
+// Returns:
+//   - error: nil on success, or an error if the owner cannot be added
+//
+// Possible errors:
+//   - ErrAppNotFound: if no app exists with the ID
+//   - ErrUserNotFound: if no user exists with the ID
+func (a *Admin) AddAppOwner(ctx context.Context, appID int32, userID int64) error {
+	const op = "admin.Admin.AddAppOwner"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+		slog.Int64("user_id", userID),
+	)
+
+	if err := a.apps.AddAppOwner(ctx, appID, userID, time.Now()); err != nil {
+		switch {
+		case errors.Is(err, storage.ErrAppNotFound):
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		case errors.Is(err, storage.ErrUserNotFound):
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to add app owner", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app owner added")
+
+	return nil
+}
+
+// RemoveAppOwner removes a user from the owners of an app. Removing a user who
+// is not an owner has no effect.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - userID: ID of the user
+//
+// Returns:
+//   - error: nil on success, or an error if the owner cannot be removed
+//
+// Possible errors:
+//   - ErrAppNotFound: if no app exists with the ID
+//   - ErrUserNotFound: if no user exists with the ID
+func (a *Admin) RemoveAppOwner(ctx context.Context, appID int32, userID int64) error {
+	const op = "admin.Admin.RemoveAppOwner"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+		slog.Int64("user_id", userID),
+	)
+
+	if err := a.apps.RemoveAppOwner(ctx, appID, userID); err != nil {
+		switch {
+		case errors.Is(err, storage.ErrAppNotFound):
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		case errors.Is(err, storage.ErrUserNotFound):
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to remove app owner", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app owner removed")
+
+	return nil
+}
+
+// ListAppOwners returns the owners of an app in order of ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//
+// Returns:
+//   - []*models.User: the owners
+//   - error: nil on success, or an error if the owners cannot be read
+//
+// Possible errors:
+//   - ErrAppNotFound: if no app exists with the ID
+func (a *Admin) ListAppOwners(ctx context.Context, appID int32) ([]*models.User, error) {
+	const op = "admin.Admin.ListAppOwners"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	owners, err := a.apps.AppOwners(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to list app owners", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return owners, nil
+}
@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/cachebus"
+)
+
+// ReloadApps drops every cached result derived from apps on every replica, e.g. after apps
+// were changed directly in the database. Apps themselves are read from storage on every call,
+// so this only concerns token validations kept by the micro cache.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - error: nil on success, or an error if the invalidation cannot be propagated to the
+//     replicas; the call can then be repeated
+func (a *Admin) ReloadApps(ctx context.Context) error {
+	const op = "admin.Admin.ReloadApps"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	// The caller asked for the reload explicitly, so unlike invalidations following a change,
+	// a failure is reported for the admin to repeat the call.
+	if err := a.cacheBus.Publish(ctx, cachebus.TopicTokens, ""); err != nil {
+		log.Error("failed to propagate app reload", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("apps reloaded")
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// purgeBatchSize is the number of device authorizations removed per transaction by RevokeAppTokens.
+const purgeBatchSize = 500
+
+// RevokeAppTokens revokes every token issued for an app so far, e.g. after its secret leaked,
+// and removes its device authorizations that were not exchanged for a token yet, so they
+// cannot be. Authorizations are removed in batches, logging progress after each.
+//
+// A leaked secret can also sign new tokens, so the app's secret must be rotated as well.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the app
+//
+// Returns:
+//   - *models.App: the app with its new revocation time
+//   - int64: number of device authorizations removed
+//   - error: nil on success, or an error if the tokens cannot be revoked
+//
+// Possible errors:
+//   - ErrAppNotFound: if no app exists with the ID
+func (a *Admin) RevokeAppTokens(ctx context.Context, appID int32) (*models.App, int64, error) {
+	const op = "admin.Admin.RevokeAppTokens"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	// Revoked first, so tokens issued from authorizations exchanged during the purge are rejected too.
+	if err := a.tokens.RevokeAppTokens(ctx, appID, time.Now()); err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return nil, 0, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to revoke app tokens", slog.String("error", err.Error()))
+
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.invalidateTokens(ctx, log)
+
+	var purged int64
+
+	for {
+		deleted, err := a.tokens.DeleteAppDeviceAuthorizations(ctx, appID, purgeBatchSize)
+		if err != nil {
+			log.Error("failed to purge device authorizations", slog.Int64("purged", purged), slog.String("error", err.Error()))
+
+			return nil, purged, fmt.Errorf("%s: %w", op, err)
+		}
+
+		purged += deleted
+
+		if deleted < purgeBatchSize {
+			break
+		}
+
+		log.Info("purging device authorizations", slog.Int64("purged", purged))
+	}
+
+	app, err := a.apps.App(ctx, appID)
+	if err != nil {
+		log.Error("failed to get app", slog.String("error", err.Error()))
+
+		return nil, purged, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Warn("app tokens revoked", slog.Int64("purged", purged))
+
+	return app, purged, nil
+}
@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// ErrSearchUnavailable is returned when users cannot be searched because their emails are stored encrypted
+var ErrSearchUnavailable = errors.New("user search is unavailable")
+
+// minSearchSimilarity is the lowest similarity of the emails returned by fuzzy searches,
+// the default threshold of the pg_trgm extension of PostgreSQL.
+const minSearchSimilarity = 0.3
+
+// SearchUsers finds users by part of their email, e.g. for support tooling. Prefix searches
+// ignore ASCII case and return users in order of email. Fuzzy searches compare the trigrams
+// of the query and the emails, so they tolerate typos, and return the most similar first.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - query: start of the emails, or text similar to them if fuzzy is set
+//   - fuzzy: whether to match similar emails rather than a prefix
+//   - userLabels: labels the users must all have; empty to not filter by labels
+//   - limit: maximum number of users returned
+//
+// Returns:
+//   - []*models.User: matching users; empty if there are none
+//   - error: nil on success, or an error if the users cannot be searched
+//
+// Possible errors:
+//   - ErrInvalidLabel: if a key or value of the label filter is invalid
+//   - ErrTooManyLabels: if userLabels has more than labels.MaxPerRecord entries
+//   - ErrSearchUnavailable: if emails are stored encrypted
+func (a *Admin) SearchUsers(
+	ctx context.Context,
+	query string,
+	fuzzy bool,
+	userLabels map[string]string,
+	limit int,
+) ([]*models.User, error) {
+	const op = "admin.Admin.SearchUsers"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Bool("fuzzy", fuzzy),
+	)
+
+	if err := validateLabels(userLabels); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		users []*models.User
+		err   error
+	)
+
+	if fuzzy {
+		users, err = a.users.UsersByEmailSimilarity(ctx, query, minSearchSimilarity, userLabels, limit)
+	} else {
+		users, err = a.users.UsersByEmailPrefix(ctx, query, userLabels, limit)
+	}
+
+	if err != nil {
+		if errors.Is(err, storage.ErrEmailsEncrypted) {
+			log.Warn("emails are encrypted", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrSearchUnavailable)
+		}
+
+		log.Error("failed to search users", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,98 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// ErrAdminAppSecret is returned when the secret of the admin app would be rotated by the service.
+var ErrAdminAppSecret = errors.New("admin app secret cannot be rotated")
+
+// appSecretBytes is the number of random bytes in a generated app secret.
+const appSecretBytes = 32
+
+// RotateAppSecret replaces the secret of an app with a random one. Owners of the app may
+// rotate it this way, since an owner choosing the secret could pick the one of another app.
+// The admin app is refused: whoever holds its secret can sign tokens admin methods accept,
+// so only admins set it with UpdateApp.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//
+// Returns:
+//   - string: the new secret
+//   - *models.App: the updated application
+//   - error: nil on success, or an error if the secret cannot be rotated
+//
+// Possible errors:
+//   - ErrAppNotFound: if no app exists with the ID
+//   - ErrAdminAppSecret: if the app is the admin app
+//   - ErrVersionConflict: if the app was modified while the secret was rotated
+func (a *Admin) RotateAppSecret(ctx context.Context, appID int32) (string, *models.App, error) {
+	const op = "admin.Admin.RotateAppSecret"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	if a.adminAppID != 0 && appID == a.adminAppID {
+		log.Warn("admin app secret cannot be rotated")
+
+		return "", nil, fmt.Errorf("%s: %w", op, ErrAdminAppSecret)
+	}
+
+	app, err := a.apps.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return "", nil, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to get app", slog.String("error", err.Error()))
+
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	b := make([]byte, appSecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		log.Error("failed to generate secret", slog.String("error", err.Error()))
+
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret := base64.RawURLEncoding.EncodeToString(b)
+
+	app, err = a.apps.UpdateApp(ctx, appID, "", secret, "", nil, nil, nil, app.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrAppNotFound):
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return "", nil, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		case errors.Is(err, storage.ErrVersionConflict):
+			log.Warn("app version conflict", slog.String("error", err.Error()))
+
+			return "", nil, fmt.Errorf("%s: %w", op, ErrVersionConflict)
+		}
+
+		log.Error("failed to save secret", slog.String("error", err.Error()))
+
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("app secret rotated", slog.Int64("version", app.Version))
+
+	a.invalidateTokens(ctx, log)
+
+	return secret, app, nil
+}
@@ -0,0 +1,79 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/services/keyring"
+)
+
+var (
+	// ErrNoSigningKey is returned when rotating the signing key without an RSA key configured
+	ErrNoSigningKey = errors.New("no RSA signing key configured")
+
+	// ErrRotationConflict is returned when another rotation of the signing key completed meanwhile
+	ErrRotationConflict = errors.New("signing key was rotated concurrently")
+)
+
+// Keyring defines the interface used to rotate the RSA keys RS256 tokens are signed with.
+type Keyring interface {
+	// Keys returns the keys in use, the signing key first.
+	Keys() []*models.SigningKey
+
+	// Rotate generates a new signing key and retires the current one.
+	Rotate(ctx context.Context) (*models.SigningKey, error)
+}
+
+// ListSigningKeys returns the RSA keys RS256 tokens are signed with and verified against,
+// as loaded by this replica: the signing key first, then the retired keys still verifying
+// tokens, newest first.
+//
+// Returns a slice of *models.SigningKey without private keys; empty if no key is configured.
+func (a *Admin) ListSigningKeys() []*models.SigningKey {
+	return a.keyring.Keys()
+}
+
+// RotateSigningKey generates a new RSA key to sign RS256 tokens with, on every replica.
+// The previous key keeps verifying tokens for token_signing.grace_period, so tokens signed
+// with it stay valid until they expire.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - *models.SigningKey: the new signing key, without its private key
+//   - error: nil on success, or an error if the key cannot be rotated
+//
+// Possible errors:
+//   - ErrNoSigningKey: if no RSA key is configured
+//   - ErrRotationConflict: if another rotation completed meanwhile
+func (a *Admin) RotateSigningKey(ctx context.Context) (*models.SigningKey, error) {
+	const op = "admin.Admin.RotateSigningKey"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	key, err := a.keyring.Rotate(ctx)
+	if err != nil {
+		if errors.Is(err, jwt.ErrNoSigningKey) {
+			log.Warn("no signing key configured")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrNoSigningKey)
+		}
+
+		if errors.Is(err, keyring.ErrRotationConflict) {
+			return nil, fmt.Errorf("%s: %w", op, ErrRotationConflict)
+		}
+
+		log.Error("failed to rotate signing key", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,195 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/disposable"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// Simulator defines the interface used to evaluate logins and registrations without performing them.
+type Simulator interface {
+	// SimulateLogin evaluates what a login of a user to an app with the given settings would yield.
+	SimulateLogin(ctx context.Context, user *models.User, app *models.App) (*models.LoginSimulation, error)
+
+	// SimulateRegistration evaluates how an email would be handled by the registration policies
+	// given the app's email domains and disposable email policy.
+	SimulateRegistration(email string, allowed, denied []string, policy disposable.Policy) *models.RegistrationSimulation
+}
+
+// EmailDomains holds the email domains allowed and denied registration through an app.
+type EmailDomains struct {
+	Allowed []string
+	Denied  []string
+}
+
+// SimulateLogin evaluates what a login of a user to an app would yield if the app's settings
+// were changed as proposed, without saving them or issuing a token.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user logging in
+//   - appID: ID of the app logged in to
+//   - tokenFormat: proposed token format; empty keeps the current one
+//   - minimalClaims, requireMembership: proposed settings; nil keeps the current ones
+//
+// Returns:
+//   - *models.LoginSimulation: whether the login would be allowed, and the claims of its token
+//   - error: nil on success, or an error if the login cannot be evaluated
+//
+// Possible errors:
+//   - ErrInvalidTokenFormat: if the token format is unknown, or RS256 without a signing key configured
+//   - ErrUserNotFound: if no user exists with the ID
+//   - ErrAppNotFound: if no app exists with the ID
+func (a *Admin) SimulateLogin(
+	ctx context.Context,
+	userID int64,
+	appID int32,
+	tokenFormat string,
+	minimalClaims *bool,
+	requireMembership *bool,
+) (*models.LoginSimulation, error) {
+	const op = "admin.Admin.SimulateLogin"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int("app_id", int(appID)),
+	)
+
+	if tokenFormat != "" && !a.validFormat(tokenFormat) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidTokenFormat)
+	}
+
+	user, err := a.users.UserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	current, err := a.apps.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to get app", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The proposal is applied to a copy, since the app may be shared with a cache.
+	app := *current
+
+	if tokenFormat != "" {
+		app.TokenFormat = tokenFormat
+	}
+
+	if minimalClaims != nil {
+		app.MinimalClaims = *minimalClaims
+	}
+
+	if requireMembership != nil {
+		app.RequireMembership = *requireMembership
+	}
+
+	simulation, err := a.simulator.SimulateLogin(ctx, user, &app)
+	if err != nil {
+		log.Error("failed to simulate login", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("login simulated", slog.Bool("allowed", simulation.Allowed), slog.String("denial_reason", simulation.DenialReason))
+
+	return simulation, nil
+}
+
+// SimulatePolicy evaluates whether an email could register through an app if the app's
+// registration policy were changed as proposed, without saving it.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - email: email registering
+//   - appID: ID of the app registered through
+//   - domains: proposed email domains of the app; nil keeps the current ones
+//   - policy: proposed disposable email policy, empty for the server default; nil keeps the current one
+//
+// Returns:
+//   - *models.RegistrationSimulation: the outcome of the registration
+//   - error: nil on success, or an error if the policy cannot be evaluated
+//
+// Possible errors:
+//   - ErrInvalidDomain: if a proposed domain is invalid
+//   - ErrDomainConflict: if a proposed domain is both allowed and denied
+//   - ErrInvalidPolicy: if the proposed disposable email policy is unknown
+//   - ErrAppNotFound: if no app exists with the ID
+func (a *Admin) SimulatePolicy(
+	ctx context.Context,
+	email string,
+	appID int32,
+	domains *EmailDomains,
+	policy *disposable.Policy,
+) (*models.RegistrationSimulation, error) {
+	const op = "admin.Admin.SimulatePolicy"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	if policy != nil && *policy != "" && !policy.Valid() {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidPolicy)
+	}
+
+	allowed, denied, err := a.apps.AppEmailDomains(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to get email domains", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if domains != nil {
+		allowed, denied, err = normalizeEmailDomains(domains.Allowed, domains.Denied)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if policy == nil {
+		current, err := a.apps.AppDisposableEmailPolicy(ctx, appID)
+		if err != nil {
+			log.Error("failed to get disposable email policy", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		p := disposable.Policy(current)
+		policy = &p
+	}
+
+	simulation := a.simulator.SimulateRegistration(email, allowed, denied, *policy)
+
+	log.Info("registration policy simulated", slog.String("outcome", simulation.Outcome), slog.String("policy", simulation.Policy))
+
+	return simulation, nil
+}
@@ -0,0 +1,258 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// SupportTokenPrefix starts every support token, telling it apart from user tokens.
+const SupportTokenPrefix = "sst_"
+
+// supportTokenBytes is the number of random bytes in a support token.
+const supportTokenBytes = 32
+
+var (
+	// ErrInvalidSupportToken is returned when a support token is unknown, expired, revoked,
+	// or its issuer is no longer an admin
+	ErrInvalidSupportToken = errors.New("invalid support token")
+
+	// ErrSupportTokenNotFound is returned when no support token exists with the given ID
+	ErrSupportTokenNotFound = errors.New("support token not found")
+
+	// ErrInvalidSupportTokenTTL is returned when a requested support token lifetime exceeds the maximum
+	ErrInvalidSupportTokenTTL = errors.New("invalid support token ttl")
+)
+
+// IssueSupportToken issues a read-only support token scoped to exactly one user or app.
+// Support tooling passes it like an admin token, but it is accepted only by read-only
+// admin methods and only for the resource it is scoped to.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - issuerID: ID of the admin issuing the token
+//   - userID: ID of the user the token is scoped to; 0 to scope it to an app
+//   - appID: ID of the app the token is scoped to; 0 to scope it to a user
+//   - ttl: lifetime of the token; 0 for support_tokens.default_ttl
+//   - reason: why the token is issued, e.g. a ticket reference
+//
+// Returns:
+//   - string: the token; only its hash is stored, so it cannot be retrieved again
+//   - *models.SupportToken: the stored token
+//   - error: nil on success, or an error if the token cannot be issued
+//
+// Possible errors:
+//   - ErrInvalidSupportTokenTTL: if ttl is negative or exceeds support_tokens.max_ttl
+//   - ErrUserNotFound: if no user exists with userID
+//   - ErrAppNotFound: if no app exists with appID
+func (a *Admin) IssueSupportToken(
+	ctx context.Context,
+	issuerID int64,
+	userID int64,
+	appID int32,
+	ttl time.Duration,
+	reason string,
+) (string, *models.SupportToken, error) {
+	const op = "admin.Admin.IssueSupportToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("issued_by", issuerID),
+		slog.Int64("user_id", userID),
+		slog.Int("app_id", int(appID)),
+	)
+
+	if ttl == 0 {
+		ttl = a.supportCfg.DefaultTTL
+	}
+
+	if ttl < 0 || ttl > a.supportCfg.MaxTTL {
+		return "", nil, fmt.Errorf("%s: %w", op, ErrInvalidSupportTokenTTL)
+	}
+
+	if userID != 0 {
+		if _, err := a.users.UserByID(ctx, userID); err != nil {
+			if errors.Is(err, storage.ErrUserNotFound) {
+				log.Warn("user not found", slog.String("error", err.Error()))
+
+				return "", nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+			}
+
+			log.Error("failed to get user", slog.String("error", err.Error()))
+
+			return "", nil, fmt.Errorf("%s: %w", op, err)
+		}
+	} else {
+		if _, err := a.apps.App(ctx, appID); err != nil {
+			if errors.Is(err, storage.ErrAppNotFound) {
+				log.Warn("app not found", slog.String("error", err.Error()))
+
+				return "", nil, fmt.Errorf("%s: %w", op, ErrAppNotFound)
+			}
+
+			log.Error("failed to get app", slog.String("error", err.Error()))
+
+			return "", nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	token, hash, err := newSupportToken()
+	if err != nil {
+		log.Error("failed to generate support token", slog.String("error", err.Error()))
+
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+
+	supportToken := &models.SupportToken{
+		IssuedBy:  issuerID,
+		UserID:    userID,
+		AppID:     appID,
+		Reason:    reason,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+
+	supportToken.ID, err = a.tokens.SaveSupportToken(ctx, supportToken, hash)
+	if err != nil {
+		log.Error("failed to save support token", slog.String("error", err.Error()))
+
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("support token issued",
+		slog.Int64("support_token_id", supportToken.ID),
+		slog.String("reason", reason),
+		slog.Time("expires_at", supportToken.ExpiresAt),
+	)
+
+	return token, supportToken, nil
+}
+
+// RevokeSupportToken revokes a support token before it expires.
+// Revoking an already revoked token succeeds.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - tokenID: ID of the token, as returned by IssueSupportToken
+//
+// Returns:
+//   - error: nil on success, or an error if the token cannot be revoked
+//
+// Possible errors:
+//   - ErrSupportTokenNotFound: if no token exists with the ID
+func (a *Admin) RevokeSupportToken(ctx context.Context, tokenID int64) error {
+	const op = "admin.Admin.RevokeSupportToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("support_token_id", tokenID),
+	)
+
+	if err := a.tokens.RevokeSupportToken(ctx, tokenID, time.Now()); err != nil {
+		if errors.Is(err, storage.ErrSupportTokenNotFound) {
+			log.Warn("support token not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrSupportTokenNotFound)
+		}
+
+		log.Error("failed to revoke support token", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("support token revoked")
+
+	return nil
+}
+
+// ValidateSupportToken verifies a support token and returns it.
+// Tokens stop working once their issuer loses admin privileges.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - token: token returned by IssueSupportToken
+//
+// Returns:
+//   - *models.SupportToken: the verified token
+//   - error: nil on success, or an error if the token cannot be verified
+//
+// Possible errors:
+//   - ErrInvalidSupportToken: if the token is unknown, expired, or revoked, or its issuer is not an admin
+func (a *Admin) ValidateSupportToken(ctx context.Context, token string) (*models.SupportToken, error) {
+	const op = "admin.Admin.ValidateSupportToken"
+
+	log := a.log.With(slog.String("op", op))
+
+	if !strings.HasPrefix(token, SupportTokenPrefix) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidSupportToken)
+	}
+
+	hash := sha256.Sum256([]byte(token))
+
+	supportToken, err := a.tokens.SupportTokenByHash(ctx, hash[:])
+	if err != nil {
+		if errors.Is(err, storage.ErrSupportTokenNotFound) {
+			log.Warn("unknown support token")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidSupportToken)
+		}
+
+		log.Error("failed to get support token", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("support_token_id", supportToken.ID))
+
+	if !supportToken.RevokedAt.IsZero() || !time.Now().Before(supportToken.ExpiresAt) {
+		log.Warn("expired or revoked support token")
+
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidSupportToken)
+	}
+
+	issuer, err := a.users.UserByID(ctx, supportToken.IssuedBy)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("support token issuer not found")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidSupportToken)
+		}
+
+		log.Error("failed to get support token issuer", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !issuer.IsAdmin {
+		log.Warn("support token issuer is no longer an admin", slog.Int64("issued_by", issuer.ID))
+
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidSupportToken)
+	}
+
+	return supportToken, nil
+}
+
+// newSupportToken generates a random support token and the SHA-256 hash under which it is stored.
+func newSupportToken() (token string, hash []byte, err error) {
+	b := make([]byte, supportTokenBytes)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+
+	token = SupportTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	sum := sha256.Sum256([]byte(token))
+
+	return token, sum[:], nil
+}
@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// SetAppTestOnly sets whether test tokens may be minted for an app with MintTestToken. Only
+// apps of services under test should be flagged, since anyone allowed to mint can name any
+// user in the tokens; this service rejects them, but the app's relying parties may not.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - testOnly: whether test tokens may be minted for the app
+//
+// Returns:
+//   - error: nil on success, or an error if the flag cannot be saved
+//
+// Possible errors:
+//   - ErrAppNotFound: if no app exists with the ID
+func (a *Admin) SetAppTestOnly(ctx context.Context, appID int32, testOnly bool) error {
+	const op = "admin.Admin.SetAppTestOnly"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	if err := a.apps.SetAppTestOnly(ctx, appID, testOnly); err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to save test_only", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("test_only updated", slog.Bool("test_only", testOnly))
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// ErrInvalidTokenTTL is returned when a token lifetime is negative or not a whole number of seconds
+var ErrInvalidTokenTTL = errors.New("invalid token ttl")
+
+// SetAppTokenTTL sets the lifetime of tokens issued for an app, overriding the token_ttl of
+// the config, e.g. to keep tokens of a sensitive app short-lived. It applies to tokens
+// issued from then on.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - ttl: lifetime of issued tokens in whole seconds, or 0 to use the token_ttl of the config
+//
+// Returns:
+//   - error: nil on success, or an error if the lifetime cannot be saved
+//
+// Possible errors:
+//   - ErrInvalidTokenTTL: if ttl is negative or not a whole number of seconds
+//   - ErrAppNotFound: if no app exists with the ID
+func (a *Admin) SetAppTokenTTL(ctx context.Context, appID int32, ttl time.Duration) error {
+	const op = "admin.Admin.SetAppTokenTTL"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	if ttl < 0 || ttl%time.Second != 0 {
+		return fmt.Errorf("%s: %w", op, ErrInvalidTokenTTL)
+	}
+
+	if err := a.apps.SetAppTokenTTL(ctx, appID, ttl); err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrAppNotFound)
+		}
+
+		log.Error("failed to save token ttl", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("token ttl updated", slog.Duration("ttl", ttl))
+
+	return nil
+}
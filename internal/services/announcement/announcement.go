@@ -0,0 +1,93 @@
+// Package announcement keeps the announcement operators attach to every response,
+// e.g. of upcoming maintenance. It is held in memory, so attaching it costs no storage
+// query per request, and reloaded whenever it changes on any replica.
+package announcement
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+)
+
+// reloadTimeout bounds the storage query reloading the announcement after it changed.
+const reloadTimeout = time.Second
+
+// Storage defines the interface used to read the announcement.
+type Storage interface {
+	// Announcement returns the announcement, or nil if there is none.
+	Announcement(ctx context.Context) (*models.Announcement, error)
+}
+
+// Board holds the current announcement, safe for concurrent use.
+type Board struct {
+	log     *slog.Logger // logger for reload failures
+	storage Storage      // storage the announcement is read from
+
+	loadMu  sync.Mutex                          // serializes loads, so the latest read is stored last
+	current atomic.Pointer[models.Announcement] // announcement last read; nil if there is none
+}
+
+// New creates a board without an announcement. Call Load to read it from storage.
+//
+// Parameters:
+//   - log: logger for reload failures
+//   - storage: storage the announcement is read from
+//
+// Returns a new *Board instance ready to use.
+func New(log *slog.Logger, storage Storage) *Board {
+	return &Board{
+		log:     log,
+		storage: storage,
+	}
+}
+
+// Load reads the announcement from storage.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - error: non-nil if the announcement cannot be read, in which case the previous one is kept
+func (b *Board) Load(ctx context.Context) error {
+	const op = "announcement.Board.Load"
+
+	b.loadMu.Lock()
+	defer b.loadMu.Unlock()
+
+	a, err := b.storage.Announcement(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	b.current.Store(a)
+
+	return nil
+}
+
+// Invalidate reloads the announcement after it changed on this or another replica.
+// It is subscribed to the announcement topic of the cache bus, which has no key.
+func (b *Board) Invalidate(string) {
+	const op = "announcement.Board.Invalidate"
+
+	ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+	defer cancel()
+
+	if err := b.Load(ctx); err != nil {
+		b.log.Error("failed to reload announcement", slog.String("op", op), slog.String("error", err.Error()))
+	}
+}
+
+// Current returns the announcement shown now, or nil if there is none or it has expired.
+func (b *Board) Current() *models.Announcement {
+	a := b.current.Load()
+	if !a.Active(time.Now()) {
+		return nil
+	}
+
+	return a
+}
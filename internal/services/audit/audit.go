@@ -0,0 +1,168 @@
+// Package audit maintains a tamper-evident log of calls to sensitive API methods.
+//
+// Every event stores the hash of its predecessor, so modifying, inserting, or
+// removing an event breaks the chain from that point on. When a key is configured,
+// hashes are HMAC-SHA256 signatures, so the chain cannot be rebuilt without the key.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+)
+
+// verifyBatchSize is the number of events loaded at once during verification.
+const verifyBatchSize = 1000
+
+// genesisHash is the predecessor hash of the first event.
+var genesisHash = make([]byte, sha256.Size)
+
+// Audit records and verifies audit events.
+type Audit struct {
+	log     *slog.Logger // logger for structured logging
+	storage Storage      // storage dependency for data persistence
+	key     []byte       // HMAC key; events are hashed with plain SHA-256 when empty
+}
+
+// Storage defines the interface that must be implemented by any storage provider
+// used by the Audit service.
+type Storage interface {
+	// AppendAuditEvent appends an event to the audit log. Within the same transaction
+	// as the insert, it calls link with the most recent event, or nil if there is none,
+	// so the event is linked to the latest one even when several processes share the log.
+	AppendAuditEvent(ctx context.Context, event *models.AuditEvent, link func(last *models.AuditEvent)) error
+
+	// AuditEvents retrieves up to limit events with IDs greater than afterID, oldest first.
+	AuditEvents(ctx context.Context, afterID int64, limit int) ([]models.AuditEvent, error)
+}
+
+// New creates a new instance of the Audit service.
+//
+// Parameters:
+//   - log: logger instance for structured logging
+//   - storage: storage implementation for data persistence
+//   - key: secret used to sign events; empty to only hash them
+//
+// Returns a new *Audit instance ready to use.
+func New(log *slog.Logger, storage Storage, key string) *Audit {
+	return &Audit{
+		log:     log,
+		storage: storage,
+		key:     []byte(key),
+	}
+}
+
+// Record appends an event to the audit log, linking it to the latest event.
+// The event's ID and hashes are assigned by Record.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - event: event to record
+//
+// Returns:
+//   - error: non-nil if the event cannot be stored
+func (a *Audit) Record(ctx context.Context, event *models.AuditEvent) error {
+	const op = "audit.Audit.Record"
+
+	err := a.storage.AppendAuditEvent(ctx, event, func(last *models.AuditEvent) {
+		event.ID, event.PrevHash = 1, genesisHash
+		if last != nil {
+			event.ID, event.PrevHash = last.ID+1, last.Hash
+		}
+
+		event.Hash = a.hash(event)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Verify checks that every event matches its hash and links to its predecessor.
+// Verification stops at the first event that does not.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - *models.AuditVerification: the result of the check
+//   - error: non-nil if the events cannot be read
+func (a *Audit) Verify(ctx context.Context) (*models.AuditVerification, error) {
+	const op = "audit.Audit.Verify"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	result := &models.AuditVerification{Valid: true, HeadHash: genesisHash}
+
+	var lastID int64
+
+	for {
+		events, err := a.storage.AuditEvents(ctx, lastID, verifyBatchSize)
+		if err != nil {
+			log.Error("failed to read audit events", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		for i := range events {
+			event := &events[i]
+
+			if event.ID != lastID+1 || !bytes.Equal(event.PrevHash, result.HeadHash) || !hmac.Equal(event.Hash, a.hash(event)) {
+				log.Warn("audit log chain is broken", slog.Int64("event_id", event.ID))
+
+				result.Valid = false
+				result.FirstInvalidID = event.ID
+
+				return result, nil
+			}
+
+			lastID = event.ID
+			result.Events++
+			result.HeadHash = event.Hash
+		}
+
+		if len(events) < verifyBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// hash computes the hash of an event and its predecessor hash.
+// Strings are length-prefixed so that distinct events never encode identically.
+// The source IP is only hashed when set, so events recorded before it was kept still verify.
+func (a *Audit) hash(event *models.AuditEvent) []byte {
+	var h hash.Hash
+	if len(a.key) > 0 {
+		h = hmac.New(sha256.New, a.key)
+	} else {
+		h = sha256.New()
+	}
+
+	h.Write(event.PrevHash)
+
+	for _, n := range []int64{event.ID, event.At.Unix(), event.ActorUserID} {
+		binary.Write(h, binary.BigEndian, n)
+	}
+
+	strs := []string{event.Method, event.Target, event.Code}
+	if event.SourceIP != "" {
+		strs = append(strs, event.SourceIP)
+	}
+
+	for _, s := range strs {
+		binary.Write(h, binary.BigEndian, uint32(len(s)))
+		h.Write([]byte(s))
+	}
+
+	return h.Sum(nil)
+}
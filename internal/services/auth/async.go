@@ -0,0 +1,213 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+var (
+	// ErrAsyncRegistrationDisabled is returned when async registration is requested but has no workers
+	ErrAsyncRegistrationDisabled = errors.New("async registration is disabled")
+
+	// ErrRegistrationQueueFull is returned when too many async registrations are waiting for a worker
+	ErrRegistrationQueueFull = errors.New("registration queue is full")
+
+	// ErrRegistrationNotFound is returned when a registration ID is unknown or its status has expired
+	ErrRegistrationNotFound = errors.New("registration not found")
+)
+
+// registrationJob is an admitted async registration waiting for a worker. The password
+// is only ever kept in memory, so jobs queued when the server stops are lost.
+type registrationJob struct {
+	id             int64 // ID of the stored registration
+	email          string
+	password       string
+	appID          int32
+	flagDisposable bool
+}
+
+// RegisterAsync admits a registration like Register, but leaves hashing the password and
+// creating the account to the workers started by RunRegistrationWorkers, so it returns
+// without waiting for bcrypt.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - email: user's email address (must be unique)
+//   - password: user's password
+//   - appID: ID of the application the user registers through; 0 for none
+//   - client: client the registration comes from, counted against the daily quota of its address
+//
+// Returns:
+//   - string: registration ID to pass to GetRegistrationStatus
+//   - error: nil if the registration was queued, or an error otherwise
+//
+// Possible errors:
+//   - ErrAsyncRegistrationDisabled: if registration.async.workers is 0
+//   - ErrRegistrationQueueFull: if too many registrations are waiting for a worker
+//   - any error of Register, except ErrUserExists for emails registered while the request is queued,
+//     which GetRegistrationStatus reports instead
+func (a *Auth) RegisterAsync(ctx context.Context, email string, password string, appID int32, client ClientInfo) (string, error) {
+	const op = "auth.Auth.RegisterAsync"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	if a.registrations == nil {
+		return "", fmt.Errorf("%s: %w", op, ErrAsyncRegistrationDisabled)
+	}
+
+	flagDisposable, err := a.admitRegistration(ctx, log, email, appID, client)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Existing emails are the most common failure, so they are reported at once rather than by the worker.
+	if _, err := a.users.User(ctx, email); err == nil {
+		log.Warn("user already exists")
+
+		return "", fmt.Errorf("%s: %w", op, ErrUserExists)
+	} else if !errors.Is(err, storage.ErrUserNotFound) {
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Registration IDs share the format of password reset tokens.
+	registrationID, registrationIDHash, err := newResetToken()
+	if err != nil {
+		log.Error("failed to generate registration id", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := a.clock.Now()
+
+	id, err := a.users.SaveRegistration(ctx, &models.Registration{
+		RegistrationIDHash: registrationIDHash,
+		CreatedAt:          now,
+		ExpiresAt:          now.Add(a.asyncCfg.StatusTTL),
+	})
+	if err != nil {
+		log.Error("failed to save registration", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	select {
+	case a.registrations <- registrationJob{
+		id:             id,
+		email:          email,
+		password:       password,
+		appID:          appID,
+		flagDisposable: flagDisposable,
+	}:
+	default:
+		log.Warn("registration queue is full", slog.Int("queue_size", cap(a.registrations)))
+
+		if err := a.users.FinishRegistration(ctx, id, 0, models.RegistrationFailed, models.RegistrationFailedInternal); err != nil {
+			log.Error("failed to record registration failure", slog.String("error", err.Error()))
+		}
+
+		return "", fmt.Errorf("%s: %w", op, ErrRegistrationQueueFull)
+	}
+
+	log.Info("registration queued", slog.Int64("registration", id))
+
+	return registrationID, nil
+}
+
+// RunRegistrationWorkers creates the accounts of async registrations until ctx is canceled.
+// A registration being processed when ctx is canceled is still completed.
+func (a *Auth) RunRegistrationWorkers(ctx context.Context) {
+	for range a.asyncCfg.Workers {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-a.registrations:
+					a.processRegistration(context.WithoutCancel(ctx), job)
+				}
+			}
+		}()
+	}
+}
+
+// processRegistration creates the account of an async registration and records the outcome.
+func (a *Auth) processRegistration(ctx context.Context, job registrationJob) {
+	const op = "auth.Auth.processRegistration"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("registration", job.id),
+	)
+
+	status, reason := models.RegistrationCompleted, ""
+
+	userID, _, err := a.createUser(ctx, log, job.email, job.password, job.appID, job.flagDisposable)
+	switch {
+	case errors.Is(err, ErrUserExists):
+		status, reason = models.RegistrationFailed, models.RegistrationFailedUserExists
+	case err != nil:
+		status, reason = models.RegistrationFailed, models.RegistrationFailedInternal
+	}
+
+	if err := a.users.FinishRegistration(ctx, job.id, userID, status, reason); err != nil {
+		log.Error("failed to record registration outcome", slog.String("error", err.Error()))
+	}
+}
+
+// GetRegistrationStatus reports the progress of an async registration.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - registrationID: ID returned by RegisterAsync
+//
+// Returns:
+//   - *models.Registration: the registration; pending ones older than registration.async.timeout
+//     are reported as failed, since the server stopped before processing them
+//   - error: nil on success, or an error if the status cannot be read
+//
+// Possible errors:
+//   - ErrRegistrationNotFound: if the registration ID is unknown or its status has expired
+//   - other errors: for any other failure
+func (a *Auth) GetRegistrationStatus(ctx context.Context, registrationID string) (*models.Registration, error) {
+	const op = "auth.Auth.GetRegistrationStatus"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	sum := sha256.Sum256([]byte(registrationID))
+
+	reg, err := a.users.Registration(ctx, sum[:])
+	if err != nil {
+		if errors.Is(err, storage.ErrRegistrationNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, ErrRegistrationNotFound)
+		}
+
+		log.Error("failed to get registration", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := a.clock.Now()
+
+	if !now.Before(reg.ExpiresAt) {
+		return nil, fmt.Errorf("%s: %w", op, ErrRegistrationNotFound)
+	}
+
+	if reg.Status == models.RegistrationPending && now.Sub(reg.CreatedAt) > a.asyncCfg.Timeout {
+		reg.Status = models.RegistrationFailed
+		reg.FailureReason = models.RegistrationFailedInterrupted
+	}
+
+	return reg, nil
+}
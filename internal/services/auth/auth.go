@@ -4,30 +4,129 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"sync"
 	"time"
 
+	"github.com/kirinyoku/sso-grpc/internal/config"
 	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/clock"
+	"github.com/kirinyoku/sso-grpc/internal/lib/disposable"
+	"github.com/kirinyoku/sso-grpc/internal/lib/emaildomain"
+	"github.com/kirinyoku/sso-grpc/internal/lib/events"
+	"github.com/kirinyoku/sso-grpc/internal/lib/ids"
 	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/lib/mailer"
+	"github.com/kirinyoku/sso-grpc/internal/lib/templates"
 	"github.com/kirinyoku/sso-grpc/internal/storage"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // Auth provides authentication and authorization services.
 type Auth struct {
-	log      *slog.Logger  // logger for structured logging
-	storage  Storage       // storage dependency for data persistence
-	tokenTTL time.Duration // duration for which JWT tokens are valid
+	log              *slog.Logger               // logger for structured logging
+	users            UserRepository             // users and their registrations
+	apps             AppRepository              // apps, their families, members, and owners
+	tokens           TokenRepository            // single-use tokens, device authorizations, and login challenges
+	mailer           Mailer                     // delivery of emails such as password reset tokens
+	templates        Templates                  // email templates
+	tokenTTL         time.Duration              // duration for which tokens are valid, unless their app sets its own
+	region           string                     // region embedded in issued tokens
+	standardClaims   jwt.StandardClaims         // optional registered claims added to issued tokens
+	resetCfg         config.PasswordReset       // password reset token policy
+	allowed          []string                   // email domains allowed to register in every app
+	denied           []string                   // email domains denied registration in every app
+	hooks            []Hook                     // extension points run around registration and login
+	disposableList   DisposableList             // blocklist of disposable email providers
+	hasher           PasswordHasher             // hashing and verification of passwords
+	auditor          Auditor                    // audit log of actions taken without an authenticated caller
+	events           Events                     // publishes registrations and logins to the subsystems reacting to them
+	avatars          BlobStore                  // storage of avatar images; nil when avatars are disabled
+	signingKeys      *jwt.Keys                  // RSA keys signing RS256 tokens; nil when none are configured
+	disposablePolicy disposable.Policy          // handling of disposable emails for apps without their own policy
+	idStrategy       ids.Strategy               // format of public IDs of new users
+	quota            *registrationQuota         // registration attempts accepted per source address and day
+	enforcement      policyModes                // whether violations of each policy are rejected or only reported
+	asyncCfg         config.AsyncRegistration   // background account creation settings
+	registrations    chan registrationJob       // async registrations waiting for a worker; nil when disabled
+	deviceCfg        config.DeviceAuthorization // device authorization grant settings
+	pushMFACfg       config.PushMFA             // how long logins wait for push approval
+	stepUpCfg        config.StepUp              // how recently a second factor must be approved for sensitive actions
+	validationCfg    config.TokenValidation     // clock skew leeway and refresh grace window
+	refreshCfg       config.RefreshTokens       // lifetime of refresh token chains; refresh tokens are not issued if 0
+	notificationsCfg config.LoginNotifications  // emails sent after every login
+	attributesCfg    config.UserAttributes      // whether user attributes are added to issued tokens
+	secretLeaksCfg   config.SecretLeaks         // who is notified when a leaked app secret is rotated
+	avatarsCfg       config.Avatars             // size limit and URLs of avatars
+	testTokensCfg    config.TestTokens          // whether test tokens may be minted, and their longest lifetime
+	reportURL        *url.URL                   // page linked from login notifications; nil when they are disabled
+	timeoutsCfg      config.StorageTimeouts     // deadlines of storage queries and how stale their fallbacks may be
+	rolesMu          sync.Mutex                 // guards roles
+	roles            map[int64]cachedRole       // admin status last read for each user, served when storage is slow
+	validatedMu      sync.Mutex                 // guards validated
+	validated        map[tokenHash]validation   // claims of recently validated tokens, reused for the micro cache TTL
+	clock            clock.Clock                // source of the current time
 }
 
-// Storage defines the interface that must be implemented by any storage provider
-// used by the Auth service.
-type Storage interface {
-	// SaveUser persists a new user with the given email and password hash.
-	// Returns the ID of the created user or an error if the operation fails.
-	SaveUser(ctx context.Context, email string, passHash []byte) (int64, error)
+// DisposableList defines the interface used to detect disposable email providers.
+type DisposableList interface {
+	// Contains reports whether domain belongs to a disposable email provider.
+	Contains(domain string) bool
+}
+
+// PasswordHasher defines the interface used to hash and verify passwords.
+type PasswordHasher interface {
+	// Hash returns the hash of password.
+	Hash(password string) ([]byte, error)
+	// Compare returns nil if password matches hash, or an error otherwise.
+	Compare(hash []byte, password string) error
+}
+
+// Auditor defines the interface used to record audit events.
+type Auditor interface {
+	// Record appends an event to the audit log.
+	Record(ctx context.Context, event *models.AuditEvent) error
+}
+
+// Events defines the interface used to publish domain events.
+type Events interface {
+	// Publish delivers an event to the handlers subscribed to its type.
+	Publish(ctx context.Context, event events.Event)
+}
+
+// BlobStore defines the interface used to store avatar images under keys derived from their content.
+type BlobStore interface {
+	// Put stores an object under a key, keeping the object already stored under it.
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// Mailer defines the interface used to deliver emails to users.
+type Mailer interface {
+	// Send delivers msg to its recipient.
+	Send(ctx context.Context, msg mailer.Message) error
+}
+
+// Templates defines the interface used to render outbound emails.
+type Templates interface {
+	// Render renders the named template for the app and locale.
+	Render(name string, appID int32, locale string, data map[string]string) (*templates.Email, error)
+}
+
+// UserRepository defines the storage of users, their attributes, and their async
+// registrations used by the Auth service.
+type UserRepository interface {
+	// SaveUser persists a new user with the given email, password hash, and public ID.
+	// An empty public ID selects the decimal form of the sequential ID.
+	// Returns the ID and public ID of the created user or an error if the operation fails.
+	SaveUser(ctx context.Context, email string, passHash []byte, publicID string) (int64, string, error)
+
+	// UserByPublicID retrieves a user by public identifier.
+	UserByPublicID(ctx context.Context, publicID string) (*models.User, error)
 
 	// User retrieves a user by email.
 	// Returns the user if found, or an error if the user doesn't exist or the operation fails.
@@ -37,9 +136,152 @@ type Storage interface {
 	// Returns true if the user is an admin, false otherwise.
 	IsAdmin(ctx context.Context, userID int64) (bool, error)
 
+	// FlagDisposableEmail marks a user as registered with a disposable email address.
+	FlagDisposableEmail(ctx context.Context, userID int64) error
+
+	// RecordLogin records that a user logged in, for activity statistics.
+	RecordLogin(ctx context.Context, userID int64, at time.Time) error
+
+	// UserByID retrieves a user by ID.
+	UserByID(ctx context.Context, userID int64) (*models.User, error)
+
+	// SetPushMFA enables or disables push MFA for a user.
+	SetPushMFA(ctx context.Context, userID int64, enabled bool) error
+
+	// SetUserAvatar sets the key of a user's avatar in blob storage.
+	SetUserAvatar(ctx context.Context, userID int64, avatar string) error
+
+	// FreezeUser freezes a user's account and revokes every token issued to the user so far.
+	FreezeUser(ctx context.Context, userID int64, at time.Time) error
+
+	// UserAttributes returns the custom attributes of a user.
+	UserAttributes(ctx context.Context, userID int64) (map[string]string, error)
+
+	// SaveRegistration persists a new pending async registration and returns its ID.
+	SaveRegistration(ctx context.Context, reg *models.Registration) (int64, error)
+
+	// Registration retrieves an async registration by the hash of its registration ID.
+	Registration(ctx context.Context, registrationIDHash []byte) (*models.Registration, error)
+
+	// FinishRegistration records the outcome of a pending async registration.
+	FinishRegistration(ctx context.Context, id int64, userID int64, status string, failureReason string) error
+}
+
+// AppRepository defines the storage of apps, their families, members, and owners
+// used by the Auth service.
+type AppRepository interface {
+	// IsAppOwner checks if a user is an owner of an application.
+	IsAppOwner(ctx context.Context, appID int32, userID int64) (bool, error)
+
+	// AppsOwnedBy lists the IDs of the applications a user owns.
+	AppsOwnedBy(ctx context.Context, userID int64) ([]int32, error)
+
 	// App retrieves application information by ID.
 	// Returns the app if found, or an error if the app doesn't exist or the operation fails.
 	App(ctx context.Context, appID int32) (*models.App, error)
+
+	// AppEmailDomains returns the email domain lists restricting registration for an app.
+	AppEmailDomains(ctx context.Context, appID int32) (allowed []string, denied []string, err error)
+
+	// AppDisposableEmailPolicy returns an app's disposable email policy, or an empty string for the default.
+	AppDisposableEmailPolicy(ctx context.Context, appID int32) (string, error)
+
+	// IsAppMember reports whether a user was granted access to an app.
+	IsAppMember(ctx context.Context, appID int32, userID int64) (bool, error)
+
+	// AppFamily retrieves an app family along with the IDs of its apps.
+	AppFamily(ctx context.Context, familyID int32) (*models.AppFamily, error)
+
+	// AppBySecret retrieves the application using a secret.
+	AppBySecret(ctx context.Context, secret string) (*models.App, error)
+
+	// ReplaceLeakedAppSecret replaces an app's leaked secret, revokes every token issued for the app,
+	// and removes its pending device authorizations. Returns the number of authorizations removed.
+	ReplaceLeakedAppSecret(ctx context.Context, appID int32, leaked string, secret string, at time.Time) (int64, error)
+}
+
+// TokenRepository defines the storage of single-use tokens, device authorizations,
+// login challenges, and token revocations used by the Auth service.
+type TokenRepository interface {
+	// SavePasswordResetToken persists the hash of a newly issued password reset token.
+	SavePasswordResetToken(ctx context.Context, userID int64, tokenHash []byte, createdAt time.Time, expiresAt time.Time) error
+
+	// CountPasswordResetTokens counts the reset tokens issued to a user since the given moment.
+	CountPasswordResetTokens(ctx context.Context, userID int64, since time.Time) (int, error)
+
+	// ResetPassword consumes a reset token and replaces the owner's password hash.
+	// Returns the ID of the user, or an error if the token is unknown, used, or expired.
+	ResetPassword(ctx context.Context, tokenHash []byte, passHash []byte, now time.Time) (int64, error)
+
+	// SaveDeviceAuthorization persists a new pending device authorization.
+	SaveDeviceAuthorization(ctx context.Context, auth *models.DeviceAuthorization) error
+
+	// DeviceAuthorization retrieves a device authorization by the hash of its device code.
+	DeviceAuthorization(ctx context.Context, deviceCodeHash []byte) (*models.DeviceAuthorization, error)
+
+	// DecideDeviceAuthorization records a user's approval or denial of a pending device authorization.
+	// Returns the ID of the app the device requested access to.
+	DecideDeviceAuthorization(ctx context.Context, userCode string, userID int64, status string, now time.Time) (int32, error)
+
+	// PollDeviceAuthorization records a poll of a device authorization and its new polling interval.
+	PollDeviceAuthorization(ctx context.Context, id int64, polledAt time.Time, interval time.Duration) error
+
+	// ConsumeDeviceAuthorization marks an approved device authorization as consumed.
+	ConsumeDeviceAuthorization(ctx context.Context, id int64) error
+
+	// SaveLoginChallenge persists a new pending login challenge.
+	SaveLoginChallenge(ctx context.Context, challenge *models.LoginChallenge) error
+
+	// LoginChallenge retrieves a login challenge by the hash of its ID.
+	LoginChallenge(ctx context.Context, challengeHash []byte) (*models.LoginChallenge, error)
+
+	// PendingLoginChallenges lists a user's pending, unexpired login challenges.
+	PendingLoginChallenges(ctx context.Context, userID int64, now time.Time) ([]models.LoginChallenge, error)
+
+	// DecideLoginChallenge records a user's approval or denial of one of their pending login challenges.
+	DecideLoginChallenge(ctx context.Context, id, userID int64, status string, now time.Time) error
+
+	// ConsumeLoginChallenge marks an approved login challenge as consumed.
+	ConsumeLoginChallenge(ctx context.Context, id int64) error
+
+	// SaveRefreshToken persists a refresh token, starting a new chain if it has none.
+	SaveRefreshToken(ctx context.Context, token *models.RefreshToken) error
+
+	// RefreshToken retrieves a refresh token by its hash, whether or not it was revoked or expired.
+	RefreshToken(ctx context.Context, tokenHash []byte) (*models.RefreshToken, error)
+
+	// RotateRefreshToken revokes an unrevoked refresh token and saves the next token of its chain.
+	RotateRefreshToken(ctx context.Context, id int64, next *models.RefreshToken) error
+
+	// RevokeRefreshTokenChain revokes every unrevoked token of a refresh token chain.
+	RevokeRefreshTokenChain(ctx context.Context, chainID int64, at time.Time) error
+
+	// UserSessions lists a user's active sessions, most recently used first.
+	UserSessions(ctx context.Context, userID int64, now time.Time) ([]models.Session, error)
+
+	// RevokeSession revokes the refresh token chain of one of a user's active sessions.
+	RevokeSession(ctx context.Context, id, userID int64, now time.Time) error
+
+	// RevokeUserTokens revokes every token issued to a user so far.
+	RevokeUserTokens(ctx context.Context, userID int64, at time.Time) error
+
+	// SaveUnfreezeToken persists the hash of a newly issued unfreeze token.
+	SaveUnfreezeToken(ctx context.Context, userID int64, tokenHash []byte, createdAt time.Time, expiresAt time.Time) error
+
+	// CountUnfreezeTokens counts the unfreeze tokens issued to a user since the given moment.
+	CountUnfreezeTokens(ctx context.Context, userID int64, since time.Time) (int, error)
+
+	// UnfreezeUser consumes an unfreeze token, replaces the owner's password hash, and unfreezes the account.
+	// Returns the ID of the user, or an error if the token is unknown, used, or expired.
+	UnfreezeUser(ctx context.Context, tokenHash []byte, passHash []byte, now time.Time) (int64, error)
+}
+
+// Storage combines the repositories the Auth service depends on. A single storage
+// provider implements them all; the service only calls each through the narrowest one.
+type Storage interface {
+	UserRepository
+	AppRepository
+	TokenRepository
 }
 
 // Common authentication errors
@@ -55,22 +297,225 @@ var (
 
 	// ErrUserNotFound is returned when a user is not found
 	ErrUserNotFound = errors.New("user not found")
+
+	// ErrInvalidToken is returned when a token cannot be verified
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrRefreshTokenRequired is returned when a token is refreshed with itself while refresh tokens are enabled
+	ErrRefreshTokenRequired = errors.New("refresh token required")
+
+	// ErrInvalidResetToken is returned when a password reset token is unknown, already used, or expired
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+
+	// ErrNotAppMember is returned when a user is issued a token for an app that requires membership
+	// without having been granted access
+	ErrNotAppMember = errors.New("user is not a member of the app")
 )
 
+// resetTokenBytes is the amount of randomness in a password reset token.
+const resetTokenBytes = 32
+
 // New creates a new instance of the Auth service with the provided dependencies.
 //
 // Parameters:
 //   - log: logger instance for structured logging
 //   - storage: storage implementation for data persistence
-//   - tokenTTL: duration for which JWT tokens should be valid
+//   - mailer: delivery of emails to users
+//   - templates: email templates
+//   - disposableList: blocklist of disposable email providers
+//   - hasher: hashing and verification of passwords
+//   - auditor: audit log recording rotations of leaked app secrets and tokens issued for approved logins
+//   - eventBus: bus publishing registrations and logins to the subsystems reacting to them
+//   - avatars: storage of avatar images; nil disables avatars
+//   - signingKeys: RSA keys signing the tokens of apps with the RS256 format; nil if none are configured
+//   - tokenTTL: duration for which tokens should be valid, unless their app sets its own
+//   - region: region of this deployment, embedded in issued tokens; empty if not geo-distributed
+//   - claimsCfg: optional standard claims added to issued tokens, and the issuer tokens must name
+//   - resetCfg: password reset token policy
+//   - registrationCfg: email domain and disposable email restrictions applied to every app,
+//     the format of public user IDs, the daily registration quota per source address,
+//     and async registration settings
+//   - enforcementCfg: whether violations of the registration and password reset policies
+//     are rejected or only reported
+//   - deviceCfg: device authorization grant settings
+//   - pushMFACfg: how long logins of users with push MFA wait for approval
+//   - stepUpCfg: how recently a second factor must be approved before sensitive actions
+//   - validationCfg: clock skew leeway and refresh grace window for token validation
+//   - refreshCfg: lifetime of refresh token chains
+//   - notificationsCfg: emails sent after every login
+//   - timeoutsCfg: deadlines of storage queries and how stale their fallbacks may be
+//   - attributesCfg: whether user attributes are added to issued tokens
+//   - secretLeaksCfg: who is notified when a leaked app secret is rotated
+//   - avatarsCfg: size limit and URLs of avatars
+//   - testTokensCfg: whether MintTestToken serves tokens, and their longest lifetime
+//   - clk: source of the current time, e.g. clock.System
+//   - hooks: optional extension points run around registration and login
 //
-// Returns a new *Auth instance ready to use.
-func New(log *slog.Logger, storage Storage, tokenTTL time.Duration) *Auth {
-	return &Auth{
-		log:      log,
-		storage:  storage,
-		tokenTTL: tokenTTL,
+// Returns:
+//   - *Auth: service ready to use
+//   - error: non-nil if registrationCfg lists an invalid domain, policy, or allowlist entry,
+//     selects sequential IDs while region is set, has invalid async settings, if enforcementCfg
+//     has an unknown mode, if the push MFA
+//     challenge TTL or a step-up max age is not positive, if the refresh token TTL is negative, or if login notifications are enabled without a key or a valid report URL
+func New(
+	log *slog.Logger,
+	storage Storage,
+	mailer Mailer,
+	templates Templates,
+	disposableList DisposableList,
+	hasher PasswordHasher,
+	auditor Auditor,
+	eventBus Events,
+	avatars BlobStore,
+	signingKeys *jwt.Keys,
+	tokenTTL time.Duration,
+	region string,
+	claimsCfg config.TokenClaims,
+	resetCfg config.PasswordReset,
+	registrationCfg config.Registration,
+	enforcementCfg config.Enforcement,
+	deviceCfg config.DeviceAuthorization,
+	pushMFACfg config.PushMFA,
+	stepUpCfg config.StepUp,
+	validationCfg config.TokenValidation,
+	refreshCfg config.RefreshTokens,
+	notificationsCfg config.LoginNotifications,
+	timeoutsCfg config.StorageTimeouts,
+	attributesCfg config.UserAttributes,
+	secretLeaksCfg config.SecretLeaks,
+	avatarsCfg config.Avatars,
+	testTokensCfg config.TestTokens,
+	clk clock.Clock,
+	hooks ...Hook,
+) (*Auth, error) {
+	const op = "auth.New"
+
+	allowed, err := emaildomain.NormalizeAll(registrationCfg.AllowedDomains)
+	if err != nil {
+		return nil, fmt.Errorf("%s: allowed_domains: %w", op, err)
+	}
+
+	denied, err := emaildomain.NormalizeAll(registrationCfg.DeniedDomains)
+	if err != nil {
+		return nil, fmt.Errorf("%s: denied_domains: %w", op, err)
+	}
+
+	disposablePolicy := disposable.Policy(registrationCfg.DisposableEmails.Policy)
+	if !disposablePolicy.Valid() {
+		return nil, fmt.Errorf("%s: unknown disposable email policy %q", op, disposablePolicy)
+	}
+
+	idStrategy := ids.Strategy(registrationCfg.IDStrategy)
+	if !idStrategy.Valid() {
+		return nil, fmt.Errorf("%s: unknown id strategy %q", op, idStrategy)
+	}
+
+	quota, err := newRegistrationQuota(registrationCfg.PerIPDailyLimit, registrationCfg.IPAllowlist)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	modes, err := enforcementModes(enforcementCfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	asyncCfg := registrationCfg.Async
+	if asyncCfg.Workers < 0 || asyncCfg.QueueSize < 0 || (asyncCfg.Workers > 0 && (asyncCfg.StatusTTL <= 0 || asyncCfg.Timeout <= 0)) {
+		return nil, fmt.Errorf("%s: async workers and queue_size must not be negative, and status_ttl and timeout must be positive", op)
+	}
+
+	var registrations chan registrationJob
+	if asyncCfg.Workers > 0 {
+		registrations = make(chan registrationJob, asyncCfg.QueueSize)
+	}
+
+	if pushMFACfg.ChallengeTTL <= 0 {
+		return nil, fmt.Errorf("%s: push_mfa challenge_ttl must be positive", op)
+	}
+
+	if stepUpCfg.MaxAge <= 0 {
+		return nil, fmt.Errorf("%s: step_up max_age must be positive", op)
+	}
+
+	for action, maxAge := range stepUpCfg.Actions {
+		if maxAge <= 0 {
+			return nil, fmt.Errorf("%s: step_up max_age of action %q must be positive", op, action)
+		}
+	}
+
+	if refreshCfg.TTL < 0 {
+		return nil, fmt.Errorf("%s: refresh_tokens ttl must not be negative", op)
+	}
+
+	if validationCfg.MicroCacheTTL < 0 || validationCfg.MicroCacheTTL > config.MaxMicroCacheTTL {
+		return nil, fmt.Errorf("%s: micro_cache_ttl must be between 0 and %s", op, config.MaxMicroCacheTTL)
 	}
+
+	// Regions assign sequential IDs independently, so they would collide once replicated.
+	if region != "" && idStrategy == ids.StrategySequential {
+		return nil, fmt.Errorf("%s: id strategy %q cannot be used in region %q", op, idStrategy, region)
+	}
+
+	var reportURL *url.URL
+	if notificationsCfg.Enabled {
+		if notificationsCfg.Key == "" {
+			return nil, fmt.Errorf("%s: login notifications require a key", op)
+		}
+
+		reportURL, err = url.Parse(notificationsCfg.ReportURL)
+		if err != nil || !reportURL.IsAbs() {
+			return nil, fmt.Errorf("%s: invalid login notifications report url %q", op, notificationsCfg.ReportURL)
+		}
+	}
+
+	return &Auth{
+		log:       log,
+		users:     storage,
+		apps:      storage,
+		tokens:    storage,
+		mailer:    mailer,
+		templates: templates,
+		tokenTTL:  tokenTTL,
+		region:    region,
+		standardClaims: jwt.StandardClaims{
+			Issuer:    claimsCfg.Issuer,
+			Audience:  claimsCfg.Audience,
+			NotBefore: claimsCfg.NotBefore,
+			TokenID:   claimsCfg.TokenID,
+		},
+		resetCfg:         resetCfg,
+		allowed:          allowed,
+		denied:           denied,
+		hooks:            hooks,
+		disposableList:   disposableList,
+		hasher:           hasher,
+		auditor:          auditor,
+		events:           eventBus,
+		avatars:          avatars,
+		signingKeys:      signingKeys,
+		disposablePolicy: disposablePolicy,
+		idStrategy:       idStrategy,
+		quota:            quota,
+		enforcement:      modes,
+		asyncCfg:         asyncCfg,
+		registrations:    registrations,
+		deviceCfg:        deviceCfg,
+		pushMFACfg:       pushMFACfg,
+		stepUpCfg:        stepUpCfg,
+		validationCfg:    validationCfg,
+		refreshCfg:       refreshCfg,
+		notificationsCfg: notificationsCfg,
+		reportURL:        reportURL,
+		timeoutsCfg:      timeoutsCfg,
+		attributesCfg:    attributesCfg,
+		secretLeaksCfg:   secretLeaksCfg,
+		avatarsCfg:       avatarsCfg,
+		testTokensCfg:    testTokensCfg,
+		roles:            make(map[int64]cachedRole),
+		validated:        make(map[tokenHash]validation),
+		clock:            clk,
+	}, nil
 }
 
 // Register creates a new user account with the provided email and password.
@@ -79,44 +524,141 @@ func New(log *slog.Logger, storage Storage, tokenTTL time.Duration) *Auth {
 //   - ctx: context for request cancellation and timeouts
 //   - email: user's email address (must be unique)
 //   - password: user's password (will be hashed before storage)
+//   - appID: ID of the application the user registers through; 0 for none
+//   - client: client the registration comes from, counted against the daily quota of its address
 //
 // Returns:
 //   - int64: ID of the newly created user
+//   - string: public ID of the newly created user
 //   - error: nil on success, or an error if registration fails
 //
 // Possible errors:
 //   - ErrUserExists: if a user with the given email already exists
+//   - ErrInvalidAppID: if appID is set but does not exist
+//   - *RejectError: if the email domain is not allowed, disposable emails are rejected,
+//     or a PreRegister hook rejected the registration
+//   - ErrRegistrationQuotaExceeded: if the client's address used up its registrations for the day
 //   - other errors: for any other failure during user creation
-func (a *Auth) Register(ctx context.Context, email string, password string) (int64, error) {
+func (a *Auth) Register(ctx context.Context, email string, password string, appID int32, client ClientInfo) (int64, string, error) {
 	const op = "auth.Auth.Register"
 
 	log := a.log.With(
 		slog.String("op", op),
 	)
 
-	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	flagDisposable, err := a.admitRegistration(ctx, log, email, appID, client)
+	if err != nil {
+		return 0, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	userID, publicID, err := a.createUser(ctx, log, email, password, appID, flagDisposable)
+	if err != nil {
+		return 0, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, publicID, nil
+}
+
+// admitRegistration applies the checks every registration passes before its password is hashed:
+// email domain restrictions, PreRegister hooks, and the daily quota of the client's address.
+// It reports whether the user should be flagged for a disposable email.
+func (a *Auth) admitRegistration(ctx context.Context, log *slog.Logger, email string, appID int32, client ClientInfo) (bool, error) {
+	flagDisposable, err := a.checkEmailDomain(ctx, log, email, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return false, ErrInvalidAppID
+		}
+
+		var rejectErr *RejectError
+		if errors.As(err, &rejectErr) {
+			log.Warn("registration rejected by domain policy", slog.String("error", err.Error()))
+
+			return false, err
+		}
+
+		log.Error("failed to check email domain", slog.String("error", err.Error()))
+
+		return false, err
+	}
+
+	if err := a.runPreHooks(func(h Hook) error { return h.PreRegister(ctx, email, appID) }); err != nil {
+		log.Warn("registration rejected by hook", slog.String("error", err.Error()))
+
+		return false, err
+	}
+
+	// Counted before hashing, so failed attempts such as existing emails use up the quota too.
+	if !a.quota.take(client.IP, a.clock.Now().Unix()) {
+		if err := a.enforce(log, policyRegistrationQuota, ErrRegistrationQuotaExceeded); err != nil {
+			log.Warn("registration quota exceeded", slog.String("ip", client.IP))
+
+			return false, err
+		}
+	}
+
+	return flagDisposable, nil
+}
+
+// createUser hashes the password and saves a user admitted by admitRegistration,
+// then publishes UserRegistered and runs the PostRegister hooks.
+func (a *Auth) createUser(
+	ctx context.Context,
+	log *slog.Logger,
+	email string,
+	password string,
+	appID int32,
+	flagDisposable bool,
+) (int64, string, error) {
+	passHash, err := a.hasher.Hash(password)
 	if err != nil {
 		log.Error("failed to generate password hash", slog.String("error", err.Error()))
 
-		return 0, fmt.Errorf("%s: %w", op, err)
+		return 0, "", err
 	}
 
-	userID, err := a.storage.SaveUser(ctx, email, passHash)
+	publicID, err := ids.New(a.idStrategy, a.clock.Now())
+	if err != nil {
+		log.Error("failed to generate public id", slog.String("error", err.Error()))
+
+		return 0, "", err
+	}
+
+	userID, publicID, err := a.users.SaveUser(ctx, email, passHash, publicID)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserExists) {
 			log.Warn("user already exists", slog.String("error", err.Error()))
 
-			return 0, fmt.Errorf("%s: %w", op, ErrUserExists)
+			return 0, "", ErrUserExists
 		}
 
 		log.Error("failed to save user", slog.String("error", err.Error()))
 
-		return 0, fmt.Errorf("%s: %w", op, err)
+		return 0, "", err
 	}
 
 	log.Info("user registered successfully", slog.Int64("user_id", userID))
 
-	return userID, nil
+	if flagDisposable {
+		log.Warn("user registered with a disposable email", slog.Int64("user_id", userID))
+
+		if err := a.users.FlagDisposableEmail(ctx, userID); err != nil {
+			log.Error("failed to flag disposable email", slog.String("error", err.Error()))
+		}
+	}
+
+	a.events.Publish(ctx, events.UserRegistered{
+		UserID:   userID,
+		PublicID: publicID,
+		Email:    email,
+		AppID:    appID,
+		At:       a.clock.Now(),
+	})
+
+	a.runPostHooks(log, func(h Hook) error { return h.PostRegister(ctx, userID, email, appID) })
+
+	return userID, publicID, nil
 }
 
 // Login authenticates a user and generates a JWT token for the specified application.
@@ -126,26 +668,40 @@ func (a *Auth) Register(ctx context.Context, email string, password string) (int
 //   - email: user's email address
 //   - password: user's password
 //   - appID: ID of the application the user is logging into
+//   - client: client the user logs in from, shown in the login notification
 //
 // Returns:
 //   - string: JWT token for authenticated sessions
 //   - error: nil on success, or an error if authentication fails
 //
 // Possible errors:
-//   - ErrInvalidCredentials: if email/password is incorrect or user doesn't exist
+//   - ErrInvalidCredentials: if email/password is incorrect, the user doesn't exist, or the account was merged into another one
 //   - ErrInvalidAppID: if the specified appID is invalid
+//   - ErrAccountFrozen: if the account is frozen and must be unfrozen first
+//   - ErrNotAppMember: if the app requires membership and the user was not granted access
+//   - *RejectError: if a PreLogin hook rejected the login
+//   - *ChallengeRequiredError: if the user has push MFA enabled; no token is issued until
+//     the user approves the challenge, and the client polls PollLoginChallenge for it
 //   - other errors: for any other failure during authentication
-func (a *Auth) Login(ctx context.Context, email string, password string, appID int32) (string, error) {
+func (a *Auth) Login(ctx context.Context, email string, password string, appID int32, client ClientInfo) (string, error) {
 	const op = "auth.Auth.Login"
 
 	log := a.log.With(
 		slog.String("op", op),
 	)
 
-	user, err := a.storage.User(ctx, email)
+	if err := a.runPreHooks(func(h Hook) error { return h.PreLogin(ctx, email, appID) }); err != nil {
+		log.Warn("login rejected by hook", slog.String("error", err.Error()))
+		a.loginFailed(ctx, email, 0, appID, events.FailureRejected, client)
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.users.User(ctx, email)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserNotFound) {
 			log.Warn("user not found", slog.String("error", err.Error()))
+			a.loginFailed(ctx, email, 0, appID, events.FailureUnknownUser, client)
 
 			return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 		}
@@ -155,16 +711,34 @@ func (a *Auth) Login(ctx context.Context, email string, password string, appID i
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
+	if err := a.hasher.Compare(user.PassHash, password); err != nil {
 		log.Error("invalid credentials", slog.String("error", err.Error()))
+		a.loginFailed(ctx, email, user.ID, appID, events.FailureInvalidPassword, client)
 
 		return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 	}
 
-	app, err := a.storage.App(ctx, appID)
+	// Merged accounts are tombstones; their owner logs in to the account they were merged into.
+	if user.MergedInto != 0 {
+		log.Warn("login to merged account", slog.Int64("user_id", user.ID), slog.Int64("merged_into", user.MergedInto))
+		a.loginFailed(ctx, email, user.ID, appID, events.FailureMergedAccount, client)
+
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	// Checked after the password, so the state of an account is only revealed to its owner.
+	if !user.FrozenAt.IsZero() {
+		log.Warn("login to frozen account", slog.Int64("user_id", user.ID))
+		a.loginFailed(ctx, email, user.ID, appID, events.FailureFrozenAccount, client)
+
+		return "", fmt.Errorf("%s: %w", op, ErrAccountFrozen)
+	}
+
+	app, err := a.apps.App(ctx, appID)
 	if err != nil {
 		if errors.Is(err, storage.ErrAppNotFound) {
 			log.Warn("app not found", slog.String("error", err.Error()))
+			a.loginFailed(ctx, email, user.ID, appID, events.FailureInvalidApp, client)
 
 			return "", fmt.Errorf("%s: %w", op, ErrInvalidAppID)
 		}
@@ -174,7 +748,51 @@ func (a *Auth) Login(ctx context.Context, email string, password string, appID i
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	token, err := jwt.NewToken(user, app, a.tokenTTL)
+	if err := a.checkAppMember(ctx, user, app); err != nil {
+		if errors.Is(err, ErrNotAppMember) {
+			log.Warn("user is not a member of the app", slog.Int64("user_id", user.ID), slog.Int("app_id", app.ID))
+			a.loginFailed(ctx, email, user.ID, appID, events.FailureNotAppMember, client)
+
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Error("failed to check app membership", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.PushMFA {
+		challengeErr, err := a.startLoginChallenge(ctx, user, int32(app.ID), "", client)
+		if err != nil {
+			log.Error("failed to start login challenge", slog.String("error", err.Error()))
+
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("login waits for push approval", slog.Int64("user_id", user.ID), slog.Int("app_id", app.ID))
+
+		return "", fmt.Errorf("%s: %w", op, challengeErr)
+	}
+
+	if err := a.loadAttributes(ctx, user, app); err != nil {
+		log.Error("failed to get user attributes", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.loadFamily(ctx, app); err != nil {
+		log.Error("failed to get app family", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := a.clock.Now()
+
+	token, err := jwt.NewToken(user, app, a.signingKeys, now, a.appTokenTTL(app), a.region, a.standardClaims, jwt.Authentication{
+		Time:    now,
+		Methods: []string{jwt.AMRPassword},
+		ACR:     jwt.ACRSingleFactor,
+	})
 	if err != nil {
 		log.Error("failed to generate token", slog.String("error", err.Error()))
 
@@ -183,11 +801,43 @@ func (a *Auth) Login(ctx context.Context, email string, password string, appID i
 
 	log.Info("user logged in successfully", slog.Int64("user_id", user.ID))
 
+	if err := a.users.RecordLogin(ctx, user.ID, now); err != nil {
+		log.Error("failed to record login", slog.String("error", err.Error()))
+	}
+
+	a.events.Publish(ctx, events.LoginSucceeded{
+		UserID:    user.ID,
+		Email:     user.Email,
+		AppID:     appID,
+		Method:    events.LoginPassword,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		At:        now,
+	})
+
+	a.runPostHooks(log, func(h Hook) error { return h.PostLogin(ctx, user.ID, user.Email, appID) })
+
 	return token, nil
 }
 
+// loginFailed publishes LoginFailed for a refused password login.
+func (a *Auth) loginFailed(ctx context.Context, email string, userID int64, appID int32, reason string, client ClientInfo) {
+	a.events.Publish(ctx, events.LoginFailed{
+		Email:  email,
+		UserID: userID,
+		AppID:  appID,
+		Reason: reason,
+		IP:     client.IP,
+		At:     a.clock.Now(),
+	})
+}
+
 // IsAdmin checks if the specified user has administrative privileges.
 //
+// The storage query is bounded by the is_admin storage timeout. If it runs out, the
+// admin status last read for the user is served instead, provided it is no older than
+// the role cache age, so a slow database delays callers rather than failing them.
+//
 // Parameters:
 //   - ctx: context for request cancellation and timeouts
 //   - userID: ID of the user to check
@@ -198,6 +848,7 @@ func (a *Auth) Login(ctx context.Context, email string, password string, appID i
 //
 // Possible errors:
 //   - ErrUserNotFound: if no user exists with the ID
+//   - ErrUnavailable: if the query timed out and no recent enough admin status is known
 //   - other errors: for any other failure during the admin check
 func (a *Auth) IsAdmin(ctx context.Context, userID int64) (bool, error) {
 	const op = "auth.Auth.IsAdmin"
@@ -207,7 +858,11 @@ func (a *Auth) IsAdmin(ctx context.Context, userID int64) (bool, error) {
 		slog.Int64("user_id", userID),
 	)
 
-	isAdmin, err := a.storage.IsAdmin(ctx, userID)
+	if cached, _, ok := a.recentRole(userID, a.validationCfg.MicroCacheTTL); ok {
+		return cached, nil
+	}
+
+	isAdmin, err := a.queryIsAdmin(ctx, userID)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserNotFound) {
 			log.Warn("user not found", slog.String("error", err.Error()))
@@ -215,6 +870,21 @@ func (a *Auth) IsAdmin(ctx context.Context, userID int64) (bool, error) {
 			return false, fmt.Errorf("%s: %w", op, ErrUserNotFound)
 		}
 
+		if errors.Is(err, errQueryTimeout) {
+			if cached, age, ok := a.recentRole(userID, a.timeoutsCfg.RoleCacheAge); ok {
+				log.Warn("storage timed out, serving cached admin status",
+					slog.Bool("is_admin", cached),
+					slog.Duration("age", age),
+				)
+
+				return cached, nil
+			}
+
+			log.Error("storage timed out and no recent admin status is cached", slog.String("error", err.Error()))
+
+			return false, fmt.Errorf("%s: %w", op, ErrUnavailable)
+		}
+
 		log.Error("failed to check if user is admin", slog.String("error", err.Error()))
 
 		return false, fmt.Errorf("%s: %w", op, err)
@@ -224,3 +894,550 @@ func (a *Auth) IsAdmin(ctx context.Context, userID int64) (bool, error) {
 
 	return isAdmin, nil
 }
+
+// IsAppOwner checks if the specified user is an owner of an application, and may
+// therefore manage it through the admin API without being an admin.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - userID: ID of the user to check
+//
+// Returns:
+//   - bool: true if the user owns the application, false otherwise
+//   - error: nil on success, or an error if the check fails
+func (a *Auth) IsAppOwner(ctx context.Context, appID int32, userID int64) (bool, error) {
+	const op = "auth.Auth.IsAppOwner"
+
+	isOwner, err := a.apps.IsAppOwner(ctx, appID, userID)
+	if err != nil {
+		a.log.Error("failed to check if user owns app",
+			slog.String("op", op),
+			slog.Int("app_id", int(appID)),
+			slog.Int64("user_id", userID),
+			slog.String("error", err.Error()),
+		)
+
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return isOwner, nil
+}
+
+// GetUserInfo returns the profile of a signed-in user, for relying parties of apps
+// whose tokens carry minimal claims.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user the caller's token was issued to
+//
+// Returns:
+//   - *models.User: the user
+//   - error: nil on success, or an error if the user cannot be read
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the ID
+func (a *Auth) GetUserInfo(ctx context.Context, userID int64) (*models.User, error) {
+	const op = "auth.Auth.GetUserInfo"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	user, err := a.users.UserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// ResolveUserID returns the ID of the user with the given public ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - publicID: public ID of the user
+//
+// Returns:
+//   - int64: ID of the user
+//   - error: nil on success, or an error if the user cannot be found
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the public ID
+func (a *Auth) ResolveUserID(ctx context.Context, publicID string) (int64, error) {
+	const op = "auth.Auth.ResolveUserID"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	user, err := a.users.UserByPublicID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user.ID, nil
+}
+
+// ValidateToken verifies a token issued by the service and returns its claims.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - token: token previously returned by Login
+//
+// Returns:
+//   - *jwt.Claims: verified token claims
+//   - error: nil on success, or an error if validation fails
+//
+// Possible errors:
+//   - ErrInvalidToken: if the token is malformed, expired, badly signed, issued for an unknown app or user,
+//     or revoked
+//   - other errors: for any other failure during validation
+func (a *Auth) ValidateToken(ctx context.Context, token string) (*jwt.Claims, error) {
+	const op = "auth.Auth.ValidateToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	if claims, ok := a.recentValidation(token); ok {
+		return claims, nil
+	}
+
+	claims, _, err := a.parseToken(ctx, token, a.validationCfg.Leeway)
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			log.Warn("invalid token", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Error("failed to validate token", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.rememberValidation(token, claims)
+
+	return claims, nil
+}
+
+// RefreshToken exchanges a token for a new one with a full lifetime.
+// Tokens that expired less than the refresh grace window ago are accepted too,
+// so callers whose token expired in flight do not have to log in again.
+//
+// It is refused while refresh tokens are enabled: a session ends when its refresh
+// tokens are revoked, which a token renewing itself would outlive.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - token: token previously returned by Login or RefreshToken
+//
+// Returns:
+//   - string: new token in the app's current token format
+//   - error: nil on success, or an error if the token cannot be refreshed
+//
+// Possible errors:
+//   - ErrInvalidToken: if the token is malformed, badly signed, expired beyond the grace window,
+//     issued for an unknown app or user, or signed with a secret for an app whose tokens the
+//     service signs (RS256)
+//   - ErrNotAppMember: if the app requires membership and the user's access was revoked
+//   - ErrRefreshTokenRequired: if refresh tokens are enabled
+//   - other errors: for any other failure
+func (a *Auth) RefreshToken(ctx context.Context, token string) (string, error) {
+	const op = "auth.Auth.RefreshToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	if a.refreshCfg.TTL > 0 {
+		log.Warn("token presented instead of a refresh token")
+
+		return "", fmt.Errorf("%s: %w", op, ErrRefreshTokenRequired)
+	}
+
+	claims, user, err := a.parseToken(ctx, token, a.validationCfg.Leeway+a.validationCfg.RefreshGrace)
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			log.Warn("invalid token", slog.String("error", err.Error()))
+
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Error("failed to validate token", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err := a.apps.App(ctx, claims.AppID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get app", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The app's relying party could have signed the token with its secret for any user. Re-signing
+	// it with the service key would turn it into a token admin methods accept.
+	if app.TokenFormat == jwt.FormatJWTRS256 && !claims.ServiceSigned {
+		log.Warn("app-signed token presented for an app whose tokens the service signs", slog.Int("app_id", app.ID))
+
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	if err := a.checkAppMember(ctx, user, app); err != nil {
+		if errors.Is(err, ErrNotAppMember) {
+			log.Warn("user is not a member of the app", slog.Int64("user_id", user.ID), slog.Int("app_id", app.ID))
+
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Error("failed to check app membership", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.loadAttributes(ctx, user, app); err != nil {
+		log.Error("failed to get user attributes", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.loadFamily(ctx, app); err != nil {
+		log.Error("failed to get app family", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Refreshing is not authenticating, so the new token records the original authentication.
+	newToken, err := jwt.NewToken(user, app, a.signingKeys, a.clock.Now(), a.appTokenTTL(app), a.region, a.standardClaims, claims.Authentication())
+	if err != nil {
+		log.Error("failed to generate token", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("token refreshed", slog.Int64("user_id", user.ID))
+
+	return newToken, nil
+}
+
+// checkAppMember returns ErrNotAppMember if app requires membership and user was not granted access.
+func (a *Auth) checkAppMember(ctx context.Context, user *models.User, app *models.App) error {
+	if !app.RequireMembership {
+		return nil
+	}
+
+	member, err := a.apps.IsAppMember(ctx, int32(app.ID), user.ID)
+	if err != nil {
+		return err
+	}
+
+	if !member {
+		return ErrNotAppMember
+	}
+
+	return nil
+}
+
+// loadAttributes sets the attributes of user if they are added to tokens issued for app.
+// Tokens of apps with minimal claims never carry them, so they are not loaded then.
+func (a *Auth) loadAttributes(ctx context.Context, user *models.User, app *models.App) error {
+	if !a.attributesCfg.InTokens || app.MinimalClaims {
+		return nil
+	}
+
+	attrs, err := a.users.UserAttributes(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	user.Attributes = attrs
+
+	return nil
+}
+
+// loadFamily sets the family of app, if it belongs to one, so tokens issued for it are
+// signed with the family secret.
+func (a *Auth) loadFamily(ctx context.Context, app *models.App) error {
+	if app.FamilyID == 0 {
+		return nil
+	}
+
+	family, err := a.apps.AppFamily(ctx, app.FamilyID)
+	if err != nil {
+		return err
+	}
+
+	app.Family = family
+
+	return nil
+}
+
+// appTokenTTL returns the lifetime of tokens issued for app: its own, or tokenTTL if it has none.
+func (a *Auth) appTokenTTL(app *models.App) time.Duration {
+	if app.TokenTTL > 0 {
+		return app.TokenTTL
+	}
+
+	return a.tokenTTL
+}
+
+// parseToken verifies a token, accepting it up to leeway after its expiration, and loads its user.
+// The user ID and email of the returned claims are filled in from the user, since tokens with
+// minimal claims do not carry them. Returns ErrInvalidToken if the token cannot be verified, its user no longer exists,
+// or the tokens of its user or app were revoked after it was issued.
+func (a *Auth) parseToken(ctx context.Context, token string, leeway time.Duration) (*jwt.Claims, *models.User, error) {
+	claims, user, app, err := a.verifyToken(ctx, token, leeway)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Tokens without an issue time predate revocation support and are treated as issued at the epoch.
+	if !user.TokensRevokedAt.IsZero() && !claims.IssuedAt.After(user.TokensRevokedAt) {
+		return nil, nil, fmt.Errorf("%w: token was revoked", ErrInvalidToken)
+	}
+
+	if !app.TokensRevokedAt.IsZero() && !claims.IssuedAt.After(app.TokensRevokedAt) {
+		return nil, nil, fmt.Errorf("%w: tokens of the app were revoked", ErrInvalidToken)
+	}
+
+	return claims, user, nil
+}
+
+// verifyToken verifies the signature and expiration of a token like parseToken, and loads
+// its user and app, without checking whether the token was revoked. Test tokens are rejected.
+func (a *Auth) verifyToken(ctx context.Context, token string, leeway time.Duration) (*jwt.Claims, *models.User, *models.App, error) {
+	var app *models.App
+
+	claims, err := jwt.ParseToken(token, func(appID int32) (string, error) {
+		var err error
+
+		app, err = a.apps.App(ctx, appID)
+		if err != nil {
+			return "", err
+		}
+
+		if err := a.loadFamily(ctx, app); err != nil {
+			return "", err
+		}
+
+		if app.Family != nil {
+			return app.Family.Secret, nil
+		}
+
+		return app.Secret, nil
+	}, a.signingKeys, a.clock.Now(), leeway, a.standardClaims.Issuer)
+	if err != nil {
+		if errors.Is(err, jwt.ErrInvalidToken) || errors.Is(err, storage.ErrAppNotFound) {
+			return nil, nil, nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+
+		return nil, nil, nil, err
+	}
+
+	// Test tokens name users without authenticating them, so they are only for services
+	// under test verifying tokens themselves.
+	if claims.Test {
+		return nil, nil, nil, fmt.Errorf("%w: test tokens are not accepted", ErrInvalidToken)
+	}
+
+	var user *models.User
+	if claims.UserID != 0 {
+		user, err = a.users.UserByID(ctx, claims.UserID)
+	} else {
+		user, err = a.users.UserByPublicID(ctx, claims.Subject)
+	}
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, nil, nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+
+		return nil, nil, nil, err
+	}
+
+	// Tokens with minimal claims identify the user by subject only.
+	claims.UserID = user.ID
+	claims.Email = user.Email
+
+	return claims, user, app, nil
+}
+
+// RequestPasswordReset issues a single-use password reset token and emails it to the user.
+//
+// The token is generated with crypto/rand and only its SHA-256 hash is stored. To prevent
+// account enumeration, unknown emails and rate-limited accounts are not reported as errors.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - email: email address of the account to reset
+//   - appID: app whose email template overrides apply; 0 for none
+//   - locale: preferred language of the email; empty for the default
+//
+// Returns:
+//   - error: nil on success, or an error if the token cannot be issued or delivered
+func (a *Auth) RequestPasswordReset(ctx context.Context, email string, appID int32, locale string) error {
+	const op = "auth.Auth.RequestPasswordReset"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	user, err := a.users.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("password reset requested for unknown email")
+
+			return nil
+		}
+
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", user.ID))
+
+	now := a.clock.Now()
+
+	issued, err := a.tokens.CountPasswordResetTokens(ctx, user.ID, now.Add(-a.resetCfg.Window))
+	if err != nil {
+		log.Error("failed to count reset tokens", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if issued >= a.resetCfg.MaxRequests {
+		limitErr := fmt.Errorf("%d reset tokens issued within %s", issued, a.resetCfg.Window)
+		if err := a.enforce(log, policyPasswordResetLimit, limitErr); err != nil {
+			log.Warn("password reset rate limit exceeded", slog.Int("issued", issued))
+
+			return nil
+		}
+	}
+
+	token, tokenHash, err := newResetToken()
+	if err != nil {
+		log.Error("failed to generate reset token", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	expiresAt := now.Add(a.resetCfg.TokenTTL)
+
+	if err := a.tokens.SavePasswordResetToken(ctx, user.ID, tokenHash, now, expiresAt); err != nil {
+		log.Error("failed to save reset token", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	msg, err := a.templates.Render(templates.PasswordReset, appID, locale, map[string]string{
+		"Email":     user.Email,
+		"Token":     token,
+		"ExpiresAt": expiresAt.UTC().Format(time.RFC1123),
+	})
+	if err != nil {
+		log.Error("failed to render reset email", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: msg.Subject,
+		Body:    msg.Body,
+	}); err != nil {
+		log.Error("failed to send reset token", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("password reset token issued")
+
+	return nil
+}
+
+// ResetPassword sets a new password for the owner of a password reset token.
+// The token is consumed, and every other outstanding token of the user is revoked.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - token: token delivered by RequestPasswordReset
+//   - newPassword: new password (will be hashed before storage)
+//
+// Returns:
+//   - error: nil on success, or an error if the reset fails
+//
+// Possible errors:
+//   - ErrInvalidResetToken: if the token is unknown, already used, or expired
+//   - other errors: for any other failure during the reset
+func (a *Auth) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	const op = "auth.Auth.ResetPassword"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	passHash, err := a.hasher.Hash(newPassword)
+	if err != nil {
+		log.Error("failed to generate password hash", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokenHash := sha256.Sum256([]byte(token))
+
+	userID, err := a.tokens.ResetPassword(ctx, tokenHash[:], passHash, a.clock.Now())
+	if err != nil {
+		if errors.Is(err, storage.ErrResetTokenNotFound) {
+			log.Warn("invalid reset token", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidResetToken)
+		}
+
+		log.Error("failed to reset password", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("password reset successfully", slog.Int64("user_id", userID))
+
+	return nil
+}
+
+// newResetToken generates a random password reset token and its SHA-256 hash.
+func newResetToken() (token string, hash []byte, err error) {
+	b := make([]byte, resetTokenBytes)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(b)
+	sum := sha256.Sum256([]byte(token))
+
+	return token, sum[:], nil
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// Avatar errors.
+var (
+	// ErrAvatarsDisabled is returned when avatars are not configured
+	ErrAvatarsDisabled = errors.New("avatars are disabled")
+
+	// ErrInvalidAvatar is returned when an avatar is empty, too large, or not a supported image
+	ErrInvalidAvatar = errors.New("invalid avatar")
+)
+
+// avatarExtensions maps the image types accepted as avatars to the extension of their keys,
+// so the servers in front of the blob storage can derive the content type from the key.
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// MaxAvatarSize returns the largest avatar accepted by SetAvatar, in bytes.
+func (a *Auth) MaxAvatarSize() int {
+	return a.avatarsCfg.MaxSize
+}
+
+// SetAvatar stores an image as the avatar of a signed-in user. Images are stored under the
+// SHA-256 digest of their content, so uploading the same image again stores nothing new, and
+// the URL of an avatar never serves other content. Previous avatars are kept, since cached
+// profiles may still link to them.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user the caller's token was issued to
+//   - image: PNG, JPEG, GIF, or WebP image of at most avatars.max_size bytes
+//
+// Returns:
+//   - string: URL of the avatar
+//   - error: nil on success, or an error if the avatar cannot be stored
+//
+// Possible errors:
+//   - ErrAvatarsDisabled: if avatars are not configured
+//   - ErrInvalidAvatar: if the image is empty, too large, or of an unsupported type
+//   - ErrUserNotFound: if no user exists with the ID
+func (a *Auth) SetAvatar(ctx context.Context, userID int64, image []byte) (string, error) {
+	const op = "auth.Auth.SetAvatar"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	if a.avatars == nil {
+		return "", fmt.Errorf("%s: %w", op, ErrAvatarsDisabled)
+	}
+
+	if len(image) == 0 || len(image) > a.avatarsCfg.MaxSize {
+		log.Warn("avatar size out of bounds", slog.Int("size", len(image)))
+
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidAvatar)
+	}
+
+	contentType := http.DetectContentType(image)
+
+	ext, ok := avatarExtensions[contentType]
+	if !ok {
+		log.Warn("unsupported avatar type", slog.String("content_type", contentType))
+
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidAvatar)
+	}
+
+	sum := sha256.Sum256(image)
+	key := hex.EncodeToString(sum[:]) + ext
+
+	if err := a.avatars.Put(ctx, key, image); err != nil {
+		log.Error("failed to store avatar", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.users.SetUserAvatar(ctx, userID, key); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return "", fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to save avatar", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("avatar set", slog.String("avatar", key))
+
+	return a.avatarURL(key), nil
+}
+
+// AvatarURL returns the URL of a user's avatar, or an empty string if the user has none
+// or avatars are disabled.
+func (a *Auth) AvatarURL(user *models.User) string {
+	if a.avatars == nil || user.Avatar == "" {
+		return ""
+	}
+
+	return a.avatarURL(user.Avatar)
+}
+
+// avatarURL appends the key of an avatar to the configured base URL.
+func (a *Auth) avatarURL(key string) string {
+	return strings.TrimSuffix(a.avatarsCfg.BaseURL, "/") + "/" + key
+}
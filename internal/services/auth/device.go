@@ -0,0 +1,396 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/events"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// Device authorization grant errors. The names follow the error codes of RFC 8628.
+var (
+	// ErrAuthorizationPending is returned when the user has not yet approved or denied the device
+	ErrAuthorizationPending = errors.New("authorization pending")
+
+	// ErrSlowDown is returned when the device polls more often than the polling interval allows
+	ErrSlowDown = errors.New("slow down")
+
+	// ErrAccessDenied is returned when the user denied the device
+	ErrAccessDenied = errors.New("access denied")
+
+	// ErrExpiredToken is returned when the device code expired before a token was issued
+	ErrExpiredToken = errors.New("device code expired")
+
+	// ErrInvalidDeviceCode is returned when a device code is unknown or was already exchanged for a token
+	ErrInvalidDeviceCode = errors.New("invalid device code")
+
+	// ErrInvalidUserCode is returned when a user code is unknown, expired, or was already used
+	ErrInvalidUserCode = errors.New("invalid or expired user code")
+)
+
+const (
+	// userCodeAlphabet contains only consonants without easily confused letters,
+	// as recommended by RFC 8628, so user codes are easy to type and never spell words.
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+	// userCodeLength is the number of characters in a user code, excluding the separator.
+	userCodeLength = 8
+
+	// userCodeAttempts is how many times a colliding user code is regenerated.
+	userCodeAttempts = 3
+
+	// slowDownStep is how much the polling interval grows each time a device polls too often.
+	slowDownStep = 5 * time.Second
+)
+
+// DeviceAuthorization holds the codes issued to a device that starts the device authorization grant.
+type DeviceAuthorization struct {
+	DeviceCode              string        // secret code the device polls with
+	UserCode                string        // short code the user enters on the verification page
+	VerificationURI         string        // page where the user enters the user code
+	VerificationURIComplete string        // verification page with the user code filled in
+	ExpiresIn               time.Duration // time until both codes expire
+	Interval                time.Duration // minimum time between polls
+}
+
+// StartDeviceAuthorization starts the OAuth 2.0 device authorization grant (RFC 8628)
+// for a device, such as a CLI tool, that cannot show a login form itself.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application the device requests a token for
+//
+// Returns:
+//   - *DeviceAuthorization: codes to show to the user and to poll with
+//   - error: nil on success, or an error if the authorization cannot be started
+//
+// Possible errors:
+//   - ErrInvalidAppID: if the app does not exist
+//   - other errors: for any other failure
+func (a *Auth) StartDeviceAuthorization(ctx context.Context, appID int32) (*DeviceAuthorization, error) {
+	const op = "auth.Auth.StartDeviceAuthorization"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+	)
+
+	if _, err := a.apps.App(ctx, appID); err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+		}
+
+		log.Error("failed to get app", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Device codes share the format of password reset tokens.
+	deviceCode, deviceCodeHash, err := newResetToken()
+	if err != nil {
+		log.Error("failed to generate device code", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := a.clock.Now()
+
+	for attempt := 1; ; attempt++ {
+		userCode, err := newUserCode()
+		if err != nil {
+			log.Error("failed to generate user code", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = a.tokens.SaveDeviceAuthorization(ctx, &models.DeviceAuthorization{
+			DeviceCodeHash: deviceCodeHash,
+			UserCode:       userCode,
+			AppID:          appID,
+			Interval:       a.deviceCfg.PollInterval,
+			CreatedAt:      now,
+			ExpiresAt:      now.Add(a.deviceCfg.CodeTTL),
+		})
+		if errors.Is(err, storage.ErrUserCodeExists) && attempt < userCodeAttempts {
+			continue
+		}
+
+		if err != nil {
+			log.Error("failed to save device authorization", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		displayCode := formatUserCode(userCode)
+
+		log.Info("device authorization started")
+
+		return &DeviceAuthorization{
+			DeviceCode:              deviceCode,
+			UserCode:                displayCode,
+			VerificationURI:         a.deviceCfg.VerificationURI,
+			VerificationURIComplete: verificationURIComplete(a.deviceCfg.VerificationURI, displayCode),
+			ExpiresIn:               a.deviceCfg.CodeTTL,
+			Interval:                a.deviceCfg.PollInterval,
+		}, nil
+	}
+}
+
+// ApproveDeviceAuthorization records a signed-in user's decision on a device
+// that showed them the given user code.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userCode: code shown on the device; case, spaces, and dashes are ignored
+//   - userID: ID of the signed-in user
+//   - approve: true to let the device obtain a token for the user, false to deny it
+//
+// Returns:
+//   - int32: ID of the app the device requested access to
+//   - error: nil on success, or an error if the decision cannot be recorded
+//
+// Possible errors:
+//   - ErrInvalidUserCode: if the code is unknown, expired, or was already used
+//   - other errors: for any other failure
+func (a *Auth) ApproveDeviceAuthorization(ctx context.Context, userCode string, userID int64, approve bool) (int32, error) {
+	const op = "auth.Auth.ApproveDeviceAuthorization"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Bool("approve", approve),
+	)
+
+	decision := models.DeviceAuthorizationDenied
+	if approve {
+		decision = models.DeviceAuthorizationApproved
+	}
+
+	appID, err := a.tokens.DecideDeviceAuthorization(ctx, normalizeUserCode(userCode), userID, decision, a.clock.Now())
+	if err != nil {
+		if errors.Is(err, storage.ErrDeviceAuthorizationNotFound) {
+			log.Warn("invalid user code", slog.String("error", err.Error()))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrInvalidUserCode)
+		}
+
+		log.Error("failed to record decision", slog.String("error", err.Error()))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("device authorization decided", slog.Int("app_id", int(appID)))
+
+	return appID, nil
+}
+
+// PollDeviceToken exchanges a device code for a token once the user approved the device.
+// A token is issued only once per device code.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - deviceCode: code returned by StartDeviceAuthorization
+//
+// Returns:
+//   - string: JWT token for the approving user and the requested app
+//   - error: nil on success, or an error if no token can be issued yet
+//
+// Possible errors:
+//   - ErrAuthorizationPending: if the user has not decided yet; poll again after the interval
+//   - ErrSlowDown: if the device polled too early; the interval grows by 5 seconds
+//   - ErrAccessDenied: if the user denied the device, the account was frozen since approving it,
+//     or the app requires membership the user was not granted
+//   - ErrExpiredToken: if the device code expired
+//   - ErrInvalidDeviceCode: if the device code is unknown or was already used
+//   - *RejectError: if a PreLogin hook rejected the login
+//   - other errors: for any other failure
+func (a *Auth) PollDeviceToken(ctx context.Context, deviceCode string) (string, error) {
+	const op = "auth.Auth.PollDeviceToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	deviceCodeHash := sha256.Sum256([]byte(deviceCode))
+
+	device, err := a.tokens.DeviceAuthorization(ctx, deviceCodeHash[:])
+	if err != nil {
+		if errors.Is(err, storage.ErrDeviceAuthorizationNotFound) {
+			log.Warn("unknown device code", slog.String("error", err.Error()))
+
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidDeviceCode)
+		}
+
+		log.Error("failed to get device authorization", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := a.clock.Now()
+
+	switch {
+	case device.Status == models.DeviceAuthorizationConsumed:
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidDeviceCode)
+	case !now.Before(device.ExpiresAt):
+		return "", fmt.Errorf("%s: %w", op, ErrExpiredToken)
+	case device.Status == models.DeviceAuthorizationDenied:
+		return "", fmt.Errorf("%s: %w", op, ErrAccessDenied)
+	case device.Status == models.DeviceAuthorizationPending:
+		interval, pollErr := device.Interval, ErrAuthorizationPending
+		if !device.LastPolledAt.IsZero() && now.Sub(device.LastPolledAt) < device.Interval {
+			interval, pollErr = device.Interval+slowDownStep, ErrSlowDown
+		}
+
+		if err := a.tokens.PollDeviceAuthorization(ctx, device.ID, now, interval); err != nil {
+			log.Error("failed to record poll", slog.String("error", err.Error()))
+
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		return "", fmt.Errorf("%s: %w", op, pollErr)
+	}
+
+	user, err := a.users.UserByID(ctx, device.UserID)
+	if err != nil {
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The account may have been frozen since the user approved the device.
+	if !user.FrozenAt.IsZero() {
+		log.Warn("device approved by frozen account", slog.Int64("user_id", user.ID))
+
+		return "", fmt.Errorf("%s: %w", op, ErrAccessDenied)
+	}
+
+	app, err := a.apps.App(ctx, device.AppID)
+	if err != nil {
+		log.Error("failed to get app", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.runPreHooks(func(h Hook) error { return h.PreLogin(ctx, user.Email, device.AppID) }); err != nil {
+		log.Warn("login rejected by hook", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.tokens.ConsumeDeviceAuthorization(ctx, device.ID); err != nil {
+		if errors.Is(err, storage.ErrDeviceAuthorizationNotFound) {
+			// A concurrent poll exchanged the code first.
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidDeviceCode)
+		}
+
+		log.Error("failed to consume device authorization", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.checkAppMember(ctx, user, app); err != nil {
+		if errors.Is(err, ErrNotAppMember) {
+			log.Warn("device approved by user who is not a member of the app", slog.Int64("user_id", user.ID))
+
+			return "", fmt.Errorf("%s: %w", op, ErrAccessDenied)
+		}
+
+		log.Error("failed to check app membership", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.loadAttributes(ctx, user, app); err != nil {
+		log.Error("failed to get user attributes", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.loadFamily(ctx, app); err != nil {
+		log.Error("failed to get app family", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The user authenticated on another device to approve this one, so how is not known here.
+	token, err := jwt.NewToken(user, app, a.signingKeys, a.clock.Now(), a.appTokenTTL(app), a.region, a.standardClaims, jwt.Authentication{
+		Time: now,
+		ACR:  jwt.ACRSingleFactor,
+	})
+	if err != nil {
+		log.Error("failed to generate token", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("device token issued", slog.Int64("user_id", user.ID), slog.Int("app_id", app.ID))
+
+	if err := a.users.RecordLogin(ctx, user.ID, now); err != nil {
+		log.Error("failed to record login", slog.String("error", err.Error()))
+	}
+
+	a.events.Publish(ctx, events.LoginSucceeded{
+		UserID: user.ID,
+		Email:  user.Email,
+		AppID:  device.AppID,
+		Method: events.LoginDevice,
+		At:     now,
+	})
+
+	a.runPostHooks(log, func(h Hook) error { return h.PostLogin(ctx, user.ID, user.Email, device.AppID) })
+
+	return token, nil
+}
+
+// newUserCode generates a random user code in normalized form.
+func newUserCode() (string, error) {
+	b := make([]byte, userCodeLength)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	// 256 is not a multiple of the alphabet size, but the bias is irrelevant for short-lived codes.
+	for i := range b {
+		b[i] = userCodeAlphabet[int(b[i])%len(userCodeAlphabet)]
+	}
+
+	return string(b), nil
+}
+
+// formatUserCode splits a normalized user code into two halves for display, e.g. "BDFG-HJKL".
+func formatUserCode(code string) string {
+	return code[:len(code)/2] + "-" + code[len(code)/2:]
+}
+
+// normalizeUserCode converts a user code as typed by the user to its normalized form.
+func normalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, strings.ToUpper(code))
+}
+
+// verificationURIComplete returns the verification URI with the user code as a query parameter.
+func verificationURIComplete(uri string, userCode string) string {
+	sep := "?"
+	if strings.Contains(uri, "?") {
+		sep = "&"
+	}
+
+	return uri + sep + "user_code=" + userCode
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/lib/enforcement"
+)
+
+// Policies whose violations can be reported instead of rejected, as named in the
+// policy_violations metric.
+const (
+	policyRegistrationDomains = "registration_domains"
+	policyDisposableEmails    = "disposable_emails"
+	policyRegistrationQuota   = "registration_quota"
+	policyPasswordResetLimit  = "password_reset_limit"
+)
+
+// policyModes maps policies to how their violations are handled.
+type policyModes map[string]enforcement.Mode
+
+// enforcementModes returns the mode of every policy, or an error naming a policy with an unknown mode.
+func enforcementModes(cfg config.Enforcement) (policyModes, error) {
+	modes := policyModes{
+		policyRegistrationDomains: enforcement.Mode(cfg.RegistrationDomains),
+		policyDisposableEmails:    enforcement.Mode(cfg.DisposableEmails),
+		policyRegistrationQuota:   enforcement.Mode(cfg.RegistrationQuota),
+		policyPasswordResetLimit:  enforcement.Mode(cfg.PasswordResetLimit),
+	}
+
+	for policy, mode := range modes {
+		if !mode.Valid() {
+			return nil, fmt.Errorf("unknown enforcement mode %q of %s", mode, policy)
+		}
+	}
+
+	return modes, nil
+}
+
+// enforce handles a violation of a policy: it returns err if the policy is enforced, or logs
+// it and returns nil if the policy only reports violations, in which case the request goes on
+// as if the policy had passed.
+func (a *Auth) enforce(log *slog.Logger, policy string, err error) error {
+	if enforcement.Violated(policy, a.enforcement[policy]) {
+		return err
+	}
+
+	log.Warn("policy violation reported", slog.String("policy", policy), slog.String("error", err.Error()))
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/mailer"
+	"github.com/kirinyoku/sso-grpc/internal/lib/templates"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+var (
+	// ErrAccountFrozen is returned when a frozen account logs in or is issued a token
+	ErrAccountFrozen = errors.New("account is frozen")
+
+	// ErrInvalidUnfreezeToken is returned when an unfreeze token is unknown, already used, or expired
+	ErrInvalidUnfreezeToken = errors.New("invalid or expired unfreeze token")
+)
+
+// RequestUnfreeze emails a single-use unfreeze token to the owner of a frozen account.
+//
+// Tokens follow the password reset policy: they expire after the reset token TTL, and
+// issuance is rate limited the same way. To prevent account enumeration, unknown emails,
+// accounts that are not frozen, and rate-limited accounts are not reported as errors.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - email: email address of the frozen account
+//   - appID: app whose email template overrides apply; 0 for none
+//   - locale: preferred language of the email; empty for the default
+//
+// Returns:
+//   - error: nil on success, or an error if the token cannot be issued or delivered
+func (a *Auth) RequestUnfreeze(ctx context.Context, email string, appID int32, locale string) error {
+	const op = "auth.Auth.RequestUnfreeze"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	user, err := a.users.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("unfreeze requested for unknown email")
+
+			return nil
+		}
+
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", user.ID))
+
+	if user.FrozenAt.IsZero() {
+		log.Warn("unfreeze requested for account that is not frozen")
+
+		return nil
+	}
+
+	now := a.clock.Now()
+
+	issued, err := a.tokens.CountUnfreezeTokens(ctx, user.ID, now.Add(-a.resetCfg.Window))
+	if err != nil {
+		log.Error("failed to count unfreeze tokens", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if issued >= a.resetCfg.MaxRequests {
+		limitErr := fmt.Errorf("%d unfreeze tokens issued within %s", issued, a.resetCfg.Window)
+		if err := a.enforce(log, policyPasswordResetLimit, limitErr); err != nil {
+			log.Warn("unfreeze rate limit exceeded", slog.Int("issued", issued))
+
+			return nil
+		}
+	}
+
+	token, tokenHash, err := newResetToken()
+	if err != nil {
+		log.Error("failed to generate unfreeze token", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	expiresAt := now.Add(a.resetCfg.TokenTTL)
+
+	if err := a.tokens.SaveUnfreezeToken(ctx, user.ID, tokenHash, now, expiresAt); err != nil {
+		log.Error("failed to save unfreeze token", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	msg, err := a.templates.Render(templates.AccountUnfreeze, appID, locale, map[string]string{
+		"Email":     user.Email,
+		"Token":     token,
+		"ExpiresAt": expiresAt.UTC().Format(time.RFC1123),
+	})
+	if err != nil {
+		log.Error("failed to render unfreeze email", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: msg.Subject,
+		Body:    msg.Body,
+	}); err != nil {
+		log.Error("failed to send unfreeze token", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("unfreeze token issued")
+
+	return nil
+}
+
+// Unfreeze unfreezes the account that an unfreeze token was issued to. The token proves
+// access to the account's email, and the password is replaced, since it may be known to
+// whoever caused the freeze.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - token: token delivered by RequestUnfreeze
+//   - newPassword: new password (will be hashed before storage)
+//
+// Returns:
+//   - error: nil on success, or an error if the account cannot be unfrozen
+//
+// Possible errors:
+//   - ErrInvalidUnfreezeToken: if the token is unknown, already used, expired, or the account is not frozen
+//   - other errors: for any other failure
+func (a *Auth) Unfreeze(ctx context.Context, token string, newPassword string) error {
+	const op = "auth.Auth.Unfreeze"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	passHash, err := a.hasher.Hash(newPassword)
+	if err != nil {
+		log.Error("failed to generate password hash", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokenHash := sha256.Sum256([]byte(token))
+
+	userID, err := a.tokens.UnfreezeUser(ctx, tokenHash[:], passHash, a.clock.Now())
+	if err != nil {
+		if errors.Is(err, storage.ErrUnfreezeTokenNotFound) {
+			log.Warn("invalid unfreeze token", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidUnfreezeToken)
+		}
+
+		log.Error("failed to unfreeze account", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("account unfrozen", slog.Int64("user_id", userID))
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Hook lets embedders extend registration and login without modifying the
+// service, e.g. to enforce domain allowlists or sync users to a CRM.
+//
+// Pre hooks run before the operation and abort it by returning an error;
+// return Reject to show the client a reason. Post hooks run after the
+// operation succeeded; their errors are logged and do not affect the result.
+// Hooks run in the order they were registered.
+type Hook interface {
+	// PreRegister is called before a new user is created.
+	// appID is 0 when the client did not register through an app.
+	PreRegister(ctx context.Context, email string, appID int32) error
+
+	// PostRegister is called after a new user is created.
+	PostRegister(ctx context.Context, userID int64, email string, appID int32) error
+
+	// PreLogin is called before the user's credentials are checked.
+	PreLogin(ctx context.Context, email string, appID int32) error
+
+	// PostLogin is called after a token is issued to the user.
+	PostLogin(ctx context.Context, userID int64, email string, appID int32) error
+}
+
+// NopHook implements Hook with methods that do nothing.
+// Embed it to implement only the hooks you need.
+type NopHook struct{}
+
+func (NopHook) PreRegister(context.Context, string, int32) error { return nil }
+
+func (NopHook) PostRegister(context.Context, int64, string, int32) error { return nil }
+
+func (NopHook) PreLogin(context.Context, string, int32) error { return nil }
+
+func (NopHook) PostLogin(context.Context, int64, string, int32) error { return nil }
+
+// RejectError is returned when a pre hook or a registration policy rejects an operation.
+// Reason is reported to the client.
+type RejectError struct {
+	Reason string
+}
+
+func (e *RejectError) Error() string {
+	return "rejected: " + e.Reason
+}
+
+// Reject returns an error that rejects an operation with the given reason.
+func Reject(reason string) error {
+	return &RejectError{Reason: reason}
+}
+
+// runPreHooks calls fn for every hook and stops at the first error.
+func (a *Auth) runPreHooks(fn func(h Hook) error) error {
+	for _, h := range a.hooks {
+		if err := fn(h); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runPostHooks calls fn for every hook and logs errors instead of returning them.
+func (a *Auth) runPostHooks(log *slog.Logger, fn func(h Hook) error) {
+	for _, h := range a.hooks {
+		if err := fn(h); err != nil {
+			log.Error("post hook failed", slog.String("error", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// Token type hints and token types of RFC 7662 introspection.
+const (
+	TokenTypeAccess  = "access_token"
+	TokenTypeRefresh = "refresh_token"
+)
+
+// Introspection describes a token in the terms of RFC 7662. Only Active is set for tokens
+// that are not active, so callers learn nothing about tokens they cannot use.
+type Introspection struct {
+	Active    bool
+	TokenType string // TokenTypeAccess or TokenTypeRefresh
+	User      *models.User
+	AppID     int32     // ID of the app the token was issued for
+	Audience  []int32   // IDs of the apps accepting the token: the app, or every app of its family
+	IssuedAt  time.Time // zero for access tokens issued before it was recorded
+	ExpiresAt time.Time
+	Authn     jwt.Authentication // how and when the user authenticated; zero for tokens that do not record it
+	Issuer    string             // issuer named by an access token; empty if it names none
+	TokenID   string             // unique ID of an access token; empty for tokens issued without one
+}
+
+// Introspect reports whether a token is active and, if it is, what it was issued for, as
+// RFC 7662 token introspection does, so that clients integrating through standard OAuth2
+// libraries can check tokens without knowing the app secret. Access tokens are active if
+// ValidateToken would accept them; refresh tokens if ExchangeRefreshToken would exchange them.
+// A token of the hinted type is looked up first, then one of the other type.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - token: access or refresh token to describe
+//   - tokenTypeHint: TokenTypeAccess, TokenTypeRefresh, or empty if unknown; other values are ignored
+//
+// Returns:
+//   - *Introspection: the description of the token, with Active false for unknown, expired,
+//     or revoked tokens
+//   - error: nil on success, or an error if the token cannot be checked
+func (a *Auth) Introspect(ctx context.Context, token, tokenTypeHint string) (*Introspection, error) {
+	const op = "auth.Auth.Introspect"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	lookups := []func(context.Context, string) (*Introspection, error){a.introspectAccessToken, a.introspectRefreshToken}
+	if tokenTypeHint == TokenTypeRefresh {
+		lookups[0], lookups[1] = lookups[1], lookups[0]
+	}
+
+	for _, lookup := range lookups {
+		in, err := lookup(ctx, token)
+		if err != nil {
+			log.Error("failed to introspect token", slog.String("error", err.Error()))
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if in.Active {
+			return in, nil
+		}
+	}
+
+	return &Introspection{}, nil
+}
+
+// introspectAccessToken describes a token as an access token. Errors are only returned for
+// failures to check it; invalid tokens are reported as not active.
+func (a *Auth) introspectAccessToken(ctx context.Context, token string) (*Introspection, error) {
+	claims, user, err := a.parseToken(ctx, token, a.validationCfg.Leeway)
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			return &Introspection{}, nil
+		}
+
+		return nil, err
+	}
+
+	app, err := a.apps.App(ctx, claims.AppID)
+	if err == nil {
+		err = a.loadFamily(ctx, app)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	in := &Introspection{
+		Active:    true,
+		TokenType: TokenTypeAccess,
+		User:      user,
+		AppID:     claims.AppID,
+		Audience:  []int32{claims.AppID},
+		IssuedAt:  claims.IssuedAt,
+		ExpiresAt: claims.ExpiresAt,
+		Authn:     claims.Authentication(),
+		Issuer:    claims.Issuer,
+		TokenID:   claims.ID,
+	}
+
+	if app.Family != nil {
+		in.Audience = app.Family.AppIDs
+	}
+
+	return in, nil
+}
+
+// introspectRefreshToken describes a token as a refresh token. Unlike ExchangeRefreshToken,
+// it does not revoke the chain of a revoked token presented again, since introspecting a
+// token does not use it.
+func (a *Auth) introspectRefreshToken(ctx context.Context, token string) (*Introspection, error) {
+	sum := sha256.Sum256([]byte(token))
+
+	stored, err := a.tokens.RefreshToken(ctx, sum[:])
+	if err != nil {
+		if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+			return &Introspection{}, nil
+		}
+
+		return nil, err
+	}
+
+	if !stored.RevokedAt.IsZero() || !a.clock.Now().Before(stored.ExpiresAt) {
+		return &Introspection{}, nil
+	}
+
+	user, err := a.users.UserByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return &Introspection{}, nil
+		}
+
+		return nil, err
+	}
+
+	app, err := a.apps.App(ctx, stored.AppID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			return &Introspection{}, nil
+		}
+
+		return nil, err
+	}
+
+	if !user.TokensRevokedAt.IsZero() && !stored.CreatedAt.After(user.TokensRevokedAt) {
+		return &Introspection{}, nil
+	}
+
+	if !app.TokensRevokedAt.IsZero() && !stored.CreatedAt.After(app.TokensRevokedAt) {
+		return &Introspection{}, nil
+	}
+
+	if err := a.checkAppMember(ctx, user, app); err != nil {
+		if errors.Is(err, ErrNotAppMember) {
+			return &Introspection{}, nil
+		}
+
+		return nil, err
+	}
+
+	// Refresh tokens are only exchanged with the app they were issued for.
+	return &Introspection{
+		Active:    true,
+		TokenType: TokenTypeRefresh,
+		User:      user,
+		AppID:     stored.AppID,
+		Audience:  []int32{stored.AppID},
+		IssuedAt:  stored.CreatedAt,
+		ExpiresAt: stored.ExpiresAt,
+		Authn:     jwt.Authentication{Time: stored.AuthTime, Methods: stored.Methods, ACR: stored.ACR},
+	}, nil
+}
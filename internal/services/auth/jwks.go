@@ -0,0 +1,9 @@
+package auth
+
+import "github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+
+// JWKS returns the public keys the tokens of apps with the RS256 format are verified
+// against, so consumers can verify them offline. It is empty when no signing key is configured.
+func (a *Auth) JWKS() []jwt.JWK {
+	return a.signingKeys.JWKS()
+}
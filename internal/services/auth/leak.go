@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/mailer"
+	"github.com/kirinyoku/sso-grpc/internal/lib/templates"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// leakReportMethod is the method recorded in the audit events of leaked secret rotations.
+const leakReportMethod = "/auth.Auth/ReportLeakedSecret"
+
+// ReportLeakedSecret handles a report from a secret scanner that an app secret leaked, e.g.
+// in a public repository. The secret is replaced with a random one, every token issued for
+// the app is revoked, the rotation is recorded in the audit log, and the addresses in
+// secret_leaks.notify_emails are emailed.
+//
+// Knowing the secret is proof enough to report it. To avoid turning reports into a way of
+// guessing secrets, secrets no app uses are not reported as errors.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - secret: secret found by the scanner
+//   - source: where the secret was found, e.g. the URL of a commit; included in the notification
+//
+// Returns:
+//   - error: nil on success, or an error if the secret cannot be rotated
+func (a *Auth) ReportLeakedSecret(ctx context.Context, secret string, source string) error {
+	const op = "auth.Auth.ReportLeakedSecret"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	app, err := a.apps.AppBySecret(ctx, secret)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("leak reported for unknown secret")
+
+			return nil
+		}
+
+		log.Error("failed to get app", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int("app_id", app.ID))
+
+	// Secrets share the format of password reset tokens.
+	newSecret, _, err := newResetToken()
+	if err != nil {
+		log.Error("failed to generate secret", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := a.clock.Now()
+
+	purged, err := a.apps.ReplaceLeakedAppSecret(ctx, int32(app.ID), secret, newSecret, now)
+	if err != nil {
+		// Another report rotated the secret in the meantime.
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("leaked secret already rotated")
+
+			return nil
+		}
+
+		log.Error("failed to rotate leaked secret", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.InvalidateTokens("")
+
+	log.Warn("leaked app secret rotated and tokens revoked", slog.String("source", source), slog.Int64("purged", purged))
+
+	// Everything below happens after the rotation and must not undo it, so failures are only logged.
+	if err := a.auditor.Record(context.WithoutCancel(ctx), &models.AuditEvent{
+		At:     now,
+		Method: leakReportMethod,
+		Target: "app_id=" + strconv.Itoa(app.ID),
+		Code:   "OK",
+	}); err != nil {
+		log.Error("failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	a.notifySecretLeak(ctx, log, app, source, now)
+
+	return nil
+}
+
+// notifySecretLeak emails the addresses in secret_leaks.notify_emails that the secret of app was rotated.
+func (a *Auth) notifySecretLeak(ctx context.Context, log *slog.Logger, app *models.App, source string, at time.Time) {
+	if len(a.secretLeaksCfg.NotifyEmails) == 0 {
+		return
+	}
+
+	if source == "" {
+		source = "unknown"
+	}
+
+	msg, err := a.templates.Render(templates.SecretLeak, int32(app.ID), "", map[string]string{
+		"App":    app.Name,
+		"AppID":  strconv.Itoa(app.ID),
+		"Time":   at.UTC().Format(time.RFC1123),
+		"Source": source,
+	})
+	if err != nil {
+		log.Error("failed to render secret leak email", slog.String("error", err.Error()))
+
+		return
+	}
+
+	for _, to := range a.secretLeaksCfg.NotifyEmails {
+		if err := a.mailer.Send(ctx, mailer.Message{
+			To:      to,
+			Subject: msg.Subject,
+			Body:    msg.Body,
+		}); err != nil {
+			log.Error("failed to send secret leak email", slog.String("error", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// Logout signs a user out of every app by revoking every token issued to the user so far.
+// To sign out of a single device, revoke its refresh token with RevokeRefreshToken instead.
+// Apps with a back-channel logout URI are sent a logout token, queued with the revocation.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the signed-in user
+//
+// Returns:
+//   - error: nil on success, or an error if the tokens cannot be revoked
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the ID
+//   - other errors: for any other failure
+func (a *Auth) Logout(ctx context.Context, userID int64) error {
+	const op = "auth.Auth.Logout"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	if err := a.tokens.RevokeUserTokens(ctx, userID, a.clock.Now()); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to revoke tokens", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.InvalidateTokens("")
+
+	log.Info("user logged out")
+
+	return nil
+}
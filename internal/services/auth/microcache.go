@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+)
+
+// maxValidatedTokens bounds the number of validation results kept at once.
+const maxValidatedTokens = 10000
+
+// tokenHash is the key of a token in the validation cache. Tokens are not kept in memory
+// in plain text.
+type tokenHash [sha256.Size]byte
+
+// validation is the result of a successful validation, reused for the micro cache TTL.
+type validation struct {
+	claims jwt.Claims
+	readAt time.Time
+}
+
+// tokenKey returns the key of a token in the validation cache.
+func tokenKey(token string) tokenHash {
+	return sha256.Sum256([]byte(token))
+}
+
+// recentValidation returns the claims of a token validated within the micro cache TTL,
+// provided the token has not expired since.
+func (a *Auth) recentValidation(token string) (*jwt.Claims, bool) {
+	if a.validationCfg.MicroCacheTTL <= 0 {
+		return nil, false
+	}
+
+	a.validatedMu.Lock()
+	defer a.validatedMu.Unlock()
+
+	key := tokenKey(token)
+
+	v, ok := a.validated[key]
+	if !ok {
+		return nil, false
+	}
+
+	now := a.clock.Now()
+	if now.Sub(v.readAt) >= a.validationCfg.MicroCacheTTL || !now.Before(v.claims.ExpiresAt.Add(a.validationCfg.Leeway)) {
+		delete(a.validated, key)
+
+		return nil, false
+	}
+
+	claims := v.claims
+
+	return &claims, true
+}
+
+// rememberValidation records the claims of a token that just passed validation.
+func (a *Auth) rememberValidation(token string, claims *jwt.Claims) {
+	if a.validationCfg.MicroCacheTTL <= 0 {
+		return
+	}
+
+	a.validatedMu.Lock()
+	defer a.validatedMu.Unlock()
+
+	now := a.clock.Now()
+
+	if len(a.validated) >= maxValidatedTokens {
+		for key, v := range a.validated {
+			if now.Sub(v.readAt) >= a.validationCfg.MicroCacheTTL {
+				delete(a.validated, key)
+			}
+		}
+
+		// Every entry is fresh: a burst of distinct tokens, which the cache cannot absorb anyway.
+		if len(a.validated) >= maxValidatedTokens {
+			clear(a.validated)
+		}
+	}
+
+	a.validated[tokenKey(token)] = validation{claims: *claims, readAt: now}
+}
+
+// InvalidateTokens forgets every validation result, so tokens revoked on this or another
+// replica are validated against storage again. It takes the key published on the cache bus,
+// which is ignored: revocations are rare, and clearing the whole cache is cheap.
+func (a *Auth) InvalidateTokens(string) {
+	a.validatedMu.Lock()
+	defer a.validatedMu.Unlock()
+
+	clear(a.validated)
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/events"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/lib/mailer"
+	"github.com/kirinyoku/sso-grpc/internal/lib/templates"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// ErrInvalidReportLink is returned when a login report link is malformed, expired, or not signed by the service.
+var ErrInvalidReportLink = errors.New("invalid or expired link")
+
+// loginReportPurpose scopes link tokens to reporting logins.
+const loginReportPurpose = "login_report"
+
+// unknownClientDetail is shown in notifications in place of client details that were not reported.
+const unknownClientDetail = "unknown"
+
+// ClientInfo describes the client a request was made from, as shown in login notifications.
+type ClientInfo struct {
+	IP        string // network address of the client; empty if unknown
+	UserAgent string // user agent reported by the client; empty if unknown
+}
+
+// NotifyLogin handles LoginSucceeded events by emailing the user about the login, with
+// a link to report it as unauthorized, if login notifications are enabled. The email is
+// sent in the background, so a slow mail server does not delay the login. Device logins
+// are not notified, since the user approved them while signed in on another device.
+func (a *Auth) NotifyLogin(ctx context.Context, event events.LoginSucceeded) {
+	if !a.notificationsCfg.Enabled || event.Method == events.LoginDevice {
+		return
+	}
+
+	go a.notifyLogin(context.WithoutCancel(ctx), event)
+}
+
+// notifyLogin sends the login notification. Failures are logged and do not affect the login.
+func (a *Auth) notifyLogin(ctx context.Context, event events.LoginSucceeded) {
+	const op = "auth.Auth.notifyLogin"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", event.UserID),
+	)
+
+	app, err := a.apps.App(ctx, event.AppID)
+	if err != nil {
+		log.Error("failed to get app", slog.String("error", err.Error()))
+
+		return
+	}
+
+	token, err := jwt.NewLinkToken([]byte(a.notificationsCfg.Key), loginReportPurpose, event.UserID, event.At, a.notificationsCfg.LinkTTL)
+	if err != nil {
+		log.Error("failed to sign report link", slog.String("error", err.Error()))
+
+		return
+	}
+
+	reportURL := *a.reportURL
+	query := reportURL.Query()
+	query.Set("token", token)
+	reportURL.RawQuery = query.Encode()
+
+	msg, err := a.templates.Render(templates.LoginNotification, int32(app.ID), "", map[string]string{
+		"Email":     event.Email,
+		"App":       app.Name,
+		"Time":      event.At.UTC().Format(time.RFC1123),
+		"IP":        orUnknown(event.IP),
+		"UserAgent": orUnknown(event.UserAgent),
+		"ReportURL": reportURL.String(),
+		"ExpiresAt": event.At.Add(a.notificationsCfg.LinkTTL).UTC().Format(time.RFC1123),
+	})
+	if err != nil {
+		log.Error("failed to render login notification", slog.String("error", err.Error()))
+
+		return
+	}
+
+	if err := a.mailer.Send(ctx, mailer.Message{
+		To:      event.Email,
+		Subject: msg.Subject,
+		Body:    msg.Body,
+	}); err != nil {
+		log.Error("failed to send login notification", slog.String("error", err.Error()))
+	}
+}
+
+// ReportLogin handles a user's report that a login was not theirs, made through the
+// link in a login notification, by freezing the account. Freezing revokes every token
+// issued to the user so far, and the account stays frozen until the user unfreezes it
+// through RequestUnfreeze and Unfreeze, proving access to the email and replacing the password.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - token: token from the report link
+//
+// Returns:
+//   - error: nil on success, or an error if the report cannot be handled
+//
+// Possible errors:
+//   - ErrInvalidReportLink: if the token is invalid or expired, its user no longer exists,
+//     or login notifications are disabled
+//   - other errors: for any other failure
+func (a *Auth) ReportLogin(ctx context.Context, token string) error {
+	const op = "auth.Auth.ReportLogin"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	if !a.notificationsCfg.Enabled {
+		log.Warn("login reported while login notifications are disabled")
+
+		return fmt.Errorf("%s: %w", op, ErrInvalidReportLink)
+	}
+
+	now := a.clock.Now()
+
+	claims, err := jwt.ParseLinkToken([]byte(a.notificationsCfg.Key), loginReportPurpose, token, now)
+	if err != nil {
+		log.Warn("invalid report link", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, ErrInvalidReportLink)
+	}
+
+	log = log.With(slog.Int64("user_id", claims.UserID))
+
+	if err := a.users.FreezeUser(ctx, claims.UserID, now); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidReportLink)
+		}
+
+		log.Error("failed to freeze account", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.InvalidateTokens("")
+
+	log.Warn("login reported as unauthorized, account frozen", slog.Time("login_at", claims.IssuedAt))
+
+	return nil
+}
+
+// orUnknown returns s, or a placeholder if s is empty.
+func orUnknown(s string) string {
+	if s == "" {
+		return unknownClientDetail
+	}
+
+	return s
+}
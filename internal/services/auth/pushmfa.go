@@ -0,0 +1,353 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/events"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// Push MFA errors.
+var (
+	// ErrChallengePending is returned when the user has not yet approved or denied a login challenge
+	ErrChallengePending = errors.New("login challenge pending")
+
+	// ErrChallengeDenied is returned when the user denied a login challenge
+	ErrChallengeDenied = errors.New("login challenge denied")
+
+	// ErrChallengeExpired is returned when a login challenge expired before a token was issued
+	ErrChallengeExpired = errors.New("login challenge expired")
+
+	// ErrInvalidChallenge is returned when a login challenge is unknown, was already decided,
+	// or was already exchanged for a token
+	ErrInvalidChallenge = errors.New("invalid login challenge")
+)
+
+// challengePollMethod is the method recorded in the audit events of tokens issued for approved challenges.
+const challengePollMethod = "/auth.Auth/PollLoginChallenge"
+
+// ChallengeRequiredError is returned by Login for users with push MFA. The password was correct,
+// but no token is issued until the user approves the login from a signed-in device.
+type ChallengeRequiredError struct {
+	ChallengeID string    // secret the client polls PollLoginChallenge with
+	ExpiresAt   time.Time // moment the challenge expires unless approved
+}
+
+func (e *ChallengeRequiredError) Error() string {
+	return "login challenge required"
+}
+
+// startLoginChallenge saves a pending challenge for a login of a user with push MFA,
+// or for a step-up before the given action.
+func (a *Auth) startLoginChallenge(
+	ctx context.Context,
+	user *models.User,
+	appID int32,
+	action string,
+	client ClientInfo,
+) (*ChallengeRequiredError, error) {
+	// Challenge IDs share the format of password reset tokens.
+	challengeID, challengeHash, err := newResetToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := a.clock.Now()
+	expiresAt := now.Add(a.pushMFACfg.ChallengeTTL)
+
+	if err := a.tokens.SaveLoginChallenge(ctx, &models.LoginChallenge{
+		ChallengeHash: challengeHash,
+		UserID:        user.ID,
+		AppID:         appID,
+		Action:        action,
+		IP:            client.IP,
+		UserAgent:     client.UserAgent,
+		CreatedAt:     now,
+		ExpiresAt:     expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &ChallengeRequiredError{ChallengeID: challengeID, ExpiresAt: expiresAt}, nil
+}
+
+// SetPushMFA turns push approval of logins on or off for a user. While it is on, Login
+// returns a challenge instead of a token, which the user approves from a device that
+// stays signed in.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - enabled: whether logins must be approved
+//
+// Returns:
+//   - error: nil on success, or an error if the setting cannot be changed
+//
+// Possible errors:
+//   - ErrUserNotFound: if no user exists with the ID
+//   - other errors: for any other failure
+func (a *Auth) SetPushMFA(ctx context.Context, userID int64, enabled bool) error {
+	const op = "auth.Auth.SetPushMFA"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Bool("enabled", enabled),
+	)
+
+	if err := a.users.SetPushMFA(ctx, userID, enabled); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to set push mfa", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("push mfa changed")
+
+	return nil
+}
+
+// ListLoginChallenges returns the pending, unexpired login challenges of a user, oldest first.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the signed-in user
+//
+// Returns:
+//   - []models.LoginChallenge: the pending challenges
+//   - error: nil on success, or an error if the challenges cannot be read
+func (a *Auth) ListLoginChallenges(ctx context.Context, userID int64) ([]models.LoginChallenge, error) {
+	const op = "auth.Auth.ListLoginChallenges"
+
+	challenges, err := a.tokens.PendingLoginChallenges(ctx, userID, a.clock.Now())
+	if err != nil {
+		a.log.Error("failed to list login challenges",
+			slog.String("op", op),
+			slog.Int64("user_id", userID),
+			slog.String("error", err.Error()),
+		)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return challenges, nil
+}
+
+// DecideLoginChallenge records a signed-in user's decision on one of their pending login challenges.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - id: ID of the challenge, as listed by ListLoginChallenges
+//   - userID: ID of the signed-in user
+//   - approve: true to let the login obtain a token, false to deny it
+//
+// Returns:
+//   - error: nil on success, or an error if the decision cannot be recorded
+//
+// Possible errors:
+//   - ErrInvalidChallenge: if the user has no pending, unexpired challenge with the ID
+//   - other errors: for any other failure
+func (a *Auth) DecideLoginChallenge(ctx context.Context, id int64, userID int64, approve bool) error {
+	const op = "auth.Auth.DecideLoginChallenge"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Int64("challenge_id", id),
+		slog.Bool("approve", approve),
+	)
+
+	decision := models.LoginChallengeDenied
+	if approve {
+		decision = models.LoginChallengeApproved
+	}
+
+	if err := a.tokens.DecideLoginChallenge(ctx, id, userID, decision, a.clock.Now()); err != nil {
+		if errors.Is(err, storage.ErrLoginChallengeNotFound) {
+			log.Warn("invalid login challenge", slog.String("error", err.Error()))
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+		}
+
+		log.Error("failed to record decision", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("login challenge decided")
+
+	return nil
+}
+
+// PollLoginChallenge exchanges a login challenge for a token once the user approved it.
+// A token is issued only once per challenge.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - challengeID: challenge ID returned by Login in a ChallengeRequiredError
+//
+// Returns:
+//   - string: JWT token for the user and the app of the login
+//   - error: nil on success, or an error if no token can be issued yet
+//
+// Possible errors:
+//   - ErrChallengePending: if the user has not decided yet; poll again shortly
+//   - ErrChallengeDenied: if the user denied the login, the account was frozen since approving it,
+//     or the app requires membership the user was not granted
+//   - ErrChallengeExpired: if the challenge expired
+//   - ErrInvalidChallenge: if the challenge is unknown or was already used
+//   - *RejectError: if a PreLogin hook rejected the login
+//   - other errors: for any other failure
+func (a *Auth) PollLoginChallenge(ctx context.Context, challengeID string) (string, error) {
+	const op = "auth.Auth.PollLoginChallenge"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	challengeHash := sha256.Sum256([]byte(challengeID))
+
+	challenge, err := a.tokens.LoginChallenge(ctx, challengeHash[:])
+	if err != nil {
+		if errors.Is(err, storage.ErrLoginChallengeNotFound) {
+			log.Warn("unknown login challenge", slog.String("error", err.Error()))
+
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+		}
+
+		log.Error("failed to get login challenge", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := a.clock.Now()
+
+	switch {
+	case challenge.Status == models.LoginChallengeConsumed:
+		return "", fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+	case challenge.Status == models.LoginChallengeDenied:
+		return "", fmt.Errorf("%s: %w", op, ErrChallengeDenied)
+	case !now.Before(challenge.ExpiresAt):
+		return "", fmt.Errorf("%s: %w", op, ErrChallengeExpired)
+	case challenge.Status == models.LoginChallengePending:
+		return "", fmt.Errorf("%s: %w", op, ErrChallengePending)
+	}
+
+	user, err := a.users.UserByID(ctx, challenge.UserID)
+	if err != nil {
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The account may have been frozen since the user approved the login.
+	if !user.FrozenAt.IsZero() {
+		log.Warn("login approved by frozen account", slog.Int64("user_id", user.ID))
+
+		return "", fmt.Errorf("%s: %w", op, ErrChallengeDenied)
+	}
+
+	app, err := a.apps.App(ctx, challenge.AppID)
+	if err != nil {
+		log.Error("failed to get app", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.runPreHooks(func(h Hook) error { return h.PreLogin(ctx, user.Email, challenge.AppID) }); err != nil {
+		log.Warn("login rejected by hook", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.tokens.ConsumeLoginChallenge(ctx, challenge.ID); err != nil {
+		if errors.Is(err, storage.ErrLoginChallengeNotFound) {
+			// A concurrent poll exchanged the challenge first.
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+		}
+
+		log.Error("failed to consume login challenge", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.checkAppMember(ctx, user, app); err != nil {
+		if errors.Is(err, ErrNotAppMember) {
+			log.Warn("login approved by user who is not a member of the app", slog.Int64("user_id", user.ID))
+
+			return "", fmt.Errorf("%s: %w", op, ErrChallengeDenied)
+		}
+
+		log.Error("failed to check app membership", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.loadAttributes(ctx, user, app); err != nil {
+		log.Error("failed to get user attributes", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.loadFamily(ctx, app); err != nil {
+		log.Error("failed to get app family", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The user approved the login from a signed-in device, a second factor.
+	token, err := jwt.NewToken(user, app, a.signingKeys, now, a.appTokenTTL(app), a.region, a.standardClaims, jwt.Authentication{
+		Time:    now,
+		Methods: []string{jwt.AMRPassword, jwt.AMRMultiFactor},
+		ACR:     jwt.ACRMultiFactor,
+	})
+	if err != nil {
+		log.Error("failed to generate token", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("token issued for approved login", slog.Int64("user_id", user.ID), slog.Int("app_id", app.ID))
+
+	// The token is issued without an authenticated caller, so the interceptor cannot audit it.
+	if err := a.auditor.Record(context.WithoutCancel(ctx), &models.AuditEvent{
+		At:          now,
+		Method:      challengePollMethod,
+		ActorUserID: user.ID,
+		Target:      "app_id=" + strconv.Itoa(app.ID) + " user_id=" + strconv.FormatInt(user.ID, 10),
+		Code:        "OK",
+	}); err != nil {
+		log.Error("failed to record audit event", slog.String("error", err.Error()))
+	}
+
+	if err := a.users.RecordLogin(ctx, user.ID, now); err != nil {
+		log.Error("failed to record login", slog.String("error", err.Error()))
+	}
+
+	a.events.Publish(ctx, events.LoginSucceeded{
+		UserID:    user.ID,
+		Email:     user.Email,
+		AppID:     challenge.AppID,
+		Method:    events.LoginPushMFA,
+		IP:        challenge.IP,
+		UserAgent: challenge.UserAgent,
+		At:        now,
+	})
+
+	a.runPostHooks(log, func(h Hook) error { return h.PostLogin(ctx, user.ID, user.Email, challenge.AppID) })
+
+	return token, nil
+}
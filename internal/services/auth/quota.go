@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/netaddr"
+)
+
+// ErrRegistrationQuotaExceeded is returned when a source address used up its registrations for the day
+var ErrRegistrationQuotaExceeded = errors.New("registration quota exceeded")
+
+// secondsPerDay is the length of the UTC days registrations are counted over.
+const secondsPerDay = 24 * 60 * 60
+
+// registrationQuota counts registration attempts per source address and UTC day.
+// Counts are kept in memory, so each instance enforces the limit on its own.
+type registrationQuota struct {
+	limit     int            // attempts accepted per address and day; 0 disables the quota
+	allowlist []netip.Prefix // addresses exempt from the quota
+	mu        sync.Mutex     // guards day and counts
+	day       int64          // UTC day counts apply to, in days since the Unix epoch
+	counts    map[string]int // attempts made today by each address key
+}
+
+// newRegistrationQuota returns a quota accepting limit attempts per address and day,
+// except from the addresses and CIDR ranges in allowlist.
+func newRegistrationQuota(limit int, allowlist []string) (*registrationQuota, error) {
+	q := &registrationQuota{limit: limit, counts: make(map[string]int)}
+
+	for _, entry := range allowlist {
+		prefix, err := netaddr.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ip_allowlist entry %q: %w", entry, err)
+		}
+
+		q.allowlist = append(q.allowlist, prefix)
+	}
+
+	return q, nil
+}
+
+// QuotaState describes how much of a quota is left to a client, so it can slow down
+// before reaching the limit.
+type QuotaState struct {
+	Limit     int       // attempts accepted per period
+	Remaining int       // attempts left in the current period
+	Reset     time.Time // end of the current period, when the quota is restored
+}
+
+// take counts an attempt from ip at unixTime and reports whether it is within the quota.
+// Attempts from allowlisted or unknown addresses are always accepted.
+func (q *registrationQuota) take(ip string, unixTime int64) bool {
+	key, ok := q.key(ip)
+	if !ok {
+		return true
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover(unixTime)
+
+	if q.counts[key] >= q.limit {
+		return false
+	}
+
+	q.counts[key]++
+
+	return true
+}
+
+// state reports the quota left to ip at unixTime without counting an attempt.
+// It reports false if the quota is disabled or does not apply to ip.
+func (q *registrationQuota) state(ip string, unixTime int64) (QuotaState, bool) {
+	key, ok := q.key(ip)
+	if !ok {
+		return QuotaState{}, false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover(unixTime)
+
+	return QuotaState{
+		Limit:     q.limit,
+		Remaining: max(q.limit-q.counts[key], 0),
+		Reset:     time.Unix((q.day+1)*secondsPerDay, 0).UTC(),
+	}, true
+}
+
+// key returns the key ip is counted under, or false if the quota does not apply to it:
+// when it is disabled, or ip is allowlisted or unknown. IPv6 addresses are counted
+// per /64, the smallest network usually assigned to a single subscriber.
+func (q *registrationQuota) key(ip string) (string, bool) {
+	if q.limit <= 0 {
+		return "", false
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "", false
+	}
+
+	addr = addr.Unmap()
+
+	for _, prefix := range q.allowlist {
+		if prefix.Contains(addr) {
+			return "", false
+		}
+	}
+
+	if addr.Is6() {
+		return netip.PrefixFrom(addr, 64).Masked().String(), true
+	}
+
+	return addr.String(), true
+}
+
+// rollover moves the quota to the UTC day of unixTime. Counts of past days are dropped
+// at once, so the map only grows within a day. q.mu must be held.
+func (q *registrationQuota) rollover(unixTime int64) {
+	if day := unixTime / secondsPerDay; day != q.day {
+		q.day = day
+		q.counts = make(map[string]int)
+	}
+}
+
+// RegistrationQuota reports the registrations left today to the address of client.
+// It reports false if the quota is disabled or the address is exempt from it.
+func (a *Auth) RegistrationQuota(client ClientInfo) (QuotaState, bool) {
+	return a.quota.state(client.IP, a.clock.Now().Unix())
+}
@@ -0,0 +1,306 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// IssueRefreshToken issues a refresh token alongside a token just issued by Login,
+// PollLoginChallenge, or PollDeviceToken. The refresh token starts a new chain, which
+// records the authentication of the token and expires refresh_tokens.ttl after it, and
+// the session of the chain, which records the client listed by ListSessions.
+//
+// Refresh tokens are generated with crypto/rand and only their SHA-256 hash is stored.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - token: access token the refresh token is issued with
+//   - client: network address and user agent of the client the token is issued to
+//
+// Returns:
+//   - string: refresh token, or an empty string if refresh tokens are disabled
+//   - error: nil on success, or an error if the refresh token cannot be issued
+//
+// Possible errors:
+//   - ErrInvalidToken: if the access token is invalid
+//   - other errors: for any other failure
+func (a *Auth) IssueRefreshToken(ctx context.Context, token string, client ClientInfo) (string, error) {
+	const op = "auth.Auth.IssueRefreshToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	if a.refreshCfg.TTL == 0 {
+		return "", nil
+	}
+
+	// The token was just issued, so it is not checked for revocation: one issued in the second
+	// tokens were revoked is rejected, and so is the refresh token issued with it.
+	claims, user, _, err := a.verifyToken(ctx, token, a.validationCfg.Leeway)
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			log.Warn("invalid token", slog.String("error", err.Error()))
+
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Error("failed to validate token", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Refresh tokens share the format of password reset tokens.
+	refreshToken, refreshTokenHash, err := newResetToken()
+	if err != nil {
+		log.Error("failed to generate refresh token", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := a.clock.Now()
+
+	if err := a.tokens.SaveRefreshToken(ctx, &models.RefreshToken{
+		TokenHash: refreshTokenHash,
+		UserID:    user.ID,
+		AppID:     claims.AppID,
+		AuthTime:  claims.AuthTime,
+		Methods:   claims.AMR,
+		ACR:       claims.ACR,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		CreatedAt: now,
+		ExpiresAt: now.Add(a.refreshCfg.TTL),
+	}); err != nil {
+		log.Error("failed to save refresh token", slog.String("error", err.Error()))
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return refreshToken, nil
+}
+
+// ExchangeRefreshToken exchanges a refresh token for a new access token with a full
+// lifetime, and rotates it: the refresh token is revoked and the next one of its chain is
+// returned instead, expiring with the chain. A revoked refresh token presented again was
+// either stolen or replayed, so the whole chain is revoked.
+//
+// Refresh tokens issued before the tokens of their user or app were revoked, e.g. by
+// Logout or Admin.RevokeAppTokens, are rejected like access tokens.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - refreshToken: refresh token previously returned by IssueRefreshToken or ExchangeRefreshToken
+//
+// Returns:
+//   - string: new access token in the app's current token format
+//   - string: next refresh token of the chain
+//   - error: nil on success, or an error if the refresh token cannot be exchanged
+//
+// Possible errors:
+//   - ErrInvalidToken: if the refresh token is unknown, revoked, or expired,
+//     or was issued for an unknown app or user
+//   - ErrNotAppMember: if the app requires membership and the user's access was revoked
+//   - other errors: for any other failure
+func (a *Auth) ExchangeRefreshToken(ctx context.Context, refreshToken string) (string, string, error) {
+	const op = "auth.Auth.ExchangeRefreshToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	sum := sha256.Sum256([]byte(refreshToken))
+
+	stored, err := a.tokens.RefreshToken(ctx, sum[:])
+	if err != nil {
+		if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+			log.Warn("refresh token not found")
+
+			return "", "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get refresh token", slog.String("error", err.Error()))
+
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", stored.UserID), slog.Int64("chain_id", stored.ChainID))
+
+	now := a.clock.Now()
+
+	if !stored.RevokedAt.IsZero() {
+		log.Warn("revoked refresh token presented, revoking its chain")
+
+		if err := a.tokens.RevokeRefreshTokenChain(ctx, stored.ChainID, now); err != nil {
+			log.Error("failed to revoke refresh token chain", slog.String("error", err.Error()))
+
+			return "", "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		return "", "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	if !now.Before(stored.ExpiresAt) {
+		log.Warn("refresh token expired")
+
+		return "", "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	user, err := a.users.UserByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", slog.String("error", err.Error()))
+
+			return "", "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get user", slog.String("error", err.Error()))
+
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err := a.apps.App(ctx, stored.AppID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return "", "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get app", slog.String("error", err.Error()))
+
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !user.TokensRevokedAt.IsZero() && !stored.CreatedAt.After(user.TokensRevokedAt) {
+		log.Warn("refresh token was revoked")
+
+		return "", "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	if !app.TokensRevokedAt.IsZero() && !stored.CreatedAt.After(app.TokensRevokedAt) {
+		log.Warn("tokens of the app were revoked", slog.Int("app_id", app.ID))
+
+		return "", "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	if err := a.checkAppMember(ctx, user, app); err != nil {
+		if errors.Is(err, ErrNotAppMember) {
+			log.Warn("user is not a member of the app", slog.Int("app_id", app.ID))
+
+			return "", "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Error("failed to check app membership", slog.String("error", err.Error()))
+
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.loadAttributes(ctx, user, app); err != nil {
+		log.Error("failed to get user attributes", slog.String("error", err.Error()))
+
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.loadFamily(ctx, app); err != nil {
+		log.Error("failed to get app family", slog.String("error", err.Error()))
+
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	next, nextHash, err := newResetToken()
+	if err != nil {
+		log.Error("failed to generate refresh token", slog.String("error", err.Error()))
+
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Concurrent exchanges of the same token race here; only the first one rotates it.
+	if err := a.tokens.RotateRefreshToken(ctx, stored.ID, &models.RefreshToken{
+		TokenHash: nextHash,
+		ChainID:   stored.ChainID,
+		UserID:    stored.UserID,
+		AppID:     stored.AppID,
+		AuthTime:  stored.AuthTime,
+		Methods:   stored.Methods,
+		ACR:       stored.ACR,
+		CreatedAt: now,
+		ExpiresAt: stored.ExpiresAt,
+	}); err != nil {
+		if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+			log.Warn("refresh token was exchanged concurrently")
+
+			return "", "", fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to rotate refresh token", slog.String("error", err.Error()))
+
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Refreshing is not authenticating, so the new token records the authentication at login.
+	token, err := jwt.NewToken(user, app, a.signingKeys, now, a.appTokenTTL(app), a.region, a.standardClaims, jwt.Authentication{
+		Time:    stored.AuthTime,
+		Methods: stored.Methods,
+		ACR:     stored.ACR,
+	})
+	if err != nil {
+		log.Error("failed to generate token", slog.String("error", err.Error()))
+
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("refresh token exchanged")
+
+	return token, next, nil
+}
+
+// RevokeRefreshToken revokes a refresh token and every other token of its chain, signing
+// the device it was issued to out once its access token expires. Unknown and already
+// revoked refresh tokens are not reported as errors, as in RFC 7009.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - refreshToken: refresh token to revoke
+//
+// Returns:
+//   - error: nil on success, or an error if the chain cannot be revoked
+func (a *Auth) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	const op = "auth.Auth.RevokeRefreshToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	sum := sha256.Sum256([]byte(refreshToken))
+
+	stored, err := a.tokens.RefreshToken(ctx, sum[:])
+	if err != nil {
+		if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+			log.Warn("refresh token not found")
+
+			return nil
+		}
+
+		log.Error("failed to get refresh token", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.tokens.RevokeRefreshTokenChain(ctx, stored.ChainID, a.clock.Now()); err != nil {
+		log.Error("failed to revoke refresh token chain", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("refresh token revoked", slog.Int64("user_id", stored.UserID), slog.Int64("chain_id", stored.ChainID))
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/disposable"
+	"github.com/kirinyoku/sso-grpc/internal/lib/emaildomain"
+)
+
+// checkEmailDomain verifies that the email's domain may register, first against
+// the lists applied to every app, then against the lists of the given app, and
+// finally against the disposable email policy in effect for the app.
+// Returns a *RejectError describing the reason if the domain is not allowed,
+// and whether the user should be flagged as using a disposable email. Violations of
+// policies in report-only mode are logged to log and let through.
+func (a *Auth) checkEmailDomain(ctx context.Context, log *slog.Logger, email string, appID int32) (flagDisposable bool, err error) {
+	var (
+		allowed, denied []string
+		policy          disposable.Policy
+	)
+
+	if appID != 0 {
+		allowed, denied, err = a.apps.AppEmailDomains(ctx, appID)
+		if err != nil {
+			return false, err
+		}
+
+		appPolicy, err := a.apps.AppDisposableEmailPolicy(ctx, appID)
+		if err != nil {
+			return false, err
+		}
+
+		policy = disposable.Policy(appPolicy)
+	}
+
+	flagDisposable, violations := a.evaluateEmailDomain(emaildomain.FromEmail(email), allowed, denied, policy)
+
+	for _, v := range violations {
+		if err := a.enforce(log, v.policy, v.err); err != nil {
+			return false, err
+		}
+	}
+
+	return flagDisposable, nil
+}
+
+// registrationViolation is a registration policy an email domain violates.
+type registrationViolation struct {
+	policy string       // policy violated, as named in the policy_violations metric
+	err    *RejectError // reason the registration is rejected if the policy is enforced
+}
+
+// evaluateEmailDomain checks domain against the lists applied to every app, the lists
+// of an app, and the disposable email policy in effect for the app: policy, or the server
+// default if empty. It returns whether the user should be flagged as using a disposable
+// email, and the policies the domain violates in the order they are checked.
+func (a *Auth) evaluateEmailDomain(domain string, allowed, denied []string, policy disposable.Policy) (bool, []registrationViolation) {
+	var violations []registrationViolation
+
+	if err := domainAllowed(domain, a.allowed, a.denied, ""); err != nil {
+		violations = append(violations, registrationViolation{policy: policyRegistrationDomains, err: err})
+	}
+
+	if err := domainAllowed(domain, allowed, denied, " for this app"); err != nil {
+		violations = append(violations, registrationViolation{policy: policyRegistrationDomains, err: err})
+	}
+
+	if policy == "" {
+		policy = a.disposablePolicy
+	}
+
+	if policy == disposable.PolicyAllow || !a.disposableList.Contains(domain) {
+		return false, violations
+	}
+
+	if policy == disposable.PolicyReject {
+		return false, append(violations, registrationViolation{
+			policy: policyDisposableEmails,
+			err:    &RejectError{Reason: "disposable email addresses are not allowed to register"},
+		})
+	}
+
+	return true, violations
+}
+
+// domainAllowed reports whether domain passes the given lists.
+// An empty allowed list allows every domain that is not denied.
+func domainAllowed(domain string, allowed []string, denied []string, scope string) *RejectError {
+	if slices.Contains(denied, domain) {
+		return &RejectError{Reason: fmt.Sprintf("email domain %s is not allowed to register%s", domain, scope)}
+	}
+
+	if len(allowed) > 0 && !slices.Contains(allowed, domain) {
+		return &RejectError{Reason: fmt.Sprintf("registration%s is limited to email domains: %s", scope, strings.Join(allowed, ", "))}
+	}
+
+	return nil
+}
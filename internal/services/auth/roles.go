@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ErrUnavailable is returned when storage did not answer in time and no fallback was fresh enough
+var ErrUnavailable = errors.New("storage unavailable")
+
+// errQueryTimeout is returned when a storage query outlives its own deadline while the caller still waits.
+var errQueryTimeout = errors.New("storage query timed out")
+
+// cachedRole is the admin status of a user as last read from storage.
+type cachedRole struct {
+	isAdmin bool
+	readAt  time.Time
+}
+
+// queryIsAdmin reads the admin status of a user from storage within the is_admin storage
+// timeout, and remembers it for later fallbacks. SQLite waits for locks without checking
+// the context, so the query runs in its own goroutine and is abandoned, rather than
+// awaited, once the deadline passes. Returns errQueryTimeout if it did not answer in time.
+func (a *Auth) queryIsAdmin(ctx context.Context, userID int64) (bool, error) {
+	if a.timeoutsCfg.IsAdmin <= 0 {
+		isAdmin, err := a.users.IsAdmin(ctx, userID)
+		if err == nil {
+			a.rememberRole(userID, isAdmin)
+		}
+
+		return isAdmin, err
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, a.timeoutsCfg.IsAdmin)
+
+	type result struct {
+		isAdmin bool
+		err     error
+	}
+
+	results := make(chan result, 1)
+
+	go func() {
+		defer cancel()
+
+		isAdmin, err := a.users.IsAdmin(queryCtx, userID)
+		if err == nil {
+			a.rememberRole(userID, isAdmin)
+		}
+
+		results <- result{isAdmin: isAdmin, err: err}
+	}()
+
+	select {
+	case r := <-results:
+		// The driver may honor the deadline itself; only the query's own deadline falls back.
+		if r.err != nil && errors.Is(queryCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			return false, errQueryTimeout
+		}
+
+		return r.isAdmin, r.err
+	case <-queryCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		return false, errQueryTimeout
+	}
+}
+
+// rememberRole records the admin status of a user just read from storage,
+// so it can be served if a later query times out.
+func (a *Auth) rememberRole(userID int64, isAdmin bool) {
+	if a.timeoutsCfg.RoleCacheAge <= 0 && a.validationCfg.MicroCacheTTL <= 0 {
+		return
+	}
+
+	a.rolesMu.Lock()
+	defer a.rolesMu.Unlock()
+
+	a.roles[userID] = cachedRole{isAdmin: isAdmin, readAt: a.clock.Now()}
+}
+
+// recentRole returns the last admin status read for a user, if it is younger than
+// maxAge, along with its age. Statuses older than both the role cache age and the
+// micro cache TTL are forgotten.
+func (a *Auth) recentRole(userID int64, maxAge time.Duration) (bool, time.Duration, bool) {
+	a.rolesMu.Lock()
+	defer a.rolesMu.Unlock()
+
+	role, ok := a.roles[userID]
+	if !ok {
+		return false, 0, false
+	}
+
+	age := a.clock.Now().Sub(role.readAt)
+	if age > max(a.timeoutsCfg.RoleCacheAge, a.validationCfg.MicroCacheTTL) {
+		delete(a.roles, userID)
+
+		return false, 0, false
+	}
+
+	if age >= maxAge {
+		return false, 0, false
+	}
+
+	return role.isAdmin, age, true
+}
+
+// InvalidateRole forgets the admin status last read for a user, whose privileges changed
+// on this or another replica, so it is never served after the change. It takes the user
+// ID as published on the cache bus; an empty key forgets every user.
+func (a *Auth) InvalidateRole(key string) {
+	a.rolesMu.Lock()
+	defer a.rolesMu.Unlock()
+
+	if key == "" {
+		clear(a.roles)
+
+		return
+	}
+
+	userID, err := strconv.ParseInt(key, 10, 64)
+	if err != nil {
+		return
+	}
+
+	delete(a.roles, userID)
+}
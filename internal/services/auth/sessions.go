@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// ErrSessionNotFound is returned when a user has no active session with the ID.
+var ErrSessionNotFound = errors.New("session not found")
+
+// ListSessions returns the active sessions of a user, most recently used first. A session
+// is a sign-in holding a refresh token that can still be exchanged, so sign-ins without
+// refresh tokens are not listed.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the signed-in user
+//
+// Returns:
+//   - []models.Session: the active sessions
+//   - error: nil on success, or an error if the sessions cannot be read
+func (a *Auth) ListSessions(ctx context.Context, userID int64) ([]models.Session, error) {
+	const op = "auth.Auth.ListSessions"
+
+	sessions, err := a.tokens.UserSessions(ctx, userID, a.clock.Now())
+	if err != nil {
+		a.log.Error("failed to list sessions",
+			slog.String("op", op),
+			slog.Int64("user_id", userID),
+			slog.String("error", err.Error()),
+		)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs a user out of one of their sessions by revoking its refresh tokens,
+// like RevokeRefreshToken does for the device holding them. Access tokens already issued
+// to the session stay valid until they expire.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - id: ID of the session, as listed by ListSessions
+//   - userID: ID of the signed-in user
+//
+// Returns:
+//   - error: nil on success, or an error if the session cannot be revoked
+//
+// Possible errors:
+//   - ErrSessionNotFound: if the user has no active session with the ID
+//   - other errors: for any other failure
+func (a *Auth) RevokeSession(ctx context.Context, id, userID int64) error {
+	const op = "auth.Auth.RevokeSession"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("session_id", id),
+		slog.Int64("user_id", userID),
+	)
+
+	if err := a.tokens.RevokeSession(ctx, id, userID, a.clock.Now()); err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			log.Warn("session not found")
+
+			return fmt.Errorf("%s: %w", op, ErrSessionNotFound)
+		}
+
+		log.Error("failed to revoke session", slog.String("error", err.Error()))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("session revoked")
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/disposable"
+	"github.com/kirinyoku/sso-grpc/internal/lib/emaildomain"
+	"github.com/kirinyoku/sso-grpc/internal/lib/events"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+)
+
+// SimulateLogin evaluates what a password login of a user to an app would yield, applying
+// the checks Login applies after the password, without issuing a token, recording the login,
+// or starting a push approval. The app may carry settings that are only proposed, so the
+// effect of changing them can be reviewed before they are saved. PreLogin hooks are not run.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - user: user logging in
+//   - app: app logged in to, with the settings to evaluate
+//
+// Returns:
+//   - *models.LoginSimulation: whether the login would be allowed, and the claims of its token
+//   - error: nil on success, or an error if the checks cannot be evaluated
+func (a *Auth) SimulateLogin(ctx context.Context, user *models.User, app *models.App) (*models.LoginSimulation, error) {
+	const op = "auth.Auth.SimulateLogin"
+
+	switch {
+	case user.MergedInto != 0:
+		return &models.LoginSimulation{DenialReason: events.FailureMergedAccount}, nil
+	case !user.FrozenAt.IsZero():
+		return &models.LoginSimulation{DenialReason: events.FailureFrozenAccount}, nil
+	}
+
+	if err := a.checkAppMember(ctx, user, app); err != nil {
+		if errors.Is(err, ErrNotAppMember) {
+			return &models.LoginSimulation{DenialReason: events.FailureNotAppMember}, nil
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	authn := jwt.Authentication{Methods: []string{jwt.AMRPassword}, ACR: jwt.ACRSingleFactor}
+	if user.PushMFA {
+		authn = jwt.Authentication{Methods: []string{jwt.AMRPassword, jwt.AMRMultiFactor}, ACR: jwt.ACRMultiFactor}
+	}
+
+	if err := a.loadAttributes(ctx, user, app); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.loadFamily(ctx, app); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := a.clock.Now()
+	authn.Time = now
+
+	claims, err := jwt.PreviewClaims(user, app, a.signingKeys, now, a.appTokenTTL(app), a.region, a.standardClaims, authn)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &models.LoginSimulation{
+		Allowed:              true,
+		PushApprovalRequired: user.PushMFA,
+		Claims:               claims,
+	}, nil
+}
+
+// SimulateRegistration evaluates how the email domain and disposable email policies would
+// handle a registration, given the lists and policy of the app registered through, which may
+// be only proposed. Policies are evaluated as if enforced, even in report-only mode, and
+// nothing is counted or logged.
+//
+// Parameters:
+//   - email: email registering
+//   - allowed, denied: email domains the app allows and denies registration, normalized
+//   - policy: disposable email policy of the app; empty for the server default
+//
+// Returns:
+//   - *models.RegistrationSimulation: the outcome, with the first policy rejecting the registration
+func (a *Auth) SimulateRegistration(email string, allowed, denied []string, policy disposable.Policy) *models.RegistrationSimulation {
+	flagDisposable, violations := a.evaluateEmailDomain(emaildomain.FromEmail(email), allowed, denied, policy)
+
+	switch {
+	case len(violations) > 0:
+		return &models.RegistrationSimulation{
+			Outcome: models.RegistrationRejected,
+			Reason:  violations[0].err.Reason,
+			Policy:  violations[0].policy,
+		}
+	case flagDisposable:
+		return &models.RegistrationSimulation{Outcome: models.RegistrationFlagged}
+	default:
+		return &models.RegistrationSimulation{Outcome: models.RegistrationAccepted}
+	}
+}
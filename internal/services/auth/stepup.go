@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+)
+
+// ErrStepUpUnavailable is returned when a step-up is required from a user without a second factor.
+var ErrStepUpUnavailable = errors.New("step-up requires push mfa")
+
+// RequireStepUp checks that the user of a token approved a second factor recently enough
+// for a sensitive action, e.g. changing the email address or deleting the account. The
+// allowed age is the step_up max_age of the action, or the default max_age. If the token
+// is not elevated enough, a challenge is started, which the user approves like a login
+// challenge; PollLoginChallenge then returns an elevated token for the same app.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - token: token of the signed-in user
+//   - action: name of the sensitive action, shown to the user with the challenge
+//   - client: client the action is requested from, shown to the user with the challenge
+//
+// Returns:
+//   - jwt.Authentication: when the user approved the second factor, if it is recent enough
+//   - error: nil if the token is elevated enough, or an error otherwise
+//
+// Possible errors:
+//   - ErrInvalidToken: if the token is malformed, badly signed, expired, or revoked
+//   - *ChallengeRequiredError: if the user must approve a second factor first
+//   - ErrStepUpUnavailable: if the user must approve a second factor but has no push MFA
+//   - other errors: for any other failure
+func (a *Auth) RequireStepUp(ctx context.Context, token string, action string, client ClientInfo) (jwt.Authentication, error) {
+	const op = "auth.Auth.RequireStepUp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("action", action),
+	)
+
+	claims, user, err := a.parseToken(ctx, token, a.validationCfg.Leeway)
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			log.Warn("invalid token", slog.String("error", err.Error()))
+
+			return jwt.Authentication{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Error("failed to validate token", slog.String("error", err.Error()))
+
+		return jwt.Authentication{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", user.ID), slog.Int("app_id", int(claims.AppID)))
+
+	maxAge, ok := a.stepUpCfg.Actions[action]
+	if !ok {
+		maxAge = a.stepUpCfg.MaxAge
+	}
+
+	authn := claims.Authentication()
+	if authn.ACR == jwt.ACRMultiFactor && a.clock.Now().Sub(authn.Time) <= maxAge {
+		return authn, nil
+	}
+
+	if !user.PushMFA {
+		log.Warn("step-up required from user without push mfa")
+
+		return jwt.Authentication{}, fmt.Errorf("%s: %w", op, ErrStepUpUnavailable)
+	}
+
+	challengeErr, err := a.startLoginChallenge(ctx, user, claims.AppID, action, client)
+	if err != nil {
+		log.Error("failed to start step-up challenge", slog.String("error", err.Error()))
+
+		return jwt.Authentication{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("step-up waits for push approval")
+
+	return jwt.Authentication{}, fmt.Errorf("%s: %w", op, challengeErr)
+}
@@ -0,0 +1,132 @@
+//go:build !notesttokens
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+var (
+	// ErrTestTokensDisabled is returned when a test token is requested without test_tokens.enabled set
+	ErrTestTokensDisabled = errors.New("test tokens are disabled")
+
+	// ErrInvalidTestTokenTTL is returned when a test token is requested for longer than test_tokens.max_ttl
+	ErrInvalidTestTokenTTL = errors.New("ttl exceeds test_tokens.max_ttl")
+
+	// ErrAppNotTestOnly is returned when a test token is requested for an app not flagged test-only
+	ErrAppNotTestOnly = errors.New("app is not test-only")
+)
+
+// TestTokenClaims holds the claims of a test token that name its user and how the user authenticated.
+type TestTokenClaims struct {
+	Subject    string            // public ID of the user, the "sub" claim
+	UserID     int64             // the "user_id" claim of full tokens
+	Email      string            // the "email" claim of full tokens
+	Attributes map[string]string // the "attrs" claim of full tokens; nil omits it
+	AMR        []string          // the "amr" claim; nil omits it, and "auth_time" with it
+	ACR        string            // the "acr" claim; empty omits it
+	Scopes     []string          // the "scope" claim; nil omits it
+}
+
+// MintTestToken mints a token for an app with the given claims, signed and formatted like
+// the app's tokens, so CI suites of dependent services can obtain tokens without seeding
+// users. The user named by the claims is not looked up, so it need not exist, and the token
+// carries the "test" claim, which makes this service reject it. It is only minted for apps
+// flagged test-only. For development only: it is refused unless test tokens are enabled,
+// and builds with the notesttokens tag leave it out.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the app the token is minted for
+//   - claims: claims naming the user and how the user authenticated
+//   - ttl: lifetime of the token; 0 selects the lifetime of tokens issued for the app
+//
+// Returns:
+//   - string: the token
+//   - time.Time: moment the token expires
+//   - error: nil on success, or an error if the token cannot be minted
+//
+// Possible errors:
+//   - ErrTestTokensDisabled: if test tokens are not enabled
+//   - ErrInvalidTestTokenTTL: if ttl exceeds the longest lifetime allowed
+//   - ErrInvalidAppID: if no app exists with the ID
+//   - ErrAppNotTestOnly: if the app is not flagged test-only
+func (a *Auth) MintTestToken(ctx context.Context, appID int32, claims TestTokenClaims, ttl time.Duration) (string, time.Time, error) {
+	const op = "auth.Auth.MintTestToken"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(appID)),
+		slog.String("subject", claims.Subject),
+	)
+
+	if !a.testTokensCfg.Enabled {
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrTestTokensDisabled)
+	}
+
+	app, err := a.apps.App(ctx, appID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Warn("app not found", slog.String("error", err.Error()))
+
+			return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidAppID)
+		}
+
+		log.Error("failed to get app", slog.String("error", err.Error()))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !app.TestOnly {
+		log.Warn("app is not test-only")
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrAppNotTestOnly)
+	}
+
+	if ttl == 0 {
+		ttl = a.appTokenTTL(app)
+	}
+
+	if ttl > a.testTokensCfg.MaxTTL {
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, ErrInvalidTestTokenTTL)
+	}
+
+	if err := a.loadFamily(ctx, app); err != nil {
+		log.Error("failed to get app family", slog.String("error", err.Error()))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user := &models.User{
+		ID:         claims.UserID,
+		PublicID:   claims.Subject,
+		Email:      claims.Email,
+		Attributes: claims.Attributes,
+	}
+
+	now := a.clock.Now()
+
+	authn := jwt.Authentication{Methods: claims.AMR, ACR: claims.ACR, Scopes: claims.Scopes, Test: true}
+	if len(claims.AMR) > 0 {
+		authn.Time = now
+	}
+
+	token, err := jwt.NewToken(user, app, a.signingKeys, now, ttl, a.region, a.standardClaims, authn)
+	if err != nil {
+		log.Error("failed to generate token", slog.String("error", err.Error()))
+
+		return "", time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Warn("test token minted", slog.Duration("ttl", ttl))
+
+	return token, now.Add(ttl), nil
+}
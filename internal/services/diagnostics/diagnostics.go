@@ -0,0 +1,165 @@
+// Package diagnostics samples the goroutines, heap, and garbage collector of the process,
+// so leaks can be spotted and investigated without attaching a profiler.
+//
+// Samples are published as metrics and reported by the GetDiagnostics admin RPC. When
+// the goroutines or the heap grow past a threshold, the goroutine stacks are logged,
+// since by the time someone looks, the process may have been restarted.
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"expvar"
+	"log/slog"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+)
+
+// maxDumpSize bounds the goroutine stacks logged at once, so a leak of thousands of
+// distinct stacks does not flood the log.
+const maxDumpSize = 1 << 20
+
+// Heap and GC statistics are already published by expvar as "memstats".
+var (
+	goroutinesMetric = expvar.NewInt("runtime_goroutines")
+	stackDumpsMetric = expvar.NewInt("diagnostics_stack_dumps")
+)
+
+// Watchdog samples the runtime and logs goroutine stacks when a threshold is crossed.
+type Watchdog struct {
+	log     *slog.Logger       // logger for structured logging
+	cfg     config.Diagnostics // sample interval, thresholds, and dump cooldown
+	started time.Time          // when the process started
+
+	mu       sync.Mutex
+	dumps    int64     // stacks logged since the process started
+	lastDump time.Time // when stacks were last logged
+}
+
+// New creates a new Watchdog.
+//
+// Parameters:
+//   - log: logger instance for structured logging
+//   - cfg: sample interval, thresholds, and dump cooldown; a sample interval of 0 disables sampling in the background
+//
+// Returns a new *Watchdog instance ready to use.
+func New(log *slog.Logger, cfg config.Diagnostics) *Watchdog {
+	return &Watchdog{
+		log:     log,
+		cfg:     cfg,
+		started: time.Now(),
+	}
+}
+
+// Sample reads the current state of the runtime. It briefly stops the world to read
+// the heap statistics, so it is cheap enough for an admin call but not for every request.
+func (w *Watchdog) Sample() *models.Diagnostics {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	d := &models.Diagnostics{
+		SampledAt:    time.Now(),
+		StartedAt:    w.started,
+		GoVersion:    runtime.Version(),
+		GOMAXPROCS:   runtime.GOMAXPROCS(0),
+		Goroutines:   runtime.NumGoroutine(),
+		HeapAlloc:    mem.HeapAlloc,
+		HeapInuse:    mem.HeapInuse,
+		HeapObjects:  mem.HeapObjects,
+		Sys:          mem.Sys,
+		NumGC:        mem.NumGC,
+		GCPauseTotal: time.Duration(mem.PauseTotalNs),
+	}
+
+	if mem.NumGC > 0 {
+		d.LastGC = time.Unix(0, int64(mem.LastGC))
+		d.LastGCPause = time.Duration(mem.PauseNs[(mem.NumGC+255)%256])
+	}
+
+	w.mu.Lock()
+	d.StackDumps = w.dumps
+	d.LastDump = w.lastDump
+	w.mu.Unlock()
+
+	return d
+}
+
+// Run samples the runtime every sample interval, publishing the goroutine count and
+// logging the goroutine stacks when a threshold is crossed, until ctx is canceled.
+// It returns immediately if the sample interval is 0.
+func (w *Watchdog) Run(ctx context.Context) {
+	if w.cfg.SampleInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.cfg.SampleInterval)
+	defer ticker.Stop()
+
+	for {
+		w.check(w.Sample())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check publishes a sample and logs the goroutine stacks if it crosses a threshold and
+// no stacks were logged within the cooldown. Stacks keep being logged every cooldown
+// while the threshold stays crossed, showing whether the leak grows.
+func (w *Watchdog) check(d *models.Diagnostics) {
+	const op = "diagnostics.Watchdog.check"
+
+	goroutinesMetric.Set(int64(d.Goroutines))
+
+	goroutinesCrossed := w.cfg.GoroutineThreshold > 0 && d.Goroutines > w.cfg.GoroutineThreshold
+	heapCrossed := w.cfg.HeapThresholdMB > 0 && d.HeapAlloc > uint64(w.cfg.HeapThresholdMB)<<20
+
+	if !goroutinesCrossed && !heapCrossed {
+		return
+	}
+
+	w.mu.Lock()
+	if !w.lastDump.IsZero() && d.SampledAt.Sub(w.lastDump) < w.cfg.DumpCooldown {
+		w.mu.Unlock()
+
+		return
+	}
+
+	w.dumps++
+	w.lastDump = d.SampledAt
+	w.mu.Unlock()
+
+	stackDumpsMetric.Add(1)
+
+	w.log.Warn("runtime threshold crossed, dumping goroutine stacks",
+		slog.String("op", op),
+		slog.Int("goroutines", d.Goroutines),
+		slog.Int("goroutine_threshold", w.cfg.GoroutineThreshold),
+		slog.Uint64("heap_alloc_mb", d.HeapAlloc>>20),
+		slog.Int("heap_threshold_mb", w.cfg.HeapThresholdMB),
+		slog.String("stacks", goroutineStacks()),
+	)
+}
+
+// goroutineStacks returns the stacks of every goroutine, with goroutines sharing a stack
+// grouped and counted, which makes leaked goroutines stand out. It is truncated to maxDumpSize.
+func goroutineStacks() string {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return "failed to dump goroutine stacks: " + err.Error()
+	}
+
+	if buf.Len() > maxDumpSize {
+		return buf.String()[:maxDumpSize] + "\n... truncated"
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,266 @@
+// Package keyring rotates the RSA keys RS256 tokens are signed with. Rotated keys are stored
+// in the database, so every replica signs with the same key, and reloaded whenever a replica
+// rotates them. A retired key keeps verifying tokens for a grace period, so tokens signed
+// before a rotation stay valid until they expire.
+package keyring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/cachebus"
+	"github.com/kirinyoku/sso-grpc/internal/lib/clock"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// reloadTimeout bounds the storage query reloading the keys after they were rotated.
+const reloadTimeout = time.Second
+
+// ErrRotationConflict is returned when another rotation completed while a key was being rotated.
+var ErrRotationConflict = errors.New("signing key was rotated concurrently")
+
+// Storage defines the interface used to store rotated signing keys.
+type Storage interface {
+	// SigningKeys returns the active key and the keys retired after a moment, newest first.
+	SigningKeys(ctx context.Context, retiredAfter time.Time) ([]*models.SigningKey, error)
+	// RotateSigningKey makes a new key sign tokens in place of the current one.
+	RotateSigningKey(ctx context.Context, current *models.SigningKey, next *models.SigningKey, at time.Time) error
+	// DeleteSigningKeys removes the keys retired before a moment.
+	DeleteSigningKeys(ctx context.Context, retiredBefore time.Time) (int64, error)
+}
+
+// CacheBus defines the interface used to make every replica reload the keys.
+type CacheBus interface {
+	// Publish invalidates an entry of a topic.
+	Publish(ctx context.Context, topic string, key string) error
+}
+
+// Keyring loads the signing keys into the keys tokens are signed with and verified against,
+// and rotates them, safe for concurrent use.
+type Keyring struct {
+	log     *slog.Logger        // logger for structured logging
+	storage Storage             // storage of rotated keys
+	bus     CacheBus            // makes every replica reload rotated keys
+	keys    *jwt.Keys           // keys tokens are signed with and verified against; nil when none are configured
+	cfg     config.TokenSigning // grace period and rotation schedule
+	clock   clock.Clock         // source of the current time
+
+	mu     sync.Mutex           // serializes loads, so the latest read is applied last
+	loaded []*models.SigningKey // keys in use, signing key first, without private keys
+}
+
+// New creates a keyring using the configured key until Load reads rotated keys.
+//
+// Parameters:
+//   - log: logger instance for structured logging
+//   - storage: storage of rotated keys
+//   - bus: cache bus making every replica reload rotated keys
+//   - keys: keys tokens are signed with and verified against, holding the configured key;
+//     nil if none is configured, in which case keys cannot be rotated
+//   - cfg: grace period and rotation schedule
+//   - clk: source of the current time
+//
+// Returns a new *Keyring instance ready to use.
+func New(log *slog.Logger, storage Storage, bus CacheBus, keys *jwt.Keys, cfg config.TokenSigning, clk clock.Clock) *Keyring {
+	return &Keyring{
+		log:     log,
+		storage: storage,
+		bus:     bus,
+		keys:    keys,
+		cfg:     cfg,
+		clock:   clk,
+	}
+}
+
+// Load reads the keys in use from storage: tokens are signed with the active key and
+// verified against it and the keys retired within the grace period. Until a key is rotated,
+// the configured key stays in use.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - error: non-nil if the keys cannot be read or parsed, in which case the previous ones are kept
+func (k *Keyring) Load(ctx context.Context) error {
+	const op = "keyring.Keyring.Load"
+
+	if k.keys == nil {
+		return nil
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	stored, err := k.storage.SigningKeys(ctx, k.clock.Now().Add(-k.cfg.GracePeriod))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(stored) == 0 || !stored[0].Active() {
+		configured, err := k.keys.SigningKey()
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		k.loaded = []*models.SigningKey{configured}
+
+		return nil
+	}
+
+	if err := k.keys.Replace(stored[0], stored[1:]); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, key := range stored {
+		key.PrivateKey = nil
+
+		if !key.Active() {
+			key.ExpiresAt = key.RetiredAt.Add(k.cfg.GracePeriod)
+		}
+	}
+
+	k.loaded = stored
+
+	return nil
+}
+
+// Invalidate reloads the keys after they were rotated on this or another replica.
+// It is subscribed to the signing keys topic of the cache bus, which has no key.
+func (k *Keyring) Invalidate(string) {
+	const op = "keyring.Keyring.Invalidate"
+
+	ctx, cancel := context.WithTimeout(context.Background(), reloadTimeout)
+	defer cancel()
+
+	if err := k.Load(ctx); err != nil {
+		k.log.Error("failed to reload signing keys", slog.String("op", op), slog.String("error", err.Error()))
+	}
+}
+
+// Keys returns the keys in use, the signing key first, without their private keys.
+// It returns nil when no key is configured.
+func (k *Keyring) Keys() []*models.SigningKey {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	keys := make([]*models.SigningKey, 0, len(k.loaded))
+	for _, key := range k.loaded {
+		copied := *key
+		keys = append(keys, &copied)
+	}
+
+	return keys
+}
+
+// Rotate generates a new key to sign tokens with and retires the current one, which keeps
+// verifying tokens for the grace period. Every replica then reloads the keys.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - *models.SigningKey: the new signing key, without its private key
+//   - error: nil on success, or an error if the key cannot be rotated
+//
+// Possible errors:
+//   - jwt.ErrNoSigningKey: if no key is configured
+//   - ErrRotationConflict: if another replica rotated the key meanwhile; the keys are then reloaded
+func (k *Keyring) Rotate(ctx context.Context) (*models.SigningKey, error) {
+	const op = "keyring.Keyring.Rotate"
+
+	log := k.log.With(
+		slog.String("op", op),
+	)
+
+	if k.keys == nil {
+		return nil, fmt.Errorf("%s: %w", op, jwt.ErrNoSigningKey)
+	}
+
+	current, err := k.keys.SigningKey()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	next, err := jwt.GenerateSigningKey()
+	if err != nil {
+		log.Error("failed to generate signing key", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	next.CreatedAt = k.clock.Now()
+
+	if err := k.storage.RotateSigningKey(ctx, current, next, next.CreatedAt); err != nil {
+		if errors.Is(err, storage.ErrVersionConflict) {
+			log.Warn("signing key rotated concurrently", slog.String("kid", current.ID))
+
+			if err := k.Load(ctx); err != nil {
+				log.Error("failed to reload signing keys", slog.String("error", err.Error()))
+			}
+
+			return nil, fmt.Errorf("%s: %w", op, ErrRotationConflict)
+		}
+
+		log.Error("failed to store signing key", slog.String("error", err.Error()))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The keys are reloaded on this replica even if the others cannot be told; they pick
+	// the new key up at their next check.
+	if err := k.bus.Publish(ctx, cachebus.TopicSigningKeys, ""); err != nil {
+		log.Warn("failed to propagate signing key", slog.String("error", err.Error()))
+	}
+
+	log.Info("signing key rotated", slog.String("kid", next.ID), slog.String("previous_kid", current.ID))
+
+	next.PrivateKey = nil
+
+	return next, nil
+}
+
+// Maintain reloads the keys, so keys past their grace period stop verifying tokens and
+// rotations missed by the cache bus are picked up, removes keys past their grace period,
+// and rotates the signing key once it is older than the rotation interval. The configured
+// key, whose age is unknown, is rotated at the first check.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - error: non-nil if a step fails
+func (k *Keyring) Maintain(ctx context.Context) error {
+	const op = "keyring.Keyring.Maintain"
+
+	if err := k.Load(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := k.clock.Now()
+
+	if _, err := k.storage.DeleteSigningKeys(ctx, now.Add(-k.cfg.GracePeriod)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if k.cfg.RotationInterval <= 0 {
+		return nil
+	}
+
+	keys := k.Keys()
+	if len(keys) > 0 && !keys[0].CreatedAt.IsZero() && now.Sub(keys[0].CreatedAt) < k.cfg.RotationInterval {
+		return nil
+	}
+
+	if _, err := k.Rotate(ctx); err != nil && !errors.Is(err, ErrRotationConflict) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
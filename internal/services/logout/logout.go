@@ -0,0 +1,191 @@
+// Package logout tells apps that a user's tokens were revoked, by POSTing logout tokens
+// to their back-channel logout URIs as in OpenID Connect Back-Channel Logout 1.0.
+//
+// Deliveries are queued in the outbox by storage, in the same transaction as the
+// revocation, and handed to the Notifier by the outbox dispatcher, which retries them.
+package logout
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/clock"
+	"github.com/kirinyoku/sso-grpc/internal/lib/jwt"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+)
+
+// Storage defines the interface used to read the apps and users named by deliveries.
+type Storage interface {
+	// App retrieves an app by its ID.
+	App(ctx context.Context, appID int32) (*models.App, error)
+
+	// AppFamily retrieves an app family along with the IDs of its apps.
+	AppFamily(ctx context.Context, familyID int32) (*models.AppFamily, error)
+
+	// UserByID retrieves a user by their ID.
+	UserByID(ctx context.Context, userID int64) (*models.User, error)
+}
+
+// delivery is a logout token to POST to an app.
+type delivery struct {
+	app  *models.App  // app to notify, with its family loaded
+	user *models.User // user whose tokens were revoked
+	jti  string       // ID of the logout token, the same for every attempt
+}
+
+// Notifier delivers logout tokens, safe for concurrent use.
+type Notifier struct {
+	log     *slog.Logger             // logger for structured logging
+	storage Storage                  // storage the apps and users are read from
+	cfg     config.BackchannelLogout // token settings and retries
+	clock   clock.Clock              // source of the time logout tokens are issued at
+	client  *http.Client             // client POSTing logout tokens
+}
+
+// New creates a new Notifier. Register Deliver with the outbox dispatcher for
+// models.TopicBackchannelLogout to start delivering logout tokens.
+//
+// Parameters:
+//   - log: logger instance for structured logging
+//   - storage: storage the apps and users are read from
+//   - cfg: token settings and retries; 0 attempts disables back-channel logout
+//   - clk: source of the time logout tokens are issued at
+//
+// Returns a new *Notifier instance ready to use.
+func New(log *slog.Logger, storage Storage, cfg config.BackchannelLogout, clk clock.Clock) *Notifier {
+	return &Notifier{
+		log:     log,
+		storage: storage,
+		cfg:     cfg,
+		clock:   clk,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			// Apps must answer at their registered URI; following a redirect would send the token elsewhere.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Deliver POSTs the logout token of a models.TopicBackchannelLogout outbox message to its
+// app. Apps whose back-channel logout URI was cleared or that were deleted since the
+// message was queued are skipped, and so are users that no longer exist. Messages are
+// discarded while back-channel logout is disabled.
+//
+// The token's jti is derived from the message, so apps can ignore the same logout
+// delivered twice, e.g. when the process stopped before recording the delivery.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - msg: outbox message whose payload is a models.BackchannelLogout
+//
+// Returns:
+//   - error: nil if the token was delivered or the message was skipped, or the error
+//     of the failed delivery, in which case the dispatcher retries it
+func (n *Notifier) Deliver(ctx context.Context, msg *models.OutboxMessage) error {
+	const op = "logout.Notifier.Deliver"
+
+	if n.cfg.Attempts == 0 {
+		return nil
+	}
+
+	var payload models.BackchannelLogout
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return fmt.Errorf("%s: decode payload: %w", op, err)
+	}
+
+	log := n.log.With(
+		slog.String("op", op),
+		slog.Int("app_id", int(payload.AppID)),
+		slog.Int64("user_id", payload.UserID),
+	)
+
+	app, err := n.storage.App(ctx, payload.AppID)
+	if err != nil {
+		if errors.Is(err, storage.ErrAppNotFound) {
+			log.Info("app was deleted, logout token not sent")
+
+			return nil
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if app.BackchannelLogoutURI == "" {
+		log.Info("back-channel logout was turned off, logout token not sent")
+
+		return nil
+	}
+
+	if app.FamilyID != 0 {
+		app.Family, err = n.storage.AppFamily(ctx, app.FamilyID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	user, err := n.storage.UserByID(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found, logout token not sent")
+
+			return nil
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	jti := fmt.Sprintf("%d-%d", msg.CreatedAt.Unix(), msg.ID)
+
+	if err := n.post(ctx, delivery{app: app, user: user, jti: jti}); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("logout token delivered", slog.Int("attempt", msg.Attempts))
+
+	return nil
+}
+
+// post POSTs a newly issued logout token to the app's back-channel logout URI.
+// Tokens are issued for every attempt, so retries do not send expired ones.
+func (n *Notifier) post(ctx context.Context, d delivery) error {
+	token, err := jwt.NewLogoutToken(d.user, d.app, n.cfg.Issuer, n.clock.Now(), n.cfg.TokenTTL, d.jti)
+	if err != nil {
+		return fmt.Errorf("generate logout token: %w", err)
+	}
+
+	body := url.Values{"logout_token": {token}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.app.BackchannelLogoutURI, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
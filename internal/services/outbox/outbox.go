@@ -0,0 +1,186 @@
+// Package outbox delivers the messages storage queues in the outbox table in the same
+// transaction as the changes they report, such as back-channel logouts. A message is
+// deleted only once its handler succeeds, so no message is lost if the process stops;
+// it is delivered again instead, and handlers must tolerate duplicates.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/clock"
+)
+
+// Storage defines the interface used to claim and settle outbox messages.
+type Storage interface {
+	// ClaimOutboxMessages claims the messages due for delivery, hiding them from other claims for lease.
+	ClaimOutboxMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error)
+
+	// DeleteOutboxMessage deletes a delivered message.
+	DeleteOutboxMessage(ctx context.Context, id int64) error
+
+	// RetryOutboxMessage records a failed delivery and when to retry it.
+	RetryOutboxMessage(ctx context.Context, id int64, nextAttemptAt time.Time, lastErr string) error
+
+	// FailOutboxMessage gives up delivering a message, keeping it for inspection.
+	FailOutboxMessage(ctx context.Context, id int64, at time.Time, lastErr string) error
+}
+
+// Handler delivers a message. A nil error settles it; otherwise it is retried.
+type Handler func(ctx context.Context, msg *models.OutboxMessage) error
+
+// route is the handler of a topic and its retry policy.
+type route struct {
+	handler  Handler
+	attempts int           // deliveries made before a message is given up
+	backoff  time.Duration // delay before the first retry, doubled for each following one
+}
+
+// Dispatcher claims due outbox messages and hands them to the handler of their topic.
+type Dispatcher struct {
+	log     *slog.Logger     // logger for structured logging
+	storage Storage          // storage the messages are claimed from
+	cfg     config.Outbox    // batching, lease, and concurrency settings
+	clock   clock.Clock      // source of the current time
+	routes  map[string]route // handlers by topic
+}
+
+// New creates a new Dispatcher. Register handlers with Handle, then call Dispatch.
+//
+// Parameters:
+//   - log: logger instance for structured logging
+//   - storage: storage the messages are claimed from
+//   - cfg: batching, lease, and concurrency settings
+//   - clk: source of the current time
+//
+// Returns a new *Dispatcher instance without handlers.
+func New(log *slog.Logger, storage Storage, cfg config.Outbox, clk clock.Clock) *Dispatcher {
+	return &Dispatcher{
+		log:     log,
+		storage: storage,
+		cfg:     cfg,
+		clock:   clk,
+		routes:  make(map[string]route),
+	}
+}
+
+// Handle registers the handler of a topic. It must be called before Dispatch.
+//
+// Parameters:
+//   - topic: topic of the messages, e.g. models.TopicBackchannelLogout
+//   - handler: delivers a message of the topic
+//   - attempts: deliveries made before a message is given up
+//   - backoff: delay before the first retry, doubled for each following one
+func (d *Dispatcher) Handle(topic string, handler Handler, attempts int, backoff time.Duration) {
+	d.routes[topic] = route{handler: handler, attempts: attempts, backoff: backoff}
+}
+
+// Dispatch delivers batches of due messages until none are left or ctx is canceled. It runs
+// as the outbox job every poll interval. Messages being delivered when ctx is canceled are
+// delivered again once their lease ends.
+//
+// Returns:
+//   - error: nil if every due message was handed to its handler, or an error if messages
+//     cannot be claimed; failed deliveries are retried rather than reported
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	const op = "outbox.Dispatcher.Dispatch"
+
+	for ctx.Err() == nil {
+		messages, err := d.storage.ClaimOutboxMessages(ctx, d.clock.Now(), d.cfg.Lease, d.cfg.BatchSize)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		var wg sync.WaitGroup
+
+		sem := make(chan struct{}, d.cfg.Workers)
+
+		for i := range messages {
+			sem <- struct{}{}
+
+			wg.Add(1)
+
+			go func(msg *models.OutboxMessage) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+
+				d.deliver(ctx, msg)
+			}(&messages[i])
+		}
+
+		wg.Wait()
+
+		if len(messages) < d.cfg.BatchSize {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// deliver hands a claimed message to its handler, then deletes it, schedules a retry,
+// or gives it up once its attempts are exhausted.
+func (d *Dispatcher) deliver(ctx context.Context, msg *models.OutboxMessage) {
+	const op = "outbox.Dispatcher.deliver"
+
+	log := d.log.With(
+		slog.String("op", op),
+		slog.Int64("message_id", msg.ID),
+		slog.String("topic", msg.Topic),
+		slog.Int("attempt", msg.Attempts),
+	)
+
+	r, ok := d.routes[msg.Topic]
+	if !ok {
+		log.Error("no handler for outbox message, giving up")
+
+		if err := d.storage.FailOutboxMessage(ctx, msg.ID, d.clock.Now(), "no handler for topic"); err != nil {
+			log.Error("failed to give up outbox message", slog.String("error", err.Error()))
+		}
+
+		return
+	}
+
+	err := r.handler(ctx, msg)
+	if err == nil {
+		if err := d.storage.DeleteOutboxMessage(ctx, msg.ID); err != nil {
+			// The message is delivered again once its lease ends.
+			log.Error("failed to delete delivered outbox message", slog.String("error", err.Error()))
+		}
+
+		return
+	}
+
+	// Stopping interrupted the delivery; the lease brings the message back.
+	if ctx.Err() != nil {
+		return
+	}
+
+	if msg.Attempts >= r.attempts {
+		log.Error("outbox message not delivered, giving up", slog.String("error", err.Error()))
+
+		if err := d.storage.FailOutboxMessage(ctx, msg.ID, d.clock.Now(), err.Error()); err != nil {
+			log.Error("failed to give up outbox message", slog.String("error", err.Error()))
+		}
+
+		return
+	}
+
+	backoff := r.backoff << (msg.Attempts - 1)
+
+	log.Warn("failed to deliver outbox message, retrying",
+		slog.Duration("backoff", backoff),
+		slog.String("error", err.Error()),
+	)
+
+	if err := d.storage.RetryOutboxMessage(ctx, msg.ID, d.clock.Now().Add(backoff), err.Error()); err != nil {
+		log.Error("failed to schedule outbox message retry", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,275 @@
+// Package slo tracks the service level objectives of gRPC methods. Calls are counted in
+// buckets of a rolling window, from which the share of the error budget left and the rate
+// at which it burns are computed and published as metrics.
+//
+// A burn rate of 1 spends exactly the error budget over the window; a burn rate of 14.4
+// spends a 30-day budget in two days. A warning is logged while the burn rates of both the
+// window and the recent alert window exceed the alert threshold, as in the multiwindow
+// alerts of the Google SRE workbook.
+package slo
+
+import (
+	"context"
+	"expvar"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/lib/clock"
+	"google.golang.org/grpc/codes"
+)
+
+// bucketsPerAlertWindow is the number of buckets the alert window is split into. The
+// burn rates of a window lag by up to one bucket.
+const bucketsPerAlertWindow = 5
+
+// Kinds of objectives, the suffixes of the names their metrics are published under.
+const (
+	kindAvailability = "availability"
+	kindLatency      = "latency"
+)
+
+// Gauges of the objectives, by objective name, e.g. "/auth.Auth/Login:availability".
+var (
+	burnRates        = expvar.NewMap("slo_burn_rate")              // by objective, then "window" and "alert_window"
+	budgetsRemaining = expvar.NewMap("slo_error_budget_remaining") // share of the window's budget left; negative once overspent
+)
+
+// serverErrors are the codes counted against availability objectives. Other codes report
+// problems with the call, such as invalid arguments or missing credentials.
+var serverErrors = map[codes.Code]bool{
+	codes.Unknown:          true,
+	codes.DeadlineExceeded: true,
+	codes.Internal:         true,
+	codes.Unavailable:      true,
+	codes.DataLoss:         true,
+}
+
+// Tracker counts the calls of the methods having objectives and checks their error budgets.
+type Tracker struct {
+	log   *slog.Logger // logger for structured logging
+	cfg   config.SLO   // windows and alert threshold
+	clock clock.Clock  // source of the current time
+
+	bucketWidth  time.Duration      // time covered by a bucket
+	alertBuckets int64              // buckets in the alert window
+	methods      map[string]*method // methods by full name; not modified after New
+}
+
+// method holds the objectives of a method and the counts of its recent calls.
+type method struct {
+	objective config.Objective
+	gauges    map[string]*gauges // gauges by objective kind
+
+	mu      sync.Mutex
+	buckets []bucket // ring of the buckets of the window, indexed by number
+}
+
+// bucket counts the calls made in one bucket width.
+type bucket struct {
+	number int64 // intervals of the bucket width since the Unix epoch; tells stale buckets apart
+	calls  int64
+	failed int64 // calls failed with a server error
+	slow   int64 // calls slower than the latency objective
+}
+
+// gauges are the published metrics of an objective.
+type gauges struct {
+	burnRate      *expvar.Float // over the window
+	burnRateAlert *expvar.Float // over the alert window
+	remaining     *expvar.Float
+}
+
+// New creates a new Tracker.
+//
+// Parameters:
+//   - log: logger instance for structured logging
+//   - cfg: objectives, windows, and alert threshold; the configuration must be valid
+//   - clk: source of the current time
+//
+// Returns a new *Tracker instance ready to use.
+func New(log *slog.Logger, cfg config.SLO, clk clock.Clock) *Tracker {
+	t := &Tracker{
+		log:     log,
+		cfg:     cfg,
+		clock:   clk,
+		methods: make(map[string]*method, len(cfg.Objectives)),
+	}
+
+	if len(cfg.Objectives) == 0 {
+		return t
+	}
+
+	t.bucketWidth = max(cfg.AlertWindow/bucketsPerAlertWindow, time.Second)
+	t.alertBuckets = int64((cfg.AlertWindow + t.bucketWidth - 1) / t.bucketWidth)
+	windowBuckets := (cfg.Window + t.bucketWidth - 1) / t.bucketWidth
+
+	for _, objective := range cfg.Objectives {
+		m := &method{
+			objective: objective,
+			gauges:    make(map[string]*gauges),
+			buckets:   make([]bucket, windowBuckets),
+		}
+
+		if objective.Availability > 0 {
+			m.gauges[kindAvailability] = newGauges(objective.Method + ":" + kindAvailability)
+		}
+
+		if objective.LatencyTarget > 0 {
+			m.gauges[kindLatency] = newGauges(objective.Method + ":" + kindLatency)
+		}
+
+		t.methods[objective.Method] = m
+	}
+
+	return t
+}
+
+// newGauges publishes the gauges of an objective, starting with its whole budget left.
+func newGauges(name string) *gauges {
+	g := &gauges{
+		burnRate:      new(expvar.Float),
+		burnRateAlert: new(expvar.Float),
+		remaining:     new(expvar.Float),
+	}
+
+	g.remaining.Set(1)
+
+	rates := new(expvar.Map)
+	rates.Set("window", g.burnRate)
+	rates.Set("alert_window", g.burnRateAlert)
+
+	burnRates.Set(name, rates)
+	budgetsRemaining.Set(name, g.remaining)
+
+	return g
+}
+
+// Record counts a call of a method. Calls of methods without objectives are ignored.
+//
+// Parameters:
+//   - fullMethod: full gRPC method name, e.g. "/auth.Auth/Login"
+//   - duration: time the call took
+//   - code: status code the call returned
+func (t *Tracker) Record(fullMethod string, duration time.Duration, code codes.Code) {
+	m, ok := t.methods[fullMethod]
+	if !ok {
+		return
+	}
+
+	number := t.clock.Now().UnixNano() / int64(t.bucketWidth)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b := &m.buckets[number%int64(len(m.buckets))]
+	if b.number != number {
+		*b = bucket{number: number}
+	}
+
+	b.calls++
+
+	if serverErrors[code] {
+		b.failed++
+	}
+
+	if m.objective.LatencyTarget > 0 && duration > m.objective.Latency {
+		b.slow++
+	}
+}
+
+// Check computes the burn rates and remaining error budgets of every objective, publishes
+// them, and logs a warning for the objectives whose budget burns too fast. It never fails;
+// the error is only returned to run as a job.
+func (t *Tracker) Check(context.Context) error {
+	const op = "slo.Tracker.Check"
+
+	log := t.log.With(
+		slog.String("op", op),
+	)
+
+	number := t.clock.Now().UnixNano() / int64(t.bucketWidth)
+
+	for name, m := range t.methods {
+		window, alert := m.sums(number, t.alertBuckets)
+
+		objectives := []struct {
+			kind        string
+			target      float64
+			bad, badAlt int64 // calls missing the objective in the window and in the alert window
+		}{
+			{kindAvailability, m.objective.Availability, window.failed, alert.failed},
+			{kindLatency, m.objective.LatencyTarget, window.slow, alert.slow},
+		}
+
+		for _, o := range objectives {
+			g, ok := m.gauges[o.kind]
+			if !ok {
+				continue
+			}
+
+			rate := burnRate(o.bad, window.calls, o.target)
+			rateAlert := burnRate(o.badAlt, alert.calls, o.target)
+
+			g.burnRate.Set(rate)
+			g.burnRateAlert.Set(rateAlert)
+			// Over a window, a burn rate of 1 spends exactly the budget.
+			g.remaining.Set(1 - rate)
+
+			if rate > t.cfg.BurnRateAlert && rateAlert > t.cfg.BurnRateAlert {
+				log.Warn("error budget burning too fast",
+					slog.String("method", name),
+					slog.String("objective", o.kind),
+					slog.Float64("target", o.target),
+					slog.Float64("burn_rate", rate),
+					slog.Float64("burn_rate_alert_window", rateAlert),
+					slog.Float64("budget_remaining", 1-rate),
+					slog.Int64("calls", window.calls),
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sums adds up the buckets of the window and of its last alertBuckets buckets, both
+// ending with the bucket numbered current.
+func (m *method) sums(current int64, alertBuckets int64) (window bucket, alert bucket) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := int64(len(m.buckets))
+
+	for i := range n {
+		number := current - i
+
+		b := m.buckets[number%n]
+		if b.number != number {
+			continue
+		}
+
+		window.calls += b.calls
+		window.failed += b.failed
+		window.slow += b.slow
+
+		if i < alertBuckets {
+			alert.calls += b.calls
+			alert.failed += b.failed
+			alert.slow += b.slow
+		}
+	}
+
+	return window, alert
+}
+
+// burnRate returns how many times faster than allowed by target the budget is spent when
+// bad of calls calls miss the objective, or 0 if there were no calls.
+func burnRate(bad, calls int64, target float64) float64 {
+	if calls == 0 {
+		return 0
+	}
+
+	return float64(bad) / float64(calls) / (1 - target)
+}
@@ -0,0 +1,395 @@
+// Package snapshot backs up the database for disaster recovery. Every run writes a consistent
+// copy of the database, encrypts it, uploads it to object storage, and deletes the snapshots
+// past their retention, so a deployment on a single host can be restored after losing it.
+//
+// Snapshots are named after the time they were taken, as in sso-20240131T020000Z.db.enc. They
+// hold a version byte, a random salt, and the database sealed with AES-256-GCM in chunks, under
+// a key derived from the snapshot key and the salt. Every chunk is bound to its position, to
+// whether it is the last one, and to the object key the snapshot was uploaded under, so chunks
+// cannot be reordered or dropped, and a snapshot cannot stand in for another, such as an older
+// one or one of another environment. Decrypt restores the database file, which the service
+// runs on once it is put at the storage path.
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/lib/clock"
+	"github.com/kirinyoku/sso-grpc/internal/lib/s3"
+)
+
+// version is the format version prepended to sealed snapshots.
+const version = 2
+
+// Sealed snapshots are a version byte, a salt of saltSize random bytes, and the database in
+// chunks of chunkSize bytes, each followed by its tag. The last chunk is shorter, and empty
+// if the size of the database is a multiple of chunkSize.
+const (
+	saltSize  = 32
+	chunkSize = 64 << 10
+	tagSize   = 16
+)
+
+// timeLayout formats the time a snapshot was taken in its name.
+const timeLayout = "20060102T150405Z"
+
+// Names of snapshots are namePrefix, the time they were taken, and nameSuffix.
+const (
+	namePrefix = "sso-"
+	nameSuffix = ".db.enc"
+)
+
+// ErrInvalidKey is returned for keys that are not base64-encoded 32-byte keys.
+var ErrInvalidKey = errors.New("snapshot key must be a base64-encoded 32-byte key")
+
+// ErrInvalidSnapshot is returned when a snapshot cannot be decrypted with the key, or was
+// uploaded under another object key.
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+var (
+	lastSuccessMetric = expvar.NewInt("snapshot_last_success") // Unix time of the last snapshot uploaded; 0 if none yet
+	lastSizeMetric    = expvar.NewInt("snapshot_last_size_bytes")
+	failuresMetric    = expvar.NewInt("snapshot_failures")
+)
+
+// Storage defines the interface used to copy the database.
+type Storage interface {
+	// Snapshot writes a consistent copy of the database to a new file.
+	Snapshot(ctx context.Context, path string) error
+}
+
+// ObjectStore defines the interface of the object storage snapshots are uploaded to.
+type ObjectStore interface {
+	// Put stores an object of size bytes read from body under a key.
+	Put(ctx context.Context, key string, body io.Reader, size int64) error
+
+	// List returns every object whose key starts with a prefix.
+	List(ctx context.Context, prefix string) ([]s3.Object, error)
+
+	// Delete removes the object stored under a key.
+	Delete(ctx context.Context, key string) error
+}
+
+// Uploader takes snapshots of the database and uploads them. Runs must not overlap,
+// which the job scheduler guarantees.
+type Uploader struct {
+	log     *slog.Logger     // logger for structured logging
+	storage Storage          // database to copy
+	store   ObjectStore      // object storage snapshots are uploaded to
+	key     []byte           // key snapshot keys are derived from
+	cfg     config.Snapshots // key prefix and retention
+	clock   clock.Clock      // source of the time snapshots are named after
+}
+
+// New creates a new Uploader. Register Run with the job scheduler to take snapshots on schedule.
+//
+// Parameters:
+//   - log: logger instance for structured logging
+//   - storage: database to copy
+//   - store: object storage snapshots are uploaded to
+//   - cfg: encryption key, key prefix, and retention
+//   - clk: source of the time snapshots are named after
+//
+// Returns:
+//   - *Uploader: uploader ready to use
+//   - error: ErrInvalidKey if the encryption key is invalid
+func New(log *slog.Logger, storage Storage, store ObjectStore, cfg config.Snapshots, clk clock.Clock) (*Uploader, error) {
+	const op = "snapshot.New"
+
+	key, err := decodeKey(cfg.Key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Uploader{
+		log:     log,
+		storage: storage,
+		store:   store,
+		key:     key,
+		cfg:     cfg,
+		clock:   clk,
+	}, nil
+}
+
+// Run takes a snapshot of the database, uploads it, and deletes the snapshots older than the
+// retention. The snapshot just uploaded is always kept, whatever the retention.
+//
+// Parameters:
+//   - ctx: context for cancellation of the run
+//
+// Returns:
+//   - error: non-nil if the snapshot cannot be taken or uploaded, or expired ones cannot be deleted
+func (u *Uploader) Run(ctx context.Context) error {
+	const op = "snapshot.Uploader.Run"
+
+	log := u.log.With(slog.String("op", op))
+
+	now := u.clock.Now().UTC()
+	key := u.cfg.Prefix + namePrefix + now.Format(timeLayout) + nameSuffix
+
+	size, err := u.upload(ctx, key)
+	if err != nil {
+		failuresMetric.Add(1)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	lastSuccessMetric.Set(now.Unix())
+	lastSizeMetric.Set(size)
+
+	log.Info("snapshot uploaded", slog.String("key", key), slog.Int64("size", size))
+
+	deleted, err := u.prune(ctx, key, now.Add(-u.cfg.Retention))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if deleted > 0 {
+		log.Info("expired snapshots deleted", slog.Int("deleted", deleted))
+	}
+
+	return nil
+}
+
+// upload takes a snapshot and uploads it under key, returning the size of the sealed snapshot.
+// The database is sealed while it is streamed to the store, a chunk at a time.
+func (u *Uploader) upload(ctx context.Context, key string) (int64, error) {
+	dir, err := os.MkdirTemp("", "sso-snapshot-")
+	if err != nil {
+		return 0, err
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "sso.db")
+
+	if err := u.storage.Snapshot(ctx, path); err != nil {
+		return 0, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	header := make([]byte, 1+saltSize)
+	header[0] = version
+
+	if _, err := rand.Read(header[1:]); err != nil {
+		return 0, err
+	}
+
+	aead, err := newAEAD(u.key, header[1:])
+	if err != nil {
+		return 0, err
+	}
+
+	chunks := info.Size()/chunkSize + 1
+	size := int64(len(header)) + info.Size() + chunks*tagSize
+
+	body := io.MultiReader(bytes.NewReader(header), &sealer{aead: aead, aad: []byte(key), src: file, remaining: info.Size()})
+
+	if err := u.store.Put(ctx, key, body, size); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+// sealer reads a database of a known size from src and returns its sealed chunks.
+type sealer struct {
+	aead      cipher.AEAD
+	aad       []byte    // object key the snapshot is uploaded under
+	src       io.Reader // database to seal
+	remaining int64     // bytes of src not sealed yet
+	index     uint64    // index of the next chunk
+	done      bool      // whether the last chunk was sealed
+	buf       []byte    // sealed chunk not read yet
+}
+
+// Read implements io.Reader.
+func (s *sealer) Read(p []byte) (int, error) {
+	if len(s.buf) == 0 {
+		if s.done {
+			return 0, io.EOF
+		}
+
+		n := min(s.remaining, chunkSize)
+		last := n < chunkSize
+
+		chunk := make([]byte, n, n+tagSize)
+		if _, err := io.ReadFull(s.src, chunk); err != nil {
+			return 0, err
+		}
+
+		s.buf = s.aead.Seal(chunk[:0], chunkNonce(s.index, last), chunk, s.aad)
+		s.remaining -= n
+		s.index++
+		s.done = last
+	}
+
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+
+	return n, nil
+}
+
+// prune deletes the snapshots taken before a moment, except the latest one. Objects under the
+// prefix that are not named like snapshots are left alone.
+func (u *Uploader) prune(ctx context.Context, latest string, before time.Time) (int, error) {
+	objects, err := u.store.List(ctx, u.cfg.Prefix+namePrefix)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+
+	for _, object := range objects {
+		stamp, ok := strings.CutSuffix(strings.TrimPrefix(object.Key, u.cfg.Prefix+namePrefix), nameSuffix)
+
+		takenAt, err := time.Parse(timeLayout, stamp)
+		if !ok || err != nil || object.Key == latest || !takenAt.Before(before) {
+			continue
+		}
+
+		if err := u.store.Delete(ctx, object.Key); err != nil {
+			return deleted, err
+		}
+
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// Decrypt restores the database file from a snapshot, a chunk at a time. Chunks are written
+// only once they are authenticated, but a snapshot that is cut short is only detected at its
+// end, so discard what was written if it fails.
+//
+// Parameters:
+//   - key: base64-encoded 32-byte key the snapshot was encrypted with
+//   - object: object key the snapshot was uploaded under, e.g. sso/sso-20240131T020000Z.db.enc
+//   - sealed: contents of the snapshot object
+//   - db: destination of the database file
+//
+// Returns:
+//   - error: ErrInvalidKey if the key is invalid, ErrInvalidSnapshot if the snapshot is
+//     corrupted, was encrypted with another key, or was uploaded under another object key,
+//     or the error of reading sealed or writing db
+func Decrypt(key, object string, sealed io.Reader, db io.Writer) error {
+	const op = "snapshot.Decrypt"
+
+	raw, err := decodeKey(key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	header := make([]byte, 1+saltSize)
+	if _, err := io.ReadFull(sealed, header); err != nil || header[0] != version {
+		return fmt.Errorf("%s: %w", op, ErrInvalidSnapshot)
+	}
+
+	aead, err := newAEAD(raw, header[1:])
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// A chunk is the last one when it is short, so one byte is read past every chunk to
+	// tell a full last chunk from a full one followed by more.
+	buf := make([]byte, chunkSize+tagSize+1)
+
+	n, err := io.ReadFull(sealed, buf)
+
+	for index := uint64(0); ; index++ {
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		last := n <= chunkSize+tagSize
+
+		chunk := buf[:min(n, chunkSize+tagSize)]
+
+		data, openErr := aead.Open(nil, chunkNonce(index, last), chunk, []byte(object))
+		if openErr != nil {
+			return fmt.Errorf("%s: %w", op, ErrInvalidSnapshot)
+		}
+
+		if _, err := db.Write(data); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if last {
+			return nil
+		}
+
+		// The byte read past the chunk starts the next one.
+		buf[0] = buf[chunkSize+tagSize]
+
+		n, err = io.ReadFull(sealed, buf[1:])
+		n++
+	}
+}
+
+// chunkNonce returns the nonce of a chunk: its index, followed by a byte set for the last one.
+// Nonces repeat across snapshots, but each snapshot has its own key.
+func chunkNonce(index uint64, last bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce[3:11], index)
+
+	if last {
+		nonce[11] = 1
+	}
+
+	return nonce
+}
+
+// decodeKey decodes a base64-encoded 32-byte key.
+func decodeKey(key string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != 32 {
+		return nil, ErrInvalidKey
+	}
+
+	return raw, nil
+}
+
+// newAEAD creates the AES-256-GCM cipher of a snapshot, under a key derived from the snapshot
+// key and the salt of the snapshot.
+func newAEAD(key, salt []byte) (cipher.AEAD, error) {
+	derived, err := hkdf.Key(sha256.New, key, salt, "sso snapshot", 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(derived)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,163 @@
+// Package usage accounts for the authenticated requests made per app and user.
+//
+// Requests are counted in memory and periodically added to daily rollups in storage,
+// so accounting costs no storage query per request. Counts not flushed yet are lost
+// if the process dies.
+package usage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+)
+
+// key identifies the counts of an app and user during a UTC day.
+type key struct {
+	day    time.Time
+	appID  int32
+	userID int64
+}
+
+// Meter counts requests and flushes the counts to storage.
+type Meter struct {
+	log     *slog.Logger // logger for structured logging
+	storage Storage      // storage dependency for data persistence
+	cfg     config.Usage // flush interval and retention
+
+	mu      sync.Mutex
+	pending map[key]*models.Usage // counts not flushed yet
+
+	lastPrune time.Time // when old rollups were last removed; only used by Maintain, whose runs never overlap
+}
+
+// Storage defines the interface that must be implemented by any storage provider
+// used by the Meter.
+type Storage interface {
+	// AddUsage adds request counts to the daily usage of apps and users.
+	AddUsage(ctx context.Context, usage []models.Usage) error
+
+	// DeleteUsage removes the daily usage of days before a moment.
+	DeleteUsage(ctx context.Context, before time.Time) (int64, error)
+}
+
+// New creates a new Meter.
+//
+// Parameters:
+//   - log: logger instance for structured logging
+//   - storage: storage implementation for data persistence
+//   - cfg: flush interval and retention; a flush interval of 0 disables accounting
+//
+// Returns a new *Meter instance ready to use.
+func New(log *slog.Logger, storage Storage, cfg config.Usage) *Meter {
+	return &Meter{
+		log:     log,
+		storage: storage,
+		cfg:     cfg,
+		pending: make(map[key]*models.Usage),
+	}
+}
+
+// Record counts a request made for an app by a user. It does nothing if accounting is disabled.
+func (m *Meter) Record(appID int32, userID int64, failed bool) {
+	if m.cfg.FlushInterval <= 0 {
+		return
+	}
+
+	k := key{day: time.Now().UTC().Truncate(24 * time.Hour), appID: appID, userID: userID}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	u, ok := m.pending[k]
+	if !ok {
+		u = &models.Usage{Date: k.day, AppID: appID, UserID: userID}
+		m.pending[k] = u
+	}
+
+	u.Requests++
+	if failed {
+		u.Errors++
+	}
+}
+
+// Maintain flushes the counts and removes rollups older than the retention at most once a
+// day. It runs as the usage job every flush interval, and a last time when the service stops.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - error: non-nil if the counts cannot be stored or old rollups cannot be removed
+func (m *Meter) Maintain(ctx context.Context) error {
+	const op = "usage.Meter.Maintain"
+
+	if err := m.Flush(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if time.Since(m.lastPrune) < 24*time.Hour {
+		return nil
+	}
+
+	deleted, err := m.storage.DeleteUsage(ctx, time.Now().Add(-m.cfg.Retention))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if deleted > 0 {
+		m.log.Info("old usage deleted", slog.String("op", op), slog.Int64("deleted", deleted))
+	}
+
+	m.lastPrune = time.Now()
+
+	return nil
+}
+
+// Flush adds the counts recorded since the last flush to storage. If that fails,
+// the counts are kept for the next flush.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - error: non-nil if the counts cannot be stored
+func (m *Meter) Flush(ctx context.Context) error {
+	const op = "usage.Meter.Flush"
+
+	m.mu.Lock()
+	pending := m.pending
+	m.pending = make(map[key]*models.Usage)
+	m.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	usage := make([]models.Usage, 0, len(pending))
+	for _, u := range pending {
+		usage = append(usage, *u)
+	}
+
+	if err := m.storage.AddUsage(ctx, usage); err != nil {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		for k, u := range pending {
+			if cur, ok := m.pending[k]; ok {
+				cur.Requests += u.Requests
+				cur.Errors += u.Errors
+			} else {
+				m.pending[k] = u
+			}
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
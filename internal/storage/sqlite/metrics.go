@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"expvar"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Types of storage errors, the keys of the error counters.
+const (
+	errorTypeNotFound   = "not_found"  // the record looked up does not exist
+	errorTypeConstraint = "constraint" // a unique, foreign key, or check constraint was violated
+	errorTypeTimeout    = "timeout"    // the call's deadline passed, or a lock was not released in time
+	errorTypeCanceled   = "canceled"   // the caller gave up on the call
+	errorTypeConnection = "connection" // the database file cannot be opened, read, or written
+	errorTypeOther      = "other"
+)
+
+// Counters of storage errors, served by the metrics endpoint. They are kept apart from the
+// gRPC status codes, which map e.g. both missing records and failed lookups to other codes.
+var (
+	errorsByType   = expvar.NewMap("storage_errors")           // by type, e.g. "constraint"
+	errorsByMethod = expvar.NewMap("storage_errors_by_method") // by Storage method, then by type
+
+	errorsByMethodMu sync.Mutex // serializes adding the counters of a method to errorsByMethod
+)
+
+// storageMethodPrefix starts the names of the Storage methods in stack traces.
+var storageMethodPrefix = reflect.TypeOf(Storage{}).PkgPath() + ".(*Storage)."
+
+// notFound counts a lookup of the calling Storage method that found nothing and returns err,
+// one of the not-found errors of the storage package.
+func notFound(err error) error {
+	countError(errorTypeNotFound)
+
+	return err
+}
+
+// countDriverError counts an error returned by the SQLite driver for the calling Storage method.
+func countDriverError(err error) {
+	countError(driverErrorType(err))
+}
+
+// countError counts an error of a type for the innermost Storage method on the stack,
+// or for "unknown" if the error did not occur in one.
+func countError(errorType string) {
+	method := "unknown"
+
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	for {
+		frame, more := frames.Next()
+
+		if name, ok := strings.CutPrefix(frame.Function, storageMethodPrefix); ok {
+			method = name
+
+			break
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	errorsByType.Add(errorType, 1)
+
+	methodErrors(method).Add(errorType, 1)
+}
+
+// methodErrors returns the error counters of a Storage method, adding them on its first error.
+func methodErrors(method string) *expvar.Map {
+	if counts, ok := errorsByMethod.Get(method).(*expvar.Map); ok {
+		return counts
+	}
+
+	errorsByMethodMu.Lock()
+	defer errorsByMethodMu.Unlock()
+
+	if counts, ok := errorsByMethod.Get(method).(*expvar.Map); ok {
+		return counts
+	}
+
+	counts := new(expvar.Map)
+	errorsByMethod.Set(method, counts)
+
+	return counts
+}
+
+// driverErrorType classifies an error returned by the SQLite driver.
+func driverErrorType(err error) string {
+	var sqliteErr sqlite3.Error
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return errorTypeTimeout
+	case errors.Is(err, context.Canceled):
+		return errorTypeCanceled
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		return errorTypeConnection
+	case errors.As(err, &sqliteErr):
+		switch sqliteErr.Code {
+		case sqlite3.ErrConstraint:
+			return errorTypeConstraint
+		case sqlite3.ErrBusy, sqlite3.ErrLocked:
+			// SQLite gave up waiting for another connection's lock after the busy timeout.
+			return errorTypeTimeout
+		case sqlite3.ErrCantOpen, sqlite3.ErrIoErr, sqlite3.ErrNotADB, sqlite3.ErrPerm, sqlite3.ErrReadonly:
+			return errorTypeConnection
+		}
+	}
+
+	return errorTypeOther
+}
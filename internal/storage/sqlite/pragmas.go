@@ -0,0 +1,162 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+)
+
+// journalModes lists the values accepted for the journal_mode setting.
+var journalModes = []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+
+// synchronousModes lists the values accepted for the synchronous setting,
+// indexed by the number PRAGMA synchronous reports for them.
+var synchronousModes = []string{"OFF", "NORMAL", "FULL", "EXTRA"}
+
+// foreignKeysEnforced reports whether cfg enforces foreign key constraints. Unlike the
+// driver, the storage enforces them unless told otherwise, since its schema relies on
+// ON DELETE CASCADE to remove the rows of deleted apps.
+func foreignKeysEnforced(cfg config.SQLite) bool {
+	return cfg.ForeignKeys == nil || *cfg.ForeignKeys
+}
+
+// dataSourceName returns the DSN opening the database at path with the settings of cfg.
+// The driver applies them to every connection it opens, so the whole pool shares them.
+func dataSourceName(path string, cfg config.SQLite) (string, error) {
+	params := url.Values{}
+
+	if cfg.JournalMode != "" {
+		mode := strings.ToUpper(cfg.JournalMode)
+		if !slices.Contains(journalModes, mode) {
+			return "", fmt.Errorf("invalid journal_mode %q, expecting one of %s", cfg.JournalMode, strings.Join(journalModes, ", "))
+		}
+
+		params.Set("_journal_mode", mode)
+	}
+
+	if cfg.Synchronous != "" {
+		mode := strings.ToUpper(cfg.Synchronous)
+		if !slices.Contains(synchronousModes, mode) {
+			return "", fmt.Errorf("invalid synchronous %q, expecting one of %s", cfg.Synchronous, strings.Join(synchronousModes, ", "))
+		}
+
+		params.Set("_synchronous", mode)
+	}
+
+	if cfg.CacheSize != 0 {
+		params.Set("_cache_size", strconv.Itoa(cfg.CacheSize))
+	}
+
+	params.Set("_foreign_keys", strconv.FormatBool(foreignKeysEnforced(cfg)))
+
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+
+	return path + sep + params.Encode(), nil
+}
+
+// verifyPragmas reads back the settings of a connection and logs them. It fails if SQLite
+// did not apply a configured one, e.g. WAL for a database that only lives in memory.
+func (s *Storage) verifyPragmas(ctx context.Context, log *slog.Logger, cfg config.SQLite) error {
+	var (
+		journalMode string
+		synchronous int
+		cacheSize   int
+		foreignKeys bool
+	)
+
+	// Every setting is read on the same connection, so they describe a single one.
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	for pragma, dest := range map[string]any{
+		"journal_mode": &journalMode,
+		"synchronous":  &synchronous,
+		"cache_size":   &cacheSize,
+		"foreign_keys": &foreignKeys,
+	} {
+		if err := conn.QueryRowContext(ctx, "PRAGMA "+pragma).Scan(dest); err != nil {
+			return fmt.Errorf("read %s: %w", pragma, err)
+		}
+	}
+
+	synchronousMode := strconv.Itoa(synchronous)
+	if synchronous >= 0 && synchronous < len(synchronousModes) {
+		synchronousMode = synchronousModes[synchronous]
+	}
+
+	log.Info("sqlite settings",
+		slog.String("journal_mode", strings.ToUpper(journalMode)),
+		slog.String("synchronous", synchronousMode),
+		slog.Int("cache_size", cacheSize),
+		slog.Bool("foreign_keys", foreignKeys),
+	)
+
+	if cfg.JournalMode != "" && !strings.EqualFold(journalMode, cfg.JournalMode) {
+		return fmt.Errorf("journal_mode is %s, not %s as configured", strings.ToUpper(journalMode), strings.ToUpper(cfg.JournalMode))
+	}
+
+	if cfg.Synchronous != "" && !strings.EqualFold(synchronousMode, cfg.Synchronous) {
+		return fmt.Errorf("synchronous is %s, not %s as configured", synchronousMode, strings.ToUpper(cfg.Synchronous))
+	}
+
+	if cfg.CacheSize != 0 && cacheSize != cfg.CacheSize {
+		return fmt.Errorf("cache_size is %d, not %d as configured", cacheSize, cfg.CacheSize)
+	}
+
+	if foreignKeys != foreignKeysEnforced(cfg) {
+		return fmt.Errorf("foreign_keys is %t, not %t as configured", foreignKeys, foreignKeysEnforced(cfg))
+	}
+
+	if !foreignKeys {
+		log.Warn("foreign keys are not enforced, so deleting an app leaves rows referencing it behind")
+
+		return nil
+	}
+
+	return s.checkForeignKeys(ctx, conn, log)
+}
+
+// checkForeignKeys logs the tables holding rows that reference missing rows. Enforcing
+// foreign keys keeps such rows from appearing, but not from having been written earlier
+// without enforcement.
+func (s *Storage) checkForeignKeys(ctx context.Context, conn *sql.Conn, log *slog.Logger) error {
+	rows, err := conn.QueryContext(ctx, "SELECT \"table\", COUNT(*) FROM pragma_foreign_key_check GROUP BY \"table\"")
+	if err != nil {
+		return fmt.Errorf("check foreign keys: %w", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			table string
+			count int
+		)
+
+		if err := rows.Scan(&table, &count); err != nil {
+			return fmt.Errorf("check foreign keys: %w", err)
+		}
+
+		log.Warn("orphaned rows reference missing rows", slog.String("table", table), slog.Int("count", count))
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("check foreign keys: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+)
+
+// slowQueries counts the queries that took longer than the slow query threshold.
+var slowQueries = expvar.NewInt("storage_slow_queries")
+
+// queryLogger logs the queries run through connections of a queryLogConnector.
+type queryLogger struct {
+	log *slog.Logger
+	cfg config.QueryLog
+}
+
+// record logs a query that started at start and finished with err. Queries slower than
+// the threshold are logged at warn level and counted; others only if logging is enabled.
+// Errors are counted by type.
+func (l *queryLogger) record(ctx context.Context, query string, args []driver.NamedValue, start time.Time, err error) {
+	duration := time.Since(start)
+
+	if err != nil {
+		countDriverError(err)
+	}
+
+	slow := l.cfg.SlowThreshold > 0 && duration >= l.cfg.SlowThreshold
+	if slow {
+		slowQueries.Add(1)
+	}
+
+	if !slow && !l.cfg.Enabled {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("query", strings.Join(strings.Fields(query), " ")),
+		slog.Any("args", redactArgs(args)),
+		slog.Duration("duration", duration),
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	if slow {
+		l.log.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs...)
+	} else {
+		l.log.LogAttrs(ctx, slog.LevelDebug, "query", attrs...)
+	}
+}
+
+// redactArgs describes query arguments by type and size only, since they carry
+// password hashes, token hashes, and personal data.
+func redactArgs(args []driver.NamedValue) []string {
+	res := make([]string, len(args))
+
+	for i, arg := range args {
+		switch v := arg.Value.(type) {
+		case nil:
+			res[i] = "NULL"
+		case string:
+			res[i] = fmt.Sprintf("string(%d)", len(v))
+		case []byte:
+			res[i] = fmt.Sprintf("[]byte(%d)", len(v))
+		default:
+			res[i] = fmt.Sprintf("%T", v)
+		}
+	}
+
+	return res
+}
+
+// queryLogConnector opens connections of the SQLite driver that report their queries
+// to a queryLogger. SQLite does most of the work of a query while its rows are read,
+// so queries returning rows are timed until the rows are closed.
+type queryLogConnector struct {
+	driver driver.Driver
+	dsn    string
+	logger *queryLogger
+}
+
+// Connect implements driver.Connector.
+func (c *queryLogConnector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loggedConn{Conn: conn, logger: c.logger}, nil
+}
+
+// Driver implements driver.Connector.
+func (c *queryLogConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// loggedConn is a driver connection reporting its queries. The SQLite connection
+// implements every context-aware interface, so they are forwarded without fallbacks.
+type loggedConn struct {
+	driver.Conn
+	logger *queryLogger
+}
+
+// ExecContext implements driver.ExecerContext.
+func (c *loggedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+
+	res, err := c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+	if !errors.Is(err, driver.ErrSkip) {
+		c.logger.record(ctx, query, args, start, err)
+	}
+
+	return res, err
+}
+
+// QueryContext implements driver.QueryerContext.
+func (c *loggedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+
+	rows, err := c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	if err != nil {
+		if !errors.Is(err, driver.ErrSkip) {
+			c.logger.record(ctx, query, args, start, err)
+		}
+
+		return nil, err
+	}
+
+	return &loggedRows{Rows: rows, ctx: ctx, logger: c.logger, query: query, args: args, start: start}, nil
+}
+
+// PrepareContext implements driver.ConnPrepareContext.
+func (c *loggedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+	if err != nil {
+		countDriverError(err)
+
+		return nil, err
+	}
+
+	return &loggedStmt{Stmt: stmt, logger: c.logger, query: query}, nil
+}
+
+// BeginTx implements driver.ConnBeginTx.
+func (c *loggedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+	if err != nil {
+		countDriverError(err)
+
+		return nil, err
+	}
+
+	return &loggedTx{Tx: tx, ctx: ctx, logger: c.logger}, nil
+}
+
+// Ping implements driver.Pinger.
+func (c *loggedConn) Ping(ctx context.Context) error {
+	return c.Conn.(driver.Pinger).Ping(ctx)
+}
+
+// loggedStmt is a prepared statement reporting its executions.
+type loggedStmt struct {
+	driver.Stmt
+	logger *queryLogger
+	query  string
+}
+
+// ExecContext implements driver.StmtExecContext.
+func (s *loggedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+
+	res, err := s.Stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+	s.logger.record(ctx, s.query, args, start, err)
+
+	return res, err
+}
+
+// QueryContext implements driver.StmtQueryContext.
+func (s *loggedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+
+	rows, err := s.Stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+	if err != nil {
+		s.logger.record(ctx, s.query, args, start, err)
+
+		return nil, err
+	}
+
+	return &loggedRows{Rows: rows, ctx: ctx, logger: s.logger, query: s.query, args: args, start: start}, nil
+}
+
+// loggedRows reports its query once it is closed, with the first error met while reading.
+type loggedRows struct {
+	driver.Rows
+	ctx    context.Context
+	logger *queryLogger
+	query  string
+	args   []driver.NamedValue
+	start  time.Time
+	err    error
+}
+
+// Next implements driver.Rows.
+func (r *loggedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err != nil && err != io.EOF && r.err == nil {
+		r.err = err
+	}
+
+	return err
+}
+
+// Close implements driver.Rows.
+func (r *loggedRows) Close() error {
+	err := r.Rows.Close()
+	r.logger.record(r.ctx, r.query, r.args, r.start, r.err)
+
+	return err
+}
+
+// loggedTx is a transaction reporting its commit, which waits for other connections
+// to release their locks when the database is contended.
+type loggedTx struct {
+	driver.Tx
+	ctx    context.Context
+	logger *queryLogger
+}
+
+// Commit implements driver.Tx.
+func (t *loggedTx) Commit() error {
+	start := time.Now()
+
+	err := t.Tx.Commit()
+	t.logger.record(t.ctx, "COMMIT", nil, start, err)
+
+	return err
+}
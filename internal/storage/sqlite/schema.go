@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SchemaVersion is the version of the schema this binary is written against, the number
+// of the latest migration in the migrations directory. Bump it with every migration.
+const SchemaVersion = 42
+
+// ErrSchemaIncompatible is returned when the database schema cannot be used by this binary.
+var ErrSchemaIncompatible = errors.New("incompatible database schema")
+
+// checkSchema verifies that the database schema can be used by this binary, so it never
+// reads or writes tables it does not know the layout of. The schema records its version,
+// and the oldest version whose binaries can still use it, in the schema_version table.
+//
+// A schema older than SchemaVersion is missing tables or columns the binary relies on.
+// A newer one is accepted as long as its migrations since SchemaVersion were additive,
+// e.g. during a rolling upgrade that migrated the database before replacing every binary.
+func (s *Storage) checkSchema(ctx context.Context, log *slog.Logger) error {
+	var version, compatibleFrom int
+
+	err := s.db.QueryRowContext(ctx, "SELECT version, compatible_from FROM schema_version WHERE id = 1").Scan(&version, &compatibleFrom)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.Is(err, sql.ErrNoRows) || (errors.As(err, &sqliteErr) && strings.HasPrefix(sqliteErr.Error(), "no such table")) {
+			return fmt.Errorf("%w: the database does not record its schema version; "+
+				"apply the migrations with the migrator up to version %d before starting the service", ErrSchemaIncompatible, SchemaVersion)
+		}
+
+		return err
+	}
+
+	log.Info("database schema checked",
+		slog.Int("schema_version", version),
+		slog.Int("compatible_from", compatibleFrom),
+		slog.Int("binary_schema_version", SchemaVersion),
+	)
+
+	switch {
+	case version < SchemaVersion:
+		return fmt.Errorf("%w: the database schema is at version %d, but this binary needs version %d; "+
+			"apply the pending migrations with the migrator before starting the service", ErrSchemaIncompatible, version, SchemaVersion)
+	case compatibleFrom > SchemaVersion:
+		return fmt.Errorf("%w: the database schema is at version %d, which binaries older than version %d cannot use, "+
+			"but this binary is at version %d; deploy a newer binary, or roll the migrations back to version %d", ErrSchemaIncompatible, version, compatibleFrom, SchemaVersion, SchemaVersion)
+	case version > SchemaVersion:
+		log.Warn("database schema is newer than this binary, but compatible with it; upgrade the binary",
+			slog.Int("schema_version", version),
+			slog.Int("binary_schema_version", SchemaVersion),
+		)
+	}
+
+	return nil
+}
@@ -4,45 +4,273 @@
 package sqlite
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/kirinyoku/sso-grpc/internal/config"
 	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/pii"
 	"github.com/kirinyoku/sso-grpc/internal/storage"
 	"github.com/mattn/go-sqlite3"
 )
 
+// nowUnix is an SQL expression evaluating to the current Unix time in seconds.
+const nowUnix = "CAST(strftime('%s', 'now') AS INTEGER)"
+
+// userColumns lists the users columns scanned by scanUser, in order.
+const userColumns = "id, public_id, email, email_enc, pass_hash, is_admin, created_at, updated_at, version, tokens_revoked_at, frozen_at, merged_into, push_mfa, avatar"
+
+// appColumns lists the apps columns scanned by scanApp, in order.
+const appColumns = "id, name, secret, token_format, minimal_claims, require_membership, family_id, created_at, updated_at, tokens_revoked_at, version, backchannel_logout_uri, token_ttl, test_only"
+
 // Storage implements the Storage interface using SQLite as the backing store.
 // It provides methods for user management, authentication, and application data access.
 type Storage struct {
-	db *sql.DB // Database connection handle
+	db  *sql.DB     // Database connection handle
+	pii *pii.Cipher // Encryption of personal data; nil to store it in plain text
 }
 
 // New creates a new SQLite storage instance and establishes a database connection.
 //
 // Parameters:
 //   - storagePath: filesystem path where the SQLite database file is located or should be created
+//   - piiCipher: encryption of user emails; nil to store them in plain text
+//   - log: logger for queries
+//   - queryLogCfg: which queries are logged, and from which duration they count as slow
+//   - sqliteCfg: PRAGMA settings applied to every connection
 //
 // Returns:
 //   - *Storage: a new Storage instance on success
-//   - error: non-nil if database connection fails
+//   - error: non-nil if database connection fails, if a setting of sqliteCfg is invalid
+//     or does not apply, if the schema is incompatible with this binary (ErrSchemaIncompatible),
+//     or if the database holds encrypted emails but piiCipher is nil
 //
-// The function ensures the database connection is working by pinging it before returning.
-func New(storagePath string) (*Storage, error) {
+// The function ensures the database connection is working by pinging it before returning,
+// and logs the settings the connection reads back and the schema version.
+// With piiCipher set, emails still stored in plain text are encrypted first.
+func New(storagePath string, piiCipher *pii.Cipher, log *slog.Logger, queryLogCfg config.QueryLog, sqliteCfg config.SQLite) (*Storage, error) {
 	const op = "storage.sqlite.New"
 
-	db, err := sql.Open("sqlite3", storagePath)
+	dsn, err := dataSourceName(storagePath, sqliteCfg)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	// Queries always go through the query logger, which also counts their errors.
+	db := sql.OpenDB(&queryLogConnector{
+		driver: &sqlite3.SQLiteDriver{},
+		dsn:    dsn,
+		logger: &queryLogger{log: log.With(slog.String("op", "storage.sqlite.query")), cfg: queryLogCfg},
+	})
+
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Storage{db: db}, nil
+	s := &Storage{db: db, pii: piiCipher}
+
+	if err := s.verifyPragmas(context.Background(), log.With(slog.String("op", op)), sqliteCfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Checked before anything is written, so an incompatible schema is left untouched.
+	if err := s.checkSchema(context.Background(), log.With(slog.String("op", op))); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.encryptEmails(context.Background()); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.bindSigningKeys(context.Background()); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return s, nil
+}
+
+// encryptEmails encrypts the emails still stored in plain text when a PII cipher is set,
+// and encrypts again those sealed before ciphertexts were bound to their row. Without a
+// cipher, it fails if any email is encrypted, since it could not be read.
+func (s *Storage) encryptEmails(ctx context.Context) error {
+	if s.pii == nil {
+		var encrypted bool
+
+		if err := s.db.QueryRowContext(ctx,
+			"SELECT EXISTS (SELECT 1 FROM users WHERE email_enc IS NOT NULL)",
+		).Scan(&encrypted); err != nil {
+			return err
+		}
+
+		if encrypted {
+			return errors.New("database holds encrypted emails but no pii key is configured")
+		}
+
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT id, email, email_enc FROM users WHERE email_enc IS NULL OR substr(email_enc, 1, 1) = ?",
+		[]byte{pii.VersionUnbound},
+	)
+	if err != nil {
+		return err
+	}
+
+	plain := make(map[int64]string)
+
+	for rows.Next() {
+		var (
+			id       int64
+			email    string
+			emailEnc []byte
+		)
+
+		if err := rows.Scan(&id, &email, &emailEnc); err != nil {
+			rows.Close()
+			return err
+		}
+
+		if emailEnc != nil {
+			if email, err = s.pii.DecryptUnbound(emailEnc); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+
+		plain[id] = email
+	}
+
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, email := range plain {
+		emailCol, emailHash := s.emailIndex(email)
+
+		emailEnc, err := s.encryptEmail(id, email)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE users SET email = ?, email_hash = ?, email_enc = ? WHERE id = ?",
+			emailCol, emailHash, emailEnc, id,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// bindSigningKeys encrypts again the private keys sealed before ciphertexts were bound
+// to their row, when a PII cipher is set.
+func (s *Storage) bindSigningKeys(ctx context.Context) error {
+	if s.pii == nil {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT id, private_key FROM signing_keys WHERE substr(private_key, 1, 1) = ?",
+		[]byte{pii.VersionUnbound},
+	)
+	if err != nil {
+		return err
+	}
+
+	privateKeys := make(map[string]string)
+
+	for rows.Next() {
+		var (
+			id         string
+			privateKey []byte
+		)
+
+		if err := rows.Scan(&id, &privateKey); err != nil {
+			rows.Close()
+			return err
+		}
+
+		decrypted, err := s.pii.DecryptUnbound(privateKey)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		privateKeys[id] = decrypted
+	}
+
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, privateKey := range privateKeys {
+		encrypted, err := s.pii.Encrypt(privateKey, piiAAD("signing_keys.private_key", id))
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE signing_keys SET private_key = ? WHERE id = ?", encrypted, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// piiAAD returns the associated data binding an encrypted value to its column and row,
+// so a ciphertext copied to another row or column fails to decrypt.
+func piiAAD(column string, id any) []byte {
+	return fmt.Appendf(nil, "%s:%v", column, id)
+}
+
+// emailIndex returns the values stored in the email and email_hash columns for an
+// email. Without a PII cipher, the email is stored in plain text.
+func (s *Storage) emailIndex(email string) (emailCol string, emailHash []byte) {
+	if s.pii == nil {
+		return email, nil
+	}
+
+	emailHash = s.pii.Index(email)
+
+	// The email column is unique and not null, so it keeps the hash in place of the address.
+	return hex.EncodeToString(emailHash), emailHash
+}
+
+// encryptEmail returns the value stored in the email_enc column for the email of the
+// user with an ID, or nil without a PII cipher.
+func (s *Storage) encryptEmail(id int64, email string) ([]byte, error) {
+	if s.pii == nil {
+		return nil, nil
+	}
+
+	return s.pii.Encrypt(email, piiAAD("users.email_enc", id))
 }
 
 // SaveUser creates a new user record in the database with the provided email and password hash.
@@ -51,38 +279,67 @@ func New(storagePath string) (*Storage, error) {
 //   - ctx: context for request cancellation and timeouts
 //   - email: user's email address (must be unique)
 //   - passHash: bcrypt hashed password
+//   - publicID: public identifier of the user, or an empty string to use
+//     the decimal form of the sequential ID
 //
 // Returns:
 //   - int64: ID of the newly created user
+//   - string: public identifier of the newly created user
 //   - error: storage.ErrUserExists if a user with the email already exists,
 //     or another error if the operation fails
-func (s *Storage) SaveUser(ctx context.Context, email string, passHash []byte) (int64, error) {
+func (s *Storage) SaveUser(ctx context.Context, email string, passHash []byte, publicID string) (int64, string, error) {
 	const op = "storage.sqlite.SaveUser"
 
-	stmt, err := s.db.Prepare("INSERT INTO users (email, pass_hash) VALUES (?, ?)")
+	emailCol, emailHash := s.emailIndex(email)
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return 0, fmt.Errorf("%s: %w", op, err)
+		return 0, "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	defer stmt.Close()
+	defer tx.Rollback()
 
-	result, err := stmt.ExecContext(ctx, email, passHash)
+	result, err := tx.ExecContext(ctx,
+		"INSERT INTO users (email, email_hash, pass_hash, public_id, created_at, updated_at) VALUES (?, ?, ?, NULLIF(?, ''), "+nowUnix+", "+nowUnix+")",
+		emailCol, emailHash, passHash, publicID,
+	)
 	if err != nil {
 		var sqliteErr sqlite3.Error
 
 		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
-			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserExists)
+			return 0, "", fmt.Errorf("%s: %w", op, storage.ErrUserExists)
 		}
 
-		return 0, fmt.Errorf("%s: %w", op, err)
+		return 0, "", fmt.Errorf("%s: %w", op, err)
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return 0, fmt.Errorf("%s: %w", op, err)
+		return 0, "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	return id, nil
+	// The encrypted email is bound to the ID of the row, so it is written once the row has one.
+	if s.pii != nil {
+		emailEnc, err := s.encryptEmail(id, email)
+		if err != nil {
+			return 0, "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET email_enc = ? WHERE id = ?", emailEnc, id); err != nil {
+			return 0, "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if publicID == "" {
+		// Assigned by the users_default_public_id trigger.
+		publicID = strconv.FormatInt(id, 10)
+	}
+
+	return id, publicID, nil
 }
 
 // User retrieves a user from the database by email.
@@ -98,26 +355,177 @@ func (s *Storage) SaveUser(ctx context.Context, email string, passHash []byte) (
 func (s *Storage) User(ctx context.Context, email string) (*models.User, error) {
 	const op = "storage.sqlite.User"
 
-	stmt, err := s.db.Prepare("SELECT id, email, pass_hash FROM users WHERE email = ?")
+	query, key := "SELECT "+userColumns+" FROM users WHERE email = ?", any(email)
+	if s.pii != nil {
+		query, key = "SELECT "+userColumns+" FROM users WHERE email_hash = ?", s.pii.Index(email)
+	}
+
+	stmt, err := s.db.Prepare(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	user, err := s.scanUser(stmt.QueryRowContext(ctx, key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrUserNotFound))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// UserByID retrieves a user by ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user to retrieve
+//
+// Returns:
+//   - *models.User: user information if found
+//   - error: storage.ErrUserNotFound if no user exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) UserByID(ctx context.Context, userID int64) (*models.User, error) {
+	const op = "storage.sqlite.UserByID"
+
+	stmt, err := s.db.Prepare("SELECT " + userColumns + " FROM users WHERE id = ?")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	defer stmt.Close()
 
-	row := stmt.QueryRowContext(ctx, email)
+	user, err := s.scanUser(stmt.QueryRowContext(ctx, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrUserNotFound))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// UserByPublicID retrieves a user by public identifier.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - publicID: public identifier of the user
+//
+// Returns:
+//   - *models.User: user information if found
+//   - error: storage.ErrUserNotFound if no user exists with the identifier,
+//     or another error if the operation fails
+func (s *Storage) UserByPublicID(ctx context.Context, publicID string) (*models.User, error) {
+	const op = "storage.sqlite.UserByPublicID"
+
+	stmt, err := s.db.Prepare("SELECT " + userColumns + " FROM users WHERE public_id = ?")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-	var user models.User
+	defer stmt.Close()
 
-	if err := row.Scan(&user.ID, &user.Email, &user.PassHash); err != nil {
+	user, err := s.scanUser(stmt.QueryRowContext(ctx, publicID))
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+			return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrUserNotFound))
 		}
 
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &user, nil
+	return user, nil
+}
+
+// UpdateUser changes a user's admin flag if the user is still at the expected version.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user to update
+//   - isAdmin: new admin flag
+//   - version: version of the user the change is based on
+//
+// Returns:
+//   - *models.User: the updated user
+//   - error: storage.ErrUserNotFound if no user exists with the ID,
+//     storage.ErrVersionConflict if the user was modified since version was read,
+//     or another error if the operation fails
+func (s *Storage) UpdateUser(ctx context.Context, userID int64, isAdmin bool, version int64) (*models.User, error) {
+	const op = "storage.sqlite.UpdateUser"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	user, err := s.scanUser(tx.QueryRowContext(ctx,
+		"UPDATE users SET is_admin = ?, updated_at = "+nowUnix+", version = version + 1 WHERE id = ? AND version = ? RETURNING "+userColumns,
+		isAdmin, userID, version,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, versionMismatch(ctx, tx, "users", userID, storage.ErrUserNotFound))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// IsEmpty reports whether the database has neither users nor apps, as on the first start.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - bool: true if there are no users and no apps
+//   - error: non-nil if the operation fails
+func (s *Storage) IsEmpty(ctx context.Context) (bool, error) {
+	const op = "storage.sqlite.IsEmpty"
+
+	var empty bool
+
+	err := s.db.QueryRowContext(ctx,
+		"SELECT NOT EXISTS (SELECT 1 FROM users) AND NOT EXISTS (SELECT 1 FROM apps)",
+	).Scan(&empty)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return empty, nil
+}
+
+// Snapshot writes a consistent copy of the database to a new file, without blocking writers
+// for longer than a read transaction does. The copy is compacted, and holds the same schema
+// and data, so the service can run on it after it is renamed to the storage path.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - path: path of the file to create; must not exist
+//
+// Returns:
+//   - error: non-nil if the copy cannot be written
+func (s *Storage) Snapshot(ctx context.Context, path string) error {
+	const op = "storage.sqlite.Snapshot"
+
+	if _, err := s.db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
 }
 
 // IsAdmin checks if a user has administrative privileges.
@@ -146,7 +554,7 @@ func (s *Storage) IsAdmin(ctx context.Context, userID int64) (bool, error) {
 
 	if err := row.Scan(&isAdmin); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return false, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+			return false, fmt.Errorf("%s: %w", op, notFound(storage.ErrUserNotFound))
 		}
 
 		return false, fmt.Errorf("%s: %w", op, err)
@@ -168,24 +576,4395 @@ func (s *Storage) IsAdmin(ctx context.Context, userID int64) (bool, error) {
 func (s *Storage) App(ctx context.Context, appID int32) (*models.App, error) {
 	const op = "storage.sqlite.App"
 
-	stmt, err := s.db.Prepare("SELECT id, name, secret FROM apps WHERE id = ?")
+	stmt, err := s.db.Prepare("SELECT " + appColumns + " FROM apps WHERE id = ?")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	defer stmt.Close()
 
-	row := stmt.QueryRowContext(ctx, appID)
+	app, err := scanApp(stmt.QueryRowContext(ctx, appID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// UpdateApp changes an application's name and secret if the app is still at the expected version.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application to update
+//   - name: new name, or an empty string to keep the current one
+//   - secret: new secret, or an empty string to keep the current one
+//   - tokenFormat: new token format, or an empty string to keep the current one
+//   - minimalClaims: whether tokens carry only minimal claims, or nil to keep the current setting
+//   - requireMembership: whether only members may log in, or nil to keep the current setting
+//   - familyID: family to move the app to, 0 to remove it from its family, or nil to keep the current one
+//   - version: version of the app the change is based on
+//
+// Returns:
+//   - *models.App: the updated application
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     storage.ErrVersionConflict if the app was modified since version was read,
+//     storage.ErrAppExists if the name or secret is taken by another app,
+//     storage.ErrAppFamilyNotFound if no family exists with familyID,
+//     or another error if the operation fails
+func (s *Storage) UpdateApp(
+	ctx context.Context,
+	appID int32,
+	name string,
+	secret string,
+	tokenFormat string,
+	minimalClaims *bool,
+	requireMembership *bool,
+	familyID *int32,
+	version int64,
+) (*models.App, error) {
+	const op = "storage.sqlite.UpdateApp"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
 
-	var app models.App
+	if familyID != nil && *familyID != 0 {
+		if err := appFamilyExists(ctx, tx, *familyID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
 
-	if err := row.Scan(&app.ID, &app.Name, &app.Secret); err != nil {
+	app, err := scanApp(tx.QueryRowContext(ctx,
+		`UPDATE apps
+		SET name = COALESCE(NULLIF(?, ''), name),
+			secret = COALESCE(NULLIF(?, ''), secret),
+			token_format = COALESCE(NULLIF(?, ''), token_format),
+			minimal_claims = COALESCE(?, minimal_claims),
+			require_membership = COALESCE(?, require_membership),
+			family_id = CASE WHEN ? IS NULL THEN family_id ELSE NULLIF(?, 0) END,
+			updated_at = `+nowUnix+`,
+			version = version + 1
+		WHERE id = ? AND version = ?
+		RETURNING `+appColumns,
+		name, secret, tokenFormat, minimalClaims, requireMembership, familyID, familyID, appID, version,
+	))
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+			return nil, fmt.Errorf("%s: %w", op, versionMismatch(ctx, tx, "apps", int64(appID), storage.ErrAppNotFound))
+		}
+
+		var sqliteErr sqlite3.Error
+
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrAppExists)
 		}
 
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &app, nil
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// SaveApp creates a new application record with the provided name and secret.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - name: application name (must be unique)
+//   - secret: secret used to sign tokens issued for the application (must be unique)
+//   - tokenFormat: format of tokens issued for the application
+//   - minimalClaims: whether tokens issued for the application carry only minimal claims
+//   - requireMembership: whether only users granted access may log in to the application
+//   - familyID: family the application joins; 0 for none
+//
+// Returns:
+//   - int32: ID of the newly created application
+//   - error: storage.ErrAppExists if an application with the name or secret already exists,
+//     storage.ErrAppFamilyNotFound if no family exists with familyID,
+//     or another error if the operation fails
+func (s *Storage) SaveApp(
+	ctx context.Context,
+	name string,
+	secret string,
+	tokenFormat string,
+	minimalClaims bool,
+	requireMembership bool,
+	familyID int32,
+) (int32, error) {
+	const op = "storage.sqlite.SaveApp"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	if familyID != 0 {
+		if err := appFamilyExists(ctx, tx, familyID); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	result, err := tx.ExecContext(ctx,
+		"INSERT INTO apps (name, secret, token_format, minimal_claims, require_membership, family_id, created_at, updated_at) "+
+			"VALUES (?, ?, ?, ?, ?, NULLIF(?, 0), "+nowUnix+", "+nowUnix+")",
+		name, secret, tokenFormat, minimalClaims, requireMembership, familyID,
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrAppExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int32(id), nil
+}
+
+// DeleteApp removes an application record by ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application to delete
+//
+// Returns:
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) DeleteApp(ctx context.Context, appID int32) error {
+	const op = "storage.sqlite.DeleteApp"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM apps WHERE id = ?", appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+	}
+
+	// App IDs may be reused, so the app's settings are removed explicitly.
+	if _, err := tx.ExecContext(ctx, "DELETE FROM app_email_domains WHERE app_id = ?", appID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM app_members WHERE app_id = ?", appID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM app_owners WHERE app_id = ?", appID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM app_labels WHERE app_id = ?", appID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AppEmailDomains returns the email domain lists restricting registration for an application.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//
+// Returns:
+//   - allowed: domains allowed to register; empty if every domain is allowed
+//   - denied: domains denied registration
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) AppEmailDomains(ctx context.Context, appID int32) (allowed []string, denied []string, err error) {
+	const op = "storage.sqlite.AppEmailDomains"
+
+	var exists bool
+
+	if err := s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM apps WHERE id = ?)", appID).Scan(&exists); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return nil, nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT domain, denied FROM app_email_domains WHERE app_id = ? ORDER BY domain", appID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			domain   string
+			isDenied bool
+		)
+
+		if err := rows.Scan(&domain, &isDenied); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if isDenied {
+			denied = append(denied, domain)
+		} else {
+			allowed = append(allowed, domain)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return allowed, denied, nil
+}
+
+// SetAppEmailDomains replaces the email domain lists restricting registration for an application.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - allowed: domains allowed to register; empty to allow every domain
+//   - denied: domains denied registration
+//
+// Returns:
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) SetAppEmailDomains(ctx context.Context, appID int32, allowed []string, denied []string) error {
+	const op = "storage.sqlite.SetAppEmailDomains"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	var exists bool
+
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM apps WHERE id = ?)", appID).Scan(&exists); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM app_email_domains WHERE app_id = ?", appID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO app_email_domains (app_id, domain, denied) VALUES (?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	for _, domain := range allowed {
+		if _, err := stmt.ExecContext(ctx, appID, domain, false); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for _, domain := range denied {
+		if _, err := stmt.ExecContext(ctx, appID, domain, true); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AppDisposableEmailPolicy returns how an application handles registrations from disposable email providers.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//
+// Returns:
+//   - string: the app's policy, or an empty string if the app uses the default
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) AppDisposableEmailPolicy(ctx context.Context, appID int32) (string, error) {
+	const op = "storage.sqlite.AppDisposableEmailPolicy"
+
+	stmt, err := s.db.Prepare("SELECT disposable_email_policy FROM apps WHERE id = ?")
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	var policy string
+
+	if err := stmt.QueryRowContext(ctx, appID).Scan(&policy); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+		}
+
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return policy, nil
+}
+
+// SetAppDisposableEmailPolicy sets how an application handles registrations from disposable email providers.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - policy: the app's policy, or an empty string to use the default
+//
+// Returns:
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) SetAppDisposableEmailPolicy(ctx context.Context, appID int32, policy string) error {
+	const op = "storage.sqlite.SetAppDisposableEmailPolicy"
+
+	stmt, err := s.db.Prepare("UPDATE apps SET disposable_email_policy = ?, updated_at = " + nowUnix + ", version = version + 1 WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, policy, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+	}
+
+	return nil
+}
+
+// SetAppTokenTTL sets the lifetime of tokens issued for an application.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - ttl: lifetime of issued tokens, stored in whole seconds, or 0 to use the server default
+//
+// Returns:
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) SetAppTokenTTL(ctx context.Context, appID int32, ttl time.Duration) error {
+	const op = "storage.sqlite.SetAppTokenTTL"
+
+	stmt, err := s.db.Prepare("UPDATE apps SET token_ttl = ?, updated_at = " + nowUnix + ", version = version + 1 WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, int64(ttl/time.Second), appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+	}
+
+	return nil
+}
+
+// SetAppTestOnly sets whether test tokens may be minted for an application.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - testOnly: whether MintTestToken may mint tokens for the application
+//
+// Returns:
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) SetAppTestOnly(ctx context.Context, appID int32, testOnly bool) error {
+	const op = "storage.sqlite.SetAppTestOnly"
+
+	stmt, err := s.db.Prepare("UPDATE apps SET test_only = ?, updated_at = " + nowUnix + ", version = version + 1 WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, testOnly, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+	}
+
+	return nil
+}
+
+// SetAppBackchannelLogoutURI sets the URI an application receives logout tokens at.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - uri: absolute http or https URI, or an empty string to stop sending logout tokens
+//
+// Returns:
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) SetAppBackchannelLogoutURI(ctx context.Context, appID int32, uri string) error {
+	const op = "storage.sqlite.SetAppBackchannelLogoutURI"
+
+	stmt, err := s.db.Prepare("UPDATE apps SET backchannel_logout_uri = ?, updated_at = " + nowUnix + ", version = version + 1 WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, uri, appID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+	}
+
+	return nil
+}
+
+// FlagDisposableEmail marks a user as registered with a disposable email address.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) FlagDisposableEmail(ctx context.Context, userID int64) error {
+	const op = "storage.sqlite.FlagDisposableEmail"
+
+	stmt, err := s.db.Prepare("UPDATE users SET disposable_email = TRUE WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RecordLogin records that a user logged in on the UTC day of the given moment.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - at: moment of the login
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) RecordLogin(ctx context.Context, userID int64, at time.Time) error {
+	const op = "storage.sqlite.RecordLogin"
+
+	stmt, err := s.db.Prepare("INSERT OR IGNORE INTO login_days (day, user_id) VALUES (?, ?)")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, unixDay(at), userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Stats computes aggregate user counts, with daily counts for every UTC day
+// from since up to and including now.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - since: first day to report
+//   - now: current moment, whose day is the last one reported
+//
+// Returns:
+//   - *models.Stats: computed counts
+//   - error: non-nil if the operation fails
+func (s *Storage) Stats(ctx context.Context, since time.Time, now time.Time) (*models.Stats, error) {
+	const op = "storage.sqlite.Stats"
+
+	firstDay, lastDay := unixDay(since), unixDay(now)
+
+	stats := &models.Stats{GeneratedAt: now}
+
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&stats.TotalUsers); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	registrations, err := s.countByDay(ctx,
+		"SELECT created_at / 86400 AS day, COUNT(*) FROM users WHERE created_at >= ? GROUP BY day",
+		firstDay*86400,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	active, err := s.countByDay(ctx,
+		"SELECT day, COUNT(*) FROM login_days WHERE day >= ? GROUP BY day",
+		firstDay,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for day := firstDay; day <= lastDay; day++ {
+		stats.Days = append(stats.Days, models.DailyStats{
+			Date:          time.Unix(day*86400, 0).UTC(),
+			Registrations: registrations[day],
+			ActiveUsers:   active[day],
+		})
+	}
+
+	return stats, nil
+}
+
+// countByDay runs a query returning (day, count) rows and collects them into a map.
+func (s *Storage) countByDay(ctx context.Context, query string, args ...any) (map[int64]int64, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	counts := make(map[int64]int64)
+
+	for rows.Next() {
+		var day, count int64
+
+		if err := rows.Scan(&day, &count); err != nil {
+			return nil, err
+		}
+
+		counts[day] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// unixDay returns the number of UTC days between the Unix epoch and t.
+func unixDay(t time.Time) int64 {
+	return t.Unix() / 86400
+}
+
+// ReserveIdempotencyKey records that a request with the given idempotency key is being processed.
+// An expired record for the same key is replaced.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - method: full gRPC method name the key is scoped to
+//   - key: client-supplied idempotency key
+//   - requestHash: hash of the request payload, used to detect key reuse
+//   - expiresAt: moment after which the key may be reused
+//
+// Returns:
+//   - error: storage.ErrIdempotencyKeyExists if an unexpired record exists for the key,
+//     or another error if the operation fails
+func (s *Storage) ReserveIdempotencyKey(ctx context.Context, method string, key string, requestHash []byte, expiresAt time.Time) error {
+	const op = "storage.sqlite.ReserveIdempotencyKey"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		"DELETE FROM idempotency_keys WHERE method = ? AND key = ? AND expires_at <= ?",
+		method, key, time.Now().Unix(),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO idempotency_keys (method, key, request_hash, expires_at) VALUES (?, ?, ?, ?)",
+		method, key, requestHash, expiresAt.Unix(),
+	); err != nil {
+		var sqliteErr sqlite3.Error
+
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+			return fmt.Errorf("%s: %w", op, storage.ErrIdempotencyKeyExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// IdempotencyRecord retrieves the unexpired record of an idempotency key.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - method: full gRPC method name the key is scoped to
+//   - key: client-supplied idempotency key
+//
+// Returns:
+//   - *models.IdempotencyRecord: record of the key if found
+//   - error: storage.ErrIdempotencyKeyNotFound if no unexpired record exists,
+//     or another error if the operation fails
+func (s *Storage) IdempotencyRecord(ctx context.Context, method string, key string) (*models.IdempotencyRecord, error) {
+	const op = "storage.sqlite.IdempotencyRecord"
+
+	stmt, err := s.db.Prepare("SELECT method, key, request_hash, response, expires_at FROM idempotency_keys WHERE method = ? AND key = ? AND expires_at > ?")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	row := stmt.QueryRowContext(ctx, method, key, time.Now().Unix())
+
+	var (
+		record    models.IdempotencyRecord
+		expiresAt int64
+	)
+
+	if err := row.Scan(&record.Method, &record.Key, &record.RequestHash, &record.Response, &expiresAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrIdempotencyKeyNotFound))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	record.ExpiresAt = time.Unix(expiresAt, 0)
+
+	return &record, nil
+}
+
+// SaveIdempotentResponse stores the response produced for a reserved idempotency key.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - method: full gRPC method name the key is scoped to
+//   - key: client-supplied idempotency key
+//   - response: serialized response returned to the original request
+//
+// Returns:
+//   - error: storage.ErrIdempotencyKeyNotFound if the key is not reserved,
+//     or another error if the operation fails
+func (s *Storage) SaveIdempotentResponse(ctx context.Context, method string, key string, response []byte) error {
+	const op = "storage.sqlite.SaveIdempotentResponse"
+
+	stmt, err := s.db.Prepare("UPDATE idempotency_keys SET response = ? WHERE method = ? AND key = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, response, method, key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrIdempotencyKeyNotFound))
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyKey releases a reserved idempotency key so the request can be retried.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - method: full gRPC method name the key is scoped to
+//   - key: client-supplied idempotency key
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) DeleteIdempotencyKey(ctx context.Context, method string, key string) error {
+	const op = "storage.sqlite.DeleteIdempotencyKey"
+
+	stmt, err := s.db.Prepare("DELETE FROM idempotency_keys WHERE method = ? AND key = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, method, key); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SavePasswordResetToken stores the hash of a newly issued password reset token.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user the token was issued to
+//   - tokenHash: SHA-256 hash of the token; the token itself is never stored
+//   - createdAt: moment the token was issued
+//   - expiresAt: moment after which the token can no longer be used
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) SavePasswordResetToken(ctx context.Context, userID int64, tokenHash []byte, createdAt time.Time, expiresAt time.Time) error {
+	const op = "storage.sqlite.SavePasswordResetToken"
+
+	stmt, err := s.db.Prepare("INSERT INTO password_reset_tokens (user_id, token_hash, created_at, expires_at) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, userID, tokenHash, createdAt.Unix(), expiresAt.Unix()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CountPasswordResetTokens counts the password reset tokens issued to a user since the given moment.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - since: start of the counting window
+//
+// Returns:
+//   - int: number of tokens issued in the window, including used and expired ones
+//   - error: non-nil if the operation fails
+func (s *Storage) CountPasswordResetTokens(ctx context.Context, userID int64, since time.Time) (int, error) {
+	const op = "storage.sqlite.CountPasswordResetTokens"
+
+	stmt, err := s.db.Prepare("SELECT COUNT(*) FROM password_reset_tokens WHERE user_id = ? AND created_at >= ?")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	var count int
+
+	if err := stmt.QueryRowContext(ctx, userID, since.Unix()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// ResetPassword consumes a password reset token and replaces the user's password hash.
+// Both happen in one transaction, and every other outstanding token of the user is revoked.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - tokenHash: SHA-256 hash of the presented token
+//   - passHash: bcrypt hash of the new password
+//   - now: moment used to check expiry and mark the token as used
+//
+// Returns:
+//   - int64: ID of the user whose password was reset
+//   - error: storage.ErrResetTokenNotFound if the token does not exist, was already used,
+//     or has expired, or another error if the operation fails
+func (s *Storage) ResetPassword(ctx context.Context, tokenHash []byte, passHash []byte, now time.Time) (int64, error) {
+	const op = "storage.sqlite.ResetPassword"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	var userID int64
+
+	err = tx.QueryRowContext(ctx,
+		"UPDATE password_reset_tokens SET used_at = ? WHERE token_hash = ? AND used_at IS NULL AND expires_at > ? RETURNING user_id",
+		now.Unix(), tokenHash, now.Unix(),
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, notFound(storage.ErrResetTokenNotFound))
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE users SET pass_hash = ?, updated_at = ?, version = version + 1 WHERE id = ?",
+		passHash, now.Unix(), userID,
+	); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"DELETE FROM password_reset_tokens WHERE user_id = ? AND used_at IS NULL",
+		userID,
+	); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
+
+// DeletePasswordResetTokens removes every unused password reset token.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - int64: number of tokens removed
+//   - error: non-nil if the operation fails
+func (s *Storage) DeletePasswordResetTokens(ctx context.Context) (int64, error) {
+	const op = "storage.sqlite.DeletePasswordResetTokens"
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM password_reset_tokens WHERE used_at IS NULL")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanUser scans a row selected with userColumns, decrypting the email if it is encrypted.
+// A missing public ID, as left by rows written outside the service, is reported as the
+// decimal form of the ID, which the users_default_public_id trigger would have assigned.
+func (s *Storage) scanUser(row rowScanner) (*models.User, error) {
+	var (
+		user                 models.User
+		publicID             sql.NullString
+		emailEnc             []byte
+		createdAt, updatedAt int64
+		tokensRevokedAt      int64
+		frozenAt             int64
+	)
+
+	if err := row.Scan(
+		&user.ID, &publicID, &user.Email, &emailEnc, &user.PassHash, &user.IsAdmin, &createdAt, &updatedAt, &user.Version,
+		&tokensRevokedAt, &frozenAt, &user.MergedInto, &user.PushMFA, &user.Avatar,
+	); err != nil {
+		return nil, err
+	}
+
+	user.PublicID = publicID.String
+	if !publicID.Valid {
+		user.PublicID = strconv.FormatInt(user.ID, 10)
+	}
+
+	if emailEnc != nil {
+		if s.pii == nil {
+			return nil, errors.New("email is encrypted but no pii key is configured")
+		}
+
+		email, err := s.pii.Decrypt(emailEnc, piiAAD("users.email_enc", user.ID))
+		if err != nil {
+			return nil, err
+		}
+
+		user.Email = email
+	}
+
+	user.CreatedAt = fromUnix(createdAt)
+	user.UpdatedAt = fromUnix(updatedAt)
+	user.TokensRevokedAt = fromUnix(tokensRevokedAt)
+	user.FrozenAt = fromUnix(frozenAt)
+
+	return &user, nil
+}
+
+// scanApp scans a row selected with appColumns.
+func scanApp(row rowScanner) (*models.App, error) {
+	var (
+		app                                             models.App
+		familyID                                        sql.NullInt32
+		createdAt, updatedAt, tokensRevokedAt, tokenTTL int64
+	)
+
+	if err := row.Scan(
+		&app.ID, &app.Name, &app.Secret, &app.TokenFormat, &app.MinimalClaims, &app.RequireMembership,
+		&familyID, &createdAt, &updatedAt, &tokensRevokedAt, &app.Version, &app.BackchannelLogoutURI, &tokenTTL,
+		&app.TestOnly,
+	); err != nil {
+		return nil, err
+	}
+
+	app.FamilyID = familyID.Int32
+	app.TokenTTL = time.Duration(tokenTTL) * time.Second
+	app.CreatedAt = fromUnix(createdAt)
+	app.UpdatedAt = fromUnix(updatedAt)
+	app.TokensRevokedAt = fromUnix(tokensRevokedAt)
+
+	return &app, nil
+}
+
+// fromUnix converts Unix seconds to a time, mapping 0 (unknown) to the zero time.
+func fromUnix(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(sec, 0).UTC()
+}
+
+// versionMismatch explains why a versioned update of the row with the given ID
+// in table matched no rows: errNotFound if the row does not exist, or
+// storage.ErrVersionConflict if it exists at another version.
+func versionMismatch(ctx context.Context, tx *sql.Tx, table string, id int64, errNotFound error) error {
+	var exists bool
+
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = ?)", id).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return notFound(errNotFound)
+	}
+
+	return storage.ErrVersionConflict
+}
+
+// appAndUserExist returns storage.ErrAppNotFound or storage.ErrUserNotFound
+// if the application or the user does not exist.
+func appAndUserExist(ctx context.Context, tx *sql.Tx, appID int32, userID int64) error {
+	var appExists, userExists bool
+
+	if err := tx.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM apps WHERE id = ?), EXISTS (SELECT 1 FROM users WHERE id = ?)", appID, userID,
+	).Scan(&appExists, &userExists); err != nil {
+		return err
+	}
+
+	if !appExists {
+		return notFound(storage.ErrAppNotFound)
+	}
+
+	if !userExists {
+		return notFound(storage.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// appFamilyExists returns storage.ErrAppFamilyNotFound if the app family does not exist.
+func appFamilyExists(ctx context.Context, tx *sql.Tx, familyID int32) error {
+	var exists bool
+
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM app_families WHERE id = ?)", familyID).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return notFound(storage.ErrAppFamilyNotFound)
+	}
+
+	return nil
+}
+
+// SaveDeviceAuthorization persists a new pending device authorization.
+// Expired authorizations are removed first.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - auth: authorization to save; ID, UserID, Status, and LastPolledAt are ignored
+//
+// Returns:
+//   - error: storage.ErrUserCodeExists if the user code is already in use,
+//     or another error if the operation fails
+func (s *Storage) SaveDeviceAuthorization(ctx context.Context, auth *models.DeviceAuthorization) error {
+	const op = "storage.sqlite.SaveDeviceAuthorization"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		"DELETE FROM device_authorizations WHERE expires_at <= ?",
+		auth.CreatedAt.Unix(),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO device_authorizations (device_code_hash, user_code, app_id, interval_seconds, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		auth.DeviceCodeHash, auth.UserCode, auth.AppID, int64(auth.Interval/time.Second), auth.CreatedAt.Unix(), auth.ExpiresAt.Unix(),
+	); err != nil {
+		var sqliteErr sqlite3.Error
+
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%s: %w", op, storage.ErrUserCodeExists)
+		}
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeviceAuthorization retrieves a device authorization by the hash of its device code.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - deviceCodeHash: SHA-256 of the device code
+//
+// Returns:
+//   - *models.DeviceAuthorization: the authorization if found
+//   - error: storage.ErrDeviceAuthorizationNotFound if no authorization exists for the code,
+//     or another error if the operation fails
+func (s *Storage) DeviceAuthorization(ctx context.Context, deviceCodeHash []byte) (*models.DeviceAuthorization, error) {
+	const op = "storage.sqlite.DeviceAuthorization"
+
+	stmt, err := s.db.Prepare(
+		`SELECT id, device_code_hash, user_code, app_id, COALESCE(user_id, 0), status,
+			interval_seconds, created_at, expires_at, last_polled_at
+		FROM device_authorizations WHERE device_code_hash = ?`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	var (
+		auth                                       models.DeviceAuthorization
+		interval, createdAt, expiresAt, lastPolled int64
+	)
+
+	if err := stmt.QueryRowContext(ctx, deviceCodeHash).Scan(
+		&auth.ID, &auth.DeviceCodeHash, &auth.UserCode, &auth.AppID, &auth.UserID, &auth.Status,
+		&interval, &createdAt, &expiresAt, &lastPolled,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrDeviceAuthorizationNotFound))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	auth.Interval = time.Duration(interval) * time.Second
+	auth.CreatedAt = fromUnix(createdAt)
+	auth.ExpiresAt = fromUnix(expiresAt)
+	auth.LastPolledAt = fromUnix(lastPolled)
+
+	return &auth, nil
+}
+
+// DecideDeviceAuthorization records the user's decision on a pending, unexpired device authorization.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userCode: normalized user code shown on the device
+//   - userID: ID of the user deciding
+//   - status: models.DeviceAuthorizationApproved or models.DeviceAuthorizationDenied
+//   - now: current moment, used to reject expired authorizations
+//
+// Returns:
+//   - int32: ID of the app the device requested access to
+//   - error: storage.ErrDeviceAuthorizationNotFound if no pending, unexpired authorization
+//     exists for the code, or another error if the operation fails
+func (s *Storage) DecideDeviceAuthorization(ctx context.Context, userCode string, userID int64, status string, now time.Time) (int32, error) {
+	const op = "storage.sqlite.DecideDeviceAuthorization"
+
+	var appID int32
+
+	err := s.db.QueryRowContext(ctx,
+		`UPDATE device_authorizations SET status = ?, user_id = ?
+		WHERE user_code = ? AND status = ? AND expires_at > ?
+		RETURNING app_id`,
+		status, userID, userCode, models.DeviceAuthorizationPending, now.Unix(),
+	).Scan(&appID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, notFound(storage.ErrDeviceAuthorizationNotFound))
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return appID, nil
+}
+
+// PollDeviceAuthorization records a poll of a device authorization and its new polling interval.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - id: ID of the authorization
+//   - polledAt: moment of the poll
+//   - interval: minimum time until the next poll
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) PollDeviceAuthorization(ctx context.Context, id int64, polledAt time.Time, interval time.Duration) error {
+	const op = "storage.sqlite.PollDeviceAuthorization"
+
+	if _, err := s.db.ExecContext(ctx,
+		"UPDATE device_authorizations SET last_polled_at = ?, interval_seconds = ? WHERE id = ?",
+		polledAt.Unix(), int64(interval/time.Second), id,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ConsumeDeviceAuthorization marks an approved device authorization as consumed,
+// so only one token is ever issued for it.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - id: ID of the authorization
+//
+// Returns:
+//   - error: storage.ErrDeviceAuthorizationNotFound if the authorization is not approved
+//     or was already consumed, or another error if the operation fails
+func (s *Storage) ConsumeDeviceAuthorization(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.ConsumeDeviceAuthorization"
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE device_authorizations SET status = ? WHERE id = ? AND status = ?",
+		models.DeviceAuthorizationConsumed, id, models.DeviceAuthorizationApproved,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrDeviceAuthorizationNotFound))
+	}
+
+	return nil
+}
+
+// SetPushMFA enables or disables push MFA for a user.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - enabled: whether logins of the user must be approved from a signed-in device
+//
+// Returns:
+//   - error: storage.ErrUserNotFound if the user doesn't exist, or another error if the operation fails
+func (s *Storage) SetPushMFA(ctx context.Context, userID int64, enabled bool) error {
+	const op = "storage.sqlite.SetPushMFA"
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE users SET push_mfa = ?, updated_at = "+nowUnix+", version = version + 1 WHERE id = ?",
+		enabled, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrUserNotFound))
+	}
+
+	return nil
+}
+
+// SetUserAvatar sets the key of a user's avatar in blob storage.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - avatar: key of the avatar, or an empty string to remove it
+//
+// Returns:
+//   - error: storage.ErrUserNotFound if the user doesn't exist, or another error if the operation fails
+func (s *Storage) SetUserAvatar(ctx context.Context, userID int64, avatar string) error {
+	const op = "storage.sqlite.SetUserAvatar"
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE users SET avatar = ?, updated_at = "+nowUnix+", version = version + 1 WHERE id = ?",
+		avatar, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrUserNotFound))
+	}
+
+	return nil
+}
+
+// SaveLoginChallenge persists a new pending login challenge.
+// Expired challenges are removed first.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - challenge: challenge to save; ID, AppName, and Status are ignored
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) SaveLoginChallenge(ctx context.Context, challenge *models.LoginChallenge) error {
+	const op = "storage.sqlite.SaveLoginChallenge"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		"DELETE FROM login_challenges WHERE expires_at <= ?",
+		challenge.CreatedAt.Unix(),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO login_challenges (challenge_hash, user_id, app_id, action, ip, user_agent, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		challenge.ChallengeHash, challenge.UserID, challenge.AppID, challenge.Action, challenge.IP, challenge.UserAgent,
+		challenge.CreatedAt.Unix(), challenge.ExpiresAt.Unix(),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// loginChallengeColumns lists the login_challenges columns scanned by scanLoginChallenge, in order.
+const loginChallengeColumns = "c.id, c.challenge_hash, c.user_id, c.app_id, COALESCE(a.name, ''), c.status, c.action, c.ip, c.user_agent, c.created_at, c.expires_at"
+
+// scanLoginChallenge scans a row selected with loginChallengeColumns.
+func scanLoginChallenge(row rowScanner) (*models.LoginChallenge, error) {
+	var (
+		challenge            models.LoginChallenge
+		createdAt, expiresAt int64
+	)
+
+	if err := row.Scan(
+		&challenge.ID, &challenge.ChallengeHash, &challenge.UserID, &challenge.AppID, &challenge.AppName,
+		&challenge.Status, &challenge.Action, &challenge.IP, &challenge.UserAgent, &createdAt, &expiresAt,
+	); err != nil {
+		return nil, err
+	}
+
+	challenge.CreatedAt = fromUnix(createdAt)
+	challenge.ExpiresAt = fromUnix(expiresAt)
+
+	return &challenge, nil
+}
+
+// LoginChallenge retrieves a login challenge by the hash of its ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - challengeHash: SHA-256 of the challenge ID
+//
+// Returns:
+//   - *models.LoginChallenge: the challenge if found
+//   - error: storage.ErrLoginChallengeNotFound if no challenge exists for the ID,
+//     or another error if the operation fails
+func (s *Storage) LoginChallenge(ctx context.Context, challengeHash []byte) (*models.LoginChallenge, error) {
+	const op = "storage.sqlite.LoginChallenge"
+
+	challenge, err := scanLoginChallenge(s.db.QueryRowContext(ctx,
+		"SELECT "+loginChallengeColumns+" FROM login_challenges c LEFT JOIN apps a ON a.id = c.app_id WHERE c.challenge_hash = ?",
+		challengeHash,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrLoginChallengeNotFound))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return challenge, nil
+}
+
+// PendingLoginChallenges lists a user's pending, unexpired login challenges, oldest first.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - now: current moment, used to skip expired challenges
+//
+// Returns:
+//   - []models.LoginChallenge: the pending challenges, with AppName set
+//   - error: non-nil if the operation fails
+func (s *Storage) PendingLoginChallenges(ctx context.Context, userID int64, now time.Time) ([]models.LoginChallenge, error) {
+	const op = "storage.sqlite.PendingLoginChallenges"
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+loginChallengeColumns+` FROM login_challenges c LEFT JOIN apps a ON a.id = c.app_id
+		WHERE c.user_id = ? AND c.status = ? AND c.expires_at > ? ORDER BY c.id`,
+		userID, models.LoginChallengePending, now.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var challenges []models.LoginChallenge
+
+	for rows.Next() {
+		challenge, err := scanLoginChallenge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		challenges = append(challenges, *challenge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return challenges, nil
+}
+
+// DecideLoginChallenge records a user's decision on one of their pending, unexpired login challenges.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - id: ID of the challenge
+//   - userID: ID of the user deciding; challenges of other users are not found
+//   - status: models.LoginChallengeApproved or models.LoginChallengeDenied
+//   - now: current moment, used to reject expired challenges
+//
+// Returns:
+//   - error: storage.ErrLoginChallengeNotFound if the user has no pending, unexpired challenge
+//     with the ID, or another error if the operation fails
+func (s *Storage) DecideLoginChallenge(ctx context.Context, id, userID int64, status string, now time.Time) error {
+	const op = "storage.sqlite.DecideLoginChallenge"
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE login_challenges SET status = ? WHERE id = ? AND user_id = ? AND status = ? AND expires_at > ?",
+		status, id, userID, models.LoginChallengePending, now.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrLoginChallengeNotFound))
+	}
+
+	return nil
+}
+
+// ConsumeLoginChallenge marks an approved login challenge as consumed,
+// so only one token is ever issued for it.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - id: ID of the challenge
+//
+// Returns:
+//   - error: storage.ErrLoginChallengeNotFound if the challenge is not approved
+//     or was already consumed, or another error if the operation fails
+func (s *Storage) ConsumeLoginChallenge(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.ConsumeLoginChallenge"
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE login_challenges SET status = ? WHERE id = ? AND status = ?",
+		models.LoginChallengeConsumed, id, models.LoginChallengeApproved,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrLoginChallengeNotFound))
+	}
+
+	return nil
+}
+
+// SaveRefreshToken persists a refresh token. Expired refresh tokens and sessions are removed first.
+// A token without a chain starts a new one, identified by the token's own ID, and the session
+// of the chain, recording the token's client.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - token: token to save; ID and RevokedAt are ignored, and ChainID is 0 for tokens issued at login
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) SaveRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	const op = "storage.sqlite.SaveRefreshToken"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	for _, table := range []string{"refresh_tokens", "sessions"} {
+		if _, err := tx.ExecContext(ctx,
+			"DELETE FROM "+table+" WHERE expires_at <= ?",
+			token.CreatedAt.Unix(),
+		); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := insertRefreshToken(ctx, tx, token); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// insertRefreshToken inserts token within tx, starting a new chain and its session if it has none.
+func insertRefreshToken(ctx context.Context, tx *sql.Tx, token *models.RefreshToken) error {
+	var authTime int64
+	if !token.AuthTime.IsZero() {
+		authTime = token.AuthTime.Unix()
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO refresh_tokens (token_hash, chain_id, user_id, app_id, auth_time, amr, acr, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		token.TokenHash, token.ChainID, token.UserID, token.AppID, authTime, strings.Join(token.Methods, ","), token.ACR,
+		token.CreatedAt.Unix(), token.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if token.ChainID != 0 {
+		return nil
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE refresh_tokens SET chain_id = id WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO sessions (id, user_id, app_id, ip, user_agent, created_at, last_used_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, token.UserID, token.AppID, token.IP, token.UserAgent,
+		token.CreatedAt.Unix(), token.CreatedAt.Unix(), token.ExpiresAt.Unix(),
+	)
+
+	return err
+}
+
+// RefreshToken retrieves a refresh token by its hash, whether or not it was revoked or expired.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - tokenHash: SHA-256 of the token
+//
+// Returns:
+//   - *models.RefreshToken: the token if found
+//   - error: storage.ErrRefreshTokenNotFound if no token exists for the hash,
+//     or another error if the operation fails
+func (s *Storage) RefreshToken(ctx context.Context, tokenHash []byte) (*models.RefreshToken, error) {
+	const op = "storage.sqlite.RefreshToken"
+
+	var (
+		token                                   models.RefreshToken
+		amr                                     string
+		authTime, createdAt, expiresAt, revoked int64
+	)
+
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, token_hash, chain_id, user_id, app_id, auth_time, amr, acr, created_at, expires_at, revoked_at
+		FROM refresh_tokens WHERE token_hash = ?`,
+		tokenHash,
+	).Scan(
+		&token.ID, &token.TokenHash, &token.ChainID, &token.UserID, &token.AppID, &authTime, &amr, &token.ACR,
+		&createdAt, &expiresAt, &revoked,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrRefreshTokenNotFound))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if amr != "" {
+		token.Methods = strings.Split(amr, ",")
+	}
+
+	token.AuthTime = fromUnix(authTime)
+	token.CreatedAt = fromUnix(createdAt)
+	token.ExpiresAt = fromUnix(expiresAt)
+	token.RevokedAt = fromUnix(revoked)
+
+	return &token, nil
+}
+
+// RotateRefreshToken revokes a refresh token and saves the next token of its chain,
+// so each refresh token is exchanged only once. The session of the chain is marked used.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - id: ID of the token being exchanged
+//   - next: token replacing it, with the chain of the exchanged token; ID and RevokedAt are ignored
+//
+// Returns:
+//   - error: storage.ErrRefreshTokenNotFound if the token does not exist or was already revoked,
+//     or another error if the operation fails
+func (s *Storage) RotateRefreshToken(ctx context.Context, id int64, next *models.RefreshToken) error {
+	const op = "storage.sqlite.RotateRefreshToken"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE refresh_tokens SET revoked_at = ? WHERE id = ? AND revoked_at = 0",
+		next.CreatedAt.Unix(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrRefreshTokenNotFound))
+	}
+
+	if err := insertRefreshToken(ctx, tx, next); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE sessions SET last_used_at = ? WHERE id = ?",
+		next.CreatedAt.Unix(), next.ChainID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeRefreshTokenChain revokes every unrevoked token of a refresh token chain,
+// e.g. when a token that was already exchanged is presented again.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - chainID: ID of the chain
+//   - at: moment of the revocation
+//
+// Returns:
+//   - error: non-nil if the operation fails; revoking a chain without unrevoked tokens is not an error
+func (s *Storage) RevokeRefreshTokenChain(ctx context.Context, chainID int64, at time.Time) error {
+	const op = "storage.sqlite.RevokeRefreshTokenChain"
+
+	if _, err := s.db.ExecContext(ctx,
+		"UPDATE refresh_tokens SET revoked_at = ? WHERE chain_id = ? AND revoked_at = 0",
+		at.Unix(), chainID,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// activeSessionsWhere filters sessions to the active ones: unexpired, with an unrevoked token in
+// their chain, and last used after the tokens of their user and app were revoked, as refresh
+// token exchanges require.
+const activeSessionsWhere = `s.expires_at > ?
+	AND EXISTS (SELECT 1 FROM refresh_tokens t WHERE t.chain_id = s.id AND t.revoked_at = 0)
+	AND s.last_used_at > (SELECT tokens_revoked_at FROM users WHERE id = s.user_id)
+	AND s.last_used_at > (SELECT tokens_revoked_at FROM apps WHERE id = s.app_id)`
+
+// UserSessions lists a user's active sessions, most recently used first.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - now: current moment, used to skip expired sessions
+//
+// Returns:
+//   - []models.Session: the active sessions, with AppName set
+//   - error: non-nil if the operation fails
+func (s *Storage) UserSessions(ctx context.Context, userID int64, now time.Time) ([]models.Session, error) {
+	const op = "storage.sqlite.UserSessions"
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT s.id, s.user_id, s.app_id, COALESCE(a.name, ''), s.ip, s.user_agent, s.created_at, s.last_used_at, s.expires_at
+		FROM sessions s LEFT JOIN apps a ON a.id = s.app_id
+		WHERE s.user_id = ? AND `+activeSessionsWhere+`
+		ORDER BY s.last_used_at DESC, s.id DESC`,
+		userID, now.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var sessions []models.Session
+
+	for rows.Next() {
+		var (
+			session                          models.Session
+			createdAt, lastUsedAt, expiresAt int64
+		)
+
+		if err := rows.Scan(
+			&session.ID, &session.UserID, &session.AppID, &session.AppName, &session.IP, &session.UserAgent,
+			&createdAt, &lastUsedAt, &expiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		session.CreatedAt = fromUnix(createdAt)
+		session.LastUsedAt = fromUnix(lastUsedAt)
+		session.ExpiresAt = fromUnix(expiresAt)
+
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes every unrevoked token of the refresh token chain of one of a user's
+// active sessions, ending the session.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - id: ID of the session
+//   - userID: ID of the user; sessions of other users are not found
+//   - now: moment of the revocation, also used to skip expired sessions
+//
+// Returns:
+//   - error: storage.ErrSessionNotFound if the user has no active session with the ID,
+//     or another error if the operation fails
+func (s *Storage) RevokeSession(ctx context.Context, id, userID int64, now time.Time) error {
+	const op = "storage.sqlite.RevokeSession"
+
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = ? WHERE chain_id = ? AND revoked_at = 0
+		AND EXISTS (SELECT 1 FROM sessions s WHERE s.id = ? AND s.user_id = ? AND `+activeSessionsWhere+`)`,
+		now.Unix(), id, id, userID, now.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrSessionNotFound))
+	}
+
+	return nil
+}
+
+// auditAppendAttempts is the number of times AppendAuditEvent tries to append an event
+// before giving up on a database that stays locked by other processes.
+const auditAppendAttempts = 5
+
+// AppendAuditEvent appends an event to the audit log, linking it to the latest event.
+// The latest event is read and the new one inserted in a single BEGIN IMMEDIATE transaction,
+// which holds the database's write lock from the start, so processes sharing the database
+// never link two events to the same predecessor. The append is retried when the database
+// stays locked past the busy timeout or the event's ID is already taken.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - event: event to append
+//   - link: sets the event's ID and hashes from the latest event, which is nil if the log is empty;
+//     it may be called once per attempt
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) AppendAuditEvent(ctx context.Context, event *models.AuditEvent, link func(last *models.AuditEvent)) error {
+	const op = "storage.sqlite.AppendAuditEvent"
+
+	var err error
+
+	for range auditAppendAttempts {
+		if err = s.appendAuditEvent(ctx, event, link); err == nil || !retryableAppend(err) {
+			break
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// appendAuditEvent makes a single attempt of AppendAuditEvent.
+func (s *Storage) appendAuditEvent(ctx context.Context, event *models.AuditEvent, link func(last *models.AuditEvent)) (err error) {
+	// database/sql has no way to pick the kind of transaction, so it is begun by hand
+	// on a connection of its own.
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			// The context may already be done, which must not leave the transaction open.
+			_, _ = conn.ExecContext(context.WithoutCancel(ctx), "ROLLBACK")
+		}
+	}()
+
+	events, err := auditEvents.query(ctx, conn, auditEvents.selectFrom()+" ORDER BY id DESC LIMIT 1")
+	if err != nil {
+		return err
+	}
+
+	var last *models.AuditEvent
+	if len(events) > 0 {
+		last = &events[0]
+	}
+
+	link(last)
+
+	if err := auditEvents.insert(ctx, conn, event); err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(ctx, "COMMIT")
+
+	return err
+}
+
+// retryableAppend reports whether an audit event append failed because of another
+// writer, so it may succeed when attempted again.
+func retryableAppend(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked ||
+		sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+}
+
+// AuditEvents retrieves audit events in ID order.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - afterID: only events with a greater ID are returned
+//   - limit: maximum number of events to return
+//
+// Returns:
+//   - []models.AuditEvent: the events, oldest first
+//   - error: non-nil if the operation fails
+func (s *Storage) AuditEvents(ctx context.Context, afterID int64, limit int) ([]models.AuditEvent, error) {
+	const op = "storage.sqlite.AuditEvents"
+
+	events, err := auditEvents.query(ctx, s.db, auditEvents.selectFrom()+" WHERE id > ? ORDER BY id LIMIT ?", afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// auditEvents maps the audit_events table to models.AuditEvent.
+var auditEvents = table[models.AuditEvent]{
+	name: "audit_events",
+	fields: []field[models.AuditEvent]{
+		{"id", func(e *models.AuditEvent) any { return &e.ID }, func(e *models.AuditEvent) any { return e.ID }},
+		{"at", func(e *models.AuditEvent) any { return unixTime{&e.At} }, func(e *models.AuditEvent) any { return e.At.Unix() }},
+		{"method", func(e *models.AuditEvent) any { return &e.Method }, func(e *models.AuditEvent) any { return e.Method }},
+		{"actor_user_id", func(e *models.AuditEvent) any { return &e.ActorUserID }, func(e *models.AuditEvent) any { return e.ActorUserID }},
+		{"target", func(e *models.AuditEvent) any { return &e.Target }, func(e *models.AuditEvent) any { return e.Target }},
+		{"code", func(e *models.AuditEvent) any { return &e.Code }, func(e *models.AuditEvent) any { return e.Code }},
+		{"prev_hash", func(e *models.AuditEvent) any { return &e.PrevHash }, func(e *models.AuditEvent) any { return e.PrevHash }},
+		{"hash", func(e *models.AuditEvent) any { return &e.Hash }, func(e *models.AuditEvent) any { return e.Hash }},
+		{"source_ip", func(e *models.AuditEvent) any { return &e.SourceIP }, func(e *models.AuditEvent) any { return e.SourceIP }},
+	},
+}
+
+// FreezeUser freezes a user's account and revokes every token issued to the user so far.
+// A back-channel logout is queued in the outbox for every app with a logout URI.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - at: moment the account is frozen
+//
+// Returns:
+//   - error: storage.ErrUserNotFound if the user doesn't exist, or another error if the operation fails
+func (s *Storage) FreezeUser(ctx context.Context, userID int64, at time.Time) error {
+	const op = "storage.sqlite.FreezeUser"
+
+	if err := s.revokeUserTokens(ctx, userID, at,
+		"UPDATE users SET frozen_at = ?1, tokens_revoked_at = ?1, updated_at = ?1, version = version + 1 WHERE id = ?2",
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeUserTokens revokes every token issued to a user so far, e.g. when the user logs out.
+// A back-channel logout is queued in the outbox for every app with a logout URI.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - at: moment of the revocation; tokens issued at or before it are rejected
+//
+// Returns:
+//   - error: storage.ErrUserNotFound if the user doesn't exist, or another error if the operation fails
+func (s *Storage) RevokeUserTokens(ctx context.Context, userID int64, at time.Time) error {
+	const op = "storage.sqlite.RevokeUserTokens"
+
+	if err := s.revokeUserTokens(ctx, userID, at,
+		"UPDATE users SET tokens_revoked_at = ?1, updated_at = ?1, version = version + 1 WHERE id = ?2",
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// revokeUserTokens runs query, an update of the user revoking their tokens with ?1 bound
+// to at and ?2 to userID, and queues the back-channel logouts in the same transaction.
+func (s *Storage) revokeUserTokens(ctx context.Context, userID int64, at time.Time, query string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, at.Unix(), userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return notFound(storage.ErrUserNotFound)
+	}
+
+	if err := enqueueBackchannelLogouts(ctx, tx, userID, at); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// enqueueBackchannelLogouts queues within tx a back-channel logout of a user for every app
+// with a logout URI. The apps are read when the message is delivered, so one whose URI is
+// cleared meanwhile is skipped then.
+func enqueueBackchannelLogouts(ctx context.Context, tx *sql.Tx, userID int64, at time.Time) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO outbox (topic, payload, created_at, next_attempt_at)
+		SELECT ?1, json_object('app_id', id, 'user_id', ?2), ?3, ?3 FROM apps WHERE backchannel_logout_uri != '' ORDER BY id`,
+		models.TopicBackchannelLogout, userID, at.Unix(),
+	)
+
+	return err
+}
+
+// SaveUnfreezeToken stores the hash of a newly issued unfreeze token.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user the token was issued to
+//   - tokenHash: SHA-256 hash of the token; the token itself is never stored
+//   - createdAt: moment the token was issued
+//   - expiresAt: moment after which the token can no longer be used
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) SaveUnfreezeToken(ctx context.Context, userID int64, tokenHash []byte, createdAt time.Time, expiresAt time.Time) error {
+	const op = "storage.sqlite.SaveUnfreezeToken"
+
+	stmt, err := s.db.Prepare("INSERT INTO unfreeze_tokens (user_id, token_hash, created_at, expires_at) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, userID, tokenHash, createdAt.Unix(), expiresAt.Unix()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CountUnfreezeTokens counts the unfreeze tokens issued to a user since the given moment.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - since: start of the counting window
+//
+// Returns:
+//   - int: number of tokens issued in the window, including used and expired ones
+//   - error: non-nil if the operation fails
+func (s *Storage) CountUnfreezeTokens(ctx context.Context, userID int64, since time.Time) (int, error) {
+	const op = "storage.sqlite.CountUnfreezeTokens"
+
+	stmt, err := s.db.Prepare("SELECT COUNT(*) FROM unfreeze_tokens WHERE user_id = ? AND created_at >= ?")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	var count int
+
+	if err := stmt.QueryRowContext(ctx, userID, since.Unix()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// UnfreezeUser consumes an unfreeze token, replaces the user's password hash, and unfreezes the account.
+// All happen in one transaction, and every other outstanding unfreeze token of the user is revoked.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - tokenHash: SHA-256 hash of the presented token
+//   - passHash: bcrypt hash of the new password
+//   - now: moment used to check expiry and mark the token as used
+//
+// Returns:
+//   - int64: ID of the user whose account was unfrozen
+//   - error: storage.ErrUnfreezeTokenNotFound if the token does not exist, was already used,
+//     has expired, or the account is not frozen; or another error if the operation fails
+func (s *Storage) UnfreezeUser(ctx context.Context, tokenHash []byte, passHash []byte, now time.Time) (int64, error) {
+	const op = "storage.sqlite.UnfreezeUser"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	var userID int64
+
+	err = tx.QueryRowContext(ctx,
+		"UPDATE unfreeze_tokens SET used_at = ? WHERE token_hash = ? AND used_at IS NULL AND expires_at > ? RETURNING user_id",
+		now.Unix(), tokenHash, now.Unix(),
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, notFound(storage.ErrUnfreezeTokenNotFound))
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE users SET pass_hash = ?, frozen_at = 0, updated_at = ?, version = version + 1 WHERE id = ? AND frozen_at != 0",
+		passHash, now.Unix(), userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return 0, fmt.Errorf("%s: %w", op, notFound(storage.ErrUnfreezeTokenNotFound))
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"DELETE FROM unfreeze_tokens WHERE user_id = ? AND used_at IS NULL",
+		userID,
+	); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
+
+// SaveSupportToken stores the hash of a newly issued support token.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - token: token to store; its ID and UserPublicID are ignored
+//   - tokenHash: SHA-256 hash of the token; the token itself is never stored
+//
+// Returns:
+//   - int64: ID of the stored token
+//   - error: non-nil if the operation fails
+func (s *Storage) SaveSupportToken(ctx context.Context, token *models.SupportToken, tokenHash []byte) (int64, error) {
+	const op = "storage.sqlite.SaveSupportToken"
+
+	stmt, err := s.db.Prepare(
+		"INSERT INTO support_tokens (token_hash, issued_by, user_id, app_id, reason, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx,
+		tokenHash, token.IssuedBy, token.UserID, token.AppID, token.Reason, token.CreatedAt.Unix(), token.ExpiresAt.Unix(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// SupportTokenByHash retrieves a support token by the hash of its value,
+// including expired and revoked ones.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - tokenHash: SHA-256 hash of the token
+//
+// Returns:
+//   - *models.SupportToken: the token, with the public ID of the user it is scoped to
+//   - error: storage.ErrSupportTokenNotFound if no token has the hash, or another error if the operation fails
+func (s *Storage) SupportTokenByHash(ctx context.Context, tokenHash []byte) (*models.SupportToken, error) {
+	const op = "storage.sqlite.SupportTokenByHash"
+
+	stmt, err := s.db.Prepare(`
+		SELECT t.id, t.issued_by, t.user_id, COALESCE(u.public_id, CAST(u.id AS TEXT), ''), t.app_id, t.reason, t.created_at, t.expires_at, t.revoked_at
+		FROM support_tokens t
+		LEFT JOIN users u ON u.id = t.user_id
+		WHERE t.token_hash = ?`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	var (
+		token                           models.SupportToken
+		createdAt, expiresAt, revokedAt int64
+	)
+
+	err = stmt.QueryRowContext(ctx, tokenHash).Scan(
+		&token.ID, &token.IssuedBy, &token.UserID, &token.UserPublicID, &token.AppID, &token.Reason,
+		&createdAt, &expiresAt, &revokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrSupportTokenNotFound))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token.CreatedAt = fromUnix(createdAt)
+	token.ExpiresAt = fromUnix(expiresAt)
+	token.RevokedAt = fromUnix(revokedAt)
+
+	return &token, nil
+}
+
+// RevokeSupportToken revokes a support token. Revoking an already revoked token keeps
+// the original revocation time.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - tokenID: ID of the token
+//   - at: moment of the revocation
+//
+// Returns:
+//   - error: storage.ErrSupportTokenNotFound if no token has the ID, or another error if the operation fails
+func (s *Storage) RevokeSupportToken(ctx context.Context, tokenID int64, at time.Time) error {
+	const op = "storage.sqlite.RevokeSupportToken"
+
+	stmt, err := s.db.Prepare(
+		"UPDATE support_tokens SET revoked_at = CASE WHEN revoked_at = 0 THEN ? ELSE revoked_at END WHERE id = ?",
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	result, err := stmt.ExecContext(ctx, at.Unix(), tokenID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrSupportTokenNotFound))
+	}
+
+	return nil
+}
+
+// UserAttributes returns the custom attributes of a user.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//
+// Returns:
+//   - map[string]string: attribute values by key; empty if the user has none
+//   - error: storage.ErrUserNotFound if no user exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) UserAttributes(ctx context.Context, userID int64) (map[string]string, error) {
+	const op = "storage.sqlite.UserAttributes"
+
+	var exists bool
+
+	if err := s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)", userID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrUserNotFound))
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT key, value FROM user_attributes WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	attrs := make(map[string]string)
+
+	for rows.Next() {
+		var key, value string
+
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		attrs[key] = value
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return attrs, nil
+}
+
+// SetUserAttributes replaces the custom attributes of a user.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - attrs: attribute values by key; empty to remove every attribute
+//
+// Returns:
+//   - error: storage.ErrUserNotFound if no user exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) SetUserAttributes(ctx context.Context, userID int64, attrs map[string]string) error {
+	const op = "storage.sqlite.SetUserAttributes"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	var exists bool
+
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)", userID).Scan(&exists); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrUserNotFound))
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_attributes WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO user_attributes (user_id, key, value) VALUES (?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	for key, value := range attrs {
+		if _, err := stmt.ExecContext(ctx, userID, key, value); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UsersByAttribute returns the users having an attribute set to a value, in order of ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - key: attribute key
+//   - value: attribute value, matched exactly
+//   - afterID: only users with a greater ID are returned; 0 to start from the first user
+//   - limit: maximum number of users returned
+//
+// Returns:
+//   - []*models.User: matching users; empty if there are none
+//   - error: non-nil if the operation fails
+func (s *Storage) UsersByAttribute(ctx context.Context, key string, value string, afterID int64, limit int) ([]*models.User, error) {
+	const op = "storage.sqlite.UsersByAttribute"
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+userColumns+" FROM users WHERE id IN "+
+			"(SELECT user_id FROM user_attributes WHERE key = ? AND value = ? AND user_id > ?) ORDER BY id LIMIT ?",
+		key, value, afterID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var users []*models.User
+
+	for rows.Next() {
+		user, err := s.scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// UsersByEmailPrefix returns the users whose email starts with a prefix, ignoring ASCII
+// case, in order of email. It uses the index on the lowercase emails.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - prefix: start of the emails
+//   - labels: labels the users must all have; empty to not filter by labels
+//   - limit: maximum number of users returned
+//
+// Returns:
+//   - []*models.User: matching users; empty if there are none
+//   - error: storage.ErrEmailsEncrypted if emails are stored encrypted,
+//     or another error if the operation fails
+func (s *Storage) UsersByEmailPrefix(ctx context.Context, prefix string, labels map[string]string, limit int) ([]*models.User, error) {
+	const op = "storage.sqlite.UsersByEmailPrefix"
+
+	if s.pii != nil {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrEmailsEncrypted)
+	}
+
+	filter, filterArgs := labelFilter("id", "user_labels", "user_id", labels, 3)
+
+	// U+10FFFF sorts after every character, so the range holds exactly the emails with the prefix.
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+userColumns+" FROM users WHERE lower(email) >= lower(?1) AND lower(email) < lower(?1) || char(1114111)"+filter+
+			" ORDER BY lower(email), id LIMIT ?2",
+		append([]any{prefix, limit}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var users []*models.User
+
+	for rows.Next() {
+		user, err := s.scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// UsersByEmailSimilarity returns the users whose email is similar to a query, most similar
+// first. Similarity is the share of distinct trigrams of the lowercase query and email that
+// both have, as in the pg_trgm extension of PostgreSQL, so typos and missing parts lower it
+// only a little. Queries shorter than three characters have no trigrams and match nothing.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - query: text to compare the emails with
+//   - minSimilarity: lowest similarity returned, between 0 and 1
+//   - labels: labels the users must all have; empty to not filter by labels
+//   - limit: maximum number of users returned
+//
+// Returns:
+//   - []*models.User: matching users; empty if there are none
+//   - error: storage.ErrEmailsEncrypted if emails are stored encrypted,
+//     or another error if the operation fails
+func (s *Storage) UsersByEmailSimilarity(
+	ctx context.Context,
+	query string,
+	minSimilarity float64,
+	labels map[string]string,
+	limit int,
+) ([]*models.User, error) {
+	const op = "storage.sqlite.UsersByEmailSimilarity"
+
+	if s.pii != nil {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrEmailsEncrypted)
+	}
+
+	filter, filterArgs := labelFilter("users.id", "user_labels", "user_id", labels, 4)
+
+	rows, err := s.db.QueryContext(ctx,
+		"WITH q (trigram) AS ("+
+			"SELECT DISTINCT substr(lower(?1), pos, 3) FROM trigram_positions WHERE pos <= length(?1) - 2"+
+			"), shared (user_id, n) AS ("+
+			"SELECT t.user_id, COUNT(*) FROM q JOIN user_email_trigrams t ON t.trigram = q.trigram GROUP BY t.user_id"+
+			"), scored (user_id, similarity) AS ("+
+			"SELECT user_id, CAST(n AS REAL) / ((SELECT COUNT(*) FROM q) + "+
+			"(SELECT COUNT(*) FROM user_email_trigrams e WHERE e.user_id = shared.user_id) - n) FROM shared"+
+			") SELECT "+userColumns+" FROM scored JOIN users ON users.id = scored.user_id "+
+			"WHERE scored.similarity >= ?2"+filter+" ORDER BY scored.similarity DESC, users.id LIMIT ?3",
+		append([]any{query, minSimilarity, limit}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var users []*models.User
+
+	for rows.Next() {
+		user, err := s.scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// UserLabels returns the labels of a user.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//
+// Returns:
+//   - map[string]string: label values by key; empty if the user has none
+//   - error: storage.ErrUserNotFound if no user exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) UserLabels(ctx context.Context, userID int64) (map[string]string, error) {
+	const op = "storage.sqlite.UserLabels"
+
+	var exists bool
+
+	if err := s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)", userID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrUserNotFound))
+	}
+
+	labels, err := queryLabels(ctx, s.db, "SELECT key, value FROM user_labels WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return labels, nil
+}
+
+// SetUserLabels replaces the labels of a user.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - labels: label values by key; empty to remove every label
+//
+// Returns:
+//   - error: storage.ErrUserNotFound if no user exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) SetUserLabels(ctx context.Context, userID int64, labels map[string]string) error {
+	const op = "storage.sqlite.SetUserLabels"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	var exists bool
+
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)", userID).Scan(&exists); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrUserNotFound))
+	}
+
+	if err := replaceLabels(ctx, tx, "user_labels", "user_id", userID, labels); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UserAtTime reconstructs the state of a user at a point in time from the user history,
+// which triggers keep in sync with the users, app_members, app_owners, user_attributes,
+// and user_labels tables.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//   - at: point in time; precise to the second
+//
+// Returns:
+//   - *models.UserSnapshot: state of the user at the time
+//   - error: storage.ErrUserNotFound if no user exists with the ID, storage.ErrUserHistoryNotFound
+//     if the user was created, or history started being recorded, after the time,
+//     or another error if the operation fails
+func (s *Storage) UserAtTime(ctx context.Context, userID int64, at time.Time) (*models.UserSnapshot, error) {
+	const op = "storage.sqlite.UserAtTime"
+
+	var exists bool
+
+	if err := s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)", userID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrUserNotFound))
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT kind, key, value FROM user_history
+		WHERE user_id = ?1 AND valid_from <= ?2 AND (valid_to IS NULL OR valid_to > ?2)`,
+		userID, at.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	snapshot := &models.UserSnapshot{
+		UserID:     userID,
+		At:         time.Unix(at.Unix(), 0),
+		Attributes: make(map[string]string),
+		Labels:     make(map[string]string),
+	}
+
+	var recorded bool
+
+	for rows.Next() {
+		var kind, key, value string
+
+		if err := rows.Scan(&kind, &key, &value); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := applyHistory(snapshot, kind, key, value); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		recorded = recorded || kind == "account"
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !recorded {
+		return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrUserHistoryNotFound))
+	}
+
+	slices.Sort(snapshot.MemberOf)
+	slices.Sort(snapshot.OwnerOf)
+
+	return snapshot, nil
+}
+
+// applyHistory sets the fact of a user_history row on a snapshot.
+func applyHistory(snapshot *models.UserSnapshot, kind, key, value string) error {
+	switch kind {
+	case "account":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("account %s: %w", key, err)
+		}
+
+		switch key {
+		case "is_admin":
+			snapshot.IsAdmin = n != 0
+		case "frozen_at":
+			if n != 0 {
+				snapshot.FrozenAt = time.Unix(n, 0)
+			}
+		case "merged_into":
+			snapshot.MergedInto = n
+		case "push_mfa":
+			snapshot.PushMFA = n != 0
+		}
+	case "member", "owner":
+		appID, err := strconv.ParseInt(key, 10, 32)
+		if err != nil {
+			return fmt.Errorf("%s app: %w", kind, err)
+		}
+
+		if kind == "member" {
+			snapshot.MemberOf = append(snapshot.MemberOf, int32(appID))
+		} else {
+			snapshot.OwnerOf = append(snapshot.OwnerOf, int32(appID))
+		}
+	case "attribute":
+		snapshot.Attributes[key] = value
+	case "label":
+		snapshot.Labels[key] = value
+	}
+
+	return nil
+}
+
+// AppLabels returns the labels of an application.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//
+// Returns:
+//   - map[string]string: label values by key; empty if the app has none
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) AppLabels(ctx context.Context, appID int32) (map[string]string, error) {
+	const op = "storage.sqlite.AppLabels"
+
+	var exists bool
+
+	if err := s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM apps WHERE id = ?)", appID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+	}
+
+	labels, err := queryLabels(ctx, s.db, "SELECT key, value FROM app_labels WHERE app_id = ?", appID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return labels, nil
+}
+
+// SetAppLabels replaces the labels of an application.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - labels: label values by key; empty to remove every label
+//
+// Returns:
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) SetAppLabels(ctx context.Context, appID int32, labels map[string]string) error {
+	const op = "storage.sqlite.SetAppLabels"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	var exists bool
+
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM apps WHERE id = ?)", appID).Scan(&exists); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+	}
+
+	if err := replaceLabels(ctx, tx, "app_labels", "app_id", int64(appID), labels); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Apps returns the applications having every given label, in order of ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - labels: labels the apps must all have; empty to return every app
+//   - afterID: only apps with a greater ID are returned; 0 to start from the first app
+//   - limit: maximum number of apps returned
+//
+// Returns:
+//   - []*models.App: matching apps; empty if there are none
+//   - error: non-nil if the operation fails
+func (s *Storage) Apps(ctx context.Context, labels map[string]string, afterID int32, limit int) ([]*models.App, error) {
+	const op = "storage.sqlite.Apps"
+
+	filter, filterArgs := labelFilter("id", "app_labels", "app_id", labels, 3)
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+appColumns+" FROM apps WHERE id > ?1"+filter+" ORDER BY id LIMIT ?2",
+		append([]any{afterID, limit}, filterArgs...)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var apps []*models.App
+
+	for rows.Next() {
+		app, err := scanApp(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		apps = append(apps, app)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return apps, nil
+}
+
+// queryLabels reads the key and value rows returned by a query into a map.
+func queryLabels(ctx context.Context, db *sql.DB, query string, args ...any) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	labels := make(map[string]string)
+
+	for rows.Next() {
+		var key, value string
+
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+
+		labels[key] = value
+	}
+
+	return labels, rows.Err()
+}
+
+// replaceLabels replaces the rows of a label table owned by a user or app.
+func replaceLabels(ctx context.Context, tx *sql.Tx, table, ownerColumn string, ownerID int64, labels map[string]string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE "+ownerColumn+" = ?", ownerID); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO "+table+" ("+ownerColumn+", key, value) VALUES (?, ?, ?)")
+	if err != nil {
+		return err
+	}
+
+	defer stmt.Close()
+
+	for key, value := range labels {
+		if _, err := stmt.ExecContext(ctx, ownerID, key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// labelFilter returns a condition, to append to a WHERE clause, that holds for the records
+// whose ID column matches an owner having every label in a label table, and its arguments.
+// The arguments are numbered from first on, so they can follow the other arguments of the
+// query. The condition is empty if there are no labels.
+func labelFilter(idColumn, table, ownerColumn string, labels map[string]string, first int) (string, []any) {
+	if len(labels) == 0 {
+		return "", nil
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	conditions := make([]string, 0, len(keys))
+	args := make([]any, 0, 2*len(keys))
+
+	for i, key := range keys {
+		n := first + 2*i
+		conditions = append(conditions, "(key = ?"+strconv.Itoa(n)+" AND value = ?"+strconv.Itoa(n+1)+")")
+		args = append(args, key, labels[key])
+	}
+
+	// Owners have at most one row per key, so matching every label means one row per label.
+	return " AND " + idColumn + " IN (SELECT " + ownerColumn + " FROM " + table + " WHERE " + strings.Join(conditions, " OR ") +
+		" GROUP BY " + ownerColumn + " HAVING COUNT(*) = " + strconv.Itoa(len(keys)) + ")", args
+}
+
+// GrantAppAccess makes a user a member of an application. Granting access to
+// an existing member keeps the original grant time.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - userID: ID of the user
+//   - at: moment of the grant
+//
+// Returns:
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     storage.ErrUserNotFound if no user exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) GrantAppAccess(ctx context.Context, appID int32, userID int64, at time.Time) error {
+	const op = "storage.sqlite.GrantAppAccess"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	if err := appAndUserExist(ctx, tx, appID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT OR IGNORE INTO app_members (app_id, user_id, granted_at) VALUES (?, ?, ?)",
+		appID, userID, at.Unix(),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeAppAccess removes a user from the members of an application.
+// Revoking access of a user who is not a member succeeds.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - userID: ID of the user
+//
+// Returns:
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     storage.ErrUserNotFound if no user exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) RevokeAppAccess(ctx context.Context, appID int32, userID int64) error {
+	const op = "storage.sqlite.RevokeAppAccess"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	if err := appAndUserExist(ctx, tx, appID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM app_members WHERE app_id = ? AND user_id = ?", appID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// IsAppMember reports whether a user was granted access to an application.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - userID: ID of the user
+//
+// Returns:
+//   - bool: true if the user is a member of the application
+//   - error: non-nil if the operation fails
+func (s *Storage) IsAppMember(ctx context.Context, appID int32, userID int64) (bool, error) {
+	const op = "storage.sqlite.IsAppMember"
+
+	var member bool
+
+	if err := s.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM app_members WHERE app_id = ? AND user_id = ?)", appID, userID,
+	).Scan(&member); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return member, nil
+}
+
+// SaveAppFamily creates a new app family.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - name: family name (must be unique)
+//   - secret: secret used to sign tokens issued for the apps in the family (must be unique)
+//   - createdAt: moment of creation
+//
+// Returns:
+//   - int32: ID of the newly created family
+//   - error: storage.ErrAppFamilyExists if a family with the name or secret already exists,
+//     or another error if the operation fails
+func (s *Storage) SaveAppFamily(ctx context.Context, name string, secret string, createdAt time.Time) (int32, error) {
+	const op = "storage.sqlite.SaveAppFamily"
+
+	result, err := s.db.ExecContext(ctx,
+		"INSERT INTO app_families (name, secret, created_at) VALUES (?, ?, ?)",
+		name, secret, createdAt.Unix(),
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrAppFamilyExists)
+		}
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int32(id), nil
+}
+
+// AppFamily returns an app family along with the IDs of its apps.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - familyID: ID of the family
+//
+// Returns:
+//   - *models.AppFamily: the family
+//   - error: storage.ErrAppFamilyNotFound if no family exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) AppFamily(ctx context.Context, familyID int32) (*models.AppFamily, error) {
+	const op = "storage.sqlite.AppFamily"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	var (
+		family    models.AppFamily
+		createdAt int64
+	)
+
+	if err := tx.QueryRowContext(ctx,
+		"SELECT id, name, secret, created_at FROM app_families WHERE id = ?", familyID,
+	).Scan(&family.ID, &family.Name, &family.Secret, &createdAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrAppFamilyNotFound))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	family.CreatedAt = fromUnix(createdAt)
+
+	rows, err := tx.QueryContext(ctx, "SELECT id FROM apps WHERE family_id = ? ORDER BY id", familyID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var appID int32
+
+		if err := rows.Scan(&appID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		family.AppIDs = append(family.AppIDs, appID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &family, nil
+}
+
+// DeleteAppFamily removes an app family that no longer has apps.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - familyID: ID of the family
+//
+// Returns:
+//   - error: storage.ErrAppFamilyNotFound if no family exists with the ID,
+//     storage.ErrAppFamilyNotEmpty if apps still belong to the family,
+//     or another error if the operation fails
+func (s *Storage) DeleteAppFamily(ctx context.Context, familyID int32) error {
+	const op = "storage.sqlite.DeleteAppFamily"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	if err := appFamilyExists(ctx, tx, familyID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var empty bool
+
+	if err := tx.QueryRowContext(ctx,
+		"SELECT NOT EXISTS (SELECT 1 FROM apps WHERE family_id = ?)", familyID,
+	).Scan(&empty); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !empty {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppFamilyNotEmpty)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM app_families WHERE id = ?", familyID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MergeUsers moves the memberships, attributes, login history, and support tokens of
+// a duplicate user to the primary one, and leaves the duplicate as a tombstone pointing
+// to it with every token revoked. The primary user becomes an admin if the duplicate was one.
+// Back-channel logouts of the duplicate are queued in the outbox.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - primaryID: ID of the user to keep
+//   - duplicateID: ID of the user merged into the primary one
+//   - at: moment of the merge
+//
+// Returns:
+//   - *models.User: the primary user after the merge
+//   - error: storage.ErrUserNotFound if either user doesn't exist,
+//     storage.ErrUserMerged if either user was already merged into another one,
+//     or another error if the operation fails
+func (s *Storage) MergeUsers(ctx context.Context, primaryID int64, duplicateID int64, at time.Time) (*models.User, error) {
+	const op = "storage.sqlite.MergeUsers"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	for _, userID := range []int64{primaryID, duplicateID} {
+		var mergedInto int64
+
+		if err := tx.QueryRowContext(ctx, "SELECT merged_into FROM users WHERE id = ?", userID).Scan(&mergedInto); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrUserNotFound))
+			}
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if mergedInto != 0 {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrUserMerged)
+		}
+	}
+
+	// ?1 is the primary user and ?2 the duplicate. Where both have a row, the primary's is kept.
+	for _, query := range []string{
+		"INSERT OR IGNORE INTO app_members (app_id, user_id, granted_at) SELECT app_id, ?1, granted_at FROM app_members WHERE user_id = ?2",
+		"DELETE FROM app_members WHERE user_id = ?2",
+		"INSERT OR IGNORE INTO app_owners (app_id, user_id, added_at) SELECT app_id, ?1, added_at FROM app_owners WHERE user_id = ?2",
+		"DELETE FROM app_owners WHERE user_id = ?2",
+		"INSERT OR IGNORE INTO user_attributes (user_id, key, value) SELECT ?1, key, value FROM user_attributes WHERE user_id = ?2",
+		"DELETE FROM user_attributes WHERE user_id = ?2",
+		"INSERT OR IGNORE INTO user_labels (user_id, key, value) SELECT ?1, key, value FROM user_labels WHERE user_id = ?2",
+		"DELETE FROM user_labels WHERE user_id = ?2",
+		"INSERT OR IGNORE INTO login_days (day, user_id) SELECT day, ?1 FROM login_days WHERE user_id = ?2",
+		"DELETE FROM login_days WHERE user_id = ?2",
+		"UPDATE support_tokens SET issued_by = ?1 WHERE issued_by = ?2",
+		"UPDATE support_tokens SET user_id = ?1 WHERE user_id = ?2",
+		// Outstanding credentials of the duplicate would let it sign in again.
+		"DELETE FROM password_reset_tokens WHERE user_id = ?2",
+		"DELETE FROM unfreeze_tokens WHERE user_id = ?2",
+		"DELETE FROM device_authorizations WHERE user_id = ?2",
+	} {
+		if _, err := tx.ExecContext(ctx, query, primaryID, duplicateID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	user, err := s.scanUser(tx.QueryRowContext(ctx,
+		`UPDATE users SET is_admin = is_admin OR (SELECT is_admin FROM users WHERE id = ?), updated_at = ?, version = version + 1
+		WHERE id = ? RETURNING `+userColumns,
+		duplicateID, at.Unix(), primaryID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE users SET merged_into = ?, is_admin = FALSE, tokens_revoked_at = ?, updated_at = ?, version = version + 1 WHERE id = ?",
+		primaryID, at.Unix(), at.Unix(), duplicateID,
+	); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := enqueueBackchannelLogouts(ctx, tx, duplicateID, at); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// SaveRegistration persists a new pending async registration and deletes expired ones.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - reg: registration to save; its ID is ignored
+//
+// Returns:
+//   - int64: ID of the saved registration
+//   - error: non-nil if the operation fails
+func (s *Storage) SaveRegistration(ctx context.Context, reg *models.Registration) (int64, error) {
+	const op = "storage.sqlite.SaveRegistration"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		"DELETE FROM registrations WHERE expires_at <= ?",
+		reg.CreatedAt.Unix(),
+	); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var id int64
+
+	if err := tx.QueryRowContext(ctx,
+		`INSERT INTO registrations (registration_id_hash, created_at, expires_at)
+		VALUES (?, ?, ?) RETURNING id`,
+		reg.RegistrationIDHash, reg.CreatedAt.Unix(), reg.ExpiresAt.Unix(),
+	).Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// Registration retrieves an async registration by the hash of its registration ID,
+// along with the public ID of the user once the account is created.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - registrationIDHash: SHA-256 of the registration ID
+//
+// Returns:
+//   - *models.Registration: the registration if found
+//   - error: storage.ErrRegistrationNotFound if no registration exists for the ID,
+//     or another error if the operation fails
+func (s *Storage) Registration(ctx context.Context, registrationIDHash []byte) (*models.Registration, error) {
+	const op = "storage.sqlite.Registration"
+
+	var (
+		reg                  models.Registration
+		createdAt, expiresAt int64
+	)
+
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT r.id, r.registration_id_hash, COALESCE(r.user_id, 0), COALESCE(u.public_id, CAST(u.id AS TEXT), ''),
+			r.status, r.failure_reason, r.created_at, r.expires_at
+		FROM registrations r LEFT JOIN users u ON u.id = r.user_id
+		WHERE r.registration_id_hash = ?`,
+		registrationIDHash,
+	).Scan(
+		&reg.ID, &reg.RegistrationIDHash, &reg.UserID, &reg.PublicID,
+		&reg.Status, &reg.FailureReason, &createdAt, &expiresAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrRegistrationNotFound))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	reg.CreatedAt = fromUnix(createdAt)
+	reg.ExpiresAt = fromUnix(expiresAt)
+
+	return &reg, nil
+}
+
+// FinishRegistration records the outcome of a pending async registration.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - id: ID of the registration
+//   - userID: ID of the created user; 0 if the registration failed
+//   - status: models.RegistrationCompleted or models.RegistrationFailed
+//   - failureReason: why the registration failed; empty if it completed
+//
+// Returns:
+//   - error: storage.ErrRegistrationNotFound if the registration is no longer pending,
+//     or another error if the operation fails
+func (s *Storage) FinishRegistration(ctx context.Context, id int64, userID int64, status string, failureReason string) error {
+	const op = "storage.sqlite.FinishRegistration"
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE registrations SET user_id = NULLIF(?, 0), status = ?, failure_reason = ? WHERE id = ? AND status = ?",
+		userID, status, failureReason, id, models.RegistrationPending,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrRegistrationNotFound))
+	}
+
+	return nil
+}
+
+// RevokeAppTokens revokes every token issued for an app so far.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the app
+//   - at: moment of the revocation; tokens issued at or before it are rejected
+//
+// Returns:
+//   - error: storage.ErrAppNotFound if the app doesn't exist, or another error if the operation fails
+func (s *Storage) RevokeAppTokens(ctx context.Context, appID int32, at time.Time) error {
+	const op = "storage.sqlite.RevokeAppTokens"
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE apps SET tokens_revoked_at = ?, updated_at = ?, version = version + 1 WHERE id = ?",
+		at.Unix(), at.Unix(), appID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+	}
+
+	return nil
+}
+
+// DeleteAppDeviceAuthorizations removes up to limit device authorizations of an app that
+// have not been exchanged for a token yet. Callers repeat it until fewer than limit are
+// removed, so large purges do not hold the write lock for long.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the app
+//   - limit: maximum number of authorizations to remove
+//
+// Returns:
+//   - int64: number of authorizations removed
+//   - error: non-nil if the operation fails
+func (s *Storage) DeleteAppDeviceAuthorizations(ctx context.Context, appID int32, limit int) (int64, error) {
+	const op = "storage.sqlite.DeleteAppDeviceAuthorizations"
+
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM device_authorizations WHERE id IN (
+			SELECT id FROM device_authorizations WHERE app_id = ? AND status != ? LIMIT ?
+		)`,
+		appID, models.DeviceAuthorizationConsumed, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// AppBySecret retrieves the application using a secret.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - secret: secret of the application
+//
+// Returns:
+//   - *models.App: application information if found
+//   - error: storage.ErrAppNotFound if no application uses the secret,
+//     or another error if the operation fails
+func (s *Storage) AppBySecret(ctx context.Context, secret string) (*models.App, error) {
+	const op = "storage.sqlite.AppBySecret"
+
+	app, err := scanApp(s.db.QueryRowContext(ctx, "SELECT "+appColumns+" FROM apps WHERE secret = ?", secret))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return app, nil
+}
+
+// ReplaceLeakedAppSecret replaces an app's secret, revokes every token issued for the app
+// so far, and removes its device authorizations that were not exchanged for a token yet,
+// in a single transaction. The secret is only replaced if it is still the leaked one.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the app
+//   - leaked: secret reported as leaked
+//   - secret: new secret
+//   - at: moment of the revocation; tokens issued at or before it are rejected
+//
+// Returns:
+//   - int64: number of device authorizations removed
+//   - error: storage.ErrAppNotFound if the app doesn't exist or no longer uses the leaked secret,
+//     or another error if the operation fails
+func (s *Storage) ReplaceLeakedAppSecret(ctx context.Context, appID int32, leaked string, secret string, at time.Time) (int64, error) {
+	const op = "storage.sqlite.ReplaceLeakedAppSecret"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE apps SET secret = ?, tokens_revoked_at = ?, updated_at = ?, version = version + 1 WHERE id = ? AND secret = ?",
+		secret, at.Unix(), at.Unix(), appID, leaked,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return 0, fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+	}
+
+	result, err = tx.ExecContext(ctx,
+		"DELETE FROM device_authorizations WHERE app_id = ? AND status != ?",
+		appID, models.DeviceAuthorizationConsumed,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return purged, nil
+}
+
+// AddAppOwner makes a user an owner of an application. Adding an owner again succeeds.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - userID: ID of the user
+//   - at: moment the user becomes an owner
+//
+// Returns:
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     storage.ErrUserNotFound if no user exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) AddAppOwner(ctx context.Context, appID int32, userID int64, at time.Time) error {
+	const op = "storage.sqlite.AddAppOwner"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	if err := appAndUserExist(ctx, tx, appID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT OR IGNORE INTO app_owners (app_id, user_id, added_at) VALUES (?, ?, ?)",
+		appID, userID, at.Unix(),
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RemoveAppOwner removes a user from the owners of an application.
+// Removing a user who is not an owner succeeds.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - userID: ID of the user
+//
+// Returns:
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     storage.ErrUserNotFound if no user exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) RemoveAppOwner(ctx context.Context, appID int32, userID int64) error {
+	const op = "storage.sqlite.RemoveAppOwner"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	if err := appAndUserExist(ctx, tx, appID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM app_owners WHERE app_id = ? AND user_id = ?", appID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// IsAppOwner reports whether a user is an owner of an application.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//   - userID: ID of the user
+//
+// Returns:
+//   - bool: true if the user is an owner of the application
+//   - error: non-nil if the operation fails
+func (s *Storage) IsAppOwner(ctx context.Context, appID int32, userID int64) (bool, error) {
+	const op = "storage.sqlite.IsAppOwner"
+
+	var owner bool
+
+	if err := s.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM app_owners WHERE app_id = ? AND user_id = ?)", appID, userID,
+	).Scan(&owner); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return owner, nil
+}
+
+// AppOwners lists the owners of an application in order of ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - appID: ID of the application
+//
+// Returns:
+//   - []*models.User: the owners
+//   - error: storage.ErrAppNotFound if no application exists with the ID,
+//     or another error if the operation fails
+func (s *Storage) AppOwners(ctx context.Context, appID int32) ([]*models.User, error) {
+	const op = "storage.sqlite.AppOwners"
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	var exists bool
+
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM apps WHERE id = ?)", appID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%s: %w", op, notFound(storage.ErrAppNotFound))
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		"SELECT "+userColumns+" FROM users WHERE id IN (SELECT user_id FROM app_owners WHERE app_id = ?) ORDER BY id",
+		appID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var owners []*models.User
+
+	for rows.Next() {
+		user, err := s.scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		owners = append(owners, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return owners, nil
+}
+
+// AppsOwnedBy lists the IDs of the applications a user owns, in order of ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - userID: ID of the user
+//
+// Returns:
+//   - []int32: IDs of the owned applications
+//   - error: non-nil if the operation fails
+func (s *Storage) AppsOwnedBy(ctx context.Context, userID int64) ([]int32, error) {
+	const op = "storage.sqlite.AppsOwnedBy"
+
+	rows, err := s.db.QueryContext(ctx, "SELECT app_id FROM app_owners WHERE user_id = ? ORDER BY app_id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var appIDs []int32
+
+	for rows.Next() {
+		var appID int32
+		if err := rows.Scan(&appID); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		appIDs = append(appIDs, appID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return appIDs, nil
+}
+
+// SaveCacheInvalidation publishes a cache invalidation for the replicas sharing the database.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - topic: kind of cached data
+//   - key: entry to drop; empty for every entry of the topic
+//   - at: moment of the change
+//
+// Returns:
+//   - int64: ID of the invalidation
+//   - error: non-nil if the operation fails
+func (s *Storage) SaveCacheInvalidation(ctx context.Context, topic string, key string, at time.Time) (int64, error) {
+	const op = "storage.sqlite.SaveCacheInvalidation"
+
+	res, err := s.db.ExecContext(ctx,
+		"INSERT INTO cache_invalidations (topic, key, created_at) VALUES (?, ?, ?)",
+		topic, key, at.Unix(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// CacheInvalidations lists up to limit cache invalidations with an ID above afterID, in order of ID.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - afterID: ID of the last invalidation already seen; 0 for the first
+//   - limit: maximum number of invalidations returned
+//
+// Returns:
+//   - []models.CacheInvalidation: the invalidations
+//   - error: non-nil if the operation fails
+func (s *Storage) CacheInvalidations(ctx context.Context, afterID int64, limit int) ([]models.CacheInvalidation, error) {
+	const op = "storage.sqlite.CacheInvalidations"
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, topic, key, created_at FROM cache_invalidations WHERE id > ? ORDER BY id LIMIT ?",
+		afterID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var invalidations []models.CacheInvalidation
+
+	for rows.Next() {
+		var (
+			inv       models.CacheInvalidation
+			createdAt int64
+		)
+
+		if err := rows.Scan(&inv.ID, &inv.Topic, &inv.Key, &createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		inv.CreatedAt = time.Unix(createdAt, 0)
+
+		invalidations = append(invalidations, inv)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invalidations, nil
+}
+
+// LastCacheInvalidationID returns the ID of the latest cache invalidation, or 0 if there is none.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - int64: ID of the latest invalidation
+//   - error: non-nil if the operation fails
+func (s *Storage) LastCacheInvalidationID(ctx context.Context) (int64, error) {
+	const op = "storage.sqlite.LastCacheInvalidationID"
+
+	var id int64
+
+	if err := s.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(id), 0) FROM cache_invalidations").Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// DeleteCacheInvalidations removes the cache invalidations published before a moment.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - before: invalidations published before this moment are removed
+//
+// Returns:
+//   - int64: number of invalidations removed
+//   - error: non-nil if the operation fails
+func (s *Storage) DeleteCacheInvalidations(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteCacheInvalidations"
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM cache_invalidations WHERE created_at < ?", before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// AddUsage adds request counts to the daily usage of apps and users.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - usage: counts to add, each for a day, app, and user
+//
+// Returns:
+//   - error: non-nil if the operation fails, in which case no counts are added
+func (s *Storage) AddUsage(ctx context.Context, usage []models.Usage) error {
+	const op = "storage.sqlite.AddUsage"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO usage_daily (day, app_id, user_id, requests, errors) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (day, app_id, user_id) DO UPDATE SET
+			requests = requests + excluded.requests,
+			errors = errors + excluded.errors`)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer stmt.Close()
+
+	for _, u := range usage {
+		if _, err := stmt.ExecContext(ctx, u.Date.Unix(), u.AppID, u.UserID, u.Requests, u.Errors); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Usage lists daily usage since a day, newest day first and busiest first within a day.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - since: midnight UTC of the first day
+//   - appID: app to report; 0 for every app
+//   - userID: user to report; 0 for every user
+//   - byUser: whether to report each user separately, rather than sum the users of each app
+//   - limit: maximum number of entries returned
+//
+// Returns:
+//   - []models.Usage: the usage
+//   - error: non-nil if the operation fails
+func (s *Storage) Usage(
+	ctx context.Context,
+	since time.Time,
+	appID int32,
+	userID int64,
+	byUser bool,
+	limit int,
+) ([]models.Usage, error) {
+	const op = "storage.sqlite.Usage"
+
+	userColumn, groupBy := "0", "GROUP BY day, app_id"
+	if byUser {
+		userColumn, groupBy = "user_id", "GROUP BY day, app_id, user_id"
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT day, app_id, `+userColumn+`, SUM(requests), SUM(errors) FROM usage_daily
+		WHERE day >= ?1 AND (?2 = 0 OR app_id = ?2) AND (?3 = 0 OR user_id = ?3)
+		`+groupBy+`
+		ORDER BY day DESC, 4 DESC, app_id, 3
+		LIMIT ?4`,
+		since.Unix(), appID, userID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var usage []models.Usage
+
+	for rows.Next() {
+		var (
+			u   models.Usage
+			day int64
+		)
+
+		if err := rows.Scan(&day, &u.AppID, &u.UserID, &u.Requests, &u.Errors); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		u.Date = time.Unix(day, 0).UTC()
+
+		usage = append(usage, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return usage, nil
+}
+
+// DeleteUsage removes the daily usage of days before a moment.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - before: usage of days starting before this moment is removed
+//
+// Returns:
+//   - int64: number of entries removed
+//   - error: non-nil if the operation fails
+func (s *Storage) DeleteUsage(ctx context.Context, before time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteUsage"
+
+	res, err := s.db.ExecContext(ctx, "DELETE FROM usage_daily WHERE day < ?", before.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// Announcement returns the announcement attached to responses, even if it has expired.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - *models.Announcement: the announcement, or nil if there is none
+//   - error: non-nil if the operation fails
+func (s *Storage) Announcement(ctx context.Context) (*models.Announcement, error) {
+	const op = "storage.sqlite.Announcement"
+
+	var (
+		a         models.Announcement
+		createdAt int64
+		expiresAt sql.NullInt64
+	)
+
+	err := s.db.QueryRowContext(ctx,
+		"SELECT message, created_at, expires_at FROM announcement WHERE id = 1",
+	).Scan(&a.Message, &createdAt, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.CreatedAt = time.Unix(createdAt, 0)
+	if expiresAt.Valid {
+		a.ExpiresAt = time.Unix(expiresAt.Int64, 0)
+	}
+
+	return &a, nil
+}
+
+// SetAnnouncement replaces the announcement attached to responses.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - a: the new announcement; a zero ExpiresAt shows it until it is cleared
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) SetAnnouncement(ctx context.Context, a *models.Announcement) error {
+	const op = "storage.sqlite.SetAnnouncement"
+
+	var expiresAt sql.NullInt64
+	if !a.ExpiresAt.IsZero() {
+		expiresAt = sql.NullInt64{Int64: a.ExpiresAt.Unix(), Valid: true}
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO announcement (id, message, created_at, expires_at) VALUES (1, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET message = excluded.message, created_at = excluded.created_at, expires_at = excluded.expires_at`,
+		a.Message, a.CreatedAt.Unix(), expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteAnnouncement clears the announcement attached to responses. Clearing it when
+// there is none succeeds.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) DeleteAnnouncement(ctx context.Context) error {
+	const op = "storage.sqlite.DeleteAnnouncement"
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM announcement"); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SigningKeys returns the stored signing keys still in use: the active key, and the keys
+// retired after a moment. Keys are returned newest first.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - retiredAfter: moment before which retired keys are no longer returned
+//
+// Returns:
+//   - []*models.SigningKey: the keys; empty if no key was ever rotated
+//   - error: non-nil if the operation fails
+func (s *Storage) SigningKeys(ctx context.Context, retiredAfter time.Time) ([]*models.SigningKey, error) {
+	const op = "storage.sqlite.SigningKeys"
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, private_key, public_key, created_at, retired_at FROM signing_keys
+		WHERE retired_at = 0 OR retired_at > ? ORDER BY retired_at = 0 DESC, retired_at DESC`,
+		retiredAfter.Unix(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var keys []*models.SigningKey
+
+	for rows.Next() {
+		var (
+			key                  models.SigningKey
+			privateKey           []byte
+			createdAt, retiredAt int64
+		)
+
+		if err := rows.Scan(&key.ID, &privateKey, &key.PublicKey, &createdAt, &retiredAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if privateKey != nil && s.pii != nil {
+			decrypted, err := s.pii.Decrypt(privateKey, piiAAD("signing_keys.private_key", key.ID))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+
+			privateKey = []byte(decrypted)
+		}
+
+		key.PrivateKey = privateKey
+
+		if createdAt != 0 {
+			key.CreatedAt = time.Unix(createdAt, 0)
+		}
+
+		if retiredAt != 0 {
+			key.RetiredAt = time.Unix(retiredAt, 0)
+		}
+
+		keys = append(keys, &key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+// RotateSigningKey makes a new key sign tokens in place of the current one, which is
+// retired: its private key is dropped, and it is recorded if it was the configured key
+// rather than a stored one.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - current: public part of the key signing tokens when the rotation was decided
+//   - next: the new key, with its private key
+//   - at: moment of the rotation
+//
+// Returns:
+//   - error: storage.ErrVersionConflict if another key than current became active
+//     meanwhile, e.g. rotated by another replica, or another error if the operation fails
+func (s *Storage) RotateSigningKey(ctx context.Context, current *models.SigningKey, next *models.SigningKey, at time.Time) error {
+	const op = "storage.sqlite.RotateSigningKey"
+
+	privateKey := next.PrivateKey
+	if s.pii != nil {
+		encrypted, err := s.pii.Encrypt(string(privateKey), piiAAD("signing_keys.private_key", next.ID))
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		privateKey = encrypted
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer tx.Rollback()
+
+	var activeID string
+
+	err = tx.QueryRowContext(ctx, "SELECT id FROM signing_keys WHERE retired_at = 0").Scan(&activeID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Before the first rotation no key is stored, and the configured key signs tokens.
+	if activeID != "" && activeID != current.ID {
+		return fmt.Errorf("%s: %w", op, storage.ErrVersionConflict)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE signing_keys SET private_key = NULL, retired_at = ? WHERE retired_at = 0",
+		at.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO signing_keys (id, public_key, retired_at) VALUES (?, ?, ?) ON CONFLICT (id) DO NOTHING",
+		current.ID, current.PublicKey, at.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO signing_keys (id, private_key, public_key, created_at) VALUES (?, ?, ?, ?)",
+		next.ID, privateKey, next.PublicKey, at.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteSigningKeys removes the signing keys retired before a moment, which no longer
+// verify tokens.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - retiredBefore: moment before which retired keys are removed
+//
+// Returns:
+//   - int64: number of keys removed
+//   - error: non-nil if the operation fails
+func (s *Storage) DeleteSigningKeys(ctx context.Context, retiredBefore time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteSigningKeys"
+
+	res, err := s.db.ExecContext(ctx,
+		"DELETE FROM signing_keys WHERE retired_at != 0 AND retired_at <= ?",
+		retiredBefore.Unix(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+// ClaimOutboxMessages claims the outbox messages due for delivery, oldest first. Claiming
+// counts an attempt and pushes the messages back by lease, so other replicas skip them
+// while they are delivered, and they are delivered again if the process stops meanwhile.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - now: current moment; messages due at or before it are claimed
+//   - lease: how long the claimed messages are hidden from other claims
+//   - limit: maximum number of messages claimed
+//
+// Returns:
+//   - []models.OutboxMessage: the claimed messages, with Attempts including the claim
+//   - error: non-nil if the operation fails
+func (s *Storage) ClaimOutboxMessages(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxMessage, error) {
+	const op = "storage.sqlite.ClaimOutboxMessages"
+
+	rows, err := s.db.QueryContext(ctx,
+		`UPDATE outbox SET attempts = attempts + 1, next_attempt_at = ?
+		WHERE id IN (SELECT id FROM outbox WHERE failed_at = 0 AND next_attempt_at <= ? ORDER BY id LIMIT ?)
+		RETURNING id, topic, payload, created_at, attempts, next_attempt_at, last_error`,
+		now.Add(lease).Unix(), now.Unix(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	defer rows.Close()
+
+	var messages []models.OutboxMessage
+
+	for rows.Next() {
+		var (
+			msg                      models.OutboxMessage
+			payload                  string
+			createdAt, nextAttemptAt int64
+		)
+
+		if err := rows.Scan(&msg.ID, &msg.Topic, &payload, &createdAt, &msg.Attempts, &nextAttemptAt, &msg.LastError); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		msg.Payload = []byte(payload)
+		msg.CreatedAt = fromUnix(createdAt)
+		msg.NextAttemptAt = fromUnix(nextAttemptAt)
+
+		messages = append(messages, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// RETURNING yields rows in no particular order.
+	slices.SortFunc(messages, func(a, b models.OutboxMessage) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return messages, nil
+}
+
+// DeleteOutboxMessage deletes a delivered outbox message.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - id: ID of the message
+//
+// Returns:
+//   - error: non-nil if the operation fails; deleting a missing message is not an error
+func (s *Storage) DeleteOutboxMessage(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DeleteOutboxMessage"
+
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM outbox WHERE id = ?", id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RetryOutboxMessage records a failed delivery of an outbox message and when to retry it.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - id: ID of the message
+//   - nextAttemptAt: moment the message is due again
+//   - lastErr: error of the failed delivery
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) RetryOutboxMessage(ctx context.Context, id int64, nextAttemptAt time.Time, lastErr string) error {
+	const op = "storage.sqlite.RetryOutboxMessage"
+
+	if _, err := s.db.ExecContext(ctx,
+		"UPDATE outbox SET next_attempt_at = ?, last_error = ? WHERE id = ?",
+		nextAttemptAt.Unix(), lastErr, id,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// FailOutboxMessage gives up delivering an outbox message. The message is kept, so it can
+// be inspected, and is never claimed again.
+//
+// Parameters:
+//   - ctx: context for request cancellation and timeouts
+//   - id: ID of the message
+//   - at: moment delivery was given up
+//   - lastErr: error of the last failed delivery
+//
+// Returns:
+//   - error: non-nil if the operation fails
+func (s *Storage) FailOutboxMessage(ctx context.Context, id int64, at time.Time, lastErr string) error {
+	const op = "storage.sqlite.FailOutboxMessage"
+
+	if _, err := s.db.ExecContext(ctx,
+		"UPDATE outbox SET failed_at = ?, last_error = ? WHERE id = ?",
+		at.Unix(), lastErr, id,
+	); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
 }
@@ -0,0 +1,753 @@
+package sqlite_test
+
+import (
+	"context"
+	"crypto/aes"
+	gocipher "crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/lib/pii"
+	"github.com/kirinyoku/sso-grpc/internal/services/audit"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+	"github.com/kirinyoku/sso-grpc/internal/storage/sqlite"
+	"github.com/kirinyoku/sso-grpc/internal/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, newStorage)
+}
+
+func TestNew_SchemaVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version uint // migration the database is migrated to; 0 for none
+		setup   string
+		wantErr bool
+	}{
+		{name: "Current schema", version: sqlite.SchemaVersion},
+		{name: "Unversioned schema", version: sqlite.SchemaVersion - 1, wantErr: true},
+		{name: "Empty database", wantErr: true},
+		{
+			name:    "Newer compatible schema",
+			version: sqlite.SchemaVersion,
+			setup:   "UPDATE schema_version SET version = version + 1",
+		},
+		{
+			name:    "Newer incompatible schema",
+			version: sqlite.SchemaVersion,
+			setup:   "UPDATE schema_version SET version = version + 1, compatible_from = version + 1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storagePath := filepath.Join(t.TempDir(), "sso.db")
+
+			if tt.version > 0 {
+				m, err := migrate.New("file://../../../migrations", "sqlite3://"+storagePath)
+				require.NoError(t, err)
+				require.NoError(t, m.Migrate(tt.version))
+				m.Close()
+			}
+
+			if tt.setup != "" {
+				db, err := sql.Open("sqlite3", storagePath)
+				require.NoError(t, err)
+				_, err = db.Exec(tt.setup)
+				require.NoError(t, err)
+				db.Close()
+			}
+
+			_, err := sqlite.New(storagePath, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), config.QueryLog{}, config.SQLite{})
+			if tt.wantErr {
+				require.ErrorIs(t, err, sqlite.ErrSchemaIncompatible)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+// TestNew_NullableColumns guards the scans against NULL values: a column added to the
+// schema without NOT NULL must be scanned into a sql.Null type or selected with COALESCE,
+// as scanning NULL into a plain string or integer fails, and then be listed here.
+func TestNew_NullableColumns(t *testing.T) {
+	handled := map[string]bool{
+		"announcement.expires_at":       true, // sql.NullInt64; no expiry
+		"apps.family_id":                true, // sql.NullInt32; no family
+		"device_authorizations.user_id": true, // COALESCE; undecided
+		"idempotency_keys.response":     true, // nil []byte; request in progress
+		"password_reset_tokens.used_at": true, // only compared, never scanned
+		"registrations.user_id":         true, // COALESCE; account not created yet
+		"signing_keys.private_key":      true, // nil []byte; key retired
+		"unfreeze_tokens.used_at":       true, // only compared, never scanned
+		"user_history.valid_to":         true, // only compared, never scanned
+		"users.email_enc":               true, // nil []byte; email not encrypted
+		"users.email_hash":              true, // never scanned
+		"users.public_id":               true, // sql.NullString; decimal ID
+	}
+
+	db, err := sql.Open("sqlite3", migratedPath(t))
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Tables of SQLite and of the migration tool are not scanned by the storage.
+	rows, err := db.Query(`
+		SELECT m.name, c.name FROM sqlite_master m, pragma_table_info(m.name) c
+		WHERE m.type = 'table' AND m.name NOT IN ('sqlite_sequence', 'schema_migrations')
+			AND c."notnull" = 0 AND c.pk = 0`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var table, column string
+		require.NoError(t, rows.Scan(&table, &column))
+
+		if !handled[table+"."+column] {
+			t.Errorf("%s.%s is nullable: handle NULL where it is scanned, then list it in handled", table, column)
+		}
+	}
+
+	require.NoError(t, rows.Err())
+}
+
+func TestStorage_NullRows(t *testing.T) {
+	ctx := context.Background()
+
+	storagePath := migratedPath(t)
+	s := openStorage(t, storagePath)
+
+	db, err := sql.Open("sqlite3", storagePath)
+	require.NoError(t, err)
+	defer db.Close()
+
+	exec := func(query string, args ...any) {
+		t.Helper()
+
+		_, err := db.Exec(query, args...)
+		require.NoError(t, err)
+	}
+
+	now := time.Now().Unix()
+
+	// The users_default_public_id trigger assigns public IDs on insert, so it is cleared afterwards.
+	exec("INSERT INTO users (id, email, pass_hash, public_id, email_hash, email_enc) VALUES (1, 'null@example.com', x'00', NULL, NULL, NULL)")
+	exec("UPDATE users SET public_id = NULL WHERE id = 1")
+	exec("INSERT INTO apps (id, name, secret, family_id) VALUES (1, 'null', 'null-secret', NULL)")
+	exec("INSERT INTO device_authorizations (device_code_hash, user_code, app_id, user_id, interval_seconds, created_at, expires_at) VALUES (x'01', 'NULLCODE', 1, NULL, 5, ?, ?)", now, now+600)
+	exec("INSERT INTO idempotency_keys (method, key, request_hash, response, expires_at) VALUES ('method', 'key', x'02', NULL, ?)", now+600)
+	exec("INSERT INTO registrations (registration_id_hash, user_id, created_at, expires_at) VALUES (x'03', NULL, ?, ?)", now, now+600)
+	exec("INSERT INTO announcement (id, message, created_at, expires_at) VALUES (1, 'maintenance', ?, NULL)", now)
+
+	t.Run("User", func(t *testing.T) {
+		user, err := s.UserByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "1", user.PublicID)
+		assert.Equal(t, "null@example.com", user.Email)
+
+		user, err = s.User(ctx, "null@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "1", user.PublicID)
+	})
+
+	t.Run("App", func(t *testing.T) {
+		app, err := s.App(ctx, 1)
+		require.NoError(t, err)
+		assert.Zero(t, app.FamilyID)
+	})
+
+	t.Run("Device authorization", func(t *testing.T) {
+		auth, err := s.DeviceAuthorization(ctx, []byte{0x01})
+		require.NoError(t, err)
+		assert.Zero(t, auth.UserID)
+	})
+
+	t.Run("Idempotency record", func(t *testing.T) {
+		record, err := s.IdempotencyRecord(ctx, "method", "key")
+		require.NoError(t, err)
+		assert.Nil(t, record.Response)
+	})
+
+	t.Run("Registration", func(t *testing.T) {
+		reg, err := s.Registration(ctx, []byte{0x03})
+		require.NoError(t, err)
+		assert.Zero(t, reg.UserID)
+		assert.Empty(t, reg.PublicID)
+	})
+
+	t.Run("Announcement", func(t *testing.T) {
+		a, err := s.Announcement(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, a)
+		assert.True(t, a.ExpiresAt.IsZero())
+	})
+}
+
+func TestStorage_Outbox(t *testing.T) {
+	ctx := context.Background()
+
+	storagePath := migratedPath(t)
+	s := openStorage(t, storagePath)
+
+	db, err := sql.Open("sqlite3", storagePath)
+	require.NoError(t, err)
+	defer db.Close()
+
+	for _, query := range []string{
+		"INSERT INTO users (id, email, pass_hash) VALUES (1, 'outbox@example.com', x'00')",
+		"INSERT INTO apps (id, name, secret, backchannel_logout_uri) VALUES (1, 'notified', 'secret-1', 'https://app.example.com/logout')",
+		"INSERT INTO apps (id, name, secret) VALUES (2, 'silent', 'secret-2')",
+	} {
+		_, err := db.Exec(query)
+		require.NoError(t, err)
+	}
+
+	now := time.Unix(time.Now().Unix(), 0)
+	lease := time.Minute
+
+	// Failed revocations queue nothing.
+	require.ErrorIs(t, s.RevokeUserTokens(ctx, 2, now), storage.ErrUserNotFound)
+
+	// Only apps with a back-channel logout URI are notified.
+	require.NoError(t, s.RevokeUserTokens(ctx, 1, now))
+
+	messages, err := s.ClaimOutboxMessages(ctx, now, lease, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	msg := messages[0]
+	assert.Equal(t, models.TopicBackchannelLogout, msg.Topic)
+	assert.JSONEq(t, `{"app_id": 1, "user_id": 1}`, string(msg.Payload))
+	assert.Equal(t, 1, msg.Attempts)
+
+	// Claimed messages are hidden until their lease ends.
+	messages, err = s.ClaimOutboxMessages(ctx, now, lease, 10)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	messages, err = s.ClaimOutboxMessages(ctx, now.Add(lease), lease, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, 2, messages[0].Attempts)
+
+	require.NoError(t, s.RetryOutboxMessage(ctx, msg.ID, now.Add(time.Second), "unexpected status 503"))
+
+	messages, err = s.ClaimOutboxMessages(ctx, now.Add(time.Second), lease, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "unexpected status 503", messages[0].LastError)
+
+	// Given up messages are kept but never claimed again.
+	require.NoError(t, s.FailOutboxMessage(ctx, msg.ID, now, "unexpected status 503"))
+
+	messages, err = s.ClaimOutboxMessages(ctx, now.Add(time.Hour), lease, 10)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	// Freezing queues logouts as well, and delivered messages are deleted.
+	require.NoError(t, s.FreezeUser(ctx, 1, now))
+
+	messages, err = s.ClaimOutboxMessages(ctx, now, lease, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.NoError(t, s.DeleteOutboxMessage(ctx, messages[0].ID))
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM outbox").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func TestStorage_SigningKeys(t *testing.T) {
+	ctx := context.Background()
+
+	s := openStorage(t, migratedPath(t))
+
+	now := time.Unix(time.Now().Unix(), 0)
+
+	configured := &models.SigningKey{ID: "configured", PublicKey: []byte("public-0")}
+	first := &models.SigningKey{ID: "first", PrivateKey: []byte("private-1"), PublicKey: []byte("public-1")}
+	second := &models.SigningKey{ID: "second", PrivateKey: []byte("private-2"), PublicKey: []byte("public-2")}
+
+	keys, err := s.SigningKeys(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	// The first rotation records the configured key as retired.
+	require.NoError(t, s.RotateSigningKey(ctx, configured, first, now))
+
+	// A rotation based on a key that is no longer active loses.
+	require.ErrorIs(t, s.RotateSigningKey(ctx, configured, second, now.Add(time.Minute)), storage.ErrVersionConflict)
+
+	require.NoError(t, s.RotateSigningKey(ctx, first, second, now.Add(time.Hour)))
+
+	keys, err = s.SigningKeys(ctx, now.Add(-time.Second))
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+
+	assert.Equal(t, "second", keys[0].ID)
+	assert.True(t, keys[0].Active())
+	assert.Equal(t, []byte("private-2"), keys[0].PrivateKey)
+	assert.Equal(t, now.Add(time.Hour), keys[0].CreatedAt)
+
+	// Retired keys lose their private key.
+	assert.Equal(t, "first", keys[1].ID)
+	assert.Equal(t, now.Add(time.Hour), keys[1].RetiredAt)
+	assert.Nil(t, keys[1].PrivateKey)
+	assert.Equal(t, []byte("public-1"), keys[1].PublicKey)
+
+	assert.Equal(t, "configured", keys[2].ID)
+	assert.True(t, keys[2].CreatedAt.IsZero())
+
+	// Keys retired before the grace period are neither returned nor kept.
+	keys, err = s.SigningKeys(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	deleted, err := s.DeleteSigningKeys(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
+
+func TestStorage_ErrorMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	s := openStorage(t, migratedPath(t))
+
+	// The counters are global, so the test compares them before and after each call.
+	count := func(method, errorType string) int64 {
+		t.Helper()
+
+		byMethod, ok := expvar.Get("storage_errors_by_method").(*expvar.Map)
+		require.True(t, ok)
+
+		counts, ok := byMethod.Get(method).(*expvar.Map)
+		if !ok {
+			return 0
+		}
+
+		n, ok := counts.Get(errorType).(*expvar.Int)
+		if !ok {
+			return 0
+		}
+
+		return n.Value()
+	}
+
+	before := count("UserByID", "not_found")
+
+	_, err := s.UserByID(ctx, 1<<40)
+	require.ErrorIs(t, err, storage.ErrUserNotFound)
+	assert.Equal(t, before+1, count("UserByID", "not_found"))
+
+	_, err = s.SaveApp(ctx, "metrics", "metrics-secret", "", false, false, 0)
+	require.NoError(t, err)
+
+	before = count("SaveApp", "constraint")
+
+	_, err = s.SaveApp(ctx, "metrics", "metrics-secret", "", false, false, 0)
+	require.ErrorIs(t, err, storage.ErrAppExists)
+	assert.Equal(t, before+1, count("SaveApp", "constraint"))
+}
+
+// newStorage opens a storage on a new database in a temporary directory, migrated to the latest schema.
+func newStorage(t *testing.T) storagetest.Storage {
+	t.Helper()
+
+	return openStorage(t, migratedPath(t))
+}
+
+// migratedPath returns the path of a new database in a temporary directory, migrated to the latest schema.
+func migratedPath(t *testing.T) string {
+	t.Helper()
+
+	storagePath := filepath.Join(t.TempDir(), "sso.db")
+
+	m, err := migrate.New("file://../../../migrations", "sqlite3://"+storagePath)
+	if err != nil {
+		t.Fatalf("failed to open migrations: %v", err)
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	m.Close()
+
+	return storagePath
+}
+
+// openStorage opens a storage on the database at storagePath.
+func openStorage(t *testing.T, storagePath string) *sqlite.Storage {
+	t.Helper()
+
+	s, err := sqlite.New(storagePath, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), config.QueryLog{}, config.SQLite{})
+	if err != nil {
+		t.Fatalf("failed to open storage: %v", err)
+	}
+
+	return s
+}
+
+func TestStorage_UserAtTime(t *testing.T) {
+	ctx := context.Background()
+
+	storagePath := migratedPath(t)
+	s := openStorage(t, storagePath)
+
+	db, err := sql.Open("sqlite3", storagePath)
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Unix(time.Now().Unix(), 0)
+
+	primaryID, _, err := s.SaveUser(ctx, "primary@example.com", []byte("hash"), "")
+	require.NoError(t, err)
+
+	duplicateID, _, err := s.SaveUser(ctx, "duplicate@example.com", []byte("hash"), "")
+	require.NoError(t, err)
+
+	appID, err := s.SaveApp(ctx, "history", "history-secret", "", false, false, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, s.GrantAppAccess(ctx, appID, duplicateID, now))
+	require.NoError(t, s.SetUserLabels(ctx, duplicateID, map[string]string{"cohort": "a"}))
+
+	// History has a resolution of one second, so the state so far is moved an hour back.
+	_, err = db.Exec("UPDATE user_history SET valid_from = valid_from - 3600")
+	require.NoError(t, err)
+
+	_, err = s.MergeUsers(ctx, primaryID, duplicateID, now)
+	require.NoError(t, err)
+
+	snapshot, err := s.UserAtTime(ctx, duplicateID, now.Add(-30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-30*time.Minute), snapshot.At)
+	assert.Zero(t, snapshot.MergedInto)
+	assert.Equal(t, []int32{appID}, snapshot.MemberOf)
+	assert.Equal(t, map[string]string{"cohort": "a"}, snapshot.Labels)
+
+	// Triggers record the changes of every writer, such as the merge.
+	snapshot, err = s.UserAtTime(ctx, duplicateID, now)
+	require.NoError(t, err)
+	assert.Equal(t, primaryID, snapshot.MergedInto)
+	assert.Empty(t, snapshot.MemberOf)
+	assert.Empty(t, snapshot.Labels)
+
+	snapshot, err = s.UserAtTime(ctx, primaryID, now)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{appID}, snapshot.MemberOf)
+	assert.Equal(t, map[string]string{"cohort": "a"}, snapshot.Labels)
+
+	_, err = s.UserAtTime(ctx, primaryID, now.Add(-2*time.Hour))
+	require.ErrorIs(t, err, storage.ErrUserHistoryNotFound)
+
+	_, err = s.UserAtTime(ctx, primaryID+duplicateID, now)
+	require.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func TestStorage_Snapshot(t *testing.T) {
+	ctx := context.Background()
+
+	s := openStorage(t, migratedPath(t))
+
+	userID, _, err := s.SaveUser(ctx, "snapshot@example.com", []byte("hash"), "")
+	require.NoError(t, err)
+
+	snapshotPath := filepath.Join(t.TempDir(), "snapshot.db")
+	require.NoError(t, s.Snapshot(ctx, snapshotPath))
+
+	// A snapshot never overwrites a file.
+	require.Error(t, s.Snapshot(ctx, snapshotPath))
+
+	// The service can run on the copy.
+	restored := openStorage(t, snapshotPath)
+
+	user, err := restored.UserByID(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "snapshot@example.com", user.Email)
+}
+
+func TestStorage_Sessions(t *testing.T) {
+	ctx := context.Background()
+
+	s := openStorage(t, migratedPath(t))
+
+	// Times are stored in seconds and read back in UTC.
+	now := time.Unix(time.Now().Unix(), 0).UTC()
+
+	userID, _, err := s.SaveUser(ctx, "sessions@example.com", []byte("hash"), "")
+	require.NoError(t, err)
+
+	otherID, _, err := s.SaveUser(ctx, "other@example.com", []byte("hash"), "")
+	require.NoError(t, err)
+
+	appID, err := s.SaveApp(ctx, "sessions", "sessions-secret", "", false, false, 0)
+	require.NoError(t, err)
+
+	login := func(hash string, createdAt time.Time, ip string) {
+		require.NoError(t, s.SaveRefreshToken(ctx, &models.RefreshToken{
+			TokenHash: []byte(hash),
+			UserID:    userID,
+			AppID:     appID,
+			IP:        ip,
+			UserAgent: "test-agent",
+			CreatedAt: createdAt,
+			ExpiresAt: createdAt.Add(time.Hour),
+		}))
+	}
+
+	login("laptop", now.Add(-2*time.Minute), "192.0.2.1")
+	login("phone", now.Add(-time.Minute), "192.0.2.2")
+
+	sessions, err := s.UserSessions(ctx, userID, now)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+
+	phone := sessions[0]
+	assert.Equal(t, "192.0.2.2", phone.IP)
+	assert.Equal(t, "test-agent", phone.UserAgent)
+	assert.Equal(t, "sessions", phone.AppName)
+	assert.Equal(t, now.Add(-time.Minute), phone.CreatedAt)
+	assert.Equal(t, phone.CreatedAt, phone.LastUsedAt)
+	assert.Equal(t, now.Add(59*time.Minute), phone.ExpiresAt)
+
+	// Exchanging a refresh token keeps the session and marks it used.
+	laptopToken, err := s.RefreshToken(ctx, []byte("laptop"))
+	require.NoError(t, err)
+	assert.Equal(t, laptopToken.ChainID, sessions[1].ID)
+
+	require.NoError(t, s.RotateRefreshToken(ctx, laptopToken.ID, &models.RefreshToken{
+		TokenHash: []byte("laptop-2"),
+		ChainID:   laptopToken.ChainID,
+		UserID:    userID,
+		AppID:     appID,
+		CreatedAt: now,
+		ExpiresAt: laptopToken.ExpiresAt,
+	}))
+
+	sessions, err = s.UserSessions(ctx, userID, now)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, laptopToken.ChainID, sessions[0].ID)
+	assert.Equal(t, now, sessions[0].LastUsedAt)
+	assert.Equal(t, "192.0.2.1", sessions[0].IP)
+
+	// Only the user's own active sessions can be revoked.
+	require.ErrorIs(t, s.RevokeSession(ctx, phone.ID, otherID, now), storage.ErrSessionNotFound)
+	require.NoError(t, s.RevokeSession(ctx, phone.ID, userID, now))
+	require.ErrorIs(t, s.RevokeSession(ctx, phone.ID, userID, now), storage.ErrSessionNotFound)
+
+	sessions, err = s.UserSessions(ctx, userID, now)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, laptopToken.ChainID, sessions[0].ID)
+
+	// Sessions end with their chain, and when the tokens of their user are revoked.
+	sessions, err = s.UserSessions(ctx, userID, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	require.NoError(t, s.RevokeUserTokens(ctx, userID, now))
+
+	sessions, err = s.UserSessions(ctx, userID, now)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestStorage_PII(t *testing.T) {
+	ctx := context.Background()
+
+	storagePath := migratedPath(t)
+
+	db, err := sql.Open("sqlite3", storagePath)
+	require.NoError(t, err)
+	defer db.Close()
+
+	master := make([]byte, pii.KeySize)
+	_, err = rand.Read(master)
+	require.NoError(t, err)
+
+	key := base64.StdEncoding.EncodeToString(master)
+
+	cipher, err := pii.New(key)
+	require.NoError(t, err)
+
+	// Emails stored before the key was configured: one in plain text, and one sealed before
+	// ciphertexts were bound to their row.
+	plain := openStorage(t, storagePath)
+
+	plainID, _, err := plain.SaveUser(ctx, "plain@example.com", []byte("hash"), "")
+	require.NoError(t, err)
+
+	unboundID, _, err := plain.SaveUser(ctx, "unbound@example.com", []byte("hash"), "")
+	require.NoError(t, err)
+
+	unboundHash := cipher.Index("unbound@example.com")
+	_, err = db.Exec("UPDATE users SET email = ?, email_hash = ?, email_enc = ? WHERE id = ?",
+		hex.EncodeToString(unboundHash), unboundHash, sealUnbound(t, master, "unbound@example.com"), unboundID)
+	require.NoError(t, err)
+
+	// Opening the storage with the key encrypts both, bound to their row.
+	s, err := sqlite.New(storagePath, cipher, slog.New(slog.NewTextHandler(io.Discard, nil)), config.QueryLog{}, config.SQLite{})
+	require.NoError(t, err)
+
+	newID, _, err := s.SaveUser(ctx, "new@example.com", []byte("hash"), "")
+	require.NoError(t, err)
+
+	for id, email := range map[int64]string{plainID: "plain@example.com", unboundID: "unbound@example.com", newID: "new@example.com"} {
+		var (
+			emailCol string
+			emailEnc []byte
+		)
+
+		require.NoError(t, db.QueryRow("SELECT email, email_enc FROM users WHERE id = ?", id).Scan(&emailCol, &emailEnc))
+		assert.Equal(t, hex.EncodeToString(cipher.Index(email)), emailCol)
+		assert.NotContains(t, string(emailEnc), email)
+
+		user, err := s.User(ctx, email)
+		require.NoError(t, err)
+		assert.Equal(t, id, user.ID)
+		assert.Equal(t, email, user.Email)
+
+		user, err = s.UserByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, email, user.Email)
+	}
+
+	// Private keys of signing keys are encrypted too.
+	require.NoError(t, s.RotateSigningKey(ctx,
+		&models.SigningKey{ID: "configured", PublicKey: []byte("public-0")},
+		&models.SigningKey{ID: "next", PrivateKey: []byte("private-1"), PublicKey: []byte("public-1")},
+		time.Now(),
+	))
+
+	keys, err := s.SigningKeys(ctx, time.Now())
+	require.NoError(t, err)
+	require.NotEmpty(t, keys)
+	assert.Equal(t, []byte("private-1"), keys[0].PrivateKey)
+
+	// A ciphertext copied to another row does not decrypt there.
+	_, err = db.Exec("UPDATE users SET email_enc = (SELECT email_enc FROM users WHERE id = ?) WHERE id = ?", newID, plainID)
+	require.NoError(t, err)
+
+	_, err = s.UserByID(ctx, plainID)
+	require.ErrorIs(t, err, pii.ErrInvalidCiphertext)
+
+	// Another key cannot read the emails, and no key refuses to open the database.
+	_, err = rand.Read(master)
+	require.NoError(t, err)
+
+	otherCipher, err := pii.New(base64.StdEncoding.EncodeToString(master))
+	require.NoError(t, err)
+
+	other, err := sqlite.New(storagePath, otherCipher, slog.New(slog.NewTextHandler(io.Discard, nil)), config.QueryLog{}, config.SQLite{})
+	require.NoError(t, err)
+
+	_, err = other.UserByID(ctx, newID)
+	require.ErrorIs(t, err, pii.ErrInvalidCiphertext)
+
+	_, err = other.User(ctx, "new@example.com")
+	require.ErrorIs(t, err, storage.ErrUserNotFound)
+
+	_, err = sqlite.New(storagePath, nil, slog.New(slog.NewTextHandler(io.Discard, nil)), config.QueryLog{}, config.SQLite{})
+	require.Error(t, err)
+}
+
+// sealUnbound encrypts a value as the pii package did before ciphertexts were bound to
+// their row: version 1, without associated data.
+func sealUnbound(t *testing.T, master []byte, plaintext string) []byte {
+	t.Helper()
+
+	mac := hmac.New(sha256.New, master)
+	mac.Write([]byte("sso pii encryption"))
+
+	block, err := aes.NewCipher(mac.Sum(nil))
+	require.NoError(t, err)
+
+	aead, err := gocipher.NewGCM(block)
+	require.NoError(t, err)
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	require.NoError(t, err)
+
+	return aead.Seal(append([]byte{pii.VersionUnbound}, nonce...), nonce, []byte(plaintext), nil)
+}
+
+func TestStorage_AuditReplicas(t *testing.T) {
+	ctx := context.Background()
+
+	const perReplica = 25
+
+	// Two replicas share a database, each with its own storage and audit service.
+	storagePath := migratedPath(t)
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	replicas := []*audit.Audit{
+		audit.New(log, openStorage(t, storagePath), "audit-key"),
+		audit.New(log, openStorage(t, storagePath), "audit-key"),
+	}
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, len(replicas))
+
+	for i, replica := range replicas {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range perReplica {
+				event := &models.AuditEvent{
+					At:     time.Now(),
+					Method: "/admin.v1.Admin/DeleteUser",
+					Target: fmt.Sprintf("replica-%d-%d", i, j),
+					Code:   "OK",
+				}
+
+				if err := replica.Record(ctx, event); err != nil {
+					errs <- err
+
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	verification, err := replicas[0].Verify(ctx)
+	require.NoError(t, err)
+	assert.True(t, verification.Valid)
+	assert.Equal(t, int64(len(replicas)*perReplica), verification.Events)
+}
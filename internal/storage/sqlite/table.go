@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// queryer is implemented by *sql.DB, *sql.Tx, and *sql.Conn.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// execer is implemented by *sql.DB, *sql.Tx, and *sql.Conn.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// table maps the columns of a table to the fields of a model. The column list,
+// the scan destinations, and the inserted values of a query are all derived from
+// the same fields, so they cannot drift apart as columns are added.
+type table[T any] struct {
+	name   string
+	fields []field[T]
+}
+
+// field maps a column to a field of the model.
+type field[T any] struct {
+	column string
+	ref    func(row *T) any // destination the column is scanned into
+	value  func(row *T) any // value inserted into the column
+}
+
+// columns returns the columns of the table, separated by commas.
+func (t table[T]) columns() string {
+	columns := make([]string, len(t.fields))
+	for i, f := range t.fields {
+		columns[i] = f.column
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+// selectFrom returns a SELECT of every column of the table, to be followed by
+// clauses such as WHERE and ORDER BY.
+func (t table[T]) selectFrom() string {
+	return "SELECT " + t.columns() + " FROM " + t.name
+}
+
+// insert inserts a row with a value for every column.
+func (t table[T]) insert(ctx context.Context, db execer, row *T) error {
+	values := make([]any, len(t.fields))
+	for i, f := range t.fields {
+		values[i] = f.value(row)
+	}
+
+	_, err := db.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO %s (%s) VALUES (?%s)", t.name, t.columns(), strings.Repeat(", ?", len(t.fields)-1)),
+		values...,
+	)
+
+	return err
+}
+
+// query runs a query built with selectFrom and scans the resulting rows.
+func (t table[T]) query(ctx context.Context, db queryer, query string, args ...any) ([]T, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var result []T
+
+	for rows.Next() {
+		var row T
+
+		refs := make([]any, len(t.fields))
+		for i, f := range t.fields {
+			refs[i] = f.ref(&row)
+		}
+
+		if err := rows.Scan(refs...); err != nil {
+			return nil, err
+		}
+
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+// unixTime scans a column of Unix seconds into a time, mapping 0 (unknown) to the zero time.
+type unixTime struct {
+	t *time.Time
+}
+
+// Scan implements sql.Scanner.
+func (u unixTime) Scan(src any) error {
+	sec, ok := src.(int64)
+	if !ok {
+		return fmt.Errorf("unix time: unsupported type %T", src)
+	}
+
+	*u.t = fromUnix(sec)
+
+	return nil
+}
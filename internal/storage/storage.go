@@ -8,6 +8,45 @@ var (
 	ErrUserExists = errors.New("user already exists")
 	// ErrUserNotFound is returned when a user with the given email does not exist
 	ErrUserNotFound = errors.New("user not found")
+	// ErrUserMerged is returned when a user to merge was already merged into another user
+	ErrUserMerged = errors.New("user already merged")
 	// ErrAppNotFound is returned when an application with the given ID does not exist
 	ErrAppNotFound = errors.New("app not found")
+	// ErrAppExists is returned when an application with the given name or secret already exists
+	ErrAppExists = errors.New("app already exists")
+	// ErrAppFamilyNotFound is returned when an app family with the given ID does not exist
+	ErrAppFamilyNotFound = errors.New("app family not found")
+	// ErrAppFamilyExists is returned when an app family with the given name or secret already exists
+	ErrAppFamilyExists = errors.New("app family already exists")
+	// ErrAppFamilyNotEmpty is returned when an app family to delete still has apps
+	ErrAppFamilyNotEmpty = errors.New("app family is not empty")
+	// ErrIdempotencyKeyExists is returned when an idempotency key is already reserved for the method
+	ErrIdempotencyKeyExists = errors.New("idempotency key already exists")
+	// ErrIdempotencyKeyNotFound is returned when no unexpired record exists for an idempotency key
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+	// ErrResetTokenNotFound is returned when a password reset token does not exist, was used, or has expired
+	ErrResetTokenNotFound = errors.New("password reset token not found")
+	// ErrUnfreezeTokenNotFound is returned when an unfreeze token does not exist, was used, has expired,
+	// or its account is no longer frozen
+	ErrUnfreezeTokenNotFound = errors.New("unfreeze token not found")
+	// ErrVersionConflict is returned when a record was modified since the version an update is based on
+	ErrVersionConflict = errors.New("version conflict")
+	// ErrDeviceAuthorizationNotFound is returned when a device authorization does not exist or is not in the expected state
+	ErrDeviceAuthorizationNotFound = errors.New("device authorization not found")
+	// ErrLoginChallengeNotFound is returned when a login challenge does not exist or is not in the expected state
+	ErrLoginChallengeNotFound = errors.New("login challenge not found")
+	// ErrRefreshTokenNotFound is returned when a refresh token does not exist or was already revoked
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	// ErrSessionNotFound is returned when a user has no active session with the ID
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrRegistrationNotFound is returned when an async registration does not exist or has expired
+	ErrRegistrationNotFound = errors.New("registration not found")
+	// ErrSupportTokenNotFound is returned when a support token does not exist
+	ErrSupportTokenNotFound = errors.New("support token not found")
+	// ErrUserCodeExists is returned when a generated device user code is already in use
+	ErrUserCodeExists = errors.New("user code already exists")
+	// ErrEmailsEncrypted is returned when searching emails that are stored encrypted
+	ErrEmailsEncrypted = errors.New("emails are encrypted")
+	// ErrUserHistoryNotFound is returned when no state of a user was recorded at the requested time
+	ErrUserHistoryNotFound = errors.New("user history not found")
 )
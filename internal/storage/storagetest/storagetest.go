@@ -0,0 +1,331 @@
+// Package storagetest provides a conformance test suite for storage implementations.
+// Every backend runs it, so the services behave the same whichever one they use.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrency is the number of goroutines racing for the same record in concurrency tests.
+const concurrency = 16
+
+// Storage is the part of a storage implementation covered by the suite: the users,
+// apps, and idempotency keys every service depends on.
+type Storage interface {
+	SaveUser(ctx context.Context, email string, passHash []byte, publicID string) (int64, string, error)
+	User(ctx context.Context, email string) (*models.User, error)
+	UserByID(ctx context.Context, userID int64) (*models.User, error)
+	UpdateUser(ctx context.Context, userID int64, isAdmin bool, version int64) (*models.User, error)
+	SaveApp(
+		ctx context.Context,
+		name string,
+		secret string,
+		tokenFormat string,
+		minimalClaims bool,
+		requireMembership bool,
+		familyID int32,
+	) (int32, error)
+	App(ctx context.Context, appID int32) (*models.App, error)
+	DeleteApp(ctx context.Context, appID int32) error
+	ReserveIdempotencyKey(ctx context.Context, method string, key string, requestHash []byte, expiresAt time.Time) error
+	IdempotencyRecord(ctx context.Context, method string, key string) (*models.IdempotencyRecord, error)
+	IsEmpty(ctx context.Context) (bool, error)
+}
+
+// Run runs the conformance suite against the storage returned by newStorage, which is
+// called once per test and must return an empty, fully migrated storage.
+func Run(t *testing.T, newStorage func(t *testing.T) Storage) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s Storage)
+	}{
+		{name: "Empty", run: testEmpty},
+		{name: "UserRoundTrip", run: testUserRoundTrip},
+		{name: "UserUniqueness", run: testUserUniqueness},
+		{name: "UserNotFound", run: testUserNotFound},
+		{name: "UserVersionConflict", run: testUserVersionConflict},
+		{name: "AppUniqueness", run: testAppUniqueness},
+		{name: "AppNotFound", run: testAppNotFound},
+		{name: "IdempotencyKeyUniqueness", run: testIdempotencyKeyUniqueness},
+		{name: "CanceledContext", run: testCanceledContext},
+		{name: "ConcurrentSaveUser", run: testConcurrentSaveUser},
+		{name: "ConcurrentReserveIdempotencyKey", run: testConcurrentReserveIdempotencyKey},
+		{name: "ConcurrentUpdateUser", run: testConcurrentUpdateUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.run(t, newStorage(t))
+		})
+	}
+}
+
+func testEmpty(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	empty, err := s.IsEmpty(ctx)
+	require.NoError(t, err)
+	assert.True(t, empty)
+
+	_, err = s.SaveApp(ctx, "test-"+gofakeit.UUID(), gofakeit.UUID(), "", false, false, 0)
+	require.NoError(t, err)
+
+	empty, err = s.IsEmpty(ctx)
+	require.NoError(t, err)
+	assert.False(t, empty)
+}
+
+func testUserRoundTrip(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	email := gofakeit.Email()
+
+	id, publicID, err := s.SaveUser(ctx, email, []byte("hash"), "")
+	require.NoError(t, err)
+	assert.Positive(t, id)
+	assert.NotEmpty(t, publicID)
+
+	user, err := s.User(ctx, email)
+	require.NoError(t, err)
+	assert.Equal(t, id, user.ID)
+	assert.Equal(t, publicID, user.PublicID)
+	assert.Equal(t, email, user.Email)
+	assert.Equal(t, []byte("hash"), user.PassHash)
+	assert.False(t, user.IsAdmin)
+
+	byID, err := s.UserByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, user, byID)
+}
+
+func testUserUniqueness(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	email := gofakeit.Email()
+	publicID := gofakeit.UUID()
+
+	_, _, err := s.SaveUser(ctx, email, []byte("hash"), publicID)
+	require.NoError(t, err)
+
+	_, _, err = s.SaveUser(ctx, email, []byte("other"), "")
+	assert.ErrorIs(t, err, storage.ErrUserExists)
+
+	_, _, err = s.SaveUser(ctx, gofakeit.Email(), []byte("hash"), publicID)
+	assert.ErrorIs(t, err, storage.ErrUserExists)
+}
+
+func testUserNotFound(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	_, err := s.User(ctx, gofakeit.Email())
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+
+	_, err = s.UserByID(ctx, 1_000_000)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+
+	_, err = s.UpdateUser(ctx, 1_000_000, true, 1)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func testUserVersionConflict(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	id, _, err := s.SaveUser(ctx, gofakeit.Email(), []byte("hash"), "")
+	require.NoError(t, err)
+
+	user, err := s.UserByID(ctx, id)
+	require.NoError(t, err)
+
+	updated, err := s.UpdateUser(ctx, id, true, user.Version)
+	require.NoError(t, err)
+	assert.True(t, updated.IsAdmin)
+	assert.Greater(t, updated.Version, user.Version)
+
+	_, err = s.UpdateUser(ctx, id, false, user.Version)
+	assert.ErrorIs(t, err, storage.ErrVersionConflict)
+}
+
+func testAppUniqueness(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	name := gofakeit.UUID()
+	secret := gofakeit.UUID()
+
+	id, err := s.SaveApp(ctx, name, secret, "jwt", false, false, 0)
+	require.NoError(t, err)
+
+	app, err := s.App(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, name, app.Name)
+	assert.Equal(t, secret, app.Secret)
+
+	_, err = s.SaveApp(ctx, name, gofakeit.UUID(), "jwt", false, false, 0)
+	assert.ErrorIs(t, err, storage.ErrAppExists)
+
+	_, err = s.SaveApp(ctx, gofakeit.UUID(), secret, "jwt", false, false, 0)
+	assert.ErrorIs(t, err, storage.ErrAppExists)
+}
+
+func testAppNotFound(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	_, err := s.App(ctx, 1_000_000)
+	assert.ErrorIs(t, err, storage.ErrAppNotFound)
+
+	err = s.DeleteApp(ctx, 1_000_000)
+	assert.ErrorIs(t, err, storage.ErrAppNotFound)
+
+	id, err := s.SaveApp(ctx, gofakeit.UUID(), gofakeit.UUID(), "jwt", false, false, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, s.DeleteApp(ctx, id))
+
+	_, err = s.App(ctx, id)
+	assert.ErrorIs(t, err, storage.ErrAppNotFound)
+
+	_, err = s.SaveApp(ctx, gofakeit.UUID(), gofakeit.UUID(), "jwt", false, false, 1_000_000)
+	assert.ErrorIs(t, err, storage.ErrAppFamilyNotFound)
+}
+
+func testIdempotencyKeyUniqueness(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	key := gofakeit.UUID()
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, err := s.IdempotencyRecord(ctx, "Register", key)
+	assert.ErrorIs(t, err, storage.ErrIdempotencyKeyNotFound)
+
+	require.NoError(t, s.ReserveIdempotencyKey(ctx, "Register", key, []byte("request"), expiresAt))
+
+	err = s.ReserveIdempotencyKey(ctx, "Register", key, []byte("request"), expiresAt)
+	assert.ErrorIs(t, err, storage.ErrIdempotencyKeyExists)
+
+	// Keys are scoped to their method.
+	require.NoError(t, s.ReserveIdempotencyKey(ctx, "Login", key, []byte("request"), expiresAt))
+
+	record, err := s.IdempotencyRecord(ctx, "Register", key)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("request"), record.RequestHash)
+	assert.Nil(t, record.Response)
+}
+
+func testCanceledContext(t *testing.T, s Storage) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	email := gofakeit.Email()
+
+	_, _, err := s.SaveUser(ctx, email, []byte("hash"), "")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = s.User(ctx, email)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = s.SaveApp(ctx, gofakeit.UUID(), gofakeit.UUID(), "jwt", false, false, 0)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = s.App(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Nothing was written by the canceled calls.
+	_, err = s.User(context.Background(), email)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func testConcurrentSaveUser(t *testing.T, s Storage) {
+	email := gofakeit.Email()
+
+	errs := race(func() error {
+		_, _, err := s.SaveUser(context.Background(), email, []byte("hash"), "")
+
+		return err
+	})
+
+	assertOneWinner(t, errs, storage.ErrUserExists)
+}
+
+func testConcurrentReserveIdempotencyKey(t *testing.T, s Storage) {
+	key := gofakeit.UUID()
+	expiresAt := time.Now().Add(time.Hour)
+
+	errs := race(func() error {
+		return s.ReserveIdempotencyKey(context.Background(), "Register", key, []byte("request"), expiresAt)
+	})
+
+	assertOneWinner(t, errs, storage.ErrIdempotencyKeyExists)
+}
+
+func testConcurrentUpdateUser(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	id, _, err := s.SaveUser(ctx, gofakeit.Email(), []byte("hash"), "")
+	require.NoError(t, err)
+
+	user, err := s.UserByID(ctx, id)
+	require.NoError(t, err)
+
+	errs := race(func() error {
+		_, err := s.UpdateUser(context.Background(), id, true, user.Version)
+
+		return err
+	})
+
+	assertOneWinner(t, errs, storage.ErrVersionConflict)
+}
+
+// race calls fn from concurrent goroutines released at once and returns their errors.
+func race(fn func() error) []error {
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make([]error, concurrency)
+	)
+
+	for i := range errs {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			<-start
+
+			errs[i] = fn()
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	return errs
+}
+
+// assertOneWinner asserts that exactly one of errs is nil and all others are lost.
+func assertOneWinner(t *testing.T, errs []error, lost error) {
+	t.Helper()
+
+	var won int
+
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			won++
+		case !errors.Is(err, lost):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	assert.Equal(t, 1, won)
+}
@@ -0,0 +1,13 @@
+-- Each event stores the hash of the previous one, forming a chain that reveals
+-- any later modification, insertion, or removal. The unique prev_hash keeps the chain linear.
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id            INTEGER PRIMARY KEY,
+    at            INTEGER NOT NULL,
+    method        TEXT    NOT NULL,
+    actor_user_id INTEGER NOT NULL DEFAULT 0,
+    target        TEXT    NOT NULL DEFAULT '',
+    code          TEXT    NOT NULL,
+    prev_hash     BLOB    NOT NULL UNIQUE,
+    hash          BLOB    NOT NULL
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_email_hash;
+
+ALTER TABLE users DROP COLUMN email_enc;
+ALTER TABLE users DROP COLUMN email_hash;
@@ -0,0 +1,5 @@
+-- When a PII key is configured, the storage layer keeps emails encrypted in email_enc
+-- and their keyed hash in email_hash; the email column then holds the hash in hex.
+ALTER TABLE users ADD COLUMN email_hash BLOB;
+ALTER TABLE users ADD COLUMN email_enc BLOB;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_hash ON users (email_hash);
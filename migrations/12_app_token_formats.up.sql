@@ -0,0 +1 @@
+ALTER TABLE apps ADD COLUMN token_format TEXT NOT NULL DEFAULT 'jwt';
@@ -0,0 +1,2 @@
+-- Tokens of a user issued at or before this moment are rejected; 0 if never revoked.
+ALTER TABLE users ADD COLUMN tokens_revoked_at INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_unfreeze_tokens_user_id;
+DROP TABLE IF EXISTS unfreeze_tokens;
+
+ALTER TABLE users DROP COLUMN frozen_at;
@@ -0,0 +1,13 @@
+-- Frozen accounts cannot log in until their owner unfreezes them; 0 if not frozen.
+ALTER TABLE users ADD COLUMN frozen_at INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS unfreeze_tokens
+(
+    id         INTEGER PRIMARY KEY,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash BLOB NOT NULL UNIQUE,
+    created_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL,
+    used_at    INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_unfreeze_tokens_user_id ON unfreeze_tokens (user_id, created_at);
@@ -0,0 +1,13 @@
+-- Read-only tokens letting support tooling query a single user or app without admin credentials.
+CREATE TABLE IF NOT EXISTS support_tokens
+(
+    id         INTEGER PRIMARY KEY,
+    token_hash BLOB NOT NULL UNIQUE,
+    issued_by  INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    user_id    INTEGER NOT NULL DEFAULT 0, -- user the token is scoped to; 0 if scoped to an app
+    app_id     INTEGER NOT NULL DEFAULT 0, -- app the token is scoped to; 0 if scoped to a user
+    reason     TEXT    NOT NULL,
+    created_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL,
+    revoked_at INTEGER NOT NULL DEFAULT 0
+);
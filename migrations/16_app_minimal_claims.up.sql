@@ -0,0 +1,2 @@
+-- Apps with minimal claims receive tokens carrying only sub, aud, iat, and exp.
+ALTER TABLE apps ADD COLUMN minimal_claims INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_attributes_key_value;
+DROP TABLE IF EXISTS user_attributes;
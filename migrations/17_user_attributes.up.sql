@@ -0,0 +1,11 @@
+-- Custom key/value attributes of users, e.g. department or clearance.
+CREATE TABLE IF NOT EXISTS user_attributes
+(
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    key     TEXT    NOT NULL,
+    value   TEXT    NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+-- Finds the users having an attribute value.
+CREATE INDEX IF NOT EXISTS idx_user_attributes_key_value ON user_attributes (key, value, user_id);
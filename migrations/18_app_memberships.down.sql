@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS app_members;
+ALTER TABLE apps DROP COLUMN require_membership;
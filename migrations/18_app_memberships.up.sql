@@ -0,0 +1,10 @@
+-- Apps requiring membership only let users granted access log in.
+ALTER TABLE apps ADD COLUMN require_membership INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS app_members
+(
+    app_id     INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    granted_at INTEGER NOT NULL,
+    PRIMARY KEY (app_id, user_id)
+);
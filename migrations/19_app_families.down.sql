@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_apps_family_id;
+ALTER TABLE apps DROP COLUMN family_id;
+DROP TABLE IF EXISTS app_families;
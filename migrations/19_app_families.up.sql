@@ -0,0 +1,13 @@
+-- Apps in a family sign tokens with the family secret, so tokens issued for one app
+-- are accepted by the others.
+CREATE TABLE IF NOT EXISTS app_families
+(
+    id         INTEGER PRIMARY KEY,
+    name       TEXT    NOT NULL UNIQUE,
+    secret     TEXT    NOT NULL UNIQUE,
+    created_at INTEGER NOT NULL
+);
+
+ALTER TABLE apps ADD COLUMN family_id INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_apps_family_id ON apps (family_id);
@@ -0,0 +1,26 @@
+-- Migration 19 drops family_id, which SQLite refuses for a column with a constraint.
+CREATE TABLE apps_old
+(
+    id                      INTEGER PRIMARY KEY,
+    name                    TEXT    NOT NULL UNIQUE,
+    secret                  TEXT    NOT NULL UNIQUE,
+    disposable_email_policy TEXT    NOT NULL DEFAULT '',
+    created_at              INTEGER NOT NULL DEFAULT 0,
+    updated_at              INTEGER NOT NULL DEFAULT 0,
+    version                 INTEGER NOT NULL DEFAULT 1,
+    token_format            TEXT    NOT NULL DEFAULT 'jwt',
+    minimal_claims          INTEGER NOT NULL DEFAULT 0,
+    require_membership      INTEGER NOT NULL DEFAULT 0,
+    family_id               INTEGER
+);
+
+INSERT INTO apps_old (id, name, secret, disposable_email_policy, created_at, updated_at, version,
+                      token_format, minimal_claims, require_membership, family_id)
+SELECT id, name, secret, disposable_email_policy, created_at, updated_at, version,
+       token_format, minimal_claims, require_membership, family_id
+FROM apps;
+
+DROP TABLE apps;
+ALTER TABLE apps_old RENAME TO apps;
+
+CREATE INDEX IF NOT EXISTS idx_apps_family_id ON apps (family_id);
@@ -0,0 +1,39 @@
+-- Foreign keys are enforced from now on, so rows left behind by deleted apps
+-- are removed first, as enforcement would have.
+DELETE FROM app_email_domains WHERE app_id NOT IN (SELECT id FROM apps);
+DELETE FROM app_members WHERE app_id NOT IN (SELECT id FROM apps) OR user_id NOT IN (SELECT id FROM users);
+DELETE FROM device_authorizations WHERE app_id NOT IN (SELECT id FROM apps) OR user_id NOT IN (SELECT id FROM users);
+DELETE FROM login_days WHERE user_id NOT IN (SELECT id FROM users);
+DELETE FROM password_reset_tokens WHERE user_id NOT IN (SELECT id FROM users);
+DELETE FROM unfreeze_tokens WHERE user_id NOT IN (SELECT id FROM users);
+DELETE FROM support_tokens WHERE issued_by NOT IN (SELECT id FROM users);
+DELETE FROM user_attributes WHERE user_id NOT IN (SELECT id FROM users);
+UPDATE apps SET family_id = NULL WHERE family_id NOT IN (SELECT id FROM app_families);
+
+-- SQLite cannot add a constraint to an existing column, so apps is rebuilt to make
+-- its family reference one. Families cannot be deleted while apps belong to them.
+CREATE TABLE apps_new
+(
+    id                      INTEGER PRIMARY KEY,
+    name                    TEXT    NOT NULL UNIQUE,
+    secret                  TEXT    NOT NULL UNIQUE,
+    disposable_email_policy TEXT    NOT NULL DEFAULT '',
+    created_at              INTEGER NOT NULL DEFAULT 0,
+    updated_at              INTEGER NOT NULL DEFAULT 0,
+    version                 INTEGER NOT NULL DEFAULT 1,
+    token_format            TEXT    NOT NULL DEFAULT 'jwt',
+    minimal_claims          INTEGER NOT NULL DEFAULT 0,
+    require_membership      INTEGER NOT NULL DEFAULT 0,
+    family_id               INTEGER REFERENCES app_families (id) ON DELETE RESTRICT
+);
+
+INSERT INTO apps_new (id, name, secret, disposable_email_policy, created_at, updated_at, version,
+                      token_format, minimal_claims, require_membership, family_id)
+SELECT id, name, secret, disposable_email_policy, created_at, updated_at, version,
+       token_format, minimal_claims, require_membership, family_id
+FROM apps;
+
+DROP TABLE apps;
+ALTER TABLE apps_new RENAME TO apps;
+
+CREATE INDEX IF NOT EXISTS idx_apps_family_id ON apps (family_id);
@@ -0,0 +1,2 @@
+-- Accounts merged into another user are kept as tombstones pointing to it; 0 if not merged.
+ALTER TABLE users ADD COLUMN merged_into INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS registrations
+(
+    id                   INTEGER PRIMARY KEY,
+    registration_id_hash BLOB    NOT NULL UNIQUE,
+    user_id              INTEGER REFERENCES users (id) ON DELETE CASCADE,
+    status               TEXT    NOT NULL DEFAULT 'pending',
+    failure_reason       TEXT    NOT NULL DEFAULT '',
+    created_at           INTEGER NOT NULL,
+    expires_at           INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_registrations_expires_at ON registrations (expires_at);
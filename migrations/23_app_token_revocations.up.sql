@@ -0,0 +1,2 @@
+-- Tokens issued for the app at or before this moment are rejected; 0 if never revoked.
+ALTER TABLE apps ADD COLUMN tokens_revoked_at INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_app_owners_user_id;
+
+DROP TABLE IF EXISTS app_owners;
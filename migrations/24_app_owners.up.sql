@@ -0,0 +1,9 @@
+-- Owners manage an app through the admin API without being admins.
+CREATE TABLE IF NOT EXISTS app_owners
+(
+    app_id   INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    user_id  INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    added_at INTEGER NOT NULL,
+    PRIMARY KEY (app_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_app_owners_user_id ON app_owners (user_id);
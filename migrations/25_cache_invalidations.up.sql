@@ -0,0 +1,10 @@
+-- Replicas sharing the database poll this table to drop entries of their in-process caches
+-- that another replica changed.
+CREATE TABLE IF NOT EXISTS cache_invalidations
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    topic      TEXT    NOT NULL,
+    key        TEXT    NOT NULL,
+    created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_cache_invalidations_created_at ON cache_invalidations (created_at);
@@ -0,0 +1,11 @@
+-- Daily rollups of authenticated requests per app and user, for chargeback and
+-- spotting misbehaving clients. Rows outlive deleted apps and users on purpose.
+CREATE TABLE IF NOT EXISTS usage_daily
+(
+    day      INTEGER NOT NULL, -- midnight UTC of the day
+    app_id   INTEGER NOT NULL,
+    user_id  INTEGER NOT NULL,
+    requests INTEGER NOT NULL DEFAULT 0,
+    errors   INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, app_id, user_id)
+);
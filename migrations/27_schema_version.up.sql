@@ -0,0 +1,10 @@
+-- The schema version, checked by the service at startup. Every later migration sets
+-- version to its own number, and compatible_from to the oldest version whose binaries
+-- can still use the schema: unchanged for additive migrations, its own number otherwise.
+CREATE TABLE IF NOT EXISTS schema_version
+(
+    id              INTEGER PRIMARY KEY CHECK (id = 1),
+    version         INTEGER NOT NULL,
+    compatible_from INTEGER NOT NULL
+);
+INSERT INTO schema_version (id, version, compatible_from) VALUES (1, 27, 27);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS announcement;
+UPDATE schema_version SET version = 27;
@@ -0,0 +1,10 @@
+-- The announcement operators attach to every response, e.g. of upcoming maintenance.
+-- There is at most one; clearing it deletes the row.
+CREATE TABLE IF NOT EXISTS announcement
+(
+    id         INTEGER PRIMARY KEY CHECK (id = 1),
+    message    TEXT    NOT NULL,
+    created_at INTEGER NOT NULL,
+    expires_at INTEGER -- NULL if shown until cleared
+);
+UPDATE schema_version SET version = 28;
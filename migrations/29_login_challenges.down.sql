@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_login_challenges_user_id;
+DROP TABLE IF EXISTS login_challenges;
+
+ALTER TABLE users DROP COLUMN push_mfa;
+
+UPDATE schema_version SET version = 28;
@@ -0,0 +1,17 @@
+ALTER TABLE users ADD COLUMN push_mfa BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS login_challenges
+(
+    id             INTEGER PRIMARY KEY,
+    challenge_hash BLOB    NOT NULL UNIQUE,
+    user_id        INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id         INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    status         TEXT    NOT NULL DEFAULT 'pending',
+    ip             TEXT    NOT NULL DEFAULT '',
+    user_agent     TEXT    NOT NULL DEFAULT '',
+    created_at     INTEGER NOT NULL,
+    expires_at     INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_login_challenges_user_id ON login_challenges (user_id, status);
+
+UPDATE schema_version SET version = 29;
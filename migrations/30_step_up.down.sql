@@ -0,0 +1,3 @@
+ALTER TABLE login_challenges DROP COLUMN action;
+
+UPDATE schema_version SET version = 29;
@@ -0,0 +1,3 @@
+ALTER TABLE login_challenges ADD COLUMN action TEXT NOT NULL DEFAULT '';
+
+UPDATE schema_version SET version = 30;
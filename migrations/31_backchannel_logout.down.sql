@@ -0,0 +1,3 @@
+ALTER TABLE apps DROP COLUMN backchannel_logout_uri;
+
+UPDATE schema_version SET version = 30;
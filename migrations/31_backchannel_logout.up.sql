@@ -0,0 +1,4 @@
+-- URI logout tokens are POSTed to when a user's tokens are revoked; empty if the app does not take them.
+ALTER TABLE apps ADD COLUMN backchannel_logout_uri TEXT NOT NULL DEFAULT '';
+
+UPDATE schema_version SET version = 31;
@@ -0,0 +1,3 @@
+ALTER TABLE audit_events DROP COLUMN source_ip;
+
+UPDATE schema_version SET version = 31;
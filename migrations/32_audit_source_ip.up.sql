@@ -0,0 +1,4 @@
+-- Address of the client that made the call; empty for events recorded before it was kept.
+ALTER TABLE audit_events ADD COLUMN source_ip TEXT NOT NULL DEFAULT '';
+
+UPDATE schema_version SET version = 32;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_expires_at;
+DROP INDEX IF EXISTS idx_refresh_tokens_chain_id;
+DROP TABLE IF EXISTS refresh_tokens;
+
+UPDATE schema_version SET version = 32;
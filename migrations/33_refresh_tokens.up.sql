@@ -0,0 +1,20 @@
+-- Refresh tokens issued at login. Every rotation inserts the next token of the chain, with
+-- the chain_id of the first one, and marks the previous token revoked.
+CREATE TABLE IF NOT EXISTS refresh_tokens
+(
+    id         INTEGER PRIMARY KEY,
+    token_hash BLOB    NOT NULL UNIQUE,
+    chain_id   INTEGER NOT NULL DEFAULT 0,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id     INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    auth_time  INTEGER NOT NULL DEFAULT 0,
+    amr        TEXT    NOT NULL DEFAULT '',
+    acr        TEXT    NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL,
+    revoked_at INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_chain_id ON refresh_tokens (chain_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON refresh_tokens (expires_at);
+
+UPDATE schema_version SET version = 33;
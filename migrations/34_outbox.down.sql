@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_outbox_next_attempt_at;
+DROP TABLE IF EXISTS outbox;
+
+UPDATE schema_version SET version = 33;
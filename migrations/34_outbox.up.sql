@@ -0,0 +1,17 @@
+-- Messages for other systems, e.g. back-channel logouts, written in the same transaction as
+-- the change they report so a crash cannot lose them. They are deleted once delivered, and
+-- kept with failed_at set once their handler gives up.
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id              INTEGER PRIMARY KEY,
+    topic           TEXT    NOT NULL,
+    payload         TEXT    NOT NULL DEFAULT '{}',
+    created_at      INTEGER NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at INTEGER NOT NULL,
+    last_error      TEXT    NOT NULL DEFAULT '',
+    failed_at       INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_next_attempt_at ON outbox (failed_at, next_attempt_at);
+
+UPDATE schema_version SET version = 34;
@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN avatar;
+
+UPDATE schema_version SET version = 34;
@@ -0,0 +1,4 @@
+-- Key of the user's avatar in blob storage, derived from its content; empty if none.
+ALTER TABLE users ADD COLUMN avatar TEXT NOT NULL DEFAULT '';
+
+UPDATE schema_version SET version = 35;
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS users_email_trigrams_update;
+DROP TRIGGER IF EXISTS users_email_trigrams_insert;
+DROP TABLE IF EXISTS trigram_positions;
+DROP TABLE IF EXISTS user_email_trigrams;
+DROP INDEX IF EXISTS idx_users_email_lower;
+
+UPDATE schema_version SET version = 35;
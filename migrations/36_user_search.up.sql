@@ -0,0 +1,45 @@
+-- Case-insensitive prefix search on emails by SearchUsers.
+CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users (lower(email));
+
+-- Trigrams of the emails stored in plain text, for fuzzy search by SearchUsers. Triggers
+-- keep them in sync, and drop them once an email is encrypted, since the email column
+-- then holds its hash.
+CREATE TABLE IF NOT EXISTS user_email_trigrams (
+    trigram TEXT    NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (trigram, user_id)
+) WITHOUT ROWID;
+CREATE INDEX IF NOT EXISTS idx_user_email_trigrams_user_id ON user_email_trigrams (user_id);
+
+-- Positions of the trigrams of an email, since triggers cannot use recursive queries.
+-- Emails are at most 254 characters long.
+CREATE TABLE IF NOT EXISTS trigram_positions (
+    pos INTEGER PRIMARY KEY
+);
+WITH RECURSIVE p (pos) AS (SELECT 1 UNION ALL SELECT pos + 1 FROM p WHERE pos < 252)
+INSERT INTO trigram_positions (pos) SELECT pos FROM p;
+
+INSERT OR IGNORE INTO user_email_trigrams (trigram, user_id)
+    SELECT substr(lower(u.email), p.pos, 3), u.id
+    FROM users u JOIN trigram_positions p ON p.pos <= length(u.email) - 2
+    WHERE u.email_enc IS NULL;
+
+CREATE TRIGGER IF NOT EXISTS users_email_trigrams_insert
+    AFTER INSERT ON users
+    WHEN NEW.email_enc IS NULL
+BEGIN
+    INSERT OR IGNORE INTO user_email_trigrams (trigram, user_id)
+        SELECT substr(lower(NEW.email), pos, 3), NEW.id
+        FROM trigram_positions WHERE pos <= length(NEW.email) - 2;
+END;
+
+CREATE TRIGGER IF NOT EXISTS users_email_trigrams_update
+    AFTER UPDATE OF email, email_enc ON users
+BEGIN
+    DELETE FROM user_email_trigrams WHERE user_id = OLD.id;
+    INSERT OR IGNORE INTO user_email_trigrams (trigram, user_id)
+        SELECT substr(lower(NEW.email), pos, 3), NEW.id
+        FROM trigram_positions WHERE pos <= length(NEW.email) - 2 AND NEW.email_enc IS NULL;
+END;
+
+UPDATE schema_version SET version = 36;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS app_labels;
+DROP TABLE IF EXISTS user_labels;
+
+UPDATE schema_version SET version = 36;
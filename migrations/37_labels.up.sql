@@ -0,0 +1,23 @@
+-- Free-form labels operators put on users and apps, e.g. to mark test accounts, migration
+-- cohorts, or risk tiers. Unlike user attributes, they never reach tokens.
+CREATE TABLE IF NOT EXISTS user_labels
+(
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    key     TEXT    NOT NULL,
+    value   TEXT    NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
+
+CREATE TABLE IF NOT EXISTS app_labels
+(
+    app_id INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    key    TEXT    NOT NULL,
+    value  TEXT    NOT NULL,
+    PRIMARY KEY (app_id, key)
+);
+
+-- Find the records having a label value, for label filters.
+CREATE INDEX IF NOT EXISTS idx_user_labels_key_value ON user_labels (key, value, user_id);
+CREATE INDEX IF NOT EXISTS idx_app_labels_key_value ON app_labels (key, value, app_id);
+
+UPDATE schema_version SET version = 37;
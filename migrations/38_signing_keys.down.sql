@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS signing_keys;
+
+UPDATE schema_version SET version = 37;
@@ -0,0 +1,14 @@
+-- RSA keys RS256 tokens are signed with, created by key rotation. The key without retired_at
+-- signs new tokens; retired ones keep verifying tokens for token_signing.grace_period, and
+-- only their public key is kept. Private keys are encrypted with the PII key if one is set.
+CREATE TABLE IF NOT EXISTS signing_keys
+(
+    id          TEXT PRIMARY KEY,
+    private_key BLOB,
+    public_key  BLOB    NOT NULL,
+    created_at  INTEGER NOT NULL DEFAULT 0,
+    retired_at  INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_signing_keys_retired_at ON signing_keys (retired_at);
+
+UPDATE schema_version SET version = 38;
@@ -0,0 +1,17 @@
+DROP TRIGGER IF EXISTS user_labels_history_delete;
+DROP TRIGGER IF EXISTS user_labels_history_insert;
+DROP TRIGGER IF EXISTS user_attributes_history_delete;
+DROP TRIGGER IF EXISTS user_attributes_history_insert;
+DROP TRIGGER IF EXISTS app_owners_history_delete;
+DROP TRIGGER IF EXISTS app_owners_history_insert;
+DROP TRIGGER IF EXISTS app_members_history_delete;
+DROP TRIGGER IF EXISTS app_members_history_insert;
+DROP TRIGGER IF EXISTS users_history_push_mfa;
+DROP TRIGGER IF EXISTS users_history_merged_into;
+DROP TRIGGER IF EXISTS users_history_frozen_at;
+DROP TRIGGER IF EXISTS users_history_is_admin;
+DROP TRIGGER IF EXISTS users_history_insert;
+DROP INDEX IF EXISTS idx_user_history_user_id;
+DROP TABLE IF EXISTS user_history;
+
+UPDATE schema_version SET version = 38;
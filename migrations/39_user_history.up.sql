@@ -0,0 +1,142 @@
+-- Temporal history of the state of users, for point-in-time queries by GetUserAtTime. Every
+-- row is a fact that held from valid_from until valid_to, exclusive, or until now while
+-- valid_to is NULL: an account setting (kind 'account', keyed by column), an app membership
+-- or ownership ('member' and 'owner', keyed by app ID), an attribute, or a label. Triggers
+-- keep it in sync with the tables it mirrors, so every writer is covered. Times are in
+-- seconds, so a fact replaced within the second it appeared never shows.
+CREATE TABLE IF NOT EXISTS user_history (
+    id         INTEGER PRIMARY KEY,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    kind       TEXT    NOT NULL,
+    key        TEXT    NOT NULL,
+    value      TEXT    NOT NULL DEFAULT '',
+    valid_from INTEGER NOT NULL,
+    valid_to   INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_user_history_user_id ON user_history (user_id, kind, key, valid_to);
+
+-- History starts now for existing users; their earlier state is unknown.
+INSERT INTO user_history (user_id, kind, key, value, valid_from)
+    SELECT id, 'account', 'is_admin', is_admin, CAST(strftime('%s', 'now') AS INTEGER) FROM users
+    UNION ALL
+    SELECT id, 'account', 'frozen_at', frozen_at, CAST(strftime('%s', 'now') AS INTEGER) FROM users
+    UNION ALL
+    SELECT id, 'account', 'merged_into', merged_into, CAST(strftime('%s', 'now') AS INTEGER) FROM users
+    UNION ALL
+    SELECT id, 'account', 'push_mfa', push_mfa, CAST(strftime('%s', 'now') AS INTEGER) FROM users
+    UNION ALL
+    SELECT user_id, 'member', app_id, '', CAST(strftime('%s', 'now') AS INTEGER) FROM app_members
+    UNION ALL
+    SELECT user_id, 'owner', app_id, '', CAST(strftime('%s', 'now') AS INTEGER) FROM app_owners
+    UNION ALL
+    SELECT user_id, 'attribute', key, value, CAST(strftime('%s', 'now') AS INTEGER) FROM user_attributes
+    UNION ALL
+    SELECT user_id, 'label', key, value, CAST(strftime('%s', 'now') AS INTEGER) FROM user_labels;
+
+CREATE TRIGGER IF NOT EXISTS users_history_insert
+    AFTER INSERT ON users
+BEGIN
+    INSERT INTO user_history (user_id, kind, key, value, valid_from) VALUES
+        (NEW.id, 'account', 'is_admin', NEW.is_admin, CAST(strftime('%s', 'now') AS INTEGER)),
+        (NEW.id, 'account', 'frozen_at', NEW.frozen_at, CAST(strftime('%s', 'now') AS INTEGER)),
+        (NEW.id, 'account', 'merged_into', NEW.merged_into, CAST(strftime('%s', 'now') AS INTEGER)),
+        (NEW.id, 'account', 'push_mfa', NEW.push_mfa, CAST(strftime('%s', 'now') AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS users_history_is_admin
+    AFTER UPDATE OF is_admin ON users
+    WHEN OLD.is_admin IS NOT NEW.is_admin
+BEGIN
+    UPDATE user_history SET valid_to = CAST(strftime('%s', 'now') AS INTEGER)
+        WHERE user_id = NEW.id AND kind = 'account' AND key = 'is_admin' AND valid_to IS NULL;
+    INSERT INTO user_history (user_id, kind, key, value, valid_from)
+        VALUES (NEW.id, 'account', 'is_admin', NEW.is_admin, CAST(strftime('%s', 'now') AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS users_history_frozen_at
+    AFTER UPDATE OF frozen_at ON users
+    WHEN OLD.frozen_at IS NOT NEW.frozen_at
+BEGIN
+    UPDATE user_history SET valid_to = CAST(strftime('%s', 'now') AS INTEGER)
+        WHERE user_id = NEW.id AND kind = 'account' AND key = 'frozen_at' AND valid_to IS NULL;
+    INSERT INTO user_history (user_id, kind, key, value, valid_from)
+        VALUES (NEW.id, 'account', 'frozen_at', NEW.frozen_at, CAST(strftime('%s', 'now') AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS users_history_merged_into
+    AFTER UPDATE OF merged_into ON users
+    WHEN OLD.merged_into IS NOT NEW.merged_into
+BEGIN
+    UPDATE user_history SET valid_to = CAST(strftime('%s', 'now') AS INTEGER)
+        WHERE user_id = NEW.id AND kind = 'account' AND key = 'merged_into' AND valid_to IS NULL;
+    INSERT INTO user_history (user_id, kind, key, value, valid_from)
+        VALUES (NEW.id, 'account', 'merged_into', NEW.merged_into, CAST(strftime('%s', 'now') AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS users_history_push_mfa
+    AFTER UPDATE OF push_mfa ON users
+    WHEN OLD.push_mfa IS NOT NEW.push_mfa
+BEGIN
+    UPDATE user_history SET valid_to = CAST(strftime('%s', 'now') AS INTEGER)
+        WHERE user_id = NEW.id AND kind = 'account' AND key = 'push_mfa' AND valid_to IS NULL;
+    INSERT INTO user_history (user_id, kind, key, value, valid_from)
+        VALUES (NEW.id, 'account', 'push_mfa', NEW.push_mfa, CAST(strftime('%s', 'now') AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS app_members_history_insert
+    AFTER INSERT ON app_members
+BEGIN
+    INSERT INTO user_history (user_id, kind, key, valid_from)
+        VALUES (NEW.user_id, 'member', NEW.app_id, CAST(strftime('%s', 'now') AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS app_members_history_delete
+    AFTER DELETE ON app_members
+BEGIN
+    UPDATE user_history SET valid_to = CAST(strftime('%s', 'now') AS INTEGER)
+        WHERE user_id = OLD.user_id AND kind = 'member' AND key = CAST(OLD.app_id AS TEXT) AND valid_to IS NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS app_owners_history_insert
+    AFTER INSERT ON app_owners
+BEGIN
+    INSERT INTO user_history (user_id, kind, key, valid_from)
+        VALUES (NEW.user_id, 'owner', NEW.app_id, CAST(strftime('%s', 'now') AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS app_owners_history_delete
+    AFTER DELETE ON app_owners
+BEGIN
+    UPDATE user_history SET valid_to = CAST(strftime('%s', 'now') AS INTEGER)
+        WHERE user_id = OLD.user_id AND kind = 'owner' AND key = CAST(OLD.app_id AS TEXT) AND valid_to IS NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS user_attributes_history_insert
+    AFTER INSERT ON user_attributes
+BEGIN
+    INSERT INTO user_history (user_id, kind, key, value, valid_from)
+        VALUES (NEW.user_id, 'attribute', NEW.key, NEW.value, CAST(strftime('%s', 'now') AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS user_attributes_history_delete
+    AFTER DELETE ON user_attributes
+BEGIN
+    UPDATE user_history SET valid_to = CAST(strftime('%s', 'now') AS INTEGER)
+        WHERE user_id = OLD.user_id AND kind = 'attribute' AND key = OLD.key AND valid_to IS NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS user_labels_history_insert
+    AFTER INSERT ON user_labels
+BEGIN
+    INSERT INTO user_history (user_id, kind, key, value, valid_from)
+        VALUES (NEW.user_id, 'label', NEW.key, NEW.value, CAST(strftime('%s', 'now') AS INTEGER));
+END;
+
+CREATE TRIGGER IF NOT EXISTS user_labels_history_delete
+    AFTER DELETE ON user_labels
+BEGIN
+    UPDATE user_history SET valid_to = CAST(strftime('%s', 'now') AS INTEGER)
+        WHERE user_id = OLD.user_id AND kind = 'label' AND key = OLD.key AND valid_to IS NULL;
+END;
+
+UPDATE schema_version SET version = 39;
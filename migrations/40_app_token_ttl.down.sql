@@ -0,0 +1,3 @@
+ALTER TABLE apps DROP COLUMN token_ttl;
+
+UPDATE schema_version SET version = 39;
@@ -0,0 +1,4 @@
+-- Lifetime of tokens issued for the app, in seconds; 0 uses the token_ttl of the config.
+ALTER TABLE apps ADD COLUMN token_ttl INTEGER NOT NULL DEFAULT 0;
+
+UPDATE schema_version SET version = 40;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS sessions;
+
+UPDATE schema_version SET version = 40;
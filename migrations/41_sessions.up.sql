@@ -0,0 +1,18 @@
+-- Sessions of users: one per refresh token chain, identified by the chain's ID, recording
+-- the client it was issued to at login. A session is active while it is unexpired and its
+-- chain has an unrevoked token.
+CREATE TABLE IF NOT EXISTS sessions
+(
+    id           INTEGER PRIMARY KEY,
+    user_id      INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id       INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    ip           TEXT    NOT NULL DEFAULT '',
+    user_agent   TEXT    NOT NULL DEFAULT '',
+    created_at   INTEGER NOT NULL,
+    last_used_at INTEGER NOT NULL,
+    expires_at   INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_expires_at ON sessions (expires_at);
+
+UPDATE schema_version SET version = 41;
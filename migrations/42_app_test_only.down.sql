@@ -0,0 +1,3 @@
+ALTER TABLE apps DROP COLUMN test_only;
+
+UPDATE schema_version SET version = 41;
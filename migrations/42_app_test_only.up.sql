@@ -0,0 +1,4 @@
+-- Whether MintTestToken may mint tokens for the app.
+ALTER TABLE apps ADD COLUMN test_only INTEGER NOT NULL DEFAULT 0;
+
+UPDATE schema_version SET version = 42;
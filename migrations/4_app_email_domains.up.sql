@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS app_email_domains
+(
+    app_id INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    domain TEXT    NOT NULL,
+    denied BOOLEAN NOT NULL,
+    PRIMARY KEY (app_id, domain)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE apps DROP COLUMN disposable_email_policy;
+
+ALTER TABLE users DROP COLUMN disposable_email;
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN disposable_email BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE apps ADD COLUMN disposable_email_policy TEXT NOT NULL DEFAULT '';
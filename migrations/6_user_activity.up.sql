@@ -0,0 +1,11 @@
+-- Users registered before this migration have an unknown registration time of 0.
+ALTER TABLE users ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at);
+
+-- One row per user and UTC day (days since the Unix epoch) with at least one login.
+CREATE TABLE IF NOT EXISTS login_days
+(
+    day     INTEGER NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (day, user_id)
+);
@@ -0,0 +1,6 @@
+ALTER TABLE apps DROP COLUMN version;
+ALTER TABLE apps DROP COLUMN updated_at;
+ALTER TABLE apps DROP COLUMN created_at;
+
+ALTER TABLE users DROP COLUMN version;
+ALTER TABLE users DROP COLUMN updated_at;
@@ -0,0 +1,7 @@
+-- Rows created before this migration have unknown timestamps of 0.
+ALTER TABLE users ADD COLUMN updated_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE apps ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE apps ADD COLUMN updated_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE apps ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS users_default_public_id;
+DROP INDEX IF EXISTS idx_users_public_id;
+ALTER TABLE users DROP COLUMN public_id;
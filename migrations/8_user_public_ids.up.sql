@@ -0,0 +1,12 @@
+-- Existing users keep their sequential ID, in decimal form, as public ID.
+ALTER TABLE users ADD COLUMN public_id TEXT;
+UPDATE users SET public_id = CAST(id AS TEXT);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_public_id ON users (public_id);
+
+-- Users inserted without a public ID get the decimal form of their sequential ID.
+CREATE TRIGGER IF NOT EXISTS users_default_public_id
+    AFTER INSERT ON users
+    WHEN NEW.public_id IS NULL
+BEGIN
+    UPDATE users SET public_id = CAST(NEW.id AS TEXT) WHERE id = NEW.id;
+END;
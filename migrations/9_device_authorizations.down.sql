@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_device_authorizations_expires_at;
+
+DROP TABLE IF EXISTS device_authorizations;
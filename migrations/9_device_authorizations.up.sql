@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS device_authorizations
+(
+    id               INTEGER PRIMARY KEY,
+    device_code_hash BLOB    NOT NULL UNIQUE,
+    user_code        TEXT    NOT NULL UNIQUE,
+    app_id           INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    user_id          INTEGER REFERENCES users (id) ON DELETE CASCADE,
+    status           TEXT    NOT NULL DEFAULT 'pending',
+    interval_seconds INTEGER NOT NULL,
+    created_at       INTEGER NOT NULL,
+    expires_at       INTEGER NOT NULL,
+    last_polled_at   INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_device_authorizations_expires_at ON device_authorizations (expires_at);
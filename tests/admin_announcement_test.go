@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+// TestAnnouncement_SetAndClear is the only test changing the announcement, as it is
+// shared by every test running against the server.
+func TestAnnouncement_SetAndClear(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	const message = "Maintenance on Sunday 02:00-03:00 UTC"
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	_, err := st.AdminClient.SetAnnouncement(adminCtx, &adminpb.SetAnnouncementRequest{
+		Message:   message,
+		ExpiresAt: timestamppb.New(expiresAt),
+	})
+	require.NoError(t, err)
+
+	var header metadata.MD
+	resp, err := st.AuthClient.GetServiceStatus(ctx, &pb.GetServiceStatusRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, message, resp.GetAnnouncement())
+	assert.WithinDuration(t, time.Now(), resp.GetAnnouncedAt().AsTime(), time.Minute)
+	assert.True(t, expiresAt.Equal(resp.GetExpiresAt().AsTime()))
+	assert.Equal(t, []string{message}, header.Get("sso-announcement"))
+
+	// Failed calls carry the announcement as well.
+	header = nil
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{}, grpc.Header(&header))
+	require.Error(t, err)
+	assert.Equal(t, []string{message}, header.Get("sso-announcement"))
+
+	_, err = st.AdminClient.SetAnnouncement(adminCtx, &adminpb.SetAnnouncementRequest{})
+	require.NoError(t, err)
+
+	header = nil
+	resp, err = st.AuthClient.GetServiceStatus(ctx, &pb.GetServiceStatusRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Empty(t, resp.GetAnnouncement())
+	assert.Nil(t, resp.GetAnnouncedAt())
+	assert.Empty(t, header.Get("sso-announcement"))
+}
+
+func TestSetAnnouncement_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	tests := []struct {
+		name        string
+		req         *adminpb.SetAnnouncementRequest
+		expectedErr string
+	}{
+		{
+			name:        "Non-ASCII message",
+			req:         &adminpb.SetAnnouncementRequest{Message: "Wartung am Montag – bitte speichern"},
+			expectedErr: "printable ASCII",
+		},
+		{
+			name: "Expiry in the past",
+			req: &adminpb.SetAnnouncementRequest{
+				Message:   "Maintenance",
+				ExpiresAt: timestamppb.New(time.Now().Add(-time.Hour)),
+			},
+			expectedErr: "expires_at",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.SetAnnouncement(adminCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+
+	_, err := st.AdminClient.SetAnnouncement(ctx, &adminpb.SetAnnouncementRequest{Message: "Maintenance"})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestAppFamily_SharedAudience(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	familySecret := gofakeit.UUID()
+
+	respFamily, err := st.AdminClient.CreateAppFamily(adminCtx, &adminpb.CreateAppFamilyRequest{
+		Name:   "test-" + gofakeit.UUID(),
+		Secret: familySecret,
+	})
+	require.NoError(t, err)
+
+	familyID := respFamily.GetFamilyId()
+
+	respFrontend, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:     "test-" + gofakeit.UUID(),
+		Secret:   gofakeit.UUID(),
+		FamilyId: familyID,
+	})
+	require.NoError(t, err)
+
+	respBackend, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:   "test-" + gofakeit.UUID(),
+		Secret: gofakeit.UUID(),
+	})
+	require.NoError(t, err)
+
+	respApp, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: respBackend.GetAppId()})
+	require.NoError(t, err)
+
+	respUpdate, err := st.AdminClient.UpdateApp(adminCtx, &adminpb.UpdateAppRequest{
+		AppId:    respBackend.GetAppId(),
+		Version:  respApp.GetApp().GetVersion(),
+		FamilyId: proto.Int32(familyID),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, familyID, respUpdate.GetApp().GetFamilyId())
+
+	respGet, err := st.AdminClient.GetAppFamily(adminCtx, &adminpb.GetAppFamilyRequest{FamilyId: familyID})
+	require.NoError(t, err)
+	assert.Equal(t, []int32{respFrontend.GetAppId(), respBackend.GetAppId()}, respGet.GetFamily().GetAppIds())
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err = st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: respFrontend.GetAppId()})
+	require.NoError(t, err)
+
+	// The backend accepts the frontend's token with the family secret.
+	parsed, err := jwt.Parse(respLog.GetToken(), func(token *jwt.Token) (interface{}, error) {
+		return []byte(familySecret), nil
+	}, jwt.WithAudience(strconv.Itoa(int(respBackend.GetAppId()))))
+	require.NoError(t, err)
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+	assert.Equal(t, float64(respFrontend.GetAppId()), claims["app_id"])
+
+	// The service validates family tokens too.
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	respInfo, err := st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, email, respInfo.GetEmail())
+
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.DeleteAppFamily(adminCtx, &adminpb.DeleteAppFamilyRequest{FamilyId: familyID})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// Once the apps leave, the family can be deleted.
+	_, err = st.AdminClient.UpdateApp(adminCtx, &adminpb.UpdateAppRequest{
+		AppId:    respBackend.GetAppId(),
+		Version:  respUpdate.GetApp().GetVersion(),
+		FamilyId: proto.Int32(0),
+	})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.DeleteApp(adminCtx, &adminpb.DeleteAppRequest{AppId: respFrontend.GetAppId()})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.DeleteAppFamily(adminCtx, &adminpb.DeleteAppFamilyRequest{FamilyId: familyID})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.GetAppFamily(adminCtx, &adminpb.GetAppFamilyRequest{FamilyId: familyID})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestAppFamily_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	name := "test-" + gofakeit.UUID()
+
+	respFamily, err := st.AdminClient.CreateAppFamily(adminCtx, &adminpb.CreateAppFamilyRequest{Name: name, Secret: gofakeit.UUID()})
+	require.NoError(t, err)
+
+	// The other apps of the family could sign tokens of the admin app with the family secret.
+	respAdminApp, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: st.Cfg.GRPC.AdminAppID})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.UpdateApp(adminCtx, &adminpb.UpdateAppRequest{
+		AppId:    st.Cfg.GRPC.AdminAppID,
+		Version:  respAdminApp.GetApp().GetVersion(),
+		FamilyId: proto.Int32(respFamily.GetFamilyId()),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = st.AdminClient.CreateAppFamily(adminCtx, &adminpb.CreateAppFamilyRequest{Name: name, Secret: gofakeit.UUID()})
+	require.Error(t, err)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = st.AdminClient.CreateAppFamily(adminCtx, &adminpb.CreateAppFamilyRequest{Name: "test-" + gofakeit.UUID()})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:     "test-" + gofakeit.UUID(),
+		Secret:   gofakeit.UUID(),
+		FamilyId: 1_000_000,
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = st.AdminClient.GetAppFamily(adminCtx, &adminpb.GetAppFamilyRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.AdminClient.DeleteAppFamily(adminCtx, &adminpb.DeleteAppFamilyRequest{FamilyId: 1_000_000})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
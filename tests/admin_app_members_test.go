@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestAppMembership_GrantRevoke(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respCreate, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:              "test-" + gofakeit.UUID(),
+		Secret:            gofakeit.UUID(),
+		RequireMembership: true,
+	})
+	require.NoError(t, err)
+
+	appID := respCreate.GetAppId()
+
+	respApp, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: appID})
+	require.NoError(t, err)
+	assert.True(t, respApp.GetApp().GetRequireMembership())
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Apps not requiring membership are unaffected.
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.GrantAppAccess(adminCtx, &adminpb.GrantAppAccessRequest{AppId: appID, PublicId: respReg.GetPublicId()})
+	require.NoError(t, err)
+
+	// Granting access twice is a no-op.
+	_, err = st.AdminClient.GrantAppAccess(adminCtx, &adminpb.GrantAppAccessRequest{AppId: appID, UserId: respReg.GetUserId()})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.RevokeAppAccess(adminCtx, &adminpb.RevokeAppAccessRequest{AppId: appID, PublicId: respReg.GetPublicId()})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAppMembership_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		req  *adminpb.GrantAppAccessRequest
+		code codes.Code
+	}{
+		{
+			name: "Missing app ID",
+			req:  &adminpb.GrantAppAccessRequest{UserId: respReg.GetUserId()},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Missing user",
+			req:  &adminpb.GrantAppAccessRequest{AppId: st.AppID},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Unknown app",
+			req:  &adminpb.GrantAppAccessRequest{AppId: 1_000_000, UserId: respReg.GetUserId()},
+			code: codes.NotFound,
+		},
+		{
+			name: "Unknown user",
+			req:  &adminpb.GrantAppAccessRequest{AppId: st.AppID, UserId: respReg.GetUserId() + 1_000_000},
+			code: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.GrantAppAccess(adminCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+
+	_, err = st.AdminClient.RevokeAppAccess(adminCtx, &adminpb.RevokeAppAccessRequest{AppId: 1_000_000, UserId: respReg.GetUserId()})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestAppOwners_ManageOwnApp(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respCreate, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:   "test-" + gofakeit.UUID(),
+		Secret: gofakeit.UUID(),
+	})
+	require.NoError(t, err)
+
+	appID := respCreate.GetAppId()
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	// Owners sign in to the admin app, like admins do.
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.Cfg.GRPC.AdminAppID})
+	require.NoError(t, err)
+
+	ownerCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	// Users who own no app are not let in.
+	_, err = st.AdminClient.GetApp(ownerCtx, &adminpb.GetAppRequest{AppId: appID})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = st.AdminClient.AddAppOwner(adminCtx, &adminpb.AddAppOwnerRequest{AppId: appID, PublicId: respReg.GetPublicId()})
+	require.NoError(t, err)
+
+	respOwners, err := st.AdminClient.ListAppOwners(ownerCtx, &adminpb.ListAppOwnersRequest{AppId: appID})
+	require.NoError(t, err)
+	require.Len(t, respOwners.GetOwners(), 1)
+	assert.Equal(t, respReg.GetPublicId(), respOwners.GetOwners()[0].GetPublicId())
+
+	respApp, err := st.AdminClient.GetApp(ownerCtx, &adminpb.GetAppRequest{AppId: appID})
+	require.NoError(t, err)
+
+	respUpdate, err := st.AdminClient.UpdateApp(ownerCtx, &adminpb.UpdateAppRequest{
+		AppId:   appID,
+		Version: respApp.GetApp().GetVersion(),
+		Name:    "test-" + gofakeit.UUID(),
+	})
+	require.NoError(t, err)
+
+	// A secret chosen by the owner would let them sign tokens of the app for any user.
+	_, err = st.AdminClient.UpdateApp(ownerCtx, &adminpb.UpdateAppRequest{
+		AppId:   appID,
+		Version: respUpdate.GetApp().GetVersion(),
+		Secret:  gofakeit.UUID(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Owners rotate the secret to one the service generates instead.
+	respRotate, err := st.AdminClient.RotateAppSecret(ownerCtx, &adminpb.RotateAppSecretRequest{AppId: appID})
+	require.NoError(t, err)
+	require.NotEmpty(t, respRotate.GetSecret())
+	assert.Greater(t, respRotate.GetApp().GetVersion(), respUpdate.GetApp().GetVersion())
+
+	respAppLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.NoError(t, err)
+
+	_, err = jwt.Parse(respAppLog.GetToken(), func(token *jwt.Token) (interface{}, error) {
+		return []byte(respRotate.GetSecret()), nil
+	})
+	require.NoError(t, err)
+
+	respUpdate = &adminpb.UpdateAppResponse{App: respRotate.GetApp()}
+
+	// Joining a family would share the app's audience with apps the owner may not own.
+	_, err = st.AdminClient.UpdateApp(ownerCtx, &adminpb.UpdateAppRequest{
+		AppId:    appID,
+		Version:  respUpdate.GetApp().GetVersion(),
+		FamilyId: proto.Int32(0),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Apps owned by others stay out of reach.
+	_, err = st.AdminClient.GetApp(ownerCtx, &adminpb.GetAppRequest{AppId: st.AppID})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Changing owners is left to admins.
+	_, err = st.AdminClient.AddAppOwner(ownerCtx, &adminpb.AddAppOwnerRequest{AppId: appID, UserId: respReg.GetUserId()})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = st.AdminClient.RemoveAppOwner(adminCtx, &adminpb.RemoveAppOwnerRequest{AppId: appID, UserId: respReg.GetUserId()})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.GetApp(ownerCtx, &adminpb.GetAppRequest{AppId: appID})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAppOwners_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		req  *adminpb.AddAppOwnerRequest
+		code codes.Code
+	}{
+		{
+			name: "Missing app ID",
+			req:  &adminpb.AddAppOwnerRequest{UserId: respReg.GetUserId()},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Missing user",
+			req:  &adminpb.AddAppOwnerRequest{AppId: st.AppID},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "Unknown app",
+			req:  &adminpb.AddAppOwnerRequest{AppId: 1_000_000, UserId: respReg.GetUserId()},
+			code: codes.NotFound,
+		},
+		{
+			name: "Unknown user",
+			req:  &adminpb.AddAppOwnerRequest{AppId: st.AppID, UserId: respReg.GetUserId() + 1_000_000},
+			code: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.AddAppOwner(adminCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+
+	_, err = st.AdminClient.ListAppOwners(adminCtx, &adminpb.ListAppOwnersRequest{AppId: 1_000_000})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	// Whoever holds the admin app's secret can sign admin tokens, so the service does not hand it out.
+	for _, tt := range []struct {
+		name  string
+		appID int32
+		code  codes.Code
+	}{
+		{name: "Missing app ID", code: codes.InvalidArgument},
+		{name: "Unknown app", appID: 1_000_000, code: codes.NotFound},
+		{name: "Admin app", appID: st.Cfg.GRPC.AdminAppID, code: codes.FailedPrecondition},
+	} {
+		t.Run("Rotate secret: "+tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.RotateAppSecret(adminCtx, &adminpb.RotateAppSecretRequest{AppId: tt.appID})
+			require.Error(t, err)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestCreateDeleteApp_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	name := "test-" + gofakeit.UUID()
+
+	respCreate, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:   name,
+		Secret: gofakeit.UUID(),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, respCreate.GetAppId())
+
+	_, err = st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:   name,
+		Secret: gofakeit.UUID(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = st.AdminClient.DeleteApp(adminCtx, &adminpb.DeleteAppRequest{AppId: respCreate.GetAppId()})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.DeleteApp(adminCtx, &adminpb.DeleteAppRequest{AppId: respCreate.GetAppId()})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCreateApp_Unauthorized(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    email,
+		Password: password,
+	})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{
+		Email:    email,
+		Password: password,
+		AppId:    st.AppID,
+	})
+	require.NoError(t, err)
+
+	req := &adminpb.CreateAppRequest{
+		Name:   "test-" + gofakeit.UUID(),
+		Secret: gofakeit.UUID(),
+	}
+
+	_, err = st.AdminClient.CreateApp(ctx, req)
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	invalidCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer not-a-token")
+	_, err = st.AdminClient.CreateApp(invalidCtx, req)
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+	_, err = st.AdminClient.CreateApp(userCtx, req)
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestAdminMethods_RequireAdminAppOrServiceSignature(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	respWho, err := st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: st.AdminToken(ctx)})
+	require.NoError(t, err)
+
+	// Anyone holding the test app's secret can sign a token of an admin for it.
+	forged := signToken(t, st, respWho.GetUserId(), respWho.GetEmail(), time.Now().Add(time.Hour))
+
+	for name, token := range map[string]string{
+		"Forged with the app secret": forged,
+		"Issued for another app":     st.AdminTokenFor(ctx, st.AppID),
+	} {
+		t.Run(name, func(t *testing.T) {
+			tokenCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+
+			_, err := st.AdminClient.GetStats(tokenCtx, &adminpb.GetStatsRequest{})
+			require.Error(t, err)
+			assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+			// The token is still good for user methods.
+			_, err = st.AuthClient.GetUserInfo(tokenCtx, &pb.GetUserInfoRequest{})
+			require.NoError(t, err)
+		})
+	}
+
+	// Tokens signed by the service are accepted whatever app they were issued for.
+	respApp, err := st.AdminClient.CreateApp(st.AdminContext(ctx), &adminpb.CreateAppRequest{
+		Name:        "test-" + gofakeit.UUID(),
+		Secret:      gofakeit.UUID(),
+		TokenFormat: adminpb.TokenFormat_TOKEN_FORMAT_JWT_RS256,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, _ = st.AdminClient.DeleteApp(st.AdminContext(ctx), &adminpb.DeleteAppRequest{AppId: respApp.GetAppId()})
+	})
+
+	signedCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+st.AdminTokenFor(ctx, respApp.GetAppId()))
+
+	_, err = st.AdminClient.GetStats(signedCtx, &adminpb.GetStatsRequest{})
+	require.NoError(t, err)
+}
+
+func TestDeleteApp_RemovesDeviceAuthorizations(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respCreate, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:   "test-" + gofakeit.UUID(),
+		Secret: gofakeit.UUID(),
+	})
+	require.NoError(t, err)
+
+	respStart, err := st.AuthClient.StartDeviceAuthorization(ctx, &pb.StartDeviceAuthorizationRequest{AppId: respCreate.GetAppId()})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.DeleteApp(adminCtx, &adminpb.DeleteAppRequest{AppId: respCreate.GetAppId()})
+	require.NoError(t, err)
+
+	// The authorization went with its app, so it cannot be approved for an app reusing the ID.
+	_, err = st.AuthClient.PollDeviceToken(ctx, &pb.PollDeviceTokenRequest{DeviceCode: respStart.GetDeviceCode()})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestRevokeAppTokens(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respCreate, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:   "test-" + gofakeit.UUID(),
+		Secret: gofakeit.UUID(),
+	})
+	require.NoError(t, err)
+
+	appID := respCreate.GetAppId()
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err = st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respRevoked, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.NoError(t, err)
+
+	respOther, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	respStart, err := st.AuthClient.StartDeviceAuthorization(ctx, &pb.StartDeviceAuthorizationRequest{AppId: appID})
+	require.NoError(t, err)
+
+	// Validated once, so a micro-cached result would be served if revocations did not bypass it.
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respRevoked.GetToken())
+
+	_, err = st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+	require.NoError(t, err)
+
+	respRevoke, err := st.AdminClient.RevokeAppTokens(adminCtx, &adminpb.RevokeAppTokensRequest{AppId: appID})
+	require.NoError(t, err)
+	assert.NotNil(t, respRevoke.GetApp().GetTokensRevokedAt())
+	assert.Equal(t, int64(1), respRevoke.GetPurgedDeviceAuthorizations())
+
+	_, err = st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respRevoked.GetRefreshToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.AuthClient.PollDeviceToken(ctx, &pb.PollDeviceTokenRequest{DeviceCode: respStart.GetDeviceCode()})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Tokens of other apps are not affected.
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respOther.GetRefreshToken()})
+	require.NoError(t, err)
+
+	// Revocation has a resolution of one second, so tokens issued in the same second are revoked too.
+	time.Sleep(time.Second)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.RevokeAppTokens(adminCtx, &adminpb.RevokeAppTokensRequest{AppId: 1_000_000})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestReloadApps(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	_, err = st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.ReloadApps(adminCtx, &adminpb.ReloadAppsRequest{})
+	require.NoError(t, err)
+
+	// Valid tokens are validated against storage again and still accepted.
+	_, err = st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.ReloadApps(userCtx, &adminpb.ReloadAppsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestDeleteApp_RejectsValidatedTokens(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respCreate, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:   "test-" + gofakeit.UUID(),
+		Secret: gofakeit.UUID(),
+	})
+	require.NoError(t, err)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err = st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	// A new app is usable right away.
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: respCreate.GetAppId()})
+	require.NoError(t, err)
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	_, err = st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.DeleteApp(adminCtx, &adminpb.DeleteAppRequest{AppId: respCreate.GetAppId()})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestSetAppTokenTtl(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	lifetime := func() time.Duration {
+		respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+		require.NoError(t, err)
+
+		claims := parseClaims(t, st, respLog.GetToken())
+
+		iat, err := claims.GetIssuedAt()
+		require.NoError(t, err)
+
+		exp, err := claims.GetExpirationTime()
+		require.NoError(t, err)
+
+		return exp.Sub(iat.Time)
+	}
+
+	assert.Equal(t, st.Cfg.TokenTTL, lifetime())
+
+	_, err = st.AdminClient.SetAppTokenTtl(adminCtx, &adminpb.SetAppTokenTtlRequest{AppId: st.AppID, TtlSeconds: 300})
+	require.NoError(t, err)
+
+	respApp, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	assert.EqualValues(t, 300, respApp.GetApp().GetTokenTtlSeconds())
+
+	assert.Equal(t, 5*time.Minute, lifetime())
+
+	// Zero restores the lifetime of the config.
+	_, err = st.AdminClient.SetAppTokenTtl(adminCtx, &adminpb.SetAppTokenTtlRequest{AppId: st.AppID})
+	require.NoError(t, err)
+
+	assert.Equal(t, st.Cfg.TokenTTL, lifetime())
+
+	_, err = st.AdminClient.SetAppTokenTtl(adminCtx, &adminpb.SetAppTokenTtlRequest{AppId: st.AppID, TtlSeconds: -1})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.AdminClient.SetAppTokenTtl(adminCtx, &adminpb.SetAppTokenTtlRequest{TtlSeconds: 300})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.AdminClient.SetAppTokenTtl(adminCtx, &adminpb.SetAppTokenTtlRequest{AppId: 1 << 30, TtlSeconds: 300})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
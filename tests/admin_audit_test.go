@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+)
+
+func TestVerifyAuditLog(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	before, err := st.AdminClient.VerifyAuditLog(adminCtx, &adminpb.VerifyAuditLogRequest{})
+	require.NoError(t, err)
+	require.True(t, before.GetValid())
+	assert.Zero(t, before.GetFirstInvalidId())
+	// Creating the test app is an audited admin call.
+	assert.Positive(t, before.GetEvents())
+	assert.Len(t, before.GetHeadHash(), 64)
+
+	_, err = st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: st.AppID})
+	require.NoError(t, err)
+
+	after, err := st.AdminClient.VerifyAuditLog(adminCtx, &adminpb.VerifyAuditLogRequest{})
+	require.NoError(t, err)
+	require.True(t, after.GetValid())
+	assert.Greater(t, after.GetEvents(), before.GetEvents())
+	assert.NotEqual(t, before.GetHeadHash(), after.GetHeadHash())
+
+	_, err = st.AdminClient.VerifyAuditLog(ctx, &adminpb.VerifyAuditLogRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
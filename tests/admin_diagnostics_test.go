@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestGetDiagnostics(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	// The in-process server requires a scope, which only tokens of the admin app are trusted with.
+	if suite.DiagnosticsScope != "" {
+		respWho, err := st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: st.AdminToken(ctx)})
+		require.NoError(t, err)
+
+		token := signScopedToken(t, st.Cfg.GRPC.AdminAppID, bootstrapAppSecret, respWho.GetUserId(), respWho.GetEmail(), suite.DiagnosticsScope)
+		adminCtx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+
+	resp, err := st.AdminClient.GetDiagnostics(adminCtx, &adminpb.GetDiagnosticsRequest{})
+	require.NoError(t, err)
+
+	// The server runs in the test process.
+	assert.Equal(t, runtime.Version(), resp.GetGoVersion())
+	assert.Positive(t, resp.GetGomaxprocs())
+	assert.Positive(t, resp.GetGoroutines())
+	assert.Positive(t, resp.GetHeapAllocBytes())
+	assert.Positive(t, resp.GetHeapObjects())
+	assert.GreaterOrEqual(t, resp.GetSysBytes(), resp.GetHeapInuseBytes())
+	require.NotNil(t, resp.GetSampledAt())
+	require.NotNil(t, resp.GetStartedAt())
+	assert.False(t, resp.GetSampledAt().AsTime().Before(resp.GetStartedAt().AsTime()))
+
+	_, err = st.AdminClient.GetDiagnostics(ctx, &adminpb.GetDiagnosticsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
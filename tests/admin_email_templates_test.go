@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+)
+
+func TestRenderEmailTemplate_Locales(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	token := gofakeit.UUID()
+	data := map[string]string{
+		"Email":     gofakeit.Email(),
+		"Token":     token,
+		"ExpiresAt": "Mon, 02 Jan 2006 15:04:05 UTC",
+	}
+
+	tests := []struct {
+		name        string
+		locale      string
+		wantSubject string
+	}{
+		{name: "Default", locale: "", wantSubject: "Reset your password"},
+		{name: "Russian", locale: "ru", wantSubject: "Сброс пароля"},
+		{name: "Regional falls back to base", locale: "ru-RU", wantSubject: "Сброс пароля"},
+		{name: "Unknown falls back to default", locale: "xx", wantSubject: "Reset your password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := st.AdminClient.RenderEmailTemplate(adminCtx, &adminpb.RenderEmailTemplateRequest{
+				Name:   "password_reset",
+				AppId:  st.AppID,
+				Locale: tt.locale,
+				Data:   data,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSubject, resp.GetSubject())
+			assert.Contains(t, resp.GetBody(), token)
+		})
+	}
+}
+
+func TestRenderEmailTemplate_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	tests := []struct {
+		name     string
+		req      *adminpb.RenderEmailTemplateRequest
+		wantCode codes.Code
+	}{
+		{
+			name:     "Empty name",
+			req:      &adminpb.RenderEmailTemplateRequest{},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Unknown template",
+			req:      &adminpb.RenderEmailTemplateRequest{Name: "no_such_template"},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "Missing data",
+			req:      &adminpb.RenderEmailTemplateRequest{Name: "password_reset"},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.RenderEmailTemplate(adminCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestFreezeUser_BlocksLogin(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	respFreeze, err := st.AdminClient.FreezeUser(adminCtx, &adminpb.FreezeUserRequest{PublicId: respReg.GetPublicId()})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), respFreeze.GetUser().GetFrozenAt().AsTime(), time.Minute)
+
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = st.AuthClient.RequestUnfreeze(ctx, &pb.RequestUnfreezeRequest{Email: email, AppId: st.AppID})
+	require.NoError(t, err)
+
+	// Unknown emails get the same response, so accounts cannot be discovered.
+	_, err = st.AuthClient.RequestUnfreeze(ctx, &pb.RequestUnfreezeRequest{Email: gofakeit.Email()})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.FreezeUser(adminCtx, &adminpb.FreezeUserRequest{UserId: respReg.GetUserId() + 1_000_000})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestUnfreeze_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	tests := []struct {
+		name        string
+		token       string
+		newPassword string
+		expectedErr string
+	}{
+		{
+			name:        "unfreeze with empty token",
+			token:       "",
+			newPassword: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+			expectedErr: "token is required",
+		},
+		{
+			name:        "unfreeze with empty password",
+			token:       gofakeit.UUID(),
+			newPassword: "",
+			expectedErr: "new_password is required",
+		},
+		{
+			name:        "unfreeze with unknown token",
+			token:       gofakeit.UUID(),
+			newPassword: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+			expectedErr: "invalid or expired unfreeze token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AuthClient.Unfreeze(ctx, &pb.UnfreezeRequest{Token: tt.token, NewPassword: tt.newPassword})
+			require.Error(t, err)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+
+	_, err := st.AuthClient.RequestUnfreeze(ctx, &pb.RequestUnfreezeRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+)
+
+func TestJobs_ListAndRunNow(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respList, err := st.AdminClient.ListJobs(adminCtx, &adminpb.ListJobsRequest{})
+	require.NoError(t, err)
+
+	var names []string
+	for _, job := range respList.GetJobs() {
+		names = append(names, job.GetName())
+		assert.NotEmpty(t, job.GetDescription())
+		assert.Positive(t, job.GetIntervalMs())
+	}
+
+	require.Contains(t, names, "outbox")
+
+	respRun, err := st.AdminClient.RunJobNow(adminCtx, &adminpb.RunJobNowRequest{Name: "outbox"})
+	require.NoError(t, err)
+
+	job := respRun.GetJob()
+	assert.Equal(t, "outbox", job.GetName())
+	assert.Positive(t, job.GetRuns())
+	assert.Empty(t, job.GetLastError())
+	require.NotNil(t, job.GetLastStartedAt())
+	require.NotNil(t, job.GetLastSucceededAt())
+	assert.Equal(t, job.GetLastStartedAt().AsTime(), job.GetLastSucceededAt().AsTime())
+
+	// The run is recorded for later reads.
+	respStatus, err := st.AdminClient.GetJobStatus(adminCtx, &adminpb.GetJobStatusRequest{Name: "outbox"})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, respStatus.GetJob().GetRuns(), job.GetRuns())
+	assert.False(t, respStatus.GetJob().GetLastStartedAt().AsTime().Before(job.GetLastStartedAt().AsTime()))
+}
+
+func TestJobs_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	_, err := st.AdminClient.GetJobStatus(adminCtx, &adminpb.GetJobStatusRequest{Name: "unknown"})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = st.AdminClient.RunJobNow(adminCtx, &adminpb.RunJobNowRequest{Name: "unknown"})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = st.AdminClient.RunJobNow(adminCtx, &adminpb.RunJobNowRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.AdminClient.ListJobs(ctx, &adminpb.ListJobsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
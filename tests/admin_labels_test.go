@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestUserLabels_SetGetAndSearch(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	prefix := strings.ToLower(gofakeit.LetterN(12))
+	cohort := strings.ToLower(gofakeit.LetterN(8))
+
+	var ids []int64
+
+	for _, name := range []string{"alice", "bob"} {
+		password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+		respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: prefix + "." + name + "@example.com", Password: password})
+		require.NoError(t, err)
+
+		ids = append(ids, respReg.GetUserId())
+	}
+
+	respGet, err := st.AdminClient.GetUserLabels(adminCtx, &adminpb.GetUserLabelsRequest{UserId: ids[0]})
+	require.NoError(t, err)
+	assert.Empty(t, respGet.GetLabels())
+
+	labels := map[string]string{"env": "test", "cohort": cohort, "example.com/flagged": ""}
+
+	_, err = st.AdminClient.SetUserLabels(adminCtx, &adminpb.SetUserLabelsRequest{UserId: ids[0], Labels: labels})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.SetUserLabels(adminCtx, &adminpb.SetUserLabelsRequest{
+		UserId: ids[1],
+		Labels: map[string]string{"env": "test"},
+	})
+	require.NoError(t, err)
+
+	respGet, err = st.AdminClient.GetUserLabels(adminCtx, &adminpb.GetUserLabelsRequest{UserId: ids[0]})
+	require.NoError(t, err)
+	assert.Equal(t, labels, respGet.GetLabels())
+
+	idsOf := func(users []*adminpb.User) []int64 {
+		var found []int64
+
+		for _, user := range users {
+			found = append(found, user.GetId())
+		}
+
+		return found
+	}
+
+	// Only users having every label of the filter are returned.
+	respSearch, err := st.AdminClient.SearchUsers(adminCtx, &adminpb.SearchUsersRequest{
+		Query:  prefix,
+		Labels: map[string]string{"env": "test"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ids, idsOf(respSearch.GetUsers()))
+
+	respSearch, err = st.AdminClient.SearchUsers(adminCtx, &adminpb.SearchUsersRequest{
+		Query:  prefix,
+		Labels: map[string]string{"env": "test", "cohort": cohort},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ids[:1], idsOf(respSearch.GetUsers()))
+
+	respSearch, err = st.AdminClient.SearchUsers(adminCtx, &adminpb.SearchUsersRequest{
+		Query:  prefix + ".alcie@example.com",
+		Mode:   adminpb.SearchMode_SEARCH_MODE_FUZZY,
+		Labels: map[string]string{"cohort": cohort, "example.com/flagged": ""},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ids[:1], idsOf(respSearch.GetUsers()))
+
+	respSearch, err = st.AdminClient.SearchUsers(adminCtx, &adminpb.SearchUsersRequest{
+		Query:  prefix,
+		Labels: map[string]string{"env": "production"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, respSearch.GetUsers())
+
+	// An empty map removes every label.
+	_, err = st.AdminClient.SetUserLabels(adminCtx, &adminpb.SetUserLabelsRequest{UserId: ids[0]})
+	require.NoError(t, err)
+
+	respGet, err = st.AdminClient.GetUserLabels(adminCtx, &adminpb.GetUserLabelsRequest{UserId: ids[0]})
+	require.NoError(t, err)
+	assert.Empty(t, respGet.GetLabels())
+}
+
+func TestAppLabels_SetGetAndList(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	tier := strings.ToLower(gofakeit.LetterN(8))
+
+	var ids []int32
+
+	for range 3 {
+		respCreate, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+			Name:   "test-" + gofakeit.UUID(),
+			Secret: gofakeit.UUID(),
+		})
+		require.NoError(t, err)
+
+		_, err = st.AdminClient.SetAppLabels(adminCtx, &adminpb.SetAppLabelsRequest{
+			AppId:  respCreate.GetAppId(),
+			Labels: map[string]string{"risk-tier": tier},
+		})
+		require.NoError(t, err)
+
+		ids = append(ids, respCreate.GetAppId())
+	}
+
+	respGet, err := st.AdminClient.GetAppLabels(adminCtx, &adminpb.GetAppLabelsRequest{AppId: ids[0]})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"risk-tier": tier}, respGet.GetLabels())
+
+	// Apps are paged in order of ID.
+	var listed []int32
+
+	pageToken := ""
+
+	for {
+		respList, err := st.AdminClient.ListApps(adminCtx, &adminpb.ListAppsRequest{
+			Labels:    map[string]string{"risk-tier": tier},
+			PageSize:  2,
+			PageToken: pageToken,
+		})
+		require.NoError(t, err)
+
+		for _, app := range respList.GetApps() {
+			listed = append(listed, app.GetId())
+		}
+
+		pageToken = respList.GetNextPageToken()
+		if pageToken == "" {
+			break
+		}
+	}
+
+	assert.Equal(t, ids, listed)
+
+	respList, err := st.AdminClient.ListApps(adminCtx, &adminpb.ListAppsRequest{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, respList.GetApps())
+}
+
+func TestLabels_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	tooMany := make(map[string]string)
+	for i := range 33 {
+		tooMany["key-"+strconv.Itoa(i)] = "x"
+	}
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode codes.Code
+	}{
+		{
+			name: "user labels without user",
+			call: func() error {
+				_, err := st.AdminClient.GetUserLabels(adminCtx, &adminpb.GetUserLabelsRequest{})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "unknown user",
+			call: func() error {
+				_, err := st.AdminClient.SetUserLabels(adminCtx, &adminpb.SetUserLabelsRequest{UserId: 1 << 40})
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "unknown app",
+			call: func() error {
+				_, err := st.AdminClient.GetAppLabels(adminCtx, &adminpb.GetAppLabelsRequest{AppId: 1 << 30})
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "uppercase key",
+			call: func() error {
+				_, err := st.AdminClient.SetAppLabels(adminCtx, &adminpb.SetAppLabelsRequest{
+					AppId:  st.AppID,
+					Labels: map[string]string{"Env": "test"},
+				})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "too many labels",
+			call: func() error {
+				_, err := st.AdminClient.SetAppLabels(adminCtx, &adminpb.SetAppLabelsRequest{AppId: st.AppID, Labels: tooMany})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "invalid filter",
+			call: func() error {
+				_, err := st.AdminClient.ListApps(adminCtx, &adminpb.ListAppsRequest{Labels: map[string]string{"-env": "test"}})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "invalid page token",
+			call: func() error {
+				_, err := st.AdminClient.ListApps(adminCtx, &adminpb.ListAppsRequest{PageToken: "abc"})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "without admin token",
+			call: func() error {
+				_, err := st.AdminClient.ListApps(ctx, &adminpb.ListAppsRequest{})
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestMergeUsers_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	primaryEmail := gofakeit.Email()
+	duplicateEmail := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respPrimary, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: primaryEmail, Password: password})
+	require.NoError(t, err)
+
+	respDuplicate, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: duplicateEmail, Password: password})
+	require.NoError(t, err)
+
+	respCreate, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:              "test-" + gofakeit.UUID(),
+		Secret:            gofakeit.UUID(),
+		RequireMembership: true,
+	})
+	require.NoError(t, err)
+
+	appID := respCreate.GetAppId()
+
+	_, err = st.AdminClient.GrantAppAccess(adminCtx, &adminpb.GrantAppAccessRequest{AppId: appID, PublicId: respDuplicate.GetPublicId()})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.SetUserAttributes(adminCtx, &adminpb.SetUserAttributesRequest{
+		PublicId:   respPrimary.GetPublicId(),
+		Attributes: map[string]string{"department": "engineering"},
+	})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.SetUserAttributes(adminCtx, &adminpb.SetUserAttributesRequest{
+		PublicId:   respDuplicate.GetPublicId(),
+		Attributes: map[string]string{"department": "sales", "location": "berlin"},
+	})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: duplicateEmail, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	respMerge, err := st.AdminClient.MergeUsers(adminCtx, &adminpb.MergeUsersRequest{
+		PrimaryPublicId:   respPrimary.GetPublicId(),
+		DuplicatePublicId: respDuplicate.GetPublicId(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, respPrimary.GetUserId(), respMerge.GetUser().GetId())
+	assert.Zero(t, respMerge.GetUser().GetMergedInto())
+
+	respGet, err := st.AdminClient.GetUser(adminCtx, &adminpb.GetUserRequest{PublicId: respDuplicate.GetPublicId()})
+	require.NoError(t, err)
+	assert.Equal(t, respPrimary.GetUserId(), respGet.GetUser().GetMergedInto())
+
+	// The primary user inherits the membership, and keeps its own attribute values.
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: primaryEmail, Password: password, AppId: appID})
+	require.NoError(t, err)
+
+	respAttrs, err := st.AdminClient.GetUserAttributes(adminCtx, &adminpb.GetUserAttributesRequest{PublicId: respPrimary.GetPublicId()})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"department": "engineering", "location": "berlin"}, respAttrs.GetAttributes())
+
+	// The duplicate can neither log in nor use its tokens.
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: duplicateEmail, Password: password, AppId: st.AppID})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	_, err = st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// A merged user cannot be merged again, in either role.
+	_, err = st.AdminClient.MergeUsers(adminCtx, &adminpb.MergeUsersRequest{
+		PrimaryPublicId:   respDuplicate.GetPublicId(),
+		DuplicatePublicId: respPrimary.GetPublicId(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestMergeUsers_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		req         *adminpb.MergeUsersRequest
+		expectedErr codes.Code
+	}{
+		{
+			name:        "Missing primary user",
+			req:         &adminpb.MergeUsersRequest{DuplicateUserId: respReg.GetUserId()},
+			expectedErr: codes.InvalidArgument,
+		},
+		{
+			name:        "Missing duplicate user",
+			req:         &adminpb.MergeUsersRequest{PrimaryUserId: respReg.GetUserId()},
+			expectedErr: codes.InvalidArgument,
+		},
+		{
+			name:        "Same user",
+			req:         &adminpb.MergeUsersRequest{PrimaryUserId: respReg.GetUserId(), DuplicatePublicId: respReg.GetPublicId()},
+			expectedErr: codes.InvalidArgument,
+		},
+		{
+			name:        "Unknown duplicate user",
+			req:         &adminpb.MergeUsersRequest{PrimaryUserId: respReg.GetUserId(), DuplicateUserId: 1_000_000_000},
+			expectedErr: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.MergeUsers(adminCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.expectedErr, status.Code(err))
+		})
+	}
+}
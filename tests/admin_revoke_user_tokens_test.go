@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestRevokeAllUserTokens(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respUser, err := st.AdminClient.GetUser(adminCtx, &adminpb.GetUserRequest{UserId: respReg.GetUserId()})
+	require.NoError(t, err)
+	assert.Nil(t, respUser.GetUser().GetTokensRevokedAt())
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+	require.NotEmpty(t, respLog.GetRefreshToken())
+
+	respRevoke, err := st.AdminClient.RevokeAllUserTokens(adminCtx, &adminpb.RevokeAllUserTokensRequest{PublicId: respReg.GetPublicId()})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), respRevoke.GetUser().GetTokensRevokedAt().AsTime(), time.Minute)
+	assert.Nil(t, respRevoke.GetUser().GetFrozenAt())
+
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// The account is not frozen, so the user may log in again.
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+}
+
+func TestRevokeAllUserTokens_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.RevokeAllUserTokens(adminCtx, &adminpb.RevokeAllUserTokensRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.AdminClient.RevokeAllUserTokens(adminCtx, &adminpb.RevokeAllUserTokensRequest{UserId: 1 << 60})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Only admins may revoke a user's tokens.
+	_, err = st.AdminClient.RevokeAllUserTokens(ctx, &adminpb.RevokeAllUserTokensRequest{UserId: respReg.GetUserId()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestSearchUsers(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	prefix := strings.ToLower(gofakeit.LetterN(12))
+	emails := []string{
+		prefix + ".jonathan@example.com",
+		strings.ToUpper(prefix) + ".margaret@example.com",
+		prefix + "x.other@example.com",
+	}
+
+	ids := make(map[string]int64, len(emails))
+
+	for _, email := range emails {
+		password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+		respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+		require.NoError(t, err)
+
+		ids[email] = respReg.GetUserId()
+	}
+
+	emailsOf := func(users []*adminpb.User) []string {
+		var found []string
+
+		for _, user := range users {
+			found = append(found, user.GetEmail())
+		}
+
+		return found
+	}
+
+	// Prefix searches ignore case and return users in order of email.
+	respPrefix, err := st.AdminClient.SearchUsers(adminCtx, &adminpb.SearchUsersRequest{Query: strings.ToUpper(prefix) + "."})
+	require.NoError(t, err)
+	assert.Equal(t, emails[:2], emailsOf(respPrefix.GetUsers()))
+
+	respPrefix, err = st.AdminClient.SearchUsers(adminCtx, &adminpb.SearchUsersRequest{
+		Query:    prefix,
+		Mode:     adminpb.SearchMode_SEARCH_MODE_PREFIX,
+		PageSize: 2,
+	})
+	require.NoError(t, err)
+	assert.Len(t, respPrefix.GetUsers(), 2)
+
+	// Fuzzy searches tolerate typos and rank the closest email first.
+	respFuzzy, err := st.AdminClient.SearchUsers(adminCtx, &adminpb.SearchUsersRequest{
+		Query: prefix + ".jonahtan@exmaple.com",
+		Mode:  adminpb.SearchMode_SEARCH_MODE_FUZZY,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, respFuzzy.GetUsers())
+	assert.Equal(t, ids[emails[0]], respFuzzy.GetUsers()[0].GetId())
+
+	respFuzzy, err = st.AdminClient.SearchUsers(adminCtx, &adminpb.SearchUsersRequest{
+		Query: strings.ToLower(gofakeit.LetterN(16)),
+		Mode:  adminpb.SearchMode_SEARCH_MODE_FUZZY,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, respFuzzy.GetUsers())
+}
+
+func TestSearchUsers_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	tests := []struct {
+		name     string
+		req      *adminpb.SearchUsersRequest
+		wantCode codes.Code
+	}{
+		{
+			name:     "Empty query",
+			req:      &adminpb.SearchUsersRequest{},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Query too long",
+			req:      &adminpb.SearchUsersRequest{Query: strings.Repeat("a", 255)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Fuzzy query too short",
+			req:      &adminpb.SearchUsersRequest{Query: "ab", Mode: adminpb.SearchMode_SEARCH_MODE_FUZZY},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Unknown mode",
+			req:      &adminpb.SearchUsersRequest{Query: "abc", Mode: 42},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Page size too large",
+			req:      &adminpb.SearchUsersRequest{Query: "abc", PageSize: 101},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.SearchUsers(adminCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	_, err := st.AdminClient.SearchUsers(ctx, &adminpb.SearchUsersRequest{Query: "abc"})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
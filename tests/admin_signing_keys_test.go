@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestRotateSigningKey(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respApp, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:        "test-" + gofakeit.UUID(),
+		Secret:      gofakeit.UUID(),
+		TokenFormat: adminpb.TokenFormat_TOKEN_FORMAT_JWT_RS256,
+	})
+	require.NoError(t, err)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err = st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	login := func() (string, string) {
+		resp, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: respApp.GetAppId()})
+		require.NoError(t, err)
+
+		token, _, err := jwt.NewParser().ParseUnverified(resp.GetToken(), jwt.MapClaims{})
+		require.NoError(t, err)
+
+		kid, _ := token.Header["kid"].(string)
+
+		return resp.GetToken(), kid
+	}
+
+	oldToken, oldKID := login()
+
+	respRotate, err := st.AdminClient.RotateSigningKey(adminCtx, &adminpb.RotateSigningKeyRequest{})
+	require.NoError(t, err)
+
+	newKey := respRotate.GetKey()
+	assert.True(t, newKey.GetActive())
+	assert.NotEqual(t, oldKID, newKey.GetKid())
+	assert.NotNil(t, newKey.GetCreatedAt())
+
+	respList, err := st.AdminClient.ListSigningKeys(adminCtx, &adminpb.ListSigningKeysRequest{})
+	require.NoError(t, err)
+	require.NotEmpty(t, respList.GetKeys())
+	assert.Equal(t, newKey.GetKid(), respList.GetKeys()[0].GetKid())
+
+	var retired *adminpb.SigningKey
+
+	for _, key := range respList.GetKeys()[1:] {
+		assert.False(t, key.GetActive())
+
+		if key.GetKid() == oldKID {
+			retired = key
+		}
+	}
+
+	require.NotNil(t, retired, "the previous key is listed until its grace period ends")
+	assert.True(t, retired.GetExpiresAt().AsTime().After(retired.GetRetiredAt().AsTime()))
+
+	// Tokens signed with the retired key stay valid during the grace period.
+	respWho, err := st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: oldToken})
+	require.NoError(t, err)
+	assert.NotEmpty(t, respWho.GetSub())
+
+	newToken, newKID := login()
+	assert.Equal(t, newKey.GetKid(), newKID)
+
+	_, err = st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: newToken})
+	require.NoError(t, err)
+
+	respKeys, err := st.AuthClient.GetJwks(ctx, &pb.GetJwksRequest{})
+	require.NoError(t, err)
+
+	var published []string
+	for _, key := range respKeys.GetKeys() {
+		published = append(published, key.GetKid())
+	}
+
+	assert.Contains(t, published, oldKID)
+	assert.Contains(t, published, newKID)
+}
@@ -0,0 +1,273 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestSimulateLogin(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	email := gofakeit.Email()
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    email,
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	simulate := func(settings *adminpb.AppLoginSettings) (*adminpb.SimulateLoginResponse, map[string]any) {
+		resp, err := st.AdminClient.SimulateLogin(adminCtx, &adminpb.SimulateLoginRequest{
+			PublicId: respReg.GetPublicId(),
+			AppId:    st.AppID,
+			Settings: settings,
+		})
+		require.NoError(t, err)
+
+		if !resp.GetAllowed() {
+			return resp, nil
+		}
+
+		var claims map[string]any
+		require.NoError(t, json.Unmarshal([]byte(resp.GetClaims()), &claims))
+
+		return resp, claims
+	}
+
+	resp, claims := simulate(nil)
+	assert.True(t, resp.GetAllowed())
+	assert.Empty(t, resp.GetDenialReason())
+	assert.False(t, resp.GetPushApprovalRequired())
+	assert.Equal(t, email, claims["email"])
+	assert.Equal(t, respReg.GetPublicId(), claims["sub"])
+	assert.Equal(t, []any{"pwd"}, claims["amr"])
+
+	resp, claims = simulate(&adminpb.AppLoginSettings{MinimalClaims: proto.Bool(true)})
+	assert.True(t, resp.GetAllowed())
+	assert.Equal(t, respReg.GetPublicId(), claims["sub"])
+	assert.NotContains(t, claims, "email")
+
+	resp, _ = simulate(&adminpb.AppLoginSettings{TokenFormat: adminpb.TokenFormat_TOKEN_FORMAT_PASETO_V4_LOCAL})
+	assert.True(t, resp.GetAllowed())
+	assert.Contains(t, resp.GetClaims(), email)
+
+	resp, _ = simulate(&adminpb.AppLoginSettings{RequireMembership: proto.Bool(true)})
+	assert.False(t, resp.GetAllowed())
+	assert.Equal(t, "not_app_member", resp.GetDenialReason())
+	assert.Empty(t, resp.GetClaims())
+
+	// Simulations change nothing.
+	respApp, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	assert.False(t, respApp.GetApp().GetRequireMembership())
+	assert.False(t, respApp.GetApp().GetMinimalClaims())
+	assert.Equal(t, adminpb.TokenFormat_TOKEN_FORMAT_JWT, respApp.GetApp().GetTokenFormat())
+
+	_, err = st.AdminClient.FreezeUser(adminCtx, &adminpb.FreezeUserRequest{UserId: respReg.GetUserId()})
+	require.NoError(t, err)
+
+	resp, _ = simulate(nil)
+	assert.False(t, resp.GetAllowed())
+	assert.Equal(t, "frozen_account", resp.GetDenialReason())
+}
+
+func TestSimulateLogin_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		req      *adminpb.SimulateLoginRequest
+		wantCode codes.Code
+	}{
+		{
+			name:     "Missing user",
+			req:      &adminpb.SimulateLoginRequest{AppId: st.AppID},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Missing app",
+			req:      &adminpb.SimulateLoginRequest{UserId: respReg.GetUserId()},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Unknown user",
+			req:      &adminpb.SimulateLoginRequest{UserId: 1 << 60, AppId: st.AppID},
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "Unknown app",
+			req:      &adminpb.SimulateLoginRequest{UserId: respReg.GetUserId(), AppId: 1 << 30},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "Unknown token format",
+			req: &adminpb.SimulateLoginRequest{
+				UserId:   respReg.GetUserId(),
+				AppId:    st.AppID,
+				Settings: &adminpb.AppLoginSettings{TokenFormat: 42},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.SimulateLogin(adminCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	// Only admins may simulate logins.
+	_, err = st.AdminClient.SimulateLogin(ctx, &adminpb.SimulateLoginRequest{UserId: respReg.GetUserId(), AppId: st.AppID})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestSimulatePolicy(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	_, err := st.AdminClient.SetAppEmailDomains(adminCtx, &adminpb.SetAppEmailDomainsRequest{
+		AppId:          st.AppID,
+		AllowedDomains: []string{"example.com", "mailinator.com"},
+	})
+	require.NoError(t, err)
+
+	simulate := func(req *adminpb.SimulatePolicyRequest) *adminpb.SimulatePolicyResponse {
+		req.AppId = st.AppID
+
+		resp, err := st.AdminClient.SimulatePolicy(adminCtx, req)
+		require.NoError(t, err)
+
+		return resp
+	}
+
+	resp := simulate(&adminpb.SimulatePolicyRequest{Email: gofakeit.Username() + "@example.com"})
+	assert.Equal(t, adminpb.RegistrationOutcome_REGISTRATION_OUTCOME_ACCEPTED, resp.GetOutcome())
+	assert.Empty(t, resp.GetReason())
+	assert.Empty(t, resp.GetPolicy())
+
+	resp = simulate(&adminpb.SimulatePolicyRequest{Email: gofakeit.Username() + "@example.org"})
+	assert.Equal(t, adminpb.RegistrationOutcome_REGISTRATION_OUTCOME_REJECTED, resp.GetOutcome())
+	assert.Contains(t, resp.GetReason(), "example.com")
+	assert.Equal(t, "registration_domains", resp.GetPolicy())
+
+	resp = simulate(&adminpb.SimulatePolicyRequest{
+		Email:        gofakeit.Username() + "@example.com",
+		EmailDomains: &adminpb.EmailDomains{DeniedDomains: []string{"Example.com"}},
+	})
+	assert.Equal(t, adminpb.RegistrationOutcome_REGISTRATION_OUTCOME_REJECTED, resp.GetOutcome())
+	assert.Equal(t, "registration_domains", resp.GetPolicy())
+
+	// An empty proposal lifts the app's restrictions.
+	resp = simulate(&adminpb.SimulatePolicyRequest{
+		Email:        gofakeit.Username() + "@example.org",
+		EmailDomains: &adminpb.EmailDomains{},
+	})
+	assert.Equal(t, adminpb.RegistrationOutcome_REGISTRATION_OUTCOME_ACCEPTED, resp.GetOutcome())
+
+	disposable := gofakeit.Username() + "@mailinator.com"
+
+	resp = simulate(&adminpb.SimulatePolicyRequest{
+		Email:                 disposable,
+		DisposableEmailPolicy: adminpb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_REJECT.Enum(),
+	})
+	assert.Equal(t, adminpb.RegistrationOutcome_REGISTRATION_OUTCOME_REJECTED, resp.GetOutcome())
+	assert.Equal(t, "disposable_emails", resp.GetPolicy())
+
+	resp = simulate(&adminpb.SimulatePolicyRequest{
+		Email:                 disposable,
+		DisposableEmailPolicy: adminpb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_FLAG.Enum(),
+	})
+	assert.Equal(t, adminpb.RegistrationOutcome_REGISTRATION_OUTCOME_FLAGGED, resp.GetOutcome())
+
+	// Simulations change nothing.
+	respDomains, err := st.AdminClient.GetAppEmailDomains(adminCtx, &adminpb.GetAppEmailDomainsRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com", "mailinator.com"}, respDomains.GetAllowedDomains())
+	assert.Empty(t, respDomains.GetDeniedDomains())
+
+	respPolicy, err := st.AdminClient.GetAppDisposableEmailPolicy(adminCtx, &adminpb.GetAppDisposableEmailPolicyRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_UNSPECIFIED, respPolicy.GetPolicy())
+}
+
+func TestSimulatePolicy_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	tests := []struct {
+		name     string
+		req      *adminpb.SimulatePolicyRequest
+		wantCode codes.Code
+	}{
+		{
+			name:     "Missing email",
+			req:      &adminpb.SimulatePolicyRequest{AppId: st.AppID},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Missing app",
+			req:      &adminpb.SimulatePolicyRequest{Email: gofakeit.Email()},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Unknown app",
+			req:      &adminpb.SimulatePolicyRequest{Email: gofakeit.Email(), AppId: 1 << 30},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "Conflicting domains",
+			req: &adminpb.SimulatePolicyRequest{
+				Email: gofakeit.Email(),
+				AppId: st.AppID,
+				EmailDomains: &adminpb.EmailDomains{
+					AllowedDomains: []string{"example.com"},
+					DeniedDomains:  []string{"example.com"},
+				},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Unknown disposable email policy",
+			req: &adminpb.SimulatePolicyRequest{
+				Email:                 gofakeit.Email(),
+				AppId:                 st.AppID,
+				DisposableEmailPolicy: adminpb.DisposableEmailPolicy(42).Enum(),
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.SimulatePolicy(adminCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestGetStats_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    email,
+		Password: password,
+	})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{
+		Email:    email,
+		Password: password,
+		AppId:    st.AppID,
+	})
+	require.NoError(t, err)
+
+	const days = 7
+
+	resp, err := st.AdminClient.GetStats(adminCtx, &adminpb.GetStatsRequest{Days: days})
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, resp.GetTotalUsers(), int64(2))
+	require.Len(t, resp.GetDays(), days)
+
+	today := resp.GetDays()[days-1]
+	assert.Equal(t, time.Now().UTC().Format(time.DateOnly), today.GetDate())
+	assert.GreaterOrEqual(t, today.GetRegistrations(), int64(1))
+	assert.GreaterOrEqual(t, today.GetActiveUsers(), int64(1))
+	assert.WithinDuration(t, time.Now(), resp.GetGeneratedAt().AsTime(), time.Minute)
+}
+
+func TestGetStats_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	for _, days := range []int32{-1, 91} {
+		_, err := st.AdminClient.GetStats(adminCtx, &adminpb.GetStatsRequest{Days: days})
+		require.Error(t, err)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
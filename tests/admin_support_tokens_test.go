@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestSupportToken_UserScope(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	respOther, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	respIssue, err := st.AdminClient.IssueSupportToken(adminCtx, &adminpb.IssueSupportTokenRequest{
+		PublicId:   respReg.GetPublicId(),
+		TtlSeconds: 600,
+		Reason:     "ticket-" + gofakeit.UUID(),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, respIssue.GetSupportTokenId())
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), respIssue.GetExpiresAt().AsTime(), time.Minute)
+
+	supportCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respIssue.GetToken())
+
+	respGet, err := st.AdminClient.GetUser(supportCtx, &adminpb.GetUserRequest{PublicId: respReg.GetPublicId()})
+	require.NoError(t, err)
+	assert.Equal(t, respReg.GetUserId(), respGet.GetUser().GetId())
+
+	_, err = st.AdminClient.GetUser(supportCtx, &adminpb.GetUserRequest{UserId: respReg.GetUserId()})
+	require.NoError(t, err)
+
+	// Other users, other resources, and methods that change data are out of scope.
+	_, err = st.AdminClient.GetUser(supportCtx, &adminpb.GetUserRequest{UserId: respOther.GetUserId()})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = st.AdminClient.GetApp(supportCtx, &adminpb.GetAppRequest{AppId: st.AppID})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = st.AdminClient.UpdateUser(supportCtx, &adminpb.UpdateUserRequest{
+		UserId:  respReg.GetUserId(),
+		IsAdmin: proto.Bool(true),
+		Version: respGet.GetUser().GetVersion(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = st.AdminClient.IssueSupportToken(supportCtx, &adminpb.IssueSupportTokenRequest{
+		UserId: respReg.GetUserId(),
+		Reason: "escalation",
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = st.AdminClient.RevokeSupportToken(adminCtx, &adminpb.RevokeSupportTokenRequest{
+		SupportTokenId: respIssue.GetSupportTokenId(),
+	})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.GetUser(supportCtx, &adminpb.GetUserRequest{UserId: respReg.GetUserId()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestSupportToken_AppScope(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respIssue, err := st.AdminClient.IssueSupportToken(adminCtx, &adminpb.IssueSupportTokenRequest{
+		AppId:  st.AppID,
+		Reason: "ticket-" + gofakeit.UUID(),
+	})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(st.Cfg.SupportTokens.DefaultTTL), respIssue.GetExpiresAt().AsTime(), time.Minute)
+
+	supportCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respIssue.GetToken())
+
+	respGet, err := st.AdminClient.GetApp(supportCtx, &adminpb.GetAppRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	assert.Equal(t, st.AppID, respGet.GetApp().GetId())
+
+	_, err = st.AdminClient.GetAppEmailDomains(supportCtx, &adminpb.GetAppEmailDomainsRequest{AppId: st.AppID})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.GetApp(supportCtx, &adminpb.GetAppRequest{AppId: st.AppID + 1})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = st.AdminClient.GetStats(supportCtx, &adminpb.GetStatsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	invalidCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer sst_"+gofakeit.UUID())
+
+	_, err = st.AdminClient.GetApp(invalidCtx, &adminpb.GetAppRequest{AppId: st.AppID})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestIssueSupportToken_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		req          *adminpb.IssueSupportTokenRequest
+		expectedCode codes.Code
+		expectedErr  string
+	}{
+		{
+			name:         "issue without resource",
+			req:          &adminpb.IssueSupportTokenRequest{Reason: "ticket"},
+			expectedCode: codes.InvalidArgument,
+			expectedErr:  "exactly one of user_id, public_id, or app_id is required",
+		},
+		{
+			name:         "issue for user and app",
+			req:          &adminpb.IssueSupportTokenRequest{UserId: respReg.GetUserId(), AppId: st.AppID, Reason: "ticket"},
+			expectedCode: codes.InvalidArgument,
+			expectedErr:  "exactly one of user_id, public_id, or app_id is required",
+		},
+		{
+			name:         "issue without reason",
+			req:          &adminpb.IssueSupportTokenRequest{UserId: respReg.GetUserId()},
+			expectedCode: codes.InvalidArgument,
+			expectedErr:  "reason is required",
+		},
+		{
+			name: "issue with ttl above maximum",
+			req: &adminpb.IssueSupportTokenRequest{
+				UserId:     respReg.GetUserId(),
+				TtlSeconds: int32((st.Cfg.SupportTokens.MaxTTL + time.Second) / time.Second),
+				Reason:     "ticket",
+			},
+			expectedCode: codes.InvalidArgument,
+			expectedErr:  "ttl_seconds exceeds the maximum support token lifetime",
+		},
+		{
+			name:         "issue for unknown user",
+			req:          &adminpb.IssueSupportTokenRequest{UserId: respReg.GetUserId() + 1_000_000, Reason: "ticket"},
+			expectedCode: codes.NotFound,
+			expectedErr:  "user not found",
+		},
+		{
+			name:         "issue for unknown app",
+			req:          &adminpb.IssueSupportTokenRequest{AppId: -1, Reason: "ticket"},
+			expectedCode: codes.NotFound,
+			expectedErr:  "app not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.IssueSupportToken(adminCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.expectedCode, status.Code(err))
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+
+	_, err = st.AdminClient.RevokeSupportToken(adminCtx, &adminpb.RevokeSupportTokenRequest{SupportTokenId: -1})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
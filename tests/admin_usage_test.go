@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestGetUsage_CountsAuthenticatedRequests(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	for range 2 {
+		_, err = st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+		require.NoError(t, err)
+	}
+
+	_, err = st.AuthClient.ApproveDeviceAuthorization(userCtx, &pb.ApproveDeviceAuthorizationRequest{UserCode: "UNKNOWN"})
+	require.Error(t, err)
+
+	adminCtx := st.AdminContext(ctx)
+
+	// Counts reach storage at the next flush.
+	var usage []*adminpb.Usage
+
+	require.Eventually(t, func() bool {
+		resp, err := st.AdminClient.GetUsage(adminCtx, &adminpb.GetUsageRequest{AppId: st.AppID, PublicId: respReg.GetPublicId()})
+		if err != nil {
+			return false
+		}
+
+		usage = resp.GetUsage()
+
+		return len(usage) == 1 && usage[0].GetRequests() == 3
+	}, 10*time.Second, 100*time.Millisecond)
+
+	assert.Equal(t, time.Now().UTC().Format(time.DateOnly), usage[0].GetDate())
+	assert.Equal(t, st.AppID, usage[0].GetAppId())
+	assert.Equal(t, respReg.GetUserId(), usage[0].GetUserId())
+	assert.Equal(t, int64(1), usage[0].GetErrors())
+
+	// Without by_user, the users of the app are summed.
+	resp, err := st.AdminClient.GetUsage(adminCtx, &adminpb.GetUsageRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	require.Len(t, resp.GetUsage(), 1)
+	assert.Zero(t, resp.GetUsage()[0].GetUserId())
+	assert.GreaterOrEqual(t, resp.GetUsage()[0].GetRequests(), int64(3))
+
+	_, err = st.AdminClient.GetUsage(adminCtx, &adminpb.GetUsageRequest{Days: 1000})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
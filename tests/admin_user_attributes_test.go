@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestUserAttributes_SetGetAndClaims(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respGet, err := st.AdminClient.GetUserAttributes(adminCtx, &adminpb.GetUserAttributesRequest{PublicId: respReg.GetPublicId()})
+	require.NoError(t, err)
+	assert.Empty(t, respGet.GetAttributes())
+
+	respSet, err := st.AdminClient.SetUserAttributes(adminCtx, &adminpb.SetUserAttributesRequest{
+		PublicId:   respReg.GetPublicId(),
+		Attributes: map[string]string{"department": "engineering", "clearance": "007", "contractor": "TRUE"},
+	})
+	require.NoError(t, err)
+
+	want := map[string]string{"department": "engineering", "clearance": "7", "contractor": "true"}
+	assert.Equal(t, want, respSet.GetAttributes())
+
+	respGet, err = st.AdminClient.GetUserAttributes(adminCtx, &adminpb.GetUserAttributesRequest{UserId: respReg.GetUserId()})
+	require.NoError(t, err)
+	assert.Equal(t, want, respGet.GetAttributes())
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"department": "engineering", "clearance": "7", "contractor": "true"},
+		parseClaims(t, st, respLog.GetToken())["attrs"])
+
+	// An empty map removes every attribute.
+	_, err = st.AdminClient.SetUserAttributes(adminCtx, &adminpb.SetUserAttributesRequest{UserId: respReg.GetUserId()})
+	require.NoError(t, err)
+
+	respRefresh, err := st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.NoError(t, err)
+	assert.NotContains(t, parseClaims(t, st, respRefresh.GetToken()), "attrs")
+}
+
+func TestUserAttributes_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	tooMany := make(map[string]string)
+	for i := 0; i <= 32; i++ {
+		tooMany[gofakeit.LetterN(10)] = "x"
+	}
+
+	tests := []struct {
+		name       string
+		userID     int64
+		attributes map[string]string
+		code       codes.Code
+	}{
+		{name: "Uppercase key", userID: respReg.GetUserId(), attributes: map[string]string{"Department": "x"}, code: codes.InvalidArgument},
+		{name: "Key starting with a digit", userID: respReg.GetUserId(), attributes: map[string]string{"1st": "x"}, code: codes.InvalidArgument},
+		{name: "Non-integer int", userID: respReg.GetUserId(), attributes: map[string]string{"clearance": "top"}, code: codes.InvalidArgument},
+		{name: "Non-boolean bool", userID: respReg.GetUserId(), attributes: map[string]string{"contractor": "maybe"}, code: codes.InvalidArgument},
+		{name: "Too long value", userID: respReg.GetUserId(), attributes: map[string]string{"note": gofakeit.LetterN(257)}, code: codes.InvalidArgument},
+		{name: "Too many attributes", userID: respReg.GetUserId(), attributes: tooMany, code: codes.InvalidArgument},
+		{name: "Missing user", attributes: map[string]string{"department": "x"}, code: codes.InvalidArgument},
+		{name: "Unknown user", userID: respReg.GetUserId() + 1_000_000, attributes: map[string]string{"department": "x"}, code: codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.SetUserAttributes(adminCtx, &adminpb.SetUserAttributesRequest{
+				UserId:     tt.userID,
+				Attributes: tt.attributes,
+			})
+			require.Error(t, err)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestFindUsersByAttribute(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	// A department no other test uses, so only the users registered here match.
+	department := "dept_" + gofakeit.UUID()
+
+	var userIDs []int64
+
+	for range 3 {
+		respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+			Email:    gofakeit.Email(),
+			Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+		})
+		require.NoError(t, err)
+
+		_, err = st.AdminClient.SetUserAttributes(adminCtx, &adminpb.SetUserAttributesRequest{
+			UserId:     respReg.GetUserId(),
+			Attributes: map[string]string{"department": department, "clearance": "3"},
+		})
+		require.NoError(t, err)
+
+		userIDs = append(userIDs, respReg.GetUserId())
+	}
+
+	var (
+		found     []int64
+		pageToken string
+	)
+
+	for {
+		resp, err := st.AdminClient.FindUsersByAttribute(adminCtx, &adminpb.FindUsersByAttributeRequest{
+			Key:       "department",
+			Value:     department,
+			PageSize:  2,
+			PageToken: pageToken,
+		})
+		require.NoError(t, err)
+
+		for _, user := range resp.GetUsers() {
+			found = append(found, user.GetId())
+		}
+
+		pageToken = resp.GetNextPageToken()
+		if pageToken == "" {
+			break
+		}
+	}
+
+	assert.Equal(t, userIDs, found)
+
+	// Values are normalized before matching, like stored values.
+	resp, err := st.AdminClient.FindUsersByAttribute(adminCtx, &adminpb.FindUsersByAttributeRequest{
+		Key:      "clearance",
+		Value:    "+03",
+		PageSize: 1000,
+	})
+	require.NoError(t, err)
+
+	var cleared []int64
+	for _, user := range resp.GetUsers() {
+		cleared = append(cleared, user.GetId())
+	}
+
+	assert.Subset(t, cleared, userIDs)
+
+	_, err = st.AdminClient.FindUsersByAttribute(adminCtx, &adminpb.FindUsersByAttributeRequest{Key: "clearance", Value: "high"})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.AdminClient.FindUsersByAttribute(adminCtx, &adminpb.FindUsersByAttributeRequest{Key: "department", PageToken: "abc"})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
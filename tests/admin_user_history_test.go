@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestGetUserAtTime(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	before := time.Now()
+
+	// History has a resolution of one second, so the changes are made in a later second.
+	time.Sleep(time.Second)
+
+	_, err = st.AdminClient.GrantAppAccess(adminCtx, &adminpb.GrantAppAccessRequest{AppId: st.AppID, UserId: respReg.GetUserId()})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.SetUserLabels(adminCtx, &adminpb.SetUserLabelsRequest{
+		UserId: respReg.GetUserId(),
+		Labels: map[string]string{"cohort": "a"},
+	})
+	require.NoError(t, err)
+
+	_, err = st.AdminClient.FreezeUser(adminCtx, &adminpb.FreezeUserRequest{UserId: respReg.GetUserId()})
+	require.NoError(t, err)
+
+	respBefore, err := st.AdminClient.GetUserAtTime(adminCtx, &adminpb.GetUserAtTimeRequest{
+		PublicId: respReg.GetPublicId(),
+		At:       timestamppb.New(before),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, respReg.GetUserId(), respBefore.GetUserId())
+	assert.Equal(t, before.Unix(), respBefore.GetAt().GetSeconds())
+	assert.False(t, respBefore.GetIsAdmin())
+	assert.Nil(t, respBefore.GetFrozenAt())
+	assert.Empty(t, respBefore.GetMemberOf())
+	assert.Empty(t, respBefore.GetLabels())
+
+	respNow, err := st.AdminClient.GetUserAtTime(adminCtx, &adminpb.GetUserAtTimeRequest{
+		UserId: respReg.GetUserId(),
+		At:     timestamppb.Now(),
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, respNow.GetFrozenAt())
+	assert.Equal(t, []int32{st.AppID}, respNow.GetMemberOf())
+	assert.Empty(t, respNow.GetOwnerOf())
+	assert.Equal(t, map[string]string{"cohort": "a"}, respNow.GetLabels())
+}
+
+func TestGetUserAtTime_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		req      *adminpb.GetUserAtTimeRequest
+		wantCode codes.Code
+	}{
+		{
+			name:     "Missing user",
+			req:      &adminpb.GetUserAtTimeRequest{At: timestamppb.Now()},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Missing time",
+			req:      &adminpb.GetUserAtTimeRequest{UserId: respReg.GetUserId()},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Invalid time",
+			req:      &adminpb.GetUserAtTimeRequest{UserId: respReg.GetUserId(), At: &timestamppb.Timestamp{Nanos: -1}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Unknown user",
+			req:      &adminpb.GetUserAtTimeRequest{UserId: 1 << 60, At: timestamppb.Now()},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "Before the user was created",
+			req: &adminpb.GetUserAtTimeRequest{
+				UserId: respReg.GetUserId(),
+				At:     timestamppb.New(time.Now().Add(-time.Hour)),
+			},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.GetUserAtTime(adminCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	// Only admins may read the history of users.
+	_, err = st.AdminClient.GetUserAtTime(ctx, &adminpb.GetUserAtTimeRequest{UserId: respReg.GetUserId(), At: timestamppb.Now()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
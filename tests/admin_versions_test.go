@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestUpdateApp_OptimisticLocking(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respGet, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: st.AppID})
+	require.NoError(t, err)
+
+	app := respGet.GetApp()
+	assert.Equal(t, st.AppID, app.GetId())
+	assert.Equal(t, int64(1), app.GetVersion())
+	assert.WithinDuration(t, time.Now(), app.GetCreatedAt().AsTime(), time.Minute)
+
+	newName := "test-" + gofakeit.UUID()
+
+	respUpdate, err := st.AdminClient.UpdateApp(adminCtx, &adminpb.UpdateAppRequest{
+		AppId:   st.AppID,
+		Version: app.GetVersion(),
+		Name:    newName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, newName, respUpdate.GetApp().GetName())
+	assert.Equal(t, app.GetVersion()+1, respUpdate.GetApp().GetVersion())
+	assert.False(t, respUpdate.GetApp().GetUpdatedAt().AsTime().Before(app.GetCreatedAt().AsTime()))
+
+	_, err = st.AdminClient.UpdateApp(adminCtx, &adminpb.UpdateAppRequest{
+		AppId:   st.AppID,
+		Version: app.GetVersion(),
+		Name:    "test-" + gofakeit.UUID(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	_, err = st.AdminClient.UpdateApp(adminCtx, &adminpb.UpdateAppRequest{
+		AppId:   -1,
+		Version: 1,
+		Name:    "test-" + gofakeit.UUID(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestUpdateUser_OptimisticLocking(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	email := gofakeit.Email()
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    email,
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	respGet, err := st.AdminClient.GetUser(adminCtx, &adminpb.GetUserRequest{UserId: respReg.GetUserId()})
+	require.NoError(t, err)
+
+	user := respGet.GetUser()
+	assert.Equal(t, email, user.GetEmail())
+	assert.False(t, user.GetIsAdmin())
+	assert.Equal(t, int64(1), user.GetVersion())
+	assert.WithinDuration(t, time.Now(), user.GetCreatedAt().AsTime(), time.Minute)
+
+	respUpdate, err := st.AdminClient.UpdateUser(adminCtx, &adminpb.UpdateUserRequest{
+		UserId:  user.GetId(),
+		Version: user.GetVersion(),
+		IsAdmin: proto.Bool(true),
+	})
+	require.NoError(t, err)
+	assert.True(t, respUpdate.GetUser().GetIsAdmin())
+	assert.Equal(t, user.GetVersion()+1, respUpdate.GetUser().GetVersion())
+
+	_, err = st.AdminClient.UpdateUser(adminCtx, &adminpb.UpdateUserRequest{
+		UserId:  user.GetId(),
+		Version: user.GetVersion(),
+		IsAdmin: proto.Bool(false),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	_, err = st.AdminClient.UpdateUser(adminCtx, &adminpb.UpdateUserRequest{
+		UserId:  user.GetId(),
+		Version: respUpdate.GetUser().GetVersion(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.AdminClient.GetUser(adminCtx, &adminpb.GetUserRequest{UserId: -1})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+// uploadAvatar streams an image to UploadAvatar in chunks of chunkSize bytes.
+func uploadAvatar(ctx context.Context, t *testing.T, st *suite.Suite, img []byte, chunkSize int) (*pb.UploadAvatarResponse, error) {
+	t.Helper()
+
+	stream, err := st.AuthClient.UploadAvatar(ctx)
+	require.NoError(t, err)
+
+	for len(img) > 0 {
+		n := min(chunkSize, len(img))
+
+		// The server may reject the upload before reading it whole; CloseAndRecv reports why.
+		if err := stream.Send(&pb.UploadAvatarRequest{Chunk: img[:n]}); err != nil {
+			break
+		}
+
+		img = img[n:]
+	}
+
+	return stream.CloseAndRecv()
+}
+
+// signedIn registers a user and returns a context carrying the user's token.
+func signedIn(ctx context.Context, t *testing.T, st *suite.Suite) context.Context {
+	t.Helper()
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+}
+
+func TestUploadAvatar_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	userCtx := signedIn(ctx, t, st)
+
+	respInfo, err := st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, respInfo.GetAvatarUrl())
+
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	img.Set(3, 5, color.RGBA{R: gofakeit.Uint8(), A: 255})
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+
+	respUpload, err := uploadAvatar(userCtx, t, st, buf.Bytes(), 100)
+	require.NoError(t, err)
+
+	avatarURL := respUpload.GetAvatarUrl()
+	assert.Regexp(t, `^http://localhost:44046/avatars/[0-9a-f]{64}\.png$`, avatarURL)
+
+	respInfo, err = st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, avatarURL, respInfo.GetAvatarUrl())
+
+	// The URL depends only on the content, so another user uploading the same image gets it too.
+	respUpload, err = uploadAvatar(signedIn(ctx, t, st), t, st, buf.Bytes(), buf.Len())
+	require.NoError(t, err)
+	assert.Equal(t, avatarURL, respUpload.GetAvatarUrl())
+
+	resp, err := http.Get(avatarURL)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+	assert.Contains(t, resp.Header.Get("Cache-Control"), "immutable")
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), body)
+}
+
+func TestUploadAvatar_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	userCtx := signedIn(ctx, t, st)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		image       []byte
+		expectedErr codes.Code
+	}{
+		{
+			name:        "Not an image",
+			ctx:         userCtx,
+			image:       []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"),
+			expectedErr: codes.InvalidArgument,
+		},
+		{
+			name:        "Empty",
+			ctx:         userCtx,
+			expectedErr: codes.InvalidArgument,
+		},
+		{
+			name:        "Larger than max_size",
+			ctx:         userCtx,
+			image:       append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, st.Cfg.Avatars.MaxSize)...),
+			expectedErr: codes.InvalidArgument,
+		},
+		{
+			name:        "No token",
+			ctx:         ctx,
+			image:       []byte("\x89PNG\r\n\x1a\n"),
+			expectedErr: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uploadAvatar(tt.ctx, t, st, tt.image, 4096)
+			require.Error(t, err)
+			assert.Equal(t, tt.expectedErr, status.Code(err))
+		})
+	}
+
+	resp, err := http.Get("http://localhost:44046/avatars/" + strings.Repeat("0", 64) + ".png")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestDeviceFlow_Approve(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respStart, err := st.AuthClient.StartDeviceAuthorization(ctx, &pb.StartDeviceAuthorizationRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	require.NotEmpty(t, respStart.GetDeviceCode())
+	assert.Regexp(t, `^[A-Z]{4}-[A-Z]{4}$`, respStart.GetUserCode())
+	assert.Equal(t, st.Cfg.DeviceAuthorization.VerificationURI, respStart.GetVerificationUri())
+	assert.Contains(t, respStart.GetVerificationUriComplete(), respStart.GetUserCode())
+	assert.Positive(t, respStart.GetExpiresIn())
+	assert.Positive(t, respStart.GetInterval())
+
+	poll := &pb.PollDeviceTokenRequest{DeviceCode: respStart.GetDeviceCode()}
+
+	respPoll, err := st.AuthClient.PollDeviceToken(ctx, poll)
+	require.NoError(t, err)
+	assert.Equal(t, pb.DeviceTokenStatus_DEVICE_TOKEN_STATUS_PENDING, respPoll.GetStatus())
+	assert.Empty(t, respPoll.GetToken())
+
+	respPoll, err = st.AuthClient.PollDeviceToken(ctx, poll)
+	require.NoError(t, err)
+	assert.Equal(t, pb.DeviceTokenStatus_DEVICE_TOKEN_STATUS_SLOW_DOWN, respPoll.GetStatus())
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	// Users may type the code in lower case and without the dash.
+	typed := strings.ToLower(strings.ReplaceAll(respStart.GetUserCode(), "-", ""))
+
+	respApprove, err := st.AuthClient.ApproveDeviceAuthorization(userCtx, &pb.ApproveDeviceAuthorizationRequest{UserCode: typed})
+	require.NoError(t, err)
+	assert.Equal(t, st.AppID, respApprove.GetAppId())
+
+	respPoll, err = st.AuthClient.PollDeviceToken(ctx, poll)
+	require.NoError(t, err)
+	require.Equal(t, pb.DeviceTokenStatus_DEVICE_TOKEN_STATUS_APPROVED, respPoll.GetStatus())
+
+	tokenParsed, err := jwt.Parse(respPoll.GetToken(), func(token *jwt.Token) (interface{}, error) {
+		return []byte(st.AppSecret), nil
+	})
+	require.NoError(t, err)
+
+	claims, ok := tokenParsed.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+
+	assert.Equal(t, email, claims["email"].(string))
+	assert.Equal(t, st.AppID, int32(claims["app_id"].(float64)))
+	assert.Equal(t, respReg.GetPublicId(), claims["sub"].(string))
+
+	// A device code is exchanged for a token only once.
+	_, err = st.AuthClient.PollDeviceToken(ctx, poll)
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.AuthClient.ApproveDeviceAuthorization(userCtx, &pb.ApproveDeviceAuthorizationRequest{UserCode: typed})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDeviceFlow_Deny(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	respStart, err := st.AuthClient.StartDeviceAuthorization(ctx, &pb.StartDeviceAuthorizationRequest{AppId: st.AppID})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.ApproveDeviceAuthorization(userCtx, &pb.ApproveDeviceAuthorizationRequest{
+		UserCode: respStart.GetUserCode(),
+		Deny:     true,
+	})
+	require.NoError(t, err)
+
+	respPoll, err := st.AuthClient.PollDeviceToken(ctx, &pb.PollDeviceTokenRequest{DeviceCode: respStart.GetDeviceCode()})
+	require.NoError(t, err)
+	assert.Equal(t, pb.DeviceTokenStatus_DEVICE_TOKEN_STATUS_DENIED, respPoll.GetStatus())
+	assert.Empty(t, respPoll.GetToken())
+}
+
+func TestDeviceFlow_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	respStart, err := st.AuthClient.StartDeviceAuthorization(ctx, &pb.StartDeviceAuthorizationRequest{AppId: st.AppID})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode codes.Code
+	}{
+		{
+			name: "Start without app_id",
+			call: func() error {
+				_, err := st.AuthClient.StartDeviceAuthorization(ctx, &pb.StartDeviceAuthorizationRequest{})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Start with unknown app",
+			call: func() error {
+				_, err := st.AuthClient.StartDeviceAuthorization(ctx, &pb.StartDeviceAuthorizationRequest{AppId: 1 << 30})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Approve without token",
+			call: func() error {
+				_, err := st.AuthClient.ApproveDeviceAuthorization(ctx, &pb.ApproveDeviceAuthorizationRequest{
+					UserCode: respStart.GetUserCode(),
+				})
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "Approve unknown user code",
+			call: func() error {
+				_, err := st.AuthClient.ApproveDeviceAuthorization(st.AdminContext(ctx), &pb.ApproveDeviceAuthorizationRequest{
+					UserCode: "BBBB-BBBB",
+				})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Poll unknown device code",
+			call: func() error {
+				_, err := st.AuthClient.PollDeviceToken(ctx, &pb.PollDeviceTokenRequest{DeviceCode: gofakeit.UUID()})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
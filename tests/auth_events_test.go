@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"expvar"
+	"strconv"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+// The server runs in the test process, so its counters are read directly. Other tests
+// run in parallel, so the counters are only checked to have grown.
+func TestEvents_Metrics(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	registered := counter(t, "auth_users_registered", "")
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+	assert.Greater(t, counter(t, "auth_users_registered", ""), registered)
+
+	failed := counter(t, "auth_logins_failed", "invalid_password")
+
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password + "x", AppId: st.AppID})
+	require.Error(t, err)
+	assert.Greater(t, counter(t, "auth_logins_failed", "invalid_password"), failed)
+
+	succeeded := counter(t, "auth_logins_succeeded", "password")
+
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+	assert.Greater(t, counter(t, "auth_logins_succeeded", "password"), succeeded)
+}
+
+// counter returns the value of an expvar counter, or of the key of a counter map.
+func counter(t *testing.T, name string, key string) int64 {
+	t.Helper()
+
+	v := expvar.Get(name)
+	require.NotNil(t, v, "metric %s is not published", name)
+
+	if m, ok := v.(*expvar.Map); ok {
+		v = m.Get(key)
+		if v == nil {
+			return 0
+		}
+	}
+
+	n, err := strconv.ParseInt(v.String(), 10, 64)
+	require.NoError(t, err)
+
+	return n
+}
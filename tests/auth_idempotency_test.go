@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestRegister_IdempotencyKey(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	keyCtx := metadata.AppendToOutgoingContext(ctx, "idempotency-key", gofakeit.UUID())
+
+	req := &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	}
+
+	respFirst, err := st.AuthClient.Register(keyCtx, req)
+	require.NoError(t, err)
+	require.NotEmpty(t, respFirst.GetUserId())
+
+	respRetry, err := st.AuthClient.Register(keyCtx, req)
+	require.NoError(t, err)
+	assert.Equal(t, respFirst.GetUserId(), respRetry.GetUserId())
+
+	_, err = st.AuthClient.Register(keyCtx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: req.GetPassword(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.AuthClient.Register(ctx, req)
+	require.Error(t, err)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestIntrospectToken(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	respAccess, err := st.AuthClient.IntrospectToken(ctx, &pb.IntrospectTokenRequest{Token: respLog.GetToken()})
+	require.NoError(t, err)
+
+	assert.True(t, respAccess.GetActive())
+	assert.Equal(t, "access_token", respAccess.GetTokenType())
+	assert.Equal(t, respReg.GetPublicId(), respAccess.GetSub())
+	assert.Equal(t, email, respAccess.GetUsername())
+	assert.Equal(t, st.AppID, respAccess.GetAppId())
+	assert.Contains(t, respAccess.GetAudience(), st.AppID)
+	assert.True(t, respAccess.GetExpiresAt().AsTime().After(respAccess.GetIssuedAt().AsTime()))
+	assert.Equal(t, []string{"pwd"}, respAccess.GetAmr())
+
+	if respLog.GetRefreshToken() != "" {
+		// The hint only decides which type is looked up first.
+		for _, hint := range []string{"", "access_token", "refresh_token"} {
+			respRefresh, err := st.AuthClient.IntrospectToken(ctx, &pb.IntrospectTokenRequest{
+				Token:         respLog.GetRefreshToken(),
+				TokenTypeHint: hint,
+			})
+			require.NoError(t, err)
+
+			assert.True(t, respRefresh.GetActive())
+			assert.Equal(t, "refresh_token", respRefresh.GetTokenType())
+			assert.Equal(t, respReg.GetPublicId(), respRefresh.GetSub())
+			assert.Equal(t, st.AppID, respRefresh.GetAppId())
+			assert.Equal(t, respAccess.GetAuthTime().AsTime(), respRefresh.GetAuthTime().AsTime())
+		}
+	}
+
+	respBad, err := st.AuthClient.IntrospectToken(ctx, &pb.IntrospectTokenRequest{Token: "not-a-token"})
+	require.NoError(t, err)
+	assert.False(t, respBad.GetActive())
+	assert.Empty(t, respBad.GetSub())
+
+	_, err = st.AuthClient.IntrospectToken(ctx, &pb.IntrospectTokenRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	_, err = st.AuthClient.Logout(userCtx, &pb.LogoutRequest{})
+	require.NoError(t, err)
+
+	for _, token := range []string{respLog.GetToken(), respLog.GetRefreshToken()} {
+		if token == "" {
+			continue
+		}
+
+		resp, err := st.AuthClient.IntrospectToken(ctx, &pb.IntrospectTokenRequest{Token: token})
+		require.NoError(t, err)
+		assert.False(t, resp.GetActive())
+	}
+}
+
+func TestIntrospectToken_HTTP(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	if st.Cfg.Introspection.Addr == "" {
+		t.Skip("introspection over HTTP is disabled")
+	}
+
+	endpoint := "http://" + st.Cfg.Introspection.Addr + "/introspect"
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	introspect := func(form url.Values) (int, map[string]any) {
+		resp, err := http.PostForm(endpoint, form)
+		require.NoError(t, err)
+
+		defer resp.Body.Close()
+
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+		return resp.StatusCode, body
+	}
+
+	code, body := introspect(url.Values{"token": {respLog.GetToken()}})
+	require.Equal(t, http.StatusOK, code)
+
+	assert.Equal(t, true, body["active"])
+	assert.Equal(t, "access_token", body["token_type"])
+	assert.Equal(t, respReg.GetPublicId(), body["sub"])
+	assert.Equal(t, email, body["username"])
+	assert.Equal(t, strconv.Itoa(int(st.AppID)), body["client_id"])
+	assert.Contains(t, body["aud"], strconv.Itoa(int(st.AppID)))
+	assert.Greater(t, body["exp"], body["iat"])
+
+	code, body = introspect(url.Values{"token": {"not-a-token"}, "token_type_hint": {"refresh_token"}})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]any{"active": false}, body)
+
+	code, body = introspect(url.Values{})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "invalid_request", body["error"])
+}
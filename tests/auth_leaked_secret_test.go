@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestReportLeakedSecret_RotatesAndRevokes(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	secret := gofakeit.UUID()
+
+	respCreate, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:   "test-" + gofakeit.UUID(),
+		Secret: secret,
+	})
+	require.NoError(t, err)
+
+	appID := respCreate.GetAppId()
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err = st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.ReportLeakedSecret(ctx, &pb.ReportLeakedSecretRequest{
+		Secret: secret,
+		Source: "https://example.com/commit/" + gofakeit.UUID(),
+	})
+	require.NoError(t, err)
+
+	respApp, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: appID})
+	require.NoError(t, err)
+	assert.NotNil(t, respApp.GetApp().GetTokensRevokedAt())
+
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// New tokens are signed with the replaced secret, so the leaked one cannot verify them.
+	respLog, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.NoError(t, err)
+
+	_, err = jwt.Parse(respLog.GetToken(), func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	})
+	assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+
+	// Repeated reports of the same secret succeed without rotating it again.
+	_, err = st.AuthClient.ReportLeakedSecret(ctx, &pb.ReportLeakedSecretRequest{Secret: secret})
+	require.NoError(t, err)
+
+	respAgain, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: appID})
+	require.NoError(t, err)
+	assert.Equal(t, respApp.GetApp().GetVersion(), respAgain.GetApp().GetVersion())
+}
+
+func TestReportLeakedSecret_UnknownSecret(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.AuthClient.ReportLeakedSecret(ctx, &pb.ReportLeakedSecretRequest{Secret: gofakeit.UUID()})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.ReportLeakedSecret(ctx, &pb.ReportLeakedSecretRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
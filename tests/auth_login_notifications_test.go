@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+// loginReportPurpose is the audience of login report link tokens.
+const loginReportPurpose = "login_report"
+
+func TestReportLogin_FreezesAccount(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	respRefresh, err := st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.NoError(t, err)
+
+	link := signReportLink(t, st, st.Cfg.LoginNotifications.Key, loginReportPurpose, respReg.GetUserId(), time.Now().Add(time.Hour))
+
+	_, err = st.AuthClient.ReportLogin(ctx, &pb.ReportLoginRequest{Token: link})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respRefresh.GetRefreshToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestReportLogin_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	key := st.Cfg.LoginNotifications.Key
+	userID := respReg.GetUserId()
+	valid := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		token       string
+		expectedErr string
+	}{
+		{
+			name:        "report with empty token",
+			token:       "",
+			expectedErr: "token is required",
+		},
+		{
+			name:        "report with malformed token",
+			token:       gofakeit.UUID(),
+			expectedErr: "invalid or expired link",
+		},
+		{
+			name:        "report with expired link",
+			token:       signReportLink(t, st, key, loginReportPurpose, userID, time.Now().Add(-time.Minute)),
+			expectedErr: "invalid or expired link",
+		},
+		{
+			name:        "report with link for another purpose",
+			token:       signReportLink(t, st, key, "password_reset", userID, valid),
+			expectedErr: "invalid or expired link",
+		},
+		{
+			name:        "report with link signed by another key",
+			token:       signReportLink(t, st, gofakeit.UUID(), loginReportPurpose, userID, valid),
+			expectedErr: "invalid or expired link",
+		},
+		{
+			name:        "report for unknown user",
+			token:       signReportLink(t, st, key, loginReportPurpose, userID+1_000_000, valid),
+			expectedErr: "invalid or expired link",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AuthClient.ReportLogin(ctx, &pb.ReportLoginRequest{Token: tt.token})
+			require.Error(t, err)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+// signReportLink signs a login report link token the way login notifications do.
+func signReportLink(t *testing.T, st *suite.Suite, key string, purpose string, userID int64, exp time.Time) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   strconv.FormatInt(userID, 10),
+		Audience:  jwt.ClaimStrings{purpose},
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(exp),
+	}).SignedString([]byte(key))
+	require.NoError(st, err)
+
+	return token
+}
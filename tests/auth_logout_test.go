@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+// logoutReceiver records the logout tokens POSTed to it. Apps are notified of every user,
+// so it fails the first delivery for one subject only, to exercise retries.
+type logoutReceiver struct {
+	failSubject string
+
+	mu       sync.Mutex
+	failed   bool
+	received map[string][]string // logout tokens by subject
+}
+
+func (r *logoutReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	token, _, err := jwt.NewParser().ParseUnverified(req.PostFormValue("logout_token"), jwt.MapClaims{})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	sub, _ := token.Claims.GetSubject()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if sub == r.failSubject && !r.failed {
+		r.failed = true
+		w.WriteHeader(http.StatusServiceUnavailable)
+
+		return
+	}
+
+	r.received[sub] = append(r.received[sub], token.Raw)
+}
+
+func (r *logoutReceiver) tokens(sub string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.received[sub]
+}
+
+func TestLogout_BackchannelNotification(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	receiver := &logoutReceiver{failSubject: respReg.GetPublicId(), received: make(map[string][]string)}
+
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	adminCtx := st.AdminContext(ctx)
+
+	_, err = st.AdminClient.SetAppBackchannelLogoutUri(adminCtx, &adminpb.SetAppBackchannelLogoutUriRequest{
+		AppId: st.AppID,
+		Uri:   srv.URL + "/logout",
+	})
+	require.NoError(t, err)
+
+	respApp, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/logout", respApp.GetApp().GetBackchannelLogoutUri())
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	_, err = st.AuthClient.Logout(userCtx, &pb.LogoutRequest{})
+	require.NoError(t, err)
+
+	// Every token issued so far is revoked.
+	_, err = st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// The first delivery fails and is retried after the backoff.
+	require.Eventually(t, func() bool {
+		return len(receiver.tokens(respReg.GetPublicId())) > 0
+	}, 5*st.Cfg.BackchannelLogout.Backoff+5*time.Second, 50*time.Millisecond)
+
+	logoutToken, err := jwt.Parse(receiver.tokens(respReg.GetPublicId())[0], func(token *jwt.Token) (interface{}, error) {
+		return []byte(st.AppSecret), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "logout+jwt", logoutToken.Header["typ"])
+
+	claims, ok := logoutToken.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+	assert.Equal(t, st.Cfg.BackchannelLogout.Issuer, claims["iss"])
+	assert.Equal(t, []any{strconv.Itoa(int(st.AppID))}, claims["aud"])
+	assert.NotEmpty(t, claims["jti"])
+	assert.Contains(t, claims["events"], "http://schemas.openid.net/event/backchannel-logout")
+	assert.NotContains(t, claims, "nonce")
+}
+
+func TestLogout_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode codes.Code
+	}{
+		{
+			name: "Logout without token",
+			call: func() error {
+				_, err := st.AuthClient.Logout(ctx, &pb.LogoutRequest{})
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "Relative URI",
+			call: func() error {
+				_, err := st.AdminClient.SetAppBackchannelLogoutUri(st.AdminContext(ctx), &adminpb.SetAppBackchannelLogoutUriRequest{
+					AppId: st.AppID,
+					Uri:   "/logout",
+				})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "URI with fragment",
+			call: func() error {
+				_, err := st.AdminClient.SetAppBackchannelLogoutUri(st.AdminContext(ctx), &adminpb.SetAppBackchannelLogoutUriRequest{
+					AppId: st.AppID,
+					Uri:   "https://app.example.com/logout#now",
+				})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Unknown app",
+			call: func() error {
+				_, err := st.AdminClient.SetAppBackchannelLogoutUri(st.AdminContext(ctx), &adminpb.SetAppBackchannelLogoutUriRequest{
+					AppId: 1_000_000,
+					Uri:   "https://app.example.com/logout",
+				})
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
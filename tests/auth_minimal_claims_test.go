@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestMinimalClaims_GetUserInfo(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respApp, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	assert.False(t, respApp.GetApp().GetMinimalClaims())
+
+	respUpdate, err := st.AdminClient.UpdateApp(adminCtx, &adminpb.UpdateAppRequest{
+		AppId:         st.AppID,
+		Version:       respApp.GetApp().GetVersion(),
+		MinimalClaims: proto.Bool(true),
+	})
+	require.NoError(t, err)
+	assert.True(t, respUpdate.GetApp().GetMinimalClaims())
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	claims := parseClaims(t, st, respLog.GetToken())
+	assert.Equal(t, respReg.GetPublicId(), claims["sub"])
+	assert.Equal(t, strconv.Itoa(int(st.AppID)), claims["aud"])
+	assert.NotContains(t, claims, "email")
+	assert.NotContains(t, claims, "user_id")
+	assert.NotContains(t, claims, "app_id")
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	var header metadata.MD
+
+	respInfo, err := st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, respReg.GetPublicId(), respInfo.GetSub())
+	assert.Equal(t, email, respInfo.GetEmail())
+	require.Len(t, header.Get("cache-control"), 1)
+	assert.Contains(t, header.Get("cache-control")[0], "max-age=")
+
+	// Minimal tokens can be refreshed and stay minimal.
+	respRefresh, err := st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.NoError(t, err)
+	assert.NotContains(t, parseClaims(t, st, respRefresh.GetToken()), "email")
+
+	// PASETO tokens support minimal claims as well.
+	respUpdate, err = st.AdminClient.UpdateApp(adminCtx, &adminpb.UpdateAppRequest{
+		AppId:       st.AppID,
+		Version:     respUpdate.GetApp().GetVersion(),
+		TokenFormat: adminpb.TokenFormat_TOKEN_FORMAT_PASETO_V4_LOCAL,
+	})
+	require.NoError(t, err)
+	assert.True(t, respUpdate.GetApp().GetMinimalClaims())
+
+	respLog, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	userCtx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	respInfo, err = st.AuthClient.GetUserInfo(userCtx, &pb.GetUserInfoRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, email, respInfo.GetEmail())
+
+	// Turning the mode off restores full claims.
+	_, err = st.AdminClient.UpdateApp(adminCtx, &adminpb.UpdateAppRequest{
+		AppId:         st.AppID,
+		Version:       respUpdate.GetApp().GetVersion(),
+		TokenFormat:   adminpb.TokenFormat_TOKEN_FORMAT_JWT,
+		MinimalClaims: proto.Bool(false),
+	})
+	require.NoError(t, err)
+
+	respLog, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+	assert.Equal(t, email, parseClaims(t, st, respLog.GetToken())["email"])
+}
+
+func TestGetUserInfo_Unauthenticated(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.AuthClient.GetUserInfo(ctx, &pb.GetUserInfoRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	invalidCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer not-a-token")
+
+	_, err = st.AuthClient.GetUserInfo(invalidCtx, &pb.GetUserInfoRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestPaseto_LoginAndValidate(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respApp, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.TokenFormat_TOKEN_FORMAT_JWT, respApp.GetApp().GetTokenFormat())
+
+	respUpdate, err := st.AdminClient.UpdateApp(adminCtx, &adminpb.UpdateAppRequest{
+		AppId:       st.AppID,
+		Version:     respApp.GetApp().GetVersion(),
+		TokenFormat: adminpb.TokenFormat_TOKEN_FORMAT_PASETO_V4_LOCAL,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.TokenFormat_TOKEN_FORMAT_PASETO_V4_LOCAL, respUpdate.GetApp().GetTokenFormat())
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err = st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	token := respLog.GetToken()
+	require.True(t, strings.HasPrefix(token, "v4.local."), "token %q is not a PASETO v4.local token", token)
+	// The payload is encrypted, so the email must not be readable from the token.
+	assert.NotContains(t, token, email)
+
+	// A PASETO token authenticates calls just like a JWT.
+	respStart, err := st.AuthClient.StartDeviceAuthorization(ctx, &pb.StartDeviceAuthorizationRequest{AppId: st.AppID})
+	require.NoError(t, err)
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+
+	_, err = st.AuthClient.ApproveDeviceAuthorization(userCtx, &pb.ApproveDeviceAuthorizationRequest{UserCode: respStart.GetUserCode()})
+	require.NoError(t, err)
+
+	// Tampering with the token invalidates it.
+	tampered := token[:len(token)-2] + "AA"
+	if tampered == token {
+		tampered = token[:len(token)-2] + "BB"
+	}
+
+	tamperedCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+tampered)
+
+	_, err = st.AuthClient.ApproveDeviceAuthorization(tamperedCtx, &pb.ApproveDeviceAuthorizationRequest{UserCode: respStart.GetUserCode()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestPaseto_CreateApp(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respCreate, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:        "test-" + gofakeit.UUID(),
+		Secret:      gofakeit.UUID(),
+		TokenFormat: adminpb.TokenFormat_TOKEN_FORMAT_PASETO_V4_LOCAL,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, _ = st.AdminClient.DeleteApp(adminCtx, &adminpb.DeleteAppRequest{AppId: respCreate.GetAppId()})
+	})
+
+	respApp, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: respCreate.GetAppId()})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.TokenFormat_TOKEN_FORMAT_PASETO_V4_LOCAL, respApp.GetApp().GetTokenFormat())
+
+	_, err = st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:        "test-" + gofakeit.UUID(),
+		Secret:      gofakeit.UUID(),
+		TokenFormat: adminpb.TokenFormat(42),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
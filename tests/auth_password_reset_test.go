@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestRequestPasswordReset_DoesNotRevealAccounts(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    email,
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: email})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: gofakeit.Email()})
+	require.NoError(t, err)
+}
+
+func TestResetPassword_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	tests := []struct {
+		name        string
+		token       string
+		newPassword string
+		expectedErr string
+	}{
+		{
+			name:        "reset with empty token",
+			token:       "",
+			newPassword: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+			expectedErr: "token is required",
+		},
+		{
+			name:        "reset with empty password",
+			token:       gofakeit.UUID(),
+			newPassword: "",
+			expectedErr: "new_password is required",
+		},
+		{
+			name:        "reset with unknown token",
+			token:       gofakeit.UUID(),
+			newPassword: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+			expectedErr: "invalid or expired reset token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AuthClient.ResetPassword(ctx, &pb.ResetPasswordRequest{
+				Token:       tt.token,
+				NewPassword: tt.newPassword,
+			})
+			require.Error(t, err)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestInvalidatePasswordResetTokens(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.AdminClient.InvalidatePasswordResetTokens(ctx, &adminpb.InvalidatePasswordResetTokensRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.AdminClient.InvalidatePasswordResetTokens(st.AdminContext(ctx), &adminpb.InvalidatePasswordResetTokensRequest{})
+	require.NoError(t, err)
+}
@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestPublicID_Lookup(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+		Email:    gofakeit.Email(),
+		Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, respReg.GetPublicId())
+
+	if st.Cfg.Registration.IDStrategy == "sequential" {
+		assert.Equal(t, strconv.FormatInt(respReg.GetUserId(), 10), respReg.GetPublicId())
+	}
+
+	respIsAdmin, err := st.AuthClient.IsAdmin(ctx, &pb.IsAdminRequest{PublicId: respReg.GetPublicId()})
+	require.NoError(t, err)
+	assert.False(t, respIsAdmin.GetIsAdmin())
+
+	_, err = st.AuthClient.IsAdmin(ctx, &pb.IsAdminRequest{PublicId: gofakeit.UUID()})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	respGet, err := st.AdminClient.GetUser(st.AdminContext(ctx), &adminpb.GetUserRequest{PublicId: respReg.GetPublicId()})
+	require.NoError(t, err)
+	assert.Equal(t, respReg.GetUserId(), respGet.GetUser().GetId())
+	assert.Equal(t, respReg.GetPublicId(), respGet.GetUser().GetPublicId())
+}
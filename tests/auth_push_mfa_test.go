@@ -0,0 +1,207 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+// enrollPushMFA registers a user, logs in on the device that will approve logins, and
+// turns push MFA on. It returns the credentials and the context of the signed-in device.
+func enrollPushMFA(ctx context.Context, t *testing.T, st *suite.Suite) (string, string, context.Context) {
+	t.Helper()
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	deviceCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	_, err = st.AuthClient.SetPushMfa(deviceCtx, &pb.SetPushMfaRequest{Enabled: true})
+	require.NoError(t, err)
+
+	return email, password, deviceCtx
+}
+
+func TestPushMFA_Approve(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email, password, deviceCtx := enrollPushMFA(ctx, t, st)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+	assert.Empty(t, respLog.GetToken())
+	require.NotEmpty(t, respLog.GetMfaChallengeId())
+	assert.WithinDuration(t, time.Now().Add(st.Cfg.PushMFA.ChallengeTTL), respLog.GetMfaChallengeExpiresAt().AsTime(), 5*time.Second)
+
+	poll := &pb.PollLoginChallengeRequest{ChallengeId: respLog.GetMfaChallengeId()}
+
+	respPoll, err := st.AuthClient.PollLoginChallenge(ctx, poll)
+	require.NoError(t, err)
+	assert.Equal(t, pb.LoginChallengeStatus_LOGIN_CHALLENGE_STATUS_PENDING, respPoll.GetStatus())
+	assert.Empty(t, respPoll.GetToken())
+
+	respList, err := st.AuthClient.ListLoginChallenges(deviceCtx, &pb.ListLoginChallengesRequest{})
+	require.NoError(t, err)
+	require.Len(t, respList.GetChallenges(), 1)
+
+	challenge := respList.GetChallenges()[0]
+	assert.Equal(t, st.AppID, challenge.GetAppId())
+	assert.NotEmpty(t, challenge.GetAppName())
+	assert.NotEmpty(t, challenge.GetIp())
+
+	_, err = st.AuthClient.DecideLoginChallenge(deviceCtx, &pb.DecideLoginChallengeRequest{Id: challenge.GetId()})
+	require.NoError(t, err)
+
+	respPoll, err = st.AuthClient.PollLoginChallenge(ctx, poll)
+	require.NoError(t, err)
+	require.Equal(t, pb.LoginChallengeStatus_LOGIN_CHALLENGE_STATUS_APPROVED, respPoll.GetStatus())
+
+	tokenParsed, err := jwt.Parse(respPoll.GetToken(), func(token *jwt.Token) (interface{}, error) {
+		return []byte(st.AppSecret), nil
+	})
+	require.NoError(t, err)
+
+	claims, ok := tokenParsed.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+	assert.Equal(t, email, claims["email"].(string))
+
+	// A challenge is exchanged for a token only once.
+	_, err = st.AuthClient.PollLoginChallenge(ctx, poll)
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	respList, err = st.AuthClient.ListLoginChallenges(deviceCtx, &pb.ListLoginChallengesRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, respList.GetChallenges())
+}
+
+func TestPushMFA_Deny(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email, password, deviceCtx := enrollPushMFA(ctx, t, st)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	respList, err := st.AuthClient.ListLoginChallenges(deviceCtx, &pb.ListLoginChallengesRequest{})
+	require.NoError(t, err)
+	require.Len(t, respList.GetChallenges(), 1)
+
+	id := respList.GetChallenges()[0].GetId()
+
+	// Other users cannot decide the challenge.
+	_, _, otherCtx := enrollPushMFA(ctx, t, st)
+
+	_, err = st.AuthClient.DecideLoginChallenge(otherCtx, &pb.DecideLoginChallengeRequest{Id: id})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = st.AuthClient.DecideLoginChallenge(deviceCtx, &pb.DecideLoginChallengeRequest{Id: id, Deny: true})
+	require.NoError(t, err)
+
+	respPoll, err := st.AuthClient.PollLoginChallenge(ctx, &pb.PollLoginChallengeRequest{ChallengeId: respLog.GetMfaChallengeId()})
+	require.NoError(t, err)
+	assert.Equal(t, pb.LoginChallengeStatus_LOGIN_CHALLENGE_STATUS_DENIED, respPoll.GetStatus())
+	assert.Empty(t, respPoll.GetToken())
+
+	// A decided challenge cannot be decided again.
+	_, err = st.AuthClient.DecideLoginChallenge(deviceCtx, &pb.DecideLoginChallengeRequest{Id: id})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestPushMFA_AdminDisables(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email, password, deviceCtx := enrollPushMFA(ctx, t, st)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+	require.NotEmpty(t, respLog.GetMfaChallengeId())
+
+	respInfo, err := st.AuthClient.GetUserInfo(deviceCtx, &pb.GetUserInfoRequest{})
+	require.NoError(t, err)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respUser, err := st.AdminClient.GetUser(adminCtx, &adminpb.GetUserRequest{PublicId: respInfo.GetSub()})
+	require.NoError(t, err)
+	assert.True(t, respUser.GetUser().GetPushMfa())
+
+	_, err = st.AdminClient.SetUserPushMfa(adminCtx, &adminpb.SetUserPushMfaRequest{PublicId: respInfo.GetSub()})
+	require.NoError(t, err)
+
+	respLog, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+	assert.NotEmpty(t, respLog.GetToken())
+	assert.Empty(t, respLog.GetMfaChallengeId())
+}
+
+func TestPushMFA_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode codes.Code
+	}{
+		{
+			name: "SetPushMfa without token",
+			call: func() error {
+				_, err := st.AuthClient.SetPushMfa(ctx, &pb.SetPushMfaRequest{Enabled: true})
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "Poll without challenge_id",
+			call: func() error {
+				_, err := st.AuthClient.PollLoginChallenge(ctx, &pb.PollLoginChallengeRequest{})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Poll with unknown challenge",
+			call: func() error {
+				_, err := st.AuthClient.PollLoginChallenge(ctx, &pb.PollLoginChallengeRequest{ChallengeId: "unknown"})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "SetUserPushMfa without user",
+			call: func() error {
+				_, err := st.AdminClient.SetUserPushMfa(st.AdminContext(ctx), &adminpb.SetUserPushMfaRequest{Enabled: true})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestRefreshToken(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	respRefresh, err := st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.NoError(t, err)
+
+	claims := parseClaims(t, st, respRefresh.GetToken())
+	assert.Equal(t, email, claims["email"].(string))
+	assert.Equal(t, respReg.GetPublicId(), claims["sub"].(string))
+	assert.InDelta(t, time.Now().Add(st.Cfg.TokenTTL).Unix(), claims["exp"].(float64), 1)
+
+	// The refreshed token records the login, not the refresh.
+	loginClaims := parseClaims(t, st, respLog.GetToken())
+	assert.Equal(t, loginClaims["auth_time"], claims["auth_time"])
+	assert.Equal(t, loginClaims["amr"], claims["amr"])
+	assert.Equal(t, loginClaims["acr"], claims["acr"])
+
+	// config/local.yml enables refresh tokens, so tokens cannot renew themselves: revoking
+	// the refresh tokens of a session would not end it otherwise.
+	require.Positive(t, st.Cfg.RefreshTokens.TTL)
+
+	for _, token := range []string{respLog.GetToken(), respRefresh.GetToken()} {
+		_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{Token: token})
+		require.Error(t, err)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	}
+}
+
+func TestRefreshToken_RefreshTokens(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	login := func() *pb.LoginResponse {
+		resp, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.GetRefreshToken())
+
+		return resp
+	}
+
+	respLog := login()
+
+	respRefresh, err := st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.NoError(t, err)
+	require.NotEmpty(t, respRefresh.GetRefreshToken())
+	assert.NotEqual(t, respLog.GetRefreshToken(), respRefresh.GetRefreshToken())
+
+	// The new token records the login, not the refresh.
+	claims := parseClaims(t, st, respRefresh.GetToken())
+	loginClaims := parseClaims(t, st, respLog.GetToken())
+	assert.Equal(t, email, claims["email"].(string))
+	assert.Equal(t, loginClaims["auth_time"], claims["auth_time"])
+	assert.Equal(t, loginClaims["amr"], claims["amr"])
+
+	// The refresh token was rotated; presenting it again revokes its successor too.
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respRefresh.GetRefreshToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Revoking a refresh token signs its device out, and unknown ones are ignored.
+	respLog = login()
+
+	_, err = st.AuthClient.RevokeRefreshToken(ctx, &pb.RevokeRefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.AuthClient.RevokeRefreshToken(ctx, &pb.RevokeRefreshTokenRequest{RefreshToken: "not-a-token"})
+	require.NoError(t, err)
+
+	// Logging out revokes refresh tokens along with access tokens.
+	respLog = login()
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+	_, err = st.AuthClient.Logout(userCtx, &pb.LogoutRequest{})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestRefreshToken_KeepsAppSignedTokensAppSigned(t *testing.T) {
+	// Tokens renew themselves only while refresh tokens are disabled.
+	ctx, st := suite.NewWithConfig(t, func(cfg *config.Config) {
+		cfg.RefreshTokens.TTL = 0
+	})
+
+	adminCtx := st.AdminContext(ctx)
+
+	secret := gofakeit.UUID()
+
+	respApp, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:        "test-" + gofakeit.UUID(),
+		Secret:      secret,
+		TokenFormat: adminpb.TokenFormat_TOKEN_FORMAT_JWT_RS256,
+	})
+	require.NoError(t, err)
+
+	appID := respApp.GetAppId()
+
+	respWho, err := st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: st.AdminToken(ctx)})
+	require.NoError(t, err)
+
+	// The app's secret still verifies HS256 tokens of it, issued before it switched to RS256.
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": respWho.GetUserId(),
+		"app_id":  appID,
+		"email":   respWho.GetEmail(),
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	forgedCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+forged)
+
+	_, err = st.AdminClient.GetStats(forgedCtx, &adminpb.GetStatsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// Re-signed by the service, it would pass as an admin token.
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{Token: forged})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Tokens the service signed are refreshed as before.
+	respRefresh, err := st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{Token: st.AdminTokenFor(ctx, appID)})
+	require.NoError(t, err)
+
+	refreshedCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respRefresh.GetToken())
+
+	_, err = st.AdminClient.GetStats(refreshedCtx, &adminpb.GetStatsRequest{})
+	require.NoError(t, err)
+}
+
+// signToken signs a token for the test app the way the service does, with the given expiration.
+func signToken(t *testing.T, st *suite.Suite, userID int64, email string, exp time.Time) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"app_id":  st.AppID,
+		"email":   email,
+		"exp":     exp.Unix(),
+	}).SignedString([]byte(st.AppSecret))
+	require.NoError(t, err)
+
+	return token
+}
+
+// parseClaims verifies a JWT issued for the test app and returns its claims.
+func parseClaims(t *testing.T, st *suite.Suite, token string) jwt.MapClaims {
+	t.Helper()
+
+	parsed, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		return []byte(st.AppSecret), nil
+	})
+	require.NoError(t, err)
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+
+	return claims
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestRegisterAsync_Completes(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password, Async: true})
+	require.NoError(t, err)
+	require.NotEmpty(t, respReg.GetRegistrationId())
+	assert.Empty(t, respReg.GetUserId())
+	assert.Empty(t, respReg.GetPublicId())
+
+	respStatus := waitForRegistration(ctx, t, st, respReg.GetRegistrationId())
+	require.Equal(t, pb.RegistrationStatus_REGISTRATION_STATUS_COMPLETED, respStatus.GetStatus())
+	assert.Positive(t, respStatus.GetUserId())
+	assert.NotEmpty(t, respStatus.GetPublicId())
+	assert.Empty(t, respStatus.GetFailureReason())
+
+	_, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+}
+
+func TestRegisterAsync_ExistingEmail(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password, Async: true})
+	require.Error(t, err)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestRegisterAsync_ConcurrentSameEmail(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	// Requests usually pass the check for a taken email before any account exists,
+	// so all but one of them fail in the worker instead.
+	var ids []string
+
+	for range 2 {
+		respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password, Async: true})
+		if status.Code(err) == codes.AlreadyExists {
+			continue
+		}
+
+		require.NoError(t, err)
+
+		ids = append(ids, respReg.GetRegistrationId())
+	}
+
+	statuses := map[pb.RegistrationStatus]int{}
+
+	for _, id := range ids {
+		respStatus := waitForRegistration(ctx, t, st, id)
+		statuses[respStatus.GetStatus()]++
+
+		if respStatus.GetStatus() == pb.RegistrationStatus_REGISTRATION_STATUS_FAILED {
+			assert.Equal(t, "user_exists", respStatus.GetFailureReason())
+		}
+	}
+
+	assert.Equal(t, 1, statuses[pb.RegistrationStatus_REGISTRATION_STATUS_COMPLETED])
+	assert.Equal(t, len(ids)-1, statuses[pb.RegistrationStatus_REGISTRATION_STATUS_FAILED])
+}
+
+func TestGetRegistrationStatus_UnknownID(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.AuthClient.GetRegistrationStatus(ctx, &pb.GetRegistrationStatusRequest{RegistrationId: gofakeit.UUID()})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = st.AuthClient.GetRegistrationStatus(ctx, &pb.GetRegistrationStatusRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// waitForRegistration polls the status of an async registration until it is no longer pending.
+func waitForRegistration(ctx context.Context, t *testing.T, st *suite.Suite, registrationID string) *pb.GetRegistrationStatusResponse {
+	t.Helper()
+
+	for {
+		respStatus, err := st.AuthClient.GetRegistrationStatus(ctx, &pb.GetRegistrationStatusRequest{RegistrationId: registrationID})
+		require.NoError(t, err)
+
+		if respStatus.GetStatus() != pb.RegistrationStatus_REGISTRATION_STATUS_PENDING {
+			return respStatus
+		}
+
+		select {
+		case <-ctx.Done():
+			t.Fatal("registration still pending")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
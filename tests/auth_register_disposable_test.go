@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestRegister_DisposableEmailPolicy(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	register := func(email string) error {
+		_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+			Email:    email,
+			Password: password,
+			AppId:    st.AppID,
+		})
+
+		return err
+	}
+
+	respGet, err := st.AdminClient.GetAppDisposableEmailPolicy(adminCtx, &adminpb.GetAppDisposableEmailPolicyRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_UNSPECIFIED, respGet.GetPolicy())
+
+	_, err = st.AdminClient.SetAppDisposableEmailPolicy(adminCtx, &adminpb.SetAppDisposableEmailPolicyRequest{
+		AppId:  st.AppID,
+		Policy: adminpb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_REJECT,
+	})
+	require.NoError(t, err)
+
+	respGet, err = st.AdminClient.GetAppDisposableEmailPolicy(adminCtx, &adminpb.GetAppDisposableEmailPolicyRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_REJECT, respGet.GetPolicy())
+
+	for _, email := range []string{gofakeit.UUID() + "@mailinator.com", gofakeit.UUID() + "@eu.Yopmail.com"} {
+		err = register(email)
+		require.Error(t, err)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, "disposable email addresses are not allowed to register", status.Convert(err).Message())
+	}
+
+	require.NoError(t, register(gofakeit.UUID()+"@corp.test"))
+
+	_, err = st.AdminClient.SetAppDisposableEmailPolicy(adminCtx, &adminpb.SetAppDisposableEmailPolicyRequest{
+		AppId:  st.AppID,
+		Policy: adminpb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_FLAG,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, register(gofakeit.UUID()+"@mailinator.com"))
+}
+
+func TestSetAppDisposableEmailPolicy_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	tests := []struct {
+		name     string
+		req      *adminpb.SetAppDisposableEmailPolicyRequest
+		wantCode codes.Code
+	}{
+		{
+			name:     "Empty app ID",
+			req:      &adminpb.SetAppDisposableEmailPolicyRequest{Policy: adminpb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_REJECT},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Unknown policy",
+			req:      &adminpb.SetAppDisposableEmailPolicyRequest{AppId: st.AppID, Policy: 42},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Unknown app",
+			req:      &adminpb.SetAppDisposableEmailPolicyRequest{AppId: -1, Policy: adminpb.DisposableEmailPolicy_DISPOSABLE_EMAIL_POLICY_REJECT},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.SetAppDisposableEmailPolicy(adminCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestRegister_AppEmailDomains(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	_, err := st.AdminClient.SetAppEmailDomains(adminCtx, &adminpb.SetAppEmailDomainsRequest{
+		AppId:          st.AppID,
+		AllowedDomains: []string{"@Corp.Test", "corp.test", "group.test"},
+		DeniedDomains:  []string{"partner.test"},
+	})
+	require.NoError(t, err)
+
+	respGet, err := st.AdminClient.GetAppEmailDomains(adminCtx, &adminpb.GetAppEmailDomainsRequest{AppId: st.AppID})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"corp.test", "group.test"}, respGet.GetAllowedDomains())
+	assert.Equal(t, []string{"partner.test"}, respGet.GetDeniedDomains())
+
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	tests := []struct {
+		name        string
+		email       string
+		appID       int32
+		wantCode    codes.Code
+		expectedErr string
+	}{
+		{
+			name:     "Allowed domain",
+			email:    gofakeit.UUID() + "@CORP.test",
+			appID:    st.AppID,
+			wantCode: codes.OK,
+		},
+		{
+			name:        "Denied domain",
+			email:       gofakeit.UUID() + "@partner.test",
+			appID:       st.AppID,
+			wantCode:    codes.PermissionDenied,
+			expectedErr: "email domain partner.test is not allowed to register for this app",
+		},
+		{
+			name:        "Domain not in allowed list",
+			email:       gofakeit.UUID() + "@other.test",
+			appID:       st.AppID,
+			wantCode:    codes.PermissionDenied,
+			expectedErr: "registration for this app is limited to email domains: corp.test, group.test",
+		},
+		{
+			name:     "Without app",
+			email:    gofakeit.UUID() + "@other.test",
+			wantCode: codes.OK,
+		},
+		{
+			name:        "Unknown app",
+			email:       gofakeit.UUID() + "@corp.test",
+			appID:       -1,
+			wantCode:    codes.InvalidArgument,
+			expectedErr: "invalid app ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+				Email:    tt.email,
+				Password: password,
+				AppId:    tt.appID,
+			})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+
+			if tt.expectedErr != "" {
+				assert.Equal(t, tt.expectedErr, status.Convert(err).Message())
+			}
+		})
+	}
+}
+
+func TestSetAppEmailDomains_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	tests := []struct {
+		name     string
+		req      *adminpb.SetAppEmailDomainsRequest
+		wantCode codes.Code
+	}{
+		{
+			name:     "Empty app ID",
+			req:      &adminpb.SetAppEmailDomainsRequest{AllowedDomains: []string{"corp.test"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Invalid domain",
+			req:      &adminpb.SetAppEmailDomainsRequest{AppId: st.AppID, DeniedDomains: []string{"user@corp.test"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Domain allowed and denied",
+			req: &adminpb.SetAppEmailDomainsRequest{
+				AppId:          st.AppID,
+				AllowedDomains: []string{"corp.test"},
+				DeniedDomains:  []string{"@corp.test"},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Unknown app",
+			req:      &adminpb.SetAppEmailDomainsRequest{AppId: -1, AllowedDomains: []string{"corp.test"}},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AdminClient.SetAppEmailDomains(adminCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
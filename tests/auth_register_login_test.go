@@ -14,9 +14,7 @@ import (
 )
 
 const (
-	emptyAppID int32  = 0
-	appID      int32  = 1
-	appSecret  string = "test-secret"
+	emptyAppID int32 = 0
 
 	passDefaultLength int = 16
 )
@@ -33,11 +31,12 @@ func TestRegisterLogin_HappyPath(t *testing.T) {
 	})
 	require.NoError(t, err)
 	assert.NotEmpty(t, respReg.GetUserId())
+	assert.NotEmpty(t, respReg.GetPublicId())
 
 	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{
 		Email:    email,
 		Password: password,
-		AppId:    appID,
+		AppId:    st.AppID,
 	})
 	require.NoError(t, err)
 
@@ -47,7 +46,7 @@ func TestRegisterLogin_HappyPath(t *testing.T) {
 	require.NotEmpty(t, token)
 
 	tokenParsed, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		return []byte(appSecret), nil
+		return []byte(st.AppSecret), nil
 	})
 	require.NoError(t, err)
 
@@ -55,8 +54,15 @@ func TestRegisterLogin_HappyPath(t *testing.T) {
 	require.True(t, ok)
 
 	assert.Equal(t, email, claims["email"].(string))
-	assert.Equal(t, appID, int32(claims["app_id"].(float64)))
+	assert.Equal(t, st.AppID, int32(claims["app_id"].(float64)))
 	assert.Equal(t, respReg.GetUserId(), int64(claims["user_id"].(float64)))
+	assert.Equal(t, respReg.GetPublicId(), claims["sub"].(string))
+
+	if st.Cfg.Region != "" {
+		assert.Equal(t, st.Cfg.Region, claims["region"].(string))
+	} else {
+		assert.NotContains(t, claims, "region")
+	}
 
 	const deltaSeconds = 1
 
@@ -140,28 +146,28 @@ func TestLogin_FailCases(t *testing.T) {
 			name:        "login with empty password",
 			email:       gofakeit.Email(),
 			password:    "",
-			appID:       appID,
+			appID:       st.AppID,
 			expectedErr: "password is required",
 		},
 		{
 			name:        "login with empty email",
 			email:       "",
 			password:    gofakeit.Password(true, true, true, true, false, passDefaultLength),
-			appID:       appID,
+			appID:       st.AppID,
 			expectedErr: "email is required",
 		},
 		{
 			name:        "login with both empty email and password",
 			email:       "",
 			password:    "",
-			appID:       appID,
+			appID:       st.AppID,
 			expectedErr: "email is required",
 		},
 		{
 			name:        "login with non matching password",
 			email:       gofakeit.Email(),
 			password:    gofakeit.Password(true, true, true, true, false, passDefaultLength),
-			appID:       appID,
+			appID:       st.AppID,
 			expectedErr: "invalid credentials",
 		},
 		{
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestRegister_RateLimitTrailers(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	register := func() (limit, remaining, reset int) {
+		var trailer metadata.MD
+
+		_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{
+			Email:    gofakeit.Email(),
+			Password: gofakeit.Password(true, true, true, true, false, passDefaultLength),
+		}, grpc.Trailer(&trailer))
+		require.NoError(t, err)
+
+		value := func(key string) int {
+			values := trailer.Get(key)
+			require.Len(t, values, 1, key)
+
+			n, err := strconv.Atoi(values[0])
+			require.NoError(t, err, key)
+
+			return n
+		}
+
+		return value("ratelimit-limit"), value("ratelimit-remaining"), value("ratelimit-reset")
+	}
+
+	limit, first, reset := register()
+	assert.Equal(t, st.Cfg.Registration.PerIPDailyLimit, limit)
+	assert.Less(t, first, limit)
+	assert.GreaterOrEqual(t, reset, 0)
+	assert.LessOrEqual(t, reset, 24*60*60)
+
+	// Other tests register concurrently from the same address, so only the order is known.
+	_, second, _ := register()
+	assert.Less(t, second, first)
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestRS256_LoginAndVerifyOffline(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	secret := gofakeit.UUID()
+
+	respApp, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:        "test-" + gofakeit.UUID(),
+		Secret:      secret,
+		TokenFormat: adminpb.TokenFormat_TOKEN_FORMAT_JWT_RS256,
+	})
+	require.NoError(t, err)
+
+	respGet, err := st.AdminClient.GetApp(adminCtx, &adminpb.GetAppRequest{AppId: respApp.GetAppId()})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.TokenFormat_TOKEN_FORMAT_JWT_RS256, respGet.GetApp().GetTokenFormat())
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: respApp.GetAppId()})
+	require.NoError(t, err)
+
+	// The keys are read after the login, since another test may rotate them meanwhile.
+	respKeys, err := st.AuthClient.GetJwks(ctx, &pb.GetJwksRequest{})
+	require.NoError(t, err)
+
+	publicKeys := make(map[string]*rsa.PublicKey)
+
+	for _, key := range respKeys.GetKeys() {
+		assert.Equal(t, "RSA", key.GetKty())
+		assert.Equal(t, "sig", key.GetUse())
+		assert.Equal(t, "RS256", key.GetAlg())
+
+		n, err := base64.RawURLEncoding.DecodeString(key.GetN())
+		require.NoError(t, err)
+
+		e, err := base64.RawURLEncoding.DecodeString(key.GetE())
+		require.NoError(t, err)
+
+		publicKeys[key.GetKid()] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+
+	// Consumers verify the token with the published key alone, without the app secret.
+	token, err := jwt.Parse(respLog.GetToken(), func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		key, ok := publicKeys[kid]
+		if !ok {
+			return nil, jwt.ErrTokenUnverifiable
+		}
+
+		return key, nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	require.NoError(t, err)
+
+	claims := token.Claims.(jwt.MapClaims)
+	assert.Equal(t, respReg.GetPublicId(), claims["sub"])
+	assert.Equal(t, email, claims["email"])
+	assert.Equal(t, float64(respApp.GetAppId()), claims["app_id"])
+
+	// The service accepts its RS256 tokens like HS256 ones.
+	respWho, err := st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: respLog.GetToken()})
+	require.NoError(t, err)
+	assert.Equal(t, respReg.GetPublicId(), respWho.GetSub())
+
+	respRefresh, err := st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLog.GetRefreshToken()})
+	require.NoError(t, err)
+
+	refreshed, _, err := jwt.NewParser().ParseUnverified(respRefresh.GetToken(), jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", refreshed.Method.Alg())
+
+	// A token signed with the app secret but claiming RS256 is rejected.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	forged.Header["alg"] = "RS256"
+	forged.Header["kid"] = token.Header["kid"]
+
+	forgedToken, err := forged.SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	respIntrospect, err := st.AuthClient.IntrospectToken(ctx, &pb.IntrospectTokenRequest{Token: forgedToken})
+	require.NoError(t, err)
+	assert.False(t, respIntrospect.GetActive())
+}
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestSessions_ListAndRevoke(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	// The tests connect from localhost, which config/local.yml lists as a trusted proxy.
+	laptopCtx := metadata.AppendToOutgoingContext(ctx, "x-forwarded-for", "203.0.113.7")
+
+	respLaptop, err := st.AuthClient.Login(laptopCtx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+	require.NotEmpty(t, respLaptop.GetRefreshToken())
+
+	respPhone, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+	require.NotEmpty(t, respPhone.GetRefreshToken())
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respPhone.GetToken())
+
+	respList, err := st.AuthClient.ListSessions(userCtx, &pb.ListSessionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, respList.GetSessions(), 2)
+
+	var laptop *pb.Session
+	for _, s := range respList.GetSessions() {
+		assert.Equal(t, st.AppID, s.GetAppId())
+		assert.NotEmpty(t, s.GetAppName())
+		assert.NotEmpty(t, s.GetUserAgent())
+		assert.False(t, s.GetLastUsedAt().AsTime().Before(s.GetCreatedAt().AsTime()))
+		assert.True(t, s.GetExpiresAt().AsTime().After(s.GetCreatedAt().AsTime()))
+
+		if s.GetIp() == "203.0.113.7" {
+			laptop = s
+		}
+	}
+	require.NotNil(t, laptop)
+
+	// Another user cannot revoke the session.
+	otherEmail := gofakeit.Email()
+
+	_, err = st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: otherEmail, Password: password})
+	require.NoError(t, err)
+
+	respOther, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: otherEmail, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	otherCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respOther.GetToken())
+	_, err = st.AuthClient.RevokeSession(otherCtx, &pb.RevokeSessionRequest{Id: laptop.GetId()})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Revoking the session signs its device out, and leaves the other session active.
+	_, err = st.AuthClient.RevokeSession(userCtx, &pb.RevokeSessionRequest{Id: laptop.GetId()})
+	require.NoError(t, err)
+
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respLaptop.GetRefreshToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Nor can the access token of the session renew itself.
+	_, err = st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{Token: respLaptop.GetToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	respList, err = st.AuthClient.ListSessions(userCtx, &pb.ListSessionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, respList.GetSessions(), 1)
+	assert.NotEqual(t, laptop.GetId(), respList.GetSessions()[0].GetId())
+
+	// A revoked session cannot be revoked again.
+	_, err = st.AuthClient.RevokeSession(userCtx, &pb.RevokeSessionRequest{Id: laptop.GetId()})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Refreshing keeps the session, whose ID is that of its chain.
+	respRefresh, err := st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respPhone.GetRefreshToken()})
+	require.NoError(t, err)
+	require.NotEmpty(t, respRefresh.GetRefreshToken())
+
+	respRefreshed, err := st.AuthClient.ListSessions(userCtx, &pb.ListSessionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, respRefreshed.GetSessions(), 1)
+	assert.Equal(t, respList.GetSessions()[0].GetId(), respRefreshed.GetSessions()[0].GetId())
+
+	// Logging out ends every session.
+	_, err = st.AuthClient.Logout(userCtx, &pb.LogoutRequest{})
+	require.NoError(t, err)
+
+	userCtx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respRefresh.GetToken())
+	_, err = st.AuthClient.ListSessions(userCtx, &pb.ListSessionsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestSessions_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.AuthClient.ListSessions(ctx, &pb.ListSessionsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.AuthClient.RevokeSession(ctx, &pb.RevokeSessionRequest{Id: 1})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err = st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+respLog.GetToken())
+
+	_, err = st.AuthClient.RevokeSession(userCtx, &pb.RevokeSessionRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
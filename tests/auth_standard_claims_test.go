@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestStandardClaims(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respFirst, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	respSecond, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	// The in-process server adds every optional claim; a server started separately may not.
+	claims := parseClaims(t, st, respFirst.GetToken())
+	if _, ok := claims["jti"]; !ok {
+		t.Skip("optional standard claims are disabled")
+	}
+
+	issuer, _ := claims["iss"].(string)
+	require.NotEmpty(t, issuer)
+	assert.Equal(t, strconv.Itoa(int(st.AppID)), claims["aud"])
+	assert.Equal(t, claims["iat"], claims["nbf"])
+	assert.NotEmpty(t, claims["jti"])
+
+	// Every token has its own ID.
+	assert.NotEqual(t, claims["jti"], parseClaims(t, st, respSecond.GetToken())["jti"])
+
+	respIntrospect, err := st.AuthClient.IntrospectToken(ctx, &pb.IntrospectTokenRequest{Token: respFirst.GetToken()})
+	require.NoError(t, err)
+	assert.True(t, respIntrospect.GetActive())
+	assert.Equal(t, issuer, respIntrospect.GetIss())
+	assert.Equal(t, claims["jti"], respIntrospect.GetJti())
+
+	// Tokens naming another issuer, or not valid yet, are rejected.
+	forge := func(edit func(jwt.MapClaims)) string {
+		forged := jwt.MapClaims{}
+		for k, v := range claims {
+			forged[k] = v
+		}
+
+		edit(forged)
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, forged).SignedString([]byte(st.AppSecret))
+		require.NoError(t, err)
+
+		return token
+	}
+
+	for name, token := range map[string]string{
+		"other issuer": forge(func(c jwt.MapClaims) { c["iss"] = issuer + "/other" }),
+		"not yet valid": forge(func(c jwt.MapClaims) {
+			c["nbf"] = time.Now().Add(st.Cfg.TokenValidation.Leeway + time.Hour).Unix()
+		}),
+	} {
+		_, err := st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: token})
+		require.Error(t, err, name)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), name)
+	}
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestStepUp_Approve(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	token := respLog.GetToken()
+	deviceCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+
+	_, err = st.AuthClient.SetPushMfa(deviceCtx, &pb.SetPushMfaRequest{Enabled: true})
+	require.NoError(t, err)
+
+	// A token from a password login is not elevated.
+	respStep, err := st.AuthClient.RequireStepUp(ctx, &pb.RequireStepUpRequest{Token: token, Action: "delete_account"})
+	require.NoError(t, err)
+	assert.False(t, respStep.GetSatisfied())
+	require.NotEmpty(t, respStep.GetChallengeId())
+
+	respList, err := st.AuthClient.ListLoginChallenges(deviceCtx, &pb.ListLoginChallengesRequest{})
+	require.NoError(t, err)
+	require.Len(t, respList.GetChallenges(), 1)
+	assert.Equal(t, "delete_account", respList.GetChallenges()[0].GetAction())
+
+	_, err = st.AuthClient.DecideLoginChallenge(deviceCtx, &pb.DecideLoginChallengeRequest{Id: respList.GetChallenges()[0].GetId()})
+	require.NoError(t, err)
+
+	respPoll, err := st.AuthClient.PollLoginChallenge(ctx, &pb.PollLoginChallengeRequest{ChallengeId: respStep.GetChallengeId()})
+	require.NoError(t, err)
+	require.Equal(t, pb.LoginChallengeStatus_LOGIN_CHALLENGE_STATUS_APPROVED, respPoll.GetStatus())
+
+	elevated := respPoll.GetToken()
+	assertElevated(t, st, elevated)
+
+	respStep, err = st.AuthClient.RequireStepUp(ctx, &pb.RequireStepUpRequest{Token: elevated, Action: "delete_account"})
+	require.NoError(t, err)
+	assert.True(t, respStep.GetSatisfied())
+	assert.Empty(t, respStep.GetChallengeId())
+	assert.NotNil(t, respStep.GetAuthTime())
+
+	// Refreshing keeps the original authentication.
+	respRefresh, err := st.AuthClient.RefreshToken(ctx, &pb.RefreshTokenRequest{RefreshToken: respPoll.GetRefreshToken()})
+	require.NoError(t, err)
+	assertElevated(t, st, respRefresh.GetToken())
+}
+
+// assertElevated checks that token records an approved second factor.
+func assertElevated(t *testing.T, st *suite.Suite, token string) {
+	t.Helper()
+
+	tokenParsed, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		return []byte(st.AppSecret), nil
+	})
+	require.NoError(t, err)
+
+	claims, ok := tokenParsed.Claims.(jwt.MapClaims)
+	require.True(t, ok)
+	assert.Equal(t, "2fa", claims["acr"])
+	assert.Equal(t, []any{"pwd", "mfa"}, claims["amr"])
+	assert.Positive(t, claims["auth_time"])
+}
+
+func TestStepUp_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		req      *pb.RequireStepUpRequest
+		wantCode codes.Code
+	}{
+		{
+			name:     "Without token",
+			req:      &pb.RequireStepUpRequest{Action: "delete_account"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Without action",
+			req:      &pb.RequireStepUpRequest{Token: respLog.GetToken()},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Invalid token",
+			req:      &pb.RequireStepUpRequest{Token: "invalid", Action: "delete_account"},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "User without push MFA",
+			req:      &pb.RequireStepUpRequest{Token: respLog.GetToken(), Action: "delete_account"},
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.AuthClient.RequireStepUp(ctx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
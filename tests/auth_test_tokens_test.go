@@ -0,0 +1,187 @@
+//go:build !notesttokens
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestMintTestToken(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	_, err := st.AdminClient.SetAppTestOnly(adminCtx, &adminpb.SetAppTestOnlyRequest{AppId: st.AppID, TestOnly: true})
+	require.NoError(t, err)
+
+	subject := gofakeit.UUID()
+	email := gofakeit.Email()
+
+	resp, err := st.AuthClient.MintTestToken(adminCtx, &pb.MintTestTokenRequest{
+		AppId:      st.AppID,
+		Subject:    subject,
+		UserId:     42,
+		Email:      email,
+		Attributes: map[string]string{"department": "qa"},
+		Amr:        []string{"pwd", "mfa"},
+		Acr:        "2fa",
+		TtlSeconds: 300,
+		Scopes:     []string{"reports.read", "reports.write"},
+	})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), resp.GetExpiresAt().AsTime(), time.Minute)
+
+	// The token is signed with the app secret, like the app's tokens.
+	claims := parseClaims(t, st, resp.GetToken())
+	assert.Equal(t, subject, claims["sub"])
+	assert.Equal(t, email, claims["email"])
+	assert.EqualValues(t, 42, claims["user_id"])
+	assert.EqualValues(t, st.AppID, claims["app_id"])
+	assert.Equal(t, map[string]any{"department": "qa"}, claims["attrs"])
+	assert.Equal(t, []any{"pwd", "mfa"}, claims["amr"])
+	assert.Equal(t, "2fa", claims["acr"])
+	assert.Contains(t, claims, "auth_time")
+	assert.Equal(t, "reports.read reports.write", claims["scope"])
+	assert.Equal(t, true, claims["test"])
+
+	exp, err := claims.GetExpirationTime()
+	require.NoError(t, err)
+	assert.True(t, exp.Time.Equal(resp.GetExpiresAt().AsTime().Truncate(time.Second)))
+
+	// Without a TTL, the token lives as long as issued tokens.
+	resp, err = st.AuthClient.MintTestToken(adminCtx, &pb.MintTestTokenRequest{AppId: st.AppID, Subject: subject})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(st.Cfg.TokenTTL), resp.GetExpiresAt().AsTime(), time.Minute)
+	claims = parseClaims(t, st, resp.GetToken())
+	assert.NotContains(t, claims, "auth_time")
+	assert.NotContains(t, claims, "scope")
+}
+
+func TestMintTestToken_NotAcceptedByService(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	respWho, err := st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: st.AdminToken(ctx)})
+	require.NoError(t, err)
+
+	// Tokens of RS256 apps are signed by the service, so admins may use them on admin methods.
+	respApp, err := st.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:        "test-" + gofakeit.UUID(),
+		Secret:      gofakeit.UUID(),
+		TokenFormat: adminpb.TokenFormat_TOKEN_FORMAT_JWT_RS256,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, _ = st.AdminClient.DeleteApp(st.AdminContext(ctx), &adminpb.DeleteAppRequest{AppId: respApp.GetAppId()})
+	})
+
+	_, err = st.AdminClient.SetAppTestOnly(adminCtx, &adminpb.SetAppTestOnlyRequest{AppId: respApp.GetAppId(), TestOnly: true})
+	require.NoError(t, err)
+
+	// A test token naming the admin is still no admin credential.
+	resp, err := st.AuthClient.MintTestToken(adminCtx, &pb.MintTestTokenRequest{
+		AppId:   respApp.GetAppId(),
+		Subject: respWho.GetSub(),
+		UserId:  respWho.GetUserId(),
+		Email:   respWho.GetEmail(),
+	})
+	require.NoError(t, err)
+
+	tokenCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+resp.GetToken())
+
+	_, err = st.AdminClient.GetStats(tokenCtx, &adminpb.GetStatsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.AuthClient.GetUserInfo(tokenCtx, &pb.GetUserInfoRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: resp.GetToken()})
+	require.Error(t, err)
+}
+
+func TestMintTestToken_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminCtx := st.AdminContext(ctx)
+
+	_, err := st.AdminClient.SetAppTestOnly(adminCtx, &adminpb.SetAppTestOnlyRequest{AppId: st.AppID, TestOnly: true})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		anonymous bool
+		req       *pb.MintTestTokenRequest
+		wantCode  codes.Code
+	}{
+		{
+			name:      "Without credentials",
+			anonymous: true,
+			req:       &pb.MintTestTokenRequest{AppId: st.AppID, Subject: gofakeit.UUID()},
+			wantCode:  codes.Unauthenticated,
+		},
+		{
+			name:     "App not test-only",
+			req:      &pb.MintTestTokenRequest{AppId: st.Cfg.GRPC.AdminAppID, Subject: gofakeit.UUID()},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "Missing app",
+			req:      &pb.MintTestTokenRequest{Subject: gofakeit.UUID()},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Missing subject",
+			req:      &pb.MintTestTokenRequest{AppId: st.AppID},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Negative TTL",
+			req:      &pb.MintTestTokenRequest{AppId: st.AppID, Subject: gofakeit.UUID(), TtlSeconds: -1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "TTL beyond max_ttl",
+			req:      &pb.MintTestTokenRequest{AppId: st.AppID, Subject: gofakeit.UUID(), TtlSeconds: int32(st.Cfg.TestTokens.MaxTTL.Seconds()) + 1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Scope with whitespace",
+			req:      &pb.MintTestTokenRequest{AppId: st.AppID, Subject: gofakeit.UUID(), Scopes: []string{"reports read"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "Unknown app",
+			req:      &pb.MintTestTokenRequest{AppId: 1 << 30, Subject: gofakeit.UUID()},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callCtx := adminCtx
+			if tt.anonymous {
+				callCtx = ctx
+			}
+
+			_, err := st.AuthClient.MintTestToken(callCtx, tt.req)
+			require.Error(t, err)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
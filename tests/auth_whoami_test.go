@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestWhoAmI_User(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	respReg, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	respLog, err := st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+	require.NoError(t, err)
+
+	respWho, err := st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: respLog.GetToken()})
+	require.NoError(t, err)
+
+	assert.Equal(t, respReg.GetPublicId(), respWho.GetSub())
+	assert.Equal(t, respReg.GetUserId(), respWho.GetUserId())
+	assert.Equal(t, email, respWho.GetEmail())
+	assert.Equal(t, st.AppID, respWho.GetAppId())
+	assert.Contains(t, respWho.GetAudience(), st.AppID)
+	assert.True(t, respWho.GetExpiresAt().AsTime().After(respWho.GetIssuedAt().AsTime()))
+	assert.Empty(t, respWho.GetRoles())
+	assert.Empty(t, respWho.GetOwnedAppIds())
+
+	// A password login is a single factor.
+	assert.Equal(t, []string{"pwd"}, respWho.GetAmr())
+	assert.Equal(t, "1fa", respWho.GetAcr())
+	assert.WithinDuration(t, respWho.GetIssuedAt().AsTime(), respWho.GetAuthTime().AsTime(), 0)
+
+	// Signed-in users may call the user methods, and nothing else.
+	methods := permittedMethods(respWho)
+	assert.Contains(t, methods, pb.Auth_GetUserInfo_FullMethodName)
+	assert.NotContains(t, methods, adminpb.Admin_GetApp_FullMethodName)
+
+	adminCtx := st.AdminContext(ctx)
+
+	_, err = st.AdminClient.AddAppOwner(adminCtx, &adminpb.AddAppOwnerRequest{AppId: st.AppID, UserId: respReg.GetUserId()})
+	require.NoError(t, err)
+
+	respWho, err = st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: respLog.GetToken()})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"app_owner"}, respWho.GetRoles())
+	assert.Equal(t, []int32{st.AppID}, respWho.GetOwnedAppIds())
+
+	// Admin methods only accept tokens of the admin app, since the test app's secret could forge this one.
+	assert.NotContains(t, permittedMethods(respWho), adminpb.Admin_GetApp_FullMethodName)
+
+	respLog, err = st.AuthClient.Login(ctx, &pb.LoginRequest{Email: email, Password: password, AppId: st.Cfg.GRPC.AdminAppID})
+	require.NoError(t, err)
+
+	respWho, err = st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: respLog.GetToken()})
+	require.NoError(t, err)
+
+	// Owners may manage their own apps only.
+	var getApp *pb.Permission
+	for _, p := range respWho.GetPermissions() {
+		if p.GetMethod() == adminpb.Admin_GetApp_FullMethodName {
+			getApp = p
+		}
+	}
+	require.NotNil(t, getApp)
+	assert.Equal(t, []int32{st.AppID}, getApp.GetAppIds())
+	assert.NotContains(t, permittedMethods(respWho), adminpb.Admin_DeleteApp_FullMethodName)
+
+	_, err = st.AdminClient.RemoveAppOwner(adminCtx, &adminpb.RemoveAppOwnerRequest{AppId: st.AppID, UserId: respReg.GetUserId()})
+	require.NoError(t, err)
+}
+
+func TestWhoAmI_Admin(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	respWho, err := st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: st.AdminToken(ctx)})
+	require.NoError(t, err)
+
+	assert.Contains(t, respWho.GetRoles(), "admin")
+
+	methods := permittedMethods(respWho)
+	assert.Contains(t, methods, adminpb.Admin_DeleteApp_FullMethodName)
+	assert.Contains(t, methods, pb.Auth_GetUserInfo_FullMethodName)
+
+	for _, p := range respWho.GetPermissions() {
+		assert.Empty(t, p.GetAppIds(), p.GetMethod())
+	}
+}
+
+func TestWhoAmI_FailCases(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: "not-a-token"})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+// permittedMethods returns the methods a WhoAmI response permits.
+func permittedMethods(resp *pb.WhoAmIResponse) []string {
+	var methods []string
+	for _, p := range resp.GetPermissions() {
+		methods = append(methods, p.GetMethod())
+	}
+
+	return methods
+}
@@ -0,0 +1,250 @@
+package tests
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kirinyoku/sso-grpc/internal/domain/models"
+	"github.com/kirinyoku/sso-grpc/internal/grpc/interceptors"
+	"github.com/kirinyoku/sso-grpc/internal/lib/authzpolicy"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestAuthzPolicy_OverridesAndReloads(t *testing.T) {
+	t.Parallel()
+
+	log := slog.New(slog.DiscardHandler)
+	defaults := map[string]authzpolicy.Level{
+		adminpb.Admin_GetStats_FullMethodName: authzpolicy.LevelAdmin,
+		pb.Auth_GetUserInfo_FullMethodName:    authzpolicy.LevelAuthenticated,
+	}
+	methods := []string{
+		adminpb.Admin_GetStats_FullMethodName,
+		pb.Auth_GetUserInfo_FullMethodName,
+		pb.Auth_GetServiceStatus_FullMethodName,
+	}
+
+	path := filepath.Join(t.TempDir(), "authz-policy.yml")
+	writePolicy := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	writePolicy("methods:\n  /admin.Admin/GetStats: scope:stats.read\n")
+
+	policy, err := authzpolicy.New(log, path, defaults, methods)
+	require.NoError(t, err)
+
+	assert.Equal(t, authzpolicy.ScopeLevel("stats.read"), policy.Level(adminpb.Admin_GetStats_FullMethodName))
+	assert.Equal(t, authzpolicy.LevelAuthenticated, policy.Level(pb.Auth_GetUserInfo_FullMethodName))
+	assert.Equal(t, authzpolicy.LevelAnonymous, policy.Level(pb.Auth_GetServiceStatus_FullMethodName))
+	assert.Empty(t, policy.Methods(authzpolicy.LevelAdmin))
+
+	scope, ok := policy.Level(adminpb.Admin_GetStats_FullMethodName).Scope()
+	assert.True(t, ok)
+	assert.Equal(t, "stats.read", scope)
+
+	// Changes apply on reload, and methods the file no longer lists fall back to their defaults.
+	writePolicy("methods:\n  /auth.Auth/GetServiceStatus: authenticated\n")
+	require.NoError(t, policy.Reload(context.Background()))
+
+	assert.Equal(t, authzpolicy.LevelAdmin, policy.Level(adminpb.Admin_GetStats_FullMethodName))
+	assert.Equal(t, []string{pb.Auth_GetServiceStatus_FullMethodName, pb.Auth_GetUserInfo_FullMethodName}, policy.Methods(authzpolicy.LevelAuthenticated))
+
+	// An invalid file is rejected on reload, keeping the levels in force.
+	for _, content := range []string{
+		"methods:\n  /auth.Auth/GetServiceStatus: everyone\n",
+		"methods:\n  /auth.Auth/GetServiceStatus: scope:\n",
+		"methods:\n  /auth.Auth/Unknown: admin\n",
+		"methods: [",
+	} {
+		writePolicy(content)
+		assert.Error(t, policy.Reload(context.Background()), content)
+		assert.Equal(t, authzpolicy.LevelAuthenticated, policy.Level(pb.Auth_GetServiceStatus_FullMethodName), content)
+	}
+
+	// An invalid file stops the policy from being created at all.
+	_, err = authzpolicy.New(log, path, defaults, methods)
+	require.Error(t, err)
+
+	_, err = authzpolicy.New(log, filepath.Join(t.TempDir(), "missing.yml"), defaults, methods)
+	require.Error(t, err)
+}
+
+func TestAuthzPolicy_WithoutFile(t *testing.T) {
+	t.Parallel()
+
+	policy, err := authzpolicy.New(slog.New(slog.DiscardHandler), "", map[string]authzpolicy.Level{
+		adminpb.Admin_GetStats_FullMethodName: authzpolicy.LevelAdmin,
+	}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, policy.Reload(context.Background()))
+	assert.Equal(t, authzpolicy.LevelAdmin, policy.Level(adminpb.Admin_GetStats_FullMethodName))
+	assert.Equal(t, []string{adminpb.Admin_GetStats_FullMethodName}, policy.Methods(authzpolicy.LevelAdmin))
+}
+
+func TestAuthzPolicy_AppliesToTLSAndAudit(t *testing.T) {
+	t.Parallel()
+
+	log := slog.New(slog.DiscardHandler)
+	defaults := map[string]authzpolicy.Level{
+		adminpb.Admin_GetStats_FullMethodName: authzpolicy.LevelAdmin,
+	}
+	methods := []string{
+		adminpb.Admin_GetStats_FullMethodName,
+		pb.Auth_GetServiceStatus_FullMethodName,
+	}
+
+	path := filepath.Join(t.TempDir(), "authz-policy.yml")
+	writePolicy := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	// The file raises a method anonymous by default and lowers an admin one.
+	writePolicy("methods:\n  /auth.Auth/GetServiceStatus: authenticated\n  /admin.Admin/GetStats: anonymous\n")
+
+	policy, err := authzpolicy.New(log, path, defaults, methods)
+	require.NoError(t, err)
+
+	recorder := &auditRecorder{}
+	requireTLS := interceptors.RequireTLS(log, policy, []string{pb.Auth_Login_FullMethodName})
+	audit := interceptors.Audit(log, recorder, policy, nil)
+
+	handler := func(context.Context, any) (any, error) { return struct{}{}, nil }
+
+	// The context carries no peer, as over a connection without TLS.
+	call := func(interceptor grpc.UnaryServerInterceptor, method string) error {
+		_, err := interceptor(context.Background(), &pb.GetServiceStatusRequest{}, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	err = call(requireTLS, pb.Auth_GetServiceStatus_FullMethodName)
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.NoError(t, call(requireTLS, adminpb.Admin_GetStats_FullMethodName))
+
+	// Methods carrying credentials require TLS although anyone may call them.
+	err = call(requireTLS, pb.Auth_Login_FullMethodName)
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	require.NoError(t, call(audit, pb.Auth_GetServiceStatus_FullMethodName))
+	require.NoError(t, call(audit, adminpb.Admin_GetStats_FullMethodName))
+	assert.Equal(t, []string{pb.Auth_GetServiceStatus_FullMethodName}, recorder.methods())
+
+	// Once the overrides are dropped, the built-in levels apply again without rebuilding the interceptors.
+	writePolicy("methods: {}\n")
+	require.NoError(t, policy.Reload(context.Background()))
+
+	require.NoError(t, call(requireTLS, pb.Auth_GetServiceStatus_FullMethodName))
+	err = call(requireTLS, adminpb.Admin_GetStats_FullMethodName)
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	require.NoError(t, call(audit, pb.Auth_GetServiceStatus_FullMethodName))
+	require.NoError(t, call(audit, adminpb.Admin_GetStats_FullMethodName))
+	assert.Equal(t, []string{pb.Auth_GetServiceStatus_FullMethodName, adminpb.Admin_GetStats_FullMethodName}, recorder.methods())
+}
+
+func TestAuthzPolicy_ScopedMethodRequiresTrustedToken(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	if suite.DiagnosticsScope == "" {
+		t.Skip("no method requires a scope of an external server")
+	}
+
+	respWho, err := st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: st.AdminToken(ctx)})
+	require.NoError(t, err)
+
+	// The service grants no scopes, so the tokens carrying them are all signed with app secrets.
+	appSigned := signScopedToken(t, st.AppID, st.AppSecret, respWho.GetUserId(), respWho.GetEmail(), suite.DiagnosticsScope)
+	adminAppSigned := signScopedToken(t, st.Cfg.GRPC.AdminAppID, bootstrapAppSecret, respWho.GetUserId(), respWho.GetEmail(), suite.DiagnosticsScope)
+
+	call := func(token string) error {
+		tokenCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+
+		_, err := st.AdminClient.GetDiagnostics(tokenCtx, &adminpb.GetDiagnosticsRequest{})
+
+		return err
+	}
+
+	// Any relying party could have signed the scope into a token of its own app.
+	err = call(appSigned)
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	respWho, err = st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: appSigned})
+	require.NoError(t, err)
+
+	for _, permission := range respWho.GetPermissions() {
+		assert.NotEqual(t, adminpb.Admin_GetDiagnostics_FullMethodName, permission.GetMethod())
+	}
+
+	// Admins get no exception without the scope.
+	err = call(st.AdminToken(ctx))
+	require.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	require.NoError(t, call(adminAppSigned))
+}
+
+// bootstrapAppSecret is the secret of the app seeded by tests/migrations, the admin app of config/local.yml.
+const bootstrapAppSecret = "test-secret"
+
+// signScopedToken signs a token of an app for a user with the app's secret, granted scope.
+func signScopedToken(t *testing.T, appID int32, secret string, userID int64, email, scope string) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"app_id":  appID,
+		"email":   email,
+		"scope":   scope,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	return token
+}
+
+// auditRecorder keeps the audit events recorded, in memory.
+type auditRecorder struct {
+	mu     sync.Mutex
+	events []*models.AuditEvent
+}
+
+func (r *auditRecorder) Record(_ context.Context, event *models.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+
+	return nil
+}
+
+func (r *auditRecorder) methods() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	methods := make([]string, 0, len(r.events))
+	for _, event := range r.events {
+		methods = append(methods, event.Method)
+	}
+
+	return methods
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+// The tests connect from localhost, which config/local.yml lists as a trusted proxy.
+func TestClientAddress_ForwardedFor(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	tests := []struct {
+		name      string
+		forwarded []string
+		want      []string // acceptable addresses; localhost is either family
+	}{
+		{
+			name:      "Single hop",
+			forwarded: []string{"203.0.113.7"},
+			want:      []string{"203.0.113.7"},
+		},
+		{
+			name:      "Entries added by the client are ignored",
+			forwarded: []string{"198.51.100.23, 203.0.113.7"},
+			want:      []string{"203.0.113.7"},
+		},
+		{
+			name:      "Trusted proxies are skipped",
+			forwarded: []string{"203.0.113.7", "127.0.0.1"},
+			want:      []string{"203.0.113.7"},
+		},
+		{
+			name:      "Invalid entry",
+			forwarded: []string{"203.0.113.7, unknown"},
+			want:      []string{"127.0.0.1", "::1"},
+		},
+		{
+			name: "No header",
+			want: []string{"127.0.0.1", "::1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email, password, deviceCtx := enrollPushMFA(ctx, t, st)
+
+			loginCtx := ctx
+			for _, value := range tt.forwarded {
+				loginCtx = metadata.AppendToOutgoingContext(loginCtx, "x-forwarded-for", value)
+			}
+
+			respLog, err := st.AuthClient.Login(loginCtx, &pb.LoginRequest{Email: email, Password: password, AppId: st.AppID})
+			require.NoError(t, err)
+			require.NotEmpty(t, respLog.GetMfaChallengeId())
+
+			// Challenges show the address the login came from.
+			respList, err := st.AuthClient.ListLoginChallenges(deviceCtx, &pb.ListLoginChallengesRequest{})
+			require.NoError(t, err)
+			require.Len(t, respList.GetChallenges(), 1)
+			assert.Contains(t, tt.want, respList.GetChallenges()[0].GetIp())
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/client"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestClientRetry_NeverRetriesInvalidArgument(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	var attempts atomic.Int32
+
+	conn, err := grpc.NewClient(
+		fmt.Sprintf("localhost:%d", st.Cfg.GRPC.Port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(client.UnaryClientInterceptor(client.DefaultRetryPolicy(), nil, client.Hooks{
+			OnAttempt: func(string, int, codes.Code, time.Duration) { attempts.Add(1) },
+		})),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	_, err = pb.NewAuthClient(conn).Login(ctx, &pb.LoginRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestClientRetry_UnavailableOpensCircuit(t *testing.T) {
+	ctx, _ := suite.New(t)
+
+	// Nothing listens on the address of a closed listener.
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+
+	var attempts, retries atomic.Int32
+
+	var states []client.CircuitState
+
+	policy := client.DefaultRetryPolicy()
+	policy.InitialBackoff = 10 * time.Millisecond
+	breaker := client.NewCircuitBreaker(3, time.Hour)
+
+	conn, err := grpc.NewClient(
+		addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(client.UnaryClientInterceptor(policy, breaker, client.Hooks{
+			OnAttempt: func(string, int, codes.Code, time.Duration) { attempts.Add(1) },
+			OnRetry:   func(string, int, time.Duration) { retries.Add(1) },
+			OnCircuitStateChange: func(_ client.CircuitState, to client.CircuitState) {
+				states = append(states, to)
+			},
+		})),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	callCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err = pb.NewAuthClient(conn).Login(callCtx, &pb.LoginRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, int32(2), retries.Load())
+
+	assert.Equal(t, client.CircuitOpen, breaker.State())
+	assert.Equal(t, []client.CircuitState{client.CircuitOpen}, states)
+
+	// Open circuits fail calls without attempting them.
+	_, err = pb.NewAuthClient(conn).Login(callCtx, &pb.LoginRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, int32(3), attempts.Load())
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/client"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestClientTokenSource_CachesAndAttaches(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	cache := client.NewCache(st.AuthClient, email, password)
+	source := cache.Source(st.AppID)
+	assert.Same(t, source, cache.Source(st.AppID))
+
+	// Concurrent first calls share a single login.
+	tokens := make([]string, 8)
+
+	var wg sync.WaitGroup
+	for i := range tokens {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			token, err := source.Token(ctx)
+			assert.NoError(t, err)
+
+			tokens[i] = token
+		}()
+	}
+	wg.Wait()
+
+	for _, token := range tokens {
+		assert.Equal(t, tokens[0], token)
+	}
+
+	creds := client.PerRPCCredentials{Source: source, AllowInsecure: true}
+
+	respInfo, err := st.AuthClient.GetUserInfo(ctx, &pb.GetUserInfoRequest{}, grpc.PerRPCCredentials(creds))
+	require.NoError(t, err)
+	assert.Equal(t, email, respInfo.GetEmail())
+
+	_, err = st.AuthClient.GetUserInfo(ctx, &pb.GetUserInfoRequest{}, grpc.PerRPCCredentials(client.PerRPCCredentials{Source: source}))
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestClientTokenSource_RefreshesAhead(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	// Refreshing ahead by more than the token lifetime refreshes on every call.
+	source := client.NewTokenSource(st.AuthClient, email, password, st.AppID, client.WithRefreshAhead(st.Cfg.TokenTTL+time.Minute))
+
+	first, err := source.Token(ctx)
+	require.NoError(t, err)
+
+	// Issue times have a resolution of one second.
+	time.Sleep(time.Second)
+
+	second, err := source.Token(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	respWho, err := st.AuthClient.WhoAmI(ctx, &pb.WhoAmIRequest{Token: second})
+	require.NoError(t, err)
+	assert.Equal(t, email, respWho.GetEmail())
+
+	_, err = client.NewTokenSource(st.AuthClient, email, "wrong-password", st.AppID).Token(ctx)
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
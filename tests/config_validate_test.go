@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kirinyoku/sso-grpc/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	local, err := os.ReadFile("../config/local.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		replacements []string // old, new pairs applied to config/local.yml
+		wantFields   []string // fields reported as invalid; none if valid
+	}{
+		{
+			name: "Local config",
+		},
+		{
+			name:         "Production without TLS, audit key, or SMTP, with test tokens",
+			replacements: []string{`env: "local"`, `env: "prod"`},
+			wantFields:   []string{"grpc.tls.cert_file", "grpc.channelz", "audit.key", "smtp.host", "test_tokens.enabled"},
+		},
+		{
+			name:         "Unknown environment",
+			replacements: []string{`env: "local"`, `env: "staging"`},
+			wantFields:   []string{"env"},
+		},
+		{
+			name:         "Cross-field rules",
+			replacements: []string{"micro_cache_ttl: 500ms", "micro_cache_ttl: 2s", `network: "127.0.0.1"`, `network: "localhost"`},
+			wantFields:   []string{"token_validation.micro_cache_ttl", "radius.clients[0].network"},
+		},
+		{
+			name:         "Unknown enforcement mode",
+			replacements: []string{"\nregistration:", "\nenforcement:\n  registration_quota: warn\nregistration:"},
+			wantFields:   []string{"enforcement.registration_quota"},
+		},
+		{
+			name:         "Snapshots without a key or bucket",
+			replacements: []string{"\nregistration:", "\nsnapshots:\n  interval: 1h\n  endpoint: https://s3.example.com\nregistration:"},
+			wantFields:   []string{"snapshots.key", "snapshots.bucket", "snapshots"},
+		},
+		{
+			name:         "Authorization policy without a reload interval",
+			replacements: []string{"\ngrpc:", "\ngrpc:\n  authz_policy:\n    file: authz-policy.yml\n    reload_interval: -1s"},
+			wantFields:   []string{"grpc.authz_policy.reload_interval"},
+		},
+		{
+			name:         "Unknown token format",
+			replacements: []string{"token_ttl: 1h", "token_ttl: 1h\ntoken_format: paseto"},
+			wantFields:   []string{"token_format"},
+		},
+		{
+			name:         "RS256 tokens without a signing key",
+			replacements: []string{"token_ttl: 1h", "token_ttl: 1h\ntoken_format: jwt_rs256"},
+			wantFields:   []string{"token_format"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "config.yml")
+			require.NoError(t, os.WriteFile(path, []byte(strings.NewReplacer(tt.replacements...).Replace(string(local))), 0o600))
+
+			cfg, err := config.LoadByPath(path)
+			require.NoError(t, err)
+
+			err = cfg.Validate()
+			if len(tt.wantFields) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, config.ErrInvalidConfig)
+
+			for _, field := range tt.wantFields {
+				assert.Contains(t, err.Error(), "\n"+field+": ")
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+)
+
+func TestMain(m *testing.M) {
+	suite.Main(m)
+}
@@ -0,0 +1,3 @@
+INSERT INTO users (email, pass_hash, is_admin) 
+VALUES ('admin@test.local', '$2a$10$NL8p1OLqSRwkgzhHv0RvVu7sr498qEPuOcyUe0gHTM6Keg0lk4N5O', TRUE)
+ON CONFLICT DO NOTHING;
\ No newline at end of file
@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"crypto/rand"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/kirinyoku/sso-grpc/internal/lib/radius"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+// radiusTimeout bounds how long a test waits for a response that may never come.
+const radiusTimeout = 2 * time.Second
+
+// newAccessRequest builds an Access-Request with a PAP password hidden with secret.
+func newAccessRequest(t *testing.T, username, password, secret string) *radius.Packet {
+	t.Helper()
+
+	req := &radius.Packet{Code: radius.CodeAccessRequest, Identifier: 7}
+	_, err := rand.Read(req.Authenticator[:])
+	require.NoError(t, err)
+
+	hidden, err := radius.EncryptPassword([]byte(password), secret, req.Authenticator)
+	require.NoError(t, err)
+
+	req.Attributes = []radius.Attribute{
+		{Type: radius.AttrUserName, Value: []byte(username)},
+		{Type: radius.AttrUserPassword, Value: hidden},
+		{Type: radius.AttrNASIdentifier, Value: []byte("test-switch")},
+	}
+
+	return req
+}
+
+// exchangeRADIUS sends an encoded request to the listener and returns the response,
+// or nil if none arrives within radiusTimeout.
+func exchangeRADIUS(t *testing.T, st *suite.Suite, packet []byte) *radius.Packet {
+	t.Helper()
+
+	conn, err := net.Dial("udp", st.Cfg.RADIUS.Addr)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	_, err = conn.Write(packet)
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(radiusTimeout)))
+
+	buf := make([]byte, radius.MaxPacketSize)
+
+	n, err := conn.Read(buf)
+	if err != nil {
+		var netErr net.Error
+		require.ErrorAs(t, err, &netErr)
+		require.True(t, netErr.Timeout())
+
+		return nil
+	}
+
+	resp, err := radius.Parse(buf[:n])
+	require.NoError(t, err)
+
+	return resp
+}
+
+func TestRADIUS_AccessRequest(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	secret := st.Cfg.RADIUS.Clients[0].Secret
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		password string
+		wantCode byte
+	}{
+		{name: "Correct password", password: password, wantCode: radius.CodeAccessAccept},
+		{name: "Wrong password", password: password + "x", wantCode: radius.CodeAccessReject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newAccessRequest(t, email, tt.password, secret)
+
+			packet, err := radius.EncodeRequest(req, secret)
+			require.NoError(t, err)
+
+			resp := exchangeRADIUS(t, st, packet)
+			require.NotNil(t, resp)
+			require.NoError(t, radius.VerifyResponse(resp, req, secret))
+			assert.Equal(t, tt.wantCode, resp.Code)
+
+			// Retransmissions are answered with the same response.
+			again := exchangeRADIUS(t, st, packet)
+			require.NotNil(t, again)
+			assert.Equal(t, resp.Authenticator, again.Authenticator)
+			assert.Equal(t, resp.Code, again.Code)
+		})
+	}
+}
+
+func TestRADIUS_UnauthenticatedRequestsDropped(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	secret := st.Cfg.RADIUS.Clients[0].Secret
+
+	email := gofakeit.Email()
+	password := gofakeit.Password(true, true, true, true, false, passDefaultLength)
+
+	_, err := st.AuthClient.Register(ctx, &pb.RegisterRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	t.Run("Wrong secret", func(t *testing.T) {
+		req := newAccessRequest(t, email, password, "wrong-secret")
+
+		packet, err := radius.EncodeRequest(req, "wrong-secret")
+		require.NoError(t, err)
+
+		assert.Nil(t, exchangeRADIUS(t, st, packet))
+	})
+
+	t.Run("Missing Message-Authenticator", func(t *testing.T) {
+		req := newAccessRequest(t, email, password, secret)
+
+		packet, err := radius.EncodeRequest(req, secret)
+		require.NoError(t, err)
+
+		// Strip the Message-Authenticator added first, fixing up the length.
+		packet = append(packet[:20:20], packet[38:]...)
+		packet[2], packet[3] = byte(len(packet)>>8), byte(len(packet))
+
+		assert.Nil(t, exchangeRADIUS(t, st, packet))
+	})
+}
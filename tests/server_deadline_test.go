@@ -0,0 +1,36 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
+)
+
+func TestDeadline_Overhead(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	require.Greater(t, st.Cfg.GRPC.DeadlineOverhead, 200*time.Millisecond)
+
+	// The server rejects right away a call it cannot finish before its deadline less the overhead.
+	shortCtx, cancel := context.WithTimeout(ctx, st.Cfg.GRPC.DeadlineOverhead/2)
+	defer cancel()
+
+	_, err := st.AuthClient.GetServiceStatus(shortCtx, &pb.GetServiceStatusRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "deadline too close")
+
+	longCtx, cancel := context.WithTimeout(ctx, st.Cfg.GRPC.DeadlineOverhead+5*time.Second)
+	defer cancel()
+
+	_, err = st.AuthClient.GetServiceStatus(longCtx, &pb.GetServiceStatusRequest{})
+	require.NoError(t, err)
+}
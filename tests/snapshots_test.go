@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"bytes"
+	"database/sql"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kirinyoku/sso-grpc/internal/services/snapshot"
+	"github.com/kirinyoku/sso-grpc/tests/suite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+)
+
+func TestSnapshots(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	if suite.Snapshots == nil {
+		t.Skip("snapshots are only inspected on the in-process server")
+	}
+
+	adminCtx := st.AdminContext(ctx)
+
+	expired := "sso/sso-20000101T000000Z.db.enc"
+	suite.Snapshots.Put(expired, []byte("expired"))
+	suite.Snapshots.Put("sso/notes.txt", []byte("not a snapshot"))
+
+	respRun, err := st.AdminClient.RunJobNow(adminCtx, &adminpb.RunJobNowRequest{Name: "snapshots"})
+	require.NoError(t, err)
+	assert.Empty(t, respRun.GetJob().GetLastError())
+
+	keys := suite.Snapshots.Keys("sso/sso-")
+	require.NotEmpty(t, keys)
+	assert.NotContains(t, keys, expired)
+	assert.NotNil(t, suite.Snapshots.Get("sso/notes.txt"))
+
+	// The latest snapshot restores a database the service can run on.
+	latest := keys[len(keys)-1]
+
+	var data bytes.Buffer
+	require.NoError(t, snapshot.Decrypt(suite.SnapshotKey, latest, bytes.NewReader(suite.Snapshots.Get(latest)), &data))
+
+	path := filepath.Join(t.TempDir(), "sso.db")
+	require.NoError(t, os.WriteFile(path, data.Bytes(), 0o600))
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var apps int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM apps WHERE id = ?", st.AppID).Scan(&apps))
+	assert.Equal(t, 1, apps)
+
+	err = snapshot.Decrypt(suite.SnapshotKey, expired, bytes.NewReader([]byte("expired")), io.Discard)
+	require.ErrorIs(t, err, snapshot.ErrInvalidSnapshot)
+
+	// A snapshot put in place of another, such as an older one, does not decrypt.
+	err = snapshot.Decrypt(suite.SnapshotKey, expired, bytes.NewReader(suite.Snapshots.Get(latest)), io.Discard)
+	require.ErrorIs(t, err, snapshot.ErrInvalidSnapshot)
+
+	// Neither does a snapshot cut short at the end of a chunk.
+	sealed := suite.Snapshots.Get(latest)
+	require.Greater(t, len(sealed), 1+32+64<<10+16)
+
+	err = snapshot.Decrypt(suite.SnapshotKey, latest, bytes.NewReader(sealed[:1+32+64<<10+16]), io.Discard)
+	require.ErrorIs(t, err, snapshot.ErrInvalidSnapshot)
+}
@@ -0,0 +1,207 @@
+package suite
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
+	"github.com/kirinyoku/sso-grpc/internal/app"
+	"github.com/kirinyoku/sso-grpc/internal/config"
+)
+
+// configPath is the configuration shared by the server and the tests.
+const configPath = "../config/local.yml"
+
+// Migrations applied to a database created for the tests: the schema, then the test fixtures.
+var migrations = []struct {
+	path  string
+	table string
+}{
+	{path: "../migrations", table: "migrations"},
+	{path: "./migrations", table: "migrations_test"},
+}
+
+// diagnosticsScope is the scope Admin.GetDiagnostics requires of the in-process server.
+const diagnosticsScope = "diagnostics.read"
+
+// DiagnosticsScope is the scope Admin.GetDiagnostics requires; empty when the tests run
+// against an external server, where the method keeps its built-in level.
+var DiagnosticsScope string
+
+// startupTimeout bounds how long Main waits for an in-process server to accept connections.
+const startupTimeout = 5 * time.Second
+
+// Main runs the functional tests and exits with their result. It is meant to be called from TestMain.
+//
+// Tests run against the server listening on the port from config/local.yml. When there is none,
+// Main starts one in-process on a freshly migrated database in a temporary directory and stops it
+// afterwards, so `go test ./tests/` needs no externally started server or database.
+func Main(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	cfg := config.MustLoadByPath(configPath)
+
+	addr := fmt.Sprintf("localhost:%d", cfg.GRPC.Port)
+
+	if listening(addr) {
+		return m.Run()
+	}
+
+	dir, err := os.MkdirTemp("", "sso-functional-")
+	if err != nil {
+		panic(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	prepare(cfg, dir)
+
+	// Snapshots are uploaded to an in-memory bucket, so tests can restore them.
+	if cfg.Snapshots.Interval == 0 {
+		store, server := newObjectStore("sso-test")
+		defer server.Close()
+
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic(err)
+		}
+
+		Snapshots, SnapshotKey = store, base64.StdEncoding.EncodeToString(key)
+
+		cfg.Snapshots.Interval = time.Hour
+		cfg.Snapshots.Key = SnapshotKey
+		cfg.Snapshots.Endpoint = server.URL
+		cfg.Snapshots.Bucket = "sso-test"
+		cfg.Snapshots.AccessKeyID = "test"
+		cfg.Snapshots.SecretAccessKey = "test"
+	}
+
+	// A method requires a scope, so tests cover scoped levels.
+	if cfg.GRPC.AuthzPolicy.File == "" {
+		cfg.GRPC.AuthzPolicy.File = filepath.Join(dir, "authz-policy.yml")
+
+		policy := fmt.Sprintf("methods:\n  %s: scope:%s\n", adminpb.Admin_GetDiagnostics_FullMethodName, diagnosticsScope)
+		if err := os.WriteFile(cfg.GRPC.AuthzPolicy.File, []byte(policy), 0o600); err != nil {
+			panic(err)
+		}
+
+		DiagnosticsScope = diagnosticsScope
+	}
+
+	stop, err := start(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	defer stop()
+
+	return m.Run()
+}
+
+// prepare points cfg at a new database and avatar directory in dir, and fills in what
+// an in-process server needs to run without outside services.
+func prepare(cfg *config.Config, dir string) {
+	cfg.StoragePath = filepath.Join(dir, "sso.db")
+	cfg.Avatars.Dir = filepath.Join(dir, "avatars")
+
+	// Tests must not depend on outbound network access.
+	cfg.ClockCheck.NTPServer = ""
+
+	// Apps with the RS256 token format need a signing key.
+	if cfg.TokenSigning.PrivateKey == "" && cfg.TokenSigning.PrivateKeyFile == "" {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(err)
+		}
+
+		cfg.TokenSigning.PrivateKey = string(pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		}))
+	}
+
+	// Tokens carry every optional standard claim, so tests cover them.
+	if cfg.TokenClaims == (config.TokenClaims{}) {
+		cfg.TokenClaims = config.TokenClaims{
+			Issuer:    "https://sso.test",
+			Audience:  true,
+			NotBefore: true,
+			TokenID:   true,
+		}
+	}
+}
+
+// start migrates the database of cfg and serves it with an in-process server until stop is called.
+func start(cfg *config.Config) (stop func(), err error) {
+	for _, mig := range migrations {
+		if err := migrateUp(cfg.StoragePath, mig.path, mig.table); err != nil {
+			return nil, err
+		}
+	}
+
+	addr := fmt.Sprintf("localhost:%d", cfg.GRPC.Port)
+
+	application := app.New(slog.New(slog.NewTextHandler(io.Discard, nil)), cfg)
+
+	go application.GRPCSrv.MustRun()
+
+	for deadline := time.Now().Add(startupTimeout); !listening(addr); {
+		if time.Now().After(deadline) {
+			application.Stop()
+
+			return nil, fmt.Errorf("in-process server did not start on %s", addr)
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return application.Stop, nil
+}
+
+// listening reports whether a server accepts connections at addr.
+func listening(addr string) bool {
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		return false
+	}
+
+	conn.Close()
+
+	return true
+}
+
+// migrateUp applies the migrations in dir to the SQLite database at storagePath.
+func migrateUp(storagePath, dir, table string) error {
+	m, err := migrate.New(
+		"file://"+dir,
+		fmt.Sprintf("sqlite3://%s?x-migrations-table=%s", storagePath, table),
+	)
+	if err != nil {
+		return err
+	}
+
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migrate %s: %w", dir, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package suite
+
+import (
+	"encoding/xml"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ObjectStore is an in-memory stand-in for an S3 bucket, answering the requests of the
+// snapshot uploader: PUT and DELETE of objects, and GET of the bucket to list them.
+// Signatures are not checked.
+type ObjectStore struct {
+	bucket string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+// Snapshots is the bucket the in-process server uploads snapshots to, encrypted with
+// SnapshotKey; nil when the tests run against an external server.
+var (
+	Snapshots   *ObjectStore
+	SnapshotKey string
+)
+
+// newObjectStore starts serving an empty bucket and returns it with the server's URL.
+func newObjectStore(bucket string) (*ObjectStore, *httptest.Server) {
+	store := &ObjectStore{bucket: bucket, objects: make(map[string][]byte)}
+
+	return store, httptest.NewServer(store)
+}
+
+// Keys returns the keys of the stored objects starting with a prefix, in order.
+func (s *ObjectStore) Keys(prefix string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+
+	for _, key := range slices.Sorted(maps.Keys(s.objects)) {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// Get returns the object stored under a key, or nil.
+func (s *ObjectStore) Get(key string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.objects[key]
+}
+
+// Put stores an object under a key.
+func (s *ObjectStore) Put(key string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.objects[key] = data
+}
+
+func (s *ObjectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		http.Error(w, "unsigned request", http.StatusForbidden)
+
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != s.bucket {
+		http.Error(w, "no such bucket", http.StatusNotFound)
+
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPut && key != "":
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		s.Put(key, data)
+	case r.Method == http.MethodDelete && key != "":
+		s.mu.Lock()
+		delete(s.objects, key)
+		s.mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && key == "":
+		type content struct {
+			Key          string
+			Size         int
+			LastModified time.Time
+		}
+
+		var result struct {
+			XMLName  xml.Name `xml:"ListBucketResult"`
+			Contents []content
+		}
+
+		for _, k := range s.Keys(r.URL.Query().Get("prefix")) {
+			result.Contents = append(result.Contents, content{Key: k, Size: len(s.Get(k)), LastModified: time.Now()})
+		}
+
+		w.Header().Set("Content-Type", "application/xml")
+		_ = xml.NewEncoder(w).Encode(result)
+	default:
+		http.Error(w, "unsupported request", http.StatusMethodNotAllowed)
+	}
+}
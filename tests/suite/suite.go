@@ -3,25 +3,82 @@ package suite
 import (
 	"context"
 	"fmt"
+	"net"
 	"testing"
 
+	"github.com/brianvoe/gofakeit/v6"
+	adminpb "github.com/kirinyoku/sso-grpc/api/admin/v1"
 	pb "github.com/kirinyoku/sso-grpc/api/auth/v1"
 	"github.com/kirinyoku/sso-grpc/internal/config"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// Credentials of the admin seeded by tests/migrations, used to manage
+// per-test apps through the admin API.
+const (
+	bootstrapAppID int32  = 1
+	adminEmail     string = "admin@test.local"
+	adminPassword  string = "test-admin-password"
 )
 
 type Suite struct {
 	*testing.T
-	Cfg        *config.Config
-	AuthClient pb.AuthClient
+	Cfg         *config.Config
+	AuthClient  pb.AuthClient
+	AdminClient adminpb.AdminClient
+	AppID       int32  // ID of the app created exclusively for this test
+	AppSecret   string // secret of the app created exclusively for this test
 }
 
 func New(t *testing.T) (context.Context, *Suite) {
 	t.Helper()
 	t.Parallel()
 
-	cfg := config.MustLoadByPath("../config/local.yml")
+	return newSuite(t, config.MustLoadByPath(configPath))
+}
+
+// NewWithConfig is like New, but runs the test against a server of its own, started in-process
+// on a new database with config/local.yml changed by configure. It covers settings the shared
+// server does not use. The server only serves gRPC: its HTTP and RADIUS listeners are disabled.
+func NewWithConfig(t *testing.T, configure func(cfg *config.Config)) (context.Context, *Suite) {
+	t.Helper()
+	t.Parallel()
+
+	cfg := config.MustLoadByPath(configPath)
+
+	prepare(cfg, t.TempDir())
+
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("failed to find a free port: %v", err)
+	}
+
+	cfg.GRPC.Port = l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	cfg.Avatars.Addr = ""
+	cfg.Metrics.Addr = ""
+	cfg.Introspection.Addr = ""
+	cfg.TokenSigning.JWKSAddr = ""
+	cfg.RADIUS.Addr = ""
+
+	configure(cfg)
+
+	stop, err := start(cfg)
+	if err != nil {
+		t.Fatalf("failed to start server: %v", err)
+	}
+
+	t.Cleanup(stop)
+
+	return newSuite(t, cfg)
+}
+
+// newSuite connects to the server cfg describes and creates the test's app.
+func newSuite(t *testing.T, cfg *config.Config) (context.Context, *Suite) {
+	t.Helper()
 
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.GRPC.Timeout)
 
@@ -35,10 +92,71 @@ func New(t *testing.T) (context.Context, *Suite) {
 		t.Fatalf("failed to create gRPC client: %v", err)
 	}
 
-	return ctx, &Suite{
-		T:          t,
-		Cfg:        cfg,
-		AuthClient: pb.NewAuthClient(conn),
+	st := &Suite{
+		T:           t,
+		Cfg:         cfg,
+		AuthClient:  pb.NewAuthClient(conn),
+		AdminClient: adminpb.NewAdminClient(conn),
 	}
 
+	st.createApp(ctx)
+
+	return ctx, st
+}
+
+// AdminContext returns ctx carrying the seeded admin's token, for calls to the admin API.
+func (s *Suite) AdminContext(ctx context.Context) context.Context {
+	s.Helper()
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+s.AdminToken(ctx))
+}
+
+// AdminToken logs in as the seeded admin and returns the token.
+func (s *Suite) AdminToken(ctx context.Context) string {
+	s.Helper()
+
+	return s.AdminTokenFor(ctx, bootstrapAppID)
+}
+
+// AdminTokenFor logs in as the seeded admin to an app and returns the token. The admin API
+// only accepts it from the bootstrap app, the admin app of config/local.yml, or RS256 apps.
+func (s *Suite) AdminTokenFor(ctx context.Context, appID int32) string {
+	s.Helper()
+
+	resp, err := s.AuthClient.Login(ctx, &pb.LoginRequest{
+		Email:    adminEmail,
+		Password: adminPassword,
+		AppId:    appID,
+	})
+	if err != nil {
+		s.Fatalf("failed to log in as admin: %v", err)
+	}
+
+	return resp.GetToken()
+}
+
+// createApp registers an app with a unique name and secret for the current test
+// and removes it once the test finishes, so parallel tests never share app state.
+func (s *Suite) createApp(ctx context.Context) {
+	s.Helper()
+
+	adminCtx := s.AdminContext(ctx)
+
+	s.AppSecret = gofakeit.UUID()
+
+	resp, err := s.AdminClient.CreateApp(adminCtx, &adminpb.CreateAppRequest{
+		Name:   "test-" + gofakeit.UUID(),
+		Secret: s.AppSecret,
+	})
+	if err != nil {
+		s.Fatalf("failed to create test app: %v", err)
+	}
+
+	s.AppID = resp.GetAppId()
+
+	s.Cleanup(func() {
+		if _, err := s.AdminClient.DeleteApp(adminCtx, &adminpb.DeleteAppRequest{AppId: s.AppID}); err != nil {
+			s.Errorf("failed to delete test app %d: %v", s.AppID, err)
+		}
+	})
 }